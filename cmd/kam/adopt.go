@@ -0,0 +1,265 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+
+	"github.com/bitomule/kamui/internal/adopt"
+	"github.com/bitomule/kamui/internal/logging"
+	"github.com/bitomule/kamui/internal/paths"
+	"github.com/bitomule/kamui/internal/session"
+)
+
+// Adopt command creates sessions for Claude conversations started without Kamui
+var adoptCmd = &cobra.Command{
+	Use:   "adopt",
+	Short: "Create sessions for Claude conversations started without Kamui",
+	Long: `Finds Claude transcripts that no session is bound to and offers to create a
+session for each, named after its first prompt. Resuming the session continues
+the conversation.
+
+Without --all only conversations started in the current directory are offered.
+--all scans every project under ~/.claude/projects, mapping each back to the
+directory Claude ran in; conversations whose directory no longer exists are
+skipped.
+
+Each conversation is confirmed in turn ([y]es, [n]o, [a]ll remaining, [q]uit)
+unless --yes is given. --dry-run lists them without creating anything.
+
+--into binds one of the conversations to an existing session that has none yet
+instead, for when Kamui didn't spot the conversation Claude started for it. The
+newest are offered first; --yes takes the newest.`,
+	Args: cobra.NoArgs,
+	RunE: runAdopt,
+}
+
+func init() {
+	adoptCmd.Flags().Bool("all", false, "scan every Claude project, not just the current directory")
+	adoptCmd.Flags().Bool("dry-run", false, "list the conversations without creating sessions")
+	adoptCmd.Flags().String("into", "", "bind a conversation to this existing session instead of creating sessions")
+	rootCmd.AddCommand(adoptCmd)
+}
+
+func runAdopt(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	all, _ := cmd.Flags().GetBool("all")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	into, _ := cmd.Flags().GetString("into")
+	if into != "" {
+		if all {
+			return fmt.Errorf("--into and --all can't be combined")
+		}
+		return adoptInto(ctx, into, dryRun)
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	store, err := openStorage(cwd)
+	if err != nil {
+		return err
+	}
+	bound, err := adopt.BoundSessions(ctx, store)
+	if err != nil {
+		return err
+	}
+
+	var result adopt.Result
+	if all {
+		result, err = adopt.ScanAll(ctx, paths.ClaudeProjectsDir(homeDir), bound)
+	} else {
+		result, err = adopt.ScanProject(ctx, paths.ClaudeProjectDir(homeDir, cwd), bound)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, skipped := range result.Skipped {
+		fmt.Fprintf(os.Stderr, "Warning: skipping %s: %s\n", skipped.TranscriptPath, skipped.Reason)
+	}
+	if len(result.Candidates) == 0 {
+		fmt.Println("Kamui: No conversations to adopt")
+		return nil
+	}
+
+	askEach := !dryRun && !viper.GetBool("yes")
+	if askEach && !term.IsTerminal(int(os.Stdin.Fd())) {
+		return fmt.Errorf("no terminal to confirm on; pass --yes to adopt every conversation or --dry-run to list them")
+	}
+
+	managers := make(map[string]*session.Manager)
+	reader := bufio.NewReader(os.Stdin)
+	adopted := 0
+	for i, candidate := range result.Candidates {
+		printCandidate(candidate)
+		if dryRun {
+			continue
+		}
+
+		if askEach {
+			answer, err := askAdopt(ctx, reader, "Adopt? [y/N/a/q] ")
+			if err != nil {
+				return err
+			}
+			if answer == "q" {
+				break
+			}
+			if answer == "a" {
+				askEach = false
+			} else if answer != "y" {
+				continue
+			}
+		}
+
+		sessionManager, ok := managers[candidate.WorkingDir]
+		if !ok {
+			if sessionManager, err = newSessionManagerFor(candidate.WorkingDir); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: skipping %s: %v\n", candidate.ClaudeSessionID, err)
+				continue
+			}
+			managers[candidate.WorkingDir] = sessionManager
+		}
+
+		sessionData, err := sessionManager.AdoptClaudeSession(ctx, candidate.ClaudeSessionID, candidate.WorkingDir, candidate.FirstPrompt, candidate.Start)
+		if err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			return fmt.Errorf("failed to adopt %s (%d of %d): %w", candidate.ClaudeSessionID, i+1, len(result.Candidates), err)
+		}
+		adopted++
+
+		_ = logging.New().Audit(logging.AuditEntry{Action: "adopt", Session: sessionData.SessionID, Detail: candidate.ClaudeSessionID})
+		fmt.Printf("  Kamui: Created session '%s'\n", sessionData.SessionID)
+	}
+
+	if dryRun {
+		fmt.Printf("Kamui: %d conversation(s) could be adopted\n", len(result.Candidates))
+		return nil
+	}
+	for _, sessionManager := range managers {
+		registerProject(ctx, sessionManager)
+	}
+	fmt.Printf("Kamui: Adopted %d of %d conversation(s)\n", adopted, len(result.Candidates))
+	return nil
+}
+
+// adoptInto offers the unbound conversations in a session's directory, newest first, and binds
+// the one chosen to the session
+func adoptInto(ctx context.Context, name string, dryRun bool) error {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+	sessionData, err := sessionManager.GetSession(ctx, name)
+	if err != nil {
+		return err
+	}
+	name = sessionData.SessionID
+	if sessionData.Claude.SessionID != "" && !sessionData.Claude.Pending {
+		return fmt.Errorf("session '%s' already continues conversation %s; use 'kam bind --replace' to rebind it", name, sessionData.Claude.SessionID)
+	}
+
+	store, err := openStorage(sessionData.Project.WorkingDirectory)
+	if err != nil {
+		return err
+	}
+	bound, err := adopt.BoundSessions(ctx, store)
+	if err != nil {
+		return err
+	}
+	result, err := adopt.ScanProject(ctx, paths.ClaudeProjectDir(homeDir, sessionData.Project.WorkingDirectory), bound)
+	if err != nil {
+		return err
+	}
+	if len(result.Candidates) == 0 {
+		fmt.Printf("Kamui: No unbound conversations in %s\n", sessionData.Project.WorkingDirectory)
+		return nil
+	}
+
+	askEach := !dryRun && !viper.GetBool("yes")
+	if askEach && !term.IsTerminal(int(os.Stdin.Fd())) {
+		return fmt.Errorf("no terminal to confirm on; pass --yes to bind the newest conversation or --dry-run to list them")
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for i := len(result.Candidates) - 1; i >= 0; i-- {
+		candidate := result.Candidates[i]
+		printCandidate(candidate)
+		if dryRun {
+			continue
+		}
+		if askEach {
+			answer, err := askAdopt(ctx, reader, fmt.Sprintf("Bind to '%s'? [y/N/q] ", name))
+			if err != nil {
+				return err
+			}
+			if answer == "q" {
+				return nil
+			}
+			if answer != "y" {
+				continue
+			}
+		}
+
+		if err := sessionManager.BindClaudeSession(ctx, name, candidate.ClaudeSessionID, false); err != nil {
+			return err
+		}
+		_ = logging.New().Audit(logging.AuditEntry{Action: "bind", Session: name, Detail: candidate.ClaudeSessionID})
+		fmt.Printf("Kamui: Session '%s' now continues conversation %s\n", name, candidate.ClaudeSessionID)
+		return nil
+	}
+	return nil
+}
+
+// printCandidate describes an unbound conversation
+func printCandidate(candidate adopt.Candidate) {
+	prompt, _, _ := strings.Cut(strings.TrimSpace(candidate.FirstPrompt), "\n")
+	if runes := []rune(prompt); len(runes) > 70 {
+		prompt = string(runes[:69]) + "…"
+	}
+	fmt.Printf("%s  %s  %d prompt(s), started %s\n", candidate.WorkingDir, candidate.ClaudeSessionID,
+		candidate.Prompts, candidate.Start.Local().Format("2006-01-02 15:04"))
+	fmt.Printf("  %q\n", prompt)
+}
+
+// askAdopt asks whether to take a conversation, returning "y", "n", "a" or "q"
+func askAdopt(ctx context.Context, reader *bufio.Reader, question string) (string, error) {
+	fmt.Fprint(os.Stderr, "  "+question)
+	answer, err := readLine(ctx, reader)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return "y", nil
+	case "a", "all":
+		return "a", nil
+	case "q", "quit":
+		return "q", nil
+	default:
+		return "n", nil
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/viper"
+
+	"github.com/bitomule/kamui/internal/terminal"
+)
+
+// Alerts ui.exitAlert can list
+const (
+	exitAlertBell = "bell"
+	exitAlertOSC9 = "osc9"
+)
+
+// alertExit rings the terminal bell and/or posts an OSC 9 notification when Claude exits, as
+// listed in ui.exitAlert, so a session left running in a background window gets noticed
+func alertExit(sessionName string, exitCode int) {
+	alerts := viper.GetStringSlice("ui.exitAlert")
+	if len(alerts) == 0 {
+		return
+	}
+
+	message := fmt.Sprintf("Kamui: Claude finished in session '%s'", sessionName)
+	if exitCode != 0 {
+		message = fmt.Sprintf("Kamui: Claude exited with status %d in session '%s'", exitCode, sessionName)
+	}
+
+	term := terminal.New(os.Stdout)
+	for _, alert := range alerts {
+		switch alert {
+		case exitAlertBell:
+			term.Bell()
+		case exitAlertOSC9:
+			term.Notify(message)
+		default:
+			fmt.Fprintf(os.Stderr, "Warning: unknown ui.exitAlert %q (expected %q or %q)\n", alert, exitAlertBell, exitAlertOSC9)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/bitomule/kamui/internal/logging"
+	"github.com/bitomule/kamui/internal/project"
+	"github.com/bitomule/kamui/internal/session"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Archive command moves sessions out of the way without deleting them
+var archiveCmd = &cobra.Command{
+	Use:   "archive <session-name>...",
+	Short: "Archive sessions",
+	Long: `Marks sessions as archived. Archived sessions are kept, and launching one brings it back,
+but they no longer count against the project's session quota (session.maxSessions in
+.kamui/config.json).
+
+With --inactive, archives instead every session of the project that hasn't been used for
+session.cleanupInactiveDays (default 30, 0 disables it); pinned sessions are kept.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if inactive, _ := cmd.Flags().GetBool("inactive"); inactive {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
+	RunE: runArchive,
+}
+
+func init() {
+	archiveCmd.Flags().Bool("inactive", false, "archive the sessions unused for session.cleanupInactiveDays")
+	archiveCmd.Flags().Bool("dry-run", false, "with --inactive, list the sessions that would be archived")
+	rootCmd.AddCommand(archiveCmd)
+}
+
+func runArchive(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	if inactive, _ := cmd.Flags().GetBool("inactive"); inactive {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		return archiveInactive(ctx, sessionManager, dryRun)
+	}
+	for _, name := range args {
+		sessionData, err := sessionManager.GetSession(ctx, name)
+		if err != nil {
+			return err
+		}
+		if err := sessionManager.ArchiveSession(ctx, sessionData.SessionID); err != nil {
+			return err
+		}
+		_ = logging.New().Audit(logging.AuditEntry{Action: "archive", Session: sessionData.SessionID})
+		fmt.Printf("Kamui: Archived session '%s'\n", sessionData.SessionID)
+	}
+	return nil
+}
+
+// archiveInactive archives the sessions unused for session.cleanupInactiveDays, or only lists them
+func archiveInactive(ctx context.Context, sessionManager *session.Manager, dryRun bool) error {
+	days := viper.GetInt("session.cleanupInactiveDays")
+	if days <= 0 {
+		return fmt.Errorf("session.cleanupInactiveDays is %d, so no session counts as inactive", days)
+	}
+	cleanup := types.CleanupConfig{Enabled: true, InactiveThreshold: types.Duration(time.Duration(days) * types.Day)}
+
+	sessions, err := sessionManager.InactiveSessions(ctx, cleanup)
+	if err != nil {
+		return err
+	}
+	if len(sessions) == 0 {
+		fmt.Printf("Kamui: No session has gone unused for %d days\n", days)
+		return nil
+	}
+
+	if dryRun {
+		fmt.Println("Kamui: Dry run, nothing was changed:")
+		for _, sessionData := range sessions {
+			fmt.Printf("  would archive  %s (last used %s)\n", sessionData.SessionID, sessionData.LastAccessed.Local().Format("2006-01-02"))
+		}
+		return nil
+	}
+	for _, sessionData := range sessions {
+		lastUsed := sessionData.LastAccessed.Local().Format("2006-01-02")
+		if err := sessionManager.ArchiveSession(ctx, sessionData.SessionID); err != nil {
+			return err
+		}
+		_ = logging.New().Audit(logging.AuditEntry{Action: "archive", Session: sessionData.SessionID, Detail: "inactive"})
+		fmt.Printf("Kamui: Archived session '%s' (last used %s)\n", sessionData.SessionID, lastUsed)
+	}
+	return nil
+}
+
+// warnQuota warns before a session is created in a project that has reached its session quota,
+// suggesting the least recently used sessions to archive; it never stops the creation
+func warnQuota(ctx context.Context, sessionManager *session.Manager) {
+	report, err := sessionManager.CheckQuota(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: couldn't check the session quota: %v\n", err)
+		return
+	}
+	if report == nil {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "Warning: this project already has %d session(s), its limit is %d (session.maxSessions in %s)\n",
+		report.Count, report.Max, project.ConfigPath(sessionManager.GetProjectPath()))
+	fmt.Fprintln(os.Stderr, "  Least recently used, consider archiving:")
+	names := make([]string, 0, len(report.Candidates))
+	for _, candidate := range report.Candidates {
+		fmt.Fprintf(os.Stderr, "    %-30s last used %s (%s)\n",
+			candidate.SessionID, candidate.LastAccessed.Local().Format("2006-01-02"), candidate.Lifecycle.State)
+		names = append(names, candidate.SessionID)
+	}
+	fmt.Fprintf(os.Stderr, "  Archive them with 'kam archive %s'\n", strings.Join(names, " "))
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// Args command shows or sets the extra arguments Claude launches with for a session
+var argsCmd = &cobra.Command{
+	Use:   "args <session-name> [-- <claude-args>...]",
+	Short: "Show or set the extra Claude arguments of a session",
+	Long: `Without Claude arguments, prints the command Claude is launched or resumed with for
+the session: claude.defaultArgs from the config, then the session's own arguments,
+then its permission mode.
+
+Arguments after -- replace the session's own arguments:
+
+  kam args my-session -- --add-dir ../shared --model opus
+
+--clear removes them. Flags Kamui manages itself, such as --resume, can't be stored.
+
+--permission-mode pins the Claude permission mode (plan, acceptEdits, default or
+bypass) the session launches in, or unpins it with none. Read-only sessions always
+run in plan mode.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runArgs,
+}
+
+func init() {
+	argsCmd.Flags().Bool("clear", false, "remove the session's own Claude arguments")
+	argsCmd.Flags().String("permission-mode", "", "pin the Claude permission mode: plan, acceptEdits, default, bypass or none")
+	rootCmd.AddCommand(argsCmd)
+}
+
+func runArgs(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	clear, _ := cmd.Flags().GetBool("clear")
+	dash := cmd.ArgsLenAtDash()
+	if (dash == -1 && len(args) > 1) || dash > 1 {
+		return fmt.Errorf("put the Claude arguments after --, e.g. kam args %s -- --add-dir ../shared", args[0])
+	}
+	sessionName := args[0]
+	claudeArgs := args[1:]
+	if clear && len(claudeArgs) > 0 {
+		return fmt.Errorf("--clear doesn't take Claude arguments")
+	}
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	if clear || dash == 1 {
+		if err := sessionManager.SetClaudeArgs(ctx, sessionName, claudeArgs); err != nil {
+			return err
+		}
+	}
+	if cmd.Flags().Changed("permission-mode") {
+		permissionMode, _ := cmd.Flags().GetString("permission-mode")
+		if permissionMode == "none" {
+			permissionMode = ""
+		}
+		if err := sessionManager.SetPermissionMode(ctx, sessionName, permissionMode); err != nil {
+			return err
+		}
+	}
+
+	sessionData, err := sessionManager.GetSession(ctx, sessionName)
+	if err != nil {
+		return err
+	}
+	fmt.Println(sessionManager.GetClaudeCommand(sessionData))
+	return nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/adopt"
+	"github.com/bitomule/kamui/internal/logging"
+)
+
+// Bind command attaches a specific Claude conversation to a session
+var bindCmd = &cobra.Command{
+	Use:   "bind <session-name> <claude-session-id>",
+	Short: "Bind a session to a specific Claude conversation",
+	Long: `Binds a session to a Claude conversation by ID, for when Kamui didn't bind the
+conversation Claude started for it. The conversation must exist for the session's
+directory (~/.claude/projects/<dir>/<id>.jsonl); a transcript path is accepted too.
+
+A session already bound to another conversation is only rebound with --replace.
+'kam adopt --into <session>' offers the unbound conversations to choose from instead.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runBind,
+}
+
+func init() {
+	bindCmd.Flags().Bool("replace", false, "rebind a session that already continues another conversation")
+	rootCmd.AddCommand(bindCmd)
+}
+
+func runBind(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	replace, _ := cmd.Flags().GetBool("replace")
+	claudeSessionID := strings.TrimSuffix(filepath.Base(args[1]), ".jsonl")
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+	sessionData, err := sessionManager.GetSession(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	name := sessionData.SessionID
+	previous := sessionData.Claude.SessionID
+	if previous == claudeSessionID && !sessionData.Claude.Pending {
+		fmt.Printf("Kamui: Session '%s' already continues conversation %s\n", name, claudeSessionID)
+		return nil
+	}
+
+	store, err := openStorage(sessionData.Project.WorkingDirectory)
+	if err != nil {
+		return err
+	}
+	bound, err := adopt.BoundSessions(ctx, store)
+	if err != nil {
+		return err
+	}
+
+	if err := sessionManager.BindClaudeSession(ctx, name, claudeSessionID, replace); err != nil {
+		return err
+	}
+	detail := claudeSessionID
+	if previous != "" {
+		detail = fmt.Sprintf("%s (was %s)", claudeSessionID, previous)
+	}
+	_ = logging.New().Audit(logging.AuditEntry{Action: "bind", Session: name, Detail: detail})
+
+	fmt.Printf("Kamui: Session '%s' now continues conversation %s\n", name, claudeSessionID)
+	if bound[claudeSessionID] && previous != claudeSessionID {
+		fmt.Fprintf(os.Stderr, "Warning: conversation %s is also bound to another session; 'kam doctor' can split them\n", claudeSessionID)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/bitomule/kamui/internal/paths"
+	"github.com/bitomule/kamui/internal/session"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// bindingWait is how long kam waits after Claude exits for the background monitor to bind the
+// conversation Claude started
+const bindingWait = 10 * time.Second
+
+// bindingPoll is how often the session is checked while waiting
+const bindingPoll = 200 * time.Millisecond
+
+// spinnerFrames animate the wait on a terminal
+var spinnerFrames = []rune("⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏")
+
+// awaitBinding waits for the conversation Claude started for a session to be bound to it, showing
+// what kam is waiting for, and explains how to bind it by hand when it doesn't turn up
+// It returns the session as last loaded
+func awaitBinding(ctx context.Context, sessionManager *session.Manager, sessionData *types.Session) *types.Session {
+	if sessionData.Claude.SessionID != "" {
+		return sessionData
+	}
+
+	homeDir, _ := os.UserHomeDir() // only used to say where kam is looking
+	transcripts := paths.ClaudeProjectDir(homeDir, sessionData.Project.WorkingDirectory)
+	waiting := fmt.Sprintf("Waiting for Claude's conversation to appear in %s", transcripts)
+	spin := term.IsTerminal(int(os.Stderr.Fd()))
+	if !spin {
+		fmt.Fprintf(os.Stderr, "Kamui: %s\n", waiting)
+	}
+
+	started := time.Now()
+	ticker := time.NewTicker(bindingPoll)
+	defer ticker.Stop()
+	for frame := 0; ; frame++ {
+		if spin {
+			fmt.Fprintf(os.Stderr, "\r\033[K%c %s (%.1fs)", spinnerFrames[frame%len(spinnerFrames)], waiting, time.Since(started).Seconds())
+		}
+
+		select {
+		case <-ctx.Done():
+			clearSpinner(spin)
+			return sessionData
+		case <-ticker.C:
+		}
+
+		if latest, err := sessionManager.GetSession(ctx, sessionData.SessionID); err == nil {
+			sessionData = latest
+		}
+		if sessionData.Claude.SessionID != "" {
+			clearSpinner(spin)
+			fmt.Printf("Kamui: Session '%s' continues conversation %s\n", sessionData.SessionID, sessionData.Claude.SessionID)
+			return sessionData
+		}
+		if time.Since(started) >= bindingWait {
+			clearSpinner(spin)
+			fmt.Fprintf(os.Stderr, "Kamui: No conversation for '%s' turned up in %s within %s\n",
+				sessionData.SessionID, transcripts, bindingWait)
+			fmt.Fprintf(os.Stderr, "Kamui: If you sent Claude a prompt, bind its conversation with 'kam adopt --into %s' or 'kam bind %s <claude-session-id>'\n", sessionData.SessionID, sessionData.SessionID)
+			return sessionData
+		}
+	}
+}
+
+// clearSpinner erases the spinner line
+func clearSpinner(spin bool) {
+	if spin {
+		fmt.Fprint(os.Stderr, "\r\033[K")
+	}
+}
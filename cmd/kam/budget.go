@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/budget"
+	"github.com/bitomule/kamui/internal/stats"
+	"github.com/bitomule/kamui/internal/theme"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Budget command group for session and project spending limits
+var budgetCmd = &cobra.Command{
+	Use:   "budget",
+	Short: "Manage cost and token budgets",
+	Long: `Budgets cap the estimated cost or tokens of a session or a whole project.
+Kamui warns when resuming a session and in the Claude Code status line once usage
+crosses 80% of a budget, and again when it is exceeded. Budgets never block Claude.`,
+}
+
+var budgetSetCmd = &cobra.Command{
+	Use:   "set [session-name] <amount>",
+	Short: "Set a session's budget, or the project's with --project",
+	Long: `Sets a budget in USD (kam budget set api 5.00), or in tokens with --tokens
+(kam budget set api 2M --tokens). Cost and token limits are independent, so a
+budget can have both.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runBudgetSet,
+}
+
+var budgetClearCmd = &cobra.Command{
+	Use:   "clear [session-name]",
+	Short: "Remove a session's budget, or the project's with --project",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runBudgetClear,
+}
+
+var budgetStatusCmd = &cobra.Command{
+	Use:   "status [session-name]",
+	Short: "Show usage against the session and project budgets",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runBudgetStatus,
+}
+
+// budgetCheck is a budget status for a named scope
+type budgetCheck struct {
+	scope  string
+	status budget.Status
+}
+
+func init() {
+	budgetSetCmd.Flags().Bool("project", false, "set the budget for the current project")
+	budgetSetCmd.Flags().Bool("tokens", false, "interpret the amount as a token count (500k, 2M)")
+	budgetClearCmd.Flags().Bool("project", false, "clear the budget for the current project")
+	budgetStatusCmd.Flags().Bool("statusline", false, "print a one-line warning for the status line, or nothing when within budget")
+
+	budgetCmd.AddCommand(budgetSetCmd)
+	budgetCmd.AddCommand(budgetClearCmd)
+	budgetCmd.AddCommand(budgetStatusCmd)
+	rootCmd.AddCommand(budgetCmd)
+}
+
+func runBudgetSet(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	forProject, _ := cmd.Flags().GetBool("project")
+	inTokens, _ := cmd.Flags().GetBool("tokens")
+
+	if forProject != (len(args) == 1) {
+		return fmt.Errorf("specify either a session name and an amount, or --project and an amount")
+	}
+	amount := args[len(args)-1]
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	var current *types.Budget
+	if forProject {
+		current, err = budget.NewStore().Get(sessionManager.GetProjectPath())
+	} else {
+		var sessionData *types.Session
+		sessionData, err = sessionManager.GetSession(ctx, args[0])
+		if sessionData != nil {
+			current = sessionData.Metadata.Budget
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	updated := types.Budget{}
+	if current != nil {
+		updated = *current
+	}
+	if inTokens {
+		if updated.Tokens, err = budget.ParseTokens(amount); err != nil {
+			return err
+		}
+	} else {
+		if updated.CostUSD, err = budget.ParseCost(amount); err != nil {
+			return err
+		}
+	}
+
+	if forProject {
+		if err := budget.NewStore().Set(sessionManager.GetProjectPath(), &updated); err != nil {
+			return err
+		}
+		fmt.Printf("Kamui: Set budget for project '%s' to %s\n", sessionManager.GetProjectName(), formatBudget(updated))
+		return nil
+	}
+
+	if err := sessionManager.SetBudget(ctx, args[0], &updated); err != nil {
+		return err
+	}
+	fmt.Printf("Kamui: Set budget for session '%s' to %s\n", args[0], formatBudget(updated))
+	return nil
+}
+
+func runBudgetClear(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	forProject, _ := cmd.Flags().GetBool("project")
+	if forProject != (len(args) == 0) {
+		return fmt.Errorf("specify either a session name or --project")
+	}
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	if forProject {
+		if err := budget.NewStore().Set(sessionManager.GetProjectPath(), nil); err != nil {
+			return err
+		}
+		fmt.Printf("Kamui: Cleared budget for project '%s'\n", sessionManager.GetProjectName())
+		return nil
+	}
+
+	if err := sessionManager.SetBudget(ctx, args[0], nil); err != nil {
+		return err
+	}
+	fmt.Printf("Kamui: Cleared budget for session '%s'\n", args[0])
+	return nil
+}
+
+func runBudgetStatus(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	statusLine, _ := cmd.Flags().GetBool("statusline")
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	var sessionData *types.Session
+	projectPath := sessionManager.GetProjectPath()
+	if len(args) == 1 {
+		if sessionData, err = sessionManager.GetSession(ctx, args[0]); err != nil {
+			return err
+		}
+		projectPath = sessionData.Project.Path
+	}
+
+	checks, err := checkBudgets(ctx, sessionData, projectPath)
+	if err != nil {
+		return err
+	}
+
+	if statusLine {
+		// Only the most pressing budget fits in the status line
+		var worst *budgetCheck
+		for i := range checks {
+			if checks[i].status.Level != budget.LevelOK && (worst == nil || checks[i].status.Ratio > worst.status.Ratio) {
+				worst = &checks[i]
+			}
+		}
+		if worst != nil {
+			// Claude draws the status line, so it is colored even though kam's output is piped
+			role := theme.Warning
+			if worst.status.Level == budget.LevelExceeded {
+				role = theme.Error
+			}
+			fmt.Println(themePainter(colorEnabled()).Paint(role, fmt.Sprintf("%s budget %.0f%%", worst.scope, worst.status.Ratio*100)))
+		}
+		return nil
+	}
+
+	if len(checks) == 0 {
+		fmt.Println("Kamui: No budget set (use 'kam budget set')")
+		return nil
+	}
+	for _, check := range checks {
+		fmt.Printf("Kamui: %s\n", check.status.Message(check.scope))
+	}
+	return nil
+}
+
+// checkBudgets evaluates the session's budget (when given) and the project's budget
+// Scopes without a budget are left out
+func checkBudgets(ctx context.Context, sessionData *types.Session, projectPath string) ([]budgetCheck, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	prices := pricingTable()
+
+	var checks []budgetCheck
+	if sessionData != nil && sessionData.Metadata.Budget != nil {
+		used, err := stats.EstimateCost(ctx, sessionData, homeDir, prices)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, budgetCheck{
+			scope:  fmt.Sprintf("session '%s'", sessionData.SessionID),
+			status: budget.Check(*sessionData.Metadata.Budget, used),
+		})
+	}
+
+	projectBudget, err := budget.NewStore().Get(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if projectBudget != nil {
+		store, err := openStorage(projectPath)
+		if err != nil {
+			return nil, err
+		}
+		used, err := stats.EstimateProjectCost(ctx, store, projectPath, homeDir, prices)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, budgetCheck{
+			scope:  "project",
+			status: budget.Check(*projectBudget, used),
+		})
+	}
+
+	return checks, nil
+}
+
+// warnBudgets prints a warning for every budget the session or its project is close to or over
+func warnBudgets(ctx context.Context, sessionData *types.Session) {
+	checks, err := checkBudgets(ctx, sessionData, sessionData.Project.Path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to check budgets: %v\n", err)
+		return
+	}
+
+	for _, check := range checks {
+		if check.status.Level != budget.LevelOK {
+			fmt.Fprintf(os.Stderr, "Kamui: ⚠️  %s\n", check.status.Message(check.scope))
+		}
+	}
+}
+
+// formatBudget renders the limits of a budget
+func formatBudget(b types.Budget) string {
+	switch {
+	case b.CostUSD > 0 && b.Tokens > 0:
+		return fmt.Sprintf("%s and %s tokens", formatCost(b.CostUSD), formatTokens(b.Tokens))
+	case b.Tokens > 0:
+		return formatTokens(b.Tokens) + " tokens"
+	default:
+		return formatCost(b.CostUSD)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/trace"
+	"github.com/bitomule/kamui/internal/vscode"
+)
+
+// Code command opens a session's project in VS Code
+var codeCmd = &cobra.Command{
+	Use:   "code <session-name>",
+	Short: "Open a session's project in VS Code",
+	Long: `Opens the session's project in VS Code after adding a "Kamui: resume <session>" task
+to .vscode/tasks.json and a matching terminal profile to .vscode/settings.json, so the
+session can be resumed in the integrated terminal. With --auto-resume the task runs
+automatically when the folder opens.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCode,
+}
+
+func init() {
+	codeCmd.Flags().Bool("auto-resume", false, "resume the session automatically when VS Code opens the folder")
+	codeCmd.Flags().Bool("no-open", false, "only write the VS Code configuration, don't launch VS Code")
+	rootCmd.AddCommand(codeCmd)
+}
+
+func runCode(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	sessionName := args[0]
+	autoResume, _ := cmd.Flags().GetBool("auto-resume")
+	noOpen, _ := cmd.Flags().GetBool("no-open")
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	sessionData, err := sessionManager.GetSession(ctx, sessionName)
+	if err != nil {
+		return err
+	}
+	projectPath := sessionData.Project.Path
+
+	tasksPath, err := vscode.UpsertTask(projectPath, vscode.ResumeTask(sessionName, autoResume))
+	if err != nil {
+		return fmt.Errorf("failed to update VS Code tasks: %w", err)
+	}
+	fmt.Printf("Kamui: Added task '%s' to %s\n", vscode.TaskLabel(sessionName), tasksPath)
+
+	settingsPath, err := vscode.UpsertTerminalProfile(projectPath, sessionName)
+	if err != nil {
+		return fmt.Errorf("failed to update VS Code settings: %w", err)
+	}
+	fmt.Printf("Kamui: Added terminal profile 'Kamui: %s' to %s\n", sessionName, settingsPath)
+
+	if noOpen {
+		return nil
+	}
+
+	codePath, err := exec.LookPath("code")
+	if err != nil {
+		return fmt.Errorf("VS Code 'code' command not found in PATH (install it from the VS Code command palette): %w", err)
+	}
+
+	editor := exec.Command(codePath, projectPath)
+	editor.Stdout = os.Stdout
+	editor.Stderr = os.Stderr
+	return trace.Run(editor)
+}
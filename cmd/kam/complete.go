@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/bitomule/kamui/internal/links"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Complete command marks a session as completed
+var completeCmd = &cobra.Command{
+	Use:   "complete <session-name>",
+	Short: "Mark a session as completed",
+	Long: `Marks the session as completed.
+
+When jira.transitionOnComplete is set (a transition or status name such as "Done"),
+the session's linked Jira issues are moved through that transition as well; use
+--no-transition to skip it.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runComplete,
+}
+
+func init() {
+	completeCmd.Flags().Bool("no-transition", false, "don't transition linked Jira issues")
+	rootCmd.AddCommand(completeCmd)
+}
+
+func runComplete(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	noTransition, _ := cmd.Flags().GetBool("no-transition")
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	sessionData, err := sessionManager.GetSession(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	if err := sessionManager.CompleteSession(ctx, sessionData.SessionID); err != nil {
+		return err
+	}
+	fmt.Printf("Kamui: Completed session '%s'\n", sessionData.SessionID)
+
+	transition := viper.GetString("jira.transitionOnComplete")
+	if transition == "" || noTransition {
+		return nil
+	}
+	transitionJiraIssues(sessionData.Metadata.Links, transition)
+	return nil
+}
+
+// transitionJiraIssues moves every linked Jira issue through the transition
+// Failures are reported but don't undo the completion
+func transitionJiraIssues(sessionLinks []types.Link, transition string) {
+	var keys []string
+	for _, link := range sessionLinks {
+		if link.Kind == links.KindJira {
+			keys = append(keys, link.Label)
+		}
+	}
+	if len(keys) == 0 {
+		return
+	}
+
+	client, err := newJiraClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: can't transition Jira issues: %v\n", err)
+		return
+	}
+
+	for _, key := range keys {
+		if err := client.Transition(key, transition); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to transition %s: %v\n", key, err)
+			continue
+		}
+		fmt.Printf("Kamui: Moved %s to '%s'\n", key, transition)
+	}
+}
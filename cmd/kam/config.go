@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/bitomule/kamui/internal/config"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Config command groups commands for Kamui's config file
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage Kamui's config file",
+}
+
+// Config init command writes a config file listing every option
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Write a config file with every option set to its default",
+	Long: `Writes a config file listing every option Kamui knows, set to its default, to the
+config path (~/.kamui/config.json, or --config) so the available settings can be found
+and edited in one place.
+
+JSON has no comments; when the path ends in .yaml or .yml the file is written as YAML
+with a comment above each option. kam schema config describes the file's structure.`,
+	Args: cobra.NoArgs,
+	RunE: runConfigInit,
+}
+
+func init() {
+	configInitCmd.Flags().StringP("output", "o", "", "write to this file instead of the config path, or - for stdout")
+	configInitCmd.Flags().Bool("force", false, "overwrite an existing file")
+	configCmd.AddCommand(configInitCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+func runConfigInit(cmd *cobra.Command, _ []string) error {
+	output, _ := cmd.Flags().GetString("output")
+	force, _ := cmd.Flags().GetBool("force")
+
+	if output == "" {
+		output = viper.GetString("config")
+	}
+	if output == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		output = filepath.Join(home, ".kamui", "config.json")
+	}
+
+	// only the defaults, not whatever the current config file, environment and flags set
+	defaults := viper.New()
+	setDefaults(defaults)
+	var cfg types.Config
+	if err := defaults.Unmarshal(&cfg); err != nil {
+		return fmt.Errorf("failed to build the default config: %w", err)
+	}
+
+	format := config.FormatFor(output)
+	data, err := config.Render(cfg, format)
+	if err != nil {
+		return err
+	}
+
+	if output == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if _, statErr := os.Stat(output); statErr == nil && !force {
+		return fmt.Errorf("%s already exists; pass --force to replace it", output)
+	}
+	if err := os.MkdirAll(filepath.Dir(output), 0o700); err != nil {
+		return err
+	}
+	// the file may come to hold API tokens
+	if err := os.WriteFile(output, data, 0o600); err != nil {
+		return err
+	}
+	fmt.Printf("Kamui: Wrote %s with every option at its default\n", output)
+	if format == config.FormatJSON {
+		fmt.Println("Write it as .yaml for a commented version, or run 'kam schema config' to see the format")
+	}
+	return nil
+}
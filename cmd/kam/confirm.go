@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+)
+
+// confirmDestructive asks before a destructive operation and reports whether to go ahead
+// It doesn't ask with --yes or when ui.confirmDestructive is off; without a terminal to
+// ask on it refuses, so scripts must pass --yes explicitly
+func confirmDestructive(ctx context.Context, question string) (bool, error) {
+	if viper.GetBool("yes") || !viper.GetBool("ui.confirmDestructive") {
+		return true, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return false, fmt.Errorf("no terminal to confirm on; pass --yes to run non-interactively")
+	}
+
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	answer, err := readLine(ctx, bufio.NewReader(os.Stdin))
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, err
+		}
+		return false, fmt.Errorf("failed to read input: %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		fmt.Fprintln(os.Stderr, "Kamui: Aborted")
+		return false, nil
+	}
+}
+
+// printDryRun lists the files a destructive operation would remove or rewrite
+func printDryRun(removed, rewritten []string) {
+	if len(removed) == 0 && len(rewritten) == 0 {
+		fmt.Println("Kamui: Dry run: nothing would be changed")
+		return
+	}
+	fmt.Println("Kamui: Dry run, nothing was changed:")
+	for _, path := range removed {
+		fmt.Printf("  would remove   %s\n", path)
+	}
+	for _, path := range rewritten {
+		fmt.Printf("  would rewrite  %s\n", path)
+	}
+}
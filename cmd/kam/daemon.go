@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/bitomule/kamui/internal/logging"
+	"github.com/bitomule/kamui/internal/schedule"
+	"github.com/bitomule/kamui/internal/session"
+)
+
+// Daemon command runs background housekeeping and scheduled runs
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Run the Kamui background daemon in the foreground",
+	Long: `Runs Kamui's background work until interrupted: executes scheduled headless runs
+(see 'kam schedule') and records their results as session notes, keeps the token
+usage shown by 'kam dashboard' up to date every minute, and once a day runs the
+same housekeeping as 'kam gc'.`,
+	Args:        cobra.NoArgs,
+	RunE:        runDaemon,
+	Annotations: launchesClaude,
+}
+
+func init() {
+	rootCmd.AddCommand(daemonCmd)
+}
+
+func runDaemon(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	fmt.Println("Kamui: Daemon started, press Ctrl+C to stop")
+
+	store := schedule.NewStore()
+	logger := newLogger()
+	var lastHousekeeping time.Time
+	for {
+		now := time.Now()
+		if now.YearDay() != lastHousekeeping.YearDay() || now.Year() != lastHousekeeping.Year() {
+			daemonHousekeeping(logger)
+			lastHousekeeping = now
+		}
+		daemonTick(ctx, store, logger, now)
+		saveDashboardSnapshot(ctx, logger, now)
+
+		// Wake up at the start of the next minute, the finest cron granularity
+		wait := time.Until(time.Now().Truncate(time.Minute).Add(time.Minute))
+		select {
+		case <-ctx.Done():
+			fmt.Println("Kamui: Daemon stopped")
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+// daemonHousekeeping enforces log retention, reporting rather than failing on errors
+func daemonHousekeeping(logger *logging.Logger) {
+	result, err := runHousekeeping(logger, false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Kamui: Housekeeping failed: %v\n", err)
+		return
+	}
+	if result.RemovedLogFiles > 0 || result.RemovedAuditEntries > 0 {
+		_ = logger.Printf("housekeeping removed %d log file(s) and %d audit entries", result.RemovedLogFiles, result.RemovedAuditEntries)
+	}
+}
+
+// daemonTick performs one round of daemon work
+func daemonTick(ctx context.Context, store *schedule.Store, logger *logging.Logger, now time.Time) {
+	schedules, err := store.List()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Kamui: Failed to load schedules: %v\n", err)
+		return
+	}
+
+	for _, entry := range schedules {
+		if entry.IsDue(now) {
+			runScheduledPrompt(ctx, store, logger, entry, now)
+		}
+	}
+}
+
+// runScheduledPrompt executes a due schedule and records the result as a session note
+func runScheduledPrompt(ctx context.Context, store *schedule.Store, logger *logging.Logger, entry *schedule.Schedule, now time.Time) {
+	fmt.Printf("Kamui: Running schedule %s for session '%s'\n", entry.ID, entry.SessionID)
+
+	status, text := "success", ""
+	sessionManager, err := session.NewForPath(entry.ProjectPath)
+	if err == nil {
+		err = useConfiguredStorage(sessionManager)
+	}
+	if err == nil {
+		result, runErr := sessionManager.RunHeadless(ctx, entry.SessionID, entry.Prompt)
+		switch {
+		case runErr != nil:
+			err = runErr
+		case result.IsError:
+			status, text = "error", result.Result
+		default:
+			text = result.Result
+		}
+	}
+	if err != nil {
+		status, text = "error", err.Error()
+	}
+
+	if sessionManager != nil {
+		if noteErr := sessionManager.AddNote(ctx, entry.SessionID, "schedule:"+entry.ID, text); noteErr != nil {
+			fmt.Fprintf(os.Stderr, "Kamui: Failed to record note for '%s': %v\n", entry.SessionID, noteErr)
+		}
+	}
+
+	_ = logger.Printf("schedule %s for session '%s' finished with status %s", entry.ID, entry.SessionID, status)
+	_ = logger.Audit(logging.AuditEntry{Action: "schedule-run", Session: entry.SessionID, Detail: entry.ID + ": " + status})
+
+	if recordErr := store.RecordRun(entry.ID, now, status); recordErr != nil {
+		fmt.Fprintf(os.Stderr, "Kamui: Failed to record schedule run: %v\n", recordErr)
+	}
+
+	if viper.GetBool("ui.notifications") {
+		outcome := "finished"
+		if status != "success" {
+			outcome = "failed"
+		}
+		notifyRunFinished(entry.SessionID, outcome, text)
+	}
+}
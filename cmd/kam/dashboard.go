@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/dashboard"
+	"github.com/bitomule/kamui/internal/logging"
+	"github.com/bitomule/kamui/internal/pricing"
+	"github.com/bitomule/kamui/internal/storage"
+)
+
+// Dashboard command shows every project's sessions in a live, full-screen view
+var dashboardCmd = &cobra.Command{
+	Use:   "dashboard",
+	Short: "Show every project's sessions in a live, full-screen view",
+	Long: `Shows the sessions of every project, grouped by project, with whether kam is
+running each one right now, its token usage and estimated cost, and when it was
+last used. Running state is refreshed every --interval.
+
+Token usage is read from the Claude transcripts, which is slow with many sessions.
+While 'kam daemon' runs it keeps the usage up to date once a minute and the
+dashboard uses that; otherwise usage is read when the dashboard opens and when r
+is pressed.
+
+Keys: j/k or arrows move, enter resumes the session (in its project directory),
+c completes it, a archives it, r refreshes everything, h shows or hides archived
+sessions, q quits. Completing here doesn't transition linked Jira issues; use
+'kam complete' for that.`,
+	Args:        cobra.NoArgs,
+	RunE:        runDashboard,
+	Annotations: launchesClaude,
+}
+
+func init() {
+	dashboardCmd.Flags().Duration("interval", 5*time.Second, "how often running state is refreshed")
+	rootCmd.AddCommand(dashboardCmd)
+}
+
+func runDashboard(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	interval, _ := cmd.Flags().GetDuration("interval")
+	if interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	store, err := openStorage(cwd)
+	if err != nil {
+		return err
+	}
+
+	source := &dashboardSource{
+		ctx:       ctx,
+		store:     store,
+		snapshots: dashboard.NewStore(),
+		homeDir:   homeDir,
+		prices:    pricingTable(),
+	}
+	snapshot, err := source.Snapshot(false)
+	if err != nil {
+		return err
+	}
+
+	action, err := dashboard.Run(dashboard.New(snapshot), source, os.Stdin, os.Stdout, interval)
+	if err != nil || action.Kind != dashboard.ActionResume {
+		return err
+	}
+
+	if err := os.Chdir(action.Project.Path); err != nil {
+		return err
+	}
+	return runSession(cmd, []string{action.Session.ID})
+}
+
+// dashboardSource collects the dashboard's snapshots, taking token usage from the daemon's
+// snapshot when it is newer than the last one read here
+type dashboardSource struct {
+	ctx       context.Context
+	store     storage.Interface
+	snapshots *dashboard.Store
+	homeDir   string
+	prices    pricing.Table
+	// usage is the latest snapshot with token usage
+	usage *dashboard.Snapshot
+}
+
+func (s *dashboardSource) Snapshot(full bool) (*dashboard.Snapshot, error) {
+	if !full {
+		if daemonSnapshot, err := s.snapshots.Load(); err == nil && daemonSnapshot != nil && daemonSnapshot.WithUsage &&
+			(s.usage == nil || daemonSnapshot.Generated.After(s.usage.Generated)) {
+			s.usage = daemonSnapshot
+		}
+	}
+	if full || s.usage == nil {
+		snapshot, err := dashboard.Collect(s.ctx, s.store, s.homeDir, s.prices, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		s.usage = snapshot
+		return snapshot, nil
+	}
+
+	snapshot, err := dashboard.Collect(s.ctx, s.store, s.homeDir, nil, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	snapshot.MergeUsage(s.usage)
+	return snapshot, nil
+}
+
+func (s *dashboardSource) Apply(action dashboard.Action) (string, error) {
+	sessionManager, err := newSessionManagerFor(action.Project.Path)
+	if err != nil {
+		return "", err
+	}
+
+	switch action.Kind {
+	case dashboard.ActionComplete:
+		if err := sessionManager.CompleteSession(s.ctx, action.Session.ID); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Completed session '%s'", action.Session.ID), nil
+	case dashboard.ActionArchive:
+		if err := sessionManager.ArchiveSession(s.ctx, action.Session.ID); err != nil {
+			return "", err
+		}
+		_ = logging.New().Audit(logging.AuditEntry{Action: "archive", Session: action.Session.ID})
+		return fmt.Sprintf("Archived session '%s'", action.Session.ID), nil
+	}
+	return "", nil
+}
+
+// saveDashboardSnapshot collects token usage for every session for kam dashboard
+func saveDashboardSnapshot(ctx context.Context, logger *logging.Logger, now time.Time) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		_ = logger.Printf("failed to update the dashboard snapshot: %v", err)
+		return
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		_ = logger.Printf("failed to update the dashboard snapshot: %v", err)
+		return
+	}
+	store, err := openStorage(cwd)
+	if err == nil {
+		var snapshot *dashboard.Snapshot
+		if snapshot, err = dashboard.Collect(ctx, store, homeDir, pricingTable(), now); err == nil {
+			err = dashboard.NewStore().Save(snapshot)
+		}
+	}
+	if err != nil && ctx.Err() == nil {
+		_ = logger.Printf("failed to update the dashboard snapshot: %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/bitomule/kamui/internal/claude"
+	"github.com/bitomule/kamui/internal/diagnostics"
+)
+
+// debugLogLines is how much of Kamui's log a debug dump includes
+const debugLogLines = 500
+
+// Debug command groups diagnostics for bug reports
+var debugCmd = &cobra.Command{
+	Use:   "debug",
+	Short: "Diagnostics for bug reports",
+}
+
+// Dump command writes a sanitized diagnostic bundle
+var debugDumpCmd = &cobra.Command{
+	Use:   "dump",
+	Short: "Write a sanitized diagnostic bundle to attach to a bug report",
+	Long: `Writes a gzipped tarball with what is needed to investigate a problem:
+the Kamui and Claude Code versions, the effective configuration, an index of the
+sessions and their metadata, and the last lines of Kamui's own log.
+
+Paths are replaced by short hashes (the same directory always gets the same hash),
+the home directory is shortened to ~, and credentials, session environment values
+and note text are redacted. Transcripts are never included.`,
+	Args: cobra.NoArgs,
+	RunE: runDebugDump,
+}
+
+func init() {
+	debugDumpCmd.Flags().StringP("output", "o", "", "file to write (default kamui-debug-<time>.tar.gz)")
+	debugCmd.AddCommand(debugDumpCmd)
+	rootCmd.AddCommand(debugCmd)
+}
+
+func runDebugDump(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	output, _ := cmd.Flags().GetString("output")
+	now := time.Now()
+	if output == "" {
+		output = fmt.Sprintf("kamui-debug-%s.tar.gz", now.Format("20060102-150405"))
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	sanitizer := diagnostics.NewSanitizer(homeDir)
+
+	bundle := &diagnostics.Bundle{
+		Environment: diagnostics.Environment{
+			KamuiVersion:  rootCmd.Version,
+			ClaudeVersion: "not found",
+			GoVersion:     runtime.Version(),
+			OS:            runtime.GOOS,
+			Arch:          runtime.GOARCH,
+			Created:       now,
+		},
+		Skipped: map[string]string{},
+	}
+	if claudePath, lookErr := exec.LookPath("claude"); lookErr == nil {
+		if version, versionErr := claude.DetectVersion(ctx, claudePath, claude.DefaultVersionCachePath()); versionErr != nil {
+			bundle.Environment.ClaudeVersion = fmt.Sprintf("unknown (%v)", versionErr)
+		} else {
+			bundle.Environment.ClaudeVersion = version.String()
+		}
+	}
+
+	store, err := openStorage(cwd)
+	if err != nil {
+		return err
+	}
+	names, err := store.ListSessions(ctx)
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sessionData, loadErr := store.LoadSession(ctx, name)
+		if loadErr != nil {
+			if ctx.Err() != nil {
+				return loadErr
+			}
+			bundle.Skipped[name] = sanitizer.Text(loadErr.Error())
+			continue
+		}
+		sanitized, sanitizeErr := sanitizer.Session(sessionData)
+		if sanitizeErr != nil {
+			bundle.Skipped[name] = sanitizeErr.Error()
+			continue
+		}
+		bundle.Sessions = append(bundle.Sessions, sanitized)
+	}
+
+	// sanitized after the sessions, so the paths they mention are hashed here too
+	bundle.Config = sanitizer.Config(viper.AllSettings())
+	logLines, err := newLogger().Tail(debugLogLines)
+	if err != nil {
+		return err
+	}
+	bundle.Log = sanitizer.Lines(logLines)
+
+	file, err := os.OpenFile(output, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", output, err)
+	}
+	files, err := bundle.WriteTarGz(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(output)
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+
+	fmt.Printf("Kamui: Wrote %s (%d session(s), %d log line(s))\n", output, len(bundle.Sessions), len(bundle.Log))
+	for _, name := range files {
+		fmt.Printf("  %s\n", name)
+	}
+	if len(bundle.Skipped) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %d session(s) couldn't be read and are only listed in manifest.json\n", len(bundle.Skipped))
+	}
+	fmt.Println("Paths are hashed and secrets redacted; check the contents before attaching it to a report")
+	return nil
+}
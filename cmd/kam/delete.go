@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/checkpoint"
+	"github.com/bitomule/kamui/internal/logging"
+	"github.com/bitomule/kamui/internal/session"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Delete command removes a session
+var deleteCmd = &cobra.Command{
+	Use:   "delete <session-name>",
+	Short: "Delete a session",
+	Long: `Deletes a session's metadata. Its Claude conversation is kept in ~/.claude/projects
+and 'kam adopt' can pick it up again; with --purge-claude it is deleted too,
+unless another session is bound to it. Protected sessions need --force, and sessions
+locked with 'kam lock' or running in kam can't be deleted.
+
+When the session works in a linked git worktree or has a kamui/<session> checkpoint
+branch, kam then offers to remove the worktree and the session's branches that are
+already merged; unmerged branches are kept. --keep-worktree skips the offer.
+
+Asks for confirmation first unless --yes or --force is given or ui.confirmDestructive
+is off. --dry-run lists what would be removed without touching anything.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDelete,
+}
+
+func init() {
+	deleteCmd.Flags().Bool("force", false, "delete even if the session is protected, without asking for confirmation")
+	deleteCmd.Flags().Bool("dry-run", false, "list what would be removed without removing anything")
+	deleteCmd.Flags().Bool("purge-claude", false, "also delete the Claude conversation the session is bound to")
+	deleteCmd.Flags().Bool("keep-worktree", false, "don't offer to remove the session's git worktree and merged branches")
+	rootCmd.AddCommand(deleteCmd)
+}
+
+func runDelete(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	force, _ := cmd.Flags().GetBool("force")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	purgeClaude, _ := cmd.Flags().GetBool("purge-claude")
+	keepWorktree, _ := cmd.Flags().GetBool("keep-worktree")
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	sessionData, err := sessionManager.GetSession(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	name := sessionData.SessionID
+
+	if dryRun {
+		return printDeletePlan(ctx, sessionManager, sessionData, force, purgeClaude, keepWorktree)
+	}
+
+	if !force {
+		question := fmt.Sprintf("Delete session '%s'?", name)
+		if purgeClaude && sessionData.Claude.SessionID != "" {
+			question = fmt.Sprintf("Delete session '%s' and its Claude conversation %s?", name, sessionData.Claude.SessionID)
+		}
+		proceed, err := confirmDestructive(ctx, question)
+		if err != nil || !proceed {
+			return err
+		}
+	}
+
+	if !purgeClaude {
+		if err := sessionManager.DeleteSession(ctx, name, force); err != nil {
+			return err
+		}
+		_ = logging.New().Audit(logging.AuditEntry{Action: "delete", Session: name})
+		fmt.Printf("Kamui: Deleted session '%s'\n", name)
+	} else {
+		purge, err := sessionManager.DeleteSessionAndConversation(ctx, name, force)
+		if purge == nil {
+			return err
+		}
+		_ = logging.New().Audit(logging.AuditEntry{Action: "delete", Session: name})
+		fmt.Printf("Kamui: Deleted session '%s'\n", name)
+		if err != nil {
+			return fmt.Errorf("the session was deleted, but its Claude conversation wasn't: %w", err)
+		}
+
+		switch {
+		case purge.Deleted:
+			_ = logging.New().Audit(logging.AuditEntry{Action: "purge-claude", Session: name, Detail: purge.ClaudeSessionID})
+			fmt.Printf("Kamui: Deleted Claude conversation %s\n", purge.ClaudeSessionID)
+		case len(purge.SharedWith) > 0:
+			fmt.Fprintf(os.Stderr, "Warning: kept Claude conversation %s; it's still bound to %s\n",
+				purge.ClaudeSessionID, strings.Join(purge.SharedWith, ", "))
+		}
+	}
+
+	if keepWorktree {
+		return nil
+	}
+	if err := offerLeftoverCleanup(ctx, sessionData, force); err != nil {
+		return fmt.Errorf("the session was deleted, but its worktree and branches weren't removed: %w", err)
+	}
+	return nil
+}
+
+// printDeletePlan lists what deleting the session would remove
+func printDeletePlan(ctx context.Context, sessionManager *session.Manager, sessionData *types.Session, force, purgeClaude, keepWorktree bool) error {
+	plan, err := sessionManager.PlanDelete(ctx, sessionData.SessionID, force, purgeClaude)
+	if err != nil {
+		return err
+	}
+
+	var removed []string
+	if plan.SessionFile != "" {
+		removed = append(removed, plan.SessionFile)
+	}
+	if plan.Transcript != "" {
+		removed = append(removed, plan.Transcript)
+	}
+	if !keepWorktree {
+		leftovers, err := checkpoint.FindLeftovers(ctx, sessionData)
+		if err != nil {
+			return err
+		}
+		if leftovers != nil {
+			removable, _ := describeLeftovers(leftovers)
+			removed = append(removed, removable...)
+		}
+	}
+	printDryRun(removed, nil)
+	if plan.Running != nil {
+		fmt.Printf("Kamui: Session '%s' is running in kam (pid %d on %s since %s), so deleting it would be refused until it exits\n",
+			sessionData.SessionID, plan.Running.PID, plan.Running.Host, plan.Running.Acquired.Local().Format("2006-01-02 15:04"))
+	}
+	if len(plan.SharedWith) > 0 {
+		fmt.Printf("Kamui: Claude conversation %s would be kept; it's still bound to %s\n",
+			sessionData.Claude.SessionID, strings.Join(plan.SharedWith, ", "))
+	}
+	return nil
+}
+
+// offerLeftoverCleanup offers to remove the git worktree and merged branches a deleted session
+// leaves in its repository; with force it removes them without asking
+func offerLeftoverCleanup(ctx context.Context, sessionData *types.Session, force bool) error {
+	leftovers, err := checkpoint.FindLeftovers(ctx, sessionData)
+	if err != nil || leftovers == nil {
+		return err
+	}
+
+	removable, kept := describeLeftovers(leftovers)
+	if len(kept) > 0 {
+		fmt.Printf("Kamui: Keeping unmerged branch(es) %s\n", strings.Join(kept, ", "))
+	}
+	if len(removable) == 0 {
+		return nil
+	}
+
+	if !force {
+		proceed, err := confirmDestructive(ctx, fmt.Sprintf("Also remove its %s?", strings.Join(removable, " and ")))
+		if err != nil || !proceed {
+			return err
+		}
+	}
+	deleted, err := leftovers.Remove(ctx)
+	if err == nil && leftovers.Worktree != "" {
+		fmt.Printf("Kamui: Removed worktree %s\n", leftovers.Worktree)
+	}
+	for _, branch := range deleted {
+		fmt.Printf("Kamui: Deleted branch %s\n", branch)
+	}
+	if err != nil {
+		return err
+	}
+	_ = logging.New().Audit(logging.AuditEntry{Action: "remove-leftovers", Session: sessionData.SessionID, Detail: strings.Join(removable, ", ")})
+	return nil
+}
+
+// describeLeftovers lists the worktree and merged branches Remove would delete, and the
+// unmerged branches it keeps
+func describeLeftovers(leftovers *checkpoint.Leftovers) (removable, kept []string) {
+	if leftovers.Worktree != "" {
+		removable = append(removable, "worktree "+leftovers.Worktree)
+	}
+	for _, branch := range leftovers.Branches {
+		if branch.Merged {
+			removable = append(removable, "merged branch "+branch.Name)
+		} else {
+			kept = append(kept, branch.Name)
+		}
+	}
+	return removable, kept
+}
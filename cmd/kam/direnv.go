@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/fence"
+	"github.com/bitomule/kamui/internal/session"
+)
+
+// envrcMarkers delimit the block Kamui manages inside .envrc
+var envrcMarkers = fence.Markers{
+	Begin: "# >>> kamui >>>",
+	End:   "# <<< kamui <<<",
+}
+
+// Direnv command writes session variables into the project's .envrc
+var direnvCmd = &cobra.Command{
+	Use:   "direnv <session-name>",
+	Short: "Export a session's variables from the project's .envrc",
+	Long: `Writes (or updates) a Kamui-managed block in the project's .envrc that exports the
+session's custom variables (see 'kam env --set'), so direnv makes them available to every
+tool started in the project. The KAMUI_* markers are left out: they are set only on the
+Claude that kam launches, so other shells aren't mistaken for a Kamui session. Only the
+text between the Kamui markers is touched; use --remove to delete the block again.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDirenv,
+}
+
+func init() {
+	direnvCmd.Flags().Bool("remove", false, "remove the Kamui block from .envrc")
+	rootCmd.AddCommand(direnvCmd)
+}
+
+func runDirenv(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	sessionName := args[0]
+	remove, _ := cmd.Flags().GetBool("remove")
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	sessionData, err := sessionManager.GetSession(ctx, sessionName)
+	if err != nil {
+		return err
+	}
+
+	envrcPath := filepath.Join(sessionData.Project.Path, ".envrc")
+
+	if remove {
+		removed, err := fence.RemoveFromFile(envrcPath, envrcMarkers)
+		if err != nil {
+			return fmt.Errorf("failed to update %s: %w", envrcPath, err)
+		}
+		if !removed {
+			fmt.Printf("Kamui: No Kamui block found in %s\n", envrcPath)
+			return nil
+		}
+		fmt.Printf("Kamui: Removed Kamui block from %s\n", envrcPath)
+		return nil
+	}
+
+	env := session.CustomEnvironment(sessionData)
+	if len(env) == 0 {
+		fmt.Fprintf(os.Stderr, "Kamui: Session '%s' has no custom variables - the block exports nothing\n", sessionName)
+	}
+	exports, err := session.FormatExports(env, "bash")
+	if err != nil {
+		return err
+	}
+	body := fmt.Sprintf("# Managed by 'kam direnv %s' - edits inside this block are overwritten\n%s", sessionName, exports)
+
+	if err := fence.UpsertFile(envrcPath, envrcMarkers, body, 0o644); err != nil {
+		return fmt.Errorf("failed to update %s: %w", envrcPath, err)
+	}
+
+	fmt.Printf("Kamui: Updated %s for session '%s'\n", envrcPath, sessionName)
+	if _, err := exec.LookPath("direnv"); err != nil {
+		fmt.Fprintln(os.Stderr, "Kamui: direnv not found in PATH - install it to load .envrc automatically")
+	} else {
+		fmt.Println("Kamui: Run 'direnv allow' to approve the change")
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/bitomule/kamui/internal/claude"
+	"github.com/bitomule/kamui/internal/logging"
+	"github.com/bitomule/kamui/internal/monitor"
+	"github.com/bitomule/kamui/internal/session"
+	"github.com/bitomule/kamui/internal/validate"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Doctor command checks Kamui's setup and sessions and helps fix what it finds
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the Claude installation and sessions, and fix shared conversations",
+	Long: `Checks that Claude Code is installed, validates every session file and looks for
+Claude conversations bound to more than one session (e.g. after copying a session
+file by hand), which makes the sessions talk over each other.
+
+On a terminal, each shared conversation is fixed in turn: pick the session that
+keeps it, then fork the conversation into a copy for each of the others or rebind
+them to a fresh one. Run 'kam validate' for the details of other problems.`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	failures := 0
+	if claudePath, lookErr := exec.LookPath("claude"); lookErr != nil {
+		fmt.Println("Claude Code:     not found in PATH")
+		failures++
+	} else if version, versionErr := claude.DetectVersion(ctx, claudePath, claude.DefaultVersionCachePath()); versionErr != nil {
+		fmt.Printf("Claude Code:     %s (version unknown: %v)\n", claudePath, versionErr)
+	} else {
+		fmt.Printf("Claude Code:     %s (%s)\n", version, claudePath)
+		minimum, minErr := configuredMinClaudeVersion()
+		if minErr == nil {
+			minErr = claude.CheckMinimum(version, minimum, enabledClaudeFeatures())
+		}
+		if minErr != nil {
+			fmt.Printf("                 %v\n", minErr)
+			failures++
+		}
+	}
+
+	store, err := openStorage(cwd)
+	if err != nil {
+		return err
+	}
+	report, err := validate.Store(ctx, store, homeDir, time.Now())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Session files:   %d checked, %d error(s), %d warning(s)\n", report.Files, report.Errors, report.Warnings)
+	fmt.Printf("Shared bindings: %d\n", len(report.Duplicates))
+
+	orphaned, err := printMonitors()
+	if err != nil {
+		return err
+	}
+	failures += orphaned
+
+	unfixed := len(report.Duplicates)
+	if unfixed > 0 {
+		fmt.Println()
+		if term.IsTerminal(int(os.Stdin.Fd())) {
+			if unfixed, err = fixDuplicates(ctx, report.Duplicates); err != nil {
+				return err
+			}
+		} else {
+			for _, duplicate := range report.Duplicates {
+				fmt.Printf("Claude conversation %s is bound to %s\n", duplicate.ClaudeSessionID, strings.Join(duplicate.Sessions, ", "))
+			}
+			fmt.Println("Run 'kam doctor' in a terminal to fork or rebind them.")
+		}
+	}
+	// each session sharing a conversation counts as one error of its own
+	sharing := 0
+	for _, duplicate := range report.Duplicates {
+		sharing += len(duplicate.Sessions)
+	}
+	if report.Errors > sharing || report.Warnings > 0 {
+		fmt.Println("Run 'kam validate' for the details of the session file problems.")
+	}
+
+	failures += unfixed
+	if failures > 0 {
+		return types.NewSessionError(types.ErrCodeSessionInvalid, fmt.Sprintf("doctor found %d problem(s) left to fix", failures), nil)
+	}
+	return nil
+}
+
+// printMonitors reports the session monitors running in the background and returns how many
+// outlived the kam that started them
+func printMonitors() (int, error) {
+	records, err := monitor.NewRegistry().List()
+	if err != nil {
+		return 0, err
+	}
+
+	counts := make(map[string]int)
+	for _, record := range records {
+		counts[record.Status()]++
+	}
+	fmt.Printf("Monitors:        %d running, %d orphaned\n", counts[monitor.StatusRunning], counts[monitor.StatusOrphaned])
+	if counts[monitor.StatusOrphaned] > 0 {
+		for _, record := range records {
+			if record.Status() == monitor.StatusOrphaned {
+				fmt.Printf("Monitor of '%s' (pid %d) outlived kam (pid %d)\n", record.Session, record.PID, record.Parent)
+			}
+		}
+		fmt.Println("Run 'kam gc --monitors' to stop them.")
+	}
+	return counts[monitor.StatusOrphaned], nil
+}
+
+// fixDuplicates walks through each shared conversation, asking which session keeps it and what
+// to do with the others; it returns how many conversations are still shared
+func fixDuplicates(ctx context.Context, duplicates []validate.DuplicateBinding) (int, error) {
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return 0, err
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	unfixed := 0
+	for _, duplicate := range duplicates {
+		fmt.Printf("Claude conversation %s is bound to %d sessions:\n", duplicate.ClaudeSessionID, len(duplicate.Sessions))
+		for i, name := range duplicate.Sessions {
+			fmt.Printf("  %d. %s\n", i+1, describeSharing(ctx, sessionManager, name))
+		}
+
+		keep, err := askKeeper(ctx, reader, len(duplicate.Sessions))
+		if err != nil {
+			return 0, err
+		}
+		if keep < 0 {
+			unfixed++
+			continue
+		}
+
+		fixed := true
+		for i, name := range duplicate.Sessions {
+			if i == keep {
+				continue
+			}
+			if !fixSharedSession(ctx, reader, sessionManager, name) {
+				fixed = false
+			}
+		}
+		if !fixed {
+			unfixed++
+		}
+		fmt.Println()
+	}
+	return unfixed, ctx.Err()
+}
+
+// describeSharing summarizes a session sharing a conversation, to tell which one owns it
+func describeSharing(ctx context.Context, sessionManager *session.Manager, name string) string {
+	sessionData, err := sessionManager.GetSession(ctx, name)
+	if err != nil {
+		return fmt.Sprintf("%s (%v)", name, err)
+	}
+	return fmt.Sprintf("%s  %s, created %s, last used %s", name, sessionData.Project.WorkingDirectory,
+		sessionData.Created.Local().Format("2006-01-02 15:04"), sessionData.LastAccessed.Local().Format("2006-01-02 15:04"))
+}
+
+// askKeeper asks which of count sessions keeps the conversation, returning its index or -1 to skip
+func askKeeper(ctx context.Context, reader *bufio.Reader, count int) (int, error) {
+	for {
+		fmt.Fprintf(os.Stderr, "Which session keeps the conversation? (1-%d, s to skip) [1] ", count)
+		input, err := readLine(ctx, reader)
+		if err != nil {
+			if ctx.Err() != nil {
+				return 0, err
+			}
+			return 0, fmt.Errorf("failed to read input: %w", err)
+		}
+
+		switch answer := strings.ToLower(strings.TrimSpace(input)); answer {
+		case "":
+			return 0, nil
+		case "s", "skip":
+			return -1, nil
+		default:
+			if choice, convErr := strconv.Atoi(answer); convErr == nil && choice >= 1 && choice <= count {
+				return choice - 1, nil
+			}
+		}
+	}
+}
+
+// fixSharedSession asks whether to fork or rebind a session that doesn't keep its conversation
+// and does it, reporting whether the session no longer shares it
+func fixSharedSession(ctx context.Context, reader *bufio.Reader, sessionManager *session.Manager, name string) bool {
+	for {
+		fmt.Fprintf(os.Stderr, "  %s: [f]ork a copy of the conversation, [r]ebind to a fresh one, or [s]kip? [f] ", name)
+		input, err := readLine(ctx, reader)
+		if err != nil {
+			return false
+		}
+
+		var action string
+		switch strings.ToLower(strings.TrimSpace(input)) {
+		case "", "f", "fork":
+			action = "fork"
+		case "r", "rebind":
+			action = "rebind"
+		case "s", "skip":
+			return false
+		default:
+			continue
+		}
+
+		resolved, err := sessionManager.LockSession(ctx, name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  Warning: %v\n", err)
+			return false
+		}
+		detail := ""
+		if action == "fork" {
+			detail, err = sessionManager.ForkClaudeSession(ctx, resolved)
+		} else {
+			err = sessionManager.ResetClaudeSession(ctx, resolved)
+		}
+		if unlockErr := sessionManager.UnlockSession(context.WithoutCancel(ctx), resolved); unlockErr != nil {
+			fmt.Fprintf(os.Stderr, "  Warning: %v\n", unlockErr)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  Warning: failed to %s '%s': %v\n", action, resolved, err)
+			return false
+		}
+
+		_ = logging.New().Audit(logging.AuditEntry{Action: action, Session: resolved, Detail: detail})
+		if action == "fork" {
+			fmt.Printf("  Kamui: Session '%s' continues on its own copy, conversation %s\n", resolved, detail)
+		} else {
+			fmt.Printf("  Kamui: Session '%s' starts a fresh conversation next time\n", resolved)
+		}
+		return true
+	}
+}
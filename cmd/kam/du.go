@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/stats"
+)
+
+// Du command shows each session's storage footprint
+var duCmd = &cobra.Command{
+	Use:   "du",
+	Short: "Show disk usage per session",
+	Long: `Lists every session with its storage footprint (metadata, backups and Claude
+transcript), largest first, to find old conversations worth pruning.`,
+	Args: cobra.NoArgs,
+	RunE: runDu,
+}
+
+func init() {
+	duCmd.Flags().Bool("json", false, "print the usage as JSON")
+	duCmd.Flags().IntP("limit", "n", 0, "show only the N largest sessions")
+	rootCmd.AddCommand(duCmd)
+}
+
+func runDu(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	asJSON, _ := cmd.Flags().GetBool("json")
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	store, err := openStorage(cwd)
+	if err != nil {
+		return err
+	}
+	sessions, err := stats.CollectSessions(ctx, store, homeDir, nil)
+	if err != nil {
+		return err
+	}
+	if limit > 0 && len(sessions) > limit {
+		sessions = sessions[:limit]
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(sessions)
+	}
+
+	if len(sessions) == 0 {
+		fmt.Println("Kamui: No sessions found")
+		return nil
+	}
+
+	fmt.Printf("%9s  %9s  %9s  %9s  %-16s  %-24s  %s\n", "TOTAL", "METADATA", "BACKUPS", "TRANSCRIPT", "LAST ACCESSED", "SESSION", "PROJECT")
+	for _, s := range sessions {
+		fmt.Printf("%9s  %9s  %9s  %9s  %-16s  %-24s  %s\n",
+			stats.FormatBytes(s.TotalBytes()),
+			stats.FormatBytes(s.MetadataBytes),
+			stats.FormatBytes(s.BackupBytes),
+			stats.FormatBytes(s.TranscriptBytes),
+			s.LastAccessed.Format("2006-01-02 15:04"),
+			s.SessionID,
+			s.ProjectName)
+	}
+	return nil
+}
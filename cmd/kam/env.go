@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/session"
+)
+
+// Env command prints or edits a session's environment variables
+var envCmd = &cobra.Command{
+	Use:   "env <session-name>",
+	Short: "Print a session's environment variables for shell eval",
+	Long: `Prints the session's KAMUI_* variables and custom per-session variables as shell
+statements, so an existing shell can be primed with:
+
+  eval "$(kam env my-session)"
+
+Custom variables are managed with --set NAME=VALUE and --unset NAME.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEnv,
+}
+
+func init() {
+	envCmd.Flags().StringArray("set", nil, "set a custom variable (NAME=VALUE), repeatable")
+	envCmd.Flags().StringArray("unset", nil, "remove a custom variable, repeatable")
+	envCmd.Flags().String("shell", "", "output syntax: sh, bash, zsh or fish (default detected from $SHELL)")
+	rootCmd.AddCommand(envCmd)
+}
+
+func runEnv(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	sessionName := args[0]
+	setVars, _ := cmd.Flags().GetStringArray("set")
+	unsetVars, _ := cmd.Flags().GetStringArray("unset")
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	// Editing mode: update the stored variables and stop
+	if len(setVars) > 0 || len(unsetVars) > 0 {
+		for _, assignment := range setVars {
+			name, value, ok := strings.Cut(assignment, "=")
+			if !ok {
+				return fmt.Errorf("invalid --set value '%s', expected NAME=VALUE", assignment)
+			}
+			if err := sessionManager.SetEnv(ctx, sessionName, name, value); err != nil {
+				return err
+			}
+		}
+		for _, name := range unsetVars {
+			if err := sessionManager.UnsetEnv(ctx, sessionName, name); err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(os.Stderr, "Kamui: Updated environment for session '%s'\n", sessionName)
+		return nil
+	}
+
+	sessionData, err := sessionManager.GetSession(ctx, sessionName)
+	if err != nil {
+		return err
+	}
+
+	shell, _ := cmd.Flags().GetString("shell")
+	if shell == "" {
+		shell = filepath.Base(os.Getenv("SHELL"))
+		if shell != "fish" {
+			shell = "sh"
+		}
+	}
+
+	exports, err := session.FormatExports(session.Environment(sessionData), shell)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(exports)
+	return nil
+}
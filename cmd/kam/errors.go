@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/bitomule/kamui/internal/theme"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// reportError prints a command's error to stderr: as a JSON object when the command was
+// run with --json, otherwise as a message followed by its recovery hint
+func reportError(cmd *cobra.Command, err error) {
+	report := types.NewErrorReport(err)
+
+	if cmd != nil {
+		if asJSON, flagErr := cmd.Flags().GetBool("json"); flagErr == nil && asJSON {
+			encoder := json.NewEncoder(os.Stderr)
+			encoder.SetIndent("", "  ")
+			if encoder.Encode(map[string]types.ErrorReport{"error": report}) == nil {
+				return
+			}
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	if report.Hint == "" {
+		return
+	}
+	painter := themePainter(colorEnabled() && term.IsTerminal(int(os.Stderr.Fd())))
+	fmt.Fprintln(os.Stderr, painter.Paint(theme.Muted, "Hint: "+report.Hint))
+}
+
+// exitStatus is the exit status of a child process that already reported its own failure;
+// kam exits with it without printing anything
+type exitStatus int
+
+func (s exitStatus) Error() string {
+	return fmt.Sprintf("exit status %d", int(s))
+}
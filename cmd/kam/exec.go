@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/session"
+	"github.com/bitomule/kamui/internal/trace"
+)
+
+// Exec command runs arbitrary commands in a session's context
+var execCmd = &cobra.Command{
+	Use:   "exec <session-name> -- <command> [args...]",
+	Short: "Run a command in a session's working directory with KAMUI_* variables set",
+	Long: `Runs an arbitrary command in the session's working directory with all KAMUI_*
+environment variables set, without launching Claude. The command's exit status is
+passed through, so helper scripts and Makefiles can be session-aware.`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runExec,
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+}
+
+func runExec(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	// Everything after "--" is the command to run
+	if dash := cmd.ArgsLenAtDash(); dash != 1 {
+		return fmt.Errorf("usage: kam exec <session-name> -- <command> [args...]")
+	}
+	sessionName, command := args[0], args[1:]
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	sessionData, err := sessionManager.GetSession(ctx, sessionName)
+	if err != nil {
+		return err
+	}
+
+	child := exec.Command(command[0], command[1:]...)
+	child.Dir = sessionData.Project.WorkingDirectory
+	child.Env = append(os.Environ(), session.Environment(sessionData)...)
+	child.Stdin = os.Stdin
+	child.Stdout = os.Stdout
+	child.Stderr = os.Stderr
+
+	if err := trace.Run(child); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			// Pass the command's exit status through unchanged
+			os.Exit(exitErr.ExitCode())
+		}
+		return fmt.Errorf("failed to run %s: %w", command[0], err)
+	}
+
+	return nil
+}
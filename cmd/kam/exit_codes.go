@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// exitCodesTopic documents kam's exit statuses under 'kam help exit-codes'
+var exitCodesTopic = &cobra.Command{
+	Use:   "exit-codes",
+	Short: "Exit statuses kam uses to report the kind of failure",
+	Long:  exitCodesHelp(),
+}
+
+func init() {
+	rootCmd.AddCommand(exitCodesTopic)
+}
+
+// exitCodesHelp renders the exit status table
+func exitCodesHelp() string {
+	var text strings.Builder
+	text.WriteString("kam exits with a status describing why a command failed, so scripts can branch\n")
+	text.WriteString("on it instead of parsing error messages. 'kam exec' and plugins exit with the\n")
+	text.WriteString("status of the command they ran.\n\n")
+
+	for _, info := range types.ExitCodes {
+		codes := make([]string, 0, len(info.Codes))
+		for _, code := range info.Codes {
+			codes = append(codes, string(code))
+		}
+		line := fmt.Sprintf("  %3d  %-36s %s", info.Status, info.Meaning, strings.Join(codes, ", "))
+		text.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+	return strings.TrimRight(text.String(), "\n")
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/transcript"
+)
+
+// Export-transcript command renders a session's Claude conversation as a document
+var exportTranscriptCmd = &cobra.Command{
+	Use:   "export-transcript <session-name>",
+	Short: "Export a session's Claude conversation as Markdown or HTML",
+	Long: `Converts the session's Claude transcript into a readable document, with each tool
+call in a collapsible section, suitable for attaching to a PR or design doc.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExportTranscript,
+}
+
+func init() {
+	exportTranscriptCmd.Flags().StringP("format", "f", "md", "output format: md or html")
+	exportTranscriptCmd.Flags().StringP("output", "o", "", "write to this file instead of stdout")
+	rootCmd.AddCommand(exportTranscriptCmd)
+}
+
+func runExportTranscript(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	formatName, _ := cmd.Flags().GetString("format")
+	output, _ := cmd.Flags().GetString("output")
+
+	format, err := transcript.ParseFormat(formatName)
+	if err != nil {
+		return err
+	}
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	sessionData, path, err := sessionManager.TranscriptPath(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	turns, err := readTurns(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	title := sessionData.SessionID
+	if sessionData.Metadata.Description != "" {
+		title = fmt.Sprintf("%s: %s", sessionData.SessionID, sessionData.Metadata.Description)
+	}
+
+	var w io.Writer = os.Stdout
+	if output != "" {
+		file, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if err := transcript.ExportTurns(w, title, turns, format); err != nil {
+		return err
+	}
+
+	if output != "" {
+		fmt.Fprintf(os.Stderr, "Kamui: Wrote %s\n", output)
+	}
+	return nil
+}
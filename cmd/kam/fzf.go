@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/bitomule/kamui/internal/links"
+	"github.com/bitomule/kamui/internal/session"
+	"github.com/bitomule/kamui/internal/trace"
+)
+
+// Pickers the bare `kam` can show (ui.picker)
+const (
+	pickerTUI  = "tui"
+	pickerMenu = "menu"
+	pickerFzf  = "fzf"
+)
+
+// configuredPicker returns the picker ui.picker asks for, warning about unknown ones
+func configuredPicker() string {
+	switch picker := viper.GetString("ui.picker"); picker {
+	case pickerTUI, pickerMenu, pickerFzf:
+		return picker
+	default:
+		fmt.Fprintf(os.Stderr, "Warning: unknown ui.picker '%s' (use tui, menu or fzf), using the TUI\n", picker)
+		return pickerTUI
+	}
+}
+
+// fzfPicker returns the fzf executable when it is installed
+func fzfPicker() (string, bool) {
+	fzfPath, err := exec.LookPath("fzf")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: ui.picker is fzf but fzf is not in PATH, using the TUI")
+		return "", false
+	}
+	return fzfPath, true
+}
+
+// pickWithFzf lets the user choose one of the sessions in fzf, previewing each with kam info
+// It returns "" when the user quits without choosing
+func pickWithFzf(ctx context.Context, fzfPath string, sessionManager *session.Manager, sessions []session.Summary) (string, error) {
+	var input bytes.Buffer
+	for _, summary := range sessions {
+		fmt.Fprintf(&input, "%s\t%s\n", summary.Name, describePickerEntry(summary))
+	}
+
+	kamPath, err := os.Executable()
+	if err != nil {
+		kamPath = "kam"
+	}
+	// fzf runs the preview through the user's shell, so quote the path
+	kamPath = "'" + strings.ReplaceAll(kamPath, "'", `'\''`) + "'"
+	cmd := exec.CommandContext(ctx, fzfPath,
+		"--delimiter", "\t",
+		"--prompt", sessionManager.GetProjectName()+"> ",
+		"--preview", kamPath+" info {1}",
+		"--height", "40%",
+		"--reverse",
+	)
+	cmd.Stdin = &input
+	cmd.Stderr = os.Stderr
+	output, err := trace.Output(cmd)
+	if err != nil {
+		// fzf exits 1 when nothing matched and 130 when the user pressed Esc or Ctrl-C
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && (exitErr.ExitCode() == 1 || exitErr.ExitCode() == 130) && ctx.Err() == nil {
+			return "", nil
+		}
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", fmt.Errorf("fzf failed: %w", err)
+	}
+
+	selected, _, _ := strings.Cut(strings.TrimRight(string(output), "\n"), "\t")
+	return selected, nil
+}
+
+// describePickerEntry summarizes a session on one line for fzf
+func describePickerEntry(summary session.Summary) string {
+	entry := "last accessed " + summary.LastAccessed.Format("2006-01-02 15:04")
+	if summary.Pinned {
+		entry = pinIcon + " " + entry
+	}
+	if summary.Lock != nil {
+		entry += "  [locked]"
+	}
+	if linkSummary := links.Summary(summary.Links); linkSummary != "" {
+		entry += "  " + linkSummary
+	}
+	if summary.Description != "" {
+		entry += "  " + summary.Description
+	}
+	return entry
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/bitomule/kamui/internal/adopt"
+	"github.com/bitomule/kamui/internal/logging"
+	"github.com/bitomule/kamui/internal/monitor"
+	"github.com/bitomule/kamui/internal/paths"
+	"github.com/bitomule/kamui/internal/stats"
+	"github.com/bitomule/kamui/internal/transcript"
+)
+
+// GC command runs Kamui's housekeeping on demand
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Clean up old Kamui data",
+	Long: `Runs Kamui's housekeeping: deletes log files and audit entries older than
+storage.logRetentionDays (default 7), then the oldest log files beyond
+storage.logMaxSize (default 50MB). The daemon runs the same housekeeping daily.
+
+With --transcripts, deletes the Claude transcripts of the current project that no
+session is bound to and that haven't been written for --older-than (default
+transcript.gcAfter, 30d). Transcripts you want to keep unbound can be protected with
+--keep <conversation-id> and released again with --unkeep.
+
+With --monitors, stops the background monitors that bind new sessions to their
+conversation when they outlived the kam that started them, and drops the records
+of monitors that are gone. A monitor is only stopped when its pid still has the
+start time it recorded, so a process that later reused the pid is never killed.
+
+Asks for confirmation first unless --yes is given or ui.confirmDestructive is off.
+--dry-run lists the files that would be removed or rewritten without touching them.`,
+	Args: cobra.NoArgs,
+	RunE: runGC,
+}
+
+func init() {
+	gcCmd.Flags().Bool("dry-run", false, "list what would be removed without removing anything")
+	gcCmd.Flags().Bool("transcripts", false, "delete Claude transcripts no session is bound to instead of logs")
+	gcCmd.Flags().Bool("monitors", false, "stop orphaned session monitors instead of cleaning logs")
+	gcCmd.Flags().String("older-than", "", "only delete transcripts last written before this (e.g. 30d, 2025-01-31)")
+	gcCmd.Flags().String("keep", "", "protect a Claude conversation from transcript garbage collection")
+	gcCmd.Flags().String("unkeep", "", "stop protecting a Claude conversation")
+	rootCmd.AddCommand(gcCmd)
+}
+
+func runGC(cmd *cobra.Command, _ []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	keep, _ := cmd.Flags().GetString("keep")
+	unkeep, _ := cmd.Flags().GetString("unkeep")
+	transcripts, _ := cmd.Flags().GetBool("transcripts")
+	monitors, _ := cmd.Flags().GetBool("monitors")
+
+	switch {
+	case keep != "" || unkeep != "":
+		return updateKeepList(keep, unkeep)
+	case transcripts:
+		olderThan, _ := cmd.Flags().GetString("older-than")
+		return gcTranscripts(cmd.Context(), olderThan, dryRun)
+	case monitors:
+		return gcMonitors(cmd.Context(), dryRun)
+	}
+
+	if dryRun {
+		result, err := runHousekeeping(newLogger(), true)
+		if err != nil {
+			return err
+		}
+		var rewritten []string
+		if result.AuditFile != "" {
+			rewritten = append(rewritten, fmt.Sprintf("%s (dropping %d entries)", result.AuditFile, result.RemovedAuditEntries))
+		}
+		printDryRun(result.LogFiles, rewritten)
+		return nil
+	}
+
+	proceed, err := confirmDestructive(cmd.Context(), fmt.Sprintf("Delete logs and audit entries older than %d days, and logs beyond %s?",
+		viper.GetInt("storage.logRetentionDays"), viper.GetString("storage.logMaxSize")))
+	if err != nil || !proceed {
+		return err
+	}
+
+	result, err := runHousekeeping(newLogger(), false)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Kamui: Removed %d log file(s) and %d audit entries (older than %d days or beyond %s)\n",
+		result.RemovedLogFiles, result.RemovedAuditEntries, viper.GetInt("storage.logRetentionDays"), viper.GetString("storage.logMaxSize"))
+	return nil
+}
+
+// newLogger returns Kamui's logger, rotating at storage.logFileSize and capped at storage.logMaxSize
+// Invalid sizes are reported and leave that limit off
+func newLogger() *logging.Logger {
+	logger := logging.New()
+	fileSize, err := transcript.ParseSize(viper.GetString("storage.logFileSize"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: storage.logFileSize: %v\n", err)
+	}
+	maxSize, err := transcript.ParseSize(viper.GetString("storage.logMaxSize"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: storage.logMaxSize: %v\n", err)
+	}
+	logger.SetLimits(fileSize, maxSize)
+	return logger
+}
+
+// runHousekeeping enforces log retention; a dry run only reports what would be removed
+func runHousekeeping(logger *logging.Logger, dryRun bool) (logging.PruneResult, error) {
+	return logger.Prune(viper.GetInt("storage.logRetentionDays"), dryRun)
+}
+
+// updateKeepList protects or releases a conversation in the transcript keep list
+func updateKeepList(keep, unkeep string) error {
+	keepList := adopt.NewKeepList()
+	if keep != "" {
+		if err := keepList.Add(keep); err != nil {
+			return err
+		}
+		fmt.Printf("Kamui: Transcript %s is kept out of garbage collection\n", keep)
+	}
+	if unkeep != "" {
+		removed, err := keepList.Remove(unkeep)
+		if err != nil {
+			return err
+		}
+		if removed {
+			fmt.Printf("Kamui: Transcript %s is no longer kept\n", unkeep)
+		} else {
+			fmt.Printf("Kamui: Transcript %s was not kept\n", unkeep)
+		}
+	}
+	return nil
+}
+
+// gcTranscripts deletes the current project's transcripts that no session is bound to and that
+// haven't been written since olderThan
+func gcTranscripts(ctx context.Context, olderThan string, dryRun bool) error {
+	if olderThan == "" {
+		olderThan = viper.GetString("transcript.gcAfter")
+	}
+	cutoff, err := parseCutoff("--older-than", olderThan, time.Now())
+	if err != nil {
+		return err
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	store, err := openStorage(cwd)
+	if err != nil {
+		return err
+	}
+	bound, err := adopt.ReferencedSessions(ctx, store)
+	if err != nil {
+		return fmt.Errorf("can't tell which transcripts are in use, run 'kam validate': %w", err)
+	}
+	kept, err := adopt.NewKeepList().IDs()
+	if err != nil {
+		return err
+	}
+
+	orphans, err := adopt.Orphans(ctx, paths.ClaudeProjectDir(homeDir, cwd), bound, kept, cutoff)
+	if err != nil {
+		return err
+	}
+	if len(orphans) == 0 {
+		fmt.Printf("Kamui: No unreferenced transcripts older than %s\n", olderThan)
+		return nil
+	}
+
+	var total int64
+	removed := make([]string, 0, len(orphans))
+	for _, orphan := range orphans {
+		total += orphan.Size
+		removed = append(removed, fmt.Sprintf("%s (%s, last written %s)", orphan.Path,
+			stats.FormatBytes(orphan.Size), orphan.ModTime.Local().Format("2006-01-02")))
+	}
+	if dryRun {
+		printDryRun(removed, nil)
+		return nil
+	}
+
+	fmt.Println("Unreferenced transcripts:")
+	for _, line := range removed {
+		fmt.Printf("  %s\n", line)
+	}
+	proceed, err := confirmDestructive(ctx, fmt.Sprintf("Delete %d unreferenced transcript(s) (%s)?", len(orphans), stats.FormatBytes(total)))
+	if err != nil || !proceed {
+		return err
+	}
+
+	deleted := 0
+	var freed int64
+	for _, orphan := range orphans {
+		if err := orphan.Remove(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			continue
+		}
+		deleted++
+		freed += orphan.Size
+		_ = logging.New().Audit(logging.AuditEntry{Action: "gc-transcript", Detail: orphan.ClaudeSessionID})
+	}
+	fmt.Printf("Kamui: Deleted %d transcript(s), freeing %s\n", deleted, stats.FormatBytes(freed))
+	return nil
+}
+
+// gcMonitors stops orphaned session monitors and drops the records of those that exited
+func gcMonitors(ctx context.Context, dryRun bool) error {
+	registry := monitor.NewRegistry()
+	records, err := registry.List()
+	if err != nil {
+		return err
+	}
+
+	var reapable []monitor.Record
+	var described []string
+	orphaned := 0
+	for _, record := range records {
+		switch record.Status() {
+		case monitor.StatusOrphaned:
+			orphaned++
+			described = append(described, fmt.Sprintf("monitor of '%s' (pid %d, started %s)", record.Session, record.PID,
+				record.Started.Local().Format("2006-01-02 15:04")))
+		case monitor.StatusStale:
+			described = append(described, fmt.Sprintf("record of the exited monitor of '%s'", record.Session))
+		default:
+			continue
+		}
+		reapable = append(reapable, record)
+	}
+	if len(reapable) == 0 {
+		fmt.Println("Kamui: No orphaned monitors")
+		return nil
+	}
+	if dryRun {
+		printDryRun(described, nil)
+		return nil
+	}
+
+	if orphaned > 0 {
+		proceed, err := confirmDestructive(ctx, fmt.Sprintf("Stop %d orphaned monitor(s)?", orphaned))
+		if err != nil || !proceed {
+			return err
+		}
+	}
+
+	stopped, dropped := 0, 0
+	for _, record := range reapable {
+		wasOrphaned := record.Status() == monitor.StatusOrphaned
+		if err := registry.Reap(record); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			continue
+		}
+		if !wasOrphaned {
+			dropped++
+			continue
+		}
+		stopped++
+		_ = logging.New().Audit(logging.AuditEntry{Action: "gc-monitor", Session: record.Session, Detail: fmt.Sprintf("pid %d", record.PID)})
+	}
+	fmt.Printf("Kamui: Stopped %d orphaned monitor(s), dropped %d stale record(s)\n", stopped, dropped)
+	return nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/index"
+)
+
+// Index command group for the global session index
+var indexCmd = &cobra.Command{
+	Use:   "index",
+	Short: "Manage the global session index",
+	Long: `The global index in ~/.claude/kamui-index.json summarizes every session in
+~/.claude/kamui-sessions so 'kam sessions --global' can list them across projects
+without reading each file. It is updated as sessions are saved and deleted, and
+synced with the sessions directory when older than storage.indexSyncInterval.
+Disable it with storage.enableGlobalIndex: false.`,
+}
+
+var indexSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Rebuild the global index from the sessions directory",
+	Long: `Adds sessions missing from the global index, refreshes outdated entries and
+removes stale ones for sessions that no longer exist. Sessions that can't be read
+keep their previous entry; an index that can't be parsed is rebuilt.`,
+	Args: cobra.NoArgs,
+	RunE: runIndexSync,
+}
+
+func init() {
+	indexCmd.AddCommand(indexSyncCmd)
+	rootCmd.AddCommand(indexCmd)
+}
+
+func runIndexSync(cmd *cobra.Command, _ []string) error {
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	result, err := sessionManager.SyncIndex(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	if result.Rebuilt {
+		fmt.Fprintf(os.Stderr, "Warning: %s couldn't be parsed and was rebuilt\n", index.DefaultPath())
+	}
+	for _, name := range result.Unreadable {
+		fmt.Fprintf(os.Stderr, "Warning: session '%s' couldn't be read, its entry was kept\n", name)
+	}
+	if !result.Changed() {
+		fmt.Println("Kamui: The global index is up to date")
+		return nil
+	}
+	printIndexChanges("Added", result.Added)
+	printIndexChanges("Updated", result.Updated)
+	printIndexChanges("Removed", result.Removed)
+	return nil
+}
+
+// printIndexChanges prints one kind of change a sync made, if it made any
+func printIndexChanges(label string, names []string) {
+	if len(names) == 0 {
+		return
+	}
+	fmt.Printf("Kamui: %s %d session(s): %s\n", label, len(names), strings.Join(names, ", "))
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/jira"
+	"github.com/bitomule/kamui/internal/links"
+	"github.com/bitomule/kamui/internal/session"
+	"github.com/bitomule/kamui/internal/stats"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Info command shows the details of a single session
+var infoCmd = &cobra.Command{
+	Use:   "info <session-name>",
+	Short: "Show details, disk usage and estimated cost of a session",
+	Long: `Shows a session's project, state, Claude conversation and tags, along with the
+size of its Claude transcript, the tokens it used and an estimated cost.
+
+The estimate uses the pricing table, which can be overridden under "pricing" in the
+config file. Models without a price are listed and left out of the estimate.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runInfo,
+}
+
+func init() {
+	rootCmd.AddCommand(infoCmd)
+}
+
+func runInfo(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	sessionData, err := sessionManager.GetSession(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Session:      %s\n", sessionData.SessionID)
+	if sessionData.Metadata.Description != "" {
+		fmt.Printf("Description:  %s\n", sessionData.Metadata.Description)
+	}
+	fmt.Printf("Project:      %s (%s)\n", sessionData.Project.Name, sessionData.Project.Path)
+	if sessionData.Project.Package != "" {
+		fmt.Printf("Package:      %s\n", sessionData.Project.Package)
+	}
+	fmt.Printf("Working dir:  %s\n", sessionData.Project.WorkingDirectory)
+	fmt.Printf("State:        %s\n", sessionData.Lifecycle.State)
+	if sessionData.Metadata.Variant != "" {
+		fmt.Printf("Variant:      %s\n", sessionData.Metadata.Variant)
+	}
+	if sessionData.Metadata.Pinned {
+		fmt.Printf("Pinned:       %s yes\n", pinIcon)
+	}
+	if lock, lockErr := sessionManager.ManualLock(sessionData.SessionID); lockErr == nil && lock != nil {
+		fmt.Printf("Locked:       %s\n", describeLock(lock))
+	}
+	if sessionData.Metadata.Protected {
+		fmt.Printf("Protected:    yes (delete, prune and trim need --force)\n")
+	}
+	if sessionData.Metadata.ReadOnly {
+		fmt.Printf("Read-only:    yes (Claude runs in %s mode)\n", session.ReadOnlyPermissionMode)
+	}
+	if !sessionData.Metadata.ReadOnly && sessionData.Claude.PermissionMode != "" {
+		fmt.Printf("Permissions:  %s mode\n", sessionData.Claude.PermissionMode)
+	}
+	fmt.Printf("Created:      %s\n", sessionData.Created.Format("2006-01-02 15:04"))
+	fmt.Printf("Last used:    %s\n", sessionData.LastAccessed.Format("2006-01-02 15:04"))
+	if completed := sessionData.Stats.CompletedAt; completed != nil {
+		detail := ""
+		if sessionData.Stats.SessionCount > 0 {
+			detail = fmt.Sprintf(" (active %s over %d run(s))", sessionData.Stats.TotalDuration, sessionData.Stats.SessionCount)
+		}
+		fmt.Printf("Completed:    %s%s\n", completed.Local().Format("2006-01-02 15:04"), detail)
+	}
+	if sessionData.Claude.Pending {
+		fmt.Printf("Claude ID:    %s (not started yet)\n", sessionData.Claude.SessionID)
+	} else if sessionData.Claude.SessionID != "" {
+		fmt.Printf("Claude ID:    %s\n", sessionData.Claude.SessionID)
+	}
+	fmt.Printf("Launches as:  %s\n", sessionManager.GetClaudeCommand(sessionData))
+	if sessionData.Claude.CLIVersion != "" {
+		fmt.Printf("Claude Code:  %s\n", sessionData.Claude.CLIVersion)
+	}
+	if len(sessionData.Metadata.Tags) > 0 {
+		fmt.Printf("Tags:         %s\n", strings.Join(sessionData.Metadata.Tags, ", "))
+	}
+	jiraClient, _ := newJiraClient()
+	for i, link := range sessionData.Metadata.Links {
+		label := ""
+		if i == 0 {
+			label = "Links:"
+		}
+		fmt.Printf("%-13s %s%s (%s)\n", label, link.Label, linkDetail(link, jiraClient), link.URL)
+	}
+	if len(sessionData.Metadata.Notes) > 0 {
+		fmt.Printf("Notes:        %d\n", len(sessionData.Metadata.Notes))
+	}
+	if len(sessionData.Metadata.Env) > 0 {
+		fmt.Printf("Env vars:     %d\n", len(sessionData.Metadata.Env))
+	}
+
+	fmt.Printf("Transcript:   %s\n", stats.FormatBytes(stats.TranscriptBytes(sessionData, homeDir)))
+
+	estimate, err := stats.EstimateCost(ctx, sessionData, homeDir, pricingTable())
+	if err != nil {
+		return fmt.Errorf("failed to read transcript: %w", err)
+	}
+	fmt.Printf("Tokens:       %s\n", formatTokens(estimate.Tokens))
+	fmt.Printf("Est. cost:    ~%s\n", formatCost(estimate.CostUSD))
+	if len(estimate.Unpriced) > 0 {
+		fmt.Printf("Unpriced:     %s\n", strings.Join(estimate.Unpriced, ", "))
+	}
+	return nil
+}
+
+// linkDetail returns the title of a link and, for Jira issues when Jira is configured, its live status
+func linkDetail(link types.Link, jiraClient *jira.Client) string {
+	title, status := link.Title, ""
+	if link.Kind == links.KindJira && jiraClient != nil {
+		if issue, err := jiraClient.GetIssue(link.Label); err == nil {
+			title, status = issue.Summary, issue.Status
+		} else {
+			status = "status unavailable"
+		}
+	}
+
+	detail := ""
+	if title != "" {
+		detail += " " + title
+	}
+	if status != "" {
+		detail += " [" + status + "]"
+	}
+	return detail
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/bitomule/kamui/internal/registry"
+)
+
+// jumpFileEnv names the file the shell integration reads the chosen project's directory from
+const jumpFileEnv = "KAMUI_JUMP_FILE"
+
+// Jump command switches to another project and opens its sessions
+var jumpCmd = &cobra.Command{
+	Use:   "jump [project]",
+	Short: "Switch to another project and open its session picker",
+	Long: `Finds a project from 'kam projects' by name or path, fuzzily ("kmi" finds
+"kamui"), and opens its session picker, or the session given with --session.
+Without a query, or when several projects match equally well, it asks which one.
+
+kam can't change the directory of the shell that runs it. To end up in the
+project, add the shell integration to your shell's startup file and use kj:
+
+  eval "$(kam jump --init zsh)"     # or bash; fish: kam jump --init fish | source
+  kj api                            # cd into the api project and pick a session`,
+	Args:        cobra.MaximumNArgs(1),
+	RunE:        runJump,
+	Annotations: launchesClaude,
+}
+
+func init() {
+	jumpCmd.Flags().StringP("session", "s", "", "open this session instead of the picker")
+	jumpCmd.Flags().String("init", "", "print the kj shell function for bash, zsh or fish")
+	rootCmd.AddCommand(jumpCmd)
+}
+
+func runJump(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	shell, _ := cmd.Flags().GetString("init")
+	sessionName, _ := cmd.Flags().GetString("session")
+	if shell != "" {
+		script, err := jumpShellFunction(shell)
+		if err != nil {
+			return err
+		}
+		fmt.Print(script)
+		return nil
+	}
+
+	store := registry.NewStore()
+	projects, err := store.List()
+	if err != nil {
+		return err
+	}
+	if len(projects) == 0 {
+		if err := rebuildRegistry(ctx, store); err != nil {
+			return err
+		}
+		if projects, err = store.List(); err != nil {
+			return err
+		}
+	}
+
+	// projects whose directory is gone can't be jumped to
+	existing := projects[:0]
+	for _, project := range projects {
+		if info, statErr := os.Stat(project.Path); statErr == nil && info.IsDir() {
+			existing = append(existing, project)
+		}
+	}
+
+	query := ""
+	if len(args) == 1 {
+		query = args[0]
+	}
+	matches := registry.MatchProjects(existing, query)
+	if len(matches) == 0 {
+		if query == "" {
+			return fmt.Errorf("no projects to jump to; 'kam projects' lists the ones Kamui knows")
+		}
+		return fmt.Errorf("no project matches '%s'; 'kam projects' lists the ones Kamui knows", query)
+	}
+
+	project := matches[0].Project
+	if query == "" || !registry.Unambiguous(matches) {
+		chosen, ok, pickErr := pickProject(ctx, matches)
+		if pickErr != nil || !ok {
+			return pickErr
+		}
+		project = chosen
+	}
+
+	// the shell integration changes directory once kam exits, whatever happens in the project
+	if jumpFile := os.Getenv(jumpFileEnv); jumpFile != "" {
+		if err := os.WriteFile(jumpFile, []byte(project.Path+"\n"), 0o600); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to pass the project directory to the shell: %v\n", err)
+		}
+	}
+	if err := os.Chdir(project.Path); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Kamui: Jumped to %s (%s)\n", project.Name, project.Path)
+
+	if sessionName != "" {
+		return runSession(cmd, []string{sessionName})
+	}
+	return runSession(cmd, nil)
+}
+
+// pickProject asks which of several matching projects to jump to
+// It returns false when the user quits without choosing
+func pickProject(ctx context.Context, matches []registry.Match) (registry.Project, bool, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		names := make([]string, 0, len(matches))
+		for _, match := range matches {
+			names = append(names, match.Project.Name)
+		}
+		return registry.Project{}, false, fmt.Errorf("several projects match: %s; narrow the query", strings.Join(names, ", "))
+	}
+
+	fmt.Fprintln(os.Stderr, "Kamui: Projects:")
+	for i, match := range matches {
+		lastActivity := "-"
+		if !match.Project.LastActivity.IsZero() {
+			lastActivity = match.Project.LastActivity.Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(os.Stderr, "  %2d. %-24s %3d session(s)  %s  %s\n", i+1, match.Project.Name, match.Project.Sessions, lastActivity, match.Project.Path)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Fprintf(os.Stderr, "Select a project (1-%d) or 'q' to quit: ", len(matches))
+		input, err := readLine(ctx, reader)
+		if err != nil {
+			if ctx.Err() != nil {
+				return registry.Project{}, false, err
+			}
+			return registry.Project{}, false, fmt.Errorf("failed to read input: %w", err)
+		}
+		input = strings.TrimSpace(input)
+		if input == "q" || input == "Q" {
+			return registry.Project{}, false, nil
+		}
+		selection, err := strconv.Atoi(input)
+		if err != nil || selection < 1 || selection > len(matches) {
+			fmt.Fprintf(os.Stderr, "Kamui: Invalid selection. Please enter a number between 1 and %d, or 'q' to quit.\n", len(matches))
+			continue
+		}
+		return matches[selection-1].Project, true, nil
+	}
+}
+
+// jumpShellFunction returns the kj function that runs kam jump and then changes into the project
+func jumpShellFunction(shell string) (string, error) {
+	switch filepath.Base(shell) {
+	case "sh", "bash", "zsh":
+		return fmt.Sprintf(`kj() {
+  local kamui_file kamui_dir kamui_status
+  kamui_file="$(mktemp)" || return
+  %s="$kamui_file" command kam jump "$@"
+  kamui_status=$?
+  kamui_dir="$(cat "$kamui_file")"
+  rm -f "$kamui_file"
+  [ -n "$kamui_dir" ] && cd "$kamui_dir"
+  return $kamui_status
+}
+`, jumpFileEnv), nil
+	case "fish":
+		return fmt.Sprintf(`function kj --description 'Jump to a Kamui project'
+    set -l kamui_file (mktemp); or return
+    env %s=$kamui_file kam jump $argv
+    set -l kamui_status $status
+    set -l kamui_dir (cat $kamui_file)
+    rm -f $kamui_file
+    test -n "$kamui_dir"; and cd $kamui_dir
+    return $kamui_status
+end
+`, jumpFileEnv), nil
+	}
+	return "", fmt.Errorf("unsupported shell '%s' (use bash, zsh or fish)", shell)
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/jira"
+	"github.com/bitomule/kamui/internal/links"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Link command ties a session to issues, pull requests and other URLs
+var linkCmd = &cobra.Command{
+	Use:   "link <session-name> [url]",
+	Short: "Link a session to an issue, pull request or URL",
+	Long: `Attaches a URL to a session. GitHub and GitLab issues and pull/merge requests,
+Jira and Linear issues are recognized and shown by their short label (org/repo#123,
+PROJ-123) in 'kam list' and 'kam info'.
+
+With the Jira integration configured (jira.baseUrl, jira.email and jira.apiToken or
+JIRA_API_TOKEN), --jira PROJ-123 links an issue by key and stores its title.
+
+Without a URL, lists the session's links. Use --remove with a URL, label or kind
+(github-pr, jira, ...) to detach links.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runLink,
+}
+
+// Open-link command opens a session's link in the browser
+var openLinkCmd = &cobra.Command{
+	Use:   "open-link <session-name> [url|label|kind|number]",
+	Short: "Open a session's link in the browser",
+	Long: `Opens one of the session's links in the default browser: the first one, or the one
+selected by URL, label (org/repo#123), kind (github-pr) or position in 'kam link <session>'.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runOpenLink,
+}
+
+func init() {
+	linkCmd.Flags().String("remove", "", "remove the links matching this URL, label or kind")
+	linkCmd.Flags().String("jira", "", "link a Jira issue by key (PROJ-123)")
+	rootCmd.AddCommand(linkCmd)
+	rootCmd.AddCommand(openLinkCmd)
+}
+
+func runLink(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	remove, _ := cmd.Flags().GetString("remove")
+	jiraKey, _ := cmd.Flags().GetString("jira")
+	sessionName := args[0]
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	given := 0
+	for _, set := range []bool{remove != "", jiraKey != "", len(args) == 2} {
+		if set {
+			given++
+		}
+	}
+	if given > 1 {
+		return fmt.Errorf("specify only one of a URL, --jira or --remove")
+	}
+
+	switch {
+	case jiraKey != "":
+		link, err := jiraLink(jiraKey)
+		if err != nil {
+			return err
+		}
+		if err := sessionManager.AddLink(ctx, sessionName, link); err != nil {
+			return err
+		}
+		fmt.Printf("Kamui: Linked session '%s' to %s %s\n", sessionName, link.Label, link.Title)
+		return nil
+
+	case remove != "":
+		removed, err := sessionManager.RemoveLink(ctx, sessionName, func(link types.Link) bool {
+			return links.Matches(link, remove)
+		})
+		if err != nil {
+			return err
+		}
+		if removed == 0 {
+			return fmt.Errorf("no link matching '%s' on session '%s'", remove, sessionName)
+		}
+		fmt.Printf("Kamui: Removed %d link(s) from session '%s'\n", removed, sessionName)
+		return nil
+
+	case len(args) == 2:
+		link, err := links.Parse(args[1])
+		if err != nil {
+			return err
+		}
+		if link.Kind == links.KindJira {
+			// Resolve the title when the issue lives on the configured Jira
+			if resolved, err := jiraLink(link.Label); err == nil && resolved.URL == link.URL {
+				link = resolved
+			}
+		}
+		if err := sessionManager.AddLink(ctx, sessionName, link); err != nil {
+			return err
+		}
+		fmt.Printf("Kamui: Linked session '%s' to %s\n", sessionName, link.Label)
+		return nil
+	}
+
+	sessionData, err := sessionManager.GetSession(ctx, sessionName)
+	if err != nil {
+		return err
+	}
+	if len(sessionData.Metadata.Links) == 0 {
+		fmt.Printf("Kamui: Session '%s' has no links (add one with 'kam link %s <url>')\n", sessionName, sessionName)
+		return nil
+	}
+	for i, link := range sessionData.Metadata.Links {
+		fmt.Printf("  %d. %-24s %-13s %s\n", i+1, link.Label, link.Kind, link.URL)
+		if link.Title != "" {
+			fmt.Printf("     %s\n", link.Title)
+		}
+	}
+	return nil
+}
+
+func runOpenLink(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	sessionData, err := sessionManager.GetSession(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	sessionLinks := sessionData.Metadata.Links
+	if len(sessionLinks) == 0 {
+		return fmt.Errorf("session '%s' has no links", sessionData.SessionID)
+	}
+
+	link := sessionLinks[0]
+	if len(args) == 2 {
+		selected, ok := selectLink(sessionLinks, args[1])
+		if !ok {
+			return fmt.Errorf("no link matching '%s' on session '%s'", args[1], sessionData.SessionID)
+		}
+		link = selected
+	}
+
+	fmt.Printf("Kamui: Opening %s\n", link.URL)
+	return links.Open(link.URL)
+}
+
+// jiraLink resolves a Jira issue key into a link carrying the issue's title
+func jiraLink(key string) (types.Link, error) {
+	if !jira.ValidKey(key) {
+		return types.Link{}, fmt.Errorf("invalid Jira issue key '%s' (expected e.g. PROJ-123)", key)
+	}
+
+	client, err := newJiraClient()
+	if err != nil {
+		return types.Link{}, err
+	}
+	issue, err := client.GetIssue(key)
+	if err != nil {
+		return types.Link{}, err
+	}
+
+	return types.Link{Kind: links.KindJira, URL: client.IssueURL(issue.Key), Label: issue.Key, Title: issue.Summary}, nil
+}
+
+// selectLink picks a link by 1-based position, URL, label or kind
+func selectLink(sessionLinks []types.Link, selector string) (types.Link, bool) {
+	if index, err := strconv.Atoi(selector); err == nil {
+		if index >= 1 && index <= len(sessionLinks) {
+			return sessionLinks[index-1], true
+		}
+		return types.Link{}, false
+	}
+
+	for _, link := range sessionLinks {
+		if links.Matches(link, selector) {
+			return link, true
+		}
+	}
+	return types.Link{}, false
+}
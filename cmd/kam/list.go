@@ -0,0 +1,255 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/links"
+	"github.com/bitomule/kamui/internal/session"
+	"github.com/bitomule/kamui/internal/theme"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// List command shows the project's sessions, grouped by package inside monorepo workspaces
+var listCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"sessions"},
+	Short:   "List the current project's sessions",
+	Long: `Lists the sessions of the current project.
+
+Inside a monorepo workspace (go.work, pnpm-workspace.yaml or a Cargo workspace)
+sessions are grouped by the package they were started in.
+
+--global lists every project's sessions, grouped by project, from the global
+index in ~/.claude/kamui-index.json. The index is synced with the sessions
+directory when it is older than storage.indexSyncInterval (5m by default);
+kam index sync repairs it on demand. Indexed listings leave out packages,
+links and the pinned, protected, read-only and bypass markers.
+
+Pinned sessions come first, then the rest by --sort: last-accessed (default,
+most recent first), created (newest first) or name. --state and --tag keep only
+matching sessions, --bound and --unbound those with or without a Claude
+conversation.
+
+--filter takes an expression for finer slicing, e.g.
+
+  kam list --filter 'state=active && tag=backend && lastAccessed>7d'
+
+Conditions compare a field with a value and are joined with && and ||, negated
+with ! and grouped with parentheses. Fields:
+
+  name, description, state, project, package, directory, claude
+      = and != (* and ? are wildcards, case is ignored), ~ (contains)
+  tag
+      = matches any tag, != matches when no tag does
+  created, lastAccessed
+      <, <=, >, >= with a date (2026-10-01) or an age: lastAccessed>7d is
+      more than 7 days ago
+  bound, pinned, protected, readOnly, locked
+      on their own, or = and != with true or false`,
+	Args: cobra.NoArgs,
+	RunE: runList,
+}
+
+func init() {
+	listCmd.Flags().String("sort", string(session.SortLastAccessed), "order sessions by last-accessed, created or name")
+	listCmd.Flags().Bool("reverse", false, "reverse the order")
+	listCmd.Flags().StringSlice("state", nil, "only list sessions in these states")
+	listCmd.Flags().StringSlice("tag", nil, "only list sessions with all of these tags")
+	listCmd.Flags().Bool("bound", false, "only list sessions with a Claude conversation")
+	listCmd.Flags().Bool("unbound", false, "only list sessions without a Claude conversation")
+	listCmd.MarkFlagsMutuallyExclusive("bound", "unbound")
+	listCmd.Flags().String("filter", "", "only list sessions matching an expression, e.g. 'state=active && lastAccessed>7d'")
+	listCmd.Flags().Bool("global", false, "list every project's sessions from the global index")
+	rootCmd.AddCommand(listCmd)
+}
+
+// listOptions reads kam list's sort and filter flags
+func listOptions(cmd *cobra.Command) (session.ListOptions, error) {
+	sortFlag, _ := cmd.Flags().GetString("sort")
+	sortKey, err := session.ParseSortKey(sortFlag)
+	if err != nil {
+		return session.ListOptions{}, err
+	}
+	reverse, _ := cmd.Flags().GetBool("reverse")
+	opts := session.ListOptions{Sort: sortKey, Reverse: reverse, PinnedFirst: true}
+
+	states, _ := cmd.Flags().GetStringSlice("state")
+	for _, state := range states {
+		parsed := types.SessionState(strings.ToLower(state))
+		if !parsed.IsValid() {
+			return session.ListOptions{}, fmt.Errorf("unknown --state '%s' (use active, paused, completed, archived or error)", state)
+		}
+		opts.States = append(opts.States, parsed)
+	}
+	opts.Tags, _ = cmd.Flags().GetStringSlice("tag")
+
+	if bound, _ := cmd.Flags().GetBool("bound"); bound {
+		opts.Binding = session.BindingBound
+	}
+	if unbound, _ := cmd.Flags().GetBool("unbound"); unbound {
+		opts.Binding = session.BindingUnbound
+	}
+	opts.Filter, _ = cmd.Flags().GetString("filter")
+	return opts, nil
+}
+
+func runList(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	opts, err := listOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	if global, _ := cmd.Flags().GetBool("global"); global {
+		return runListGlobal(cmd, sessionManager, opts)
+	}
+
+	sessions, err := sessionManager.ListSummaries(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	if len(sessions) == 0 {
+		if len(opts.States) > 0 || len(opts.Tags) > 0 || opts.Binding != session.BindingAny || opts.Filter != "" {
+			fmt.Printf("Kamui: No sessions in %s match the filters\n", sessionManager.GetProjectPath())
+			return nil
+		}
+		fmt.Printf("Kamui: No sessions found in %s\n", sessionManager.GetProjectPath())
+		return nil
+	}
+
+	groups := groupSessionsByPackage(sessions)
+	packages := make([]string, 0, len(groups))
+	for pkg := range groups {
+		packages = append(packages, pkg)
+	}
+	sort.Strings(packages)
+
+	workspace := sessionManager.GetWorkspace()
+	if workspace == nil {
+		fmt.Printf("Kamui: Sessions in %s:\n\n", sessionManager.GetProjectName())
+	} else {
+		fmt.Printf("Kamui: Sessions in %s (%s workspace):\n\n", sessionManager.GetProjectName(), workspace.Kind)
+	}
+
+	painter := themePainter(useColor())
+	for _, pkg := range packages {
+		indent := "  "
+		if workspace != nil || pkg != "" {
+			label := pkg
+			if label == "" {
+				label = "(workspace root)"
+			}
+			fmt.Printf("  %s\n", painter.Paint(theme.Accent, label))
+			indent = "    "
+		}
+
+		for _, s := range groups[pkg] {
+			printSummaryLine(painter, indent, s)
+		}
+	}
+	return nil
+}
+
+// runListGlobal lists every project's sessions from the global index, grouped by project
+func runListGlobal(cmd *cobra.Command, sessionManager *session.Manager, opts session.ListOptions) error {
+	sessions, err := sessionManager.ListIndexed(cmd.Context(), opts)
+	if err != nil {
+		return err
+	}
+
+	if len(sessions) == 0 {
+		if len(opts.States) > 0 || len(opts.Tags) > 0 || opts.Binding != session.BindingAny || opts.Filter != "" {
+			fmt.Println("Kamui: No sessions in any project match the filters")
+			return nil
+		}
+		fmt.Println("Kamui: No sessions found in any project")
+		return nil
+	}
+
+	groups := make(map[string][]session.Summary)
+	for _, s := range sessions {
+		groups[s.ProjectPath] = append(groups[s.ProjectPath], s)
+	}
+	projects := make([]string, 0, len(groups))
+	for projectPath := range groups {
+		projects = append(projects, projectPath)
+	}
+	sort.Strings(projects)
+
+	fmt.Printf("Kamui: Sessions in %d project(s):\n\n", len(projects))
+	painter := themePainter(useColor())
+	for _, projectPath := range projects {
+		fmt.Printf("  %s\n", painter.Paint(theme.Accent, projectPath))
+		for _, s := range groups[projectPath] {
+			printSummaryLine(painter, "    ", s)
+		}
+	}
+	return nil
+}
+
+// printSummaryLine prints a session's line of kam list
+func printSummaryLine(painter *theme.Painter, indent string, s session.Summary) {
+	fmt.Printf("%s%s %s last accessed %s", indent,
+		padded(painter, theme.Emphasis, s.Name, 24),
+		padded(painter, stateRole(s.State), string(s.State), 10),
+		s.LastAccessed.Format("2006-01-02 15:04"))
+	if s.Pinned {
+		fmt.Print("  " + pinIcon)
+	}
+	if s.Lock != nil {
+		fmt.Printf("  [locked: %s]", describeLock(s.Lock))
+	}
+	if s.Protected {
+		fmt.Print("  [protected]")
+	}
+	if s.ReadOnly {
+		fmt.Print("  [read-only]")
+	}
+	if !s.ReadOnly && s.PermissionMode == session.BypassPermissionMode {
+		fmt.Print("  [bypass]")
+	}
+	if len(s.Links) > 0 {
+		fmt.Printf("  %s", links.Summary(s.Links))
+	}
+	fmt.Println()
+}
+
+// stateRole is the theme role a session state is shown in
+func stateRole(state types.SessionState) theme.Role {
+	switch state {
+	case types.SessionStateActive:
+		return theme.Success
+	case types.SessionStatePaused:
+		return theme.Warning
+	case types.SessionStateError:
+		return theme.Error
+	default:
+		return theme.Muted
+	}
+}
+
+// padded pads text to width before styling it, so styled columns still line up
+func padded(painter *theme.Painter, role theme.Role, text string, width int) string {
+	return painter.Paint(role, text) + strings.Repeat(" ", max(width-utf8.RuneCountInString(text), 0))
+}
+
+// groupSessionsByPackage buckets sessions by workspace package, keeping their order
+func groupSessionsByPackage(sessions []session.Summary) map[string][]session.Summary {
+	groups := make(map[string][]session.Summary)
+	for _, s := range sessions {
+		groups[s.Package] = append(groups[s.Package], s)
+	}
+	return groups
+}
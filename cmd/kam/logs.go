@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/theme"
+	"github.com/bitomule/kamui/internal/transcript"
+)
+
+// Logs command searches a session's Claude conversation, or shows Kamui's own log
+var logsCmd = &cobra.Command{
+	Use:   "logs <session-name> --grep <pattern> | logs --self",
+	Short: "Search a session's Claude conversation",
+	Long: `Searches the session's Claude transcript (messages, tool inputs and tool results)
+for a regular expression and prints each matching message with its timestamp and
+the messages around it, like grep -C.
+
+Matching messages show their matching lines (--full prints the whole message);
+context messages show a one-line preview.
+
+--self prints the last --lines lines of Kamui's own log (~/.kamui/logs) instead:
+background monitors, Claude exits and daemon runs. --grep keeps only matching lines.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLogs,
+}
+
+func init() {
+	logsCmd.Flags().StringP("grep", "g", "", "regular expression to search for (required without --self)")
+	logsCmd.Flags().IntP("context", "C", 1, "number of messages to show around each match")
+	logsCmd.Flags().BoolP("ignore-case", "i", false, "match case-insensitively")
+	logsCmd.Flags().Bool("full", false, "print matching messages in full")
+	logsCmd.Flags().Bool("self", false, "show Kamui's own recent log instead of a session's conversation")
+	logsCmd.Flags().IntP("lines", "n", 50, "number of log lines to show with --self")
+	rootCmd.AddCommand(logsCmd)
+}
+
+func runLogs(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	expr, _ := cmd.Flags().GetString("grep")
+	context, _ := cmd.Flags().GetInt("context")
+	ignoreCase, _ := cmd.Flags().GetBool("ignore-case")
+	full, _ := cmd.Flags().GetBool("full")
+	self, _ := cmd.Flags().GetBool("self")
+
+	switch {
+	case self && len(args) > 0:
+		return fmt.Errorf("--self shows Kamui's own log and takes no session")
+	case !self && len(args) == 0:
+		return fmt.Errorf("a session name is required (or --self for Kamui's own log)")
+	case !self && expr == "":
+		return fmt.Errorf("--grep is required when searching a session's conversation")
+	}
+
+	if ignoreCase {
+		expr = "(?i)" + expr
+	}
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return fmt.Errorf("invalid --grep pattern: %w", err)
+	}
+
+	if self {
+		lines, _ := cmd.Flags().GetInt("lines")
+		if expr == "" {
+			pattern = nil
+		}
+		return printSelfLog(pattern, lines)
+	}
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	_, path, err := sessionManager.TranscriptPath(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	turns, err := readTurns(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	groups := transcript.Grep(transcript.Entries(turns), pattern, context)
+	if len(groups) == 0 {
+		return fmt.Errorf("no messages match %q", expr)
+	}
+
+	fmt.Print(formatLogs(groups, pattern, full, themePainter(useColor())))
+	return nil
+}
+
+// printSelfLog prints the last lines of Kamui's own log, only those matching pattern unless nil
+func printSelfLog(pattern *regexp.Regexp, lines int) error {
+	logger := newLogger()
+	// read everything when filtering, so the last matches are found however far back they are
+	tail := lines
+	if pattern != nil {
+		tail = math.MaxInt
+	}
+	logLines, err := logger.Tail(tail)
+	if err != nil {
+		return err
+	}
+
+	if len(logLines) == 0 {
+		fmt.Printf("Kamui: Nothing logged in %s\n", logger.Dir())
+		return nil
+	}
+
+	var matching []string
+	for _, line := range logLines {
+		if pattern == nil || pattern.MatchString(line) {
+			matching = append(matching, line)
+		}
+	}
+	if len(matching) == 0 {
+		return fmt.Errorf("no log lines match %q", pattern)
+	}
+	if len(matching) > lines {
+		matching = matching[len(matching)-lines:]
+	}
+	for _, line := range matching {
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// formatLogs renders search results grep-style, separating non-adjacent groups with "--"
+func formatLogs(groups [][]transcript.Hit, pattern *regexp.Regexp, full bool, painter *theme.Painter) string {
+	var b strings.Builder
+
+	for i, group := range groups {
+		if i > 0 {
+			b.WriteString(painter.Paint(theme.Muted, "--") + "\n")
+		}
+
+		for _, hit := range group {
+			stamp := ""
+			if !hit.Entry.Timestamp.IsZero() {
+				stamp = "[" + hit.Entry.Timestamp.Local().Format("2006-01-02 15:04:05") + "] "
+			}
+
+			if !hit.Match {
+				fmt.Fprintf(&b, "%s\n", painter.Paint(theme.Muted, stamp+hit.Entry.Header()))
+				fmt.Fprintf(&b, "%s\n", painter.Paint(theme.Muted, "  "+preview(hit.Entry.Text)))
+				continue
+			}
+
+			fmt.Fprintf(&b, "%s\n", painter.Paint(theme.Emphasis, stamp+hit.Entry.Header()))
+			lines := transcript.MatchingLines(hit.Entry.Text, pattern)
+			if full || len(lines) == 0 {
+				// a pattern spanning lines matches the message but no single line
+				lines = strings.Split(strings.TrimRight(hit.Entry.Text, "\n"), "\n")
+			}
+			for _, line := range lines {
+				line = pattern.ReplaceAllStringFunc(line, func(match string) string {
+					return painter.Paint(theme.Match, match)
+				})
+				fmt.Fprintf(&b, "> %s\n", line)
+			}
+		}
+	}
+	return b.String()
+}
+
+// preview collapses text onto one line, shortened to fit a terminal row
+func preview(text string) string {
+	line := []rune(strings.Join(strings.Fields(text), " "))
+	if len(line) > 100 {
+		return string(line[:100]) + " …"
+	}
+	return string(line)
+}
@@ -3,10 +3,14 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -15,10 +19,32 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"golang.org/x/term"
 
+	"github.com/bitomule/kamui/internal/checkpoint"
 	"github.com/bitomule/kamui/internal/claude"
+	"github.com/bitomule/kamui/internal/config"
+	"github.com/bitomule/kamui/internal/gist"
+	"github.com/bitomule/kamui/internal/index"
+	"github.com/bitomule/kamui/internal/jira"
+	"github.com/bitomule/kamui/internal/links"
+	"github.com/bitomule/kamui/internal/logging"
+	"github.com/bitomule/kamui/internal/monitor"
+	"github.com/bitomule/kamui/internal/paths"
+	"github.com/bitomule/kamui/internal/pricing"
+	"github.com/bitomule/kamui/internal/process"
+	"github.com/bitomule/kamui/internal/project"
+	"github.com/bitomule/kamui/internal/record"
+	"github.com/bitomule/kamui/internal/retry"
+	"github.com/bitomule/kamui/internal/schedule"
 	"github.com/bitomule/kamui/internal/session"
+	"github.com/bitomule/kamui/internal/snapshot"
 	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/internal/terminal"
+	"github.com/bitomule/kamui/internal/theme"
+	"github.com/bitomule/kamui/internal/timesheet"
+	"github.com/bitomule/kamui/internal/trace"
+	"github.com/bitomule/kamui/internal/transcript"
 	"github.com/bitomule/kamui/pkg/types"
 )
 
@@ -29,9 +55,18 @@ var (
 )
 
 func main() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	registerPluginCommands()
+
+	ctx, stop := signalContext()
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	stop()
+	if err != nil {
+		var status exitStatus
+		if errors.As(err, &status) {
+			os.Exit(int(status))
+		}
+		reportError(cmd, err)
+		os.Exit(types.ExitCodeFor(err))
 	}
 }
 
@@ -45,6 +80,38 @@ Each session maintains its own Claude conversation context and shows in the stat
 	Version: fmt.Sprintf("%s (%s, %s)", version, commit, date),
 	Args:    cobra.MaximumNArgs(1),
 	RunE:    runSession,
+	// Resuming a session launches Claude, so an outdated one is reported before anything else
+	Annotations: launchesClaude,
+	// Errors are printed by reportError, with their recovery hint or as JSON
+	SilenceErrors: true,
+	// Arguments are valid once a command starts running; its failures don't need the usage text
+	PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+		cmd.SilenceUsage = true
+		if viper.GetBool("trace") {
+			trace.Enable(os.Stderr)
+		}
+		if viper.GetBool("session.record") {
+			record.Enable(record.DefaultDir(), viper.GetInt("session.recordingCount"))
+		}
+		if _, ok := cmd.Annotations[claudeAnnotation]; ok {
+			if offline() {
+				return types.NewClaudeError(
+					types.ErrCodeClaudeNotFound,
+					fmt.Sprintf("'%s' launches Claude, which --offline rules out", cmd.CommandPath()),
+					nil,
+				)
+			}
+			if _, err := exec.LookPath("claude"); err != nil {
+				return types.NewClaudeError(
+					types.ErrCodeClaudeNotFound,
+					fmt.Sprintf("'%s' launches Claude, which isn't in PATH (other commands work without it)", cmd.CommandPath()),
+					err,
+				)
+			}
+			return checkClaudeVersion(cmd.Context())
+		}
+		return nil
+	},
 }
 
 func init() {
@@ -54,6 +121,9 @@ func init() {
 	rootCmd.PersistentFlags().StringP("config", "c", "", "config file (default is ~/.kamui/config.json)")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
 	rootCmd.PersistentFlags().Bool("no-color", false, "disable color output")
+	rootCmd.PersistentFlags().BoolP("yes", "y", false, "don't ask for confirmation before destructive operations")
+	rootCmd.PersistentFlags().Bool("trace", false, "print every external command run (claude, git, ...) with its directory, exit code and duration")
+	rootCmd.PersistentFlags().Bool("offline", false, "never run claude: work with session metadata only (launching and running sessions is refused)")
 
 	// Bind flags to viper
 	if err := viper.BindPFlag("config", rootCmd.PersistentFlags().Lookup("config")); err != nil {
@@ -65,6 +135,15 @@ func init() {
 	if err := viper.BindPFlag("no-color", rootCmd.PersistentFlags().Lookup("no-color")); err != nil {
 		panic(fmt.Sprintf("failed to bind no-color flag: %v", err))
 	}
+	if err := viper.BindPFlag("yes", rootCmd.PersistentFlags().Lookup("yes")); err != nil {
+		panic(fmt.Sprintf("failed to bind yes flag: %v", err))
+	}
+	if err := viper.BindPFlag("trace", rootCmd.PersistentFlags().Lookup("trace")); err != nil {
+		panic(fmt.Sprintf("failed to bind trace flag: %v", err))
+	}
+	if err := viper.BindPFlag("offline", rootCmd.PersistentFlags().Lookup("offline")); err != nil {
+		panic(fmt.Sprintf("failed to bind offline flag: %v", err))
+	}
 
 	// Add subcommands
 	rootCmd.AddCommand(setupCmd)
@@ -91,12 +170,19 @@ func initConfig() {
 		viper.SetConfigName("config")
 	}
 
-	// Environment variables
-	viper.SetEnvPrefix("KAMUI")
+	// Environment variables: KAMUI_CLAUDE_DEFAULTMODEL through the replacer, and the documented
+	// KAMUI_CLAUDE_DEFAULT_MODEL through an explicit binding per option
+	viper.SetEnvPrefix(config.EnvPrefix)
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
 	viper.AutomaticEnv()
+	for _, option := range config.Options() {
+		if err := viper.BindEnv(option, config.EnvName(option)); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to bind %s: %v\n", config.EnvName(option), err)
+		}
+	}
 
 	// Set defaults
-	setDefaults()
+	setDefaults(viper.GetViper())
 
 	// Read config file
 	if err := viper.ReadInConfig(); err != nil {
@@ -105,30 +191,267 @@ func initConfig() {
 		}
 		// Continue with defaults if config file not found
 	}
+
+	configureTranscriptLimits()
+}
+
+// setDefaults sets the default of every option that has one
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("default.projectDetection", string(project.DefaultStrategy))
+
+	v.SetDefault("claude.defaultModel", "claude-3-sonnet")
+	v.SetDefault("claude.retryAttempts", 3)
+	v.SetDefault("claude.injectContext", false)
+
+	v.SetDefault("storage.backend", storage.DefaultBackend)
+	v.SetDefault("storage.logRetentionDays", 7)
+	v.SetDefault("storage.logFileSize", "5MB")
+	v.SetDefault("storage.logMaxSize", "50MB")
+	v.SetDefault("storage.durableWrites", false)
+	v.SetDefault("storage.enableGlobalIndex", true)
+	v.SetDefault("storage.indexSyncInterval", "5m")
+
+	v.SetDefault("session.cleanupInactiveDays", 30)
+	v.SetDefault("session.enableStatistics", true)
+	v.SetDefault("session.snapshots", true)
+	v.SetDefault("session.snapshotDirty", true)
+	v.SetDefault("session.snapshotCount", 20)
+	v.SetDefault("session.record", false)
+	v.SetDefault("session.recordingCount", 10)
+	v.SetDefault("session.checkpoint", checkpoint.ModeOff)
+
+	v.SetDefault("ui.colorOutput", true)
+	v.SetDefault("ui.confirmDestructive", true)
+	v.SetDefault("ui.picker", pickerTUI)
+	v.SetDefault("ui.verboseLogging", false)
+	v.SetDefault("ui.notifications", true)
+	v.SetDefault("ui.exitAlert", []string{})
+	v.SetDefault("ui.iterm2.badge", true)
+	v.SetDefault("ui.theme", theme.Default)
+
+	v.SetDefault("github.apiUrl", gist.DefaultAPIURL)
+
+	v.SetDefault("timesheet.enabled", true)
+
+	v.SetDefault("transcript.maxLineSize", "16MB")
+	v.SetDefault("transcript.gcAfter", "30d")
+}
+
+// newSessionManager creates a session manager using the configured project detection strategy
+func newSessionManager() (*session.Manager, error) {
+	return newSessionManagerFor("")
+}
+
+// newSessionManagerFor creates a configured session manager for the project containing dir,
+// or the current directory when dir is empty
+func newSessionManagerFor(dir string) (*session.Manager, error) {
+	detector, err := project.NewDetector(
+		viper.GetString("default.projectDetection"),
+		viper.GetStringSlice("default.projectMarkers"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var sessionManager *session.Manager
+	if dir == "" {
+		sessionManager, err = session.NewWithDetector(detector)
+	} else {
+		sessionManager, err = session.NewForDir(detector, dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sessionManager.SetCaseFolding(viper.GetBool("session.caseFolding"))
+	sessionManager.SetManualLocks(session.NewManualLocks())
+	sessionManager.SetTimesheet(timesheet.NewStore())
+	sessionManager.SetRenamedState(snapshot.NewStore(), record.DefaultDir(), schedule.NewStore())
+	if offline() {
+		sessionManager.SetClaudeClient(claude.NewOffline("kam is running with --offline"))
+	}
+	if err = useConfiguredStorage(sessionManager); err != nil {
+		return nil, err
+	}
+
+	compactThreshold, err := session.ParseCompactThreshold(viper.GetString("storage.compactThreshold"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v, using %d\n", err, session.DefaultCompactThreshold)
+		compactThreshold = session.DefaultCompactThreshold
+	}
+	sessionManager.SetCompactThreshold(compactThreshold)
+	sessionManager.SetDefaultClaudeArgs(viper.GetStringSlice("claude.defaultArgs"))
+	sessionManager.SetInjectContext(viper.GetBool("claude.injectContext"))
+
+	projectConfig, err := project.LoadConfig(sessionManager.GetProjectPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v, ignoring it\n", err)
+		projectConfig = &types.ProjectConfig{}
+	}
+	sessionManager.SetProjectConfig(projectConfig)
+
+	return sessionManager, nil
+}
+
+// configureTranscriptLimits applies the `transcript` limits from config to transcript reads
+// Invalid sizes are reported and leave the built-in limit in place
+func configureTranscriptLimits() {
+	limits := transcript.DefaultLimits
+	if size, err := transcript.ParseSize(viper.GetString("transcript.maxLineSize")); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: transcript.maxLineSize: %v\n", err)
+	} else if size > 0 {
+		limits.MaxLineSize = int(size)
+	}
+	if size, err := transcript.ParseSize(viper.GetString("transcript.maxBytes")); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: transcript.maxBytes: %v\n", err)
+	} else {
+		limits.MaxBytes = size
+	}
+	limits.MaxLines = viper.GetInt("transcript.maxLines")
+	transcript.DefaultLimits = limits
+}
+
+// openStorage opens the configured storage backend for a project
+func openStorage(projectPath string) (storage.Interface, error) {
+	return storage.Open(viper.GetString("storage.backend"), storage.Options{
+		ProjectPath:   projectPath,
+		DurableWrites: viper.GetBool("storage.durableWrites"),
+		URL:           viper.GetString("storage.remote.url"),
+		Token:         serverToken(),
+	})
+}
+
+// serverToken returns the token shared by kam serve and its clients; KAMUI_SERVER_TOKEN
+// overrides storage.remote.token
+func serverToken() string {
+	if token := os.Getenv("KAMUI_SERVER_TOKEN"); token != "" {
+		return token
+	}
+	return viper.GetString("storage.remote.token")
+}
+
+// useConfiguredStorage switches the manager to the configured storage backend
+// Sessions kept on this machine are also kept in the global index, unless it is disabled
+func useConfiguredStorage(sessionManager *session.Manager) error {
+	store, err := openStorage(sessionManager.GetProjectPath())
+	if err != nil {
+		return err
+	}
+	sessionManager.SetStorage(store)
+	if local, ok := store.(*storage.Storage); ok {
+		removeStaleTempFiles(local)
+		if viper.GetBool("storage.enableGlobalIndex") {
+			sessionManager.SetIndex(index.NewStore(), indexSyncInterval())
+		}
+	}
+	return nil
+}
+
+// indexSyncInterval returns storage.indexSyncInterval; invalid values are reported and the
+// default used instead
+func indexSyncInterval() time.Duration {
+	interval, err := types.ParseDuration(viper.GetString("storage.indexSyncInterval"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: storage.indexSyncInterval: %v, using %s\n", err, types.Duration(index.DefaultSyncInterval))
+		return index.DefaultSyncInterval
+	}
+	return interval.Std()
+}
+
+// removeStaleTempFiles removes temporary files interrupted saves left in the sessions directory,
+// so they never confuse later listings; what is removed goes to Kamui's log
+func removeStaleTempFiles(store *storage.Storage) {
+	removed, err := store.RemoveStaleTempFiles(storage.StaleTempAge)
+	logger := newLogger()
+	for _, path := range removed {
+		_ = logger.Printf("removed %s, left behind by an interrupted save", path)
+	}
+	if err != nil {
+		_ = logger.Printf("failed to clean up temporary files in %s: %v", store.GetSessionsPath(), err)
+	}
+}
+
+// pricingTable returns the default price table with any `pricing` overrides from config applied
+func pricingTable() pricing.Table {
+	var overrides pricing.Table
+	if err := viper.UnmarshalKey("pricing", &overrides); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: invalid pricing config, using default prices: %v\n", err)
+		return pricing.DefaultTable
+	}
+	return pricing.DefaultTable.Merge(overrides)
 }
 
-func setDefaults() {
-	viper.SetDefault("claude.defaultModel", "claude-3-sonnet")
-	viper.SetDefault("claude.retryAttempts", 3)
+// newJiraClient creates a Jira client from the jira.* settings; JIRA_API_TOKEN overrides jira.apiToken
+func newJiraClient() (*jira.Client, error) {
+	token := os.Getenv("JIRA_API_TOKEN")
+	if token == "" {
+		token = viper.GetString("jira.apiToken")
+	}
+	return jira.NewClient(viper.GetString("jira.baseUrl"), viper.GetString("jira.email"), token)
+}
 
-	viper.SetDefault("session.cleanupInactiveDays", 30)
-	viper.SetDefault("session.enableStatistics", true)
+// newGistClient creates a gist client from the github.* settings; GITHUB_TOKEN and GH_TOKEN
+// override github.token
+func newGistClient() (*gist.Client, error) {
+	return gist.NewClient(viper.GetString("github.apiUrl"), githubToken())
+}
 
-	viper.SetDefault("ui.colorOutput", true)
-	viper.SetDefault("ui.verboseLogging", false)
+// githubToken returns the configured GitHub token, or "" when there is none
+func githubToken() string {
+	for _, name := range []string{"GITHUB_TOKEN", "GH_TOKEN"} {
+		if token := os.Getenv(name); token != "" {
+			return token
+		}
+	}
+	return viper.GetString("github.token")
+}
+
+// useColor reports whether output should be colored: enabled in config, not disabled
+// with --no-color, and going to a terminal
+func useColor() bool {
+	return colorEnabled() && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// colorEnabled reports whether color is enabled in config and not disabled with --no-color
+func colorEnabled() bool {
+	return viper.GetBool("ui.colorOutput") && !viper.GetBool("no-color")
+}
+
+// themePainter returns a painter for the configured ui.theme, or a plain one when color is false
+// An invalid theme is reported and the default theme used instead
+func themePainter(color bool) *theme.Painter {
+	if !color {
+		return theme.Plain()
+	}
+
+	var custom map[string]types.ThemeConfig
+	err := viper.UnmarshalKey("ui.themes", &custom)
+	if err == nil {
+		var resolved types.ThemeConfig
+		if resolved, err = theme.Resolve(viper.GetString("ui.theme"), custom); err == nil {
+			var painter *theme.Painter
+			if painter, err = theme.New(resolved); err == nil {
+				return painter
+			}
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Warning: invalid ui.theme, using the default theme: %v\n", err)
+	resolved, _ := theme.Resolve(theme.Default, nil)
+	painter, _ := theme.New(resolved)
+	return painter
 }
 
-func runSession(_ *cobra.Command, args []string) error {
+func runSession(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
 	// Check if Claude Code integration needs setup
-	if err := checkAndSetupClaudeIntegration(); err != nil {
+	if err := checkAndSetupClaudeIntegration(ctx); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: Failed to setup Claude integration: %v\n", err)
 		// Continue anyway - Kamui can work without status line
 	}
 
 	// Import session manager
-	sessionManager, err := session.New()
+	sessionManager, err := newSessionManager()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
 	}
 
@@ -136,7 +459,7 @@ func runSession(_ *cobra.Command, args []string) error {
 
 	// If no session name provided, show picker
 	if len(args) == 0 {
-		selectedSession, pickerErr := showSessionPicker(sessionManager)
+		selectedSession, pickerErr := showSessionPicker(ctx, sessionManager)
 		if pickerErr != nil {
 			return pickerErr
 		}
@@ -150,29 +473,143 @@ func runSession(_ *cobra.Command, args []string) error {
 		sessionName = args[0]
 	}
 
+	// Recoverable failures (a stale lock, a conversation that won't resume) offer a way out
+	// on a terminal instead of just failing
+	for {
+		err := launchSession(ctx, sessionManager, sessionName)
+		if err == nil {
+			return nil
+		}
+
+		candidates := resumeCandidates(ctx, sessionManager, sessionName, err)
+		reason := types.NewErrorReport(err).Message
+		switch chooseRecovery(ctx, err, len(candidates)) {
+		case recoveryRetry:
+			continue
+		case recoveryRemoveLock:
+			if unlockErr := sessionManager.UnlockSession(ctx, sessionName); unlockErr != nil {
+				return unlockErr
+			}
+			fmt.Printf("Kamui: Removed the lock on '%s'\n", sessionName)
+		case recoveryBindNewest:
+			newest := newestCandidate(candidates)
+			if fallbackErr := sessionManager.ApplyResumeFallback(ctx, sessionName, types.ResumeChoiceNewest, newest.ClaudeSessionID, reason); fallbackErr != nil {
+				return fallbackErr
+			}
+			fmt.Printf("Kamui: Continuing conversation %s in '%s'\n", newest.ClaudeSessionID, sessionName)
+		case recoveryPickConversation:
+			picked, ok := pickConversation(ctx, candidates)
+			if !ok {
+				return err
+			}
+			if fallbackErr := sessionManager.ApplyResumeFallback(ctx, sessionName, types.ResumeChoicePicked, picked.ClaudeSessionID, reason); fallbackErr != nil {
+				return fallbackErr
+			}
+			fmt.Printf("Kamui: Continuing conversation %s in '%s'\n", picked.ClaudeSessionID, sessionName)
+		case recoveryStartFresh:
+			if fallbackErr := sessionManager.ApplyResumeFallback(ctx, sessionName, types.ResumeChoiceFresh, "", reason); fallbackErr != nil {
+				return fallbackErr
+			}
+			fmt.Printf("Kamui: Starting a fresh Claude conversation for '%s'\n", sessionName)
+		default:
+			return err
+		}
+	}
+}
+
+// launchSession locks the session, then creates or resumes it and runs Claude until it exits
+func launchSession(ctx context.Context, sessionManager *session.Manager, sessionName string) error {
+	resolved, err := sessionManager.LockSession(ctx, sessionName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// Release the lock even when kam was interrupted
+		if unlockErr := sessionManager.UnlockSession(context.WithoutCancel(ctx), resolved); unlockErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", unlockErr)
+		}
+	}()
+
+	// Set clean terminal title "Claude - SessionName" plus tab title and user variables
+	// New sessions launch Claude inside the manager, so announce the session up front
+	workingDir := sessionManager.GetWorkingDirectory()
+	if existing, getErr := sessionManager.GetSession(ctx, sessionName); getErr == nil {
+		workingDir = existing.Project.WorkingDirectory
+		announceSession(sessionName, string(existing.Lifecycle.State), existing.Project.WorkingDirectory, existing.Metadata.Variant)
+		if existing.Metadata.ReadOnly {
+			fmt.Fprintf(os.Stderr, "Kamui: Session '%s' is read-only; Claude starts in %s mode and won't edit files ('kam readonly %s --off' to change)\n",
+				existing.SessionID, session.ReadOnlyPermissionMode, existing.SessionID)
+		} else if existing.Claude.PermissionMode == session.BypassPermissionMode {
+			fmt.Fprintf(os.Stderr, "Kamui: Session '%s' bypasses Claude's permission prompts ('kam args %s --permission-mode none' to change)\n",
+				existing.SessionID, existing.SessionID)
+		}
+	} else {
+		warnQuota(ctx, sessionManager)
+		announceSession(sessionName, string(types.SessionStateActive), workingDir, "")
+	}
+	takeSnapshot(ctx, resolved, workingDir)
+
 	// Create or resume session
-	sessionData, claudeWasExecuted, err := sessionManager.CreateOrResumeSession(sessionName)
+	started := time.Now()
+	sessionData, claudeWasExecuted, err := sessionManager.CreateOrResumeSession(ctx, sessionName)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
 	}
+	// A new session has already run Claude; a Ctrl-C meant for it shouldn't skip the registry
+	registerProject(context.WithoutCancel(ctx), sessionManager)
 
 	// If Claude was already executed during session creation, we're done
 	if claudeWasExecuted {
+		sessionData = awaitBinding(ctx, sessionManager, sessionData)
+		recordTime(sessionData, started, time.Now())
+		checkpointRun(ctx, sessionManager, sessionData.SessionID, started)
+		alertExit(sessionData.SessionID, 0)
 		return nil
 	}
 
-	// Execute Claude session directly (for resume)
-	if err := executeClaudeSession(sessionManager, sessionData); err != nil {
-		fmt.Fprintf(os.Stderr, "Error starting Claude: %v\n", err)
+	if proceed, healthErr := checkTranscriptHealth(ctx, sessionManager, sessionData); healthErr != nil || !proceed {
+		return healthErr
+	}
+
+	warnBudgets(ctx, sessionData)
+
+	if err := sessionManager.PrepareLaunch(sessionData); err != nil {
 		return err
 	}
 
-	return nil
+	// Execute Claude session directly (for resume)
+	return executeClaudeSession(ctx, sessionManager, sessionData)
 }
 
-// runMonitor implements the background monitoring process
-func runMonitor(sessionName, workingDir string) error {
+// runMonitor implements the background monitoring process: it binds the session to the
+// conversation Claude starts, exiting once bound or when the kam that spawned it exits
+func runMonitor(ctx context.Context, sessionName, workingDir string) error {
+	// The parent holds stdin open while it runs; end of input means it has exited
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		_, _ = io.Copy(io.Discard, os.Stdin)
+		cancel()
+	}()
+
+	// One monitor per session: step aside if another is still running for it
+	registry := monitor.NewRegistry()
+	hostname, _ := os.Hostname()
+	running, err := registry.Claim(monitor.Record{
+		Session:    sessionName,
+		PID:        os.Getpid(),
+		Parent:     os.Getppid(),
+		Host:       hostname,
+		WorkingDir: workingDir,
+		Started:    time.Now(),
+		// lets a later kam tell this monitor apart from a process that reuses its pid
+		ProcessStart: process.StartTime(os.Getpid()),
+	})
+	if err != nil || running != nil {
+		return err
+	}
+	defer func() { _ = registry.Release(sessionName, os.Getpid()) }() // a stale record is reaped later
+
 	// Create Claude client for monitoring
 	claudeClient, err := claude.New()
 	if err != nil {
@@ -180,66 +617,55 @@ func runMonitor(sessionName, workingDir string) error {
 	}
 
 	// Get baseline sessions before monitoring
-	beforeSessions, err := claudeClient.DiscoverExistingSessions(workingDir)
+	beforeSessions, err := claudeClient.DiscoverExistingSessions(ctx, workingDir)
 	if err != nil {
 		return fmt.Errorf("failed to discover existing sessions: %w", err)
 	}
 
-	// Monitor for new session creation (60 second timeout)
-	timeout := 60 * time.Second
-	start := time.Now()
-
-	for time.Since(start) < timeout {
-		// Check for new sessions
-		afterSessions, err := claudeClient.DiscoverExistingSessions(workingDir)
-		if err != nil {
-			time.Sleep(1 * time.Second)
-			continue // Keep trying
-		}
-
-		// Find any new session
-		for _, sessionID := range afterSessions {
-			found := false
-			for _, oldSession := range beforeSessions {
-				if sessionID == oldSession {
-					found = true
-					break
-				}
-			}
-			if !found {
-				// Found new session - save mapping and exit
-				if err := saveSessionMapping(sessionName, sessionID, workingDir); err != nil {
-					return fmt.Errorf("failed to save session mapping: %w", err)
-				}
-
-				// Session mapping saved silently
-				return nil // Exit monitor process
-			}
+	// Wait for Claude to write the new conversation's transcript
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	newSessionID, err := claude.WatchNewSession(ctx, paths.ClaudeProjectDir(homeDir, workingDir), beforeSessions)
+	if err != nil {
+		if ctx.Err() != nil {
+			_ = newLogger().Printf("monitor for session '%s' stopped: kam exited before Claude wrote a conversation", sessionName)
+			return nil
 		}
+		return fmt.Errorf("failed waiting for Claude session creation: %w", err)
+	}
 
-		// Wait before checking again
-		time.Sleep(1 * time.Second)
+	// Found new session - save mapping, retrying while the session file is busy
+	bindingPolicy := retry.Default
+	bindingPolicy.MaxAttempts = viper.GetInt("claude.retryAttempts")
+	if err := retry.Do(ctx, bindingPolicy, func(int) error {
+		return saveSessionMapping(ctx, sessionName, newSessionID, workingDir)
+	}); err != nil {
+		return fmt.Errorf("failed to save session mapping: %w", err)
 	}
 
-	// Timeout reached
-	return fmt.Errorf("timeout waiting for Claude session creation")
+	// The monitor runs detached, so its outcome goes to Kamui's log rather than a terminal
+	_ = newLogger().Printf("monitor bound session '%s' to conversation %s", sessionName, newSessionID)
+	return nil
 }
 
 // saveSessionMapping saves the session mapping to global storage
-func saveSessionMapping(sessionName, claudeSessionID, workingDir string) error {
+func saveSessionMapping(ctx context.Context, sessionName, claudeSessionID, workingDir string) error {
 	// Create storage instance
-	storage := storage.New(workingDir)
+	storage, err := openStorage(workingDir)
+	if err != nil {
+		return err
+	}
 
 	// Create or load session
 	var session *types.Session
-	if storage.SessionExists(sessionName) {
-		var err error
-		session, err = storage.LoadSession(sessionName)
+	if storage.SessionExists(ctx, sessionName) {
+		session, err = storage.LoadSession(ctx, sessionName)
 		if err != nil {
 			return err
 		}
 	} else {
-		var err error
 		session, err = storage.CreateSession(sessionName, workingDir)
 		if err != nil {
 			return err
@@ -249,11 +675,81 @@ func saveSessionMapping(sessionName, claudeSessionID, workingDir string) error {
 	// Update Claude session info
 	session.Claude.SessionID = claudeSessionID
 	session.Claude.HasActiveContext = true
+	if version := installedClaudeVersion(ctx); !version.IsZero() {
+		session.Claude.CLIVersion = version.String()
+	}
 	session.Claude.LastInteraction = time.Now()
 	session.LastModified = time.Now()
 
 	// Save updated session
-	return storage.SaveSession(session)
+	return storage.SaveSession(ctx, session)
+}
+
+// claudeAnnotation marks the commands that launch Claude, whose installed version is checked
+// against claude.minVersion and the enabled features before they start
+const claudeAnnotation = "kamui/launches-claude"
+
+// launchesClaude annotates a command with claudeAnnotation
+var launchesClaude = map[string]string{claudeAnnotation: "true"}
+
+// checkClaudeVersion fails with ErrCodeDependencyVersion when the installed Claude Code is older
+// than claude.minVersion or than the features enabled in config need; the detected version is
+// cached, so this costs a subprocess only after Claude changes
+func checkClaudeVersion(ctx context.Context) error {
+	minimum, err := configuredMinClaudeVersion()
+	if err != nil {
+		return err
+	}
+	features := enabledClaudeFeatures()
+	if minimum.IsZero() && len(features) == 0 {
+		return nil
+	}
+	return claude.CheckMinimum(installedClaudeVersion(ctx), minimum, features)
+}
+
+// configuredMinClaudeVersion parses claude.minVersion, zero when unset
+func configuredMinClaudeVersion() (claude.Version, error) {
+	setting := strings.TrimSpace(viper.GetString("claude.minVersion"))
+	if setting == "" {
+		return claude.Version{}, nil
+	}
+	minimum, err := claude.ParseVersion(setting)
+	if err != nil {
+		return claude.Version{}, types.NewConfigError(
+			types.ErrCodeConfigInvalid,
+			fmt.Sprintf("claude.minVersion '%s' is not a version such as 1.0.94", setting),
+			err,
+		)
+	}
+	return minimum, nil
+}
+
+// enabledClaudeFeatures returns the version-gated features the config turns on for every launch
+func enabledClaudeFeatures() []claude.Feature {
+	features := claude.FeaturesForArgs(viper.GetStringSlice("claude.defaultArgs"))
+	if viper.GetBool("claude.injectContext") {
+		features = append(features, claude.FeatureSystemPrompt)
+	}
+	return features
+}
+
+// offline reports whether --offline (or KAMUI_OFFLINE) rules out running claude
+func offline() bool {
+	return viper.GetBool("offline")
+}
+
+// installedClaudeVersion returns the installed Claude Code version, or zero when it can't be
+// determined or kam is offline
+func installedClaudeVersion(ctx context.Context) claude.Version {
+	if offline() {
+		return claude.Version{}
+	}
+	claudePath, err := exec.LookPath("claude")
+	if err != nil {
+		return claude.Version{}
+	}
+	version, _ := claude.DetectVersion(ctx, claudePath, claude.DefaultVersionCachePath())
+	return version
 }
 
 // Setup command
@@ -261,8 +757,8 @@ var setupCmd = &cobra.Command{
 	Use:   "setup",
 	Short: "Setup Claude Code integration",
 	Long:  "Configures Claude Code to display Kamui session status automatically",
-	RunE: func(_ *cobra.Command, _ []string) error {
-		return setupClaudeIntegration()
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return setupClaudeIntegration(cmd.Context())
 	},
 }
 
@@ -272,72 +768,78 @@ var monitorCmd = &cobra.Command{
 	Short:  "Background session monitor (internal use)",
 	Hidden: true, // Hide from help output
 	Args:   cobra.ExactArgs(2),
-	RunE: func(_ *cobra.Command, args []string) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		sessionName := args[0]
 		workingDir := args[1]
-		return runMonitor(sessionName, workingDir)
+		err := runMonitor(cmd.Context(), sessionName, workingDir)
+		if err != nil && cmd.Context().Err() == nil {
+			_ = newLogger().Printf("monitor for session '%s' failed: %v", sessionName, err)
+		}
+		return err
 	},
 }
 
-// showSessionPicker displays an interactive menu of available sessions
-func showSessionPicker(sessionManager *session.Manager) (string, error) {
-	// Get list of available sessions
-	sessions, err := sessionManager.ListSessions()
+// pickerListOptions lists the sessions every picker offers, pinned ones first
+var pickerListOptions = session.ListOptions{Sort: session.SortName, PinnedFirst: true, AllProjects: true}
+
+// showSessionPicker lets the user choose one of the sessions in the picker ui.picker asks for
+func showSessionPicker(ctx context.Context, sessionManager *session.Manager) (string, error) {
+	summaries, err := sessionManager.ListSummaries(ctx, pickerListOptions)
 	if err != nil {
 		return "", fmt.Errorf("failed to list sessions: %w", err)
 	}
 
 	// Handle no sessions case
-	if len(sessions) == 0 {
+	if len(summaries) == 0 {
 		fmt.Printf("Kamui: No sessions found in %s\n", sessionManager.GetProjectPath())
 		fmt.Println("Kamui: Create a new session with 'kam <session-name>'")
 		return "", nil
 	}
 
-	// Display session picker
-	fmt.Printf("Kamui: Available sessions in %s:\n\n", sessionManager.GetProjectName())
-
-	// Load and display session info
-	sessionInfos := make([]sessionInfo, 0, len(sessions))
-	for i, sessionName := range sessions {
-		info := sessionInfo{
-			Index: i + 1,
-			Name:  sessionName,
-		}
-
-		// Load session data for metadata
-		if sessionData, err := sessionManager.GetSession(sessionName); err == nil {
-			info.Created = sessionData.Created
-			info.LastAccessed = sessionData.LastAccessed
-			info.ProjectPath = sessionData.Project.Path
-			info.ClaudeSessionID = sessionData.Claude.SessionID
-			info.IsActive = sessionData.Claude.HasActiveContext
+	picker := configuredPicker()
+	if picker == pickerFzf {
+		if fzfPath, ok := fzfPicker(); ok {
+			return pickWithFzf(ctx, fzfPath, sessionManager, summaries)
 		}
+		picker = pickerTUI
+	}
+	// the menu also serves when kam's input or output isn't a terminal
+	if picker == pickerTUI && term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd())) {
+		return pickWithTUI(ctx, sessionManager, summaries)
+	}
 
-		sessionInfos = append(sessionInfos, info)
-
-		// Display session entry
-		fmt.Printf("  %d. %s\n", info.Index, info.Name)
-		fmt.Printf("     Created: %s\n", info.Created.Format("2006-01-02 15:04:05"))
-		fmt.Printf("     Last accessed: %s\n", info.LastAccessed.Format("2006-01-02 15:04:05"))
-		if info.ClaudeSessionID != "" {
-			status := "active"
-			if !info.IsActive {
-				status = "inactive"
-			}
-			fmt.Printf("     Claude session: %s (%s)\n", info.ClaudeSessionID[:8]+"...", status)
-		} else {
-			fmt.Printf("     Claude session: none\n")
-		}
-		fmt.Println()
+	// Load session info for display
+	sessions := make([]string, 0, len(summaries))
+	sessionInfos := make([]sessionInfo, 0, len(summaries))
+	for i, summary := range summaries {
+		sessions = append(sessions, summary.Name)
+		sessionInfos = append(sessionInfos, sessionInfo{
+			Index:            i + 1,
+			Name:             summary.Name,
+			Created:          summary.Created,
+			LastAccessed:     summary.LastAccessed,
+			ProjectPath:      summary.ProjectPath,
+			WorkingDirectory: summary.WorkingDirectory,
+			ClaudeSessionID:  summary.ClaudeSessionID,
+			IsActive:         summary.HasActiveContext,
+			Links:            links.Summary(summary.Links),
+			Pinned:           summary.Pinned,
+			Lock:             summary.Lock,
+		})
 	}
 
+	// Display session picker
+	printSessionMenu(os.Stdout, themePainter(useColor()), sessionManager.GetProjectName(), sessionManager.GetProjectPath(), sessionInfos, terminal.Width(os.Stdout))
+
 	// Get user selection
 	reader := bufio.NewReader(os.Stdin)
 	for {
 		fmt.Printf("Select a session (1-%d) or 'q' to quit: ", len(sessions))
-		input, err := reader.ReadString('\n')
+		input, err := readLine(ctx, reader)
 		if err != nil {
+			if ctx.Err() != nil {
+				return "", err
+			}
 			return "", fmt.Errorf("failed to read input: %w", err)
 		}
 
@@ -363,17 +865,24 @@ func showSessionPicker(sessionManager *session.Manager) (string, error) {
 
 // sessionInfo holds metadata about a session for display
 type sessionInfo struct {
-	Index           int
-	Name            string
-	Created         time.Time
-	LastAccessed    time.Time
-	ProjectPath     string
-	ClaudeSessionID string
-	IsActive        bool
+	Index            int
+	Name             string
+	Created          time.Time
+	LastAccessed     time.Time
+	ProjectPath      string
+	WorkingDirectory string
+	ClaudeSessionID  string
+	IsActive         bool
+	Links            string
+	Pinned           bool
+	Lock             *types.ManualLock
 }
 
-// executeClaudeSession launches Claude with the session's resume command
-func executeClaudeSession(_ *session.Manager, sessionData *types.Session) error {
+// resumeFailureWindow is how soon after launch a failing resumed Claude counts as a failed resume
+const resumeFailureWindow = 5 * time.Second
+
+// executeClaudeSession launches Claude with the session's resume command and extra arguments
+func executeClaudeSession(ctx context.Context, sessionManager *session.Manager, sessionData *types.Session) error {
 	// Parse the command - it's either "claude" or "claude --resume <session-id>"
 	var args []string
 	if sessionData.Claude.SessionID != "" {
@@ -381,11 +890,16 @@ func executeClaudeSession(_ *session.Manager, sessionData *types.Session) error
 	} else {
 		args = []string{"claude"}
 	}
+	args = append(args, sessionManager.LaunchArgs(sessionData)...)
 
 	// Find claude executable
 	claudePath, err := exec.LookPath("claude")
 	if err != nil {
-		return fmt.Errorf("claude not found in PATH: %w", err)
+		return types.NewClaudeError(types.ErrCodeClaudeNotFound, "claude not found in PATH", err)
+	}
+	version, _ := claude.DetectVersion(ctx, claudePath, claude.DefaultVersionCachePath())
+	if err = claude.CheckArgs(version, args[1:]); err != nil {
+		return err
 	}
 
 	// Set working directory to project directory
@@ -394,52 +908,199 @@ func executeClaudeSession(_ *session.Manager, sessionData *types.Session) error
 		return fmt.Errorf("failed to change to project directory: %w", err)
 	}
 
-	// Set up Kamui environment variables
-	env := os.Environ()
-
-	// Short Claude session ID for display
-	claudeSessionShort := sessionData.Claude.SessionID
-	if len(claudeSessionShort) > 8 {
-		claudeSessionShort = claudeSessionShort[:8] + "..."
-	}
-
-	// Set clean terminal title: "Claude - SessionName"
-	terminalTitle := fmt.Sprintf("Claude - %s", sessionData.SessionID)
-	fmt.Printf("\033]0;%s\007", terminalTitle)
-
 	// Create status display
-	statusLine := fmt.Sprintf("Kamui: %s | %s | %s",
-		sessionData.SessionID,
-		claudeSessionShort,
-		sessionData.Project.Name)
+	statusLine := session.StatusLine(sessionData)
 
 	// Show enhanced status display
-	fmt.Printf("\n\033[96m╭─ Kamui Session ────────────────────────────────╮\033[0m\n")
-	fmt.Printf("\033[96m│\033[0m \033[1m%-45s\033[0m \033[96m│\033[0m\n", statusLine)
-	fmt.Printf("\033[96m╰────────────────────────────────────────────────╯\033[0m\n\n")
+	painter := themePainter(useColor())
+	fmt.Printf("\n%s\n", painter.Paint(theme.Accent, "╭─ Kamui Session ────────────────────────────────╮"))
+	fmt.Printf("%s %s %s\n", painter.Paint(theme.Accent, "│"), painter.Paint(theme.Emphasis, fmt.Sprintf("%-45s", statusLine)), painter.Paint(theme.Accent, "│"))
+	fmt.Printf("%s\n\n", painter.Paint(theme.Accent, "╰────────────────────────────────────────────────╯"))
 
 	// Set all environment variables for Claude Code statusLine integration
-	env = append(env, fmt.Sprintf("KAMUI_SESSION_ID=%s", sessionData.SessionID))
-	env = append(env, fmt.Sprintf("KAMUI_CLAUDE_SESSION_ID=%s", sessionData.Claude.SessionID))
-	env = append(env, fmt.Sprintf("KAMUI_PROJECT_NAME=%s", sessionData.Project.Name))
-	env = append(env, fmt.Sprintf("KAMUI_PROJECT_PATH=%s", sessionData.Project.Path))
-	env = append(env, fmt.Sprintf("KAMUI_STATUS_LINE=%s", statusLine))
-	env = append(env, "KAMUI_ACTIVE=1")
-	env = append(env, fmt.Sprintf("KAMUI_SESSION_SHORT=%s", claudeSessionShort))
+	// The status line is drawn by Claude, so it follows the color settings rather than kam's output
+	env := append(os.Environ(), session.Environment(sessionData)...)
+	env = append(env, themePainter(colorEnabled()).Environment()...)
 
 	fmt.Printf("Kamui: Launching Claude in %s...\n", sessionData.Project.WorkingDirectory)
 
-	err = syscall.Exec(claudePath, args, env)
+	// Run Claude as a child rather than exec'ing it so the time spent can be recorded
+	// Ctrl-C reaches Claude through the terminal; Kamui only waits for it to exit
+	cmd := exec.Command(claudePath, args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = env
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	if err := types.ContextError(ctx); err != nil {
+		return err
+	}
+	recording := record.Attach(cmd, sessionData.SessionID)
+	started := time.Now()
+	if err := trace.Start(cmd); err != nil {
+		_ = recording.Close()
+		return fmt.Errorf("failed to start claude: %w", err)
+	}
+	recording.Begin()
+	go func() {
+		for sig := range signals {
+			if sig != os.Interrupt {
+				_ = cmd.Process.Signal(sig) // Claude may already be gone
+			}
+		}
+	}()
+
+	err = trace.Wait(cmd)
+	if closeErr := recording.Close(); closeErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", closeErr)
+	} else if recording != nil {
+		fmt.Printf("Kamui: Recorded this run to %s\n", recording.Path)
+	}
+	recordTime(sessionData, started, time.Now())
+	checkpointRun(ctx, sessionManager, sessionData.SessionID, started)
+	recordExit(ctx, sessionManager, sessionData.SessionID, cmd.ProcessState)
+	if cmd.ProcessState != nil {
+		alertExit(sessionData.SessionID, cmd.ProcessState.ExitCode())
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return err
+	}
+	if sessionData.Claude.SessionID != "" && time.Since(started) < resumeFailureWindow {
+		return types.NewClaudeError(
+			types.ErrCodeClaudeResumeFailed,
+			fmt.Sprintf("Claude exited right after resuming conversation %s", sessionData.Claude.SessionID),
+			err,
+		)
+	}
+	// Claude already reported the problem; exit with its status as exec would have
+	return exitStatus(exitErr.ExitCode())
+}
+
+// recordExit records how Claude exited in the session's statistics, which moves the session to the
+// error state when Claude failed; failures only warn
+func recordExit(ctx context.Context, sessionManager *session.Manager, sessionName string, state *os.ProcessState) {
+	if state == nil {
+		return
+	}
+	reason := ""
+	if state.ExitCode() == -1 {
+		reason = fmt.Sprintf("Claude crashed (%s)", state)
+	}
+	_ = newLogger().Printf("session '%s': Claude exited (%s)", sessionName, state)
+	if err := sessionManager.RecordExit(context.WithoutCancel(ctx), sessionName, state.ExitCode(), reason); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record how Claude exited: %v\n", err)
+		return
+	}
+	if !state.Success() {
+		fmt.Fprintf(os.Stderr, "Kamui: Session '%s' is marked as failed (state 'error') until Claude next exits cleanly\n", sessionName)
+	}
+}
+
+// takeSnapshot records where the session's working tree stands before Claude runs, so
+// 'kam rollback' can return to it; failures only warn
+func takeSnapshot(ctx context.Context, sessionName, workingDir string) {
+	if !viper.GetBool("session.snapshots") {
+		return
+	}
+	store := snapshot.NewStore()
+	if _, err := store.Take(ctx, sessionName, workingDir, snapshot.ReasonLaunch, viper.GetBool("session.snapshotDirty"), time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to snapshot the working tree: %v\n", err)
+		return
+	}
+	if err := store.Prune(sessionName, viper.GetInt("session.snapshotCount")); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to prune snapshots: %v\n", err)
+	}
+}
+
+// checkpointRun commits what changed during a run of the session when session.checkpoint is on;
+// failures only warn
+func checkpointRun(ctx context.Context, sessionManager *session.Manager, sessionName string, started time.Time) {
+	mode, err := checkpoint.ParseMode(viper.GetString("session.checkpoint"))
 	if err != nil {
-		return fmt.Errorf("failed to exec claude: %w", err)
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return
+	}
+	if mode == checkpoint.ModeOff {
+		return
 	}
 
-	// This line should never be reached if exec succeeds
-	return nil
+	// Claude has exited, so finish the bookkeeping even if kam was interrupted
+	ctx = context.WithoutCancel(ctx)
+	sessionData, path, err := sessionManager.TranscriptPath(ctx, sessionName)
+	if err != nil {
+		if sessionData, err = sessionManager.GetSession(ctx, sessionName); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to create checkpoint: %v\n", err)
+			return
+		}
+	}
+	var summary transcript.Summary
+	if path != "" {
+		summary, _ = transcript.SummarizeFile(ctx, path, started) // the message is still useful without it
+	}
+
+	commit, err := checkpoint.Create(ctx, sessionData, mode, summary)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to create checkpoint: %v\n", err)
+		return
+	}
+	if commit == "" {
+		return
+	}
+	_ = logging.New().Audit(logging.AuditEntry{Action: "checkpoint", Session: sessionData.SessionID, Detail: commit})
+	if mode == checkpoint.ModeBranch {
+		fmt.Printf("Kamui: Checkpoint %.12s on %s\n", commit, checkpoint.BranchName(sessionData.SessionID))
+	} else {
+		fmt.Printf("Kamui: Checkpoint %.12s\n", commit)
+	}
+}
+
+// recordTime adds an interactive run of the session to the timesheet
+func recordTime(sessionData *types.Session, start, end time.Time) {
+	if sessionData == nil || !viper.GetBool("timesheet.enabled") {
+		return
+	}
+
+	entry := timesheet.Entry{
+		SessionID:   sessionData.SessionID,
+		Project:     sessionData.Project.Name,
+		ProjectPath: sessionData.Project.Path,
+		Start:       start,
+		End:         end,
+	}
+	if err := timesheet.NewStore().Record(entry); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record session time: %v\n", err)
+	}
+}
+
+// announceSession publishes the session to the terminal (title, tab title, user variables)
+// On iTerm2 it also sets the badge and the profile configured for the session variant
+func announceSession(sessionName, state, workingDir, variant string) {
+	info := terminal.SessionInfo{
+		Name:       sessionName,
+		State:      state,
+		WorkingDir: workingDir,
+	}
+
+	if viper.GetBool("ui.iterm2.badge") {
+		info.Badge = sessionName
+	}
+	if variant == "" {
+		variant = "default"
+	}
+	// Viper lowercases map keys, so variants are matched case-insensitively
+	info.Profile = viper.GetStringMapString("ui.iterm2.profiles")[strings.ToLower(variant)]
+
+	terminal.New(os.Stdout).AnnounceSession(info)
 }
 
 // setupClaudeIntegration configures Claude Code to use Kamui status line
-func setupClaudeIntegration() error {
+func setupClaudeIntegration(ctx context.Context) error {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return fmt.Errorf("failed to get home directory: %w", err)
@@ -451,6 +1112,13 @@ func setupClaudeIntegration() error {
 
 	fmt.Println("Kamui: Setting up Claude Code integration...")
 
+	if version := installedClaudeVersion(ctx); !version.IsZero() {
+		fmt.Printf("Kamui: Found Claude Code %s\n", version)
+		if err := claude.Check(version, claude.FeatureStatusLine); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v; the session won't be shown in Claude Code until it is updated\n", err)
+		}
+	}
+
 	// Create .claude directory if it doesn't exist
 	if err := os.MkdirAll(claudeDir, 0o755); err != nil {
 		return fmt.Errorf("failed to create .claude directory: %w", err)
@@ -475,7 +1143,17 @@ func setupClaudeIntegration() error {
 
 // installStatusLineScript creates the Kamui status line script
 func installStatusLineScript(scriptPath string) error {
-	statusLineContent := `#!/usr/bin/env node
+	if err := os.WriteFile(scriptPath, []byte(statusLineScriptContent), 0o600); err != nil {
+		return err
+	}
+
+	fmt.Printf("   Created status line script: %s\n", scriptPath)
+	return nil
+}
+
+// statusLineScriptContent is the status line script Claude Code runs; it takes its colors from
+// the KAMUI_COLOR_* variables kam sets for the session
+const statusLineScriptContent = `#!/usr/bin/env node
 
 function getKamuiStatus() {
     const kamuiSessionId = process.env.KAMUI_SESSION_ID;
@@ -492,12 +1170,59 @@ function getKamuiStatus() {
     
     const status = [
         '🎯',
-        ` + "`" + `\x1b[96m${kamuiSessionId}\x1b[0m` + "`" + `,
-        '\x1b[90m•\x1b[0m',
-        ` + "`" + `\x1b[32m${kamuiProjectName || projectDir}\x1b[0m` + "`" + `
-    ].join(' ');
+        paint('ACCENT', '96', kamuiSessionId),
+        paint('MUTED', '90', '•'),
+        paint('SUCCESS', '32', kamuiProjectName || projectDir)
+    ];
     
-    return status;
+    const budgetWarning = getBudgetWarning(kamuiSessionId);
+    if (budgetWarning) {
+        status.push(paint('MUTED', '90', '•'), budgetWarning);
+    }
+    
+    return status.join(' ');
+}
+
+// paint styles text with kam's theme color for a role, passed as KAMUI_COLOR_<ROLE>
+function paint(role, fallback, text) {
+    const code = process.env['KAMUI_COLOR_' + role] ?? fallback;
+    return code ? ` + "`" + `\x1b[${code}m${text}\x1b[0m` + "`" + ` : text;
+}
+
+// budgetCacheTTL is how long a budget check is reused; the status line is redrawn far more often
+// than usage changes, and each check runs kam
+const budgetCacheTTL = 30 * 1000;
+
+function getBudgetWarning(sessionId) {
+    const fs = require('fs');
+    const path = require('path');
+    const cacheFile = path.join(require('os').homedir(), '.kamui', 'run', 'statusline', 'budget-' + sessionId);
+    try {
+        if (Date.now() - fs.statSync(cacheFile).mtimeMs < budgetCacheTTL) {
+            return fs.readFileSync(cacheFile, 'utf8');
+        }
+    } catch (e) {
+        // no cached check yet
+    }
+
+    let warning = '';
+    try {
+        const { execFileSync } = require('child_process');
+        warning = execFileSync('kam', ['budget', 'status', sessionId, '--statusline'], {
+            encoding: 'utf8',
+            timeout: 2000,
+            stdio: ['ignore', 'pipe', 'ignore']
+        }).trim();
+    } catch (e) {
+        warning = '';
+    }
+    try {
+        fs.mkdirSync(path.dirname(cacheFile), { recursive: true });
+        fs.writeFileSync(cacheFile, warning);
+    } catch (e) {
+        // the next redraw checks again
+    }
+    return warning;
 }
 
 function main() {
@@ -542,14 +1267,6 @@ function main() {
 
 main();`
 
-	if err := os.WriteFile(scriptPath, []byte(statusLineContent), 0o600); err != nil {
-		return err
-	}
-
-	fmt.Printf("   Created status line script: %s\n", scriptPath)
-	return nil
-}
-
 // configureClaudeSettings updates Claude Code settings to use Kamui status line
 func configureClaudeSettings(settingsFile, scriptPath string) error {
 	var settings map[string]interface{}
@@ -584,7 +1301,7 @@ func configureClaudeSettings(settingsFile, scriptPath string) error {
 }
 
 // checkAndSetupClaudeIntegration checks if Kamui is already configured and sets it up if not
-func checkAndSetupClaudeIntegration() error {
+func checkAndSetupClaudeIntegration(ctx context.Context) error {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return err
@@ -593,11 +1310,17 @@ func checkAndSetupClaudeIntegration() error {
 	statusLineScript := filepath.Join(homeDir, ".claude", "kamui-statusline.js")
 
 	// Check if Kamui status line script already exists
-	if _, err := os.Stat(statusLineScript); err == nil {
+	if installed, err := os.ReadFile(statusLineScript); err == nil {
+		// Scripts written by older versions are brought up to date
+		if string(installed) != statusLineScriptContent {
+			if err := os.WriteFile(statusLineScript, []byte(statusLineScriptContent), 0o600); err != nil {
+				_ = newLogger().Printf("failed to update %s: %v", statusLineScript, err)
+			}
+		}
 		return nil // Already set up
 	}
 
 	// First time setup
 	fmt.Println("Kamui: First run detected - setting up Claude Code integration...")
-	return setupClaudeIntegration()
+	return setupClaudeIntegration(ctx)
 }
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/logging"
+	"github.com/bitomule/kamui/internal/session"
+)
+
+// Move command relocates a session to another project directory
+var moveCmd = &cobra.Command{
+	Use:   "move <session-name> <new-project-path>",
+	Short: "Move a session to another project directory",
+	Long: `Points a session at another project directory, e.g. after the project was moved
+or cloned elsewhere. A working directory inside the old project, such as a
+workspace package, keeps its place under the new one when it exists there.
+
+Claude keeps each conversation under a directory named after where it ran, so the
+session's transcript is copied there for the conversation to resume in the new
+project, with the working directory its records were written in rewritten to
+match. --transcript move removes the original afterwards; --transcript none
+leaves it alone, and the next resume offers another conversation instead.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMove,
+}
+
+func init() {
+	moveCmd.Flags().String("transcript", string(session.TranscriptCopy), "what to do with the Claude transcript: copy, move or none")
+	rootCmd.AddCommand(moveCmd)
+}
+
+func runMove(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	transcriptFlag, _ := cmd.Flags().GetString("transcript")
+	action, err := session.ParseTranscriptAction(transcriptFlag)
+	if err != nil {
+		return err
+	}
+	projectPath, err := filepath.Abs(args[1])
+	if err != nil {
+		return err
+	}
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	// a running session would write its old project back when it next saves
+	resolved, err := sessionManager.LockSession(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if unlockErr := sessionManager.UnlockSession(context.WithoutCancel(ctx), resolved); unlockErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", unlockErr)
+		}
+	}()
+
+	result, err := sessionManager.MoveSession(ctx, resolved, projectPath, action)
+	if result == nil {
+		return err
+	}
+	_ = logging.New().Audit(logging.AuditEntry{Action: "move", Session: resolved, Detail: result.FromDir + " -> " + result.ToDir})
+
+	fmt.Printf("Kamui: Moved session '%s' from %s to %s\n", resolved, result.FromDir, result.ToDir)
+	switch {
+	case result.Transcript != "":
+		verb := "Copied"
+		if action == session.TranscriptMove {
+			verb = "Moved"
+		}
+		fmt.Printf("Kamui: %s the transcript to %s (%d record(s) rewritten)\n", verb, result.Transcript, result.Rewritten)
+	case result.TranscriptMissing:
+		fmt.Fprintf(os.Stderr, "Warning: the session's Claude transcript wasn't found, so there was nothing to relocate\n")
+	}
+	return err
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/logging"
+	"github.com/bitomule/kamui/internal/session"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// New command creates a session, optionally naming it after its description
+var newCmd = &cobra.Command{
+	Use:   "new [session-name]",
+	Short: "Create a new session",
+	Long: `Creates a new session and opens Claude in it.
+
+With --from-description the session name is generated from the description
+("Investigate flaky CI on main" becomes investigate-flaky-ci) and the full
+text is kept as the session description.
+
+--permission-mode pins the Claude permission mode (plan, acceptEdits, default or
+bypass) the session launches in every time; 'kam args --permission-mode' changes it.
+
+--variant picks the session variant (default: project.defaultSessionVariant from
+.kamui/config.json). Sessions of a variant with instructions under session.prompts in
+that file launch with its systemPrompt appended to Claude's system prompt and its
+claudeMd kept in a Kamui block of CLAUDE.md.`,
+	Args:        cobra.MaximumNArgs(1),
+	RunE:        runNew,
+	Annotations: launchesClaude,
+}
+
+func init() {
+	newCmd.Flags().String("from-description", "", "generate the session name from this description")
+	newCmd.Flags().StringP("description", "d", "", "session description")
+	newCmd.Flags().StringSlice("tag", nil, "tag the session (repeatable)")
+	newCmd.Flags().Bool("no-launch", false, "create the session without opening Claude")
+	newCmd.Flags().String("variant", "", "session variant, selecting the instructions Claude gets from the project config")
+	newCmd.Flags().String("permission-mode", "", "Claude permission mode the session always launches in: plan, acceptEdits, default or bypass")
+	rootCmd.AddCommand(newCmd)
+}
+
+func runNew(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	fromDescription, _ := cmd.Flags().GetString("from-description")
+	description, _ := cmd.Flags().GetString("description")
+	tags, _ := cmd.Flags().GetStringSlice("tag")
+	noLaunch, _ := cmd.Flags().GetBool("no-launch")
+	permissionMode, _ := cmd.Flags().GetString("permission-mode")
+	variant, _ := cmd.Flags().GetString("variant")
+
+	if (len(args) == 0) == (fromDescription == "") {
+		return fmt.Errorf("specify either a session name or --from-description")
+	}
+	permissionMode, err := session.ParsePermissionMode(permissionMode)
+	if err != nil {
+		return err
+	}
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+	if variant != "" {
+		if err := sessionManager.ValidateVariant(variant); err != nil {
+			return err
+		}
+	}
+
+	warnQuota(ctx, sessionManager)
+
+	var sessionData *types.Session
+	if fromDescription != "" {
+		sessionData, err = sessionManager.CreateSessionFromDescription(ctx, fromDescription, tags)
+	} else {
+		sessionData, err = sessionManager.CreateSession(ctx, args[0], description, tags)
+	}
+	if err != nil {
+		return err
+	}
+	if variant != "" {
+		if err := sessionManager.SetVariant(ctx, sessionData.SessionID, variant); err != nil {
+			return err
+		}
+	}
+	if permissionMode != "" {
+		if err := sessionManager.SetPermissionMode(ctx, sessionData.SessionID, permissionMode); err != nil {
+			return err
+		}
+	}
+
+	_ = logging.New().Audit(logging.AuditEntry{Action: "create", Session: sessionData.SessionID, Detail: sessionData.Metadata.Description})
+	registerProject(ctx, sessionManager)
+
+	fmt.Printf("Kamui: Created session '%s'\n", sessionData.SessionID)
+	if noLaunch {
+		fmt.Printf("Kamui: Start it with 'kam %s'\n", sessionData.SessionID)
+		return nil
+	}
+
+	return runSession(cmd, []string{sessionData.SessionID})
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+
+	"github.com/bitomule/kamui/internal/index"
+	"github.com/bitomule/kamui/internal/monitor"
+	"github.com/bitomule/kamui/internal/storage"
+)
+
+// nukeWord is what has to be typed to confirm kam nuke
+const nukeWord = "nuke"
+
+// Nuke command removes every piece of Kamui data on this machine
+var nukeCmd = &cobra.Command{
+	Use:   "nuke",
+	Short: "Remove all Kamui data from this machine",
+	Long: `Deletes everything Kamui keeps on this machine, to start over or offboard it:
+session files with their backups (~/.claude/kamui-sessions), the global session
+index (~/.claude/kamui-index.json), and ~/.kamui with logs, snapshots, recordings, timesheets, locks, budgets, schedules,
+runtime state and the config file. Claude's own conversations in ~/.claude/projects
+are left alone, as are project .kamui/config.json files and sessions kept on a
+kam serve instance.
+
+With --uninstall, the Claude Code integration set up by 'kam setup' is removed too:
+the status line script and its entry in ~/.claude/settings.json.
+
+Sessions open in kam must be closed first. Orphaned monitors are stopped.
+
+There is no undo: kam asks you to type "nuke" first, even when ui.confirmDestructive
+is off; pass --yes to skip that in scripts. --dry-run lists what would be removed.`,
+	Args: cobra.NoArgs,
+	RunE: runNuke,
+}
+
+func init() {
+	nukeCmd.Flags().Bool("dry-run", false, "list what would be removed without removing anything")
+	nukeCmd.Flags().Bool("uninstall", false, "also remove the Claude Code status line integration")
+	nukeCmd.Flags().Bool("keep-config", false, "keep ~/.kamui/config.json")
+	rootCmd.AddCommand(nukeCmd)
+}
+
+func runNuke(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	uninstall, _ := cmd.Flags().GetBool("uninstall")
+	keepConfig, _ := cmd.Flags().GetBool("keep-config")
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	store, err := openStorage(cwd)
+	if err != nil {
+		return err
+	}
+	// json-files is the only backend with local sessions; the others are left to their owner
+	sessionsDir := filepath.Join(homeDir, ".claude", "kamui-sessions")
+	if local, ok := store.(*storage.Storage); ok {
+		sessionsDir = local.GetSessionsPath()
+		if open := openSessions(ctx, local); len(open) > 0 {
+			return fmt.Errorf("%d session(s) are open in kam (%s); close them first", len(open), strings.Join(open, ", "))
+		}
+	} else {
+		fmt.Printf("Kamui: Sessions kept by the %s backend are not removed\n", viper.GetString("storage.backend"))
+	}
+
+	kamuiDir := filepath.Join(homeDir, ".kamui")
+	removed := append(existing(sessionsDir), existing(index.DefaultPath())...)
+	if keepConfig {
+		entries, _ := os.ReadDir(kamuiDir)
+		for _, entry := range entries {
+			if !strings.HasPrefix(entry.Name(), "config.") {
+				removed = append(removed, filepath.Join(kamuiDir, entry.Name()))
+			}
+		}
+	} else {
+		removed = append(removed, existing(kamuiDir)...)
+	}
+
+	var rewritten []string
+	settingsFile := filepath.Join(homeDir, ".claude", "settings.json")
+	statusLineScript := filepath.Join(homeDir, ".claude", "kamui-statusline.js")
+	if uninstall {
+		removed = append(removed, existing(statusLineScript)...)
+		if usesStatusLine(settingsFile, statusLineScript) {
+			rewritten = append(rewritten, settingsFile+" (dropping the statusLine entry)")
+		}
+	}
+
+	if len(removed) == 0 && len(rewritten) == 0 {
+		fmt.Println("Kamui: No Kamui data to remove")
+		return nil
+	}
+	if dryRun {
+		printDryRun(removed, rewritten)
+		return nil
+	}
+
+	fmt.Println("Kamui: This permanently deletes:")
+	for _, path := range removed {
+		fmt.Printf("  %s\n", path)
+	}
+	for _, path := range rewritten {
+		fmt.Printf("  and rewrites %s\n", path)
+	}
+	proceed, err := confirmTyped(ctx, nukeWord)
+	if err != nil || !proceed {
+		return err
+	}
+
+	// orphaned monitors would write their records and logs back
+	registry := monitor.NewRegistry()
+	if records, listErr := registry.List(); listErr == nil {
+		for _, record := range records {
+			_ = registry.Reap(record)
+		}
+	}
+
+	failed := 0
+	for _, path := range removed {
+		if err := os.RemoveAll(path); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to remove %s: %v\n", path, err)
+			failed++
+		}
+	}
+	if uninstall && len(rewritten) > 0 {
+		if err := removeStatusLineSetting(settingsFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to update %s: %v\n", settingsFile, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d item(s) could not be removed", failed)
+	}
+
+	fmt.Println("Kamui: All Kamui data removed")
+	if !uninstall && len(existing(statusLineScript)) > 0 {
+		fmt.Println("Kamui: The Claude Code status line is still installed; 'kam nuke --uninstall' removes it")
+	}
+	return nil
+}
+
+// openSessions returns the sessions a live kam process holds
+func openSessions(ctx context.Context, store *storage.Storage) []string {
+	names, err := store.ListSessions(ctx)
+	if err != nil {
+		return nil
+	}
+	var open []string
+	for _, name := range names {
+		if lock, lockErr := store.ReadLock(ctx, name); lockErr == nil && lock != nil && !storage.LockIsStale(lock) {
+			open = append(open, name)
+		}
+	}
+	return open
+}
+
+// existing returns path in a slice when it exists, so it can be listed for removal
+func existing(path string) []string {
+	if _, err := os.Lstat(path); err != nil {
+		return nil
+	}
+	return []string{path}
+}
+
+// usesStatusLine reports whether Claude's settings run Kamui's status line script, wherever
+// the home directory was when it was set up
+func usesStatusLine(settingsFile, scriptPath string) bool {
+	data, err := os.ReadFile(settingsFile)
+	if err != nil {
+		return false
+	}
+	var settings struct {
+		StatusLine struct {
+			Command string `json:"command"`
+		} `json:"statusLine"`
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return false
+	}
+	return filepath.Base(settings.StatusLine.Command) == filepath.Base(scriptPath)
+}
+
+// removeStatusLineSetting drops the statusLine entry configureClaudeSettings added, keeping
+// every other setting
+func removeStatusLineSetting(settingsFile string) error {
+	data, err := os.ReadFile(settingsFile)
+	if err != nil {
+		return err
+	}
+	var settings map[string]interface{}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return fmt.Errorf("failed to parse settings: %w", err)
+	}
+	delete(settings, "statusLine")
+
+	data, err = json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal settings: %w", err)
+	}
+	return os.WriteFile(settingsFile, data, 0o600)
+}
+
+// confirmTyped asks for word to be typed before an operation there's no coming back from
+// Unlike confirmDestructive, ui.confirmDestructive doesn't turn it off; only --yes does
+func confirmTyped(ctx context.Context, word string) (bool, error) {
+	if viper.GetBool("yes") {
+		return true, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return false, fmt.Errorf("no terminal to confirm on; pass --yes to run non-interactively")
+	}
+
+	fmt.Fprintf(os.Stderr, "Type %q to continue: ", word)
+	answer, err := readLine(ctx, bufio.NewReader(os.Stdin))
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, err
+		}
+		return false, fmt.Errorf("failed to read input: %w", err)
+	}
+	if strings.TrimSpace(answer) != word {
+		fmt.Fprintln(os.Stderr, "Kamui: Aborted")
+		return false, nil
+	}
+	return true, nil
+}
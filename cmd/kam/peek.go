@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/terminal"
+	"github.com/bitomule/kamui/internal/theme"
+	"github.com/bitomule/kamui/internal/transcript"
+)
+
+// Peek command prints a session's details and latest messages without touching the session
+var peekCmd = &cobra.Command{
+	Use:   "peek <session-name>",
+	Short: "Show a session's details and latest messages without resuming it",
+	Long: `Prints the session's details and the last messages of its Claude conversation,
+then exits. Nothing is launched or written: the session's last accessed time stays
+as it was, so browsing old sessions doesn't reorder 'most recent' listings, and
+peeking at a session that is running elsewhere is safe.
+
+Long messages are cut to --lines lines (0 prints them whole). Use 'kam view' to
+browse the whole conversation.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPeek,
+}
+
+func init() {
+	peekCmd.Flags().IntP("messages", "n", 6, "number of messages to show")
+	peekCmd.Flags().Int("lines", 10, "lines shown per message (0 for all)")
+	rootCmd.AddCommand(peekCmd)
+}
+
+func runPeek(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	messages, _ := cmd.Flags().GetInt("messages")
+	lines, _ := cmd.Flags().GetInt("lines")
+	if messages < 0 || lines < 0 {
+		return fmt.Errorf("--messages and --lines can't be negative")
+	}
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	sessionData, err := sessionManager.GetSession(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	painter := themePainter(useColor())
+	fmt.Printf("Session:       %s\n", painter.Paint(theme.Emphasis, sessionData.SessionID))
+	if sessionData.Metadata.Description != "" {
+		fmt.Printf("Description:   %s\n", sessionData.Metadata.Description)
+	}
+	fmt.Printf("Working dir:   %s\n", sessionData.Project.WorkingDirectory)
+	state := string(sessionData.Lifecycle.State)
+	if running, lockErr := sessionManager.RunningLock(ctx, sessionData.SessionID); lockErr == nil && running != nil {
+		state += fmt.Sprintf(", running in kam (pid %d on %s since %s)",
+			running.PID, running.Host, running.Acquired.Local().Format("2006-01-02 15:04"))
+	}
+	fmt.Printf("State:         %s\n", painter.Paint(stateRole(sessionData.Lifecycle.State), state))
+	if lock, lockErr := sessionManager.ManualLock(sessionData.SessionID); lockErr == nil && lock != nil {
+		fmt.Printf("Locked:        %s\n", describeLock(lock))
+	}
+	fmt.Printf("Last accessed: %s\n", sessionData.LastAccessed.Local().Format("2006-01-02 15:04"))
+
+	if sessionData.Claude.SessionID == "" {
+		fmt.Println()
+		fmt.Println(painter.Paint(theme.Muted, "No Claude conversation yet"))
+		return nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	path := transcript.Path(sessionData, homeDir)
+	if _, statErr := os.Stat(path); os.IsNotExist(statErr) {
+		fmt.Println()
+		fmt.Println(painter.Paint(theme.Muted, fmt.Sprintf("Claude conversation %s has no transcript at %s", sessionData.Claude.SessionID, path)))
+		return nil
+	}
+
+	turns, err := readTurns(ctx, path)
+	if err != nil {
+		return err
+	}
+	printPeek(os.Stdout, painter, turns, messages, lines, terminal.Width(os.Stdout))
+	return nil
+}
+
+// printPeek prints the last messages of a conversation, each cut to maxLines lines unless 0
+// width is the terminal width lines are cut to, 0 when the output isn't a terminal
+func printPeek(w io.Writer, painter *theme.Painter, turns []*transcript.Turn, messages, maxLines, width int) {
+	shown := turns[max(len(turns)-messages, 0):]
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, painter.Paint(theme.Accent, fmt.Sprintf("── Last %d of %d messages ──", len(shown), len(turns))))
+
+	fit := func(line string) string {
+		if width == 0 {
+			return line
+		}
+		return terminal.TruncateEnd(line, width)
+	}
+	for _, turn := range shown {
+		fmt.Fprintln(w)
+		header := painter.Paint(theme.Emphasis, transcript.RoleLabel(turn.Role))
+		if !turn.Timestamp.IsZero() {
+			header += " " + painter.Paint(theme.Muted, turn.Timestamp.Local().Format("2006-01-02 15:04"))
+		}
+		fmt.Fprintln(w, header)
+
+		if text := strings.TrimSpace(turn.Text); text != "" {
+			textLines := strings.Split(text, "\n")
+			hidden := 0
+			if maxLines > 0 && len(textLines) > maxLines {
+				hidden = len(textLines) - maxLines
+				textLines = textLines[:maxLines]
+			}
+			for _, line := range textLines {
+				fmt.Fprintln(w, fit("  "+line))
+			}
+			if hidden > 0 {
+				fmt.Fprintln(w, painter.Paint(theme.Muted, fmt.Sprintf("  … %d more line(s)", hidden)))
+			}
+		}
+		for _, tool := range turn.Tools {
+			role, outcome := theme.Muted, ""
+			if tool.IsError {
+				role, outcome = theme.Error, " (failed)"
+			}
+			fmt.Fprintln(w, painter.Paint(role, fit("  → "+tool.Name+outcome)))
+		}
+	}
+}
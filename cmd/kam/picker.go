@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bitomule/kamui/internal/terminal"
+	"github.com/bitomule/kamui/internal/theme"
+)
+
+// detailedMenuWidth is the narrowest terminal the picker shows a block per session on;
+// narrower ones get one line per session
+const detailedMenuWidth = 100
+
+// printSessionMenu lists the sessions to pick from, laid out for a terminal width columns wide
+// (0 when the output isn't a terminal)
+func printSessionMenu(w io.Writer, painter *theme.Painter, projectName, projectPath string, infos []sessionInfo, width int) {
+	if width > 0 && width < detailedMenuWidth {
+		fmt.Fprintf(w, "Kamui: Sessions in %s:\n\n", terminal.TruncateMiddle(projectName, width-len("Kamui: Sessions in :")))
+		printCompactMenu(w, painter, projectPath, infos, width)
+		fmt.Fprintln(w)
+		return
+	}
+
+	fmt.Fprintf(w, "Kamui: Available sessions in %s:\n\n", projectName)
+	for _, info := range infos {
+		printDetailedEntry(w, painter, projectPath, info, width)
+		fmt.Fprintln(w)
+	}
+}
+
+// printDetailedEntry prints a session as a block of lines
+func printDetailedEntry(w io.Writer, painter *theme.Painter, projectPath string, info sessionInfo, width int) {
+	// fit shortens a value so its line doesn't wrap
+	fit := func(prefix, value string, truncate func(string, int) string) string {
+		if width == 0 {
+			return prefix + value
+		}
+		return prefix + truncate(value, width-utf8.RuneCountInString(prefix))
+	}
+
+	name := painter.Paint(theme.Emphasis, info.Name)
+	if info.Pinned {
+		name += " " + pinIcon
+	}
+	fmt.Fprintf(w, "  %s %s\n", painter.Paint(theme.Accent, fmt.Sprintf("%d.", info.Index)), name)
+	fmt.Fprintf(w, "     Created: %s\n", info.Created.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(w, "     Last accessed: %s\n", info.LastAccessed.Format("2006-01-02 15:04:05"))
+	if info.WorkingDirectory != "" && info.WorkingDirectory != projectPath {
+		fmt.Fprintln(w, fit("     Directory: ", info.WorkingDirectory, terminal.TruncateMiddle))
+	}
+	if info.ClaudeSessionID != "" {
+		claudeID := info.ClaudeSessionID
+		if len(claudeID) > 8 {
+			claudeID = claudeID[:8] + "..."
+		}
+		status := claudeStatus(info)
+		if info.IsActive {
+			status = painter.Paint(theme.Success, status)
+		}
+		fmt.Fprintf(w, "     Claude session: %s (%s)\n", claudeID, status)
+	} else {
+		fmt.Fprintf(w, "     Claude session: none\n")
+	}
+	if info.Links != "" {
+		fmt.Fprintln(w, fit("     Links: ", info.Links, terminal.TruncateEnd))
+	}
+	if info.Lock != nil {
+		fmt.Fprintln(w, painter.Paint(theme.Warning, fit("     Locked: ", describeLock(info.Lock), terminal.TruncateEnd)))
+	}
+}
+
+// printCompactMenu prints a table with one line per session, cut to the terminal width
+func printCompactMenu(w io.Writer, painter *theme.Painter, projectPath string, infos []sessionInfo, width int) {
+	indexWidth := len(fmt.Sprint(len(infos)))
+	nameWidth := len("SESSION")
+	for _, info := range infos {
+		nameWidth = max(nameWidth, utf8.RuneCountInString(info.Name))
+	}
+	nameWidth = min(nameWidth, max(width/3, len("SESSION")))
+
+	row := func(index, name, accessed, claude string) string {
+		return fmt.Sprintf("  %*s  %-*s  %-16s  %-8s", indexWidth+1, index, nameWidth,
+			terminal.TruncateMiddle(name, nameWidth), accessed, claude)
+	}
+
+	fmt.Fprintln(w, painter.Paint(theme.Muted, terminal.TruncateEnd(strings.TrimRight(row("#", "SESSION", "LAST ACCESSED", "CLAUDE"), " "), width)))
+	// the index and name columns end here, and are styled once the row is cut to the width
+	indexEnd := 2 + indexWidth + 1
+	nameEnd := indexEnd + 2 + nameWidth
+	for _, info := range infos {
+		line := row(fmt.Sprintf("%d.", info.Index), info.Name, info.LastAccessed.Format("2006-01-02 15:04"), claudeStatus(info))
+
+		// The directory goes first, shortened in the middle; links fill what's left
+		room := width - utf8.RuneCountInString(line) - 2
+		if info.Pinned {
+			// the icon is two columns wide
+			line += "  " + pinIcon
+			room -= 4
+		}
+		if info.Lock != nil {
+			line += "  [locked]"
+			room -= len("  [locked]")
+		}
+		if info.WorkingDirectory != "" && info.WorkingDirectory != projectPath {
+			dir := info.WorkingDirectory
+			if rel, err := filepath.Rel(projectPath, dir); err == nil && !strings.HasPrefix(rel, "..") {
+				dir = rel
+			}
+			if info.Links != "" {
+				dir = terminal.TruncateMiddle(dir, room/2)
+			}
+			if dir = terminal.TruncateMiddle(dir, room); dir != "" {
+				line += "  " + dir
+				room -= utf8.RuneCountInString(dir) + 2
+			}
+		}
+		if info.Links != "" && room > 0 {
+			line += "  " + info.Links
+		}
+		line = terminal.TruncateEnd(strings.TrimRight(line, " "), width)
+		fmt.Fprintln(w, paintColumns(line, []int{indexEnd, nameEnd}, []func(string) string{
+			func(text string) string { return painter.Paint(theme.Accent, text) },
+			func(text string) string { return painter.Paint(theme.Emphasis, text) },
+		}))
+	}
+}
+
+// paintColumns styles the columns of a row that end at the given rune offsets, each with its own
+// paint function and without the padding around it; what follows the last one is left as is
+func paintColumns(line string, ends []int, paints []func(string) string) string {
+	runes := []rune(line)
+	var b strings.Builder
+	start := 0
+	for i, end := range ends {
+		end = min(end, len(runes))
+		column := string(runes[start:end])
+		text := strings.TrimSpace(column)
+		before, after, _ := strings.Cut(column, text)
+		if text == "" {
+			before, after = column, ""
+		}
+		b.WriteString(before + paints[i](text) + after)
+		start = end
+	}
+	b.WriteString(string(runes[start:]))
+	return b.String()
+}
+
+// claudeStatus describes a session's Claude conversation in a word
+func claudeStatus(info sessionInfo) string {
+	switch {
+	case info.ClaudeSessionID == "":
+		return "none"
+	case info.IsActive:
+		return "active"
+	default:
+		return "inactive"
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/bitomule/kamui/internal/logging"
+	"github.com/bitomule/kamui/internal/picker"
+	"github.com/bitomule/kamui/internal/session"
+)
+
+// pickWithTUI lets the user choose one of the sessions in the full-screen picker, where sessions
+// can also be renamed, archived and deleted
+// It returns "" when the user quits without choosing
+func pickWithTUI(ctx context.Context, sessionManager *session.Manager, sessions []session.Summary) (string, error) {
+	model := picker.New("Kamui · "+sessionManager.GetProjectName(), sessions)
+	source := &pickerSource{ctx: ctx, sessionManager: sessionManager}
+	return picker.Run(model, source, os.Stdin, os.Stdout)
+}
+
+// pickerSource lists the picker's sessions and carries out its actions
+type pickerSource struct {
+	ctx            context.Context
+	sessionManager *session.Manager
+}
+
+func (s *pickerSource) Sessions() ([]session.Summary, error) {
+	return s.sessionManager.ListSummaries(s.ctx, pickerListOptions)
+}
+
+func (s *pickerSource) Apply(action picker.Action) (string, error) {
+	switch action.Kind {
+	case picker.ActionRename:
+		renamed, err := s.sessionManager.RenameSession(s.ctx, action.Session, action.NewName)
+		if err != nil {
+			return "", err
+		}
+		_ = logging.New().Audit(logging.AuditEntry{Action: "rename", Session: renamed.SessionID, Detail: "from " + action.Session})
+		return fmt.Sprintf("Renamed session '%s' to '%s'", action.Session, renamed.SessionID), nil
+	case picker.ActionArchive:
+		if err := s.sessionManager.ArchiveSession(s.ctx, action.Session); err != nil {
+			return "", err
+		}
+		_ = logging.New().Audit(logging.AuditEntry{Action: "archive", Session: action.Session})
+		return fmt.Sprintf("Archived session '%s'", action.Session), nil
+	case picker.ActionDelete:
+		if err := s.sessionManager.DeleteSession(s.ctx, action.Session, false); err != nil {
+			return "", err
+		}
+		_ = logging.New().Audit(logging.AuditEntry{Action: "delete", Session: action.Session})
+		return fmt.Sprintf("Deleted session '%s'", action.Session), nil
+	}
+	return "", nil
+}
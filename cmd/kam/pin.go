@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// pinIcon marks pinned sessions in the picker, kam list and kam info
+const pinIcon = "📌"
+
+// Pin command keeps the sessions used every day at the top of the picker and kam list
+var pinCmd = &cobra.Command{
+	Use:   "pin <session-name>",
+	Short: "Pin a session to the top of the picker and kam list",
+	Long: `Pins a session: pinned sessions sort above the others in the picker and in
+kam list, are marked with ` + pinIcon + `, and are never suggested for archiving when the
+project reaches its session quota.
+
+--off unpins it.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPin,
+}
+
+func init() {
+	pinCmd.Flags().Bool("off", false, "unpin the session")
+	rootCmd.AddCommand(pinCmd)
+}
+
+func runPin(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	off, _ := cmd.Flags().GetBool("off")
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	if err := sessionManager.SetPinned(ctx, args[0], !off); err != nil {
+		return err
+	}
+
+	if off {
+		fmt.Printf("Kamui: Session '%s' is no longer pinned\n", args[0])
+		return nil
+	}
+	fmt.Printf("Kamui: Pinned session '%s'\n", args[0])
+	return nil
+}
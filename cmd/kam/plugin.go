@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/plugin"
+	"github.com/bitomule/kamui/internal/storage"
+)
+
+// pluginAnnotation marks cobra commands that wrap an external plugin
+const pluginAnnotation = "kamui-plugin"
+
+// Plugin command group for inspecting external plugins
+var pluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Inspect external plugins",
+	Long: `Plugins are executables named kam-<name> found on PATH. They are available as
+'kam <name> [args...]' and receive a JSON request describing the Kamui context on stdin.`,
+}
+
+var pluginListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List plugins discovered on PATH",
+	Args:  cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		plugins := plugin.Discover(os.Getenv("PATH"))
+		if len(plugins) == 0 {
+			fmt.Println("Kamui: No plugins found (executables named kam-<name> on PATH)")
+			return nil
+		}
+
+		for _, p := range plugins {
+			status := ""
+			if isBuiltinCommand(p.Name) {
+				status = " (shadowed by built-in command)"
+			}
+			fmt.Printf("  %-20s %s%s\n", p.Name, p.Path, status)
+		}
+		return nil
+	},
+}
+
+func init() {
+	pluginCmd.AddCommand(pluginListCmd)
+	rootCmd.AddCommand(pluginCmd)
+}
+
+// registerPluginCommands adds a subcommand for every plugin that doesn't clash with a built-in
+func registerPluginCommands() {
+	for _, p := range plugin.Discover(os.Getenv("PATH")) {
+		if isBuiltinCommand(p.Name) {
+			continue
+		}
+		rootCmd.AddCommand(newPluginCommand(p))
+	}
+}
+
+// newPluginCommand wraps a plugin executable as a cobra command
+func newPluginCommand(p plugin.Plugin) *cobra.Command {
+	return &cobra.Command{
+		Use:                p.Name,
+		Short:              fmt.Sprintf("Plugin provided by %s", p.Path),
+		Annotations:        map[string]string{pluginAnnotation: p.Path},
+		DisableFlagParsing: true,
+		RunE: func(_ *cobra.Command, args []string) error {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			err = plugin.Run(p, plugin.Request{
+				KamuiVersion:   version,
+				Args:           args,
+				WorkingDir:     cwd,
+				SessionsDir:    storage.New(cwd).GetSessionsPath(),
+				CurrentSession: os.Getenv("KAMUI_SESSION_ID"),
+			}, os.Stdout, os.Stderr)
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				return exitStatus(exitErr.ExitCode())
+			}
+			return err
+		},
+	}
+}
+
+// isBuiltinCommand reports whether name is already taken by a built-in command
+func isBuiltinCommand(name string) bool {
+	for _, cmd := range rootCmd.Commands() {
+		if _, isPlugin := cmd.Annotations[pluginAnnotation]; isPlugin {
+			continue
+		}
+		if cmd.Name() == name || cmd.HasAlias(name) {
+			return true
+		}
+	}
+	return name == "help" || name == "completion"
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/claude"
+	"github.com/bitomule/kamui/internal/git"
+	"github.com/bitomule/kamui/internal/prdraft"
+	"github.com/bitomule/kamui/internal/trace"
+	"github.com/bitomule/kamui/internal/transcript"
+)
+
+// PR-draft command writes a pull request description from a session's work
+var prDraftCmd = &cobra.Command{
+	Use:   "pr-draft <session-name>",
+	Short: "Draft a pull request title and description from a session",
+	Long: `Synthesizes a pull request title and Markdown description from the session's
+description, its Claude transcript (first prompt, files edited, test commands),
+commits made during the session, notes and links.
+
+--refine has Claude rewrite the draft in a separate headless run; the session's
+own conversation is not touched. --create opens a draft pull request with the
+GitHub CLI (gh) instead of printing the Markdown.
+
+Linked GitHub issues are listed as "Refs <url>"; --closes lists them as
+"Closes <url>" instead, so merging the pull request closes them.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPRDraft,
+}
+
+func init() {
+	prDraftCmd.Flags().Bool("refine", false, "have Claude rewrite the draft (headless run)")
+	prDraftCmd.Flags().Bool("create", false, "create a draft pull request with gh")
+	prDraftCmd.Flags().Bool("closes", false, "close the linked GitHub issues when the pull request is merged")
+	prDraftCmd.Flags().StringP("output", "o", "", "write the Markdown to this file instead of stdout")
+	rootCmd.AddCommand(prDraftCmd)
+}
+
+func runPRDraft(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	refine, _ := cmd.Flags().GetBool("refine")
+	create, _ := cmd.Flags().GetBool("create")
+	output, _ := cmd.Flags().GetString("output")
+	closes, _ := cmd.Flags().GetBool("closes")
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	sessionData, err := sessionManager.GetSession(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	input := prdraft.Input{Session: sessionData, CloseIssues: closes}
+	if _, path, err := sessionManager.TranscriptPath(ctx, sessionData.SessionID); err == nil {
+		if summary, err := transcript.SummarizeFile(ctx, path, time.Time{}); err == nil {
+			input.Summary = summary
+			input.FirstPrompt = summary.FirstPrompt
+		}
+	}
+	if !input.Summary.Start.IsZero() {
+		commits, err := git.CommitsBetween(sessionData.Project.WorkingDirectory, input.Summary.Start, input.Summary.End)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		input.Commits = commits
+	}
+
+	draft := prdraft.Build(input)
+
+	if refine {
+		if offline() {
+			return fmt.Errorf("--refine runs Claude, which --offline rules out")
+		}
+		fmt.Fprintln(os.Stderr, "Kamui: Refining the draft with Claude...")
+		claudeClient, err := claude.New()
+		if err != nil {
+			return err
+		}
+		result, err := claudeClient.RunHeadless(ctx, sessionData.Project.WorkingDirectory, "", prdraft.RefinePrompt(draft))
+		if err != nil {
+			return err
+		}
+		if result.IsError {
+			return fmt.Errorf("claude failed to refine the draft: %s", result.Result)
+		}
+		if draft, err = prdraft.ParseRefined(result.Result); err != nil {
+			return err
+		}
+	}
+
+	if create {
+		return createDraftPR(sessionData.Project.WorkingDirectory, draft)
+	}
+
+	if output != "" {
+		if err := os.WriteFile(output, []byte(draft.Markdown()), 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Kamui: Wrote %s\n", output)
+		return nil
+	}
+	fmt.Print(draft.Markdown())
+	return nil
+}
+
+// createDraftPR opens a draft pull request for the current branch with the GitHub CLI
+func createDraftPR(workingDir string, draft prdraft.Draft) error {
+	ghPath, err := exec.LookPath("gh")
+	if err != nil {
+		return fmt.Errorf("gh not found in PATH (install the GitHub CLI or drop --create): %w", err)
+	}
+
+	gh := exec.Command(ghPath, "pr", "create", "--draft", "--title", draft.Title, "--body-file", "-")
+	gh.Dir = workingDir
+	gh.Stdin = strings.NewReader(draft.Body)
+	gh.Stdout = os.Stdout
+	gh.Stderr = os.Stderr
+
+	if err := trace.Run(gh); err != nil {
+		return fmt.Errorf("gh pr create failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/registry"
+	"github.com/bitomule/kamui/internal/session"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Projects command lists every project Kamui has seen
+var projectsCmd = &cobra.Command{
+	Use:   "projects",
+	Short: "List every project with Kamui sessions",
+	Long: `Lists the projects Kamui has seen, most recently active first, with how many
+sessions each has (archived ones in brackets) and when one was last used.
+
+The list is kept in ~/.kamui/projects.json and updated whenever a session is
+created or launched. --refresh recounts every project from the stored sessions,
+e.g. after sessions were moved or deleted by hand.`,
+	Args: cobra.NoArgs,
+	RunE: runProjects,
+}
+
+func init() {
+	projectsCmd.Flags().Bool("refresh", false, "recount every project from the stored sessions")
+	projectsCmd.Flags().Bool("json", false, "print the projects as JSON")
+	rootCmd.AddCommand(projectsCmd)
+}
+
+func runProjects(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	refresh, _ := cmd.Flags().GetBool("refresh")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	store := registry.NewStore()
+	projects, err := store.List()
+	if err != nil {
+		return err
+	}
+	// sessions created before the registry existed are found on first use
+	if refresh || len(projects) == 0 {
+		if err := rebuildRegistry(ctx, store); err != nil {
+			return err
+		}
+		if projects, err = store.List(); err != nil {
+			return err
+		}
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(projects)
+	}
+
+	if len(projects) == 0 {
+		fmt.Println("Kamui: No projects found")
+		return nil
+	}
+
+	fmt.Printf("%-24s  %-13s  %-16s  %s\n", "PROJECT", "SESSIONS", "LAST ACTIVITY", "PATH")
+	for _, project := range projects {
+		sessions := fmt.Sprintf("%d", project.Sessions)
+		if project.Archived > 0 {
+			sessions = fmt.Sprintf("%d (%d archived)", project.Sessions, project.Archived)
+		}
+		lastActivity := "-"
+		if !project.LastActivity.IsZero() {
+			lastActivity = project.LastActivity.Format("2006-01-02 15:04")
+		}
+		path := project.Path
+		if _, statErr := os.Stat(path); os.IsNotExist(statErr) {
+			path += "  [missing]"
+		}
+		fmt.Printf("%-24s  %-13s  %-16s  %s\n", project.Name, sessions, lastActivity, path)
+	}
+	return nil
+}
+
+// rebuildRegistry recounts every project in the registry from all the stored sessions
+func rebuildRegistry(ctx context.Context, store *registry.Store) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	sessionStore, err := openStorage(cwd)
+	if err != nil {
+		return err
+	}
+	names, err := sessionStore.ListSessions(ctx)
+	if err != nil {
+		return err
+	}
+
+	var sessions []*types.Session
+	for _, name := range names {
+		sessionData, loadErr := sessionStore.LoadSession(ctx, name)
+		if loadErr != nil {
+			if ctx.Err() != nil {
+				return loadErr
+			}
+			continue // unreadable sessions are left to kam validate
+		}
+		sessions = append(sessions, sessionData)
+	}
+	return store.Rebuild(sessions, time.Now())
+}
+
+// registerProject records the manager's project in the registry as active now
+// Failures only go to Kamui's log, since the registry is a convenience
+func registerProject(ctx context.Context, sessionManager *session.Manager) {
+	sessions, err := sessionManager.ListProjectSessions(ctx)
+	if err == nil {
+		project := registry.Summarize(sessionManager.GetProjectPath(), sessions)
+		project.LastActivity = time.Now()
+		err = registry.NewStore().Record(project, project.LastActivity)
+	}
+	if err != nil {
+		_ = newLogger().Printf("failed to update the projects registry for %s: %v", sessionManager.GetProjectPath(), err)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// Protect command guards important sessions against cleanup
+var protectCmd = &cobra.Command{
+	Use:   "protect <session-name>",
+	Short: "Protect a session from delete, prune and trim",
+	Long: `Marks a session as protected: deleting, pruning or trimming it is refused unless
+--force is given, so long-running sessions survive bulk cleanup.
+
+--off removes the protection.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runProtect,
+}
+
+func init() {
+	protectCmd.Flags().Bool("off", false, "remove the protection")
+	rootCmd.AddCommand(protectCmd)
+}
+
+func runProtect(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	off, _ := cmd.Flags().GetBool("off")
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	if err := sessionManager.SetProtected(ctx, args[0], !off); err != nil {
+		return err
+	}
+
+	if off {
+		fmt.Printf("Kamui: Session '%s' is no longer protected\n", args[0])
+		return nil
+	}
+	fmt.Printf("Kamui: Session '%s' is protected; delete, prune and trim need --force\n", args[0])
+	return nil
+}
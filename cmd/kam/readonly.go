@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/session"
+)
+
+// Readonly command protects reference sessions from accidental edits
+var readonlyCmd = &cobra.Command{
+	Use:   "readonly <session-name>",
+	Short: "Mark a session as read-only",
+	Long: `Marks a session as read-only: resuming it prints a warning and launches Claude in
+plan permission mode, where it can read and answer but not edit files or run
+commands. Useful for sessions kept as reference conversations.
+
+--off makes the session editable again.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReadonly,
+}
+
+func init() {
+	readonlyCmd.Flags().Bool("off", false, "make the session editable again")
+	rootCmd.AddCommand(readonlyCmd)
+}
+
+func runReadonly(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	off, _ := cmd.Flags().GetBool("off")
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	if err := sessionManager.SetReadOnly(ctx, args[0], !off); err != nil {
+		return err
+	}
+
+	if off {
+		fmt.Printf("Kamui: Session '%s' is editable again\n", args[0])
+		return nil
+	}
+	fmt.Printf("Kamui: Session '%s' is read-only; Claude will start in %s mode\n", args[0], session.ReadOnlyPermissionMode)
+	return nil
+}
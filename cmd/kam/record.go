@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/bitomule/kamui/internal/record"
+	"github.com/bitomule/kamui/internal/stats"
+)
+
+// Record command launches a session with its terminal output recorded
+var recordCmd = &cobra.Command{
+	Use:   "record <session-name>",
+	Short: "Launch a session recording what Claude shows",
+	Long: `Launches the session like 'kam <session-name>', recording everything Claude shows on
+the terminal as an asciinema v2 cast in ~/.kamui/recordings/<session>/. Keystrokes are not
+recorded, only output. Set "session": {"record": true} to record every launch; each session
+keeps its last session.recordingCount casts (default 10).
+
+--list shows a session's recordings; play them back with 'kam replay' or 'asciinema play <file>'.`,
+	Args:        cobra.ExactArgs(1),
+	RunE:        runRecord,
+	Annotations: launchesClaude,
+}
+
+func init() {
+	recordCmd.Flags().Bool("list", false, "list the session's recordings")
+	rootCmd.AddCommand(recordCmd)
+}
+
+func runRecord(cmd *cobra.Command, args []string) error {
+	if list, _ := cmd.Flags().GetBool("list"); !list {
+		record.Enable(record.DefaultDir(), viper.GetInt("session.recordingCount"))
+		return runSession(cmd, args)
+	}
+
+	_, casts, err := sessionCasts(cmd.Context(), args[0])
+	if err != nil {
+		return err
+	}
+	for i, cast := range casts {
+		fmt.Printf("%3d  %s  %8s  %s\n", i+1, cast.Started.Format("2006-01-02 15:04:05"), stats.FormatBytes(cast.Size), cast.Path)
+	}
+	return nil
+}
+
+// sessionCasts returns the resolved name of a session and its recordings, oldest first; a session
+// without recordings is an error
+func sessionCasts(ctx context.Context, sessionName string) (string, []record.Cast, error) {
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return "", nil, err
+	}
+	sessionData, err := sessionManager.GetSession(ctx, sessionName)
+	if err != nil {
+		return "", nil, err
+	}
+	name := sessionData.SessionID
+
+	casts, err := record.List(record.DefaultDir(), name)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(casts) == 0 {
+		return "", nil, fmt.Errorf("session '%s' has no recordings yet; launch it with 'kam record %s'", name, name)
+	}
+	return name, casts, nil
+}
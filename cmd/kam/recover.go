@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/bitomule/kamui/internal/adopt"
+	"github.com/bitomule/kamui/internal/session"
+	"github.com/bitomule/kamui/internal/transcript"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// recoveryAction is what to do after a recoverable failure
+type recoveryAction int
+
+const (
+	recoveryAbort recoveryAction = iota
+	recoveryRetry
+	recoveryRemoveLock
+	recoveryStartFresh
+	recoveryBindNewest
+	recoveryPickConversation
+	recoveryResumeAnyway
+	recoveryRepair
+	recoveryFallBack
+)
+
+// recoveryOption is one choice offered after a recoverable failure
+type recoveryOption struct {
+	label  string
+	action recoveryAction
+}
+
+// recoveryOptions returns the ways out of a recoverable error, or nil when there are none
+// conversations is the number of unbound Claude conversations a failed resume can fall back to
+func recoveryOptions(agxErr *types.AGXError, conversations int) []recoveryOption {
+	if !agxErr.IsRecoverable() {
+		return nil
+	}
+
+	abort := recoveryOption{"Abort", recoveryAbort}
+	switch agxErr.Code {
+	case types.ErrCodeSessionLocked, types.ErrCodeStorageLocked:
+		if stale, _ := agxErr.Context["stale"].(bool); stale {
+			return []recoveryOption{{"Remove the stale lock and continue", recoveryRemoveLock}, abort}
+		}
+		return []recoveryOption{
+			{"Retry (after closing the other session)", recoveryRetry},
+			{"Remove the lock anyway (only if that kam is really gone)", recoveryRemoveLock},
+			abort,
+		}
+	case types.ErrCodeClaudeResumeFailed:
+		// retrying can't bring back a missing conversation or repair a damaged one
+		var options []recoveryOption
+		missing, _ := agxErr.Context["missing"].(bool)
+		damaged, _ := agxErr.Context["damaged"].(bool)
+		if !missing && !damaged {
+			options = append(options, recoveryOption{"Retry", recoveryRetry})
+		}
+		if conversations > 0 {
+			options = append(options, recoveryOption{"Continue the newest conversation in this directory", recoveryBindNewest})
+		}
+		if conversations > 1 {
+			options = append(options, recoveryOption{fmt.Sprintf("Pick one of the %d conversations in this directory", conversations), recoveryPickConversation})
+		}
+		return append(options, recoveryOption{"Start a fresh Claude conversation for this session", recoveryStartFresh}, abort)
+	default:
+		return []recoveryOption{{"Retry", recoveryRetry}, abort}
+	}
+}
+
+// chooseRecovery asks how to handle a recoverable error; it aborts for other errors,
+// when there is no terminal to ask on and when ctx is canceled
+func chooseRecovery(ctx context.Context, err error, conversations int) recoveryAction {
+	var agxErr *types.AGXError
+	if !errors.As(err, &agxErr) {
+		return recoveryAbort
+	}
+	options := recoveryOptions(agxErr, conversations)
+	if len(options) == 0 || ctx.Err() != nil || !term.IsTerminal(int(os.Stdin.Fd())) {
+		return recoveryAbort
+	}
+
+	fmt.Fprintf(os.Stderr, "Kamui: %s\n", agxErr.Message)
+	return askRecovery(ctx, options)
+}
+
+// askRecovery lists the options and asks for one; it aborts when input can't be read
+func askRecovery(ctx context.Context, options []recoveryOption) recoveryAction {
+	for i, option := range options {
+		fmt.Fprintf(os.Stderr, "  %d. %s\n", i+1, option.label)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Fprintf(os.Stderr, "What now? (1-%d) ", len(options))
+		input, readErr := readLine(ctx, reader)
+		if readErr != nil {
+			return recoveryAbort
+		}
+		choice, convErr := strconv.Atoi(strings.TrimSpace(input))
+		if convErr == nil && choice >= 1 && choice <= len(options) {
+			return options[choice-1].action
+		}
+	}
+}
+
+// resumeCandidates returns the conversations a failed resume can fall back to; other errors have none
+func resumeCandidates(ctx context.Context, sessionManager *session.Manager, sessionName string, err error) []adopt.Candidate {
+	var agxErr *types.AGXError
+	if !errors.As(err, &agxErr) || agxErr.Code != types.ErrCodeClaudeResumeFailed {
+		return nil
+	}
+	candidates, candidatesErr := sessionManager.ResumeCandidates(ctx, sessionName)
+	if candidatesErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to look for other conversations: %v\n", candidatesErr)
+	}
+	return candidates
+}
+
+// newestCandidate returns the most recently active conversation
+func newestCandidate(candidates []adopt.Candidate) adopt.Candidate {
+	newest := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if candidate.End.After(newest.End) {
+			newest = candidate
+		}
+	}
+	return newest
+}
+
+// pickConversation lists the conversations and asks for one; it returns false when none was picked
+func pickConversation(ctx context.Context, candidates []adopt.Candidate) (adopt.Candidate, bool) {
+	for i, candidate := range candidates {
+		prompt, _, _ := strings.Cut(strings.TrimSpace(candidate.FirstPrompt), "\n")
+		if runes := []rune(prompt); len(runes) > 60 {
+			prompt = string(runes[:59]) + "…"
+		}
+		fmt.Fprintf(os.Stderr, "  %d. %s  last active %s  %q\n", i+1, candidate.ClaudeSessionID,
+			candidate.End.Local().Format("2006-01-02 15:04"), prompt)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Fprintf(os.Stderr, "Conversation (1-%d, empty to abort): ", len(candidates))
+		input, err := readLine(ctx, reader)
+		if err != nil || strings.TrimSpace(input) == "" {
+			return adopt.Candidate{}, false
+		}
+		choice, convErr := strconv.Atoi(strings.TrimSpace(input))
+		if convErr == nil && choice >= 1 && choice <= len(candidates) {
+			return candidates[choice-1], true
+		}
+	}
+}
+
+// checkTranscriptHealth warns about a damaged transcript before it is resumed and, on a terminal,
+// asks what to do; it reports whether to go ahead with the resume
+// Choosing another conversation returns a failed resume error, which offers the fallbacks
+func checkTranscriptHealth(ctx context.Context, sessionManager *session.Manager, sessionData *types.Session) (bool, error) {
+	health, err := sessionManager.CheckTranscript(ctx, sessionData)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, err
+		}
+		fmt.Fprintf(os.Stderr, "Warning: failed to check the transcript: %v\n", err)
+		return true, nil
+	}
+	// a missing transcript is reported by the resume itself
+	if health.OK() || health.Has(transcript.IssueMissing) {
+		return true, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Kamui: The transcript of '%s' looks damaged: %s\n", sessionData.SessionID, health.Summary())
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Fprintln(os.Stderr, "Kamui: Resuming anyway; run kam on a terminal to repair it or continue another conversation")
+		return true, nil
+	}
+
+	options := []recoveryOption{{"Resume anyway", recoveryResumeAnyway}}
+	if health.Repairable() {
+		options = append(options, recoveryOption{"Drop the broken last line (the transcript is backed up first)", recoveryRepair})
+	}
+	options = append(options,
+		recoveryOption{"Continue another conversation or start fresh", recoveryFallBack},
+		recoveryOption{"Abort", recoveryAbort},
+	)
+
+	switch askRecovery(ctx, options) {
+	case recoveryResumeAnyway:
+		return true, nil
+	case recoveryRepair:
+		backupPath, repairErr := sessionManager.RepairTranscript(ctx, sessionData.SessionID)
+		if repairErr != nil {
+			return false, repairErr
+		}
+		fmt.Printf("Kamui: Dropped the broken last line (backup: %s)\n", backupPath)
+		return true, nil
+	case recoveryFallBack:
+		return false, types.NewClaudeError(
+			types.ErrCodeClaudeResumeFailed,
+			fmt.Sprintf("the transcript of Claude conversation %s looks damaged: %s", sessionData.Claude.SessionID, health.Summary()),
+			nil,
+		).WithContext("damaged", true)
+	default:
+		if ctxErr := types.ContextError(ctx); ctxErr != nil {
+			return false, ctxErr
+		}
+		fmt.Fprintln(os.Stderr, "Kamui: Aborted")
+		return false, nil
+	}
+}
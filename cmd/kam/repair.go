@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/bitomule/kamui/internal/logging"
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/internal/validate"
+)
+
+// Repair command fixes a damaged session file
+var repairCmd = &cobra.Command{
+	Use:   "repair <session-name>",
+	Short: "Repair a damaged session file",
+	Long: `Rebuilds a session file that is damaged or fails 'kam validate':
+
+  - a file that isn't valid JSON is restored from the last good version Kamui kept
+    when it last saved the session, or rebuilt from scratch when there is none
+  - sections that are missing or don't decode are restored from that version where
+    possible and dropped otherwise; unknown fields are stripped
+  - missing required fields are re-derived: the session ID from the file name, the
+    project path from the working directory (or the current directory), timestamps
+    from the others or the file's modification time, unknown states from the history
+
+Every fix is listed. The damaged file is moved into the session's backups before
+the repaired one is written; --dry-run lists the fixes without writing anything.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRepair,
+}
+
+func init() {
+	repairCmd.Flags().Bool("dry-run", false, "list the fixes without writing anything")
+	repairCmd.Flags().Bool("json", false, "print the report as JSON")
+	rootCmd.AddCommand(repairCmd)
+}
+
+func runRepair(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	name := args[0]
+
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	store := storage.New(cwd)
+	store.SetDurableWrites(viper.GetBool("storage.durableWrites"))
+	if !dryRun {
+		// a running session would overwrite the repair when it next saves
+		if err := store.LockSession(ctx, name); err != nil {
+			return err
+		}
+		defer func() { _ = store.UnlockSession(ctx, name) }()
+	}
+
+	report, err := validate.RepairFile(ctx, store, name, cwd, time.Now(), dryRun)
+	if err != nil {
+		return err
+	}
+	if !dryRun && len(report.Fixes) > 0 {
+		_ = logging.New().Audit(logging.AuditEntry{Action: "repair", Session: name, Detail: fmt.Sprintf("%d fix(es)", len(report.Fixes))})
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	if len(report.Fixes) == 0 {
+		fmt.Printf("Kamui: Session '%s' needs no repair\n", name)
+	} else {
+		fmt.Printf("%s\n", report.File)
+		for _, fix := range report.Fixes {
+			field := ""
+			if fix.Field != "" {
+				field = fix.Field + ": "
+			}
+			fmt.Printf("  fixed   %s%s\n", field, fix.Message)
+		}
+	}
+	for _, problem := range report.Remaining {
+		field := ""
+		if problem.Field != "" {
+			field = problem.Field + ": "
+		}
+		fmt.Printf("  %-7s %s%s\n", problem.Severity, field, problem.Message)
+	}
+
+	switch {
+	case len(report.Fixes) == 0:
+	case dryRun:
+		fmt.Printf("Kamui: Dry run, %d fix(es) not written\n", len(report.Fixes))
+	default:
+		fmt.Printf("Kamui: Repaired session '%s' with %d fix(es); the damaged file is in %s\n", name, len(report.Fixes), report.Backup)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/bitomule/kamui/internal/record"
+	"github.com/bitomule/kamui/internal/trace"
+)
+
+// Export formats kam replay writes
+const (
+	exportText = "txt"
+	exportGIF  = "gif"
+)
+
+// Replay command plays a session's recordings back in the terminal
+var replayCmd = &cobra.Command{
+	Use:   "replay <session-name>",
+	Short: "Play back a session's recording in the terminal",
+	Long: `Plays back a run recorded with 'kam record' (the latest, or the one picked with --cast,
+numbered as in 'kam record <session> --list'). Pauses longer than --idle-limit are shortened.
+
+While playing, space pauses and resumes, + and - double and halve the speed, and q stops.
+
+--export txt writes the recording's output as plain text (to stdout unless -o is given);
+--export gif renders it with agg (https://github.com/asciinema/agg), which must be installed.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReplay,
+}
+
+func init() {
+	replayCmd.Flags().Int("cast", 0, "recording to play, numbered as in 'kam record --list' (default: the latest)")
+	replayCmd.Flags().Float64("speed", 1, "playback speed")
+	replayCmd.Flags().Duration("idle-limit", 2*time.Second, "longest pause kept (0 keeps them all)")
+	replayCmd.Flags().String("export", "", "write the recording as txt or gif instead of playing it")
+	replayCmd.Flags().StringP("output", "o", "", "file to export to")
+	rootCmd.AddCommand(replayCmd)
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	index, _ := cmd.Flags().GetInt("cast")
+	speed, _ := cmd.Flags().GetFloat64("speed")
+	idleLimit, _ := cmd.Flags().GetDuration("idle-limit")
+	export, _ := cmd.Flags().GetString("export")
+	output, _ := cmd.Flags().GetString("output")
+
+	if speed <= 0 {
+		return fmt.Errorf("invalid --speed %v, expected a positive number", speed)
+	}
+	if export != "" && export != exportText && export != exportGIF {
+		return fmt.Errorf("unknown --export %q, expected %s or %s", export, exportText, exportGIF)
+	}
+
+	name, casts, err := sessionCasts(cmd.Context(), args[0])
+	if err != nil {
+		return err
+	}
+	if index == 0 {
+		index = len(casts)
+	}
+	if index < 1 || index > len(casts) {
+		return fmt.Errorf("session '%s' has recordings 1 to %d", name, len(casts))
+	}
+	cast := casts[index-1]
+
+	switch export {
+	case exportText:
+		return exportCastText(cast.Path, output)
+	case exportGIF:
+		if output == "" {
+			output = fmt.Sprintf("%s-%s.gif", strings.ReplaceAll(name, string(filepath.Separator), "-"), strings.TrimSuffix(filepath.Base(cast.Path), record.Ext))
+		}
+		return exportCastGIF(cast.Path, output, speed, idleLimit)
+	}
+
+	file, err := os.Open(cast.Path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	opts := record.PlayOptions{Speed: speed, MaxIdle: idleLimit}
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		state, rawErr := term.MakeRaw(int(os.Stdin.Fd()))
+		if rawErr == nil {
+			defer term.Restore(int(os.Stdin.Fd()), state)
+			opts.Keys = readKeys()
+		}
+	}
+	if err := record.Play(file, os.Stdout, opts); err != nil {
+		return fmt.Errorf("failed to play %s: %w", cast.Path, err)
+	}
+	fmt.Print("\r\n")
+	fmt.Printf("Kamui: End of recording %d of session '%s'\r\n", index, name)
+	return nil
+}
+
+// readKeys relays keypresses from stdin; the reader is left behind when playback ends, as kam exits
+func readKeys() <-chan byte {
+	keys := make(chan byte, 16)
+	go func() {
+		defer close(keys)
+		buf := make([]byte, 16)
+		for {
+			n, err := os.Stdin.Read(buf)
+			for _, b := range buf[:n] {
+				keys <- b
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return keys
+}
+
+// exportCastText writes a cast's output as plain text to output, or stdout when it is empty
+func exportCastText(castPath, output string) error {
+	in, err := os.Open(castPath)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if output == "" {
+		return record.ExportText(in, os.Stdout)
+	}
+	out, err := os.OpenFile(output, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", output, err)
+	}
+	err = record.ExportText(in, out)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	fmt.Printf("Kamui: Wrote %s\n", output)
+	return nil
+}
+
+// exportCastGIF renders a cast as a GIF with agg
+func exportCastGIF(castPath, output string, speed float64, idleLimit time.Duration) error {
+	aggPath, err := exec.LookPath("agg")
+	if err != nil {
+		return fmt.Errorf("agg not found in PATH (install it from https://github.com/asciinema/agg to export GIFs): %w", err)
+	}
+
+	args := []string{"--speed", strconv.FormatFloat(speed, 'f', -1, 64)}
+	if idleLimit > 0 {
+		args = append(args, "--idle-time-limit", strconv.FormatFloat(idleLimit.Seconds(), 'f', -1, 64))
+	}
+	agg := exec.Command(aggPath, append(args, castPath, output)...)
+	agg.Stdout = os.Stdout
+	agg.Stderr = os.Stderr
+	if err := trace.Run(agg); err != nil {
+		return fmt.Errorf("agg failed to render %s: %w", castPath, err)
+	}
+	fmt.Printf("Kamui: Wrote %s\n", output)
+	return nil
+}
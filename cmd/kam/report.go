@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/git"
+	"github.com/bitomule/kamui/internal/transcript"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Report command summarizes the work done in sessions
+var reportCmd = &cobra.Command{
+	Use:   "report [session-name]",
+	Short: "Summarize the work done in a session or the whole project",
+	Long: `Prints a Markdown work summary built from the Claude transcript: files edited,
+commands run, commits made during the session window, active time and token usage.
+
+Without a session name every session of the current project is included.
+--since limits the report to recent activity, e.g. --since 24h, --since 7d or
+--since 2025-03-01.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runReport,
+}
+
+func init() {
+	reportCmd.Flags().String("since", "", "only include activity since this duration ago (24h, 7d) or date (2006-01-02)")
+	reportCmd.Flags().StringP("output", "o", "", "write the report to this file instead of stdout")
+	rootCmd.AddCommand(reportCmd)
+}
+
+// workReportEntry is one session's section of a work report
+type workReportEntry struct {
+	Session *types.Session
+	Summary transcript.Summary
+	Commits []git.Commit
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	sinceValue, _ := cmd.Flags().GetString("since")
+	output, _ := cmd.Flags().GetString("output")
+
+	since, err := parseSince(sinceValue, time.Now())
+	if err != nil {
+		return err
+	}
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	var sessions []*types.Session
+	if len(args) == 1 {
+		sessionData, err := sessionManager.GetSession(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		sessions = append(sessions, sessionData)
+	} else {
+		if sessions, err = sessionManager.ListProjectSessions(ctx); err != nil {
+			return err
+		}
+	}
+
+	var entries []workReportEntry
+	for _, sessionData := range sessions {
+		if sessionData.Claude.SessionID == "" {
+			continue
+		}
+		_, path, err := sessionManager.TranscriptPath(ctx, sessionData.SessionID)
+		if err != nil {
+			continue
+		}
+		summary, err := transcript.SummarizeFile(ctx, path, since)
+		if ctx.Err() != nil {
+			return err
+		}
+		if err != nil {
+			continue // the conversation may have been removed outside Kamui
+		}
+
+		if summary.Start.IsZero() {
+			continue
+		}
+
+		commits, err := git.CommitsBetween(sessionData.Project.WorkingDirectory, summary.Start, summary.End)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		entries = append(entries, workReportEntry{Session: sessionData, Summary: summary, Commits: commits})
+	}
+
+	title := sessionManager.GetProjectName()
+	if len(args) == 1 {
+		title = args[0]
+	}
+	report := formatWorkReport(title, since, entries)
+
+	if output != "" {
+		if err := os.WriteFile(output, []byte(report), 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Kamui: Wrote %s\n", output)
+		return nil
+	}
+	fmt.Print(report)
+	return nil
+}
+
+// parseSince parses a --since value: a duration such as 24h, 7d or 2w, or a date
+func parseSince(value string, now time.Time) (time.Time, error) {
+	return parseCutoff("--since", value, now)
+}
+
+// parseCutoff parses the value of flag as a time before now: a duration such as 24h, 7d or 2w, or a date
+func parseCutoff(flag, value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if duration, err := types.ParseDuration(value); err == nil {
+		return now.Add(-duration.Std()), nil
+	}
+	if date, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return date, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid %s value %q: use a duration like 24h, 7d or 2w, or a date like 2006-01-02", flag, value)
+}
+
+// formatWorkReport renders a Markdown work summary
+func formatWorkReport(title string, since time.Time, entries []workReportEntry) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Work report: %s\n\n", title)
+	if !since.IsZero() {
+		fmt.Fprintf(&b, "Since %s\n\n", since.Format("2006-01-02 15:04"))
+	}
+	if len(entries) == 0 {
+		b.WriteString("No session activity found.\n")
+		return b.String()
+	}
+
+	var totalActive time.Duration
+	var totalUsage transcript.Usage
+	for _, entry := range entries {
+		summary := entry.Summary
+		totalActive += summary.Active
+		totalUsage.Add(summary.Usage)
+
+		fmt.Fprintf(&b, "## %s\n\n", entry.Session.SessionID)
+		if entry.Session.Metadata.Description != "" {
+			fmt.Fprintf(&b, "%s\n\n", entry.Session.Metadata.Description)
+		}
+		fmt.Fprintf(&b, "- Window: %s → %s (active %s)\n", summary.Start.Local().Format("2006-01-02 15:04"), summary.End.Local().Format("2006-01-02 15:04"), summary.Active.Round(time.Minute))
+		fmt.Fprintf(&b, "- Prompts: %d\n", summary.Prompts)
+		fmt.Fprintf(&b, "- Tokens: %d input, %d output, %d cache write, %d cache read\n",
+			summary.Usage.InputTokens, summary.Usage.OutputTokens, summary.Usage.CacheCreationInputTokens, summary.Usage.CacheReadInputTokens)
+		if len(summary.Models) > 0 {
+			fmt.Fprintf(&b, "- Models: %s\n", strings.Join(summary.Models, ", "))
+		}
+
+		if len(summary.FilesEdited) > 0 {
+			b.WriteString("\n### Files edited\n\n")
+			for _, file := range summary.FilesEdited {
+				if rel, err := filepath.Rel(entry.Session.Project.WorkingDirectory, file); err == nil && !strings.HasPrefix(rel, "..") {
+					file = rel
+				}
+				fmt.Fprintf(&b, "- `%s`\n", file)
+			}
+		}
+
+		if len(summary.Commands) > 0 {
+			b.WriteString("\n### Commands run\n\n")
+			for _, command := range summary.Commands {
+				fmt.Fprintf(&b, "- `%s`\n", strings.ReplaceAll(firstLine(command), "`", "'"))
+			}
+		}
+
+		if len(entry.Commits) > 0 {
+			b.WriteString("\n### Commits\n\n")
+			for _, commit := range entry.Commits {
+				fmt.Fprintf(&b, "- %s %s\n", commit.Hash, commit.Subject)
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	if len(entries) > 1 {
+		fmt.Fprintf(&b, "---\n\n%d sessions, active %s, %d tokens\n", len(entries), totalActive.Round(time.Minute), totalUsage.TotalTokens())
+	}
+	return b.String()
+}
+
+// firstLine returns the first line of s, marking truncation
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if idx := strings.IndexByte(s, '\n'); idx >= 0 {
+		return s[:idx] + " …"
+	}
+	return s
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/logging"
+	"github.com/bitomule/kamui/internal/snapshot"
+	"github.com/bitomule/kamui/internal/stats"
+)
+
+// Rollback command restores a session's working tree to a snapshot taken before one of its runs
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback <session-name>",
+	Short: "Restore the working tree to how it was before a session's run",
+	Long: `Kamui snapshots the repository each time a session launches: the commit checked out
+and, unless session.snapshotDirty is off, a patch of the uncommitted changes to
+tracked files. The last session.snapshotCount (default 20) are kept per session.
+
+Rollback restores the tracked files to the snapshot before the session's last run,
+or the one given with --to (see --list). Commits made since stay in the branch
+history; only the working tree and index go back. Untracked files are left alone.
+
+The current state is snapshotted first, so a rollback can itself be rolled back.
+Asks for confirmation first unless --yes is given or ui.confirmDestructive is off.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRollback,
+}
+
+func init() {
+	rollbackCmd.Flags().Int("to", 0, "snapshot to restore (default: the one before the last run)")
+	rollbackCmd.Flags().Bool("list", false, "list the session's snapshots")
+	rollbackCmd.Flags().Bool("dry-run", false, "show what would be restored without changing anything")
+	rootCmd.AddCommand(rollbackCmd)
+}
+
+func runRollback(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	to, _ := cmd.Flags().GetInt("to")
+	list, _ := cmd.Flags().GetBool("list")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+	sessionData, err := sessionManager.GetSession(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	name := sessionData.SessionID
+
+	store := snapshot.NewStore()
+	history, err := store.List(name)
+	if err != nil {
+		return err
+	}
+	if len(history) == 0 {
+		return fmt.Errorf("session '%s' has no snapshots yet; they are taken each time it launches in a git repository", name)
+	}
+	if list {
+		for _, snap := range history {
+			fmt.Println(describeSnapshot(snap))
+		}
+		return nil
+	}
+
+	target, err := pickSnapshot(store, name, history, to)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		fmt.Printf("Kamui: Dry run, nothing was changed; would restore %s to:\n  %s\n", target.Root, describeSnapshot(*target))
+		return nil
+	}
+
+	resolved, err := sessionManager.LockSession(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if unlockErr := sessionManager.UnlockSession(context.WithoutCancel(ctx), resolved); unlockErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", unlockErr)
+		}
+	}()
+
+	proceed, err := confirmDestructive(ctx, fmt.Sprintf("Restore the tracked files of %s to snapshot %d (%s)?",
+		target.Root, target.ID, target.Taken.Local().Format("2006-01-02 15:04")))
+	if err != nil || !proceed {
+		return err
+	}
+
+	// Keep the current state so the rollback can be undone
+	current, err := store.Take(ctx, name, target.Root, snapshot.ReasonRollback, true, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to snapshot the current state, nothing was changed: %w", err)
+	}
+	if err := store.Restore(ctx, name, target); err != nil {
+		return err
+	}
+	_ = logging.New().Audit(logging.AuditEntry{Action: "rollback", Session: name, Detail: fmt.Sprintf("snapshot %d", target.ID)})
+
+	fmt.Printf("Kamui: Restored %s to snapshot %d\n", target.Root, target.ID)
+	if current != nil {
+		fmt.Printf("Kamui: Undo with 'kam rollback %s --to %d'\n", name, current.ID)
+		if current.Branch != target.Branch && target.Branch != "" {
+			fmt.Fprintf(os.Stderr, "Warning: snapshot %d was taken on branch %s, you are on %s; the branch was not switched\n",
+				target.ID, target.Branch, describeBranch(current.Branch))
+		}
+	}
+	return nil
+}
+
+// pickSnapshot returns the snapshot to restore: the one asked for, or the last taken at launch
+func pickSnapshot(store *snapshot.Store, name string, history []snapshot.Snapshot, to int) (*snapshot.Snapshot, error) {
+	if to != 0 {
+		return store.Get(name, to)
+	}
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Reason == snapshot.ReasonLaunch {
+			return &history[i], nil
+		}
+	}
+	return &history[len(history)-1], nil
+}
+
+// describeSnapshot renders a snapshot as one line of the --list output
+func describeSnapshot(snap snapshot.Snapshot) string {
+	line := fmt.Sprintf("%3d  %s  %-8s  %.12s on %s", snap.ID, snap.Taken.Local().Format("2006-01-02 15:04"),
+		snap.Reason, snap.Commit, describeBranch(snap.Branch))
+	if snap.Patch != "" {
+		line += fmt.Sprintf(" + uncommitted changes (%s)", stats.FormatBytes(snap.Changes))
+	}
+	return line
+}
+
+// describeBranch names a branch for display, a detached HEAD when there is none
+func describeBranch(branch string) string {
+	if branch == "" {
+		return "detached HEAD"
+	}
+	return branch
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/bitomule/kamui/internal/notify"
+	"github.com/bitomule/kamui/internal/session"
+)
+
+// Run command for headless prompts
+var runCmd = &cobra.Command{
+	Use:   "run [session-name]",
+	Short: "Run a prompt against a session without opening Claude",
+	Long: `Runs a single prompt headlessly (claude -p) against the session's Claude conversation
+and prints the result. A desktop notification is shown when the run finishes.
+
+With --tag, the prompt runs against every session carrying that tag in parallel
+(bounded by --concurrency) and a per-session report is printed.`,
+	Args:        cobra.MaximumNArgs(1),
+	RunE:        runHeadless,
+	Annotations: launchesClaude,
+}
+
+func init() {
+	runCmd.Flags().StringP("prompt", "p", "", "prompt to send to Claude (required)")
+	runCmd.Flags().Bool("notify", true, "show a desktop notification when the run finishes")
+	runCmd.Flags().String("tag", "", "run against every session with this tag")
+	runCmd.Flags().Int("concurrency", 4, "maximum number of parallel runs when using --tag")
+	runCmd.Flags().String("report", "", "also write the batch report to this file")
+	if err := runCmd.MarkFlagRequired("prompt"); err != nil {
+		panic(fmt.Sprintf("failed to mark prompt flag required: %v", err))
+	}
+
+	rootCmd.AddCommand(runCmd)
+}
+
+func runHeadless(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	prompt, _ := cmd.Flags().GetString("prompt")
+	tag, _ := cmd.Flags().GetString("tag")
+
+	if (len(args) == 0) == (tag == "") {
+		return fmt.Errorf("specify either a session name or --tag")
+	}
+
+	shouldNotify := viper.GetBool("ui.notifications")
+	if cmd.Flags().Changed("notify") {
+		shouldNotify, _ = cmd.Flags().GetBool("notify")
+	}
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	if tag != "" {
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		reportFile, _ := cmd.Flags().GetString("report")
+		return runHeadlessBatch(ctx, sessionManager, tag, prompt, concurrency, reportFile, shouldNotify)
+	}
+
+	sessionName := args[0]
+	result, err := sessionManager.RunHeadless(ctx, sessionName, prompt)
+	if err != nil {
+		if shouldNotify {
+			notifyRunFinished(sessionName, "failed", err.Error())
+		}
+		return err
+	}
+
+	fmt.Println(result.Result)
+
+	if result.IsError {
+		if shouldNotify {
+			notifyRunFinished(sessionName, "failed", result.Result)
+		}
+		return fmt.Errorf("claude reported an error for session '%s'", sessionName)
+	}
+
+	if shouldNotify {
+		notifyRunFinished(sessionName, "finished", result.Result)
+	}
+
+	return nil
+}
+
+// runHeadlessBatch runs the prompt against every session with the tag and prints a report
+func runHeadlessBatch(ctx context.Context, sessionManager *session.Manager, tag, prompt string, concurrency int, reportFile string, shouldNotify bool) error {
+	sessionNames, err := sessionManager.FindSessionsByTag(ctx, tag)
+	if err != nil {
+		return err
+	}
+	if len(sessionNames) == 0 {
+		return fmt.Errorf("no sessions tagged '%s'", tag)
+	}
+
+	fmt.Fprintf(os.Stderr, "Kamui: Running prompt against %d sessions tagged '%s' (concurrency %d)...\n", len(sessionNames), tag, concurrency)
+
+	results := sessionManager.RunHeadlessBatch(ctx, sessionNames, prompt, concurrency)
+	report, failures := formatBatchReport(tag, prompt, results)
+
+	fmt.Print(report)
+	if reportFile != "" {
+		if err := os.WriteFile(reportFile, []byte(report), 0o600); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+	}
+
+	if shouldNotify {
+		outcome := "finished"
+		if failures > 0 {
+			outcome = "finished with failures"
+		}
+		summary := fmt.Sprintf("%d of %d sessions succeeded", len(results)-failures, len(results))
+		notifyRunFinished("tag "+tag, outcome, summary)
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("%d of %d runs failed", failures, len(results))
+	}
+	return nil
+}
+
+// formatBatchReport renders batch results as Markdown and counts failed runs
+func formatBatchReport(tag, prompt string, results []session.BatchResult) (string, int) {
+	var b strings.Builder
+	failures := 0
+
+	fmt.Fprintf(&b, "# Kamui batch run: tag '%s'\n\n", tag)
+	fmt.Fprintf(&b, "Prompt: %s\n\n", prompt)
+
+	for _, result := range results {
+		status, body := "ok", ""
+		switch {
+		case result.Err != nil:
+			status, body = "failed", result.Err.Error()
+		case result.Result.IsError:
+			status, body = "failed", result.Result.Result
+		default:
+			body = result.Result.Result
+		}
+		if status != "ok" {
+			failures++
+		}
+
+		fmt.Fprintf(&b, "## %s (%s, %s)\n\n", result.SessionID, status, result.Duration.Round(100*time.Millisecond))
+		fmt.Fprintf(&b, "%s\n\n", strings.TrimSpace(body))
+	}
+
+	fmt.Fprintf(&b, "%d of %d sessions succeeded\n", len(results)-failures, len(results))
+	return b.String(), failures
+}
+
+// notifyRunFinished shows a desktop notification for a completed headless run
+func notifyRunFinished(sessionName, outcome, message string) {
+	title := fmt.Sprintf("Kamui: %s %s", sessionName, outcome)
+	if err := notify.Send(title, message); err != nil && viper.GetBool("verbose") {
+		fmt.Fprintf(os.Stderr, "Warning: failed to send notification: %v\n", err)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/schedule"
+)
+
+// Schedule command group for recurring headless prompts
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Manage scheduled headless runs",
+	Long: `Schedules run headless prompts against sessions on a cron schedule.
+Schedules are executed by 'kam daemon' and each result is recorded as a note on the session.`,
+}
+
+var scheduleAddCmd = &cobra.Command{
+	Use:   "add <session-name>",
+	Short: "Schedule a recurring headless prompt for a session",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runScheduleAdd,
+}
+
+var scheduleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List scheduled runs",
+	Args:  cobra.NoArgs,
+	RunE:  runScheduleList,
+}
+
+var scheduleRemoveCmd = &cobra.Command{
+	Use:   "remove <schedule-id>",
+	Short: "Remove a scheduled run",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		if err := schedule.NewStore().Remove(args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("Kamui: Removed schedule %s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	scheduleAddCmd.Flags().String("cron", "", "five-field cron expression, e.g. \"0 9 * * 1\" (required)")
+	scheduleAddCmd.Flags().StringP("prompt", "p", "", "prompt to send to Claude (required)")
+	for _, name := range []string{"cron", "prompt"} {
+		if err := scheduleAddCmd.MarkFlagRequired(name); err != nil {
+			panic(fmt.Sprintf("failed to mark %s flag required: %v", name, err))
+		}
+	}
+
+	scheduleCmd.AddCommand(scheduleAddCmd)
+	scheduleCmd.AddCommand(scheduleListCmd)
+	scheduleCmd.AddCommand(scheduleRemoveCmd)
+	rootCmd.AddCommand(scheduleCmd)
+}
+
+func runScheduleAdd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	sessionName := args[0]
+	cronExpr, _ := cmd.Flags().GetString("cron")
+	prompt, _ := cmd.Flags().GetString("prompt")
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	// Make sure the session exists before scheduling work against it
+	if _, err := sessionManager.GetSession(ctx, sessionName); err != nil {
+		return err
+	}
+
+	entry := &schedule.Schedule{
+		SessionID:   sessionName,
+		ProjectPath: sessionManager.GetProjectPath(),
+		Cron:        cronExpr,
+		Prompt:      prompt,
+	}
+	if err := schedule.NewStore().Add(entry); err != nil {
+		return err
+	}
+
+	next, _ := entry.NextRun()
+	fmt.Printf("Kamui: Scheduled %s for session '%s' (next run %s)\n", entry.ID, sessionName, next.Format("2006-01-02 15:04"))
+	fmt.Println("Kamui: Schedules run while 'kam daemon' is running")
+	return nil
+}
+
+func runScheduleList(_ *cobra.Command, _ []string) error {
+	schedules, err := schedule.NewStore().List()
+	if err != nil {
+		return err
+	}
+
+	if len(schedules) == 0 {
+		fmt.Println("Kamui: No scheduled runs")
+		return nil
+	}
+
+	for _, entry := range schedules {
+		fmt.Printf("  %s  %-15s  %s\n", entry.ID, entry.Cron, entry.SessionID)
+		fmt.Printf("     Prompt: %s\n", entry.Prompt)
+		if next, err := entry.NextRun(); err == nil && !next.IsZero() {
+			fmt.Printf("     Next run: %s\n", next.Format("2006-01-02 15:04"))
+		}
+		if entry.LastRun != nil {
+			fmt.Printf("     Last run: %s (%s)\n", entry.LastRun.Format(time.DateTime), entry.LastStatus)
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
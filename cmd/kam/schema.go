@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/schema"
+)
+
+// Schema command prints JSON Schemas for Kamui's file formats
+var schemaCmd = &cobra.Command{
+	Use:   "schema [session|index|config]",
+	Short: "Print the JSON Schema of a Kamui file format",
+	Long: `Prints a JSON Schema (draft 2020-12) derived from Kamui's types, for validating
+and autocompleting session files (the default), the global index or the config file
+in editors and external tools.
+
+Fields Kamui always writes are required in the session and index schemas; nothing
+is required in the config schema since config files are usually partial.`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: schema.Names(),
+	RunE:      runSchema,
+}
+
+func init() {
+	schemaCmd.Flags().StringP("output", "o", "", "write the schema to this file instead of stdout")
+	rootCmd.AddCommand(schemaCmd)
+}
+
+func runSchema(cmd *cobra.Command, args []string) error {
+	output, _ := cmd.Flags().GetString("output")
+
+	name := "session"
+	if len(args) == 1 {
+		name = args[0]
+	}
+	document, ok := schema.Find(name)
+	if !ok {
+		return fmt.Errorf("unknown format '%s' (expected one of: %s)", name, strings.Join(schema.Names(), ", "))
+	}
+
+	data, err := json.MarshalIndent(schema.Generate(document), "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if output != "" {
+		if err := os.WriteFile(output, data, 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Kamui: Wrote %s\n", output)
+		return nil
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/internal/storage/remote"
+)
+
+// Serve command shares this machine's session catalog with remote clients
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the session catalog to other machines",
+	Long: `Serves sessions over HTTP so machines configured with the remote storage backend
+("storage": {"backend": "remote", "remote": {"url": "http://host:7878"}}) share one
+session catalog. Sessions are kept in the --backend store on this machine.
+
+Set KAMUI_SERVER_TOKEN (or storage.remote.token) to require clients to send the same
+token. Put the server behind TLS before exposing it beyond a trusted network.`,
+	Args: cobra.NoArgs,
+	RunE: runServe,
+}
+
+func init() {
+	serveCmd.Flags().String("listen", "127.0.0.1:7878", "address to listen on")
+	serveCmd.Flags().String("backend", storage.DefaultBackend, "storage backend holding the served sessions")
+	rootCmd.AddCommand(serveCmd)
+}
+
+func runServe(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	listen, _ := cmd.Flags().GetString("listen")
+	backend, _ := cmd.Flags().GetString("backend")
+	if backend == remote.BackendName {
+		return fmt.Errorf("kam serve can't serve the remote backend; use --backend %s", storage.DefaultBackend)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	store, err := storage.Open(backend, storage.Options{ProjectPath: cwd, DurableWrites: viper.GetBool("storage.durableWrites")})
+	if err != nil {
+		return err
+	}
+
+	token := serverToken()
+	if token == "" {
+		fmt.Fprintln(os.Stderr, "Warning: no KAMUI_SERVER_TOKEN set; anyone who can reach the server can change sessions")
+	}
+
+	server := &http.Server{
+		Addr:              listen,
+		Handler:           remote.NewHandler(store, token),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("Kamui: Serving %s sessions on http://%s, press Ctrl+C to stop\n", backend, listen)
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	fmt.Println("Kamui: Server stopped")
+	return nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/stats"
+	"github.com/bitomule/kamui/internal/trace"
+	"github.com/bitomule/kamui/internal/transcript"
+)
+
+// Share-transcript command publishes a session's Claude conversation as a secret gist or an HTML file
+var shareTranscriptCmd = &cobra.Command{
+	Use:   "share-transcript <session-name>",
+	Short: "Publish a session's Claude conversation as a secret gist or an HTML file",
+	Long: `Renders the session's Claude conversation like 'kam export-transcript' and uploads it
+as a secret GitHub gist, printing a link to drop into a code review. Secret gists are
+unlisted: anyone with the link can read them, so check the conversation holds nothing
+private before sharing it. kam asks before uploading; --yes skips the question.
+
+The gist is created with the GitHub token from GITHUB_TOKEN, GH_TOKEN or github.token
+(it needs the gist scope), or else with the GitHub CLI (gh) and its login.
+
+With --html the conversation is written to a standalone HTML file instead, and its
+file:// link is printed; nothing is uploaded.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runShareTranscript,
+}
+
+func init() {
+	shareTranscriptCmd.Flags().StringP("format", "f", "md", "gist format: md or html")
+	shareTranscriptCmd.Flags().String("html", "", "write a standalone HTML file here instead of creating a gist")
+	rootCmd.AddCommand(shareTranscriptCmd)
+}
+
+func runShareTranscript(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	formatName, _ := cmd.Flags().GetString("format")
+	htmlFile, _ := cmd.Flags().GetString("html")
+
+	format, err := transcript.ParseFormat(formatName)
+	if err != nil {
+		return err
+	}
+	if htmlFile != "" {
+		if cmd.Flags().Changed("format") && format != transcript.FormatHTML {
+			return fmt.Errorf("--html always writes HTML; drop --format")
+		}
+		format = transcript.FormatHTML
+	}
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	sessionData, path, err := sessionManager.TranscriptPath(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	turns, err := readTurns(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	title := sessionData.SessionID
+	if sessionData.Metadata.Description != "" {
+		title = fmt.Sprintf("%s: %s", sessionData.SessionID, sessionData.Metadata.Description)
+	}
+
+	var buf bytes.Buffer
+	if err := transcript.ExportTurns(&buf, title, turns, format); err != nil {
+		return err
+	}
+
+	if htmlFile != "" {
+		absolute, err := filepath.Abs(htmlFile)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(absolute, buf.Bytes(), 0o644); err != nil {
+			return err
+		}
+		fmt.Println("file://" + filepath.ToSlash(absolute))
+		return nil
+	}
+
+	proceed, err := confirmDestructive(ctx, fmt.Sprintf("Upload the conversation of '%s' (%d turns, %s) as a secret gist anyone with the link can read?",
+		sessionData.SessionID, len(turns), stats.FormatBytes(int64(buf.Len()))))
+	if err != nil || !proceed {
+		return err
+	}
+
+	filename := sessionData.SessionID + "." + string(format)
+	link, err := createGist(ctx, title, filename, buf.String())
+	if err != nil {
+		return err
+	}
+	fmt.Println(link)
+	return nil
+}
+
+// createGist uploads content as a secret gist, through the API when a GitHub token is set and
+// with gh otherwise, and returns its URL
+func createGist(ctx context.Context, description, filename, content string) (string, error) {
+	if githubToken() != "" {
+		client, err := newGistClient()
+		if err != nil {
+			return "", err
+		}
+		return client.Create(ctx, description, filename, content, false)
+	}
+
+	ghPath, err := exec.LookPath("gh")
+	if err != nil {
+		return "", fmt.Errorf("no GitHub token and gh not found in PATH (set GITHUB_TOKEN or github.token, install the GitHub CLI, or use --html): %w", err)
+	}
+
+	gh := exec.CommandContext(ctx, ghPath, "gist", "create", "--desc", description, "--filename", filename, "-")
+	gh.Stdin = strings.NewReader(content)
+	gh.Stderr = os.Stderr
+
+	out, err := trace.Output(gh)
+	if err != nil {
+		return "", fmt.Errorf("gh gist create failed: %w", err)
+	}
+	// gh prints the gist URL as its last line
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1]), nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// signalContext returns the context commands run under, canceled by SIGINT, SIGTERM or SIGHUP
+// Cancellation stops polling, prompts and Claude subprocesses so kam exits through its normal
+// cleanup (releasing locks, finishing writes) instead of dying mid-operation
+// Interactive Claude owns the terminal and Ctrl-C pressed there is meant for it, so work done
+// after Claude exits runs under context.WithoutCancel
+func signalContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+}
+
+// readLine reads a line of input, giving up with ErrCodeInterrupted when ctx is canceled
+func readLine(ctx context.Context, reader *bufio.Reader) (string, error) {
+	type line struct {
+		text string
+		err  error
+	}
+	lines := make(chan line, 1)
+	go func() {
+		text, err := reader.ReadString('\n')
+		lines <- line{text, err}
+	}()
+
+	select {
+	case read := <-lines:
+		return read.text, read.err
+	case <-ctx.Done():
+		return "", types.ContextError(ctx)
+	}
+}
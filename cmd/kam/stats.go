@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/stats"
+)
+
+// Stats command reports session counts and disk usage across all projects
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show session counts and disk usage",
+	Long: `Shows how many sessions Kamui tracks and how much disk they use, per project
+and in total. Usage covers session metadata and each session's Claude transcript.
+
+Estimated cost is computed from the token usage in the transcripts using the
+pricing table, which can be overridden under "pricing" in the config file.`,
+	Args: cobra.NoArgs,
+	RunE: runStats,
+}
+
+func init() {
+	statsCmd.Flags().Bool("json", false, "print the report as JSON")
+	rootCmd.AddCommand(statsCmd)
+}
+
+func runStats(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	store, err := openStorage(cwd)
+	if err != nil {
+		return err
+	}
+	report, err := stats.Collect(ctx, store, homeDir, pricingTable())
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	fmt.Printf("Kamui: %d session(s) in %d project(s), %d active, %s on disk, ~%s estimated cost\n",
+		report.Index.TotalSessions, report.Index.TotalProjects, report.Index.ActiveSessionsCount, report.Index.DiskUsage,
+		formatCost(report.TotalCostUSD))
+	if len(report.Projects) == 0 {
+		return nil
+	}
+
+	fmt.Println()
+	for _, project := range report.Projects {
+		fmt.Printf("  %-24s %3d session(s)  %8s  (metadata %s, backups %s, transcripts %s)\n",
+			project.ProjectName,
+			project.Sessions,
+			stats.FormatBytes(project.TotalBytes()),
+			stats.FormatBytes(project.MetadataBytes),
+			stats.FormatBytes(project.BackupBytes),
+			stats.FormatBytes(project.TranscriptBytes))
+		fmt.Printf("  %-24s %s tokens, ~%s\n", "", formatTokens(project.Tokens), formatCost(project.CostUSD))
+		fmt.Printf("  %-24s %s\n", "", project.ProjectPath)
+	}
+	return nil
+}
+
+// formatCost renders an estimated USD cost
+func formatCost(usd float64) string {
+	return fmt.Sprintf("$%.2f", usd)
+}
+
+// formatTokens renders a token count with a k/M suffix
+func formatTokens(tokens int64) string {
+	switch {
+	case tokens >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(tokens)/1e6)
+	case tokens >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(tokens)/1e3)
+	default:
+		return fmt.Sprintf("%d", tokens)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/bitomule/kamui/pkg/kamui"
+)
+
+// Status command reports the project's sessions, optionally as JSON for integrations
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the current project's sessions",
+	Long: `Shows the sessions of the current project and which one is active in this shell.
+
+With --json a versioned document (see docs/status-json.md) is printed; editor
+extensions and other integrations can rely on its schemaVersion contract.`,
+	Args: cobra.NoArgs,
+	RunE: runStatus,
+}
+
+func init() {
+	statusCmd.Flags().Bool("json", false, "print the status as JSON")
+	rootCmd.AddCommand(statusCmd)
+}
+
+func runStatus(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	client, err := kamui.Open(kamui.Options{
+		ProjectDetection: viper.GetString("default.projectDetection"),
+		ProjectMarkers:   viper.GetStringSlice("default.projectMarkers"),
+		CaseFolding:      viper.GetBool("session.caseFolding"),
+	})
+	if err != nil {
+		return err
+	}
+
+	report, err := client.Status(ctx, os.Getenv("KAMUI_SESSION_ID"))
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	fmt.Printf("Kamui: Project %s (%s)\n", report.Project.Name, report.Project.Path)
+	if report.CurrentSession != "" {
+		fmt.Printf("Kamui: Current session: %s\n", report.CurrentSession)
+	}
+	if len(report.Sessions) == 0 {
+		fmt.Println("Kamui: No sessions for this project")
+		return nil
+	}
+
+	fmt.Println()
+	for _, s := range report.Sessions {
+		marker := " "
+		if s.Name == report.CurrentSession {
+			marker = "*"
+		}
+		fmt.Printf(" %s %-24s %-10s last accessed %s\n", marker, s.Name, s.State, s.LastAccessed.Format("2006-01-02 15:04"))
+	}
+	return nil
+}
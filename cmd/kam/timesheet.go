@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/timesheet"
+)
+
+// Timesheet command summarizes the time spent in interactive sessions
+var timesheetCmd = &cobra.Command{
+	Use:   "timesheet",
+	Short: "Show the hours spent in sessions per project",
+	Long: `Kamui records every interactive run of a session, from launch until Claude
+exits, in ~/.kamui/timesheet.jsonl (disable with timesheet.enabled: false).
+
+The timesheet sums the recorded time per project and session. --week limits it
+to the current week (from Monday), --since to recent activity (24h, 7d or
+2025-03-01). --csv prints one row per run instead, for spreadsheets and billing.`,
+	Args: cobra.NoArgs,
+	RunE: runTimesheet,
+}
+
+func init() {
+	timesheetCmd.Flags().Bool("week", false, "only include the current week")
+	timesheetCmd.Flags().String("since", "", "only include time since this duration ago (24h, 7d) or date (2006-01-02)")
+	timesheetCmd.Flags().Bool("csv", false, "print one CSV row per run")
+	timesheetCmd.Flags().Bool("json", false, "print the summary as JSON")
+	timesheetCmd.Flags().StringP("output", "o", "", "write to this file instead of stdout")
+	rootCmd.AddCommand(timesheetCmd)
+}
+
+func runTimesheet(cmd *cobra.Command, _ []string) error {
+	week, _ := cmd.Flags().GetBool("week")
+	sinceValue, _ := cmd.Flags().GetString("since")
+	asCSV, _ := cmd.Flags().GetBool("csv")
+	asJSON, _ := cmd.Flags().GetBool("json")
+	output, _ := cmd.Flags().GetString("output")
+
+	if week && sinceValue != "" {
+		return fmt.Errorf("specify only one of --week and --since")
+	}
+	if asCSV && asJSON {
+		return fmt.Errorf("specify only one of --csv and --json")
+	}
+
+	now := time.Now()
+	since, err := parseSince(sinceValue, now)
+	if err != nil {
+		return err
+	}
+	if week {
+		since = timesheet.WeekStart(now)
+	}
+
+	entries, err := timesheet.NewStore().Entries()
+	if err != nil {
+		return err
+	}
+	summary := timesheet.Summarize(entries, since, time.Time{})
+
+	out := os.Stdout
+	if output != "" {
+		file, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	switch {
+	case asCSV:
+		err = timesheet.WriteCSV(out, summary)
+	case asJSON:
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(summary)
+	default:
+		_, err = fmt.Fprint(out, formatTimesheet(summary))
+	}
+	if err != nil {
+		return err
+	}
+
+	if output != "" {
+		fmt.Fprintf(os.Stderr, "Kamui: Wrote %s\n", output)
+	}
+	return nil
+}
+
+// formatTimesheet renders the per-project and per-session hours as a table
+func formatTimesheet(summary timesheet.Summary) string {
+	period := "all recorded time"
+	if !summary.From.IsZero() {
+		period = "since " + summary.From.Format("Mon 2006-01-02 15:04")
+	}
+	if len(summary.Projects) == 0 {
+		return fmt.Sprintf("Kamui: No session time recorded (%s)\n", period)
+	}
+
+	text := fmt.Sprintf("Kamui: %sh in %d project(s), %s\n", timesheet.Hours(summary.Total), len(summary.Projects), period)
+	for _, project := range summary.Projects {
+		text += fmt.Sprintf("\n  %-40s %7sh\n", project.Project, timesheet.Hours(project.Duration))
+		for _, session := range project.Sessions {
+			text += fmt.Sprintf("    %-38s %7sh  (%d run(s))\n", session.SessionID, timesheet.Hours(session.Duration), session.Runs)
+		}
+	}
+	return text
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/stats"
+)
+
+// Trim command truncates a session's Claude transcript
+var trimCmd = &cobra.Command{
+	Use:   "trim <session-name>",
+	Short: "Truncate a session's Claude transcript to its last exchanges",
+	Long: `Keeps only the last N exchanges (a prompt and everything Claude did in response)
+of the session's Claude transcript, so huge conversations resume quickly again.
+
+The original transcript is backed up next to the session metadata first. Don't trim
+a session while Claude is running in it. Protected sessions need --force.
+
+Asks for confirmation first unless --yes is given or ui.confirmDestructive is off.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTrim,
+}
+
+func init() {
+	trimCmd.Flags().Int("keep-last", 200, "number of exchanges to keep")
+	trimCmd.Flags().Bool("force", false, "trim even if the session is protected")
+	rootCmd.AddCommand(trimCmd)
+}
+
+func runTrim(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	keepLast, _ := cmd.Flags().GetInt("keep-last")
+	force, _ := cmd.Flags().GetBool("force")
+
+	sessionManager, err := newSessionManager()
+	if err != nil {
+		return err
+	}
+
+	proceed, err := confirmDestructive(ctx, fmt.Sprintf("Trim session '%s' to its last %d exchanges?", args[0], keepLast))
+	if err != nil || !proceed {
+		return err
+	}
+
+	result, err := sessionManager.TrimTranscript(ctx, args[0], keepLast, force)
+	if err != nil {
+		return err
+	}
+
+	if result.RemovedRecords == 0 {
+		fmt.Printf("Kamui: Transcript has %d exchange(s), nothing to trim\n", result.Exchanges)
+		return nil
+	}
+
+	fmt.Printf("Kamui: Kept %d of %d exchanges (%s -> %s)\n",
+		result.KeptExchanges, result.Exchanges, stats.FormatBytes(result.BytesBefore), stats.FormatBytes(result.BytesAfter))
+	fmt.Printf("Kamui: Original saved to %s\n", result.BackupPath)
+	return nil
+}
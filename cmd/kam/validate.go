@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/internal/validate"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Validate command checks the integrity of every session file
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check every session file for problems",
+	Long: `Loads every session file and checks required fields, timestamps, lifecycle states
+and Claude bindings, reporting each problem with its file and error code.
+
+Exits with a non-zero status when a file has errors (or, with --strict, warnings),
+so it can gate a sync or run from cron.`,
+	Args: cobra.NoArgs,
+	RunE: runValidate,
+}
+
+func init() {
+	validateCmd.Flags().Bool("json", false, "print the report as JSON")
+	validateCmd.Flags().Bool("strict", false, "fail on warnings as well as errors")
+	rootCmd.AddCommand(validateCmd)
+}
+
+func runValidate(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	asJSON, _ := cmd.Flags().GetBool("json")
+	strict, _ := cmd.Flags().GetBool("strict")
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	store := storage.New(cwd)
+	report, err := validate.Store(ctx, store, homeDir, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		file := ""
+		for _, problem := range report.Problems {
+			if problem.File != file {
+				file = problem.File
+				fmt.Printf("%s\n", file)
+			}
+			field := ""
+			if problem.Field != "" {
+				field = problem.Field + ": "
+			}
+			fmt.Printf("  %-7s %-24s %s%s\n", problem.Severity, problem.Code, field, problem.Message)
+		}
+		if len(report.Problems) > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("Kamui: Checked %d session file(s) in %s: %d error(s), %d warning(s)\n",
+			report.Files, store.GetSessionsPath(), report.Errors, report.Warnings)
+	}
+
+	if report.Errors > 0 || (strict && report.Warnings > 0) {
+		return types.NewSessionError(
+			types.ErrCodeSessionInvalid,
+			fmt.Sprintf("validation failed: %d error(s), %d warning(s)", report.Errors, report.Warnings),
+			nil,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitomule/kamui/internal/transcript"
+	"github.com/bitomule/kamui/internal/viewer"
+)
+
+// View command opens a read-only, scrollable view of a session's Claude conversation
+var viewCmd = &cobra.Command{
+	Use:   "view [session-name]",
+	Short: "Browse a session's Claude conversation without resuming it",
+	Long: `Opens the session's Claude transcript in a full-screen, read-only viewer. Tool calls
+start folded; nothing about the session or the transcript is changed.
+
+Keys: j/k or arrows move between messages, enter folds or unfolds, z/Z fold or
+unfold all tool calls, t/T jump to the next or previous tool call, / searches
+(regular expressions, case-insensitive), n/N repeat the search, q quits.
+
+Use --file to view a transcript that isn't bound to a local session, such as one
+shared by a teammate.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runView,
+}
+
+func init() {
+	viewCmd.Flags().String("file", "", "view this transcript file (.jsonl) instead of a session's")
+	rootCmd.AddCommand(viewCmd)
+}
+
+func runView(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	file, _ := cmd.Flags().GetString("file")
+	if (len(args) == 0) == (file == "") {
+		return fmt.Errorf("specify either a session name or --file")
+	}
+
+	title, path := filepath.Base(file), file
+	if file == "" {
+		sessionManager, err := newSessionManager()
+		if err != nil {
+			return err
+		}
+
+		sessionData, transcriptPath, err := sessionManager.TranscriptPath(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		title, path = sessionData.SessionID, transcriptPath
+		if sessionData.Metadata.Description != "" {
+			title = fmt.Sprintf("%s: %s", sessionData.SessionID, sessionData.Metadata.Description)
+		}
+	}
+
+	turns, err := readTurns(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	return viewer.Run(viewer.New(title, turns), os.Stdin, os.Stdout)
+}
+
+// readTurns reads a transcript's turns within the configured limits, warning about anything left out
+func readTurns(ctx context.Context, path string) ([]*transcript.Turn, error) {
+	turns, readStats, err := transcript.ReadTurns(ctx, path)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to read transcript %s: %w", path, err)
+	}
+	if warning := readStats.Warning(); warning != "" {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+	return turns, nil
+}
@@ -0,0 +1,187 @@
+// Package adopt finds Claude Code transcripts that no Kamui session is bound to,
+// so conversations started with plain `claude` can be given a session afterwards
+package adopt
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bitomule/kamui/internal/paths"
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/internal/transcript"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Candidate is an unbound transcript that a session can be created for
+type Candidate struct {
+	ClaudeSessionID string
+	TranscriptPath  string
+
+	// WorkingDir is the directory Claude ran in
+	WorkingDir string
+
+	FirstPrompt string
+	Prompts     int
+	Start       time.Time
+	End         time.Time
+}
+
+// Skipped is an unbound transcript that can't be adopted, and why
+type Skipped struct {
+	TranscriptPath string
+	Reason         string
+}
+
+// Result lists the unbound transcripts found by a scan
+type Result struct {
+	Candidates []Candidate
+	Skipped    []Skipped
+}
+
+// BoundSessions returns the Claude session IDs already bound to a Kamui session in store
+func BoundSessions(ctx context.Context, store storage.Interface) (map[string]bool, error) {
+	return boundSessions(ctx, store, true)
+}
+
+// boundSessions collects the bound Claude session IDs, skipping unreadable sessions or failing on them
+func boundSessions(ctx context.Context, store storage.Interface, skipUnreadable bool) (map[string]bool, error) {
+	names, err := store.ListSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bound := make(map[string]bool, len(names))
+	for _, name := range names {
+		session, err := store.LoadSession(ctx, name)
+		if err != nil {
+			if ctxErr := types.ContextError(ctx); ctxErr != nil {
+				return nil, ctxErr
+			}
+			if !skipUnreadable {
+				return nil, err
+			}
+			continue // unreadable sessions are skipped rather than failing the scan
+		}
+		if session.Claude.SessionID != "" {
+			bound[session.Claude.SessionID] = true
+		}
+	}
+	return bound, nil
+}
+
+// ScanAll scans every project directory under projectsDir (~/.claude/projects)
+func ScanAll(ctx context.Context, projectsDir string, bound map[string]bool) (Result, error) {
+	entries, err := os.ReadDir(projectsDir)
+	if os.IsNotExist(err) {
+		return Result{}, nil
+	}
+	if err != nil {
+		return Result{}, types.NewStorageError(types.ErrCodeStoragePermission, "failed to read Claude's projects directory", err)
+	}
+
+	var result Result
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		project, err := ScanProject(ctx, filepath.Join(projectsDir, entry.Name()), bound)
+		if err != nil {
+			return Result{}, err
+		}
+		result.Candidates = append(result.Candidates, project.Candidates...)
+		result.Skipped = append(result.Skipped, project.Skipped...)
+	}
+	sortCandidates(result.Candidates)
+	return result, nil
+}
+
+// ScanProject scans one of Claude's project directories for unbound transcripts
+// Transcripts without a prompt are ignored, as there is nothing to resume
+func ScanProject(ctx context.Context, dir string, bound map[string]bool) (Result, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return Result{}, nil
+	}
+	if err != nil {
+		return Result{}, types.NewStorageError(types.ErrCodeStoragePermission, "failed to read Claude's project directory", err)
+	}
+
+	// the directory name is only decoded when a transcript doesn't record where it ran
+	decoded, decodedDone := "", false
+
+	var result Result
+	for _, entry := range entries {
+		claudeSessionID, ok := strings.CutSuffix(entry.Name(), ".jsonl")
+		if !ok || entry.IsDir() || bound[claudeSessionID] {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+
+		candidate, err := readCandidate(ctx, path)
+		if err != nil {
+			if ctx.Err() != nil {
+				return Result{}, err
+			}
+			result.Skipped = append(result.Skipped, Skipped{TranscriptPath: path, Reason: err.Error()})
+			continue
+		}
+		if candidate.Prompts == 0 {
+			continue
+		}
+		candidate.ClaudeSessionID = claudeSessionID
+
+		if candidate.WorkingDir == "" {
+			if !decodedDone {
+				decoded, decodedDone = paths.DecodeClaude(filepath.Base(dir)), true
+			}
+			candidate.WorkingDir = decoded
+		}
+		if candidate.WorkingDir == "" {
+			result.Skipped = append(result.Skipped, Skipped{TranscriptPath: path, Reason: "can't tell which directory Claude ran in"})
+			continue
+		}
+		if info, statErr := os.Stat(candidate.WorkingDir); statErr != nil || !info.IsDir() {
+			result.Skipped = append(result.Skipped, Skipped{TranscriptPath: path, Reason: candidate.WorkingDir + " no longer exists"})
+			continue
+		}
+		result.Candidates = append(result.Candidates, candidate)
+	}
+	sortCandidates(result.Candidates)
+	return result, nil
+}
+
+// readCandidate summarizes a transcript, taking the working directory from its first record that has one
+func readCandidate(ctx context.Context, path string) (Candidate, error) {
+	candidate := Candidate{TranscriptPath: path}
+	summarizer := transcript.NewSummarizer(time.Time{})
+	if _, err := transcript.Scan(ctx, path, transcript.DefaultLimits, func(record *transcript.Record) error {
+		if candidate.WorkingDir == "" && record.CWD != "" {
+			candidate.WorkingDir = record.CWD
+		}
+		summarizer.Add(record)
+		return nil
+	}); err != nil {
+		return Candidate{}, err
+	}
+
+	summary := summarizer.Summary()
+	candidate.FirstPrompt = summary.FirstPrompt
+	candidate.Prompts = summary.Prompts
+	candidate.Start = summary.Start
+	candidate.End = summary.End
+	return candidate, nil
+}
+
+// sortCandidates orders candidates by directory, oldest conversation first
+func sortCandidates(candidates []Candidate) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].WorkingDir != candidates[j].WorkingDir {
+			return candidates[i].WorkingDir < candidates[j].WorkingDir
+		}
+		return candidates[i].Start.Before(candidates[j].Start)
+	})
+}
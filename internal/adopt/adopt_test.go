@@ -0,0 +1,104 @@
+package adopt
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/paths"
+	"github.com/bitomule/kamui/internal/storage"
+)
+
+// writeTranscript writes a transcript of one prompt for a conversation Claude ran in cwd
+func writeTranscript(t *testing.T, dir, claudeSessionID, cwd, prompt, timestamp string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	line := fmt.Sprintf(`{"type":"user","sessionId":%q,"timestamp":%q,"cwd":%q,"message":{"role":"user","content":%q}}`+"\n",
+		claudeSessionID, timestamp, cwd, prompt)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, claudeSessionID+".jsonl"), []byte(line), 0o600))
+}
+
+func TestScanAll(t *testing.T) {
+	root := paths.Canonical(t.TempDir())
+	projectsDir := filepath.Join(root, "projects")
+	api := filepath.Join(root, "work", "api")
+	web := filepath.Join(root, "work", "web.app")
+	require.NoError(t, os.MkdirAll(api, 0o755))
+	require.NoError(t, os.MkdirAll(web, 0o755))
+
+	apiDir := filepath.Join(projectsDir, paths.EncodeClaude(api))
+	writeTranscript(t, apiDir, "c-late", api, "Add rate limiting", "2025-03-11T09:00:00Z")
+	writeTranscript(t, apiDir, "c-early", api, "Fix the login bug", "2025-03-10T09:00:00Z")
+	writeTranscript(t, apiDir, "c-bound", api, "Already a session", "2025-03-09T09:00:00Z")
+	writeTranscript(t, filepath.Join(projectsDir, paths.EncodeClaude(web)), "c-web", web, "Style the header", "2025-03-12T09:00:00Z")
+	writeTranscript(t, filepath.Join(projectsDir, "-gone"), "c-gone", filepath.Join(root, "gone"), "Old work", "2025-01-01T09:00:00Z")
+	require.NoError(t, os.WriteFile(filepath.Join(apiDir, "c-empty.jsonl"), []byte(`{"type":"summary","summary":"nothing"}`+"\n"), 0o600))
+
+	result, err := ScanAll(context.Background(), projectsDir, map[string]bool{"c-bound": true})
+	require.NoError(t, err)
+
+	var ids []string
+	for _, candidate := range result.Candidates {
+		ids = append(ids, candidate.ClaudeSessionID)
+	}
+	assert.Equal(t, []string{"c-early", "c-late", "c-web"}, ids, "by directory, oldest first; bound and empty transcripts left out")
+	assert.Equal(t, api, result.Candidates[0].WorkingDir)
+	assert.Equal(t, "Fix the login bug", result.Candidates[0].FirstPrompt)
+	assert.Equal(t, 1, result.Candidates[0].Prompts)
+	assert.Equal(t, web, result.Candidates[2].WorkingDir)
+
+	require.Len(t, result.Skipped, 1)
+	assert.Contains(t, result.Skipped[0].Reason, "no longer exists")
+}
+
+func TestScanAll_MissingProjectsDir(t *testing.T) {
+	result, err := ScanAll(context.Background(), filepath.Join(t.TempDir(), "projects"), nil)
+	require.NoError(t, err)
+	assert.Empty(t, result.Candidates)
+}
+
+func TestScanProject_DecodesDirectoryWithoutCWD(t *testing.T) {
+	root := paths.Canonical(t.TempDir())
+	project := filepath.Join(root, "my-app")
+	require.NoError(t, os.MkdirAll(project, 0o755))
+
+	dir := filepath.Join(root, "projects", paths.EncodeClaude(project))
+	writeTranscript(t, dir, "c-1", "", "Hello", "2025-03-10T09:00:00Z")
+
+	result, err := ScanProject(context.Background(), dir, nil)
+	require.NoError(t, err)
+	require.Len(t, result.Candidates, 1)
+	assert.Equal(t, project, result.Candidates[0].WorkingDir)
+}
+
+func TestScanProject_CanceledContext(t *testing.T) {
+	dir := t.TempDir()
+	writeTranscript(t, dir, "c-1", dir, "Hello", "2025-03-10T09:00:00Z")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := ScanProject(ctx, dir, nil)
+	require.Error(t, err)
+}
+
+func TestBoundSessions(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemory(t.TempDir())
+
+	bound, err := store.CreateSession("api", store.GetProjectPath())
+	require.NoError(t, err)
+	bound.Claude.SessionID = "c-1"
+	require.NoError(t, store.SaveSession(ctx, bound))
+	unbound, err := store.CreateSession("web", store.GetProjectPath())
+	require.NoError(t, err)
+	require.NoError(t, store.SaveSession(ctx, unbound))
+
+	ids, err := BoundSessions(ctx, store)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"c-1": true}, ids)
+}
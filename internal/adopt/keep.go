@@ -0,0 +1,101 @@
+package adopt
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// KeepList persists the Claude conversations kept out of garbage collection although no session
+// is bound to them, as a JSON list of conversation IDs
+type KeepList struct {
+	path string
+}
+
+// NewKeepList creates a keep list backed by ~/.kamui/kept-transcripts.json
+func NewKeepList() *KeepList {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+	return NewKeepListWithPath(filepath.Join(homeDir, ".kamui", "kept-transcripts.json"))
+}
+
+// NewKeepListWithPath creates a keep list backed by the given file
+func NewKeepListWithPath(path string) *KeepList {
+	return &KeepList{path: path}
+}
+
+// IDs returns the kept conversation IDs
+func (k *KeepList) IDs() (map[string]bool, error) {
+	data, err := os.ReadFile(k.path)
+	if os.IsNotExist(err) {
+		return map[string]bool{}, nil
+	}
+	if err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStoragePermission, "failed to read kept transcripts file", err)
+	}
+
+	var ids []string
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStorageCorrupted, "failed to parse kept transcripts file", err)
+	}
+	kept := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		kept[id] = true
+	}
+	return kept, nil
+}
+
+// Add keeps a conversation
+func (k *KeepList) Add(claudeSessionID string) error {
+	kept, err := k.IDs()
+	if err != nil {
+		return err
+	}
+	kept[claudeSessionID] = true
+	return k.save(kept)
+}
+
+// Remove stops keeping a conversation, reporting whether it was kept
+func (k *KeepList) Remove(claudeSessionID string) (bool, error) {
+	kept, err := k.IDs()
+	if err != nil {
+		return false, err
+	}
+	if !kept[claudeSessionID] {
+		return false, nil
+	}
+	delete(kept, claudeSessionID)
+	return true, k.save(kept)
+}
+
+// save writes the list atomically, sorted
+func (k *KeepList) save(kept map[string]bool) error {
+	ids := make([]string, 0, len(kept))
+	for id := range kept {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	if err := os.MkdirAll(filepath.Dir(k.path), 0o700); err != nil {
+		return types.NewStorageError(types.ErrCodeStoragePermission, "failed to create kept transcripts directory", err)
+	}
+	data, err := json.MarshalIndent(ids, "", "  ")
+	if err != nil {
+		return types.NewStorageError(types.ErrCodeStorageCorrupted, "failed to marshal kept transcripts", err)
+	}
+
+	tempFile := k.path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0o600); err != nil {
+		return types.NewStorageError(types.ErrCodeStoragePermission, "failed to write kept transcripts file", err)
+	}
+	if err := os.Rename(tempFile, k.path); err != nil {
+		os.Remove(tempFile) // cleanup temp file
+		return types.NewStorageError(types.ErrCodeStoragePermission, "failed to save kept transcripts file", err)
+	}
+	return nil
+}
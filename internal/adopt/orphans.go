@@ -0,0 +1,79 @@
+package adopt
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Orphan is a transcript no session is bound to, a candidate for garbage collection
+type Orphan struct {
+	ClaudeSessionID string
+	Path            string
+	Size            int64
+	ModTime         time.Time
+}
+
+// ReferencedSessions returns the Claude session IDs bound to a session in store; unlike
+// BoundSessions it fails on an unreadable session, whose transcript must not be mistaken for an orphan
+func ReferencedSessions(ctx context.Context, store storage.Interface) (map[string]bool, error) {
+	return boundSessions(ctx, store, false)
+}
+
+// Orphans lists the transcripts in one of Claude's project directories that no session is bound to,
+// that aren't kept and that were last written before cutoff, oldest first
+func Orphans(ctx context.Context, dir string, bound, kept map[string]bool, cutoff time.Time) ([]Orphan, error) {
+	if err := types.ContextError(ctx); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStoragePermission, "failed to read Claude's project directory", err)
+	}
+
+	var orphans []Orphan
+	for _, entry := range entries {
+		claudeSessionID, ok := strings.CutSuffix(entry.Name(), ".jsonl")
+		if !ok || entry.IsDir() || bound[claudeSessionID] || kept[claudeSessionID] {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		orphans = append(orphans, Orphan{
+			ClaudeSessionID: claudeSessionID,
+			Path:            filepath.Join(dir, entry.Name()),
+			Size:            info.Size(),
+			ModTime:         info.ModTime(),
+		})
+	}
+	sort.SliceStable(orphans, func(i, j int) bool {
+		return orphans[i].ModTime.Before(orphans[j].ModTime)
+	})
+	return orphans, nil
+}
+
+// Remove deletes the orphan's transcript along with the directory Claude keeps next to it for the
+// conversation, if any
+func (o Orphan) Remove() error {
+	if err := os.Remove(o.Path); err != nil && !os.IsNotExist(err) {
+		return types.NewStorageError(types.ErrCodeStoragePermission, "failed to delete transcript", err)
+	}
+	companion := filepath.Join(filepath.Dir(o.Path), o.ClaudeSessionID)
+	if info, err := os.Stat(companion); err == nil && info.IsDir() {
+		if err := os.RemoveAll(companion); err != nil {
+			return types.NewStorageError(types.ErrCodeStoragePermission, "failed to delete transcript directory", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package adopt
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/storage"
+)
+
+func TestReferencedSessions(t *testing.T) {
+	ctx := context.Background()
+	sessionsDir := t.TempDir()
+	store := storage.NewWithSessionsDir(t.TempDir(), sessionsDir)
+
+	bound, err := store.CreateSession("api", store.GetProjectPath())
+	require.NoError(t, err)
+	bound.Claude.SessionID = "c-1"
+	require.NoError(t, store.SaveSession(ctx, bound))
+
+	ids, err := ReferencedSessions(ctx, store)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"c-1": true}, ids)
+
+	// a session that can't be read might be bound to any transcript
+	require.NoError(t, os.WriteFile(filepath.Join(sessionsDir, "web.json"), []byte("{"), 0o600))
+	_, err = ReferencedSessions(ctx, store)
+	assert.Error(t, err)
+
+	ids, err = BoundSessions(ctx, store)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"c-1": true}, ids)
+}
+
+func TestOrphans(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	for id, age := range map[string]time.Duration{
+		"c-old":    60 * 24 * time.Hour,
+		"c-older":  90 * 24 * time.Hour,
+		"c-recent": time.Hour,
+		"c-bound":  90 * 24 * time.Hour,
+		"c-kept":   90 * 24 * time.Hour,
+	} {
+		path := filepath.Join(dir, id+".jsonl")
+		require.NoError(t, os.WriteFile(path, []byte("{}\n"), 0o600))
+		require.NoError(t, os.Chtimes(path, now.Add(-age), now.Add(-age)))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0o600))
+
+	orphans, err := Orphans(context.Background(), dir, map[string]bool{"c-bound": true}, map[string]bool{"c-kept": true}, now.AddDate(0, 0, -30))
+	require.NoError(t, err)
+	require.Len(t, orphans, 2)
+	assert.Equal(t, "c-older", orphans[0].ClaudeSessionID, "oldest first")
+	assert.Equal(t, "c-old", orphans[1].ClaudeSessionID)
+	assert.Equal(t, int64(3), orphans[1].Size)
+
+	orphans, err = Orphans(context.Background(), filepath.Join(dir, "missing"), nil, nil, now)
+	require.NoError(t, err)
+	assert.Empty(t, orphans)
+}
+
+func TestOrphanRemove(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "c-1.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("{}\n"), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "c-1", "subagents"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "c-2.jsonl"), []byte("{}\n"), 0o600))
+
+	require.NoError(t, Orphan{ClaudeSessionID: "c-1", Path: path}.Remove())
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "c-2.jsonl", entries[0].Name())
+}
+
+func TestKeepList(t *testing.T) {
+	keep := NewKeepListWithPath(filepath.Join(t.TempDir(), "kamui", "kept-transcripts.json"))
+
+	kept, err := keep.IDs()
+	require.NoError(t, err)
+	assert.Empty(t, kept)
+
+	require.NoError(t, keep.Add("c-2"))
+	require.NoError(t, keep.Add("c-1"))
+	kept, err = keep.IDs()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"c-1": true, "c-2": true}, kept)
+
+	removed, err := keep.Remove("c-2")
+	require.NoError(t, err)
+	assert.True(t, removed)
+	removed, err = keep.Remove("c-3")
+	require.NoError(t, err)
+	assert.False(t, removed)
+
+	kept, err = keep.IDs()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"c-1": true}, kept)
+}
@@ -0,0 +1,222 @@
+// Package budget checks session and project usage against cost and token budgets
+package budget
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/bitomule/kamui/internal/pricing"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// WarningRatio is the share of a budget at which usage starts to warn
+const WarningRatio = 0.8
+
+// Level is how close usage is to its budget
+type Level int
+
+const (
+	LevelOK Level = iota
+	LevelWarning
+	LevelExceeded
+)
+
+// Status compares usage against a budget
+type Status struct {
+	Budget types.Budget
+	Used   pricing.Estimate
+
+	// Ratio is the largest share of the budget used, across cost and tokens
+	Ratio float64
+	Level Level
+}
+
+// Check evaluates usage against a budget; limits left at zero are ignored
+func Check(budget types.Budget, used pricing.Estimate) Status {
+	status := Status{Budget: budget, Used: used}
+
+	if budget.CostUSD > 0 {
+		status.Ratio = used.CostUSD / budget.CostUSD
+	}
+	if budget.Tokens > 0 {
+		if ratio := float64(used.Tokens) / float64(budget.Tokens); ratio > status.Ratio {
+			status.Ratio = ratio
+		}
+	}
+
+	switch {
+	case status.Ratio >= 1:
+		status.Level = LevelExceeded
+	case status.Ratio >= WarningRatio:
+		status.Level = LevelWarning
+	}
+	return status
+}
+
+// Message describes the status of a budget for the named scope, e.g. "session 'api'"
+func (s Status) Message(scope string) string {
+	var limits []string
+	if s.Budget.CostUSD > 0 {
+		limits = append(limits, fmt.Sprintf("$%.2f of $%.2f", s.Used.CostUSD, s.Budget.CostUSD))
+	}
+	if s.Budget.Tokens > 0 {
+		limits = append(limits, fmt.Sprintf("%d of %d tokens", s.Used.Tokens, s.Budget.Tokens))
+	}
+
+	verb := "has used"
+	if s.Level == LevelExceeded {
+		verb = "is over budget:"
+	}
+	return fmt.Sprintf("%s %s %.0f%% (%s)", scope, verb, s.Ratio*100, strings.Join(limits, ", "))
+}
+
+// ParseCost parses a USD amount such as "5", "5.00" or "$5"
+func ParseCost(value string) (float64, error) {
+	amount, err := strconv.ParseFloat(strings.TrimPrefix(strings.TrimSpace(value), "$"), 64)
+	if err != nil || amount <= 0 {
+		return 0, types.NewSessionError(
+			types.ErrCodeInvalidInput,
+			fmt.Sprintf("invalid budget '%s': expected a positive USD amount such as 5.00", value),
+			err,
+		)
+	}
+	return amount, nil
+}
+
+// ParseTokens parses a token count such as "500000", "500k" or "2M"
+func ParseTokens(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(value, "k"), strings.HasSuffix(value, "K"):
+		multiplier, value = 1e3, value[:len(value)-1]
+	case strings.HasSuffix(value, "m"), strings.HasSuffix(value, "M"):
+		multiplier, value = 1e6, value[:len(value)-1]
+	}
+
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil || amount <= 0 {
+		return 0, types.NewSessionError(
+			types.ErrCodeInvalidInput,
+			fmt.Sprintf("invalid token budget '%s': expected a positive count such as 500k or 2M", value),
+			err,
+		)
+	}
+	return int64(amount * multiplier), nil
+}
+
+// Store persists project budgets in a single JSON file keyed by project path
+type Store struct {
+	path string
+}
+
+// NewStore creates a store backed by ~/.kamui/budgets.json
+func NewStore() *Store {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+	return NewStoreWithPath(filepath.Join(homeDir, ".kamui", "budgets.json"))
+}
+
+// NewStoreWithPath creates a store backed by the given file
+func NewStoreWithPath(path string) *Store {
+	return &Store{path: path}
+}
+
+// Get returns the budget for a project, or nil when it has none
+func (s *Store) Get(projectPath string) (*types.Budget, error) {
+	budgets, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	budget, ok := budgets[projectPath]
+	if !ok {
+		return nil, nil
+	}
+	return &budget, nil
+}
+
+// Set stores or, with a nil budget, removes a project's budget
+func (s *Store) Set(projectPath string, budget *types.Budget) error {
+	budgets, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	if budget == nil {
+		delete(budgets, projectPath)
+	} else {
+		budgets[projectPath] = *budget
+	}
+	return s.save(budgets)
+}
+
+func (s *Store) load() (map[string]types.Budget, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return map[string]types.Budget{}, nil
+	}
+	if err != nil {
+		return nil, types.NewStorageError(
+			types.ErrCodeStoragePermission,
+			"failed to read budgets file",
+			err,
+		)
+	}
+
+	budgets := map[string]types.Budget{}
+	if err := json.Unmarshal(data, &budgets); err != nil {
+		return nil, types.NewStorageError(
+			types.ErrCodeStorageCorrupted,
+			"failed to parse budgets file",
+			err,
+		)
+	}
+	return budgets, nil
+}
+
+// save writes all budgets atomically
+func (s *Store) save(budgets map[string]types.Budget) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return types.NewStorageError(
+			types.ErrCodeStoragePermission,
+			"failed to create budgets directory",
+			err,
+		)
+	}
+
+	data, err := json.MarshalIndent(budgets, "", "  ")
+	if err != nil {
+		return types.NewStorageError(
+			types.ErrCodeStorageCorrupted,
+			"failed to marshal budgets",
+			err,
+		)
+	}
+
+	tempFile := s.path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0o600); err != nil {
+		return types.NewStorageError(
+			types.ErrCodeStoragePermission,
+			"failed to write budgets file",
+			err,
+		)
+	}
+
+	if err := os.Rename(tempFile, s.path); err != nil {
+		os.Remove(tempFile) // cleanup temp file
+		return types.NewStorageError(
+			types.ErrCodeStoragePermission,
+			"failed to save budgets file",
+			err,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package budget
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/pricing"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		budget types.Budget
+		used   pricing.Estimate
+		level  Level
+	}{
+		{"under", types.Budget{CostUSD: 5}, pricing.Estimate{CostUSD: 1}, LevelOK},
+		{"warning", types.Budget{CostUSD: 5}, pricing.Estimate{CostUSD: 4}, LevelWarning},
+		{"exceeded", types.Budget{CostUSD: 5}, pricing.Estimate{CostUSD: 5}, LevelExceeded},
+		{"tokens drive the ratio", types.Budget{CostUSD: 5, Tokens: 1000}, pricing.Estimate{CostUSD: 1, Tokens: 900}, LevelWarning},
+		{"no limits", types.Budget{}, pricing.Estimate{CostUSD: 100, Tokens: 1e9}, LevelOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.level, Check(tt.budget, tt.used).Level)
+		})
+	}
+}
+
+func TestStatus_Message(t *testing.T) {
+	status := Check(types.Budget{CostUSD: 5}, pricing.Estimate{CostUSD: 4.10})
+	assert.Equal(t, "session 'api' has used 82% ($4.10 of $5.00)", status.Message("session 'api'"))
+
+	status = Check(types.Budget{Tokens: 1000}, pricing.Estimate{Tokens: 1500})
+	assert.Equal(t, "project is over budget: 150% (1500 of 1000 tokens)", status.Message("project"))
+}
+
+func TestParseCost(t *testing.T) {
+	amount, err := ParseCost("5.00")
+	require.NoError(t, err)
+	assert.Equal(t, 5.0, amount)
+
+	amount, err = ParseCost("$12.5")
+	require.NoError(t, err)
+	assert.Equal(t, 12.5, amount)
+
+	for _, invalid := range []string{"", "five", "0", "-1"} {
+		_, err := ParseCost(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestParseTokens(t *testing.T) {
+	tests := map[string]int64{"500000": 500000, "500k": 500000, "2M": 2000000, "1.5m": 1500000}
+	for input, expected := range tests {
+		tokens, err := ParseTokens(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, tokens, input)
+	}
+
+	_, err := ParseTokens("lots")
+	assert.Error(t, err)
+}
+
+func TestStore_SetGet(t *testing.T) {
+	store := NewStoreWithPath(filepath.Join(t.TempDir(), "budgets.json"))
+
+	budget, err := store.Get("/work/api")
+	require.NoError(t, err)
+	assert.Nil(t, budget)
+
+	require.NoError(t, store.Set("/work/api", &types.Budget{CostUSD: 20}))
+	budget, err = store.Get("/work/api")
+	require.NoError(t, err)
+	require.NotNil(t, budget)
+	assert.Equal(t, 20.0, budget.CostUSD)
+
+	require.NoError(t, store.Set("/work/api", nil))
+	budget, err = store.Get("/work/api")
+	require.NoError(t, err)
+	assert.Nil(t, budget)
+}
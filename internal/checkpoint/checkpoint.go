@@ -0,0 +1,117 @@
+// Package checkpoint commits what a session changed when Claude exits, for a durable history per session
+package checkpoint
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/bitomule/kamui/internal/git"
+	"github.com/bitomule/kamui/internal/transcript"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Checkpoint modes (session.checkpoint)
+const (
+	// ModeOff makes no checkpoints
+	ModeOff = "off"
+	// ModeCommit commits the changes to the checked out branch
+	ModeCommit = "commit"
+	// ModeBranch commits a copy of the working tree to the session's branch, leaving the checkout alone
+	ModeBranch = "branch"
+)
+
+// subjectLength caps the commit subject taken from a prompt
+const subjectLength = 72
+
+// ParseMode checks a checkpoint mode; an empty value means off
+func ParseMode(value string) (string, error) {
+	switch value {
+	case "", ModeOff:
+		return ModeOff, nil
+	case ModeCommit, ModeBranch:
+		return value, nil
+	}
+	return "", types.NewSessionError(
+		types.ErrCodeInvalidInput,
+		fmt.Sprintf("invalid checkpoint mode '%s' (use off, commit or branch)", value),
+		nil,
+	)
+}
+
+// BranchName returns the branch a session's checkpoints go to in branch mode, kamui/<session>
+func BranchName(sessionName string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '-'
+	}, sessionName)
+	return "kamui/" + strings.Trim(name, ".")
+}
+
+// Message returns the commit message for a checkpoint of a session's run: the run's first prompt
+// (or the session description) as the subject, then what the run did
+func Message(session *types.Session, summary transcript.Summary) string {
+	subject := firstLine(summary.FirstPrompt)
+	if subject == "" {
+		subject = firstLine(session.Metadata.Description)
+	}
+	if subject == "" {
+		subject = "checkpoint"
+	}
+	if runes := []rune(subject); len(runes) > subjectLength {
+		subject = string(runes[:subjectLength-3]) + "..."
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s\n\n", session.SessionID, subject)
+	fmt.Fprintf(&b, "Kamui session: %s\n", session.SessionID)
+	if session.Metadata.Description != "" {
+		fmt.Fprintf(&b, "Description: %s\n", session.Metadata.Description)
+	}
+	if session.Claude.SessionID != "" {
+		fmt.Fprintf(&b, "Claude conversation: %s\n", session.Claude.SessionID)
+	}
+	fmt.Fprintf(&b, "Prompts: %d\n", summary.Prompts)
+	if len(summary.FilesEdited) > 0 {
+		b.WriteString("Files edited by Claude:\n")
+		for _, file := range summary.FilesEdited {
+			fmt.Fprintf(&b, "- %s\n", file)
+		}
+	}
+	return b.String()
+}
+
+// Create commits the changes in the session's working tree in the given mode and returns the commit
+// It returns "" when there is nothing to commit or the session isn't in a git repository
+func Create(ctx context.Context, session *types.Session, mode string, summary transcript.Summary) (string, error) {
+	if mode == ModeOff {
+		return "", nil
+	}
+	state, err := git.CurrentState(ctx, session.Project.WorkingDirectory)
+	if err != nil || state == nil {
+		return "", err
+	}
+	changed, err := git.HasChanges(ctx, state.Root)
+	if err != nil || !changed {
+		return "", err
+	}
+
+	message := Message(session, summary)
+	if mode == ModeBranch {
+		return git.CommitToBranch(ctx, state.Root, BranchName(session.SessionID), message)
+	}
+	return git.CommitAll(ctx, state.Root, message)
+}
+
+// firstLine returns the first non-blank line of text, trimmed
+func firstLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
@@ -0,0 +1,171 @@
+package checkpoint
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/transcript"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestParseMode(t *testing.T) {
+	for value, want := range map[string]string{"": ModeOff, "off": ModeOff, "commit": ModeCommit, "branch": ModeBranch} {
+		mode, err := ParseMode(value)
+		require.NoError(t, err)
+		assert.Equal(t, want, mode)
+	}
+
+	_, err := ParseMode("always")
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeInvalidInput, agxErr.Code)
+}
+
+func TestBranchName(t *testing.T) {
+	assert.Equal(t, "kamui/api", BranchName("api"))
+	assert.Equal(t, "kamui/fix-login-bug", BranchName("fix login~bug"))
+	assert.Equal(t, "kamui/café", BranchName("café"))
+}
+
+func TestMessage(t *testing.T) {
+	session := &types.Session{SessionID: "api"}
+	session.Claude.SessionID = "c-1"
+	session.Metadata.Description = "Rework auth"
+
+	message := Message(session, transcript.Summary{
+		Prompts:     2,
+		FirstPrompt: "\n  Add token refresh\nand tests",
+		FilesEdited: []string{"/src/auth.go"},
+	})
+	assert.Equal(t, `api: Add token refresh
+
+Kamui session: api
+Description: Rework auth
+Claude conversation: c-1
+Prompts: 2
+Files edited by Claude:
+- /src/auth.go
+`, message)
+
+	// without a prompt the description names the checkpoint, and long subjects are cut
+	assert.True(t, strings.HasPrefix(Message(session, transcript.Summary{}), "api: Rework auth\n"))
+	long := Message(session, transcript.Summary{FirstPrompt: strings.Repeat("x", 100)})
+	assert.Equal(t, "api: "+strings.Repeat("x", subjectLength-3)+"...", strings.SplitN(long, "\n", 2)[0])
+	assert.True(t, strings.HasPrefix(Message(&types.Session{SessionID: "web"}, transcript.Summary{}), "web: checkpoint\n"))
+}
+
+func TestCreate(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	ctx := context.Background()
+	for _, name := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(name, "Ada")
+	}
+	for _, name := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(name, "ada@example.com")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+		return strings.TrimSpace(string(output))
+	}
+	git("init", "-q", "-b", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one\n"), 0o644))
+	git("add", ".")
+	git("commit", "-q", "-m", "first")
+
+	session := &types.Session{SessionID: "api"}
+	session.Project.WorkingDirectory = dir
+
+	commit, err := Create(ctx, session, ModeCommit, transcript.Summary{})
+	require.NoError(t, err)
+	assert.Empty(t, commit, "a clean tree needs no checkpoint")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("two\n"), 0o644))
+	commit, err = Create(ctx, session, ModeBranch, transcript.Summary{FirstPrompt: "Change a"})
+	require.NoError(t, err)
+	assert.Equal(t, commit, git("rev-parse", "kamui/api"))
+	assert.Equal(t, "main", git("symbolic-ref", "--short", "HEAD"))
+	assert.Equal(t, "M a.txt", git("status", "--porcelain"))
+
+	commit, err = Create(ctx, session, ModeCommit, transcript.Summary{FirstPrompt: "Change a"})
+	require.NoError(t, err)
+	assert.Equal(t, commit, git("rev-parse", "HEAD"))
+	assert.Equal(t, "api: Change a", git("log", "-1", "--format=%s"))
+
+	commit, err = Create(ctx, session, ModeOff, transcript.Summary{})
+	require.NoError(t, err)
+	assert.Empty(t, commit)
+}
+
+func TestLeftovers(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	ctx := context.Background()
+	for _, name := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(name, "Ada")
+	}
+	for _, name := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(name, "ada@example.com")
+	}
+
+	base := t.TempDir()
+	dir := filepath.Join(base, "repo")
+	require.NoError(t, os.Mkdir(dir, 0o755))
+	git := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+		return strings.TrimSpace(string(output))
+	}
+	git("init", "-q", "-b", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one\n"), 0o644))
+	git("add", ".")
+	git("commit", "-q", "-m", "first")
+	repo, err := filepath.EvalSymlinks(dir)
+	require.NoError(t, err)
+
+	session := &types.Session{SessionID: "api"}
+	session.Project.WorkingDirectory = dir
+	leftovers, err := FindLeftovers(ctx, session)
+	require.NoError(t, err)
+	assert.Nil(t, leftovers, "the main worktree without a checkpoint branch leaves nothing")
+
+	// a session in its own worktree, with an unmerged checkpoint branch
+	worktree := filepath.Join(base, "api")
+	git("worktree", "add", "-q", "-b", "feature", worktree)
+	session.Project.WorkingDirectory = worktree
+	require.NoError(t, os.WriteFile(filepath.Join(worktree, "a.txt"), []byte("two\n"), 0o644))
+	_, err = Create(ctx, session, ModeBranch, transcript.Summary{})
+	require.NoError(t, err)
+	git("-C", worktree, "checkout", "-q", "a.txt")
+
+	leftovers, err = FindLeftovers(ctx, session)
+	require.NoError(t, err)
+	require.NotNil(t, leftovers)
+	worktreeRoot, err := filepath.EvalSymlinks(worktree)
+	require.NoError(t, err)
+	assert.Equal(t, repo, leftovers.Repo)
+	assert.Equal(t, worktreeRoot, leftovers.Worktree)
+	assert.Equal(t, []Branch{{Name: "kamui/api", Merged: false}, {Name: "feature", Merged: true}}, leftovers.Branches)
+
+	deleted, err := leftovers.Remove(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"feature"}, deleted)
+	assert.NoDirExists(t, worktree)
+	assert.Equal(t, "kamui/api\n* main", git("branch", "--list"))
+}
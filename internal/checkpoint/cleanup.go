@@ -0,0 +1,85 @@
+package checkpoint
+
+import (
+	"context"
+
+	"github.com/bitomule/kamui/internal/git"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Branch is a branch dedicated to a session
+type Branch struct {
+	Name string
+	// Merged is set when the main working tree's HEAD already contains the branch
+	Merged bool
+}
+
+// Leftovers are the git worktree and branches a session leaves in its repository
+type Leftovers struct {
+	// Repo is the repository's main working tree
+	Repo string
+	// Worktree is the linked worktree the session works in, empty when it uses the main one
+	Worktree string
+	// Branches are the session's checkpoint branch and the branch checked out in its worktree
+	Branches []Branch
+}
+
+// FindLeftovers returns what the session leaves in its repository, or nil when there is nothing:
+// the session isn't in a git repository, doesn't work in a linked worktree and has no checkpoint branch
+func FindLeftovers(ctx context.Context, session *types.Session) (*Leftovers, error) {
+	state, err := git.CurrentState(ctx, session.Project.WorkingDirectory)
+	if err != nil || state == nil {
+		return nil, err
+	}
+	repo, err := git.MainWorktree(ctx, state.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	leftovers := &Leftovers{Repo: repo}
+	candidates := []string{BranchName(session.SessionID)}
+	if repo == "" {
+		leftovers.Repo = state.Root
+	} else {
+		leftovers.Worktree = state.Root
+		if state.Branch != "" && state.Branch != candidates[0] {
+			candidates = append(candidates, state.Branch)
+		}
+	}
+
+	for _, name := range candidates {
+		exists, merged, err := git.BranchMerged(ctx, leftovers.Repo, name)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			leftovers.Branches = append(leftovers.Branches, Branch{Name: name, Merged: merged})
+		}
+	}
+	if leftovers.Worktree == "" && len(leftovers.Branches) == 0 {
+		return nil, nil
+	}
+	return leftovers, nil
+}
+
+// Remove removes the worktree, then deletes the merged branches and returns their names;
+// unmerged branches are kept
+func (l *Leftovers) Remove(ctx context.Context) ([]string, error) {
+	if l.Worktree != "" {
+		if err := git.RemoveWorktree(ctx, l.Repo, l.Worktree); err != nil {
+			return nil, err
+		}
+	}
+
+	var deleted []string
+	for _, branch := range l.Branches {
+		if !branch.Merged {
+			continue
+		}
+		if err := git.DeleteBranch(ctx, l.Repo, branch.Name); err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, branch.Name)
+	}
+	return deleted, nil
+}
@@ -2,19 +2,37 @@
 package claude
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
-	"time"
+	"sync"
 
+	"github.com/bitomule/kamui/internal/monitor"
+	"github.com/bitomule/kamui/internal/paths"
+	"github.com/bitomule/kamui/internal/record"
+	"github.com/bitomule/kamui/internal/trace"
 	"github.com/bitomule/kamui/pkg/types"
 )
 
 // Client manages Claude Code operations
 type Client struct {
 	claudePath string
+	// unavailable says why an offline client can't run claude; empty when it can
+	unavailable string
+
+	// versionCachePath records the detected version between runs; empty disables the cache
+	versionCachePath string
+	versionOnce      sync.Once
+	version          Version
+
+	// monitorStdin keeps the monitors' stdin open; they exit when it closes
+	monitorStdin []io.WriteCloser
 }
 
 // New creates a new Claude client
@@ -30,25 +48,69 @@ func New() (*Client, error) {
 	}
 
 	return &Client{
-		claudePath: claudePath,
+		claudePath:       claudePath,
+		versionCachePath: DefaultVersionCachePath(),
 	}, nil
 }
 
-// HasSession checks if a Claude session exists by ID for the given working directory
-func (c *Client) HasSession(sessionID, workingDir string) (bool, error) {
-	if sessionID == "" {
-		return false, nil
-	}
+// NewOffline creates a client that never runs claude, for working with sessions when it isn't
+// installed or --offline is set; reason says why, for the errors of everything that needs it
+// Transcripts are still found on disk, so binding and adopting conversations keep working
+func NewOffline(reason string) *Client {
+	return &Client{unavailable: reason}
+}
 
-	// Use the same path resolution as other methods to handle symlinks
-	canonicalPath, err := filepath.EvalSymlinks(workingDir)
+// NewOrOffline creates a client for the claude in PATH, or an offline one when there is none
+func NewOrOffline() *Client {
+	client, err := New()
 	if err != nil {
-		// If we can't resolve symlinks, use the original path
-		canonicalPath = workingDir
+		return NewOffline("claude not found in PATH")
+	}
+	return client
+}
+
+// Offline reports whether the client can't run claude
+func (c *Client) Offline() bool {
+	return c.unavailable != ""
+}
+
+// requireBinary fails for offline clients, naming what needed claude
+func (c *Client) requireBinary(action string) error {
+	if c.unavailable == "" {
+		return nil
 	}
+	return types.NewClaudeError(
+		types.ErrCodeClaudeNotFound,
+		fmt.Sprintf("can't %s: %s", action, c.unavailable),
+		nil,
+	)
+}
+
+// Version returns the installed Claude Code version, detected once per client
+// It is zero when the version couldn't be determined, and for offline clients
+func (c *Client) Version(ctx context.Context) Version {
+	if c.Offline() {
+		return Version{}
+	}
+	c.versionOnce.Do(func() {
+		c.version, _ = DetectVersion(ctx, c.claudePath, c.versionCachePath)
+	})
+	return c.version
+}
+
+// Require returns an error when the installed Claude Code lacks the feature
+func (c *Client) Require(ctx context.Context, feature Feature) error {
+	return Check(c.Version(ctx), feature)
+}
 
-	// Encode the path like Claude does (replace / with -)
-	encodedPath := strings.ReplaceAll(canonicalPath, "/", "-")
+// HasSession checks if a Claude session exists by ID for the given working directory
+func (c *Client) HasSession(ctx context.Context, sessionID, workingDir string) (bool, error) {
+	if err := types.ContextError(ctx); err != nil {
+		return false, err
+	}
+	if sessionID == "" {
+		return false, nil
+	}
 
 	// Check if session file exists in ~/.claude/projects/[encoded-path]/
 	homeDir, err := os.UserHomeDir()
@@ -56,14 +118,14 @@ func (c *Client) HasSession(sessionID, workingDir string) (bool, error) {
 		return false, err
 	}
 
-	sessionFile := filepath.Join(homeDir, ".claude", "projects", encodedPath, sessionID+".jsonl")
+	sessionFile := filepath.Join(paths.ClaudeProjectDir(homeDir, workingDir), sessionID+".jsonl")
 	_, err = os.Stat(sessionFile)
 
 	return err == nil, nil
 }
 
 // StartSession creates a fresh Claude session
-func (c *Client) StartSession(_ string) (string, error) {
+func (c *Client) StartSession(_ context.Context, _ string) (string, error) {
 	// For AGX, we want each session to have its own Claude session
 	// Don't reuse existing Claude sessions - let each AGX session be independent
 	fmt.Printf("Kamui: Will start fresh Claude session\n")
@@ -73,9 +135,9 @@ func (c *Client) StartSession(_ string) (string, error) {
 }
 
 // ResumeSession resumes an existing Claude session
-func (c *Client) ResumeSession(sessionID, workingDir string) error {
+func (c *Client) ResumeSession(ctx context.Context, sessionID, workingDir string) error {
 	// Check if session exists
-	exists, err := c.HasSession(sessionID, workingDir)
+	exists, err := c.HasSession(ctx, sessionID, workingDir)
 	if err != nil {
 		return err
 	}
@@ -95,10 +157,16 @@ func (c *Client) ResumeSession(sessionID, workingDir string) error {
 }
 
 // ListSessions returns a list of all Claude sessions
-func (c *Client) ListSessions() ([]string, error) {
-	cmd := exec.Command(c.claudePath, "sessions", "list")
-	output, err := cmd.Output()
+func (c *Client) ListSessions(ctx context.Context) ([]string, error) {
+	if err := c.requireBinary("list Claude sessions"); err != nil {
+		return nil, err
+	}
+	cmd := exec.CommandContext(ctx, c.claudePath, "sessions", "list")
+	output, err := trace.Output(cmd)
 	if err != nil {
+		if ctxErr := types.ContextError(ctx); ctxErr != nil {
+			return nil, ctxErr
+		}
 		// If no sessions exist, claude may return exit code 1
 		if exitError, ok := err.(*exec.ExitError); ok {
 			if exitError.ExitCode() == 1 {
@@ -123,9 +191,9 @@ func (c *Client) ListSessions() ([]string, error) {
 }
 
 // GetSessionInfo returns information about a Claude session
-func (c *Client) GetSessionInfo(sessionID, workingDir string) (*SessionInfo, error) {
+func (c *Client) GetSessionInfo(ctx context.Context, sessionID, workingDir string) (*SessionInfo, error) {
 	// Check if session exists
-	exists, err := c.HasSession(sessionID, workingDir)
+	exists, err := c.HasSession(ctx, sessionID, workingDir)
 	if err != nil {
 		return nil, err
 	}
@@ -138,9 +206,13 @@ func (c *Client) GetSessionInfo(sessionID, workingDir string) (*SessionInfo, err
 		)
 	}
 
+	if err := c.requireBinary("get Claude session info"); err != nil {
+		return nil, err
+	}
+
 	// Get session information (just verify it exists)
-	cmd := exec.Command(c.claudePath, "sessions", "info", sessionID)
-	_, err = cmd.Output()
+	cmd := exec.CommandContext(ctx, c.claudePath, "sessions", "info", sessionID)
+	_, err = trace.Output(cmd)
 	if err != nil {
 		return nil, types.NewClaudeError(
 			types.ErrCodeClaudeCommandFailed,
@@ -160,10 +232,10 @@ func (c *Client) GetSessionInfo(sessionID, workingDir string) (*SessionInfo, err
 	return info, nil
 }
 
-// TerminateSession terminates a Claude session
-func (c *Client) TerminateSession(sessionID, workingDir string) error {
-	// Check if session exists
-	exists, err := c.HasSession(sessionID, workingDir)
+// TerminateSession deletes a Claude session's transcript from ~/.claude/projects, so it can no
+// longer be resumed; it needs no claude binary
+func (c *Client) TerminateSession(ctx context.Context, sessionID, workingDir string) error {
+	exists, err := c.HasSession(ctx, sessionID, workingDir)
 	if err != nil {
 		return err
 	}
@@ -176,12 +248,15 @@ func (c *Client) TerminateSession(sessionID, workingDir string) error {
 		)
 	}
 
-	// Terminate session
-	cmd := exec.Command(c.claudePath, "sessions", "terminate", sessionID)
-	if err := cmd.Run(); err != nil {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	sessionFile := filepath.Join(paths.ClaudeProjectDir(homeDir, workingDir), sessionID+".jsonl")
+	if err := os.Remove(sessionFile); err != nil {
 		return types.NewClaudeError(
 			types.ErrCodeClaudeCommandFailed,
-			fmt.Sprintf("failed to terminate Claude session '%s'", sessionID),
+			fmt.Sprintf("failed to delete Claude session '%s'", sessionID),
 			err,
 		)
 	}
@@ -207,49 +282,28 @@ type Message struct {
 }
 
 // DiscoverExistingSessions finds existing Claude sessions for the current directory
-func (c *Client) DiscoverExistingSessions(workingDir string) ([]string, error) {
-	// Resolve canonical path to handle symlinks like /tmp -> /private/tmp
-	canonicalPath, err := filepath.EvalSymlinks(workingDir)
-	if err != nil {
-		// If we can't resolve symlinks, use the original path
-		canonicalPath = workingDir
+func (c *Client) DiscoverExistingSessions(ctx context.Context, workingDir string) ([]string, error) {
+	if err := types.ContextError(ctx); err != nil {
+		return nil, err
 	}
 
-	// Encode the path like Claude does (replace / with -)
-	encodedPath := strings.ReplaceAll(canonicalPath, "/", "-")
-
 	// Check if project directory exists in ~/.claude/projects/
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return nil, err
 	}
 
-	projectDir := filepath.Join(homeDir, ".claude", "projects", encodedPath)
+	projectDir := paths.ClaudeProjectDir(homeDir, workingDir)
 	if _, statErr := os.Stat(projectDir); os.IsNotExist(statErr) {
 		return []string{}, nil // No sessions for this project
 	}
 
-	// Read all .jsonl files in the project directory
-	entries, err := os.ReadDir(projectDir)
-	if err != nil {
-		return nil, err
-	}
-
-	var sessionIDs []string
-	for _, entry := range entries {
-		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".jsonl" {
-			// Remove .jsonl extension to get session ID
-			sessionID := entry.Name()[:len(entry.Name())-6]
-			sessionIDs = append(sessionIDs, sessionID)
-		}
-	}
-
-	return sessionIDs, nil
+	return transcriptIDs(projectDir)
 }
 
 // DiscoverNewestSession finds the newest Claude session (most recently created)
-func (c *Client) DiscoverNewestSession(workingDir string) (string, error) {
-	sessions, err := c.DiscoverExistingSessions(workingDir)
+func (c *Client) DiscoverNewestSession(ctx context.Context, workingDir string) (string, error) {
+	sessions, err := c.DiscoverExistingSessions(ctx, workingDir)
 	if err != nil {
 		return "", err
 	}
@@ -264,23 +318,27 @@ func (c *Client) DiscoverNewestSession(workingDir string) (string, error) {
 }
 
 // LaunchClaudeInteractively spawns a monitor subprocess and runs Claude in main process
-func (c *Client) LaunchClaudeInteractively(workingDir string, sessionName string) error {
-	// Spawn monitor subprocess first
-	monitorCmd, err := c.spawnMonitorProcess(sessionName, workingDir)
-	if err != nil {
-		return fmt.Errorf("failed to spawn monitor process: %w", err)
+func (c *Client) LaunchClaudeInteractively(ctx context.Context, workingDir string, sessionName string, args ...string) error {
+	if err := types.ContextError(ctx); err != nil {
+		return err
 	}
 
-	// Set up cleanup timer for monitor process (1 minute timeout)
-	go func() {
-		time.Sleep(1 * time.Minute)
-		if monitorCmd.Process != nil {
-			_ = monitorCmd.Process.Kill() // Kill errors are not actionable in cleanup
+	if err := c.requireBinary("launch Claude"); err != nil {
+		return err
+	}
+	if err := CheckArgs(c.Version(ctx), args); err != nil {
+		return err
+	}
+
+	// Spawn monitor subprocess first, unless Kamui chose the conversation ID itself
+	if !slices.Contains(args, FeatureSessionID.Flag) {
+		if err := c.spawnMonitorProcess(sessionName, workingDir); err != nil {
+			return fmt.Errorf("failed to spawn monitor process: %w", err)
 		}
-	}()
+	}
 
 	// Run Claude in main process (blocking with full terminal access)
-	cmd := exec.Command(c.claudePath)
+	cmd := exec.Command(c.claudePath, args...)
 	cmd.Dir = workingDir
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
@@ -294,7 +352,7 @@ func (c *Client) LaunchClaudeInteractively(workingDir string, sessionName string
 	cmd.Env = env
 
 	// This blocks until Claude exits - main process handles user interaction
-	if err := cmd.Run(); err != nil {
+	if err := record.Run(cmd, sessionName); err != nil {
 		return types.NewClaudeError(
 			types.ErrCodeClaudeStartFailed,
 			"Claude session ended with error",
@@ -306,23 +364,99 @@ func (c *Client) LaunchClaudeInteractively(workingDir string, sessionName string
 }
 
 // spawnMonitorProcess starts the monitor subprocess
-func (c *Client) spawnMonitorProcess(sessionName, workingDir string) (*exec.Cmd, error) {
+// The monitor exits once it has bound the session or when its stdin closes, which happens when
+// this process exits
+func (c *Client) spawnMonitorProcess(sessionName, workingDir string) error {
+	// A monitor still running for the session will bind it; don't start a second one
+	if running, _ := monitor.NewRegistry().Get(sessionName); running != nil && running.Status() == monitor.StatusRunning {
+		return nil
+	}
+
 	// Get path to current executable
 	executable, err := os.Executable()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	// Spawn monitor subprocess with no stdio (truly background)
+	// Spawn monitor subprocess without output (truly background)
 	cmd := exec.Command(executable, "monitor", sessionName, workingDir)
 	cmd.Dir = workingDir
-	// Don't attach stdin/stdout/stderr - runs in background
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := trace.Start(cmd); err != nil {
+		return err
+	}
 
-	if err := cmd.Start(); err != nil {
+	// Hold the pipe open for the rest of this process's life
+	c.monitorStdin = append(c.monitorStdin, stdin)
+	return nil
+}
+
+// HeadlessResult contains the outcome of a non-interactive Claude run
+type HeadlessResult struct {
+	SessionID  string  `json:"session_id"`
+	Result     string  `json:"result"`
+	IsError    bool    `json:"is_error"`
+	DurationMS int64   `json:"duration_ms"`
+	CostUSD    float64 `json:"total_cost_usd"`
+}
+
+// RunHeadless runs a single prompt non-interactively, resuming sessionID when set, with extra
+// Claude arguments such as the permission mode
+func (c *Client) RunHeadless(ctx context.Context, workingDir, sessionID, prompt string, extraArgs ...string) (*HeadlessResult, error) {
+	if err := c.requireBinary("run Claude"); err != nil {
+		return nil, err
+	}
+	args := []string{"-p", prompt, "--output-format", "json"}
+	if sessionID != "" {
+		args = append(args, "--resume", sessionID)
+	}
+	args = append(args, extraArgs...)
+	if err := CheckArgs(c.Version(ctx), args); err != nil {
 		return nil, err
 	}
 
-	return cmd, nil
+	cmd := exec.CommandContext(ctx, c.claudePath, args...)
+	cmd.Dir = workingDir
+	cmd.Stderr = os.Stderr
+
+	output, err := trace.Output(cmd)
+	if err != nil {
+		if ctxErr := types.ContextError(ctx); ctxErr != nil {
+			return nil, ctxErr
+		}
+		// Claude still reports a JSON result for failed runs when it can
+		if result, parseErr := parseHeadlessOutput(output); parseErr == nil {
+			result.IsError = true
+			return result, nil
+		}
+		return nil, types.NewClaudeError(
+			types.ErrCodeClaudeCommandFailed,
+			"headless Claude run failed",
+			err,
+		)
+	}
+
+	result, err := parseHeadlessOutput(output)
+	if err != nil {
+		return nil, types.NewClaudeError(
+			types.ErrCodeClaudeCommandFailed,
+			"failed to parse headless Claude output",
+			err,
+		)
+	}
+
+	return result, nil
 }
 
-// monitorForSession monitors filesystem for new Claude sessions
+// parseHeadlessOutput decodes the JSON document printed by `claude -p --output-format json`
+func parseHeadlessOutput(output []byte) (*HeadlessResult, error) {
+	var result HeadlessResult
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
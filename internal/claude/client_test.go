@@ -1,6 +1,7 @@
 package claude
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
@@ -29,10 +30,40 @@ func TestNew(t *testing.T) {
 	// Note: This won't run in CI as expected since we don't install claude there
 }
 
+func TestNewOffline(t *testing.T) {
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+	ctx := context.Background()
+
+	client := NewOffline("offline for the test")
+	assert.True(t, client.Offline())
+	assert.True(t, client.Version(ctx).IsZero())
+
+	// transcripts are still found on disk
+	workingDir := "/tmp/test-project"
+	sessionDir := filepath.Join(tempHome, ".claude", "projects", strings.ReplaceAll(workingDir, "/", "-"))
+	require.NoError(t, os.MkdirAll(sessionDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(sessionDir, "abc.jsonl"), []byte("{}\n"), 0o600))
+	exists, err := client.HasSession(ctx, "abc", workingDir)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	// running claude isn't
+	err = client.LaunchClaudeInteractively(ctx, workingDir, "demo")
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeClaudeNotFound, agxErr.Code)
+	assert.Equal(t, "can't launch Claude: offline for the test", agxErr.Message)
+
+	_, err = client.RunHeadless(ctx, workingDir, "", "hello")
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeClaudeNotFound, agxErr.Code)
+}
+
 func TestHasSession_EmptySessionID(t *testing.T) {
 	client := &Client{claudePath: "/mock/claude"}
 
-	exists, err := client.HasSession("", "/tmp/project")
+	exists, err := client.HasSession(context.Background(), "", "/tmp/project")
 	require.NoError(t, err)
 	assert.False(t, exists)
 }
@@ -60,7 +91,7 @@ func TestHasSession_WithSessionFile(t *testing.T) {
 	client := &Client{claudePath: "/mock/claude"}
 
 	// Session should not exist initially
-	exists, err := client.HasSession(sessionID, workingDir)
+	exists, err := client.HasSession(context.Background(), sessionID, workingDir)
 	require.NoError(t, err)
 	assert.False(t, exists)
 
@@ -69,7 +100,7 @@ func TestHasSession_WithSessionFile(t *testing.T) {
 	require.NoError(t, os.WriteFile(sessionFile, []byte(`{"test": "data"}`), 0o644))
 
 	// Session should exist now
-	exists, err = client.HasSession(sessionID, workingDir)
+	exists, err = client.HasSession(context.Background(), sessionID, workingDir)
 	require.NoError(t, err)
 	assert.True(t, exists)
 }
@@ -80,15 +111,36 @@ func TestHasSession_HomeDirectoryError(t *testing.T) {
 
 	client := &Client{claudePath: "/mock/claude"}
 
-	_, err := client.HasSession("session-123", "/tmp/project")
+	_, err := client.HasSession(context.Background(), "session-123", "/tmp/project")
 	require.Error(t, err)
 	// The specific error type depends on OS, so we just verify an error occurred
 }
 
+func TestTerminateSession_DeletesTranscript(t *testing.T) {
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+
+	workingDir := "/tmp/test-project"
+	sessionDir := filepath.Join(tempHome, ".claude", "projects", strings.ReplaceAll(workingDir, "/", "-"))
+	require.NoError(t, os.MkdirAll(sessionDir, 0o755))
+	sessionFile := filepath.Join(sessionDir, "abc.jsonl")
+	require.NoError(t, os.WriteFile(sessionFile, []byte("{}\n"), 0o600))
+
+	// no claude binary is needed
+	client := NewOffline("offline for the test")
+	require.NoError(t, client.TerminateSession(context.Background(), "abc", workingDir))
+	assert.NoFileExists(t, sessionFile)
+
+	err := client.TerminateSession(context.Background(), "abc", workingDir)
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeClaudeSessionNotFound, agxErr.Code)
+}
+
 func TestStartSession(t *testing.T) {
 	client := &Client{claudePath: "/mock/claude"}
 
-	sessionID, err := client.StartSession("/tmp/project")
+	sessionID, err := client.StartSession(context.Background(), "/tmp/project")
 	require.NoError(t, err)
 
 	// StartSession currently returns empty string to indicate fresh session
@@ -112,7 +164,7 @@ func TestResumeSession_SessionExists(t *testing.T) {
 
 	client := &Client{claudePath: "/mock/claude"}
 
-	err := client.ResumeSession(sessionID, workingDir)
+	err := client.ResumeSession(context.Background(), sessionID, workingDir)
 	require.NoError(t, err)
 }
 
@@ -122,7 +174,7 @@ func TestResumeSession_SessionNotFound(t *testing.T) {
 
 	client := &Client{claudePath: "/mock/claude"}
 
-	err := client.ResumeSession("nonexistent-session", "/tmp/project")
+	err := client.ResumeSession(context.Background(), "nonexistent-session", "/tmp/project")
 	require.Error(t, err)
 
 	var agxErr *types.AGXError
@@ -140,7 +192,7 @@ func TestDiscoverExistingSessions(t *testing.T) {
 	client := &Client{claudePath: "/mock/claude"}
 
 	// Should return empty when no project directory exists
-	sessions, err := client.DiscoverExistingSessions(workingDir)
+	sessions, err := client.DiscoverExistingSessions(context.Background(), workingDir)
 	require.NoError(t, err)
 	assert.Empty(t, sessions)
 
@@ -160,7 +212,7 @@ func TestDiscoverExistingSessions(t *testing.T) {
 	require.NoError(t, os.WriteFile(filepath.Join(sessionDir, "readme.txt"), []byte("test"), 0o644))
 
 	// Discover sessions
-	sessions, err = client.DiscoverExistingSessions(workingDir)
+	sessions, err = client.DiscoverExistingSessions(context.Background(), workingDir)
 	require.NoError(t, err)
 	assert.Len(t, sessions, 3)
 
@@ -183,7 +235,7 @@ func TestDiscoverNewestSession(t *testing.T) {
 	client := &Client{claudePath: "/mock/claude"}
 
 	// Should return empty when no sessions exist
-	newest, err := client.DiscoverNewestSession(workingDir)
+	newest, err := client.DiscoverNewestSession(context.Background(), workingDir)
 	require.NoError(t, err)
 	assert.Empty(t, newest)
 
@@ -196,7 +248,7 @@ func TestDiscoverNewestSession(t *testing.T) {
 	require.NoError(t, os.WriteFile(sessionFile, []byte(`{"test": "data"}`), 0o644))
 
 	// Should return the session
-	newest, err = client.DiscoverNewestSession(workingDir)
+	newest, err = client.DiscoverNewestSession(context.Background(), workingDir)
 	require.NoError(t, err)
 	assert.Equal(t, "test-session", newest)
 }
@@ -240,3 +292,21 @@ func TestClientInterface(t *testing.T) {
 	// This test ensures the interface contract is maintained
 	// If Client doesn't implement all interface methods, this will fail to compile
 }
+
+func TestParseHeadlessOutput(t *testing.T) {
+	output := []byte(`{"type":"result","subtype":"success","is_error":false,"duration_ms":5230,"result":"All tests pass","session_id":"abc-123","total_cost_usd":0.0125}`)
+
+	result, err := parseHeadlessOutput(output)
+	require.NoError(t, err)
+
+	assert.Equal(t, "abc-123", result.SessionID)
+	assert.Equal(t, "All tests pass", result.Result)
+	assert.False(t, result.IsError)
+	assert.Equal(t, int64(5230), result.DurationMS)
+	assert.InDelta(t, 0.0125, result.CostUSD, 1e-9)
+}
+
+func TestParseHeadlessOutput_Invalid(t *testing.T) {
+	_, err := parseHeadlessOutput([]byte("not json"))
+	require.Error(t, err)
+}
@@ -1,35 +1,47 @@
 // Package claude provides integration with Claude Code CLI
 package claude
 
+import "context"
+
 // ClientInterface defines the methods required for Claude Code integration
 // This interface allows for easy mocking in unit tests
+// Canceling ctx stops Claude subprocesses, except the interactive Claude, which owns the terminal until it exits
 type ClientInterface interface {
 	// HasSession checks if a Claude session exists by ID for the given working directory
-	HasSession(sessionID, workingDir string) (bool, error)
+	HasSession(ctx context.Context, sessionID, workingDir string) (bool, error)
 
 	// StartSession creates a fresh Claude session
-	StartSession(workingDir string) (string, error)
+	StartSession(ctx context.Context, workingDir string) (string, error)
 
 	// ResumeSession resumes an existing Claude session
-	ResumeSession(sessionID, workingDir string) error
+	ResumeSession(ctx context.Context, sessionID, workingDir string) error
 
 	// ListSessions returns a list of all Claude sessions
-	ListSessions() ([]string, error)
+	ListSessions(ctx context.Context) ([]string, error)
 
 	// GetSessionInfo returns information about a Claude session
-	GetSessionInfo(sessionID, workingDir string) (*SessionInfo, error)
+	GetSessionInfo(ctx context.Context, sessionID, workingDir string) (*SessionInfo, error)
 
-	// TerminateSession terminates a Claude session
-	TerminateSession(sessionID, workingDir string) error
+	// TerminateSession deletes a Claude session, so it can no longer be resumed
+	TerminateSession(ctx context.Context, sessionID, workingDir string) error
 
 	// DiscoverExistingSessions finds existing Claude sessions for the current directory
-	DiscoverExistingSessions(workingDir string) ([]string, error)
+	DiscoverExistingSessions(ctx context.Context, workingDir string) ([]string, error)
 
 	// DiscoverNewestSession finds the newest Claude session (most recently created)
-	DiscoverNewestSession(workingDir string) (string, error)
+	DiscoverNewestSession(ctx context.Context, workingDir string) (string, error)
 
 	// LaunchClaudeInteractively spawns monitor subprocess and runs Claude in main process
-	LaunchClaudeInteractively(workingDir string, sessionName string) error
+	// with any extra Claude arguments; ctx only bounds the launch, not the running Claude
+	// No monitor is needed, and none is spawned, when args name the conversation with --session-id
+	LaunchClaudeInteractively(ctx context.Context, workingDir string, sessionName string, args ...string) error
+
+	// Version returns the installed Claude Code version, zero when it couldn't be determined
+	Version(ctx context.Context) Version
+
+	// RunHeadless runs a single prompt non-interactively, resuming sessionID when set, with
+	// extra Claude arguments such as the permission mode
+	RunHeadless(ctx context.Context, workingDir, sessionID, prompt string, args ...string) (*HeadlessResult, error)
 }
 
 // Verify that Client implements ClientInterface at compile time
@@ -0,0 +1,56 @@
+package claude
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Todo is an item of the todo list Claude Code keeps for a conversation
+type Todo struct {
+	ID      string `json:"id"`
+	Content string `json:"content"`
+	Status  string `json:"status"`
+}
+
+// TodoCompleted is the status of a finished todo; pending and in_progress todos are open
+const TodoCompleted = "completed"
+
+// TodosDir returns where Claude Code keeps todo lists, ~/.claude/todos
+func TodosDir(homeDir string) string {
+	return filepath.Join(homeDir, ".claude", "todos")
+}
+
+// OpenTodos returns the unfinished todos of a conversation from the todo lists in dir
+// Claude Code writes one list per agent of the conversation, as <conversation>-agent-<agent>.json
+func OpenTodos(dir, claudeSessionID string) ([]Todo, error) {
+	if claudeSessionID == "" {
+		return nil, nil
+	}
+	files, err := filepath.Glob(filepath.Join(dir, claudeSessionID+"-agent-*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var open []Todo
+	for _, file := range files {
+		data, err := os.ReadFile(file) // #nosec G304 -- file is a todo list under ~/.claude/todos
+		if err != nil {
+			return nil, types.NewClaudeError(types.ErrCodeClaudeCommandFailed, "failed to read Claude todo list", err)
+		}
+		var todos []Todo
+		if err := json.Unmarshal(data, &todos); err != nil {
+			return nil, types.NewClaudeError(types.ErrCodeClaudeCommandFailed, "failed to parse Claude todo list "+file, err)
+		}
+		for _, todo := range todos {
+			if todo.Status != TodoCompleted {
+				open = append(open, todo)
+			}
+		}
+	}
+	return open, nil
+}
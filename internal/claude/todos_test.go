@@ -0,0 +1,39 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenTodos(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "c-1-agent-c-1.json"), []byte(`[
+  {"id": "1", "content": "Write the parser", "status": "completed"},
+  {"id": "2", "content": "Add tests", "status": "in_progress"},
+  {"id": "3", "content": "Update docs", "status": "pending"}
+]`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "c-2-agent-c-2.json"), []byte(`[{"id": "1", "content": "Other", "status": "pending"}]`), 0o600))
+
+	todos, err := OpenTodos(dir, "c-1")
+	require.NoError(t, err)
+	assert.Equal(t, []Todo{
+		{ID: "2", Content: "Add tests", Status: "in_progress"},
+		{ID: "3", Content: "Update docs", Status: "pending"},
+	}, todos)
+
+	todos, err = OpenTodos(dir, "missing")
+	require.NoError(t, err)
+	assert.Empty(t, todos)
+
+	todos, err = OpenTodos(dir, "")
+	require.NoError(t, err)
+	assert.Empty(t, todos)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "c-3-agent-c-3.json"), []byte("{"), 0o600))
+	_, err = OpenTodos(dir, "c-3")
+	assert.Error(t, err)
+}
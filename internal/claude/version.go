@@ -0,0 +1,214 @@
+package claude
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/bitomule/kamui/internal/trace"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// versionTimeout bounds `claude --version`, which should answer immediately
+const versionTimeout = 10 * time.Second
+
+var versionPattern = regexp.MustCompile(`(\d+)\.(\d+)\.(\d+)`)
+
+// Version is a Claude Code CLI version
+type Version struct {
+	Major, Minor, Patch int
+}
+
+// ParseVersion reads the first x.y.z version in s, e.g. the output of `claude --version`
+func ParseVersion(s string) (Version, error) {
+	match := versionPattern.FindStringSubmatch(s)
+	if match == nil {
+		return Version{}, fmt.Errorf("no version number in %q", s)
+	}
+	major, _ := strconv.Atoi(match[1])
+	minor, _ := strconv.Atoi(match[2])
+	patch, _ := strconv.Atoi(match[3])
+	return Version{Major: major, Minor: minor, Patch: patch}, nil
+}
+
+// String formats the version as x.y.z
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// IsZero reports whether the version is unknown
+func (v Version) IsZero() bool {
+	return v == Version{}
+}
+
+// AtLeast reports whether v is minimum or newer
+func (v Version) AtLeast(minimum Version) bool {
+	if v.Major != minimum.Major {
+		return v.Major > minimum.Major
+	}
+	if v.Minor != minimum.Minor {
+		return v.Minor > minimum.Minor
+	}
+	return v.Patch >= minimum.Patch
+}
+
+// Feature is a Claude Code capability Kamui relies on, and the first version that has it
+type Feature struct {
+	Name       string
+	Flag       string
+	MinVersion Version
+}
+
+// Features gated on the installed Claude Code version
+var (
+	FeaturePermissionMode = Feature{Name: "read-only sessions", Flag: "--permission-mode", MinVersion: Version{1, 0, 0}}
+	FeatureJSONOutput     = Feature{Name: "headless runs", Flag: "--output-format", MinVersion: Version{1, 0, 0}}
+	FeatureSessionID      = Feature{Name: "choosing the conversation ID", Flag: "--session-id", MinVersion: Version{1, 0, 56}}
+	FeatureForkSession    = Feature{Name: "forking conversations", Flag: "--fork-session", MinVersion: Version{1, 0, 94}}
+	FeatureStatusLine     = Feature{Name: "the status line", MinVersion: Version{1, 0, 71}}
+	FeatureSystemPrompt   = Feature{Name: "variant system prompts", Flag: "--append-system-prompt", MinVersion: Version{1, 0, 0}}
+)
+
+// flagFeatures maps Claude flags to the feature they need
+var flagFeatures = map[string]Feature{
+	FeaturePermissionMode.Flag: FeaturePermissionMode,
+	FeatureJSONOutput.Flag:     FeatureJSONOutput,
+	FeatureSessionID.Flag:      FeatureSessionID,
+	FeatureForkSession.Flag:    FeatureForkSession,
+	FeatureSystemPrompt.Flag:   FeatureSystemPrompt,
+}
+
+// Check returns an error when version is known to lack the feature
+// An unknown (zero) version passes, so a failed version check never blocks Claude
+func Check(version Version, feature Feature) error {
+	if version.IsZero() || version.AtLeast(feature.MinVersion) {
+		return nil
+	}
+
+	name := feature.Name
+	if feature.Flag != "" {
+		name = fmt.Sprintf("%s (%s)", feature.Name, feature.Flag)
+	}
+	return &types.AGXError{
+		Code:    types.ErrCodeDependencyVersion,
+		Message: fmt.Sprintf("%s requires Claude Code >= %s, but %s is installed", name, feature.MinVersion, version),
+	}
+}
+
+// CheckArgs checks every flag in a Claude command line against version
+func CheckArgs(version Version, args []string) error {
+	for _, arg := range args {
+		if feature, ok := flagFeatures[arg]; ok {
+			if err := Check(version, feature); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// FeaturesForArgs returns the version-gated features a Claude command line uses
+func FeaturesForArgs(args []string) []Feature {
+	var features []Feature
+	for _, arg := range args {
+		if feature, ok := flagFeatures[arg]; ok {
+			features = append(features, feature)
+		}
+	}
+	return features
+}
+
+// CheckMinimum returns an error when version is older than minimum (the claude.minVersion
+// setting, zero when unset) or than the newest of features needs
+// An unknown (zero) version passes, as in Check
+func CheckMinimum(version, minimum Version, features []Feature) error {
+	if version.IsZero() {
+		return nil
+	}
+	required, reason := minimum, "claude.minVersion"
+	for _, feature := range features {
+		if !required.AtLeast(feature.MinVersion) {
+			required, reason = feature.MinVersion, feature.Name
+			if feature.Flag != "" {
+				reason = fmt.Sprintf("%s (%s)", feature.Name, feature.Flag)
+			}
+		}
+	}
+	if required.IsZero() || version.AtLeast(required) {
+		return nil
+	}
+	return &types.AGXError{
+		Code:    types.ErrCodeDependencyVersion,
+		Message: fmt.Sprintf("Claude Code >= %s is required by %s, but %s is installed", required, reason, version),
+	}
+}
+
+// versionCache is the recorded version of a Claude binary
+// It is reused while the binary at Path is unchanged
+type versionCache struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+	Version string    `json:"version"`
+}
+
+// DefaultVersionCachePath returns where the detected Claude Code version is recorded
+func DefaultVersionCachePath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".kamui", "claude-version.json")
+}
+
+// DetectVersion returns the version of the Claude binary at claudePath
+// The result is recorded in cachePath and reused until the binary changes; an empty
+// cachePath always runs `claude --version`
+func DetectVersion(ctx context.Context, claudePath, cachePath string) (Version, error) {
+	info, err := os.Stat(claudePath)
+	if err != nil {
+		return Version{}, types.NewClaudeError(types.ErrCodeClaudeNotFound, "claude not found", err)
+	}
+	current := versionCache{Path: claudePath, Size: info.Size(), ModTime: info.ModTime().UTC()}
+
+	if cachePath != "" {
+		var cached versionCache
+		if data, readErr := os.ReadFile(cachePath); readErr == nil && json.Unmarshal(data, &cached) == nil { // #nosec G304 -- fixed path under ~/.kamui
+			if cached.Path == current.Path && cached.Size == current.Size && cached.ModTime.Equal(current.ModTime) {
+				if version, parseErr := ParseVersion(cached.Version); parseErr == nil {
+					return version, nil
+				}
+			}
+		}
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, versionTimeout)
+	defer cancel()
+	output, err := trace.Output(exec.CommandContext(runCtx, claudePath, "--version")) // #nosec G204 -- claudePath is the claude binary found in PATH
+	if err != nil {
+		if ctxErr := types.ContextError(ctx); ctxErr != nil {
+			return Version{}, ctxErr
+		}
+		return Version{}, types.NewClaudeError(types.ErrCodeClaudeCommandFailed, "failed to run 'claude --version'", err)
+	}
+	version, err := ParseVersion(string(output))
+	if err != nil {
+		return Version{}, types.NewClaudeError(types.ErrCodeClaudeCommandFailed, "failed to parse 'claude --version'", err)
+	}
+
+	if cachePath != "" {
+		current.Version = version.String()
+		if data, marshalErr := json.MarshalIndent(current, "", "  "); marshalErr == nil {
+			if mkdirErr := os.MkdirAll(filepath.Dir(cachePath), 0o750); mkdirErr == nil {
+				_ = os.WriteFile(cachePath, data, 0o600) // the cache only saves a subprocess next time
+			}
+		}
+	}
+	return version, nil
+}
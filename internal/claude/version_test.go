@@ -0,0 +1,125 @@
+package claude
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// writeFakeClaude writes a claude that prints version and counts its runs in a file next to it
+func writeFakeClaude(t *testing.T, version string) (string, string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake claude is a shell script")
+	}
+	dir := t.TempDir()
+	runs := filepath.Join(dir, "runs")
+	path := filepath.Join(dir, "claude")
+	script := "#!/bin/sh\necho x >> " + runs + "\necho '" + version + " (Claude Code)'\n"
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o700)) // #nosec G306 -- test script must be executable
+	return path, runs
+}
+
+func countRuns(t *testing.T, runs string) int {
+	t.Helper()
+	data, err := os.ReadFile(runs)
+	if os.IsNotExist(err) {
+		return 0
+	}
+	require.NoError(t, err)
+	return len(data) / 2
+}
+
+func TestParseVersion(t *testing.T) {
+	version, err := ParseVersion("1.0.71 (Claude Code)\n")
+	require.NoError(t, err)
+	assert.Equal(t, Version{1, 0, 71}, version)
+	assert.Equal(t, "1.0.71", version.String())
+
+	_, err = ParseVersion("claude")
+	require.Error(t, err)
+}
+
+func TestVersion_AtLeast(t *testing.T) {
+	v := Version{1, 2, 3}
+	assert.True(t, v.AtLeast(Version{1, 2, 3}))
+	assert.True(t, v.AtLeast(Version{1, 1, 9}))
+	assert.True(t, v.AtLeast(Version{0, 9, 9}))
+	assert.False(t, v.AtLeast(Version{1, 2, 4}))
+	assert.False(t, v.AtLeast(Version{2, 0, 0}))
+}
+
+func TestCheck(t *testing.T) {
+	require.NoError(t, Check(Version{1, 0, 94}, FeatureForkSession))
+	require.NoError(t, Check(Version{}, FeatureForkSession), "an unknown version never blocks")
+
+	err := Check(Version{1, 0, 50}, FeatureForkSession)
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeDependencyVersion, agxErr.Code)
+	assert.Equal(t, "forking conversations (--fork-session) requires Claude Code >= 1.0.94, but 1.0.50 is installed", agxErr.Message)
+}
+
+func TestCheckArgs(t *testing.T) {
+	old := Version{0, 2, 0}
+	require.NoError(t, CheckArgs(old, []string{"--resume", "abc"}))
+	require.Error(t, CheckArgs(old, []string{"--resume", "abc", "--permission-mode", "plan"}))
+	require.NoError(t, CheckArgs(Version{1, 0, 0}, []string{"--permission-mode", "plan"}))
+}
+
+func TestCheckMinimum(t *testing.T) {
+	installed := Version{1, 0, 60}
+	require.NoError(t, CheckMinimum(installed, Version{}, nil))
+	require.NoError(t, CheckMinimum(installed, Version{1, 0, 60}, []Feature{FeatureSessionID}))
+	require.NoError(t, CheckMinimum(Version{}, Version{2, 0, 0}, nil), "an unknown version never blocks")
+
+	err := CheckMinimum(installed, Version{1, 0, 80}, []Feature{FeatureSessionID})
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeDependencyVersion, agxErr.Code)
+	assert.Equal(t, "Claude Code >= 1.0.80 is required by claude.minVersion, but 1.0.60 is installed", agxErr.Message)
+
+	err = CheckMinimum(installed, Version{1, 0, 80}, FeaturesForArgs([]string{"--model", "opus", "--fork-session"}))
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, "Claude Code >= 1.0.94 is required by forking conversations (--fork-session), but 1.0.60 is installed", agxErr.Message)
+}
+
+func TestDetectVersion_CachesUntilBinaryChanges(t *testing.T) {
+	claudePath, runs := writeFakeClaude(t, "1.0.80")
+	cachePath := filepath.Join(t.TempDir(), "claude-version.json")
+
+	version, err := DetectVersion(context.Background(), claudePath, cachePath)
+	require.NoError(t, err)
+	assert.Equal(t, Version{1, 0, 80}, version)
+
+	version, err = DetectVersion(context.Background(), claudePath, cachePath)
+	require.NoError(t, err)
+	assert.Equal(t, Version{1, 0, 80}, version)
+	assert.Equal(t, 1, countRuns(t, runs), "the second call is answered from the cache")
+
+	script := "#!/bin/sh\necho x >> " + runs + "\necho '2.0.1 (Claude Code)'\n"
+	require.NoError(t, os.WriteFile(claudePath, []byte(script), 0o700)) // #nosec G306 -- test script must be executable
+	version, err = DetectVersion(context.Background(), claudePath, cachePath)
+	require.NoError(t, err)
+	assert.Equal(t, Version{2, 0, 1}, version)
+	assert.Equal(t, 2, countRuns(t, runs))
+}
+
+func TestClient_Require(t *testing.T) {
+	claudePath, runs := writeFakeClaude(t, "1.0.10")
+	client := &Client{claudePath: claudePath}
+
+	require.Error(t, client.Require(context.Background(), FeatureStatusLine))
+	require.NoError(t, client.Require(context.Background(), FeaturePermissionMode))
+	assert.Equal(t, 1, countRuns(t, runs), "detected once per client")
+
+	missing := &Client{claudePath: "/mock/claude"}
+	require.NoError(t, missing.Require(context.Background(), FeatureStatusLine), "unknown versions don't block")
+}
@@ -0,0 +1,90 @@
+package claude
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// WatchNewSession waits until Claude writes a transcript to projectDir that isn't one of existing
+// and returns its session ID
+// projectDir may not exist yet: Claude creates it along with the first transcript of a project
+func WatchNewSession(ctx context.Context, projectDir string, existing []string) (string, error) {
+	known := make(map[string]bool, len(existing))
+	for _, sessionID := range existing {
+		known[sessionID] = true
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return "", err
+	}
+	defer watcher.Close()
+
+	// Watch the parent for the project directory appearing, then the directory itself
+	parent := filepath.Dir(projectDir)
+	if err := os.MkdirAll(parent, 0o700); err != nil {
+		return "", err
+	}
+	if err := watcher.Add(parent); err != nil {
+		return "", err
+	}
+	if err := watcher.Add(projectDir); err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	// A transcript written before the watches were in place is found by the first scan
+	for {
+		if sessionID := newTranscript(projectDir, known); sessionID != "" {
+			return sessionID, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", types.ContextError(ctx)
+		case err := <-watcher.Errors:
+			return "", err
+		case event := <-watcher.Events:
+			if event.Name == projectDir && event.Has(fsnotify.Create) {
+				if err := watcher.Add(projectDir); err != nil && !os.IsNotExist(err) {
+					return "", err
+				}
+			}
+		}
+	}
+}
+
+// newTranscript returns the ID of the first transcript in projectDir that isn't known
+func newTranscript(projectDir string, known map[string]bool) string {
+	sessionIDs, err := transcriptIDs(projectDir)
+	if err != nil {
+		return "" // the directory may not exist yet
+	}
+	for _, sessionID := range sessionIDs {
+		if !known[sessionID] {
+			return sessionID
+		}
+	}
+	return ""
+}
+
+// transcriptIDs lists the session IDs of the transcripts in a Claude project directory
+func transcriptIDs(projectDir string) ([]string, error) {
+	entries, err := os.ReadDir(projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var sessionIDs []string
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".jsonl" {
+			sessionIDs = append(sessionIDs, strings.TrimSuffix(entry.Name(), ".jsonl"))
+		}
+	}
+	return sessionIDs, nil
+}
@@ -0,0 +1,60 @@
+package claude
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestWatchNewSession(t *testing.T) {
+	projectDir := filepath.Join(t.TempDir(), "projects", "-src-app")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	found := make(chan string, 1)
+	go func() {
+		sessionID, err := WatchNewSession(ctx, projectDir, []string{"old"})
+		assert.NoError(t, err)
+		found <- sessionID
+	}()
+
+	// Claude creates the project directory with the first transcript; known ones are ignored
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, os.MkdirAll(projectDir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "old.jsonl"), []byte("{}\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "notes.txt"), []byte("x"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "new.jsonl"), []byte("{}\n"), 0o600))
+
+	select {
+	case sessionID := <-found:
+		assert.Equal(t, "new", sessionID)
+	case <-ctx.Done():
+		t.Fatal("the new transcript was not noticed")
+	}
+}
+
+func TestWatchNewSession_Existing(t *testing.T) {
+	projectDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "new.jsonl"), []byte("{}\n"), 0o600))
+
+	sessionID, err := WatchNewSession(context.Background(), projectDir, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "new", sessionID)
+}
+
+func TestWatchNewSession_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	_, err := WatchNewSession(ctx, t.TempDir(), nil)
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeInterrupted, agxErr.Code)
+}
@@ -0,0 +1,51 @@
+// Package clock abstracts the current time so time-based policies can run against simulated time
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the current time
+type Clock interface {
+	Now() time.Time
+}
+
+// System is the wall clock
+var System Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// Fake is a clock that only moves when told to, for tests and replays; it is safe for concurrent use
+type Fake struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFake creates a fake clock stopped at now
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now returns the fake clock's time
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Set moves the clock to now
+func (f *Fake) Set(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = now
+}
+
+// Advance moves the clock forward by d
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
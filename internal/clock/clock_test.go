@@ -0,0 +1,29 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSystem(t *testing.T) {
+	before := time.Now()
+	now := System.Now()
+	assert.False(t, now.Before(before))
+	assert.WithinDuration(t, time.Now(), now, time.Second)
+}
+
+func TestFake(t *testing.T) {
+	start := time.Date(2025, 3, 10, 14, 0, 0, 0, time.UTC)
+	fake := NewFake(start)
+
+	assert.Equal(t, start, fake.Now())
+	assert.Equal(t, start, fake.Now(), "time stands still until moved")
+
+	fake.Advance(90 * time.Minute)
+	assert.Equal(t, start.Add(90*time.Minute), fake.Now())
+
+	fake.Set(start.AddDate(0, 1, 0))
+	assert.Equal(t, start.AddDate(0, 1, 0), fake.Now())
+}
@@ -0,0 +1,224 @@
+// Package config renders Kamui's global configuration file with every option filled in, so the
+// available settings can be discovered from the file itself
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Version is written to the version field of new config files
+const Version = "1.0.0"
+
+// EnvPrefix starts the environment variables that override options
+const EnvPrefix = "KAMUI"
+
+// Format is a config file format
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	// FormatYAML is the only format written with comments, since JSON has none
+	FormatYAML Format = "yaml"
+)
+
+// header starts YAML config files
+const header = `Kamui configuration, written by kam config init with every option at its default.
+Options marked Reserved aren't used yet. kam schema config prints a JSON Schema of this file.`
+
+// FormatFor returns the format viper reads path in, going by its extension
+func FormatFor(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	default:
+		return FormatJSON
+	}
+}
+
+// Render writes cfg in format, with empty lists and maps instead of nulls so every option shows
+// the shape of its value
+func Render(cfg types.Config, format Format) ([]byte, error) {
+	if cfg.Version == "" {
+		cfg.Version = Version
+	}
+	fillEmpty(reflect.ValueOf(&cfg).Elem())
+
+	switch format {
+	case FormatJSON:
+		data, err := json.MarshalIndent(cfg, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case FormatYAML:
+		root, err := yamlNode(reflect.ValueOf(cfg), "")
+		if err != nil {
+			return nil, err
+		}
+		document := &yaml.Node{Kind: yaml.DocumentNode, HeadComment: header, Content: []*yaml.Node{root}}
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(document); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unsupported config format '%s'", format)
+	}
+}
+
+// Options lists the dotted paths of every option in the config, in file order
+func Options() []string {
+	var paths []string
+	walk(func(path string, section bool) {
+		if !section {
+			paths = append(paths, path)
+		}
+	})
+	return paths
+}
+
+// EnvName returns the environment variable that overrides an option: KAMUI_ followed by its
+// path in upper snake case, e.g. KAMUI_CLAUDE_DEFAULT_MODEL for claude.defaultModel
+func EnvName(path string) string {
+	var b strings.Builder
+	b.WriteString(EnvPrefix)
+	for _, segment := range strings.Split(path, ".") {
+		b.WriteByte('_')
+		for i, r := range segment {
+			if unicode.IsUpper(r) && i > 0 {
+				previous := rune(segment[i-1])
+				if unicode.IsLower(previous) || unicode.IsDigit(previous) {
+					b.WriteByte('_')
+				}
+			}
+			b.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	return b.String()
+}
+
+// walk calls fn with the dotted path of every option and section in the config, in file order
+func walk(fn func(path string, section bool)) {
+	var visit func(t reflect.Type, prefix string)
+	visit = func(t reflect.Type, prefix string) {
+		for i := 0; i < t.NumField(); i++ {
+			name := jsonName(t.Field(i))
+			if name == "" {
+				continue
+			}
+			path := joinPath(prefix, name)
+			section := isSection(t.Field(i).Type)
+			fn(path, section)
+			if section {
+				visit(t.Field(i).Type, path)
+			}
+		}
+	}
+	visit(reflect.TypeOf(types.Config{}), "")
+}
+
+// yamlNode builds the mapping for a config section, commenting each option
+func yamlNode(value reflect.Value, prefix string) (*yaml.Node, error) {
+	mapping := &yaml.Node{Kind: yaml.MappingNode}
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name := jsonName(field)
+		if name == "" {
+			continue
+		}
+		path := joinPath(prefix, name)
+
+		var node *yaml.Node
+		var err error
+		if isSection(field.Type) {
+			node, err = yamlNode(value.Field(i), path)
+		} else {
+			node, err = scalarNode(value.Field(i).Interface())
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		key := &yaml.Node{Kind: yaml.ScalarNode, Value: name, HeadComment: descriptions[path]}
+		mapping.Content = append(mapping.Content, key, node)
+	}
+	return mapping, nil
+}
+
+// scalarNode encodes an option's value the way it appears in JSON, so durations stay strings
+func scalarNode(value interface{}) (*yaml.Node, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var plain interface{}
+	if err := json.Unmarshal(data, &plain); err != nil {
+		return nil, err
+	}
+	node := &yaml.Node{}
+	if err := node.Encode(plain); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// fillEmpty replaces nil slices and maps in the config with empty ones
+func fillEmpty(value reflect.Value) {
+	switch value.Kind() {
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if value.Type().Field(i).IsExported() {
+				fillEmpty(value.Field(i))
+			}
+		}
+	case reflect.Slice:
+		if value.IsNil() {
+			value.Set(reflect.MakeSlice(value.Type(), 0, 0))
+		}
+	case reflect.Map:
+		if value.IsNil() {
+			value.Set(reflect.MakeMap(value.Type()))
+		}
+	}
+}
+
+// isSection reports whether a field holds nested options rather than a value
+func isSection(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct
+}
+
+// jsonName returns the key a field is written under, or "" for fields never written
+func jsonName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
+
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestEveryOptionIsDescribed(t *testing.T) {
+	var paths []string
+	walk(func(path string, _ bool) { paths = append(paths, path) })
+	for _, path := range paths {
+		assert.NotEmpty(t, descriptions[path], "%s has no description", path)
+	}
+	for path := range descriptions {
+		assert.Contains(t, paths, path, "description for unknown option")
+	}
+}
+
+func TestOptions(t *testing.T) {
+	options := Options()
+	assert.Equal(t, "version", options[0])
+	assert.Contains(t, options, "claude.defaultModel")
+	assert.Contains(t, options, "ui.iterm2.badge")
+	assert.NotContains(t, options, "ui.iterm2", "sections aren't options")
+}
+
+func TestEnvName(t *testing.T) {
+	tests := map[string]string{
+		"version":                   "KAMUI_VERSION",
+		"claude.defaultModel":       "KAMUI_CLAUDE_DEFAULT_MODEL",
+		"jira.apiToken":             "KAMUI_JIRA_API_TOKEN",
+		"jira.baseUrl":              "KAMUI_JIRA_BASE_URL",
+		"ui.iterm2.badge":           "KAMUI_UI_ITERM2_BADGE",
+		"transcript.gcAfter":        "KAMUI_TRANSCRIPT_GC_AFTER",
+		"storage.indexSyncInterval": "KAMUI_STORAGE_INDEX_SYNC_INTERVAL",
+	}
+	for path, expected := range tests {
+		assert.Equal(t, expected, EnvName(path))
+	}
+}
+
+func TestFormatFor(t *testing.T) {
+	assert.Equal(t, FormatJSON, FormatFor("/home/me/.kamui/config.json"))
+	assert.Equal(t, FormatYAML, FormatFor("config.YAML"))
+	assert.Equal(t, FormatYAML, FormatFor("config.yml"))
+	assert.Equal(t, FormatJSON, FormatFor("-"))
+}
+
+func TestRenderJSON(t *testing.T) {
+	cfg := types.Config{}
+	cfg.UI.Picker = "menu"
+	cfg.Storage.IndexSyncInterval = types.Duration(5 * time.Minute)
+
+	data, err := Render(cfg, FormatJSON)
+	require.NoError(t, err)
+
+	var settings map[string]map[string]interface{}
+	require.NoError(t, json.Unmarshal(stripVersion(t, data), &settings))
+	assert.Equal(t, "menu", settings["ui"]["picker"])
+	assert.Equal(t, []interface{}{}, settings["ui"]["exitAlert"], "lists are written empty rather than null")
+	assert.Equal(t, map[string]interface{}{}, settings["ui"]["iterm2"].(map[string]interface{})["profiles"])
+	assert.Equal(t, "5m", settings["storage"]["indexSyncInterval"])
+
+	var decoded types.Config
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, Version, decoded.Version)
+}
+
+func TestRenderYAML(t *testing.T) {
+	cfg := types.Config{Version: "2.0.0"}
+	cfg.Session.SnapshotCount = 20
+	cfg.Transcript.GCAfter = "30d"
+
+	data, err := Render(cfg, FormatYAML)
+	require.NoError(t, err)
+	text := string(data)
+
+	assert.True(t, strings.HasPrefix(text, "# Kamui configuration"))
+	assert.Contains(t, text, "  # Snapshots kept per session\n  snapshotCount: 20\n")
+	assert.Contains(t, text, "gcAfter: 30d")
+
+	var decoded map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(data, &decoded))
+	assert.Equal(t, "2.0.0", decoded["version"])
+	assert.Equal(t, "", decoded["storage"].(map[string]interface{})["indexSyncInterval"])
+}
+
+// stripVersion drops the top-level version, the only option outside a section
+func stripVersion(t *testing.T, data []byte) []byte {
+	var settings map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &settings))
+	delete(settings, "version")
+	stripped, err := json.Marshal(settings)
+	require.NoError(t, err)
+	return stripped
+}
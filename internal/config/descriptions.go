@@ -0,0 +1,81 @@
+package config
+
+// descriptions are the comments written above each option, by dotted path
+var descriptions = map[string]string{
+	"version": "Format version of this file",
+
+	"default":                    "Project and session defaults",
+	"default.sessionVariant":     "Reserved",
+	"default.autoCreateSessions": "Reserved",
+	"default.projectDetection":   "Which directory counts as the project: auto (the monorepo workspace root, else the repository root), git (the repository root), marker (nearest directory with one of projectMarkers) or cwd",
+	"default.projectMarkers":     "Files or directories that mark a project root for projectDetection: marker (empty uses .kamui, .git, go.mod, package.json, Cargo.toml, pyproject.toml)",
+
+	"claude":                     "Claude Code integration",
+	"claude.defaultModel":        "Reserved",
+	"claude.resumeTimeout":       "Reserved",
+	"claude.defaultArgs":         "Arguments passed to Claude on every launch, before each session's own",
+	"claude.retryAttempts":       "Attempts at saving a new conversation's binding while the session file is busy",
+	"claude.contextPreservation": "Reserved",
+	"claude.injectContext":       "Append the session's name, description, links and open todos to Claude's system prompt",
+	"claude.minVersion":          "Oldest Claude Code version to launch, e.g. 1.0.94; older ones are refused with an upgrade hint",
+
+	"session":                     "Session management",
+	"session.autoBranchSessions":  "Reserved",
+	"session.cleanupInactiveDays": "Days a session can go unused before 'kam archive --inactive' archives it; 0 disables it",
+	"session.backupCount":         "Reserved",
+	"session.autoArchive":         "Reserved",
+	"session.enableStatistics":    "Reserved",
+	"session.caseFolding":         "Treat session names differing only in case or Unicode form as the same session",
+	"session.snapshots":           "Snapshot the repository before every launch, for kam rollback",
+	"session.snapshotDirty":       "Include uncommitted changes in snapshots",
+	"session.snapshotCount":       "Snapshots kept per session",
+	"session.record":              "Record Claude's terminal output on every launch, for kam replay",
+	"session.recordingCount":      "Recordings kept per session",
+	"session.checkpoint":          "Commit changes each time Claude exits: off, commit (to the checked out branch) or branch (to kamui/<session>)",
+
+	"storage":                   "Where and how sessions are stored",
+	"storage.backend":           "json-files, remote (a kam serve instance) or memory (nothing kept between runs)",
+	"storage.durableWrites":     "Fsync each session file and its directory when saving",
+	"storage.remote":            "The kam serve instance used by the remote backend",
+	"storage.remote.url":        "Address of the server",
+	"storage.remote.token":      "Token shared with the server; KAMUI_SERVER_TOKEN overrides it",
+	"storage.indexSyncInterval": "How old the global session index may get before kam sessions --global syncs it",
+	"storage.enableGlobalIndex": "Keep the global session index in ~/.claude/kamui-index.json",
+	"storage.compactThreshold":  "State history entries kept per session before older ones are compacted (empty means 100)",
+	"storage.logRetentionDays":  "Days kam gc keeps logs and audit entries",
+	"storage.logFileSize":       "Size at which Kamui's log rotates",
+	"storage.logMaxSize":        "Total size of logs kam gc keeps",
+
+	"ui":                    "Terminal output and prompts",
+	"ui.colorOutput":        "Color output on terminals (--no-color turns it off for one command)",
+	"ui.verboseLogging":     "Reserved",
+	"ui.confirmDestructive": "Ask before destructive commands such as kam trim and kam gc",
+	"ui.picker":             "Session picker: tui, menu or fzf",
+	"ui.defaultEditor":      "Reserved",
+	"ui.notifications":      "Desktop notifications when kam run and the daemon finish",
+	"ui.exitAlert":          "Alerts when Claude exits: bell and/or osc9",
+	"ui.iterm2":             "iTerm2 integration",
+	"ui.iterm2.badge":       "Show the session name in the iTerm2 badge",
+	"ui.iterm2.profiles":    "iTerm2 profile to switch to, by session variant",
+	"ui.theme":              "Color theme: default, solarized, high-contrast, monochrome or one from ui.themes",
+	"ui.themes":             "Custom color themes by name; each styles accent, emphasis, muted, success, warning, error and match (e.g. \"bold #268bd2\") and takes the rest from base",
+
+	"jira":                      "Jira integration for linked issues",
+	"jira.baseUrl":              "Jira site, e.g. https://example.atlassian.net",
+	"jira.email":                "Account email for the Jira API",
+	"jira.apiToken":             "Jira API token",
+	"jira.transitionOnComplete": "Transition applied to linked issues by kam complete, e.g. Done",
+
+	"github":        "GitHub access for kam share-transcript",
+	"github.apiUrl": "GitHub REST API, e.g. https://github.example.com/api/v3 for GitHub Enterprise",
+	"github.token":  "Token with the gist scope; without one kam share-transcript uses the gh CLI",
+
+	"timesheet":         "Time tracking",
+	"timesheet.enabled": "Record the time spent in interactive sessions, for kam timesheet",
+
+	"transcript":             "Reading Claude transcripts",
+	"transcript.maxLineSize": "Longer transcript lines are skipped",
+	"transcript.maxBytes":    "Stop reading a transcript after this much (empty means no limit)",
+	"transcript.maxLines":    "Stop reading a transcript after this many lines (0 means no limit)",
+	"transcript.gcAfter":     "Age after which kam gc --transcripts deletes transcripts no session is bound to",
+}
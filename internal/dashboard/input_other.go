@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package dashboard
+
+import "os"
+
+// openInput returns in; reads can't time out here, so the dashboard refreshes after each key
+func openInput(in *os.File) (input *os.File, timed bool, closeInput func()) {
+	return in, false, func() {}
+}
@@ -0,0 +1,35 @@
+//go:build linux || darwin
+
+package dashboard
+
+import (
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// openInput returns a non-blocking duplicate of in whose reads can time out, so the dashboard
+// refreshes while no key is pressed; timed is false when the terminal can't be polled, and then
+// in is returned as is. closeInput undoes it.
+func openInput(in *os.File) (input *os.File, timed bool, closeInput func()) {
+	fd, err := unix.Dup(int(in.Fd()))
+	if err != nil {
+		return in, false, func() {}
+	}
+	if err := unix.SetNonblock(fd, true); err != nil {
+		unix.Close(fd)
+		return in, false, func() {}
+	}
+
+	input = os.NewFile(uintptr(fd), in.Name())
+	closeInput = func() {
+		input.Close()
+		_ = unix.SetNonblock(int(in.Fd()), false) // the duplicate shared in's mode
+	}
+	if input.SetReadDeadline(time.Time{}) != nil {
+		closeInput()
+		return in, false, func() {}
+	}
+	return input, true, closeInput
+}
@@ -0,0 +1,379 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bitomule/kamui/internal/terminal"
+	"github.com/bitomule/kamui/internal/viewer"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Help is the key summary shown in the status bar
+const Help = "j/k move · enter resume · c complete · a archive · r refresh · h show/hide archived · q quit"
+
+// ActionKind is what the user asked the dashboard to do
+type ActionKind int
+
+const (
+	ActionNone ActionKind = iota
+	ActionQuit
+	// ActionRefresh recollects everything, token usage included
+	ActionRefresh
+	ActionResume
+	ActionComplete
+	ActionArchive
+)
+
+// Action is a request from the dashboard, with the session it applies to
+type Action struct {
+	Kind    ActionKind
+	Project Project
+	Session Session
+}
+
+// line is one rendered row: a project header, or one of its sessions when session >= 0
+type line struct {
+	project int
+	session int
+}
+
+// Model is the dashboard state; it does no I/O so it can be driven by tests
+type Model struct {
+	snapshot     *Snapshot
+	showArchived bool
+	// selected is the ID of the selected session, kept across refreshes
+	selected string
+	offset   int
+	width    int
+	height   int
+
+	pending Action
+	message string
+}
+
+// New builds a dashboard over a snapshot; archived sessions start hidden
+func New(snapshot *Snapshot) *Model {
+	m := &Model{width: 80, height: 24}
+	m.SetSnapshot(snapshot)
+	return m
+}
+
+// SetSnapshot replaces the sessions shown, keeping the selection when the session is still there
+func (m *Model) SetSnapshot(snapshot *Snapshot) {
+	if snapshot == nil {
+		snapshot = &Snapshot{}
+	}
+	m.snapshot = snapshot
+	m.clampSelection()
+}
+
+// SetMessage shows a message in the status bar until the next keypress
+func (m *Model) SetMessage(message string) {
+	m.message = message
+}
+
+// SetSize sets the terminal size the view is rendered for
+func (m *Model) SetSize(width, height int) {
+	if width < 40 {
+		width = 40
+	}
+	if height < 4 {
+		height = 4
+	}
+	m.width, m.height = width, height
+	m.scrollToSelection()
+}
+
+// Selected returns the selected session and its project
+func (m *Model) Selected() (Project, Session, bool) {
+	for _, l := range m.lines() {
+		if l.session < 0 {
+			continue
+		}
+		project := m.snapshot.Projects[l.project]
+		if session := project.Sessions[l.session]; session.ID == m.selected {
+			return project, session, true
+		}
+	}
+	return Project{}, Session{}, false
+}
+
+// HandleKey applies a keypress and returns what the caller should do about it
+func (m *Model) HandleKey(key viewer.Key) Action {
+	if m.pending.Kind != ActionNone {
+		action := m.pending
+		m.pending, m.message = Action{}, ""
+		if key == "y" || key == "Y" {
+			return action
+		}
+		return Action{}
+	}
+
+	m.message = ""
+	switch key {
+	case "q", viewer.KeyInterrupt, viewer.KeyEscape:
+		return Action{Kind: ActionQuit}
+	case "j", viewer.KeyDown:
+		m.moveSelection(1)
+	case "k", viewer.KeyUp:
+		m.moveSelection(-1)
+	case "g", viewer.KeyHome:
+		m.moveSelection(-len(m.lines()))
+	case "G", viewer.KeyEnd:
+		m.moveSelection(len(m.lines()))
+	case viewer.KeyPageDown:
+		m.moveSelection(m.pageSize())
+	case viewer.KeyPageUp:
+		m.moveSelection(-m.pageSize())
+	case "r":
+		return Action{Kind: ActionRefresh}
+	case "h":
+		m.showArchived = !m.showArchived
+		m.clampSelection()
+	case viewer.KeyEnter:
+		return m.sessionAction(ActionResume)
+	case "c":
+		return m.confirm(ActionComplete, "Complete")
+	case "a":
+		return m.confirm(ActionArchive, "Archive")
+	}
+	return Action{}
+}
+
+// View renders the visible page, title and status bar included
+func (m *Model) View() string {
+	var b strings.Builder
+	b.WriteString("\033[H")
+
+	fmt.Fprintf(&b, "\033[7m%s\033[0m\033[K\r\n", pad(terminal.TruncateEnd(m.title(), m.width), m.width))
+
+	lines := m.lines()
+	for row := 0; row < m.pageSize(); row++ {
+		index := m.offset + row
+		if index >= len(lines) {
+			b.WriteString("\033[K\r\n")
+			continue
+		}
+
+		l := lines[index]
+		project := m.snapshot.Projects[l.project]
+		if l.session < 0 {
+			header := fmt.Sprintf("%s  %s", project.Name, project.Path)
+			fmt.Fprintf(&b, "\033[1;96m%s\033[0m\033[K\r\n", terminal.TruncateEnd(header, m.width))
+			continue
+		}
+
+		session := project.Sessions[l.session]
+		text := m.sessionRow(session)
+		switch {
+		case session.ID == m.selected:
+			fmt.Fprintf(&b, "\033[1;7m%s\033[0m", pad(text, m.width))
+		case session.Running:
+			fmt.Fprintf(&b, "\033[32m%s\033[0m", text)
+		case session.State == types.SessionStateArchived:
+			fmt.Fprintf(&b, "\033[2m%s\033[0m", text)
+		default:
+			b.WriteString(text)
+		}
+		b.WriteString("\033[K\r\n")
+	}
+
+	status := Help
+	switch {
+	case m.pending.Kind != ActionNone || m.message != "":
+		status = m.message
+	case len(lines) == 0 && !m.showArchived:
+		status = "No sessions (h shows archived ones) · q quit"
+	case len(lines) == 0:
+		status = "No sessions · q quit"
+	}
+	fmt.Fprintf(&b, "\033[7m%s\033[0m\033[K", pad(terminal.TruncateEnd(" "+status, m.width), m.width))
+	return b.String()
+}
+
+// title summarizes the snapshot for the title bar
+func (m *Model) title() string {
+	var sessions, running int
+	for _, project := range m.snapshot.Projects {
+		for _, session := range project.Sessions {
+			sessions++
+			if session.Running {
+				running++
+			}
+		}
+	}
+	title := fmt.Sprintf(" Kamui · %d project(s) · %d session(s) · %d running", len(m.snapshot.Projects), sessions, running)
+	if !m.snapshot.Generated.IsZero() {
+		title += " · updated " + m.snapshot.Generated.Local().Format("15:04:05")
+	}
+	return title + " "
+}
+
+// sessionRow renders a session's columns
+func (m *Model) sessionRow(session Session) string {
+	activity := session.Activity()
+	if session.Running && session.PID > 0 {
+		activity = fmt.Sprintf("running (pid %d)", session.PID)
+	}
+	lastAccessed := "-"
+	if !session.LastAccessed.IsZero() {
+		lastAccessed = session.LastAccessed.Local().Format("2006-01-02 15:04")
+	}
+	usage := "-"
+	if m.snapshot.WithUsage {
+		usage = fmt.Sprintf("%s tok $%.2f", formatTokens(session.Tokens), session.CostUSD)
+	}
+
+	row := fmt.Sprintf("  %-28s %-18s %-18s %s  ", terminal.TruncateEnd(session.ID, 28), activity, usage, lastAccessed)
+	return terminal.TruncateEnd(row+session.Description, m.width)
+}
+
+// lines lists the visible rows: every project with sessions to show, followed by those sessions
+func (m *Model) lines() []line {
+	var lines []line
+	for p, project := range m.snapshot.Projects {
+		header := len(lines)
+		for s, session := range project.Sessions {
+			if session.State == types.SessionStateArchived && !m.showArchived {
+				continue
+			}
+			if len(lines) == header {
+				lines = append(lines, line{project: p, session: -1})
+			}
+			lines = append(lines, line{project: p, session: s})
+		}
+	}
+	return lines
+}
+
+// sessionLines returns the indexes in lines of the session rows
+func sessionLines(lines []line) []int {
+	var indexes []int
+	for i, l := range lines {
+		if l.session >= 0 {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// position returns the selected session's index among the session rows, or -1
+func (m *Model) position(lines []line, rows []int) int {
+	for i, index := range rows {
+		l := lines[index]
+		if m.snapshot.Projects[l.project].Sessions[l.session].ID == m.selected {
+			return i
+		}
+	}
+	return -1
+}
+
+// moveSelection moves the selection by delta sessions, stopping at either end
+func (m *Model) moveSelection(delta int) {
+	lines := m.lines()
+	rows := sessionLines(lines)
+	if len(rows) == 0 {
+		return
+	}
+	position := m.position(lines, rows) + delta
+	if position < 0 {
+		position = 0
+	}
+	if position >= len(rows) {
+		position = len(rows) - 1
+	}
+	l := lines[rows[position]]
+	m.selected = m.snapshot.Projects[l.project].Sessions[l.session].ID
+	m.scrollToSelection()
+}
+
+// clampSelection selects the first visible session when the selected one is gone or hidden
+func (m *Model) clampSelection() {
+	lines := m.lines()
+	rows := sessionLines(lines)
+	if len(rows) == 0 {
+		m.selected = ""
+	} else if m.position(lines, rows) < 0 {
+		l := lines[rows[0]]
+		m.selected = m.snapshot.Projects[l.project].Sessions[l.session].ID
+	}
+	m.scrollToSelection()
+}
+
+// scrollToSelection keeps the selected session, and its project's header when it fits, on screen
+func (m *Model) scrollToSelection() {
+	lines := m.lines()
+	selected := m.position(lines, sessionLines(lines))
+	if selected >= 0 {
+		index := sessionLines(lines)[selected]
+		header := index
+		for header > 0 && lines[header].session >= 0 {
+			header--
+		}
+		top := index
+		if index-header < m.pageSize() {
+			top = header
+		}
+		if top < m.offset {
+			m.offset = top
+		}
+		if index >= m.offset+m.pageSize() {
+			m.offset = index - m.pageSize() + 1
+		}
+	}
+	if maxOffset := len(lines) - m.pageSize(); m.offset > maxOffset {
+		m.offset = maxOffset
+	}
+	if m.offset < 0 {
+		m.offset = 0
+	}
+}
+
+func (m *Model) pageSize() int {
+	return m.height - 2
+}
+
+// sessionAction returns kind for the selected session, refusing to act on a running one
+func (m *Model) sessionAction(kind ActionKind) Action {
+	project, session, ok := m.Selected()
+	if !ok {
+		return Action{}
+	}
+	if session.Running {
+		m.message = fmt.Sprintf("Session '%s' is running in kam (pid %d)", session.ID, session.PID)
+		return Action{}
+	}
+	return Action{Kind: kind, Project: project, Session: session}
+}
+
+// confirm asks before completing or archiving the selected session
+func (m *Model) confirm(kind ActionKind, verb string) Action {
+	action := m.sessionAction(kind)
+	if action.Kind == ActionNone {
+		return action
+	}
+	m.pending = action
+	m.message = fmt.Sprintf("%s session '%s' in %s? (y/n)", verb, action.Session.ID, action.Project.Name)
+	return Action{}
+}
+
+// formatTokens renders a token count with a k/M suffix
+func formatTokens(tokens int64) string {
+	switch {
+	case tokens >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(tokens)/1e6)
+	case tokens >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(tokens)/1e3)
+	default:
+		return fmt.Sprintf("%d", tokens)
+	}
+}
+
+func pad(text string, width int) string {
+	if n := utf8.RuneCountInString(text); n < width {
+		return text + strings.Repeat(" ", width-n)
+	}
+	return text
+}
@@ -0,0 +1,117 @@
+package dashboard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bitomule/kamui/internal/viewer"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func newFixtureModel() *Model {
+	m := New(&Snapshot{
+		Generated: fixtureTime,
+		Projects: []Project{
+			{Name: "api", Path: "/work/api", Sessions: []Session{
+				{ID: "api-main", State: types.SessionStateActive, Running: true, PID: 4242},
+				{ID: "api-fix", State: types.SessionStateActive},
+				{ID: "api-old", State: types.SessionStateArchived},
+			}},
+			{Name: "web", Path: "/work/web", Sessions: []Session{
+				{ID: "web-main", State: types.SessionStateCompleted},
+			}},
+		},
+	})
+	m.SetSize(120, 20)
+	return m
+}
+
+func selectedID(m *Model) string {
+	_, session, _ := m.Selected()
+	return session.ID
+}
+
+func TestModel_MovesBetweenSessions(t *testing.T) {
+	m := newFixtureModel()
+	assert.Equal(t, "api-main", selectedID(m))
+
+	m.HandleKey("j")
+	assert.Equal(t, "api-fix", selectedID(m))
+	m.HandleKey("j")
+	assert.Equal(t, "web-main", selectedID(m), "archived sessions are hidden and project headers skipped")
+	m.HandleKey("j")
+	assert.Equal(t, "web-main", selectedID(m))
+	m.HandleKey("g")
+	assert.Equal(t, "api-main", selectedID(m))
+}
+
+func TestModel_TogglesArchived(t *testing.T) {
+	m := newFixtureModel()
+	assert.NotContains(t, m.View(), "api-old")
+
+	m.HandleKey("h")
+	assert.Contains(t, m.View(), "api-old")
+	m.HandleKey("G")
+	m.HandleKey("k")
+	assert.Equal(t, "api-old", selectedID(m))
+
+	m.HandleKey("h")
+	assert.Equal(t, "api-main", selectedID(m), "a hidden selection moves to the first session")
+}
+
+func TestModel_View(t *testing.T) {
+	m := newFixtureModel()
+	view := m.View()
+
+	assert.Contains(t, view, "2 project(s) · 4 session(s) · 1 running")
+	assert.Contains(t, view, "api  /work/api")
+	assert.Contains(t, view, "running (pid 4242)")
+	assert.Contains(t, view, "idle")
+	assert.Contains(t, view, Help)
+}
+
+func TestModel_ResumeRefusesRunningSession(t *testing.T) {
+	m := newFixtureModel()
+
+	action := m.HandleKey(viewer.KeyEnter)
+	assert.Equal(t, ActionNone, action.Kind)
+	assert.Contains(t, m.View(), "is running in kam (pid 4242)")
+
+	m.HandleKey("j")
+	action = m.HandleKey(viewer.KeyEnter)
+	assert.Equal(t, ActionResume, action.Kind)
+	assert.Equal(t, "api-fix", action.Session.ID)
+	assert.Equal(t, "/work/api", action.Project.Path)
+}
+
+func TestModel_ConfirmsBeforeArchiving(t *testing.T) {
+	m := newFixtureModel()
+	m.HandleKey("j")
+
+	assert.Equal(t, ActionNone, m.HandleKey("a").Kind)
+	assert.Contains(t, m.View(), "Archive session 'api-fix' in api? (y/n)")
+	assert.Equal(t, ActionNone, m.HandleKey("n").Kind)
+	assert.Contains(t, m.View(), Help)
+
+	m.HandleKey("c")
+	action := m.HandleKey("y")
+	assert.Equal(t, ActionComplete, action.Kind)
+	assert.Equal(t, "api-fix", action.Session.ID)
+}
+
+func TestModel_SetSnapshotKeepsSelection(t *testing.T) {
+	m := newFixtureModel()
+	m.HandleKey("j")
+
+	m.SetSnapshot(&Snapshot{Projects: []Project{
+		{Name: "api", Path: "/work/api", Sessions: []Session{{ID: "api-fix"}, {ID: "api-main"}}},
+	}})
+	assert.Equal(t, "api-fix", selectedID(m))
+
+	m.SetSnapshot(&Snapshot{})
+	_, _, ok := m.Selected()
+	assert.False(t, ok)
+	assert.Equal(t, ActionNone, m.HandleKey(viewer.KeyEnter).Kind)
+	assert.Equal(t, ActionQuit, m.HandleKey("q").Kind)
+}
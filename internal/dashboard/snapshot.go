@@ -0,0 +1,219 @@
+// Package dashboard is the live view of every project's sessions behind kam dashboard
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/bitomule/kamui/internal/paths"
+	"github.com/bitomule/kamui/internal/pricing"
+	"github.com/bitomule/kamui/internal/stats"
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Session is one session as the dashboard shows it
+type Session struct {
+	ID           string             `json:"id"`
+	Description  string             `json:"description,omitempty"`
+	State        types.SessionState `json:"state"`
+	LastAccessed time.Time          `json:"lastAccessed"`
+	// Running is set while a kam process holds the session's lock; PID is that process
+	Running bool    `json:"running"`
+	PID     int     `json:"pid,omitempty"`
+	Tokens  int64   `json:"tokens"`
+	CostUSD float64 `json:"estimatedCostUsd"`
+}
+
+// Activity describes what the session is doing: running, or its lifecycle state
+func (s Session) Activity() string {
+	if s.Running {
+		return "running"
+	}
+	if s.State == types.SessionStateActive || s.State == "" {
+		return "idle"
+	}
+	return string(s.State)
+}
+
+// Project is a project and its sessions, running ones first, then most recently used
+type Project struct {
+	Name     string    `json:"name"`
+	Path     string    `json:"path"`
+	Sessions []Session `json:"sessions"`
+}
+
+// LastActivity returns when one of the project's sessions was last used
+func (p Project) LastActivity() time.Time {
+	var last time.Time
+	for _, session := range p.Sessions {
+		if session.LastAccessed.After(last) {
+			last = session.LastAccessed
+		}
+	}
+	return last
+}
+
+// Snapshot is the state of every session at one point in time
+// WithUsage is set when token usage was computed; reading transcripts is slow, so live refreshes
+// skip it and take usage from an earlier snapshot instead
+type Snapshot struct {
+	Generated time.Time `json:"generated"`
+	WithUsage bool      `json:"withUsage"`
+	Projects  []Project `json:"projects"`
+}
+
+// Collect reads every stored session and whether a kam process is running it
+// With a price table, token usage and estimated cost are read from the transcripts too
+// Projects are ordered by their latest activity, most recent first
+func Collect(ctx context.Context, store storage.Interface, homeDir string, prices pricing.Table, now time.Time) (*Snapshot, error) {
+	names, err := store.ListSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{Generated: now, WithUsage: prices != nil}
+	byPath := map[string]*Project{}
+	var order []string
+	for _, name := range names {
+		sessionData, err := store.LoadSession(ctx, name)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			continue // unreadable sessions are left to kam validate
+		}
+
+		entry := Session{
+			ID:           sessionData.SessionID,
+			Description:  sessionData.Metadata.Description,
+			State:        sessionData.Lifecycle.State,
+			LastAccessed: sessionData.LastAccessed,
+		}
+		if lock, lockErr := store.ReadLock(ctx, name); lockErr == nil && lock != nil && !storage.LockIsStale(lock) {
+			entry.Running, entry.PID = true, lock.PID
+		}
+		if prices != nil {
+			if estimate, costErr := stats.EstimateCost(ctx, sessionData, homeDir, prices); costErr == nil {
+				entry.Tokens, entry.CostUSD = estimate.Tokens, estimate.CostUSD
+			}
+		}
+
+		projectPath := paths.Canonical(sessionData.Project.Path)
+		project, ok := byPath[projectPath]
+		if !ok {
+			projectName := sessionData.Project.Name
+			if projectName == "" {
+				projectName = filepath.Base(projectPath)
+			}
+			project = &Project{Name: projectName, Path: projectPath}
+			byPath[projectPath] = project
+			order = append(order, projectPath)
+		}
+		project.Sessions = append(project.Sessions, entry)
+	}
+
+	for _, path := range order {
+		project := byPath[path]
+		sort.SliceStable(project.Sessions, func(i, j int) bool {
+			a, b := project.Sessions[i], project.Sessions[j]
+			if a.Running != b.Running {
+				return a.Running
+			}
+			return a.LastAccessed.After(b.LastAccessed)
+		})
+		snapshot.Projects = append(snapshot.Projects, *project)
+	}
+	sort.SliceStable(snapshot.Projects, func(i, j int) bool {
+		a, b := snapshot.Projects[i].LastActivity(), snapshot.Projects[j].LastActivity()
+		if !a.Equal(b) {
+			return a.After(b)
+		}
+		return snapshot.Projects[i].Path < snapshot.Projects[j].Path
+	})
+	return snapshot, nil
+}
+
+// MergeUsage copies token usage and cost from an earlier snapshot into sessions still present
+func (s *Snapshot) MergeUsage(from *Snapshot) {
+	if from == nil || !from.WithUsage {
+		return
+	}
+	usage := map[string]Session{}
+	for _, project := range from.Projects {
+		for _, session := range project.Sessions {
+			usage[session.ID] = session
+		}
+	}
+	for i := range s.Projects {
+		for j := range s.Projects[i].Sessions {
+			if earlier, ok := usage[s.Projects[i].Sessions[j].ID]; ok {
+				s.Projects[i].Sessions[j].Tokens = earlier.Tokens
+				s.Projects[i].Sessions[j].CostUSD = earlier.CostUSD
+			}
+		}
+	}
+	s.WithUsage = true
+}
+
+// Store keeps the latest snapshot the daemon collected, so dashboards don't each read every
+// transcript
+type Store struct {
+	path string
+}
+
+// NewStore creates a store backed by ~/.kamui/run/dashboard.json
+func NewStore() *Store {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+	return NewStoreWithPath(filepath.Join(homeDir, ".kamui", "run", "dashboard.json"))
+}
+
+// NewStoreWithPath creates a store backed by the given file
+func NewStoreWithPath(path string) *Store {
+	return &Store{path: path}
+}
+
+// Load returns the stored snapshot, or nil when there is none
+func (s *Store) Load() (*Snapshot, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStoragePermission, "failed to read dashboard snapshot", err)
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStorageCorrupted, "failed to parse dashboard snapshot", err)
+	}
+	return &snapshot, nil
+}
+
+// Save replaces the stored snapshot atomically
+func (s *Store) Save(snapshot *Snapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return types.NewStorageError(types.ErrCodeStorageCorrupted, "failed to marshal dashboard snapshot", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return types.NewStorageError(types.ErrCodeStoragePermission, "failed to create dashboard directory", err)
+	}
+
+	tempFile := s.path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0o600); err != nil {
+		return types.NewStorageError(types.ErrCodeStoragePermission, "failed to write dashboard snapshot", err)
+	}
+	if err := os.Rename(tempFile, s.path); err != nil {
+		os.Remove(tempFile) // cleanup temp file
+		return types.NewStorageError(types.ErrCodeStoragePermission, "failed to save dashboard snapshot", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package dashboard
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+var fixtureTime = time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+func saveSession(t *testing.T, store storage.Interface, id, projectPath string, state types.SessionState, lastAccessed time.Time) {
+	t.Helper()
+	session := storage.NewSession(id, projectPath, fixtureTime)
+	session.Lifecycle.State = state
+	session.LastAccessed = lastAccessed
+	require.NoError(t, store.SaveSession(context.Background(), session))
+}
+
+func TestCollect_GroupsByProject(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemory("/work/api")
+	api, web := filepath.FromSlash("/work/api"), filepath.FromSlash("/work/web")
+	saveSession(t, store, "api-old", api, types.SessionStateActive, fixtureTime)
+	saveSession(t, store, "api-new", api, types.SessionStateActive, fixtureTime.Add(2*time.Hour))
+	saveSession(t, store, "api-done", api, types.SessionStateCompleted, fixtureTime.Add(-time.Hour))
+	saveSession(t, store, "web-main", web, types.SessionStateActive, fixtureTime.Add(3*time.Hour))
+	require.NoError(t, store.LockSession(ctx, "api-old"))
+
+	snapshot, err := Collect(ctx, store, t.TempDir(), nil, fixtureTime)
+	require.NoError(t, err)
+
+	assert.Equal(t, fixtureTime, snapshot.Generated)
+	assert.False(t, snapshot.WithUsage)
+	require.Len(t, snapshot.Projects, 2)
+	assert.Equal(t, "web", snapshot.Projects[0].Name)
+
+	sessions := snapshot.Projects[1].Sessions
+	require.Len(t, sessions, 3)
+	assert.Equal(t, "api-old", sessions[0].ID, "running sessions come first")
+	assert.True(t, sessions[0].Running)
+	assert.Equal(t, "running", sessions[0].Activity())
+	assert.Equal(t, "api-new", sessions[1].ID)
+	assert.Equal(t, "idle", sessions[1].Activity())
+	assert.Equal(t, "completed", sessions[2].Activity())
+}
+
+func TestSnapshot_MergeUsage(t *testing.T) {
+	earlier := &Snapshot{WithUsage: true, Projects: []Project{{Sessions: []Session{{ID: "api", Tokens: 1200, CostUSD: 0.5}}}}}
+	snapshot := &Snapshot{Projects: []Project{{Sessions: []Session{{ID: "api", Running: true}, {ID: "new"}}}}}
+
+	snapshot.MergeUsage(earlier)
+
+	assert.True(t, snapshot.WithUsage)
+	assert.Equal(t, int64(1200), snapshot.Projects[0].Sessions[0].Tokens)
+	assert.Equal(t, 0.5, snapshot.Projects[0].Sessions[0].CostUSD)
+	assert.True(t, snapshot.Projects[0].Sessions[0].Running)
+	assert.Zero(t, snapshot.Projects[0].Sessions[1].Tokens)
+}
+
+func TestSnapshot_MergeUsageIgnoresSnapshotsWithout(t *testing.T) {
+	snapshot := &Snapshot{}
+	snapshot.MergeUsage(&Snapshot{})
+	snapshot.MergeUsage(nil)
+	assert.False(t, snapshot.WithUsage)
+}
+
+func TestStore_SaveLoad(t *testing.T) {
+	store := NewStoreWithPath(filepath.Join(t.TempDir(), "run", "dashboard.json"))
+
+	loaded, err := store.Load()
+	require.NoError(t, err)
+	assert.Nil(t, loaded)
+
+	snapshot := &Snapshot{Generated: fixtureTime, WithUsage: true, Projects: []Project{{Name: "api", Path: "/work/api", Sessions: []Session{{ID: "api", Tokens: 42}}}}}
+	require.NoError(t, store.Save(snapshot))
+
+	loaded, err = store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, snapshot, loaded)
+}
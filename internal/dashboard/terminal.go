@@ -0,0 +1,102 @@
+package dashboard
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/bitomule/kamui/internal/viewer"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Source supplies what the dashboard shows and carries out the actions taken in it
+type Source interface {
+	// Snapshot recollects every session; full also recomputes token usage
+	Snapshot(full bool) (*Snapshot, error)
+	// Apply completes or archives a session, returning a message for the status bar
+	Apply(action Action) (string, error)
+}
+
+// Run shows the model full-screen, refreshing it from source every interval, until the user
+// quits or picks a session to resume; the action that ended it is returned
+// The terminal is switched to raw mode on the alternate screen and restored afterwards
+func Run(m *Model, source Source, in, out *os.File, interval time.Duration) (Action, error) {
+	if !term.IsTerminal(int(in.Fd())) || !term.IsTerminal(int(out.Fd())) {
+		return Action{}, types.NewSessionError(types.ErrCodeInvalidInput, "the dashboard needs an interactive terminal", nil)
+	}
+
+	state, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return Action{}, fmt.Errorf("failed to enter raw mode: %w", err)
+	}
+	defer term.Restore(int(in.Fd()), state)
+
+	// Input is released before the terminal is restored, so nothing is left reading keys
+	// meant for a resumed session
+	input, timed, closeInput := openInput(in)
+	defer closeInput()
+
+	fmt.Fprint(out, "\033[?1049h\033[?25l")
+	defer fmt.Fprint(out, "\033[?25h\033[?1049l")
+
+	refresh := func(full bool) {
+		snapshot, err := source.Snapshot(full)
+		if err != nil {
+			m.SetMessage(fmt.Sprintf("Refresh failed: %v", err))
+			return
+		}
+		m.SetSnapshot(snapshot)
+	}
+
+	reader := bufio.NewReader(input)
+	for {
+		// Size is re-read on every redraw so resizes apply on the next one
+		if width, height, err := term.GetSize(int(out.Fd())); err == nil {
+			m.SetSize(width, height)
+		}
+		fmt.Fprint(out, m.View())
+
+		if timed {
+			_ = input.SetReadDeadline(time.Now().Add(interval))
+		}
+		key, err := viewer.ReadKey(reader)
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			refresh(false)
+			continue
+		}
+		if err == io.EOF {
+			return Action{Kind: ActionQuit}, nil
+		}
+		if err != nil {
+			return Action{}, err
+		}
+
+		action := m.HandleKey(key)
+		switch action.Kind {
+		case ActionQuit, ActionResume:
+			return action, nil
+		case ActionRefresh:
+			// reading every transcript takes a while
+			m.SetMessage("Refreshing token usage…")
+			fmt.Fprint(out, m.View())
+			m.SetMessage("")
+			refresh(true)
+		case ActionComplete, ActionArchive:
+			message, err := source.Apply(action)
+			refresh(false)
+			if err != nil {
+				message = fmt.Sprintf("Error: %v", err)
+			}
+			m.SetMessage(message)
+		default:
+			if !timed {
+				refresh(false)
+			}
+		}
+	}
+}
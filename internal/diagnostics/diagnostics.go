@@ -0,0 +1,268 @@
+// Package diagnostics builds sanitized bundles of Kamui's state to attach to bug reports
+//
+// Paths are replaced by short hashes, so the same directory can be recognized across files
+// without revealing it; secrets, environment values and note text are redacted.
+package diagnostics
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Redacted replaces values left out of a bundle
+const Redacted = "[redacted]"
+
+// minReplacedPath is the shortest path replaced wherever it appears in text
+const minReplacedPath = 4
+
+// secretKeys are substrings of config keys whose values are never included
+var secretKeys = []string{"token", "apikey", "password", "secret"}
+
+// Environment describes where the bundle was made
+type Environment struct {
+	KamuiVersion  string    `json:"kamuiVersion"`
+	ClaudeVersion string    `json:"claudeVersion"`
+	GoVersion     string    `json:"goVersion"`
+	OS            string    `json:"os"`
+	Arch          string    `json:"arch"`
+	Created       time.Time `json:"created"`
+}
+
+// IndexEntry summarizes one session in the bundle's index
+type IndexEntry struct {
+	SessionID    string             `json:"sessionId"`
+	Project      string             `json:"project"`
+	State        types.SessionState `json:"state"`
+	LastAccessed time.Time          `json:"lastAccessed"`
+	Bound        bool               `json:"bound"`
+}
+
+// Bundle is what a debug dump contains, already sanitized
+type Bundle struct {
+	Environment Environment
+	Config      map[string]interface{}
+	Sessions    []*types.Session
+	Skipped     map[string]string // sessions that couldn't be read, with the reason
+	Log         []string
+}
+
+// Sanitizer hashes the paths it is told about and redacts secrets
+type Sanitizer struct {
+	homeDir string
+	paths   map[string]string
+}
+
+// NewSanitizer creates a sanitizer that also shortens homeDir to ~ wherever it remains
+func NewSanitizer(homeDir string) *Sanitizer {
+	return &Sanitizer{homeDir: homeDir, paths: make(map[string]string)}
+}
+
+// HashPath returns a stable stand-in for a path and replaces it wherever it appears in text
+// sanitized afterwards
+func (s *Sanitizer) HashPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(path))
+	hashed := "path-" + hex.EncodeToString(sum[:6])
+	if len(path) < minReplacedPath {
+		return hashed // replacing a root like "/" everywhere would mangle every other path
+	}
+	s.paths[path] = hashed
+	// JSON escapes the backslashes of Windows paths
+	if escaped, err := json.Marshal(path); err == nil {
+		s.paths[strings.Trim(string(escaped), `"`)] = hashed
+	}
+	return hashed
+}
+
+// Text replaces the known paths in text with their hashes and the home directory with ~
+func (s *Sanitizer) Text(text string) string {
+	// longest first, so a path isn't partly replaced by one of its parents
+	known := make([]string, 0, len(s.paths))
+	for path := range s.paths {
+		known = append(known, path)
+	}
+	sort.Slice(known, func(i, j int) bool { return len(known[i]) > len(known[j]) })
+	for _, path := range known {
+		text = strings.ReplaceAll(text, path, s.paths[path])
+	}
+	if len(s.homeDir) >= minReplacedPath {
+		text = strings.ReplaceAll(text, s.homeDir, "~")
+	}
+	return text
+}
+
+// Session returns a sanitized copy of a session: its paths hashed, environment values, note text
+// and git remote credentials redacted
+func (s *Sanitizer) Session(session *types.Session) (*types.Session, error) {
+	s.HashPath(session.Project.Path)
+	s.HashPath(session.Project.WorkingDirectory)
+
+	// paths may appear anywhere, e.g. in resume errors, so they are replaced in the whole record
+	data, err := json.Marshal(session)
+	if err != nil {
+		return nil, err
+	}
+	var sanitized types.Session
+	if err := json.Unmarshal([]byte(s.Text(string(data))), &sanitized); err != nil {
+		return nil, err
+	}
+	for name := range sanitized.Metadata.Env {
+		sanitized.Metadata.Env[name] = Redacted
+	}
+	for i := range sanitized.Metadata.Notes {
+		sanitized.Metadata.Notes[i].Text = Redacted
+	}
+	sanitized.Project.GitRemote = withoutCredentials(sanitized.Project.GitRemote)
+	return &sanitized, nil
+}
+
+// Config returns a copy of nested settings with secret values redacted and paths sanitized
+func (s *Sanitizer) Config(settings map[string]interface{}) map[string]interface{} {
+	sanitized := make(map[string]interface{}, len(settings))
+	for key, value := range settings {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			sanitized[key] = s.Config(v)
+		case string:
+			if isSecret(key) && v != "" {
+				sanitized[key] = Redacted
+			} else {
+				sanitized[key] = s.Text(v)
+			}
+		default:
+			if isSecret(key) {
+				sanitized[key] = Redacted
+			} else {
+				sanitized[key] = v
+			}
+		}
+	}
+	return sanitized
+}
+
+// Lines sanitizes each line of a log
+func (s *Sanitizer) Lines(lines []string) []string {
+	sanitized := make([]string, len(lines))
+	for i, line := range lines {
+		sanitized[i] = s.Text(line)
+	}
+	return sanitized
+}
+
+// isSecret reports whether a config key holds a credential
+func isSecret(key string) bool {
+	key = strings.ToLower(key)
+	for _, secret := range secretKeys {
+		if strings.Contains(key, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutCredentials drops the user info from a remote URL
+func withoutCredentials(remote string) string {
+	u, err := url.Parse(remote)
+	if err != nil || u.User == nil {
+		return remote
+	}
+	u.User = nil
+	return u.String()
+}
+
+// Index summarizes the bundle's sessions, ordered by name
+func (b *Bundle) Index() []IndexEntry {
+	index := make([]IndexEntry, 0, len(b.Sessions))
+	for _, session := range b.Sessions {
+		index = append(index, IndexEntry{
+			SessionID:    session.SessionID,
+			Project:      session.Project.Path,
+			State:        session.Lifecycle.State,
+			LastAccessed: session.LastAccessed,
+			Bound:        session.Claude.SessionID != "",
+		})
+	}
+	sort.Slice(index, func(i, j int) bool { return index[i].SessionID < index[j].SessionID })
+	return index
+}
+
+// WriteTarGz writes the bundle as a gzipped tarball of JSON files and the log, returning the
+// names of the files written
+func (b *Bundle) WriteTarGz(w io.Writer) ([]string, error) {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	manifest := struct {
+		Environment Environment       `json:"environment"`
+		Sessions    int               `json:"sessions"`
+		Skipped     map[string]string `json:"skipped,omitempty"`
+	}{b.Environment, len(b.Sessions), b.Skipped}
+
+	files := []struct {
+		name  string
+		value interface{}
+	}{
+		{"manifest.json", manifest},
+		{"config.json", b.Config},
+		{"index.json", b.Index()},
+	}
+	for _, session := range b.Sessions {
+		files = append(files, struct {
+			name  string
+			value interface{}
+		}{"sessions/" + session.SessionID + ".json", session})
+	}
+
+	var names []string
+	for _, file := range files {
+		data, err := json.MarshalIndent(file.value, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if err := writeFile(tw, file.name, data, b.Environment.Created); err != nil {
+			return nil, err
+		}
+		names = append(names, file.name)
+	}
+
+	log := strings.Join(b.Log, "\n")
+	if log != "" {
+		log += "\n"
+	}
+	if err := writeFile(tw, "logs/kamui.log", []byte(log), b.Environment.Created); err != nil {
+		return nil, err
+	}
+	names = append(names, "logs/kamui.log")
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return names, gz.Close()
+}
+
+// writeFile adds a file to a tarball
+func writeFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	header := &tar.Header{
+		Name:    "kamui-debug/" + name,
+		Mode:    0o600,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
@@ -0,0 +1,143 @@
+// Package fence maintains marker-delimited blocks inside user-owned text files
+//
+// Kamui only ever rewrites the text between its begin and end markers, so the
+// rest of the file (an .envrc, a CLAUDE.md, ...) is left exactly as the user wrote it.
+package fence
+
+import (
+	"os"
+	"strings"
+)
+
+// Markers are the begin and end lines that delimit a managed block
+type Markers struct {
+	Begin string
+	End   string
+}
+
+// Upsert replaces the managed block in content with body, appending it if absent
+func Upsert(content string, markers Markers, body string) string {
+	block := markers.Begin + "\n" + strings.TrimRight(body, "\n") + "\n" + markers.End + "\n"
+
+	start, end, ok := find(content, markers)
+	if !ok {
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		if content != "" {
+			content += "\n"
+		}
+		return content + block
+	}
+
+	return content[:start] + block + content[end:]
+}
+
+// Remove deletes the managed block from content, reporting whether one was found
+func Remove(content string, markers Markers) (string, bool) {
+	start, end, ok := find(content, markers)
+	if !ok {
+		return content, false
+	}
+
+	before := strings.TrimRight(content[:start], "\n")
+	after := strings.TrimLeft(content[end:], "\n")
+	switch {
+	case before == "":
+		return after, true
+	case after == "":
+		return before + "\n", true
+	default:
+		return before + "\n\n" + after, true
+	}
+}
+
+// Extract returns the body of the managed block, if present
+func Extract(content string, markers Markers) (string, bool) {
+	start, end, ok := find(content, markers)
+	if !ok {
+		return "", false
+	}
+	block := content[start:end]
+	block = strings.TrimPrefix(block, markers.Begin+"\n")
+	block = strings.TrimSuffix(strings.TrimSuffix(block, "\n"), markers.End)
+	return strings.TrimSuffix(block, "\n"), true
+}
+
+// UpsertFile applies Upsert to a file, creating it with perm if it doesn't exist
+func UpsertFile(path string, markers Markers, body string, perm os.FileMode) error {
+	content, err := readOptional(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(Upsert(content, markers, body)), perm)
+}
+
+// RemoveFromFile applies Remove to a file, reporting whether a block was removed
+func RemoveFromFile(path string, markers Markers) (bool, error) {
+	content, err := readOptional(path)
+	if err != nil {
+		return false, err
+	}
+
+	updated, removed := Remove(content, markers)
+	if !removed {
+		return false, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(path, []byte(updated), info.Mode().Perm())
+}
+
+// find locates the managed block, returning the byte range including the end marker line
+func find(content string, markers Markers) (int, int, bool) {
+	start := indexLine(content, markers.Begin, 0)
+	if start < 0 {
+		return 0, 0, false
+	}
+
+	endMarker := indexLine(content, markers.End, start+len(markers.Begin))
+	if endMarker < 0 {
+		return 0, 0, false
+	}
+
+	end := endMarker + len(markers.End)
+	if end < len(content) && content[end] == '\n' {
+		end++
+	}
+	return start, end, true
+}
+
+// indexLine finds line as a whole line in content at or after offset
+func indexLine(content, line string, offset int) int {
+	for offset <= len(content) {
+		idx := strings.Index(content[offset:], line)
+		if idx < 0 {
+			return -1
+		}
+		pos := offset + idx
+		atLineStart := pos == 0 || content[pos-1] == '\n'
+		lineEnd := pos + len(line)
+		atLineEnd := lineEnd == len(content) || content[lineEnd] == '\n' || content[lineEnd] == '\r'
+		if atLineStart && atLineEnd {
+			return pos
+		}
+		offset = pos + 1
+	}
+	return -1
+}
+
+// readOptional reads a file, treating a missing file as empty
+func readOptional(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,81 @@
+package fence
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testMarkers = Markers{Begin: "# >>> kamui >>>", End: "# <<< kamui <<<"}
+
+func TestUpsert_AppendsToEmpty(t *testing.T) {
+	result := Upsert("", testMarkers, "export A=1")
+	assert.Equal(t, "# >>> kamui >>>\nexport A=1\n# <<< kamui <<<\n", result)
+}
+
+func TestUpsert_AppendsAfterExistingContent(t *testing.T) {
+	result := Upsert("use nix", testMarkers, "export A=1\n")
+	assert.Equal(t, "use nix\n\n# >>> kamui >>>\nexport A=1\n# <<< kamui <<<\n", result)
+}
+
+func TestUpsert_ReplacesExistingBlock(t *testing.T) {
+	content := "before\n# >>> kamui >>>\nexport A=1\n# <<< kamui <<<\nafter\n"
+
+	result := Upsert(content, testMarkers, "export A=2")
+	assert.Equal(t, "before\n# >>> kamui >>>\nexport A=2\n# <<< kamui <<<\nafter\n", result)
+
+	// Upserting again is idempotent
+	assert.Equal(t, result, Upsert(result, testMarkers, "export A=2"))
+}
+
+func TestUpsert_IgnoresMarkersInsideLines(t *testing.T) {
+	content := "echo '# >>> kamui >>>'\n"
+	result := Upsert(content, testMarkers, "x")
+	assert.Equal(t, "echo '# >>> kamui >>>'\n\n# >>> kamui >>>\nx\n# <<< kamui <<<\n", result)
+}
+
+func TestRemove(t *testing.T) {
+	content := "before\n\n# >>> kamui >>>\nexport A=1\n# <<< kamui <<<\n\nafter\n"
+
+	result, removed := Remove(content, testMarkers)
+	assert.True(t, removed)
+	assert.Equal(t, "before\n\nafter\n", result)
+
+	_, removed = Remove("nothing here\n", testMarkers)
+	assert.False(t, removed)
+
+	onlyBlock, removed := Remove("# >>> kamui >>>\nx\n# <<< kamui <<<\n", testMarkers)
+	assert.True(t, removed)
+	assert.Empty(t, onlyBlock)
+}
+
+func TestExtract(t *testing.T) {
+	body, ok := Extract("a\n# >>> kamui >>>\nline1\nline2\n# <<< kamui <<<\n", testMarkers)
+	assert.True(t, ok)
+	assert.Equal(t, "line1\nline2", body)
+
+	_, ok = Extract("a\n", testMarkers)
+	assert.False(t, ok)
+}
+
+func TestUpsertAndRemoveFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".envrc")
+
+	require.NoError(t, UpsertFile(path, testMarkers, "export A=1", 0o644))
+	require.NoError(t, UpsertFile(path, testMarkers, "export A=2", 0o644))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "# >>> kamui >>>\nexport A=2\n# <<< kamui <<<\n", string(data))
+
+	removed, err := RemoveFromFile(path, testMarkers)
+	require.NoError(t, err)
+	assert.True(t, removed)
+
+	removed, err = RemoveFromFile(filepath.Join(t.TempDir(), "missing"), testMarkers)
+	require.NoError(t, err)
+	assert.False(t, removed)
+}
@@ -0,0 +1,102 @@
+// Package gist is a minimal GitHub client for publishing files as gists
+package gist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// DefaultAPIURL is the GitHub.com REST API; GitHub Enterprise uses https://<host>/api/v3
+const DefaultAPIURL = "https://api.github.com"
+
+// Client talks to the GitHub gists API
+type Client struct {
+	apiURL     string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for apiURL (DefaultAPIURL when empty) authenticating with token
+func NewClient(apiURL, token string) (*Client, error) {
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+	parsed, err := url.Parse(apiURL)
+	if err != nil || parsed.Host == "" {
+		return nil, types.NewConfigError(
+			types.ErrCodeConfigInvalid,
+			fmt.Sprintf("github.apiUrl '%s' is not a valid URL", apiURL),
+			err,
+		)
+	}
+	if token == "" {
+		return nil, types.NewConfigError(
+			types.ErrCodeConfigInvalid,
+			"no GitHub token: set github.token, GITHUB_TOKEN or GH_TOKEN",
+			nil,
+		)
+	}
+
+	return &Client{
+		apiURL:     strings.TrimRight(apiURL, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Create uploads a single-file gist and returns its browser URL; secret gists are unlisted,
+// so only people with the link can find them
+func (c *Client) Create(ctx context.Context, description, filename, content string, public bool) (string, error) {
+	request := map[string]interface{}{
+		"description": description,
+		"public":      public,
+		"files":       map[string]map[string]string{filename: {"content": content}},
+	}
+	data, err := json.Marshal(request)
+	if err != nil {
+		return "", apiError("failed to encode gist request", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL+"/gists", bytes.NewReader(data))
+	if err != nil {
+		return "", apiError("failed to build gist request", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", apiError("failed to reach GitHub", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", apiError(fmt.Sprintf("GitHub returned %s creating the gist", resp.Status), fmt.Errorf("%s", strings.TrimSpace(string(detail))))
+	}
+
+	var response struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", apiError("failed to parse GitHub response", err)
+	}
+	if response.HTMLURL == "" {
+		return "", apiError("GitHub response has no gist URL", nil)
+	}
+	return response.HTMLURL, nil
+}
+
+func apiError(message string, cause error) *types.AGXError {
+	return &types.AGXError{Code: types.ErrCodeDependencyFailed, Message: message, Cause: cause}
+}
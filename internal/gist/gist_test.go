@@ -0,0 +1,90 @@
+package gist
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestNewClient_RequiresToken(t *testing.T) {
+	_, err := NewClient("", "")
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeConfigInvalid, agxErr.Code)
+
+	_, err = NewClient("not a url", "secret")
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeConfigInvalid, agxErr.Code)
+
+	client, err := NewClient("", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultAPIURL, client.apiURL)
+}
+
+func TestCreate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/gists", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		var body struct {
+			Description string                       `json:"description"`
+			Public      bool                         `json:"public"`
+			Files       map[string]map[string]string `json:"files"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "demo: Fix login", body.Description)
+		assert.False(t, body.Public)
+		assert.Equal(t, "# demo", body.Files["demo.md"]["content"])
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"abc","html_url":"https://gist.github.com/me/abc"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL+"/", "secret")
+	require.NoError(t, err)
+
+	link, err := client.Create(context.Background(), "demo: Fix login", "demo.md", "# demo", false)
+	require.NoError(t, err)
+	assert.Equal(t, "https://gist.github.com/me/abc", link)
+}
+
+func TestCreate_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"message":"Bad credentials"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "wrong")
+	require.NoError(t, err)
+
+	_, err = client.Create(context.Background(), "demo", "demo.md", "# demo", false)
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeDependencyFailed, agxErr.Code)
+	assert.Contains(t, agxErr.Error(), "Bad credentials")
+}
+
+func TestCreate_Canceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no request should be sent once the context is canceled")
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "secret")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.Create(ctx, "demo", "demo.md", "# demo", false)
+	require.ErrorIs(t, err, context.Canceled)
+}
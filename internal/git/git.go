@@ -0,0 +1,64 @@
+// Package git reads repository information with the git CLI
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/bitomule/kamui/internal/trace"
+)
+
+// Commit is a single commit from the log
+type Commit struct {
+	Hash    string
+	Author  string
+	Date    time.Time
+	Subject string
+}
+
+// fieldSeparator separates log fields; it can't appear in subjects
+const fieldSeparator = "\x1f"
+
+// CommitsBetween returns the commits in dir's repository authored between since and until, newest first
+// It returns no commits when dir is not inside a git repository
+func CommitsBetween(dir string, since, until time.Time) ([]Commit, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, nil
+	}
+
+	cmd := exec.Command("git", "log",
+		"--since="+since.Format(time.RFC3339),
+		"--until="+until.Format(time.RFC3339),
+		"--format=%h"+fieldSeparator+"%an"+fieldSeparator+"%aI"+fieldSeparator+"%s",
+	)
+	cmd.Dir = dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := trace.Output(cmd)
+	if err != nil {
+		if strings.Contains(stderr.String(), "not a git repository") || strings.Contains(stderr.String(), "does not have any commits") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("git log failed: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	return parseLog(string(output)), nil
+}
+
+// parseLog parses git log output in the CommitsBetween format
+func parseLog(output string) []Commit {
+	var commits []Commit
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(line, fieldSeparator, 4)
+		if len(fields) != 4 {
+			continue
+		}
+		date, _ := time.Parse(time.RFC3339, fields[2])
+		commits = append(commits, Commit{Hash: fields[0], Author: fields[1], Date: date, Subject: fields[3]})
+	}
+	return commits
+}
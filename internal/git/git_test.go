@@ -0,0 +1,65 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runGit(t *testing.T, dir string, env []string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+}
+
+func TestParseLog(t *testing.T) {
+	output := "abc123\x1fAda\x1f2025-03-10T09:30:00Z\x1fFix login\x1fwith separator\nbroken line\n"
+
+	commits := parseLog(output)
+	require.Len(t, commits, 1)
+	assert.Equal(t, "abc123", commits[0].Hash)
+	assert.Equal(t, "Ada", commits[0].Author)
+	assert.Equal(t, time.Date(2025, 3, 10, 9, 30, 0, 0, time.UTC), commits[0].Date)
+	assert.Equal(t, "Fix login\x1fwith separator", commits[0].Subject)
+}
+
+func TestCommitsBetween(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	runGit(t, dir, nil, "init", "-q")
+	commitAt := func(message, date string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte(message), 0o644))
+		runGit(t, dir, nil, "add", ".")
+		runGit(t, dir, []string{
+			"GIT_AUTHOR_NAME=Ada", "GIT_AUTHOR_EMAIL=ada@example.com", "GIT_AUTHOR_DATE=" + date,
+			"GIT_COMMITTER_NAME=Ada", "GIT_COMMITTER_EMAIL=ada@example.com", "GIT_COMMITTER_DATE=" + date,
+		}, "commit", "-q", "-m", message)
+	}
+	commitAt("before", "2025-03-09T12:00:00Z")
+	commitAt("during", "2025-03-10T09:30:00Z")
+	commitAt("after", "2025-03-11T12:00:00Z")
+
+	commits, err := CommitsBetween(dir,
+		time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, commits, 1)
+	assert.Equal(t, "during", commits[0].Subject)
+}
+
+func TestCommitsBetween_NotARepository(t *testing.T) {
+	commits, err := CommitsBetween(t.TempDir(), time.Now().Add(-time.Hour), time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, commits)
+}
@@ -0,0 +1,218 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitomule/kamui/internal/trace"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// State is where a repository's working tree stands
+type State struct {
+	Root   string
+	Commit string
+	// Branch is empty on a detached HEAD
+	Branch string
+}
+
+// CurrentState returns the state of the repository containing dir
+// It returns nil when dir is not inside a git repository with commits
+func CurrentState(ctx context.Context, dir string) (*State, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, nil
+	}
+
+	root, err := run(ctx, dir, nil, "rev-parse", "--show-toplevel")
+	if err != nil {
+		if ctxErr := types.ContextError(ctx); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, nil // not a repository
+	}
+	commit, err := run(ctx, root, nil, "rev-parse", "--verify", "-q", "HEAD")
+	if err != nil {
+		if ctxErr := types.ContextError(ctx); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, nil // no commits yet
+	}
+	branch, _ := run(ctx, root, nil, "symbolic-ref", "-q", "--short", "HEAD")
+
+	return &State{Root: root, Commit: commit, Branch: branch}, nil
+}
+
+// Diff returns the uncommitted changes to tracked files, staged or not, as a binary patch
+// Untracked files are not included
+func Diff(ctx context.Context, root string) ([]byte, error) {
+	patch, err := runRaw(ctx, root, nil, "diff", "--binary", "HEAD")
+	if err != nil || len(patch) == 0 {
+		return nil, err
+	}
+	return patch, nil
+}
+
+// RestoreTree makes the tracked files and the index match commit, deleting tracked files commit
+// doesn't have; the branch and untracked files are left alone
+func RestoreTree(ctx context.Context, root, commit string) error {
+	_, err := run(ctx, root, nil, "restore", "--source="+commit, "--staged", "--worktree", "--", ":/")
+	return err
+}
+
+// ApplyPatch applies a patch made by Diff to the working tree
+func ApplyPatch(ctx context.Context, root string, patch []byte) error {
+	_, err := runRaw(ctx, root, bytes.NewReader(patch), "apply", "--binary", "-")
+	return err
+}
+
+// HasChanges reports whether the working tree differs from HEAD, counting untracked files
+func HasChanges(ctx context.Context, root string) (bool, error) {
+	status, err := run(ctx, root, nil, "status", "--porcelain", "--untracked-files=normal")
+	return status != "", err
+}
+
+// CommitAll commits every change in the working tree, untracked files included, to the current
+// branch and returns the new commit
+func CommitAll(ctx context.Context, root, message string) (string, error) {
+	if _, err := run(ctx, root, nil, "add", "-A"); err != nil {
+		return "", err
+	}
+	if _, err := run(ctx, root, strings.NewReader(message), "commit", "-q", "-F", "-"); err != nil {
+		return "", err
+	}
+	return run(ctx, root, nil, "rev-parse", "HEAD")
+}
+
+// CommitToBranch commits the working tree, untracked files included, on top of branch (created
+// from HEAD if needed) and returns the new commit; HEAD, the index and the files are left alone
+func CommitToBranch(ctx context.Context, root, branch, message string) (string, error) {
+	ref := "refs/heads/" + branch
+	if _, err := run(ctx, root, nil, "check-ref-format", ref); err != nil {
+		return "", fmt.Errorf("invalid branch name %q", branch)
+	}
+	parent, err := run(ctx, root, nil, "rev-parse", "--verify", "-q", ref)
+	if err != nil {
+		if parent, err = run(ctx, root, nil, "rev-parse", "HEAD"); err != nil {
+			return "", err
+		}
+	}
+
+	// Stage into a scratch index so the user's index is untouched
+	scratch, err := os.MkdirTemp("", "kamui-index-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(scratch)
+	env := []string{"GIT_INDEX_FILE=" + filepath.Join(scratch, "index")}
+
+	if _, err := runEnv(ctx, root, env, nil, "read-tree", "HEAD"); err != nil {
+		return "", err
+	}
+	if _, err := runEnv(ctx, root, env, nil, "add", "-A"); err != nil {
+		return "", err
+	}
+	tree, err := runEnv(ctx, root, env, nil, "write-tree")
+	if err != nil {
+		return "", err
+	}
+	commit, err := run(ctx, root, strings.NewReader(message), "commit-tree", tree, "-p", parent, "-F", "-")
+	if err != nil {
+		return "", err
+	}
+	if _, err := run(ctx, root, nil, "update-ref", "-m", "kamui checkpoint", ref, commit); err != nil {
+		return "", err
+	}
+	return commit, nil
+}
+
+// MainWorktree returns the main working tree of the repository when root is a linked worktree
+// (see git worktree), or "" when root is the main working tree itself
+func MainWorktree(ctx context.Context, root string) (string, error) {
+	gitDir, err := run(ctx, root, nil, "rev-parse", "--git-dir")
+	if err != nil {
+		return "", err
+	}
+	commonDir, err := run(ctx, root, nil, "rev-parse", "--git-common-dir")
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(root, gitDir)
+	}
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(root, commonDir)
+	}
+	if filepath.Clean(gitDir) == filepath.Clean(commonDir) {
+		return "", nil
+	}
+	return filepath.Dir(filepath.Clean(commonDir)), nil
+}
+
+// RemoveWorktree removes a linked worktree of the repository at root; git refuses when the
+// worktree has uncommitted changes
+func RemoveWorktree(ctx context.Context, root, worktree string) error {
+	_, err := run(ctx, root, nil, "worktree", "remove", worktree)
+	return err
+}
+
+// BranchMerged reports whether a branch exists and, if so, whether HEAD already contains it
+func BranchMerged(ctx context.Context, root, branch string) (exists, merged bool, err error) {
+	if _, err := run(ctx, root, nil, "rev-parse", "--verify", "-q", "refs/heads/"+branch); err != nil {
+		return false, false, types.ContextError(ctx)
+	}
+	listed, err := run(ctx, root, nil, "branch", "--list", "--merged", "HEAD", branch)
+	if err != nil {
+		return true, false, err
+	}
+	return true, listed != "", nil
+}
+
+// DeleteBranch deletes a branch merged into HEAD; git refuses unmerged branches
+func DeleteBranch(ctx context.Context, root, branch string) error {
+	_, err := run(ctx, root, nil, "branch", "-q", "-d", branch)
+	return err
+}
+
+// run runs git in dir and returns its output without the trailing newline
+func run(ctx context.Context, dir string, stdin io.Reader, args ...string) (string, error) {
+	return runEnv(ctx, dir, nil, stdin, args...)
+}
+
+// runEnv runs git in dir with extra environment variables and returns its output without the trailing newline
+func runEnv(ctx context.Context, dir string, env []string, stdin io.Reader, args ...string) (string, error) {
+	output, err := runRawEnv(ctx, dir, env, stdin, args...)
+	return strings.TrimRight(string(output), "\n"), err
+}
+
+// runRaw runs git in dir and returns its output, or an error carrying git's message
+func runRaw(ctx context.Context, dir string, stdin io.Reader, args ...string) ([]byte, error) {
+	return runRawEnv(ctx, dir, nil, stdin, args...)
+}
+
+// runRawEnv runs git in dir with extra environment variables and returns its output, or an error
+// carrying git's message
+func runRawEnv(ctx context.Context, dir string, env []string, stdin io.Reader, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Stdin = stdin
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := trace.Output(cmd)
+	if err != nil {
+		if ctxErr := types.ContextError(ctx); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, fmt.Errorf("git %s failed: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
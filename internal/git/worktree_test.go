@@ -0,0 +1,205 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// commitEnv makes test commits independent of the user's git configuration
+var commitEnv = []string{
+	"GIT_AUTHOR_NAME=Ada", "GIT_AUTHOR_EMAIL=ada@example.com",
+	"GIT_COMMITTER_NAME=Ada", "GIT_COMMITTER_EMAIL=ada@example.com",
+}
+
+func TestWorkingTreeRoundTrip(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	runGit(t, dir, nil, "init", "-q", "-b", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one\n"), 0o644))
+	runGit(t, dir, nil, "add", ".")
+	runGit(t, dir, commitEnv, "commit", "-q", "-m", "first")
+
+	sub := filepath.Join(dir, "sub")
+	require.NoError(t, os.Mkdir(sub, 0o755))
+	state, err := CurrentState(ctx, sub)
+	require.NoError(t, err)
+	require.NotNil(t, state)
+	root, err := filepath.EvalSymlinks(dir)
+	require.NoError(t, err)
+	assert.Equal(t, root, state.Root)
+	assert.Equal(t, "main", state.Branch)
+	assert.Len(t, state.Commit, 40)
+
+	patch, err := Diff(ctx, state.Root)
+	require.NoError(t, err)
+	assert.Empty(t, patch)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one\ntwo\n"), 0o644))
+	patch, err = Diff(ctx, state.Root)
+	require.NoError(t, err)
+	assert.Contains(t, string(patch), "+two")
+
+	// a later commit and more edits are undone by restoring the commit and reapplying the patch
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.txt"), []byte("new\n"), 0o644))
+	runGit(t, dir, nil, "add", ".")
+	runGit(t, dir, commitEnv, "commit", "-q", "-m", "second")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("three\n"), 0o644))
+
+	require.NoError(t, RestoreTree(ctx, state.Root, state.Commit))
+	assert.NoFileExists(t, filepath.Join(dir, "b.txt"))
+	require.NoError(t, ApplyPatch(ctx, state.Root, patch))
+	data, err := os.ReadFile(filepath.Join(dir, "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "one\ntwo\n", string(data))
+}
+
+func TestCurrentState_NotARepository(t *testing.T) {
+	state, err := CurrentState(context.Background(), t.TempDir())
+	require.NoError(t, err)
+	assert.Nil(t, state)
+}
+
+func TestCommitAll(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	ctx := context.Background()
+	t.Setenv("GIT_AUTHOR_NAME", "Ada")
+	t.Setenv("GIT_AUTHOR_EMAIL", "ada@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Ada")
+	t.Setenv("GIT_COMMITTER_EMAIL", "ada@example.com")
+
+	dir := t.TempDir()
+	runGit(t, dir, nil, "init", "-q", "-b", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one\n"), 0o644))
+	runGit(t, dir, nil, "add", ".")
+	runGit(t, dir, nil, "commit", "-q", "-m", "first")
+
+	changed, err := HasChanges(ctx, dir)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new.txt"), []byte("new\n"), 0o644))
+	changed, err = HasChanges(ctx, dir)
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	commit, err := CommitAll(ctx, dir, "checkpoint\n\nbody")
+	require.NoError(t, err)
+	state, err := CurrentState(ctx, dir)
+	require.NoError(t, err)
+	assert.Equal(t, commit, state.Commit)
+	changed, err = HasChanges(ctx, dir)
+	require.NoError(t, err)
+	assert.False(t, changed)
+}
+
+func TestCommitToBranch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	ctx := context.Background()
+	t.Setenv("GIT_AUTHOR_NAME", "Ada")
+	t.Setenv("GIT_AUTHOR_EMAIL", "ada@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Ada")
+	t.Setenv("GIT_COMMITTER_EMAIL", "ada@example.com")
+
+	dir := t.TempDir()
+	runGit(t, dir, nil, "init", "-q", "-b", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one\n"), 0o644))
+	runGit(t, dir, nil, "add", ".")
+	runGit(t, dir, nil, "commit", "-q", "-m", "first")
+	before, err := CurrentState(ctx, dir)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("two\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new.txt"), []byte("new\n"), 0o644))
+	first, err := CommitToBranch(ctx, dir, "kamui/api", "checkpoint 1")
+	require.NoError(t, err)
+	second, err := CommitToBranch(ctx, dir, "kamui/api", "checkpoint 2")
+	require.NoError(t, err)
+
+	// HEAD, the branch and the changes in the working tree are untouched
+	after, err := CurrentState(ctx, dir)
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+	status, err := run(ctx, dir, nil, "status", "--porcelain")
+	require.NoError(t, err)
+	assert.Equal(t, " M a.txt\n?? new.txt", status)
+
+	// the checkpoints chain on the session branch and hold the whole working tree
+	parent, err := run(ctx, dir, nil, "rev-parse", second+"^")
+	require.NoError(t, err)
+	assert.Equal(t, first, parent)
+	parent, err = run(ctx, dir, nil, "rev-parse", first+"^")
+	require.NoError(t, err)
+	assert.Equal(t, before.Commit, parent)
+	content, err := run(ctx, dir, nil, "show", "kamui/api:new.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "new", content)
+
+	_, err = CommitToBranch(ctx, dir, "bad..name", "x")
+	assert.Error(t, err)
+}
+
+func TestWorktreesAndBranches(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	runGit(t, dir, nil, "init", "-q", "-b", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one\n"), 0o644))
+	runGit(t, dir, nil, "add", ".")
+	runGit(t, dir, commitEnv, "commit", "-q", "-m", "first")
+	root, err := filepath.EvalSymlinks(dir)
+	require.NoError(t, err)
+
+	mainTree, err := MainWorktree(ctx, root)
+	require.NoError(t, err)
+	assert.Empty(t, mainTree)
+
+	linked := filepath.Join(root, "..", filepath.Base(root)+"-feature")
+	runGit(t, dir, nil, "worktree", "add", "-q", "-b", "feature", linked)
+	t.Cleanup(func() { os.RemoveAll(linked) })
+	state, err := CurrentState(ctx, linked)
+	require.NoError(t, err)
+	mainTree, err = MainWorktree(ctx, state.Root)
+	require.NoError(t, err)
+	assert.Equal(t, root, mainTree)
+
+	exists, merged, err := BranchMerged(ctx, root, "feature")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.True(t, merged)
+	exists, _, err = BranchMerged(ctx, root, "missing")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.NoError(t, os.WriteFile(filepath.Join(linked, "b.txt"), []byte("new\n"), 0o644))
+	runGit(t, linked, nil, "add", ".")
+	runGit(t, linked, commitEnv, "commit", "-q", "-m", "second")
+	_, merged, err = BranchMerged(ctx, root, "feature")
+	require.NoError(t, err)
+	assert.False(t, merged)
+
+	require.NoError(t, RemoveWorktree(ctx, root, state.Root))
+	assert.NoDirExists(t, linked)
+	assert.Error(t, DeleteBranch(ctx, root, "feature"), "unmerged branches are kept")
+	runGit(t, dir, commitEnv, "merge", "-q", "feature")
+	require.NoError(t, DeleteBranch(ctx, root, "feature"))
+	exists, _, err = BranchMerged(ctx, root, "feature")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
@@ -0,0 +1,370 @@
+// Package index maintains the global session index, a summary of every session kept in
+// ~/.claude/kamui-index.json so sessions can be listed across projects without reading each
+// session file
+package index
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/bitomule/kamui/internal/stats"
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// FormatVersion is the index file format written by the store
+const FormatVersion = "1.0.0"
+
+// DefaultSyncInterval is how long the index is trusted before a listing resyncs it
+// (storage.indexSyncInterval)
+const DefaultSyncInterval = 5 * time.Minute
+
+// DefaultPath returns ~/.claude/kamui-index.json
+func DefaultPath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+	return filepath.Join(homeDir, ".claude", "kamui-index.json")
+}
+
+// lockTimeout bounds how long an update waits for another kam to finish updating the index
+const lockTimeout = 2 * time.Second
+
+// Store reads and writes the index file
+// Entries are updated one at a time as sessions are saved and deleted; Sync rebuilds the index
+// from storage, repairing whatever those updates missed, and recomputes its statistics. Updates
+// hold a lock file next to the index so concurrent kam processes don't lose each other's entries.
+type Store struct {
+	path string
+}
+
+// NewStore creates a store backed by DefaultPath
+func NewStore() *Store {
+	return NewStoreWithPath(DefaultPath())
+}
+
+// NewStoreWithPath creates a store backed by the given file
+func NewStoreWithPath(path string) *Store {
+	return &Store{path: path}
+}
+
+// Path returns the index file
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Load reads the index; a missing file is an empty index that needs a sync
+func (s *Store) Load() (*types.GlobalIndex, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return &types.GlobalIndex{Version: FormatVersion}, nil
+	}
+	if err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStoragePermission, "failed to read session index", err)
+	}
+
+	var index types.GlobalIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStorageCorrupted, "failed to parse session index", err)
+	}
+	return &index, nil
+}
+
+// Put adds or refreshes a session's entry; sessionFile is where storage keeps the session, ""
+// when it isn't on this machine
+func (s *Store) Put(session *types.Session, sessionFile string) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	index, err := s.loadForUpdate()
+	if err != nil {
+		return err
+	}
+
+	entry := NewEntry(session, sessionFile)
+	for i := range index.Sessions {
+		if index.Sessions[i].SessionID == entry.SessionID {
+			index.Sessions[i] = entry
+			return s.save(index)
+		}
+	}
+	index.Sessions = append(index.Sessions, entry)
+	return s.save(index)
+}
+
+// Remove drops a session's entry
+func (s *Store) Remove(sessionID string) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	index, err := s.loadForUpdate()
+	if err != nil {
+		return err
+	}
+
+	kept := index.Sessions[:0]
+	for _, entry := range index.Sessions {
+		if entry.SessionID != sessionID {
+			kept = append(kept, entry)
+		}
+	}
+	if len(kept) == len(index.Sessions) {
+		return nil
+	}
+	index.Sessions = kept
+	return s.save(index)
+}
+
+// SyncResult lists what a sync changed, by session name
+type SyncResult struct {
+	Added   []string
+	Updated []string
+	// Removed are stale entries for sessions storage no longer has
+	Removed []string
+	// Unreadable sessions keep their previous entry, if they had one
+	Unreadable []string
+	// Rebuilt is set when the index file couldn't be parsed and was started over
+	Rebuilt bool
+}
+
+// Changed reports whether the sync changed any entry
+func (r *SyncResult) Changed() bool {
+	return r.Rebuilt || len(r.Added)+len(r.Updated)+len(r.Removed) > 0
+}
+
+// Sync rebuilds the index from every session in store: missing sessions are added, outdated
+// entries refreshed and stale ones removed, and the statistics are recomputed by internal/stats.
+// The index records now as its last sync and interval as how long it is trusted after that.
+func (s *Store) Sync(ctx context.Context, store storage.Interface, interval time.Duration, now time.Time) (*types.GlobalIndex, *SyncResult, error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
+	result := &SyncResult{}
+	index, err := s.Load()
+	var agxErr *types.AGXError
+	if errors.As(err, &agxErr) && agxErr.Code == types.ErrCodeStorageCorrupted {
+		index, result.Rebuilt = &types.GlobalIndex{}, true
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	names, err := store.ListSessions(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	previous := make(map[string]types.IndexedSession, len(index.Sessions))
+	for _, entry := range index.Sessions {
+		previous[entry.SessionID] = entry
+	}
+
+	homeDir, _ := os.UserHomeDir()
+	sessions := make([]types.IndexedSession, 0, len(names))
+	usages := make([]stats.SessionUsage, 0, len(names))
+	for _, name := range names {
+		old, indexed := previous[name]
+		delete(previous, name)
+
+		session, err := store.LoadSession(ctx, name)
+		if err != nil {
+			if ctxErr := types.ContextError(ctx); ctxErr != nil {
+				return nil, nil, ctxErr
+			}
+			result.Unreadable = append(result.Unreadable, name)
+			if indexed {
+				sessions = append(sessions, old)
+			}
+			continue
+		}
+
+		usages = append(usages, stats.CollectSession(ctx, store, session, homeDir, nil))
+		entry := NewEntry(session, SessionFile(store, name))
+		switch {
+		case !indexed:
+			result.Added = append(result.Added, name)
+		case !reflect.DeepEqual(old, entry):
+			result.Updated = append(result.Updated, name)
+		}
+		sessions = append(sessions, entry)
+	}
+	for name := range previous {
+		result.Removed = append(result.Removed, name)
+	}
+	for _, names := range [][]string{result.Added, result.Updated, result.Removed, result.Unreadable} {
+		sort.Strings(names)
+	}
+
+	index.Version = FormatVersion
+	index.Sessions = sessions
+	index.LastSync = now
+	index.SyncInterval = types.Duration(interval)
+	lastCleanup := index.Statistics.LastCleanup
+	index.Statistics = stats.Aggregate(usages).Index
+	index.Statistics.LastCleanup = lastCleanup
+	if err := s.save(index); err != nil {
+		return nil, nil, err
+	}
+	return index, result, nil
+}
+
+// NewEntry summarizes a session for the index
+func NewEntry(session *types.Session, sessionFile string) types.IndexedSession {
+	projectName := session.Project.Name
+	if projectName == "" && session.Project.Path != "" {
+		projectName = filepath.Base(session.Project.Path)
+	}
+	return types.IndexedSession{
+		SessionID:   session.SessionID,
+		ProjectName: projectName,
+		ProjectPath: session.Project.Path,
+		SessionFile: sessionFile,
+		Variant:     session.Metadata.Variant,
+		Status: types.IndexStatus{
+			IsActive:     session.Lifecycle.State == types.SessionStateActive,
+			LastAccessed: session.LastAccessed,
+			State:        session.Lifecycle.State,
+		},
+		Runtime: types.RuntimeInfo{
+			ClaudeActive:    session.Claude.HasActiveContext,
+			ClaudeSessionID: session.Claude.SessionID,
+		},
+		Git: types.GitInfo{
+			Branch: session.Project.GitBranch,
+			Commit: session.Project.GitCommit,
+		},
+		Metadata: types.IndexMeta{
+			Description: session.Metadata.Description,
+			Tags:        session.Metadata.Tags,
+			Created:     session.Created,
+		},
+	}
+}
+
+// SessionFile returns the file store keeps a session in, or "" for backends off this machine
+func SessionFile(store storage.Interface, sessionID string) string {
+	dir := store.GetSessionsPath()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, sessionID+".json")
+}
+
+// loadForUpdate loads the index for a single-entry update, starting over when the file is
+// corrupted; the next sync fills it in again
+func (s *Store) loadForUpdate() (*types.GlobalIndex, error) {
+	index, err := s.Load()
+	var agxErr *types.AGXError
+	if errors.As(err, &agxErr) && agxErr.Code == types.ErrCodeStorageCorrupted {
+		return &types.GlobalIndex{Version: FormatVersion}, nil
+	}
+	return index, err
+}
+
+// lock takes the index lock, a file next to the index recording its holder like a session lock
+// It waits up to lockTimeout for another kam to release it, and takes over a lock whose holder
+// no longer runs. The returned func releases it.
+func (s *Store) lock() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStoragePermission, "failed to create session index directory", err)
+	}
+	hostname, _ := os.Hostname()
+	data, err := json.Marshal(types.SessionLock{PID: os.Getpid(), Host: hostname, Acquired: time.Now()})
+	if err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStorageCorrupted, "failed to marshal session index lock", err)
+	}
+
+	lockPath := s.path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		file, err := os.OpenFile(lockPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if err == nil {
+			_, err = file.Write(data)
+			file.Close()
+			if err != nil {
+				os.Remove(lockPath)
+				return nil, types.NewStorageError(types.ErrCodeStoragePermission, "failed to write session index lock", err)
+			}
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, types.NewStorageError(types.ErrCodeStoragePermission, "failed to create session index lock", err)
+		}
+
+		holder, abandoned := readLock(lockPath)
+		if holder == nil {
+			continue // released meanwhile
+		}
+		if abandoned {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, types.NewStorageError(types.ErrCodeStorageLocked,
+				fmt.Sprintf("the session index is being updated by kam (pid %d on %s)", holder.PID, holder.Host), nil).
+				WithContext("pid", holder.PID).
+				WithContext("host", holder.Host)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// readLock reads the index lock, nil once it is released, and reports whether it was abandoned:
+// its holder no longer runs, or it stayed unreadable longer than a holder takes to write it
+func readLock(lockPath string) (*types.SessionLock, bool) {
+	info, err := os.Stat(lockPath)
+	if err != nil {
+		return nil, false
+	}
+	var holder types.SessionLock
+	data, err := os.ReadFile(lockPath)
+	if err != nil || json.Unmarshal(data, &holder) != nil {
+		return &holder, time.Since(info.ModTime()) > lockTimeout
+	}
+	return &holder, storage.LockIsStale(&holder)
+}
+
+// save sorts the entries and writes the index atomically
+func (s *Store) save(index *types.GlobalIndex) error {
+	sort.Slice(index.Sessions, func(i, j int) bool {
+		a, b := index.Sessions[i], index.Sessions[j]
+		if a.ProjectPath != b.ProjectPath {
+			return a.ProjectPath < b.ProjectPath
+		}
+		return a.SessionID < b.SessionID
+	})
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return types.NewStorageError(types.ErrCodeStoragePermission, "failed to create session index directory", err)
+	}
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return types.NewStorageError(types.ErrCodeStorageCorrupted, "failed to marshal session index", err)
+	}
+
+	tempFile := s.path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0o600); err != nil {
+		return types.NewStorageError(types.ErrCodeStoragePermission, "failed to write session index", err)
+	}
+	if err := os.Rename(tempFile, s.path); err != nil {
+		_ = os.Remove(tempFile)
+		return types.NewStorageError(types.ErrCodeStoragePermission, "failed to replace session index", err)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package index
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+var fixtureTime = time.Date(2026, 3, 14, 9, 30, 0, 0, time.UTC)
+
+func newFixtureSession(name, projectPath string) *types.Session {
+	session := storage.NewSession(name, projectPath, fixtureTime)
+	session.Project.Name = filepath.Base(projectPath)
+	session.Project.GitBranch = "main"
+	session.Metadata.Description = "Work on " + name
+	session.Metadata.Tags = []string{"auth"}
+	return session
+}
+
+func sessionIDs(index *types.GlobalIndex) []string {
+	ids := make([]string, 0, len(index.Sessions))
+	for _, entry := range index.Sessions {
+		ids = append(ids, entry.SessionID)
+	}
+	return ids
+}
+
+func TestStore_PutAndRemove(t *testing.T) {
+	store := NewStoreWithPath(filepath.Join(t.TempDir(), "kamui-index.json"))
+
+	index, err := store.Load()
+	require.NoError(t, err)
+	assert.Empty(t, index.Sessions)
+	assert.True(t, index.NeedsSync(fixtureTime), "a missing index needs a sync")
+
+	require.NoError(t, store.Put(newFixtureSession("login-fix", "/work/web"), "/sessions/login-fix.json"))
+	require.NoError(t, store.Put(newFixtureSession("parser", "/work/api"), ""))
+	updated := newFixtureSession("login-fix", "/work/web")
+	updated.Lifecycle.State = types.SessionStateCompleted
+	require.NoError(t, store.Put(updated, "/sessions/login-fix.json"))
+
+	index, err = store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"parser", "login-fix"}, sessionIDs(index), "entries are ordered by project")
+	entry := index.Sessions[1]
+	assert.Equal(t, "web", entry.ProjectName)
+	assert.Equal(t, "/sessions/login-fix.json", entry.SessionFile)
+	assert.Equal(t, types.SessionStateCompleted, entry.Status.State)
+	assert.False(t, entry.Status.IsActive)
+	assert.Equal(t, "main", entry.Git.Branch)
+	assert.Equal(t, []string{"auth"}, entry.Metadata.Tags)
+
+	require.NoError(t, store.Remove("login-fix"))
+	require.NoError(t, store.Remove("missing"))
+	index, err = store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"parser"}, sessionIDs(index))
+}
+
+func TestStore_SyncRepairsStaleEntries(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	sessionsDir := filepath.Join(dir, "sessions")
+	sessions := storage.NewWithSessionsDir("/work/api", sessionsDir)
+	require.NoError(t, sessions.Initialize(ctx))
+	for _, name := range []string{"added", "changed", "unchanged"} {
+		require.NoError(t, sessions.SaveSession(ctx, newFixtureSession(name, "/work/api")))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(sessionsDir, "broken.json"), []byte("{"), 0o600))
+
+	store := NewStoreWithPath(filepath.Join(dir, "kamui-index.json"))
+	require.NoError(t, store.Put(newFixtureSession("changed", "/work/old"), ""))
+	require.NoError(t, store.Put(newFixtureSession("unchanged", "/work/api"), SessionFile(sessions, "unchanged")))
+	require.NoError(t, store.Put(newFixtureSession("broken", "/work/api"), ""))
+	require.NoError(t, store.Put(newFixtureSession("deleted", "/work/api"), ""))
+
+	index, result, err := store.Sync(ctx, sessions, 5*time.Minute, fixtureTime)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"added"}, result.Added)
+	assert.Equal(t, []string{"changed"}, result.Updated)
+	assert.Equal(t, []string{"deleted"}, result.Removed)
+	assert.Equal(t, []string{"broken"}, result.Unreadable)
+	assert.True(t, result.Changed())
+	assert.ElementsMatch(t, []string{"added", "broken", "changed", "unchanged"}, sessionIDs(index),
+		"unreadable sessions keep their entry")
+	assert.Equal(t, filepath.Join(sessionsDir, "added.json"), index.Sessions[0].SessionFile)
+
+	assert.Equal(t, 1, index.Statistics.TotalProjects)
+	assert.Equal(t, 3, index.Statistics.TotalSessions, "unreadable sessions aren't counted")
+	assert.Equal(t, 3, index.Statistics.ActiveSessionsCount)
+	assert.NotEmpty(t, index.Statistics.DiskUsage)
+
+	assert.Equal(t, fixtureTime, index.LastSync)
+	assert.False(t, index.NeedsSync(fixtureTime.Add(time.Minute)))
+	assert.True(t, index.NeedsSync(fixtureTime.Add(5*time.Minute)))
+
+	_, result, err = store.Sync(ctx, sessions, 5*time.Minute, fixtureTime)
+	require.NoError(t, err)
+	assert.False(t, result.Changed(), "a second sync has nothing to repair")
+}
+
+func TestStore_RebuildsCorruptedIndex(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	sessions := storage.NewWithSessionsDir("/work/api", filepath.Join(dir, "sessions"))
+	require.NoError(t, sessions.Initialize(ctx))
+	require.NoError(t, sessions.SaveSession(ctx, newFixtureSession("parser", "/work/api")))
+
+	path := filepath.Join(dir, "kamui-index.json")
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0o600))
+	store := NewStoreWithPath(path)
+
+	_, err := store.Load()
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeStorageCorrupted, agxErr.Code)
+
+	index, result, err := store.Sync(ctx, sessions, time.Minute, fixtureTime)
+	require.NoError(t, err)
+	assert.True(t, result.Rebuilt)
+	assert.Equal(t, []string{"parser"}, sessionIDs(index))
+}
+
+func TestSessionFile(t *testing.T) {
+	assert.Equal(t, filepath.Join("/sessions", "parser.json"), SessionFile(storage.NewWithSessionsDir("/work/api", "/sessions"), "parser"))
+	assert.Equal(t, "", SessionFile(storage.NewMemory("/work/api"), "parser"), "backends off this machine have no file")
+}
+
+func TestStore_TakesOverAbandonedLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kamui-index.json")
+	store := NewStoreWithPath(path)
+
+	// left by a kam that died while updating the index
+	require.NoError(t, os.WriteFile(path+".lock", []byte(`{"pid":999999999,"host":"`+hostname(t)+`"}`), 0o600))
+
+	require.NoError(t, store.Put(newFixtureSession("parser", "/work/api"), ""))
+	assert.NoFileExists(t, path+".lock", "the lock is released after the update")
+	index, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"parser"}, sessionIDs(index))
+}
+
+func hostname(t *testing.T) string {
+	t.Helper()
+	name, err := os.Hostname()
+	require.NoError(t, err)
+	return name
+}
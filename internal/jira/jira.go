@@ -0,0 +1,166 @@
+// Package jira is a minimal Jira REST client for resolving and transitioning issues linked to sessions
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// keyPattern matches issue keys such as PROJ-123
+var keyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]+-[0-9]+$`)
+
+// Issue is the part of a Jira issue Kamui shows
+type Issue struct {
+	Key     string
+	Summary string
+	Status  string
+}
+
+// Client talks to the Jira REST API (v2, supported by Jira Cloud and Data Center)
+type Client struct {
+	baseURL    string
+	email      string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for baseURL; with an email it authenticates with basic auth
+// (Jira Cloud API tokens), without one it sends the token as a bearer personal access token
+func NewClient(baseURL, email, token string) (*Client, error) {
+	parsed, err := url.Parse(baseURL)
+	if baseURL == "" || err != nil || parsed.Host == "" {
+		return nil, types.NewConfigError(
+			types.ErrCodeConfigInvalid,
+			"jira.baseUrl is not configured (e.g. https://acme.atlassian.net)",
+			err,
+		)
+	}
+	if token == "" {
+		return nil, types.NewConfigError(
+			types.ErrCodeConfigInvalid,
+			"no Jira API token: set jira.apiToken or JIRA_API_TOKEN",
+			nil,
+		)
+	}
+
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		email:      email,
+		token:      token,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// ValidKey reports whether key looks like a Jira issue key
+func ValidKey(key string) bool {
+	return keyPattern.MatchString(key)
+}
+
+// IssueURL returns the browser URL of an issue
+func (c *Client) IssueURL(key string) string {
+	return c.baseURL + "/browse/" + key
+}
+
+// GetIssue fetches an issue's summary and status
+func (c *Client) GetIssue(key string) (*Issue, error) {
+	var response struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Summary string `json:"summary"`
+			Status  struct {
+				Name string `json:"name"`
+			} `json:"status"`
+		} `json:"fields"`
+	}
+	if err := c.do(http.MethodGet, "/rest/api/2/issue/"+url.PathEscape(key)+"?fields=summary,status", nil, &response); err != nil {
+		return nil, err
+	}
+
+	return &Issue{Key: response.Key, Summary: response.Fields.Summary, Status: response.Fields.Status.Name}, nil
+}
+
+// Transition moves an issue through the workflow transition whose name, or target status, is name
+func (c *Client) Transition(key, name string) error {
+	var response struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			To   struct {
+				Name string `json:"name"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	path := "/rest/api/2/issue/" + url.PathEscape(key) + "/transitions"
+	if err := c.do(http.MethodGet, path, nil, &response); err != nil {
+		return err
+	}
+
+	var available []string
+	for _, transition := range response.Transitions {
+		if strings.EqualFold(transition.Name, name) || strings.EqualFold(transition.To.Name, name) {
+			request := map[string]interface{}{"transition": map[string]string{"id": transition.ID}}
+			return c.do(http.MethodPost, path, request, nil)
+		}
+		available = append(available, transition.Name)
+	}
+
+	return apiError(fmt.Sprintf("%s has no transition '%s' (available: %s)", key, name, strings.Join(available, ", ")), nil)
+}
+
+// do sends a request and decodes a JSON response into out, when given
+func (c *Client) do(method, path string, body, out interface{}) error {
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return apiError("failed to encode Jira request", err)
+		}
+		payload = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, payload)
+	if err != nil {
+		return apiError("failed to build Jira request", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.email != "" {
+		req.SetBasicAuth(c.email, c.token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return apiError("failed to reach Jira", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return apiError(fmt.Sprintf("Jira returned %s for %s %s", resp.Status, method, path), fmt.Errorf("%s", strings.TrimSpace(string(detail))))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return apiError("failed to parse Jira response", err)
+	}
+	return nil
+}
+
+func apiError(message string, cause error) *types.AGXError {
+	return &types.AGXError{Code: types.ErrCodeDependencyFailed, Message: message, Cause: cause}
+}
@@ -0,0 +1,114 @@
+package jira
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// fakeJira serves one issue, PROJ-1, with "Start" and "Done" transitions
+func fakeJira(t *testing.T, transitioned *string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/rest/api/2/issue/PROJ-1", func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "me@example.com" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "summary,status", r.URL.Query().Get("fields"))
+		w.Write([]byte(`{"key":"PROJ-1","fields":{"summary":"Fix login","status":{"name":"In Progress"}}}`))
+	})
+
+	mux.HandleFunc("/rest/api/2/issue/PROJ-1/transitions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			var body struct {
+				Transition struct {
+					ID string `json:"id"`
+				} `json:"transition"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			*transitioned = body.Transition.ID
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Write([]byte(`{"transitions":[{"id":"11","name":"Start","to":{"name":"In Progress"}},{"id":"31","name":"Close","to":{"name":"Done"}}]}`))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestNewClient_RequiresConfig(t *testing.T) {
+	_, err := NewClient("", "me@example.com", "secret")
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeConfigInvalid, agxErr.Code)
+
+	_, err = NewClient("https://acme.atlassian.net", "me@example.com", "")
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeConfigInvalid, agxErr.Code)
+}
+
+func TestGetIssue(t *testing.T) {
+	server := fakeJira(t, new(string))
+	client, err := NewClient(server.URL+"/", "me@example.com", "secret")
+	require.NoError(t, err)
+
+	issue, err := client.GetIssue("PROJ-1")
+	require.NoError(t, err)
+	assert.Equal(t, &Issue{Key: "PROJ-1", Summary: "Fix login", Status: "In Progress"}, issue)
+	assert.Equal(t, server.URL+"/browse/PROJ-1", client.IssueURL("PROJ-1"))
+}
+
+func TestGetIssue_Errors(t *testing.T) {
+	server := fakeJira(t, new(string))
+
+	client, err := NewClient(server.URL, "me@example.com", "wrong")
+	require.NoError(t, err)
+	_, err = client.GetIssue("PROJ-1")
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeDependencyFailed, agxErr.Code)
+	assert.Contains(t, err.Error(), "401")
+
+	client, err = NewClient(server.URL, "me@example.com", "secret")
+	require.NoError(t, err)
+	_, err = client.GetIssue("PROJ-404")
+	assert.Error(t, err)
+}
+
+func TestTransition(t *testing.T) {
+	var transitioned string
+	server := fakeJira(t, &transitioned)
+	client, err := NewClient(server.URL, "me@example.com", "secret")
+	require.NoError(t, err)
+
+	// matched by target status
+	require.NoError(t, client.Transition("PROJ-1", "done"))
+	assert.Equal(t, "31", transitioned)
+
+	// matched by transition name
+	require.NoError(t, client.Transition("PROJ-1", "Start"))
+	assert.Equal(t, "11", transitioned)
+
+	err = client.Transition("PROJ-1", "Reopen")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "available: Start, Close")
+}
+
+func TestValidKey(t *testing.T) {
+	assert.True(t, ValidKey("PROJ-123"))
+	assert.True(t, ValidKey("AB2-1"))
+	assert.False(t, ValidKey("proj-123"))
+	assert.False(t, ValidKey("PROJ"))
+	assert.False(t, ValidKey("https://acme.atlassian.net/browse/PROJ-1"))
+}
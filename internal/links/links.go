@@ -0,0 +1,138 @@
+// Package links classifies issue and pull request URLs attached to sessions and opens them
+package links
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/bitomule/kamui/internal/trace"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Link kinds
+const (
+	KindGitHubIssue = "github-issue"
+	KindGitHubPR    = "github-pr"
+	KindGitLabIssue = "gitlab-issue"
+	KindGitLabMR    = "gitlab-mr"
+	KindJira        = "jira"
+	KindLinear      = "linear"
+	KindURL         = "url"
+)
+
+// issueKeyPattern matches Jira and Linear issue keys such as PROJ-123
+var issueKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]+-[0-9]+$`)
+
+// Parse validates a URL and classifies it, deriving a short label such as "org/repo#123"
+func Parse(raw string) (types.Link, error) {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return types.Link{}, types.NewSessionError(
+			types.ErrCodeInvalidInput,
+			fmt.Sprintf("invalid link '%s': expected an http(s) URL", raw),
+			err,
+		)
+	}
+
+	link := types.Link{Kind: KindURL, URL: parsed.String(), Label: parsed.Host + strings.TrimSuffix(parsed.Path, "/")}
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	host := strings.ToLower(parsed.Hostname())
+
+	switch {
+	case host == "github.com" && len(segments) >= 4 && isNumber(segments[3]):
+		repo := segments[0] + "/" + segments[1]
+		switch segments[2] {
+		case "issues":
+			link.Kind, link.Label = KindGitHubIssue, repo+"#"+segments[3]
+		case "pull":
+			link.Kind, link.Label = KindGitHubPR, repo+"#"+segments[3]
+		}
+
+	case strings.Contains(host, "gitlab"):
+		link.Kind, link.Label = parseGitLab(segments, link.Kind, link.Label)
+
+	case host == "linear.app" && len(segments) >= 3 && segments[1] == "issue" && issueKeyPattern.MatchString(segments[2]):
+		link.Kind, link.Label = KindLinear, segments[2]
+
+	case len(segments) >= 2 && segments[0] == "browse" && issueKeyPattern.MatchString(segments[1]):
+		link.Kind, link.Label = KindJira, segments[1]
+	}
+
+	return link, nil
+}
+
+// Matches reports whether a link is selected by a URL, label or kind
+func Matches(link types.Link, selector string) bool {
+	return link.URL == selector || strings.EqualFold(link.Label, selector) || link.Kind == selector
+}
+
+// Summary renders links as a comma-separated list of labels
+func Summary(links []types.Link) string {
+	labels := make([]string, len(links))
+	for i, link := range links {
+		labels[i] = link.Label
+	}
+	return strings.Join(labels, ", ")
+}
+
+// Open launches the default browser on a URL
+func Open(rawURL string) error {
+	name, args, err := openCommand(runtime.GOOS, rawURL)
+	if err != nil {
+		return err
+	}
+
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return fmt.Errorf("%s not found in PATH: %w", name, err)
+	}
+
+	return trace.Start(exec.Command(path, args...))
+}
+
+// openCommand returns the browser launcher and arguments for the given OS
+func openCommand(goos, rawURL string) (string, []string, error) {
+	switch goos {
+	case "darwin":
+		return "open", []string{rawURL}, nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return "xdg-open", []string{rawURL}, nil
+	case "windows":
+		return "rundll32", []string{"url.dll,FileProtocolHandler", rawURL}, nil
+	default:
+		return "", nil, fmt.Errorf("opening links is not supported on %s", goos)
+	}
+}
+
+// parseGitLab classifies GitLab paths, whose projects can be nested: group/subgroup/project/-/issues/12
+func parseGitLab(segments []string, kind, label string) (string, string) {
+	for i := 0; i+2 < len(segments); i++ {
+		if segments[i] != "-" || !isNumber(segments[i+2]) {
+			continue
+		}
+		repo := strings.Join(segments[:i], "/")
+		switch segments[i+1] {
+		case "issues":
+			return KindGitLabIssue, repo + "#" + segments[i+2]
+		case "merge_requests":
+			return KindGitLabMR, repo + "!" + segments[i+2]
+		}
+	}
+	return kind, label
+}
+
+func isNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
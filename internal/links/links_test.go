@@ -0,0 +1,77 @@
+package links
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		url   string
+		kind  string
+		label string
+	}{
+		{"https://github.com/org/repo/issues/123", KindGitHubIssue, "org/repo#123"},
+		{"https://github.com/org/repo/pull/45/files", KindGitHubPR, "org/repo#45"},
+		{"https://gitlab.com/group/sub/project/-/issues/12", KindGitLabIssue, "group/sub/project#12"},
+		{"https://gitlab.example.com/team/app/-/merge_requests/7", KindGitLabMR, "team/app!7"},
+		{"https://acme.atlassian.net/browse/PROJ-123", KindJira, "PROJ-123"},
+		{"https://linear.app/acme/issue/ENG-42/fix-login", KindLinear, "ENG-42"},
+		{"https://github.com/org/repo", KindURL, "github.com/org/repo"},
+		{"https://docs.example.com/design/", KindURL, "docs.example.com/design"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			link, err := Parse(tt.url)
+			require.NoError(t, err)
+			assert.Equal(t, tt.kind, link.Kind)
+			assert.Equal(t, tt.label, link.Label)
+			assert.Equal(t, tt.url, link.URL)
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, raw := range []string{"", "PROJ-123", "ftp://example.com/file", "https://"} {
+		_, err := Parse(raw)
+		require.Error(t, err, raw)
+
+		var agxErr *types.AGXError
+		require.ErrorAs(t, err, &agxErr)
+		assert.Equal(t, types.ErrCodeInvalidInput, agxErr.Code)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	link, err := Parse("https://github.com/org/repo/pull/45")
+	require.NoError(t, err)
+
+	assert.True(t, Matches(link, "https://github.com/org/repo/pull/45"))
+	assert.True(t, Matches(link, "org/repo#45"))
+	assert.True(t, Matches(link, KindGitHubPR))
+	assert.False(t, Matches(link, KindGitHubIssue))
+}
+
+func TestSummary(t *testing.T) {
+	assert.Equal(t, "org/repo#1, PROJ-2", Summary([]types.Link{{Label: "org/repo#1"}, {Label: "PROJ-2"}}))
+	assert.Equal(t, "", Summary(nil))
+}
+
+func TestOpenCommand(t *testing.T) {
+	name, args, err := openCommand("darwin", "https://example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "open", name)
+	assert.Equal(t, []string{"https://example.com"}, args)
+
+	name, _, err = openCommand("linux", "https://example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "xdg-open", name)
+
+	_, _, err = openCommand("plan9", "https://example.com")
+	assert.Error(t, err)
+}
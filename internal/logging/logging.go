@@ -0,0 +1,410 @@
+// Package logging writes Kamui's operation logs and audit trail and enforces log retention
+//
+// Operation logs rotate daily (kamui-YYYY-MM-DD.log), and within a day once they reach a size
+// limit (kamui-YYYY-MM-DD.N.log, oldest first); audit entries are appended as JSON lines to
+// audit.jsonl. Both live in ~/.kamui/logs by default.
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bitomule/kamui/internal/clock"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+const (
+	logFilePrefix = "kamui-"
+	logFileSuffix = ".log"
+	logDateLayout = "2006-01-02"
+	auditFileName = "audit.jsonl"
+)
+
+// AuditEntry records a user-visible change made by Kamui
+type AuditEntry struct {
+	Time    time.Time `json:"time"`
+	Action  string    `json:"action"`
+	Session string    `json:"session,omitempty"`
+	Detail  string    `json:"detail,omitempty"`
+}
+
+// PruneResult reports what a retention pass removed, or would remove on a dry run
+type PruneResult struct {
+	RemovedLogFiles     int
+	RemovedAuditEntries int
+	LogFiles            []string // paths of the removed log files
+	AuditFile           string   // path of the rewritten audit trail, empty if untouched
+}
+
+// Logger writes to a log directory
+type Logger struct {
+	dir   string
+	clock clock.Clock
+
+	// maxFileSize rotates the day's log once it would grow past it; zero means no limit
+	maxFileSize int64
+	// maxTotalSize drops the oldest log files once they add up to more; zero means no limit
+	maxTotalSize int64
+}
+
+// logFile is an operation log file in the log directory
+type logFile struct {
+	name  string
+	day   time.Time
+	index int // 0 for the day's current file, 1 and up for its rotated files, oldest first
+	size  int64
+}
+
+// New creates a logger writing to ~/.kamui/logs
+func New() *Logger {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+	return NewWithDir(filepath.Join(homeDir, ".kamui", "logs"))
+}
+
+// NewWithDir creates a logger writing to the given directory
+func NewWithDir(dir string) *Logger {
+	return &Logger{dir: dir, clock: clock.System}
+}
+
+// SetClock sets the clock used to timestamp entries and to decide what Prune removes
+func (l *Logger) SetClock(c clock.Clock) {
+	l.clock = c
+}
+
+// SetLimits sets the size at which the day's log is rotated and the cap on the total size of the
+// log files, beyond which the oldest are removed; zero means no limit
+func (l *Logger) SetLimits(maxFileSize, maxTotalSize int64) {
+	l.maxFileSize = maxFileSize
+	l.maxTotalSize = maxTotalSize
+}
+
+// Dir returns the log directory
+func (l *Logger) Dir() string {
+	return l.dir
+}
+
+// Printf appends a timestamped line to the current day's log file
+func (l *Logger) Printf(format string, args ...interface{}) error {
+	now := l.clock.Now()
+	line := fmt.Sprintf("%s %s\n", now.Format(time.RFC3339), strings.TrimRight(fmt.Sprintf(format, args...), "\n"))
+	name := logFilePrefix + now.Format(logDateLayout) + logFileSuffix
+
+	rotated, err := l.rotate(name, int64(len(line)))
+	if err != nil {
+		return err
+	}
+	if err := l.appendFile(name, []byte(line)); err != nil {
+		return err
+	}
+	if rotated {
+		_, err = l.capLogs(false)
+	}
+	return err
+}
+
+// Tail returns the last n lines of the operation logs, oldest first
+func (l *Logger) Tail(n int) ([]string, error) {
+	files, err := l.logFiles(time.Local)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for i := len(files) - 1; i >= 0 && len(lines) < n; i-- {
+		data, err := os.ReadFile(filepath.Join(l.dir, files[i].name))
+		if err != nil {
+			return nil, types.NewStorageError(types.ErrCodeStoragePermission, "failed to read log file", err)
+		}
+		fileLines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+		if len(fileLines) == 1 && fileLines[0] == "" {
+			continue
+		}
+		lines = append(fileLines, lines...)
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines, nil
+}
+
+// Audit appends an entry to the audit trail, stamping it with the current time if unset
+func (l *Logger) Audit(entry AuditEntry) error {
+	if entry.Time.IsZero() {
+		entry.Time = l.clock.Now()
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return l.appendFile(auditFileName, append(data, '\n'))
+}
+
+// Prune deletes log files and audit entries older than retentionDays, then the oldest log files
+// beyond the total size limit
+// A retention of zero or less keeps everything regardless of age; a dry run only reports what would go
+func (l *Logger) Prune(retentionDays int, dryRun bool) (PruneResult, error) {
+	var result PruneResult
+	if retentionDays <= 0 {
+		return l.pruneToCap(result, dryRun)
+	}
+
+	now := l.clock.Now()
+	cutoff := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, -retentionDays)
+
+	entries, err := os.ReadDir(l.dir)
+	if os.IsNotExist(err) {
+		return result, nil
+	}
+	if err != nil {
+		return result, types.NewStorageError(types.ErrCodeStoragePermission, "failed to read log directory", err)
+	}
+
+	for _, entry := range entries {
+		day, _, ok := parseLogFileName(entry.Name(), now.Location())
+		if !ok || !day.Before(cutoff) {
+			continue
+		}
+		path := filepath.Join(l.dir, entry.Name())
+		if !dryRun {
+			if err := os.Remove(path); err != nil {
+				return result, types.NewStorageError(types.ErrCodeStoragePermission, "failed to remove old log file", err)
+			}
+		}
+		result.RemovedLogFiles++
+		result.LogFiles = append(result.LogFiles, path)
+	}
+
+	removed, err := l.pruneAudit(cutoff, dryRun)
+	result.RemovedAuditEntries = removed
+	if removed > 0 {
+		result.AuditFile = filepath.Join(l.dir, auditFileName)
+	}
+	if err != nil {
+		return result, err
+	}
+	return l.pruneToCap(result, dryRun)
+}
+
+// pruneToCap adds the log files removed to stay within the total size limit to result
+// A dry run counts the files already in result as removed
+func (l *Logger) pruneToCap(result PruneResult, dryRun bool) (PruneResult, error) {
+	var removed []string
+	var err error
+	if dryRun {
+		removed, err = l.capLogsExcept(result.LogFiles, true)
+	} else {
+		removed, err = l.capLogs(false)
+	}
+	result.RemovedLogFiles += len(removed)
+	result.LogFiles = append(result.LogFiles, removed...)
+	return result, err
+}
+
+// rotate renames the day's log file aside when appending size bytes would take it past the size
+// limit, reporting whether it did
+func (l *Logger) rotate(name string, size int64) (bool, error) {
+	if l.maxFileSize <= 0 {
+		return false, nil
+	}
+	path := filepath.Join(l.dir, name)
+	info, err := os.Stat(path)
+	if err != nil || info.Size() == 0 || info.Size()+size <= l.maxFileSize {
+		return false, nil // a missing file is created by the append
+	}
+
+	// number it after the day's newest rotated file, which may outlive older ones removed by the cap
+	files, err := l.logFiles(time.UTC)
+	if err != nil {
+		return false, err
+	}
+	last := 0
+	for _, file := range files {
+		if strings.HasPrefix(file.name, strings.TrimSuffix(name, logFileSuffix)+".") {
+			last = max(last, file.index)
+		}
+	}
+
+	rotated := filepath.Join(l.dir, fmt.Sprintf("%s.%d%s", strings.TrimSuffix(name, logFileSuffix), last+1, logFileSuffix))
+	if err := os.Rename(path, rotated); err != nil {
+		return false, types.NewStorageError(types.ErrCodeStoragePermission, "failed to rotate log file", err)
+	}
+	return true, nil
+}
+
+// capLogs removes the oldest log files until they add up to no more than the total size limit,
+// never the newest; it returns the paths removed, or that would be on a dry run
+func (l *Logger) capLogs(dryRun bool) ([]string, error) {
+	return l.capLogsExcept(nil, dryRun)
+}
+
+// capLogsExcept is capLogs treating the files at the given paths as already removed
+func (l *Logger) capLogsExcept(gone []string, dryRun bool) ([]string, error) {
+	if l.maxTotalSize <= 0 {
+		return nil, nil
+	}
+	files, err := l.logFiles(l.clock.Now().Location())
+	if err != nil {
+		return nil, err
+	}
+
+	var kept []logFile
+	var total int64
+	for _, file := range files {
+		if !slices.Contains(gone, filepath.Join(l.dir, file.name)) {
+			kept = append(kept, file)
+			total += file.size
+		}
+	}
+
+	var removed []string
+	for _, file := range kept {
+		if total <= l.maxTotalSize || file == kept[len(kept)-1] {
+			break
+		}
+		path := filepath.Join(l.dir, file.name)
+		if !dryRun {
+			if err := os.Remove(path); err != nil {
+				return removed, types.NewStorageError(types.ErrCodeStoragePermission, "failed to remove old log file", err)
+			}
+		}
+		total -= file.size
+		removed = append(removed, path)
+	}
+	return removed, nil
+}
+
+// logFiles lists the operation log files, oldest first
+func (l *Logger) logFiles(loc *time.Location) ([]logFile, error) {
+	entries, err := os.ReadDir(l.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStoragePermission, "failed to read log directory", err)
+	}
+
+	var files []logFile
+	for _, entry := range entries {
+		day, index, ok := parseLogFileName(entry.Name(), loc)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue // removed meanwhile
+		}
+		files = append(files, logFile{name: entry.Name(), day: day, index: index, size: info.Size()})
+	}
+
+	// the day's current file (index 0) is newer than the ones rotated from it
+	order := func(index int) int {
+		if index == 0 {
+			return math.MaxInt
+		}
+		return index
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if !files[i].day.Equal(files[j].day) {
+			return files[i].day.Before(files[j].day)
+		}
+		return order(files[i].index) < order(files[j].index)
+	})
+	return files, nil
+}
+
+// pruneAudit rewrites the audit trail without entries older than cutoff
+func (l *Logger) pruneAudit(cutoff time.Time, dryRun bool) (int, error) {
+	path := filepath.Join(l.dir, auditFileName)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, types.NewStorageError(types.ErrCodeStoragePermission, "failed to read audit log", err)
+	}
+
+	var kept bytes.Buffer
+	removed := 0
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var entry AuditEntry
+		if err := json.Unmarshal(line, &entry); err == nil && entry.Time.Before(cutoff) {
+			removed++
+			continue
+		}
+		kept.Write(line)
+		kept.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, types.NewStorageError(types.ErrCodeStorageCorrupted, "failed to parse audit log", err)
+	}
+	if removed == 0 || dryRun {
+		return removed, nil
+	}
+
+	tempFile := path + ".tmp"
+	if err := os.WriteFile(tempFile, kept.Bytes(), 0o600); err != nil {
+		return 0, types.NewStorageError(types.ErrCodeStoragePermission, "failed to write audit log", err)
+	}
+	if err := os.Rename(tempFile, path); err != nil {
+		os.Remove(tempFile)
+		return 0, types.NewStorageError(types.ErrCodeStoragePermission, "failed to replace audit log", err)
+	}
+	return removed, nil
+}
+
+// appendFile appends data to a file in the log directory
+func (l *Logger) appendFile(name string, data []byte) error {
+	if err := os.MkdirAll(l.dir, 0o700); err != nil {
+		return types.NewStorageError(types.ErrCodeStoragePermission, "failed to create log directory", err)
+	}
+
+	file, err := os.OpenFile(filepath.Join(l.dir, name), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return types.NewStorageError(types.ErrCodeStoragePermission, "failed to open log file", err)
+	}
+	defer file.Close()
+
+	_, err = file.Write(data)
+	return err
+}
+
+// parseLogFileName extracts the day and rotation index (0 for the day's current file) from a log
+// file name
+func parseLogFileName(name string, loc *time.Location) (time.Time, int, bool) {
+	if !strings.HasPrefix(name, logFilePrefix) || !strings.HasSuffix(name, logFileSuffix) {
+		return time.Time{}, 0, false
+	}
+	stem := strings.TrimSuffix(strings.TrimPrefix(name, logFilePrefix), logFileSuffix)
+
+	index := 0
+	if date, suffix, rotated := strings.Cut(stem, "."); rotated {
+		n, err := strconv.Atoi(suffix)
+		if err != nil || n < 1 {
+			return time.Time{}, 0, false
+		}
+		stem, index = date, n
+	}
+	day, err := time.ParseInLocation(logDateLayout, stem, loc)
+	return day, index, err == nil
+}
@@ -0,0 +1,151 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/clock"
+)
+
+func newTestLogger(t *testing.T, now time.Time) *Logger {
+	t.Helper()
+	logger := NewWithDir(t.TempDir())
+	logger.SetClock(clock.NewFake(now))
+	return logger
+}
+
+func TestPrintf_RotatesDaily(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 0, 0, 0, time.UTC)
+	logger := newTestLogger(t, now)
+
+	require.NoError(t, logger.Printf("first %d", 1))
+	logger.SetClock(clock.NewFake(now.AddDate(0, 0, 1)))
+	require.NoError(t, logger.Printf("second"))
+
+	day1, err := os.ReadFile(filepath.Join(logger.Dir(), "kamui-2025-03-10.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "2025-03-10T14:00:00Z first 1\n", string(day1))
+
+	_, err = os.Stat(filepath.Join(logger.Dir(), "kamui-2025-03-11.log"))
+	require.NoError(t, err)
+}
+
+func TestPrune(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 0, 0, 0, time.UTC)
+	logger := newTestLogger(t, now)
+
+	for _, day := range []string{"2025-02-01", "2025-03-02", "2025-03-03", "2025-03-10"} {
+		require.NoError(t, os.MkdirAll(logger.Dir(), 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(logger.Dir(), "kamui-"+day+".log"), []byte("x\n"), 0o600))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(logger.Dir(), "notes.log"), []byte("keep\n"), 0o600))
+
+	require.NoError(t, logger.Audit(AuditEntry{Time: now.AddDate(0, 0, -30), Action: "create", Session: "old"}))
+	require.NoError(t, logger.Audit(AuditEntry{Time: now.AddDate(0, 0, -1), Action: "complete", Session: "recent"}))
+	require.NoError(t, logger.Audit(AuditEntry{Action: "create", Session: "now"}))
+
+	// A dry run reports the same result without touching anything
+	planned, err := logger.Prune(7, true)
+	require.NoError(t, err)
+	assert.Len(t, planned.LogFiles, 2)
+	assert.Equal(t, filepath.Join(logger.Dir(), "audit.jsonl"), planned.AuditFile)
+	entries, err := os.ReadDir(logger.Dir())
+	require.NoError(t, err)
+	assert.Len(t, entries, 6)
+
+	result, err := logger.Prune(7, false)
+	require.NoError(t, err)
+	assert.Equal(t, planned, result)
+	assert.Equal(t, 2, result.RemovedLogFiles)
+	assert.Equal(t, 1, result.RemovedAuditEntries)
+	assert.ElementsMatch(t, []string{
+		filepath.Join(logger.Dir(), "kamui-2025-02-01.log"),
+		filepath.Join(logger.Dir(), "kamui-2025-03-02.log"),
+	}, result.LogFiles)
+
+	entries, err = os.ReadDir(logger.Dir())
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.ElementsMatch(t, []string{"audit.jsonl", "kamui-2025-03-03.log", "kamui-2025-03-10.log", "notes.log"}, names)
+
+	audit, err := os.ReadFile(filepath.Join(logger.Dir(), "audit.jsonl"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(audit), `"old"`)
+	assert.Equal(t, 2, strings.Count(string(audit), "\n"))
+}
+
+func TestPrune_DisabledOrMissing(t *testing.T) {
+	logger := NewWithDir(filepath.Join(t.TempDir(), "missing"))
+
+	result, err := logger.Prune(7, false)
+	require.NoError(t, err)
+	assert.Equal(t, PruneResult{}, result)
+
+	result, err = logger.Prune(0, false)
+	require.NoError(t, err)
+	assert.Equal(t, PruneResult{}, result)
+}
+
+func TestPrintf_RotatesBySize(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 0, 0, 0, time.UTC)
+	logger := newTestLogger(t, now)
+	// each line is 31 bytes: two fit in a file, five files in the cap
+	logger.SetLimits(64, 5*31)
+
+	for i := 0; i < 12; i++ {
+		require.NoError(t, logger.Printf("line %04d", i))
+	}
+
+	entries, err := os.ReadDir(logger.Dir())
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.ElementsMatch(t, []string{"kamui-2025-03-10.4.log", "kamui-2025-03-10.5.log", "kamui-2025-03-10.log"}, names)
+
+	lines, err := logger.Tail(3)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"2025-03-10T14:00:00Z line 0009",
+		"2025-03-10T14:00:00Z line 0010",
+		"2025-03-10T14:00:00Z line 0011",
+	}, lines)
+}
+
+func TestPrune_SizeCap(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 0, 0, 0, time.UTC)
+	logger := newTestLogger(t, now)
+	logger.SetLimits(0, 10)
+
+	require.NoError(t, os.MkdirAll(logger.Dir(), 0o700))
+	for _, name := range []string{"kamui-2025-03-09.log", "kamui-2025-03-10.1.log", "kamui-2025-03-10.log"} {
+		require.NoError(t, os.WriteFile(filepath.Join(logger.Dir(), name), []byte("1234\n"), 0o600))
+	}
+
+	planned, err := logger.Prune(7, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(logger.Dir(), "kamui-2025-03-09.log")}, planned.LogFiles)
+
+	result, err := logger.Prune(0, false)
+	require.NoError(t, err)
+	assert.Equal(t, planned, result)
+	_, err = os.Stat(filepath.Join(logger.Dir(), "kamui-2025-03-10.1.log"))
+	assert.NoError(t, err)
+}
+
+func TestTail_Empty(t *testing.T) {
+	logger := NewWithDir(filepath.Join(t.TempDir(), "missing"))
+	lines, err := logger.Tail(10)
+	require.NoError(t, err)
+	assert.Empty(t, lines)
+}
@@ -0,0 +1,210 @@
+// Package monitor records the background processes that bind new sessions to the Claude
+// conversation they start, so duplicates can be avoided and orphaned monitors stopped
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bitomule/kamui/internal/process"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Statuses of a recorded monitor
+const (
+	// StatusRunning is a monitor whose kam still runs
+	StatusRunning = "running"
+	// StatusOrphaned is a monitor that outlived the kam that started it
+	StatusOrphaned = "orphaned"
+	// StatusStale is a record left by a monitor that no longer runs
+	StatusStale = "stale"
+)
+
+// Record is a session monitor as registered when it starts
+type Record struct {
+	Session    string    `json:"session"`
+	PID        int       `json:"pid"`
+	Parent     int       `json:"parent"`
+	Host       string    `json:"host"`
+	WorkingDir string    `json:"workingDir"`
+	Started    time.Time `json:"started"`
+	// ProcessStart is process.StartTime of the monitor, which tells it apart from an unrelated
+	// process that later got its pid; records written before it was kept have none
+	ProcessStart string `json:"processStart,omitempty"`
+}
+
+// Status reports whether the monitor and the kam that started it still run
+// Monitors on other hosts can't be checked and count as running
+func (r Record) Status() string {
+	if hostname, _ := os.Hostname(); r.Host != hostname {
+		return StatusRunning
+	}
+	switch {
+	case !process.Alive(r.PID):
+		return StatusStale
+	case r.ProcessStart != "" && !r.Verified():
+		// the monitor exited and its pid went to another process
+		return StatusStale
+	case !process.Alive(r.Parent):
+		return StatusOrphaned
+	default:
+		return StatusRunning
+	}
+}
+
+// Verified reports whether the recorded pid still belongs to the monitor, judged by its start
+// time; without a recorded start time, or one that can be read now, it can't be told
+func (r Record) Verified() bool {
+	return r.ProcessStart != "" && process.StartTime(r.PID) == r.ProcessStart
+}
+
+// Registry keeps one record per session monitor in a runtime directory
+type Registry struct {
+	dir string
+}
+
+// NewRegistry creates a registry under ~/.kamui/run/monitors
+func NewRegistry() *Registry {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+	return NewRegistryWithDir(filepath.Join(homeDir, ".kamui", "run", "monitors"))
+}
+
+// NewRegistryWithDir creates a registry under the given directory
+func NewRegistryWithDir(dir string) *Registry {
+	return &Registry{dir: dir}
+}
+
+// Claim registers a monitor for its session unless another one is already running for it,
+// which is returned instead; an orphaned monitor for the session is stopped first when its pid
+// is verified to still be that monitor, and otherwise only replaced
+func (r *Registry) Claim(record Record) (*Record, error) {
+	existing, err := r.Get(record.Session)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.PID != record.PID {
+		switch existing.Status() {
+		case StatusRunning:
+			return existing, nil
+		case StatusOrphaned:
+			if existing.Verified() {
+				if err := process.Kill(existing.PID); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return nil, r.write(record)
+}
+
+// Release removes a monitor's record, unless the session has since been claimed by another one
+func (r *Registry) Release(session string, pid int) error {
+	existing, err := r.Get(session)
+	if err != nil || existing == nil || existing.PID != pid {
+		return err
+	}
+	return r.remove(session)
+}
+
+// Get returns the monitor recorded for a session, or nil when there is none
+func (r *Registry) Get(session string) (*Record, error) {
+	data, err := os.ReadFile(r.path(session))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStoragePermission, "failed to read monitor record", err)
+	}
+
+	var record Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStorageCorrupted, "failed to parse monitor record", err)
+	}
+	return &record, nil
+}
+
+// List returns every recorded monitor, ordered by session; unreadable records are skipped
+func (r *Registry) List() ([]Record, error) {
+	entries, err := os.ReadDir(r.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStoragePermission, "failed to read monitor records", err)
+	}
+
+	var records []Record
+	for _, entry := range entries {
+		session, ok := strings.CutSuffix(entry.Name(), ".json")
+		if entry.IsDir() || !ok {
+			continue
+		}
+		if record, err := r.Get(session); err == nil && record != nil {
+			records = append(records, *record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Session < records[j].Session })
+	return records, nil
+}
+
+// Reap stops an orphaned monitor and drops its record, or just drops the record of a stale one;
+// running monitors are left alone, and so are orphans whose pid can't be verified to still be
+// the monitor
+func (r *Registry) Reap(record Record) error {
+	switch record.Status() {
+	case StatusOrphaned:
+		if !record.Verified() {
+			return types.NewSessionError(types.ErrCodeInvalidInput,
+				fmt.Sprintf("can't verify that pid %d is still the monitor for session '%s'; stop it yourself if it is", record.PID, record.Session), nil)
+		}
+		if err := process.Kill(record.PID); err != nil {
+			return err
+		}
+	case StatusRunning:
+		return nil
+	}
+	return r.Release(record.Session, record.PID)
+}
+
+// path returns the file holding a session's monitor record
+func (r *Registry) path(session string) string {
+	return filepath.Join(r.dir, session+".json")
+}
+
+// write saves a record atomically
+func (r *Registry) write(record Record) error {
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return types.NewStorageError(types.ErrCodeStorageCorrupted, "failed to marshal monitor record", err)
+	}
+	if err := os.MkdirAll(r.dir, 0o700); err != nil {
+		return types.NewStorageError(types.ErrCodeStoragePermission, "failed to create monitor directory", err)
+	}
+
+	path := r.path(record.Session)
+	tempFile := path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0o600); err != nil {
+		return types.NewStorageError(types.ErrCodeStoragePermission, "failed to write monitor record", err)
+	}
+	if err := os.Rename(tempFile, path); err != nil {
+		os.Remove(tempFile) // cleanup temp file
+		return types.NewStorageError(types.ErrCodeStoragePermission, "failed to save monitor record", err)
+	}
+	return nil
+}
+
+// remove deletes a session's monitor record
+func (r *Registry) remove(session string) error {
+	if err := os.Remove(r.path(session)); err != nil && !os.IsNotExist(err) {
+		return types.NewStorageError(types.ErrCodeStoragePermission, "failed to remove monitor record", err)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package monitor
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/process"
+)
+
+// startSleeper starts a process standing in for a monitor
+func startSleeper(t *testing.T) int {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX sleep")
+	}
+	cmd := exec.Command("sleep", "30")
+	require.NoError(t, cmd.Start())
+	// reap it as soon as it is killed, so its pid stops counting as alive
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		<-exited
+	})
+	return cmd.Process.Pid
+}
+
+// exitedPID returns the pid of a process that has already exited
+func exitedPID(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command("true")
+	require.NoError(t, cmd.Run())
+	return cmd.Process.Pid
+}
+
+func record(session string, pid, parent int) Record {
+	hostname, _ := os.Hostname()
+	return Record{Session: session, PID: pid, Parent: parent, Host: hostname, WorkingDir: "/src/app", Started: time.Now(),
+		ProcessStart: process.StartTime(pid)}
+}
+
+func TestRecordStatus(t *testing.T) {
+	monitorPID := startSleeper(t)
+	assert.Equal(t, StatusRunning, record("api", monitorPID, os.Getpid()).Status())
+	assert.Equal(t, StatusOrphaned, record("api", monitorPID, exitedPID(t)).Status())
+	assert.Equal(t, StatusStale, record("api", exitedPID(t), os.Getpid()).Status())
+
+	reused := record("api", monitorPID, os.Getpid())
+	reused.ProcessStart = "earlier"
+	assert.Equal(t, StatusStale, reused.Status(), "a pid now held by another process is stale")
+
+	elsewhere := record("api", exitedPID(t), exitedPID(t))
+	elsewhere.Host = "another-host"
+	assert.Equal(t, StatusRunning, elsewhere.Status(), "monitors on other hosts can't be checked")
+}
+
+func TestRegistry_Claim(t *testing.T) {
+	registry := NewRegistryWithDir(t.TempDir())
+
+	first := record("api", startSleeper(t), os.Getpid())
+	existing, err := registry.Claim(first)
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+
+	// a second monitor for the session while the first runs is turned away
+	second := record("api", os.Getpid(), os.Getpid())
+	existing, err = registry.Claim(second)
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	assert.Equal(t, first.PID, existing.PID)
+
+	// once the first is orphaned it is stopped and replaced
+	orphan := record("api", first.PID, exitedPID(t))
+	_, err = registry.Claim(orphan)
+	require.NoError(t, err)
+	existing, err = registry.Claim(second)
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+	assert.Eventually(t, func() bool { return orphan.Status() == StatusStale }, time.Second, 10*time.Millisecond)
+
+	saved, err := registry.Get("api")
+	require.NoError(t, err)
+	assert.Equal(t, second.PID, saved.PID)
+}
+
+func TestRegistry_ReleaseListReap(t *testing.T) {
+	registry := NewRegistryWithDir(t.TempDir())
+	records, err := registry.List()
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	running := record("web", startSleeper(t), os.Getpid())
+	orphaned := record("api", startSleeper(t), exitedPID(t))
+	stale := record("docs", exitedPID(t), os.Getpid())
+	for _, r := range []Record{running, orphaned, stale} {
+		_, err := registry.Claim(r)
+		require.NoError(t, err)
+	}
+
+	records, err = registry.List()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, []string{"api", "docs", "web"}, []string{records[0].Session, records[1].Session, records[2].Session})
+
+	for _, r := range records {
+		require.NoError(t, registry.Reap(r))
+	}
+	records, err = registry.List()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "web", records[0].Session)
+	assert.Eventually(t, func() bool { return orphaned.Status() == StatusStale }, time.Second, 10*time.Millisecond)
+
+	// releasing someone else's record leaves it in place
+	require.NoError(t, registry.Release("web", os.Getpid()))
+	saved, err := registry.Get("web")
+	require.NoError(t, err)
+	assert.NotNil(t, saved)
+	require.NoError(t, registry.Release("web", running.PID))
+	saved, err = registry.Get("web")
+	require.NoError(t, err)
+	assert.Nil(t, saved)
+}
+
+func TestRegistry_LeavesUnverifiedOrphansRunning(t *testing.T) {
+	registry := NewRegistryWithDir(t.TempDir())
+
+	// a record from before start times were kept can't prove its pid is still the monitor
+	orphan := record("api", startSleeper(t), exitedPID(t))
+	orphan.ProcessStart = ""
+	_, err := registry.Claim(orphan)
+	require.NoError(t, err)
+
+	assert.Error(t, registry.Reap(orphan))
+	assert.True(t, process.Alive(orphan.PID), "an unverified pid is never killed")
+	saved, err := registry.Get("api")
+	require.NoError(t, err)
+	assert.NotNil(t, saved)
+
+	// a new monitor replaces the record without killing the process
+	existing, err := registry.Claim(record("api", os.Getpid(), os.Getpid()))
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+	assert.True(t, process.Alive(orphan.PID))
+}
@@ -0,0 +1,59 @@
+// Package notify sends native desktop notifications on macOS and Linux
+package notify
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/bitomule/kamui/internal/trace"
+)
+
+// maxBodyLength keeps notification bodies readable in notification centers
+const maxBodyLength = 200
+
+// Send displays a desktop notification with the given title and body
+func Send(title, body string) error {
+	name, args, err := command(runtime.GOOS, title, Snippet(body))
+	if err != nil {
+		return err
+	}
+
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return fmt.Errorf("%s not found in PATH: %w", name, err)
+	}
+
+	return trace.Run(exec.Command(path, args...))
+}
+
+// Snippet collapses whitespace and truncates text to fit in a notification
+func Snippet(text string) string {
+	snippet := strings.Join(strings.Fields(text), " ")
+	runes := []rune(snippet)
+	if len(runes) > maxBodyLength {
+		return string(runes[:maxBodyLength-3]) + "..."
+	}
+	return snippet
+}
+
+// command returns the notifier executable and arguments for the given OS
+func command(goos, title, body string) (string, []string, error) {
+	switch goos {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(body), appleScriptString(title))
+		return "osascript", []string{"-e", script}, nil
+	case "linux":
+		return "notify-send", []string{"--app-name=Kamui", title, body}, nil
+	default:
+		return "", nil, fmt.Errorf("desktop notifications are not supported on %s", goos)
+	}
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
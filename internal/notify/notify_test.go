@@ -0,0 +1,39 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_Darwin(t *testing.T) {
+	name, args, err := command("darwin", "Kamui: done", `said "hi"`)
+	require.NoError(t, err)
+
+	assert.Equal(t, "osascript", name)
+	assert.Equal(t, []string{"-e", `display notification "said \"hi\"" with title "Kamui: done"`}, args)
+}
+
+func TestCommand_Linux(t *testing.T) {
+	name, args, err := command("linux", "Kamui: done", "body")
+	require.NoError(t, err)
+
+	assert.Equal(t, "notify-send", name)
+	assert.Equal(t, []string{"--app-name=Kamui", "Kamui: done", "body"}, args)
+}
+
+func TestCommand_Unsupported(t *testing.T) {
+	_, _, err := command("windows", "title", "body")
+	require.Error(t, err)
+}
+
+func TestSnippet(t *testing.T) {
+	assert.Equal(t, "line one line two", Snippet("line one\n\n  line two  "))
+
+	long := strings.Repeat("a", 500)
+	snippet := Snippet(long)
+	assert.Len(t, snippet, maxBodyLength)
+	assert.True(t, strings.HasSuffix(snippet, "..."))
+}
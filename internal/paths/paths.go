@@ -0,0 +1,149 @@
+// Package paths canonicalizes filesystem paths and encodes them the way Claude Code does
+//
+// Storage keys, Claude transcript discovery and session lookups must all agree on
+// a project's path, so every comparison and encoding goes through this package.
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// privateAliases are the macOS top-level directories that are symlinks into /private
+var privateAliases = []string{"/tmp", "/var", "/etc"}
+
+// Canonical returns the absolute, symlink-resolved form of path
+// Paths that don't exist yet are resolved through their deepest existing ancestor
+func Canonical(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = filepath.Clean(path)
+	}
+
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		return resolved
+	}
+
+	dir, rest := abs, ""
+	for {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		rest = filepath.Join(filepath.Base(dir), rest)
+		dir = parent
+
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			return filepath.Join(resolved, rest)
+		}
+	}
+
+	if runtime.GOOS == "darwin" {
+		return resolvePrivateAlias(abs)
+	}
+	return abs
+}
+
+// resolvePrivateAlias maps /tmp, /var and /etc to their /private targets
+func resolvePrivateAlias(path string) string {
+	for _, alias := range privateAliases {
+		if path == alias || strings.HasPrefix(path, alias+"/") {
+			return "/private" + path
+		}
+	}
+	return path
+}
+
+// Equal reports whether a and b refer to the same location
+// On case-insensitive filesystems paths differing only in case are equal when they resolve to the same file
+func Equal(a, b string) bool {
+	if a == "" || b == "" {
+		return a == b
+	}
+
+	ca, cb := Canonical(a), Canonical(b)
+	if ca == cb {
+		return true
+	}
+	if !strings.EqualFold(ca, cb) {
+		return false
+	}
+
+	infoA, errA := os.Stat(ca)
+	infoB, errB := os.Stat(cb)
+	return errA == nil && errB == nil && os.SameFile(infoA, infoB)
+}
+
+// EncodeClaude encodes a path the way Claude Code names its project directories:
+// every character other than ASCII letters and digits becomes "-"
+func EncodeClaude(path string) string {
+	var b strings.Builder
+	b.Grow(len(path))
+	for _, r := range path {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
+
+// ClaudeProjectsDir returns the directory holding Claude Code's per-project transcript directories
+func ClaudeProjectsDir(homeDir string) string {
+	return filepath.Join(homeDir, ".claude", "projects")
+}
+
+// ClaudeProjectDir returns the directory where Claude Code stores transcripts for workingDir
+func ClaudeProjectDir(homeDir, workingDir string) string {
+	return filepath.Join(ClaudeProjectsDir(homeDir), EncodeClaude(Canonical(workingDir)))
+}
+
+// DecodeClaude finds the existing directory a Claude Code project directory name was encoded from
+// The encoding is lossy ("-" may have been "/", "-", "." or a space), so the name is matched
+// against the filesystem one directory at a time; it returns "" when no directory matches
+func DecodeClaude(encoded string) string {
+	root, rest := "/", strings.TrimPrefix(encoded, "-")
+	if len(encoded) > 3 && encoded[1:3] == "--" && isDriveLetter(encoded[0]) {
+		root, rest = encoded[:1]+`:\`, encoded[3:]
+	} else if !strings.HasPrefix(encoded, "-") {
+		return ""
+	}
+	if rest == "" {
+		return root
+	}
+	return decodeUnder(root, rest)
+}
+
+// decodeUnder returns the directory below dir whose encoded relative path is rest
+func decodeUnder(dir, rest string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		name := EncodeClaude(entry.Name())
+		if !strings.HasPrefix(rest, name) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		switch remaining := rest[len(name):]; {
+		case remaining == "":
+			return path
+		case remaining[0] == '-':
+			if found := decodeUnder(path, remaining[1:]); found != "" {
+				return found
+			}
+		}
+	}
+	return ""
+}
+
+func isDriveLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,83 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonical_ResolvesSymlinks(t *testing.T) {
+	root := Canonical(t.TempDir())
+	target := filepath.Join(root, "real")
+	link := filepath.Join(root, "link")
+	require.NoError(t, os.Mkdir(target, 0o755))
+	require.NoError(t, os.Symlink(target, link))
+
+	assert.Equal(t, target, Canonical(link))
+	assert.Equal(t, filepath.Join(target, "not", "yet"), Canonical(filepath.Join(link, "not", "yet")))
+}
+
+func TestCanonical_RelativePath(t *testing.T) {
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	assert.Equal(t, Canonical(cwd), Canonical("."))
+}
+
+func TestResolvePrivateAlias(t *testing.T) {
+	assert.Equal(t, "/private/tmp/project", resolvePrivateAlias("/tmp/project"))
+	assert.Equal(t, "/private/var", resolvePrivateAlias("/var"))
+	assert.Equal(t, "/tmpfiles/project", resolvePrivateAlias("/tmpfiles/project"))
+	assert.Equal(t, "/Users/me", resolvePrivateAlias("/Users/me"))
+}
+
+func TestEqual(t *testing.T) {
+	root := Canonical(t.TempDir())
+	target := filepath.Join(root, "real")
+	link := filepath.Join(root, "link")
+	require.NoError(t, os.Mkdir(target, 0o755))
+	require.NoError(t, os.Symlink(target, link))
+
+	assert.True(t, Equal(target, link))
+	assert.True(t, Equal(target+"/", target))
+	assert.False(t, Equal(target, root))
+	assert.False(t, Equal("", target))
+}
+
+func TestEncodeClaude(t *testing.T) {
+	testCases := map[string]string{
+		"/tmp/project":                 "-tmp-project",
+		"/Users/test/my-project":       "-Users-test-my-project",
+		"/Users/test/.config/app":      "-Users-test--config-app",
+		"/home/user/project_with.dots": "-home-user-project-with-dots",
+		"relative/path":                "relative-path",
+	}
+
+	for input, expected := range testCases {
+		assert.Equal(t, expected, EncodeClaude(input), input)
+	}
+}
+
+func TestClaudeProjectDir(t *testing.T) {
+	dir := Canonical(t.TempDir())
+
+	assert.Equal(t, filepath.Join("/home/me", ".claude", "projects", EncodeClaude(dir)), ClaudeProjectDir("/home/me", dir))
+}
+
+func TestDecodeClaude(t *testing.T) {
+	root := Canonical(t.TempDir())
+	project := filepath.Join(root, "my-app.v2", "service")
+	require.NoError(t, os.MkdirAll(project, 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "my", "app"), 0o755))
+	hidden := filepath.Join(root, ".config", "tool")
+	require.NoError(t, os.MkdirAll(hidden, 0o755))
+
+	assert.Equal(t, project, DecodeClaude(EncodeClaude(project)), "backtracks past my/app")
+	assert.Equal(t, hidden, DecodeClaude(EncodeClaude(hidden)))
+	assert.Equal(t, root, DecodeClaude(EncodeClaude(root)))
+	assert.Empty(t, DecodeClaude(EncodeClaude(filepath.Join(root, "deleted"))))
+	assert.Empty(t, DecodeClaude("relative-path"))
+}
@@ -0,0 +1,494 @@
+package picker
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bitomule/kamui/internal/links"
+	"github.com/bitomule/kamui/internal/session"
+	"github.com/bitomule/kamui/internal/terminal"
+	"github.com/bitomule/kamui/internal/viewer"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Help is the key summary shown in the status bar
+const Help = "type to filter · ↑/↓ move · enter resume · ctrl-r rename · ctrl-a archive · ctrl-x delete · esc quit"
+
+// Control keys, named by the raw character as viewer.ReadKey returns them
+const (
+	keyCtrlA viewer.Key = "\x01"
+	keyCtrlN viewer.Key = "\x0e"
+	keyCtrlP viewer.Key = "\x10"
+	keyCtrlR viewer.Key = "\x12"
+	keyCtrlX viewer.Key = "\x18"
+)
+
+// previewHeight is the number of rows the preview of the selected session takes, separator included
+const previewHeight = 7
+
+// ActionKind is what the user asked the picker to do
+type ActionKind int
+
+const (
+	ActionNone ActionKind = iota
+	ActionQuit
+	ActionPick
+	ActionRename
+	ActionArchive
+	ActionDelete
+)
+
+// Action is a request from the picker, with the session it applies to
+type Action struct {
+	Kind    ActionKind
+	Session string
+	// NewName is the name an ActionRename gives the session
+	NewName string
+}
+
+// Model is the picker state; it does no I/O so it can be driven by tests
+type Model struct {
+	title    string
+	sessions []session.Summary
+	query    string
+	// matches indexes the sessions matching the query, best first
+	matches []int
+	// selected is the name of the selected session, kept while filtering and across refreshes
+	selected string
+	offset   int
+	width    int
+	height   int
+
+	pending Action
+	// renaming is set while the new name for the selected session is typed into input
+	renaming bool
+	input    string
+	message  string
+}
+
+// New builds a picker over sessions, shown in the order given until a filter is typed
+func New(title string, sessions []session.Summary) *Model {
+	m := &Model{title: title, width: 80, height: 24}
+	m.SetSessions(sessions)
+	return m
+}
+
+// SetSessions replaces the sessions shown, keeping the selection when the session is still there
+func (m *Model) SetSessions(sessions []session.Summary) {
+	m.sessions = sessions
+	m.filter()
+}
+
+// SetMessage shows a message in the status bar until the next keypress
+func (m *Model) SetMessage(message string) {
+	m.message = message
+}
+
+// SetSize sets the terminal size the view is rendered for
+func (m *Model) SetSize(width, height int) {
+	if width < 40 {
+		width = 40
+	}
+	if height < 6 {
+		height = 6
+	}
+	m.width, m.height = width, height
+	m.scrollToSelection()
+}
+
+// Select selects the named session if it matches the filter
+func (m *Model) Select(name string) {
+	for _, index := range m.matches {
+		if m.sessions[index].Name == name {
+			m.selected = name
+			m.scrollToSelection()
+			return
+		}
+	}
+}
+
+// Selected returns the selected session
+func (m *Model) Selected() (session.Summary, bool) {
+	for _, index := range m.matches {
+		if m.sessions[index].Name == m.selected {
+			return m.sessions[index], true
+		}
+	}
+	return session.Summary{}, false
+}
+
+// HandleKey applies a keypress and returns what the caller should do about it
+func (m *Model) HandleKey(key viewer.Key) Action {
+	if m.pending.Kind != ActionNone {
+		action := m.pending
+		m.pending, m.message = Action{}, ""
+		if key == "y" || key == "Y" {
+			return action
+		}
+		return Action{}
+	}
+	if m.renaming {
+		return m.handleRenameKey(key)
+	}
+
+	m.message = ""
+	switch key {
+	case viewer.KeyInterrupt:
+		return Action{Kind: ActionQuit}
+	case viewer.KeyEscape:
+		if m.query == "" {
+			return Action{Kind: ActionQuit}
+		}
+		m.setQuery("")
+	case viewer.KeyDown, keyCtrlN:
+		m.moveSelection(1)
+	case viewer.KeyUp, keyCtrlP:
+		m.moveSelection(-1)
+	case viewer.KeyHome:
+		m.moveSelection(-len(m.matches))
+	case viewer.KeyEnd:
+		m.moveSelection(len(m.matches))
+	case viewer.KeyPageDown:
+		m.moveSelection(m.pageSize())
+	case viewer.KeyPageUp:
+		m.moveSelection(-m.pageSize())
+	case viewer.KeyBackspace:
+		if m.query != "" {
+			_, size := utf8.DecodeLastRuneInString(m.query)
+			m.setQuery(m.query[:len(m.query)-size])
+		}
+	case viewer.KeyEnter:
+		if summary, ok := m.Selected(); ok {
+			return Action{Kind: ActionPick, Session: summary.Name}
+		}
+	case keyCtrlR:
+		if summary, ok := m.Selected(); ok {
+			m.renaming, m.input = true, summary.Name
+		}
+	case keyCtrlA:
+		return m.confirm(ActionArchive, "Archive")
+	case keyCtrlX:
+		return m.confirm(ActionDelete, "Delete")
+	default:
+		if isText(key) {
+			m.setQuery(m.query + string(key))
+		}
+	}
+	return Action{}
+}
+
+// setQuery changes the filter, selecting the best match like fzf does
+func (m *Model) setQuery(query string) {
+	m.query = query
+	m.selected = ""
+	m.filter()
+}
+
+// handleRenameKey edits the new name until it is submitted with enter or dropped with escape
+func (m *Model) handleRenameKey(key viewer.Key) Action {
+	switch key {
+	case viewer.KeyEscape, viewer.KeyInterrupt:
+		m.renaming = false
+	case viewer.KeyEnter:
+		m.renaming = false
+		summary, ok := m.Selected()
+		if ok && strings.TrimSpace(m.input) != "" && m.input != summary.Name {
+			return Action{Kind: ActionRename, Session: summary.Name, NewName: strings.TrimSpace(m.input)}
+		}
+	case viewer.KeyBackspace:
+		if m.input != "" {
+			_, size := utf8.DecodeLastRuneInString(m.input)
+			m.input = m.input[:len(m.input)-size]
+		}
+	default:
+		if isText(key) {
+			m.input += string(key)
+		}
+	}
+	return Action{}
+}
+
+// confirm asks before archiving or deleting the selected session
+func (m *Model) confirm(kind ActionKind, verb string) Action {
+	summary, ok := m.Selected()
+	if !ok {
+		return Action{}
+	}
+	m.pending = Action{Kind: kind, Session: summary.Name}
+	m.message = fmt.Sprintf("%s session '%s'? (y/n)", verb, summary.Name)
+	return Action{}
+}
+
+// View renders the picker a line per terminal row: title, filter prompt, matching sessions,
+// a preview of the selected one and the status bar
+func (m *Model) View() string {
+	var b strings.Builder
+
+	title := fmt.Sprintf(" %s · %d of %d session(s) ", m.title, len(m.matches), len(m.sessions))
+	fmt.Fprintf(&b, "\033[7m%s\033[0m\n", pad(terminal.TruncateEnd(title, m.width), m.width))
+	fmt.Fprintf(&b, "\033[1;96m>\033[0m %s\033[7m \033[0m\n", terminal.TruncateEnd(m.query, m.width-3))
+
+	for row := 0; row < m.pageSize(); row++ {
+		index := m.offset + row
+		if index >= len(m.matches) {
+			b.WriteString("\n")
+			continue
+		}
+		summary := m.sessions[m.matches[index]]
+		text := m.sessionRow(summary)
+		switch {
+		case summary.Name == m.selected:
+			fmt.Fprintf(&b, "\033[1;7m%s\033[0m", pad(text, m.width))
+		case summary.State == types.SessionStateArchived || summary.State == types.SessionStateCompleted:
+			fmt.Fprintf(&b, "\033[2m%s\033[0m", text)
+		default:
+			b.WriteString(text)
+		}
+		b.WriteString("\n")
+	}
+
+	if m.showPreview() {
+		fmt.Fprintf(&b, "\033[2m%s\033[0m\n", strings.Repeat("─", m.width))
+		preview := m.preview()
+		for row := 0; row < previewHeight-1; row++ {
+			if row < len(preview) {
+				b.WriteString(terminal.TruncateEnd(preview[row], m.width))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	status := " " + Help
+	switch {
+	case m.renaming:
+		status = fmt.Sprintf(" Rename '%s' to: %s█  (enter to rename, esc to cancel)", m.selected, m.input)
+	case m.pending.Kind != ActionNone || m.message != "":
+		status = " " + m.message
+	case len(m.sessions) == 0:
+		status = " No sessions · esc quit"
+	case len(m.matches) == 0:
+		status = " No session matches · backspace edits the filter · esc clears it"
+	}
+	fmt.Fprintf(&b, "\033[7m%s\033[0m", pad(terminal.TruncateEnd(status, m.width), m.width))
+	return b.String()
+}
+
+// sessionRow renders a session's columns
+func (m *Model) sessionRow(summary session.Summary) string {
+	name := summary.Name
+	if summary.Pinned {
+		name = "📌 " + name
+	}
+	lastAccessed := "-"
+	if !summary.LastAccessed.IsZero() {
+		lastAccessed = summary.LastAccessed.Local().Format("2006-01-02 15:04")
+	}
+	row := fmt.Sprintf("  %-30s %-10s %s  ", terminal.TruncateEnd(name, 30), summary.State, lastAccessed)
+	if len(summary.Tags) > 0 {
+		row += "#" + strings.Join(summary.Tags, " #") + "  "
+	}
+	return terminal.TruncateEnd(row+summary.Description, m.width)
+}
+
+// preview describes the selected session
+func (m *Model) preview() []string {
+	summary, ok := m.Selected()
+	if !ok {
+		return nil
+	}
+
+	lines := []string{fmt.Sprintf("  %s  %s", summary.Name, summary.Description)}
+	lastAccessed := fmt.Sprintf("  Last accessed: %s   Created: %s",
+		summary.LastAccessed.Local().Format("2006-01-02 15:04"), summary.Created.Local().Format("2006-01-02 15:04"))
+	lines = append(lines, lastAccessed)
+
+	claude := "  Claude session: none"
+	if summary.ClaudeSessionID != "" {
+		status := "inactive"
+		if summary.HasActiveContext {
+			status = "active"
+		}
+		claude = fmt.Sprintf("  Claude session: %s (%s)", summary.ClaudeSessionID, status)
+	}
+	lines = append(lines, claude)
+
+	var details []string
+	if len(summary.Tags) > 0 {
+		details = append(details, "Tags: "+strings.Join(summary.Tags, ", "))
+	}
+	if linkSummary := links.Summary(summary.Links); linkSummary != "" {
+		details = append(details, "Links: "+linkSummary)
+	}
+	if len(details) > 0 {
+		lines = append(lines, "  "+strings.Join(details, "   "))
+	}
+
+	directory := summary.WorkingDirectory
+	if directory == "" {
+		directory = summary.ProjectPath
+	}
+	lines = append(lines, fmt.Sprintf("  Project: %s   Directory: %s", filepath.Base(summary.ProjectPath), directory))
+
+	var flags []string
+	if summary.Lock != nil {
+		locked := "Locked"
+		if summary.Lock.Reason != "" {
+			locked += ": " + summary.Lock.Reason
+		}
+		flags = append(flags, locked)
+	}
+	if summary.Protected {
+		flags = append(flags, "Protected")
+	}
+	if summary.ReadOnly {
+		flags = append(flags, "Read-only")
+	}
+	if len(flags) > 0 {
+		lines = append(lines, "  "+strings.Join(flags, " · "))
+	}
+	return lines
+}
+
+// filter recomputes the sessions matching the query, keeping the selection when it still matches
+func (m *Model) filter() {
+	query := strings.ToLower(strings.TrimSpace(m.query))
+	scores := make(map[int]int, len(m.sessions))
+	m.matches = m.matches[:0]
+	for i, summary := range m.sessions {
+		if score := matchScore(summary, query); score > 0 {
+			scores[i] = score
+			m.matches = append(m.matches, i)
+		}
+	}
+	sort.SliceStable(m.matches, func(i, j int) bool { return scores[m.matches[i]] > scores[m.matches[j]] })
+
+	if len(m.matches) == 0 {
+		m.selected = ""
+	} else if _, ok := m.Selected(); !ok {
+		m.selected = m.sessions[m.matches[0]].Name
+	}
+	m.offset = 0
+	m.scrollToSelection()
+}
+
+// Scores of the ways a query can match a session, best first
+const (
+	scoreExactName       = 100
+	scoreNamePrefix      = 80
+	scoreNameContains    = 60
+	scoreDetailsContain  = 40
+	scoreNameSubsequence = 20
+	scoreAny             = 1 // the empty query
+)
+
+// matchScore rates how well a lowercased query matches a session: as a substring of its name,
+// description or tags, or against its name with characters left out ("lgfx" matches "login-fix")
+func matchScore(summary session.Summary, query string) int {
+	if query == "" {
+		return scoreAny
+	}
+	name := strings.ToLower(summary.Name)
+	details := strings.ToLower(summary.Description + " " + strings.Join(summary.Tags, " "))
+	switch {
+	case name == query:
+		return scoreExactName
+	case strings.HasPrefix(name, query):
+		return scoreNamePrefix
+	case strings.Contains(name, query):
+		return scoreNameContains
+	case strings.Contains(details, query):
+		return scoreDetailsContain
+	case isSubsequence(name, query):
+		return scoreNameSubsequence
+	}
+	return 0
+}
+
+// isSubsequence reports whether every character of query appears in text, in order
+func isSubsequence(text, query string) bool {
+	rest := []rune(query)
+	for _, r := range text {
+		if len(rest) == 0 {
+			break
+		}
+		if r == rest[0] {
+			rest = rest[1:]
+		}
+	}
+	return len(rest) == 0
+}
+
+// position returns the selected session's index among the matches, or -1
+func (m *Model) position() int {
+	for i, index := range m.matches {
+		if m.sessions[index].Name == m.selected {
+			return i
+		}
+	}
+	return -1
+}
+
+// moveSelection moves the selection by delta sessions, stopping at either end
+func (m *Model) moveSelection(delta int) {
+	if len(m.matches) == 0 {
+		return
+	}
+	position := m.position() + delta
+	if position < 0 {
+		position = 0
+	}
+	if position >= len(m.matches) {
+		position = len(m.matches) - 1
+	}
+	m.selected = m.sessions[m.matches[position]].Name
+	m.scrollToSelection()
+}
+
+// scrollToSelection keeps the selected session on screen
+func (m *Model) scrollToSelection() {
+	if selected := m.position(); selected >= 0 {
+		if selected < m.offset {
+			m.offset = selected
+		}
+		if selected >= m.offset+m.pageSize() {
+			m.offset = selected - m.pageSize() + 1
+		}
+	}
+	if maxOffset := len(m.matches) - m.pageSize(); m.offset > maxOffset {
+		m.offset = maxOffset
+	}
+	if m.offset < 0 {
+		m.offset = 0
+	}
+}
+
+// showPreview reports whether the terminal is tall enough for the preview below the list
+func (m *Model) showPreview() bool {
+	return m.height >= 3*previewHeight
+}
+
+// pageSize is the number of session rows shown
+func (m *Model) pageSize() int {
+	size := m.height - 3
+	if m.showPreview() {
+		size -= previewHeight
+	}
+	return size
+}
+
+// isText reports whether a key types a character rather than naming a key or a control
+func isText(key viewer.Key) bool {
+	r, size := utf8.DecodeRuneInString(string(key))
+	return size == len(key) && size > 0 && unicode.IsPrint(r)
+}
+
+func pad(text string, width int) string {
+	if n := utf8.RuneCountInString(text); n < width {
+		return text + strings.Repeat(" ", width-n)
+	}
+	return text
+}
@@ -0,0 +1,143 @@
+package picker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bitomule/kamui/internal/session"
+	"github.com/bitomule/kamui/internal/viewer"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+var fixtureTime = time.Date(2026, 3, 14, 9, 30, 0, 0, time.UTC)
+
+func newFixtureModel() *Model {
+	m := New("Kamui · api", []session.Summary{
+		{Name: "login-fix", Description: "Fix the login redirect", Tags: []string{"auth"}, State: types.SessionStateActive,
+			ProjectPath: "/work/api", LastAccessed: fixtureTime, ClaudeSessionID: "abc123", HasActiveContext: true, Pinned: true},
+		{Name: "refactor-parser", Description: "Split the parser", State: types.SessionStateActive, ProjectPath: "/work/api", LastAccessed: fixtureTime},
+		{Name: "release", Tags: []string{"ops"}, State: types.SessionStateCompleted, ProjectPath: "/work/api", LastAccessed: fixtureTime},
+	})
+	m.SetSize(120, 30)
+	return m
+}
+
+func selectedName(m *Model) string {
+	summary, _ := m.Selected()
+	return summary.Name
+}
+
+func typeText(m *Model, text string) {
+	for _, r := range text {
+		m.HandleKey(viewer.Key(string(r)))
+	}
+}
+
+func TestModel_MovesBetweenSessions(t *testing.T) {
+	m := newFixtureModel()
+	assert.Equal(t, "login-fix", selectedName(m))
+
+	m.HandleKey(viewer.KeyDown)
+	assert.Equal(t, "refactor-parser", selectedName(m))
+	m.HandleKey(keyCtrlN)
+	m.HandleKey(keyCtrlN)
+	assert.Equal(t, "release", selectedName(m), "the selection stops at the last session")
+	m.HandleKey(viewer.KeyHome)
+	assert.Equal(t, "login-fix", selectedName(m))
+
+	assert.Equal(t, Action{Kind: ActionPick, Session: "login-fix"}, m.HandleKey(viewer.KeyEnter))
+}
+
+func TestModel_FiltersFuzzily(t *testing.T) {
+	m := newFixtureModel()
+
+	typeText(m, "rp")
+	assert.Equal(t, "refactor-parser", selectedName(m), "letters of the name in order match")
+	assert.Len(t, m.matches, 1)
+
+	m.HandleKey(viewer.KeyEscape)
+	assert.Len(t, m.matches, 3, "escape clears the filter")
+	assert.Equal(t, Action{Kind: ActionQuit}, m.HandleKey(viewer.KeyEscape), "and quits once it is empty")
+
+	m = newFixtureModel()
+	typeText(m, "ops")
+	assert.Equal(t, "release", selectedName(m), "tags match")
+
+	m.HandleKey(viewer.KeyBackspace)
+	m.HandleKey(viewer.KeyBackspace)
+	m.HandleKey(viewer.KeyBackspace)
+	typeText(m, "re")
+	assert.Equal(t, []string{"refactor-parser", "release", "login-fix"}, matchNames(m), "name prefixes rank above descriptions")
+
+	typeText(m, "zz")
+	assert.Empty(t, m.matches)
+	assert.Equal(t, Action{}, m.HandleKey(viewer.KeyEnter))
+	assert.Contains(t, m.View(), "No session matches")
+}
+
+func matchNames(m *Model) []string {
+	names := make([]string, 0, len(m.matches))
+	for _, index := range m.matches {
+		names = append(names, m.sessions[index].Name)
+	}
+	return names
+}
+
+func TestModel_ConfirmsDeleteAndArchive(t *testing.T) {
+	m := newFixtureModel()
+
+	assert.Equal(t, Action{}, m.HandleKey(keyCtrlX))
+	assert.Contains(t, m.View(), "Delete session 'login-fix'? (y/n)")
+	assert.Equal(t, Action{}, m.HandleKey("n"))
+	assert.Equal(t, "", m.query, "the answer isn't typed into the filter")
+
+	m.HandleKey(keyCtrlA)
+	assert.Equal(t, Action{Kind: ActionArchive, Session: "login-fix"}, m.HandleKey("y"))
+}
+
+func TestModel_Renames(t *testing.T) {
+	m := newFixtureModel()
+
+	m.HandleKey(keyCtrlR)
+	assert.Contains(t, m.View(), "Rename 'login-fix' to: login-fix")
+	for range "fix" {
+		m.HandleKey(viewer.KeyBackspace)
+	}
+	typeText(m, "redirect")
+	assert.Equal(t, Action{Kind: ActionRename, Session: "login-fix", NewName: "login-redirect"}, m.HandleKey(viewer.KeyEnter))
+	assert.Equal(t, "", m.query)
+
+	m.HandleKey(keyCtrlR)
+	typeText(m, "-2")
+	assert.Equal(t, Action{}, m.HandleKey(viewer.KeyEscape), "escape drops the rename")
+	assert.Equal(t, "", m.query)
+
+	m.HandleKey(keyCtrlR)
+	assert.Equal(t, Action{}, m.HandleKey(viewer.KeyEnter), "unchanged names aren't renamed")
+}
+
+func TestModel_KeepsSelectionAcrossRefreshes(t *testing.T) {
+	m := newFixtureModel()
+	m.HandleKey(viewer.KeyDown)
+
+	m.SetSessions(append([]session.Summary{{Name: "new"}}, m.sessions...))
+	assert.Equal(t, "refactor-parser", selectedName(m))
+
+	m.SetSessions([]session.Summary{m.sessions[0], m.sessions[3]})
+	assert.Equal(t, "new", selectedName(m), "a deleted selection moves to the first session")
+}
+
+func TestModel_ViewShowsPreview(t *testing.T) {
+	m := newFixtureModel()
+	view := m.View()
+	assert.Contains(t, view, "Kamui · api · 3 of 3 session(s)")
+	assert.Contains(t, view, "📌 login-fix")
+	assert.Contains(t, view, "#auth")
+	assert.Contains(t, view, "Claude session: abc123 (active)")
+	assert.Contains(t, view, "Tags: auth")
+
+	m.SetSize(120, 12)
+	assert.NotContains(t, m.View(), "Claude session:", "short terminals leave the preview out")
+}
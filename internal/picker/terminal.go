@@ -0,0 +1,142 @@
+package picker
+
+import (
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/term"
+
+	"github.com/bitomule/kamui/internal/session"
+	"github.com/bitomule/kamui/internal/viewer"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Source supplies the sessions to pick from and carries out the actions taken in the picker
+type Source interface {
+	// Sessions lists the sessions again after an action changed them
+	Sessions() ([]session.Summary, error)
+	// Apply renames, archives or deletes a session, returning a message for the status bar
+	Apply(action Action) (string, error)
+}
+
+// Run shows the model full-screen until the user picks a session, whose name is returned, or
+// quits, which returns ""
+// It runs as a Bubble Tea program on the alternate screen, so the view follows terminal resizes
+func Run(m *Model, source Source, in, out *os.File) (string, error) {
+	if !term.IsTerminal(int(in.Fd())) || !term.IsTerminal(int(out.Fd())) {
+		return "", types.NewSessionError(types.ErrCodeInvalidInput, "the session picker needs an interactive terminal", nil)
+	}
+
+	p := &program{model: m, source: source}
+	if _, err := tea.NewProgram(p, tea.WithInput(in), tea.WithOutput(out), tea.WithAltScreen()).Run(); err != nil {
+		return "", fmt.Errorf("session picker failed: %w", err)
+	}
+	return p.picked, nil
+}
+
+// program runs a Model as a Bubble Tea model, carrying out its actions through source
+type program struct {
+	model  *Model
+	source Source
+	picked string
+	// busy is set while an action is carried out; keys are ignored until it is done
+	busy bool
+}
+
+// appliedMsg reports the outcome of an action and the sessions listed after it
+type appliedMsg struct {
+	action   Action
+	message  string
+	sessions []session.Summary
+	err      error
+}
+
+func (p *program) Init() tea.Cmd {
+	return nil
+}
+
+func (p *program) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		p.model.SetSize(msg.Width, msg.Height)
+	case tea.KeyMsg:
+		if p.busy {
+			return p, nil
+		}
+		for _, key := range keysOf(msg) {
+			action := p.model.HandleKey(key)
+			switch action.Kind {
+			case ActionQuit:
+				return p, tea.Quit
+			case ActionPick:
+				p.picked = action.Session
+				return p, tea.Quit
+			case ActionRename, ActionArchive, ActionDelete:
+				p.busy = true
+				return p, p.apply(action)
+			}
+		}
+	case appliedMsg:
+		p.busy = false
+		if msg.sessions != nil {
+			p.model.SetSessions(msg.sessions)
+		}
+		message := msg.message
+		if msg.err != nil {
+			message = fmt.Sprintf("Error: %v", msg.err)
+		} else if msg.action.Kind == ActionRename {
+			p.model.Select(msg.action.NewName)
+		}
+		p.model.SetMessage(message)
+	}
+	return p, nil
+}
+
+func (p *program) View() string {
+	return p.model.View()
+}
+
+// apply carries out an action off the event loop and lists the sessions again
+func (p *program) apply(action Action) tea.Cmd {
+	return func() tea.Msg {
+		message, err := p.source.Apply(action)
+		sessions, listErr := p.source.Sessions()
+		if err == nil {
+			err = listErr
+		}
+		return appliedMsg{action: action, message: message, sessions: sessions, err: err}
+	}
+}
+
+// keysOf translates a Bubble Tea key into the keys the model handles; pasted text arrives as
+// several runes, typed one after the other
+func keysOf(msg tea.KeyMsg) []viewer.Key {
+	switch msg.Type {
+	case tea.KeyRunes:
+		if msg.Alt {
+			return nil
+		}
+		keys := make([]viewer.Key, 0, len(msg.Runes))
+		for _, r := range msg.Runes {
+			keys = append(keys, viewer.Key(string(r)))
+		}
+		return keys
+	case tea.KeySpace:
+		return []viewer.Key{" "}
+	case tea.KeyCtrlC:
+		return []viewer.Key{viewer.KeyInterrupt}
+	case tea.KeyCtrlA:
+		return []viewer.Key{keyCtrlA}
+	case tea.KeyCtrlN:
+		return []viewer.Key{keyCtrlN}
+	case tea.KeyCtrlP:
+		return []viewer.Key{keyCtrlP}
+	case tea.KeyCtrlR:
+		return []viewer.Key{keyCtrlR}
+	case tea.KeyCtrlX:
+		return []viewer.Key{keyCtrlX}
+	}
+	// the names of the remaining keys, such as "up", "pgdown" and "esc", are the viewer's
+	return []viewer.Key{viewer.Key(msg.String())}
+}
@@ -0,0 +1,75 @@
+package picker
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/session"
+)
+
+// fakeSource renames sessions in memory
+type fakeSource struct {
+	sessions []session.Summary
+}
+
+func (s *fakeSource) Sessions() ([]session.Summary, error) {
+	return s.sessions, nil
+}
+
+func (s *fakeSource) Apply(action Action) (string, error) {
+	for i := range s.sessions {
+		if s.sessions[i].Name == action.Session {
+			s.sessions[i].Name = action.NewName
+		}
+	}
+	return "Renamed session '" + action.Session + "'", nil
+}
+
+func TestProgram_Resize(t *testing.T) {
+	p := &program{model: newFixtureModel()}
+
+	p.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	assert.Len(t, strings.Split(p.View(), "\n"), 40, "the view fills the resized terminal")
+
+	p.Update(tea.WindowSizeMsg{Width: 100, Height: 12})
+	assert.Len(t, strings.Split(p.View(), "\n"), 12)
+	assert.NotContains(t, p.View(), "Claude session:", "the preview is dropped once the terminal is too short")
+}
+
+func TestProgram_PickAndQuit(t *testing.T) {
+	p := &program{model: newFixtureModel()}
+
+	p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("parser")})
+	_, cmd := p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.NotNil(t, cmd)
+	assert.Equal(t, tea.QuitMsg{}, cmd())
+	assert.Equal(t, "refactor-parser", p.picked)
+
+	p = &program{model: newFixtureModel()}
+	_, cmd = p.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	require.NotNil(t, cmd)
+	assert.Equal(t, tea.QuitMsg{}, cmd())
+	assert.Empty(t, p.picked)
+}
+
+func TestProgram_AppliesActions(t *testing.T) {
+	m := newFixtureModel()
+	source := &fakeSource{sessions: append([]session.Summary(nil), m.sessions...)}
+	p := &program{model: m, source: source}
+
+	p.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("-v2")})
+	_, cmd := p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.NotNil(t, cmd)
+	assert.True(t, p.busy)
+
+	p.Update(tea.KeyMsg{Type: tea.KeyDown})
+	p.Update(cmd())
+	assert.False(t, p.busy)
+	assert.Equal(t, "login-fix-v2", selectedName(m), "keys sent while the rename ran are ignored, and the renamed session stays selected")
+	assert.Contains(t, p.View(), "Renamed session 'login-fix'")
+}
@@ -0,0 +1,22 @@
+//go:build !windows
+
+package plugin
+
+import (
+	"os"
+	"strings"
+)
+
+// isExecutable reports whether path is a regular file with an execute bit set
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	return info.Mode().Perm()&0o111 != 0
+}
+
+// trimExecutableExt strips the .exe of plugins built for Windows from a file name
+func trimExecutableExt(fileName string) string {
+	return strings.TrimSuffix(fileName, ".exe")
+}
@@ -0,0 +1,45 @@
+//go:build windows
+
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultPathExt is used when PATHEXT isn't set
+const defaultPathExt = ".COM;.EXE;.BAT;.CMD"
+
+// isExecutable reports whether path is a regular file with one of the PATHEXT extensions;
+// Windows has no execute bit
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	return executableExt(path) != ""
+}
+
+// trimExecutableExt strips the PATHEXT extension from a file name
+func trimExecutableExt(fileName string) string {
+	return strings.TrimSuffix(fileName, executableExt(fileName))
+}
+
+// executableExt returns the extension of path if PATHEXT lists it, or ""
+func executableExt(path string) string {
+	ext := filepath.Ext(path)
+	if ext == "" {
+		return ""
+	}
+	pathExt := os.Getenv("PATHEXT")
+	if pathExt == "" {
+		pathExt = defaultPathExt
+	}
+	for _, candidate := range filepath.SplitList(pathExt) {
+		if strings.EqualFold(candidate, ext) {
+			return ext
+		}
+	}
+	return ""
+}
@@ -0,0 +1,127 @@
+// Package plugin discovers and runs external Kamui plugins
+//
+// A plugin is any executable named kam-<name> on PATH. Running `kam <name> args...`
+// executes the plugin with the arguments and a JSON Request document on stdin.
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bitomule/kamui/internal/trace"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Prefix is the executable name prefix that identifies Kamui plugins
+const Prefix = "kam-"
+
+// ProtocolVersion is the version of the JSON request sent to plugins
+const ProtocolVersion = 1
+
+// Plugin is an executable discovered on PATH
+type Plugin struct {
+	Name string
+	Path string
+}
+
+// Request is the JSON document written to a plugin's stdin
+type Request struct {
+	ProtocolVersion int      `json:"protocolVersion"`
+	KamuiVersion    string   `json:"kamuiVersion"`
+	Plugin          string   `json:"plugin"`
+	Args            []string `json:"args"`
+	WorkingDir      string   `json:"workingDir"`
+	SessionsDir     string   `json:"sessionsDir"`
+	CurrentSession  string   `json:"currentSession,omitempty"`
+}
+
+// Discover returns the plugins found in the directories of pathList
+// When several directories provide the same plugin, the first one on PATH wins
+func Discover(pathList string) []Plugin {
+	seen := make(map[string]bool)
+	var plugins []Plugin
+
+	for _, dir := range filepath.SplitList(pathList) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue // missing or unreadable PATH entries are common
+		}
+
+		for _, entry := range entries {
+			name, ok := pluginName(entry.Name())
+			if !ok || seen[name] || entry.IsDir() {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+
+			seen[name] = true
+			plugins = append(plugins, Plugin{Name: name, Path: path})
+		}
+	}
+
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].Name < plugins[j].Name
+	})
+	return plugins
+}
+
+// Run executes the plugin with the request on stdin and the given output streams
+// A plugin exiting non-zero returns its *exec.ExitError, so callers can pass the status on
+func Run(p Plugin, req Request, stdout, stderr io.Writer) error {
+	req.ProtocolVersion = ProtocolVersion
+	req.Plugin = p.Name
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to encode plugin request: %w", err)
+	}
+
+	cmd := exec.Command(p.Path, req.Args...)
+	cmd.Dir = req.WorkingDir
+	cmd.Stdin = strings.NewReader(string(payload))
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.Env = append(os.Environ(),
+		fmt.Sprintf("KAMUI_PLUGIN_PROTOCOL=%d", ProtocolVersion),
+		fmt.Sprintf("KAMUI_PLUGIN_NAME=%s", p.Name),
+	)
+
+	if err := trace.Run(cmd); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr // the plugin reported its own failure
+		}
+		return types.NewDependencyError(
+			fmt.Sprintf("plugin '%s' failed", p.Name),
+			err,
+		).WithContext("path", p.Path)
+	}
+
+	return nil
+}
+
+// pluginName extracts the plugin name from an executable file name
+func pluginName(fileName string) (string, bool) {
+	if !strings.HasPrefix(fileName, Prefix) {
+		return "", false
+	}
+	name := trimExecutableExt(strings.TrimPrefix(fileName, Prefix))
+	if name == "" || strings.HasPrefix(name, "-") {
+		return "", false
+	}
+	return name, true
+}
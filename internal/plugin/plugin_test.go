@@ -0,0 +1,94 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeExecutable(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o755))
+	return path
+}
+
+func TestDiscover(t *testing.T) {
+	first := t.TempDir()
+	second := t.TempDir()
+
+	writeExecutable(t, first, "kam-report", "#!/bin/sh\n")
+	writeExecutable(t, second, "kam-report", "#!/bin/sh\n") // shadowed by first
+	writeExecutable(t, second, "kam-deploy", "#!/bin/sh\n")
+	writeExecutable(t, second, "kubectl-foo", "#!/bin/sh\n")
+	require.NoError(t, os.WriteFile(filepath.Join(second, "kam-notexec"), []byte(""), 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(second, "kam-dir"), 0o755))
+
+	plugins := Discover(strings.Join([]string{first, "", "/nonexistent", second}, string(os.PathListSeparator)))
+
+	require.Len(t, plugins, 2)
+	assert.Equal(t, "deploy", plugins[0].Name)
+	assert.Equal(t, "report", plugins[1].Name)
+	assert.Equal(t, filepath.Join(first, "kam-report"), plugins[1].Path)
+}
+
+func TestPluginName(t *testing.T) {
+	name, ok := pluginName("kam-stats")
+	assert.True(t, ok)
+	assert.Equal(t, "stats", name)
+
+	name, ok = pluginName("kam-stats.exe")
+	assert.True(t, ok)
+	assert.Equal(t, "stats", name)
+
+	_, ok = pluginName("kam-")
+	assert.False(t, ok)
+	_, ok = pluginName("kam--x")
+	assert.False(t, ok)
+	_, ok = pluginName("kamui")
+	assert.False(t, ok)
+}
+
+func TestRun_SendsRequestOnStdin(t *testing.T) {
+	dir := t.TempDir()
+	path := writeExecutable(t, dir, "kam-echo", "#!/bin/sh\necho \"args:$*\"\ncat\n")
+
+	var stdout, stderr bytes.Buffer
+	err := Run(Plugin{Name: "echo", Path: path}, Request{
+		KamuiVersion: "test",
+		Args:         []string{"one", "two"},
+		WorkingDir:   dir,
+	}, &stdout, &stderr)
+	require.NoError(t, err)
+
+	lines := strings.SplitN(stdout.String(), "\n", 2)
+	assert.Equal(t, "args:one two", lines[0])
+
+	var req Request
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &req))
+	assert.Equal(t, ProtocolVersion, req.ProtocolVersion)
+	assert.Equal(t, "echo", req.Plugin)
+	assert.Equal(t, []string{"one", "two"}, req.Args)
+	assert.Equal(t, "test", req.KamuiVersion)
+}
+
+func TestRun_Failure(t *testing.T) {
+	dir := t.TempDir()
+	path := writeExecutable(t, dir, "kam-fail", "#!/bin/sh\nexit 3\n")
+
+	err := Run(Plugin{Name: "fail", Path: path}, Request{WorkingDir: dir}, &bytes.Buffer{}, &bytes.Buffer{})
+	var exitErr *exec.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, 3, exitErr.ExitCode(), "the plugin's exit status is passed on")
+
+	err = Run(Plugin{Name: "missing", Path: filepath.Join(dir, "kam-missing")}, Request{WorkingDir: dir}, &bytes.Buffer{}, &bytes.Buffer{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "plugin 'missing' failed")
+}
@@ -0,0 +1,184 @@
+// Package prdraft builds pull request titles and descriptions from a session's work
+package prdraft
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/bitomule/kamui/internal/git"
+	"github.com/bitomule/kamui/internal/links"
+	"github.com/bitomule/kamui/internal/transcript"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// maxTitleLength keeps titles within what GitHub shows without truncating
+const maxTitleLength = 72
+
+// verificationPattern matches commands worth listing as how the change was verified
+var verificationPattern = regexp.MustCompile(`\b(test|tests|lint|vet|check|build|spec)\b`)
+
+// Input is everything known about a session's work
+type Input struct {
+	Session     *types.Session
+	Summary     transcript.Summary
+	FirstPrompt string
+	Commits     []git.Commit
+	// CloseIssues links GitHub issues with "Closes" so merging closes them; they are only
+	// referenced otherwise
+	CloseIssues bool
+}
+
+// Draft is a pull request title and Markdown body
+type Draft struct {
+	Title string
+	Body  string
+}
+
+// Markdown renders the draft as a single document with the title as heading
+func (d Draft) Markdown() string {
+	return fmt.Sprintf("# %s\n\n%s", d.Title, d.Body)
+}
+
+// Build synthesizes a draft from the session description, transcript summary, notes, links and commits
+func Build(in Input) Draft {
+	return Draft{Title: title(in), Body: body(in)}
+}
+
+// title prefers the session description, then a single commit's subject, then the first prompt
+func title(in Input) string {
+	candidates := []string{in.Session.Metadata.Description}
+	if len(in.Commits) == 1 {
+		candidates = append(candidates, in.Commits[0].Subject)
+	}
+	candidates = append(candidates, in.FirstPrompt)
+
+	for _, candidate := range candidates {
+		if line := strings.TrimSpace(firstLine(candidate)); line != "" {
+			return shorten(capitalize(line), maxTitleLength)
+		}
+	}
+	return capitalize(strings.NewReplacer("-", " ", "_", " ").Replace(in.Session.SessionID))
+}
+
+func body(in Input) string {
+	var b strings.Builder
+	workingDir := in.Session.Project.WorkingDirectory
+
+	b.WriteString("## Summary\n\n")
+	switch {
+	case in.Session.Metadata.Description != "" && strings.TrimSpace(in.FirstPrompt) != "":
+		fmt.Fprintf(&b, "%s\n\n%s\n", in.Session.Metadata.Description, quote(in.FirstPrompt))
+	case in.Session.Metadata.Description != "":
+		fmt.Fprintf(&b, "%s\n", in.Session.Metadata.Description)
+	case strings.TrimSpace(in.FirstPrompt) != "":
+		fmt.Fprintf(&b, "%s\n", quote(in.FirstPrompt))
+	default:
+		fmt.Fprintf(&b, "Work from session `%s`.\n", in.Session.SessionID)
+	}
+
+	if len(in.Summary.FilesEdited) > 0 {
+		b.WriteString("\n## Changes\n\n")
+		for _, file := range in.Summary.FilesEdited {
+			if rel, err := filepath.Rel(workingDir, file); err == nil && !strings.HasPrefix(rel, "..") {
+				file = rel
+			}
+			fmt.Fprintf(&b, "- `%s`\n", file)
+		}
+	}
+
+	if len(in.Commits) > 0 {
+		b.WriteString("\n## Commits\n\n")
+		for _, commit := range in.Commits {
+			fmt.Fprintf(&b, "- %s %s\n", commit.Hash, commit.Subject)
+		}
+	}
+
+	var verification []string
+	for _, command := range in.Summary.Commands {
+		if verificationPattern.MatchString(command) {
+			verification = append(verification, command)
+		}
+	}
+	if len(verification) > 0 {
+		b.WriteString("\n## Verification\n\n")
+		for _, command := range verification {
+			fmt.Fprintf(&b, "- `%s`\n", strings.ReplaceAll(firstLine(command), "`", "'"))
+		}
+	}
+
+	if len(in.Session.Metadata.Notes) > 0 {
+		b.WriteString("\n## Notes\n\n")
+		for _, note := range in.Session.Metadata.Notes {
+			fmt.Fprintf(&b, "- %s\n", strings.ReplaceAll(strings.TrimSpace(note.Text), "\n", "\n  "))
+		}
+	}
+
+	if len(in.Session.Metadata.Links) > 0 {
+		b.WriteString("\n## Related\n\n")
+		for _, link := range in.Session.Metadata.Links {
+			if link.Kind == links.KindGitHubIssue {
+				keyword := "Refs"
+				if in.CloseIssues {
+					keyword = "Closes"
+				}
+				fmt.Fprintf(&b, "- %s %s\n", keyword, link.URL)
+				continue
+			}
+			fmt.Fprintf(&b, "- [%s](%s)\n", link.Label, link.URL)
+		}
+	}
+
+	return b.String()
+}
+
+// RefinePrompt asks Claude to rewrite a draft, keeping the format ParseRefined expects
+func RefinePrompt(d Draft) string {
+	return "Rewrite the following pull request draft so it reads well for a reviewer. " +
+		"Keep it factual and concise, keep the Markdown sections that matter and drop the rest. " +
+		"Reply with only the result: the title on the first line prefixed with '# ', a blank line, then the description.\n\n" +
+		d.Markdown()
+}
+
+// ParseRefined reads a draft back from Claude's reply: a "# Title" line followed by the body
+func ParseRefined(text string) (Draft, error) {
+	text = strings.TrimSpace(text)
+	heading, rest, _ := strings.Cut(text, "\n")
+	heading = strings.TrimSpace(heading)
+	if !strings.HasPrefix(heading, "# ") {
+		return Draft{}, types.NewClaudeError(types.ErrCodeClaudeCommandFailed, "refined draft does not start with a '# ' title line", nil)
+	}
+
+	return Draft{
+		Title: strings.TrimSpace(strings.TrimPrefix(heading, "# ")),
+		Body:  strings.TrimSpace(rest) + "\n",
+	}, nil
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
+
+func quote(s string) string {
+	return "> " + strings.ReplaceAll(strings.TrimSpace(s), "\n", "\n> ")
+}
+
+func capitalize(s string) string {
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return s
+	}
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+func shorten(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:max-1])) + "…"
+}
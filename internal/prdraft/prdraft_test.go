@@ -0,0 +1,112 @@
+package prdraft
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/git"
+	"github.com/bitomule/kamui/internal/transcript"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func newSession(name, description string) *types.Session {
+	return &types.Session{
+		SessionID: name,
+		Project:   types.ProjectInfo{WorkingDirectory: "/work/app"},
+		Metadata:  types.SessionMeta{Description: description},
+	}
+}
+
+func TestBuild_Title(t *testing.T) {
+	assert.Equal(t, "Fix token refresh", Build(Input{Session: newSession("s", "fix token refresh")}).Title)
+
+	single := Input{Session: newSession("s", ""), Commits: []git.Commit{{Hash: "abc1234", Subject: "Refresh expired tokens"}}}
+	assert.Equal(t, "Refresh expired tokens", Build(single).Title)
+
+	prompt := Input{Session: newSession("s", ""), FirstPrompt: "why does login fail?\nsee logs"}
+	assert.Equal(t, "Why does login fail?", Build(prompt).Title)
+
+	assert.Equal(t, "Login flow fix", Build(Input{Session: newSession("login-flow_fix", "")}).Title)
+
+	long := Build(Input{Session: newSession("s", strings.Repeat("word ", 30))}).Title
+	assert.LessOrEqual(t, len([]rune(long)), maxTitleLength)
+	assert.True(t, strings.HasSuffix(long, "…"))
+}
+
+func TestBuild_Body(t *testing.T) {
+	session := newSession("login-fix", "Fix token refresh")
+	session.Metadata.Notes = []types.Note{{Text: "Weekly check found no regressions"}}
+	session.Metadata.Links = []types.Link{
+		{Kind: "github-issue", URL: "https://github.com/org/app/issues/7", Label: "org/app#7"},
+		{Kind: "jira", URL: "https://acme.atlassian.net/browse/APP-3", Label: "APP-3"},
+	}
+
+	draft := Build(Input{
+		Session:     session,
+		FirstPrompt: "Why does login fail?",
+		Summary: transcript.Summary{
+			FilesEdited: []string{"/work/app/login.go", "/elsewhere/notes.md"},
+			Commands:    []string{"go test ./...", "git status"},
+		},
+		Commits: []git.Commit{{Hash: "abc1234", Subject: "Refresh tokens"}, {Hash: "def5678", Subject: "Add test"}},
+	})
+
+	assert.Equal(t, `## Summary
+
+Fix token refresh
+
+> Why does login fail?
+
+## Changes
+
+- `+"`login.go`"+`
+- `+"`/elsewhere/notes.md`"+`
+
+## Commits
+
+- abc1234 Refresh tokens
+- def5678 Add test
+
+## Verification
+
+- `+"`go test ./...`"+`
+
+## Notes
+
+- Weekly check found no regressions
+
+## Related
+
+- Refs https://github.com/org/app/issues/7
+- [APP-3](https://acme.atlassian.net/browse/APP-3)
+`, draft.Body)
+}
+
+func TestBuild_CloseIssues(t *testing.T) {
+	session := newSession("login-fix", "Fix token refresh")
+	session.Metadata.Links = []types.Link{{Kind: "github-issue", URL: "https://github.com/org/app/issues/7", Label: "org/app#7"}}
+
+	assert.Contains(t, Build(Input{Session: session}).Body, "- Refs https://github.com/org/app/issues/7\n")
+	assert.Contains(t, Build(Input{Session: session, CloseIssues: true}).Body, "- Closes https://github.com/org/app/issues/7\n")
+}
+
+func TestBuild_MinimalBody(t *testing.T) {
+	draft := Build(Input{Session: newSession("quick", "")})
+	assert.Equal(t, "## Summary\n\nWork from session `quick`.\n", draft.Body)
+}
+
+func TestRefineRoundTrip(t *testing.T) {
+	draft := Draft{Title: "Fix token refresh", Body: "## Summary\n\nRefreshes tokens.\n"}
+	assert.Contains(t, RefinePrompt(draft), draft.Markdown())
+
+	refined, err := ParseRefined("\n# Refresh expired login tokens\n\nTokens are now refreshed before expiry.\n")
+	require.NoError(t, err)
+	assert.Equal(t, "Refresh expired login tokens", refined.Title)
+	assert.Equal(t, "Tokens are now refreshed before expiry.\n", refined.Body)
+
+	_, err = ParseRefined("Here is your draft: ...")
+	assert.Error(t, err)
+}
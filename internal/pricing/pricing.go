@@ -0,0 +1,115 @@
+// Package pricing estimates Claude usage costs from a per-model price table
+package pricing
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/bitomule/kamui/internal/transcript"
+)
+
+// Price is the USD price per million tokens for a model
+type Price struct {
+	Input      float64 `json:"input" mapstructure:"input"`
+	Output     float64 `json:"output" mapstructure:"output"`
+	CacheWrite float64 `json:"cacheWrite" mapstructure:"cacheWrite"`
+	CacheRead  float64 `json:"cacheRead" mapstructure:"cacheRead"`
+}
+
+// Table maps model name prefixes to prices; the longest matching prefix wins
+type Table map[string]Price
+
+// DefaultTable holds list prices for Claude models, in USD per million tokens
+var DefaultTable = Table{
+	"claude-opus-4-5":   {Input: 5, Output: 25, CacheWrite: 6.25, CacheRead: 0.50},
+	"claude-opus-4":     {Input: 15, Output: 75, CacheWrite: 18.75, CacheRead: 1.50},
+	"claude-sonnet-4":   {Input: 3, Output: 15, CacheWrite: 3.75, CacheRead: 0.30},
+	"claude-haiku-4-5":  {Input: 1, Output: 5, CacheWrite: 1.25, CacheRead: 0.10},
+	"claude-3-opus":     {Input: 15, Output: 75, CacheWrite: 18.75, CacheRead: 1.50},
+	"claude-3-7-sonnet": {Input: 3, Output: 15, CacheWrite: 3.75, CacheRead: 0.30},
+	"claude-3-5-sonnet": {Input: 3, Output: 15, CacheWrite: 3.75, CacheRead: 0.30},
+	"claude-3-5-haiku":  {Input: 0.80, Output: 4, CacheWrite: 1, CacheRead: 0.08},
+	"claude-3-haiku":    {Input: 0.25, Output: 1.25, CacheWrite: 0.30, CacheRead: 0.03},
+}
+
+// Estimate is the estimated cost of some usage
+type Estimate struct {
+	CostUSD float64
+	Tokens  int64
+
+	// Unpriced lists models with usage but no matching price; their tokens are not in CostUSD
+	Unpriced []string
+}
+
+// Add accumulates another estimate
+func (e *Estimate) Add(other Estimate) {
+	e.CostUSD += other.CostUSD
+	e.Tokens += other.Tokens
+	for _, model := range other.Unpriced {
+		if !contains(e.Unpriced, model) {
+			e.Unpriced = append(e.Unpriced, model)
+		}
+	}
+	sort.Strings(e.Unpriced)
+}
+
+// Merge returns a table with overrides applied on top of t
+func (t Table) Merge(overrides Table) Table {
+	merged := make(Table, len(t)+len(overrides))
+	for model, price := range t {
+		merged[model] = price
+	}
+	for model, price := range overrides {
+		merged[model] = price
+	}
+	return merged
+}
+
+// Lookup returns the price for a model by longest matching prefix
+func (t Table) Lookup(model string) (Price, bool) {
+	best := ""
+	for prefix := range t {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return Price{}, false
+	}
+	return t[best], true
+}
+
+// Cost returns the USD cost of usage at this price
+func (p Price) Cost(usage transcript.Usage) float64 {
+	return (float64(usage.InputTokens)*p.Input +
+		float64(usage.OutputTokens)*p.Output +
+		float64(usage.CacheCreationInputTokens)*p.CacheWrite +
+		float64(usage.CacheReadInputTokens)*p.CacheRead) / 1e6
+}
+
+// EstimateUsage prices per-model usage
+func (t Table) EstimateUsage(byModel map[string]transcript.Usage) Estimate {
+	var estimate Estimate
+	for model, usage := range byModel {
+		estimate.Tokens += usage.TotalTokens()
+		price, ok := t.Lookup(model)
+		if !ok {
+			if usage.TotalTokens() > 0 {
+				estimate.Unpriced = append(estimate.Unpriced, model)
+			}
+			continue
+		}
+		estimate.CostUSD += price.Cost(usage)
+	}
+	sort.Strings(estimate.Unpriced)
+	return estimate
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
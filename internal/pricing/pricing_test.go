@@ -0,0 +1,60 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/transcript"
+)
+
+func TestLookup_LongestPrefix(t *testing.T) {
+	price, ok := DefaultTable.Lookup("claude-opus-4-5-20251101")
+	require.True(t, ok)
+	assert.InDelta(t, 5.0, price.Input, 1e-9)
+
+	price, ok = DefaultTable.Lookup("claude-opus-4-1-20250805")
+	require.True(t, ok)
+	assert.InDelta(t, 15.0, price.Input, 1e-9)
+
+	_, ok = DefaultTable.Lookup("gpt-4o")
+	assert.False(t, ok)
+}
+
+func TestPriceCost(t *testing.T) {
+	price := Price{Input: 3, Output: 15, CacheWrite: 3.75, CacheRead: 0.30}
+	usage := transcript.Usage{InputTokens: 1_000_000, OutputTokens: 100_000, CacheCreationInputTokens: 200_000, CacheReadInputTokens: 2_000_000}
+
+	assert.InDelta(t, 3+1.5+0.75+0.6, price.Cost(usage), 1e-9)
+}
+
+func TestEstimateUsage(t *testing.T) {
+	table := DefaultTable.Merge(Table{"my-proxy-model": {Input: 1, Output: 2}})
+
+	estimate := table.EstimateUsage(map[string]transcript.Usage{
+		"claude-sonnet-4-20250514": {InputTokens: 1_000_000},
+		"my-proxy-model":           {OutputTokens: 1_000_000},
+		"unknown-model":            {InputTokens: 10},
+	})
+
+	assert.InDelta(t, 5.0, estimate.CostUSD, 1e-9)
+	assert.Equal(t, int64(2_000_010), estimate.Tokens)
+	assert.Equal(t, []string{"unknown-model"}, estimate.Unpriced)
+}
+
+func TestEstimateAdd(t *testing.T) {
+	total := Estimate{CostUSD: 1, Tokens: 10, Unpriced: []string{"b"}}
+	total.Add(Estimate{CostUSD: 2, Tokens: 5, Unpriced: []string{"a", "b"}})
+
+	assert.InDelta(t, 3.0, total.CostUSD, 1e-9)
+	assert.Equal(t, int64(15), total.Tokens)
+	assert.Equal(t, []string{"a", "b"}, total.Unpriced)
+}
+
+func TestMerge_DoesNotModifyDefaults(t *testing.T) {
+	merged := DefaultTable.Merge(Table{"claude-sonnet-4": {Input: 99}})
+
+	assert.InDelta(t, 99.0, merged["claude-sonnet-4"].Input, 1e-9)
+	assert.InDelta(t, 3.0, DefaultTable["claude-sonnet-4"].Input, 1e-9)
+}
@@ -0,0 +1,18 @@
+//go:build windows
+
+package process
+
+import "os"
+
+// Alive reports whether a process with the pid exists; on Windows finding it opens a handle
+func Alive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = process.Release() // the handle was only needed to look the process up
+	return true
+}
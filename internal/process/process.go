@@ -0,0 +1,20 @@
+// Package process looks up and stops other processes by pid, such as the holders of session locks
+// and background session monitors
+package process
+
+import "os"
+
+// Kill stops a process; a process that already exited is not an error
+func Kill(pid int) error {
+	if !Alive(pid) {
+		return nil
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	if err := process.Kill(); err != nil && Alive(pid) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package process
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlive(t *testing.T) {
+	assert.True(t, Alive(os.Getpid()))
+	assert.False(t, Alive(0))
+	assert.False(t, Alive(-1))
+}
+
+func TestKill(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX sleep")
+	}
+	cmd := exec.Command("sleep", "30")
+	require.NoError(t, cmd.Start())
+	pid := cmd.Process.Pid
+	require.True(t, Alive(pid))
+
+	require.NoError(t, Kill(pid))
+	_ = cmd.Wait() // reap it so the pid is gone
+	assert.Eventually(t, func() bool { return !Alive(pid) }, time.Second, 10*time.Millisecond)
+	assert.NoError(t, Kill(pid), "killing an exited process is not an error")
+}
+
+func TestStartTime(t *testing.T) {
+	start := StartTime(os.Getpid())
+	assert.NotEmpty(t, start)
+	assert.Equal(t, start, StartTime(os.Getpid()), "a running process keeps its start time")
+	assert.Empty(t, StartTime(0))
+}
@@ -0,0 +1,31 @@
+//go:build linux
+
+package process
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// StartTime returns when a process started, in clock ticks since boot, or "" when it can't be read
+// Together with the pid it tells a process apart from a later one that reused its pid
+func StartTime(pid int) string {
+	if pid <= 0 {
+		return ""
+	}
+	data, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return ""
+	}
+	// the command name in parentheses may hold spaces; starttime is the 20th field after it
+	end := strings.LastIndexByte(string(data), ')')
+	if end < 0 {
+		return ""
+	}
+	fields := strings.Fields(string(data[end+1:]))
+	if len(fields) < 20 {
+		return ""
+	}
+	return fields[19]
+}
@@ -0,0 +1,22 @@
+//go:build !linux && !windows
+
+package process
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// StartTime returns when a process started, as ps reports it, or "" when it can't be read
+// Together with the pid it tells a process apart from a later one that reused its pid
+func StartTime(pid int) string {
+	if pid <= 0 {
+		return ""
+	}
+	out, err := exec.Command("ps", "-o", "lstart=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
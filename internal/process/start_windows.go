@@ -0,0 +1,28 @@
+//go:build windows
+
+package process
+
+import (
+	"strconv"
+
+	"golang.org/x/sys/windows"
+)
+
+// StartTime returns when a process was created, in nanoseconds since 1970, or "" when it can't be read
+// Together with the pid it tells a process apart from a later one that reused its pid
+func StartTime(pid int) string {
+	if pid <= 0 {
+		return ""
+	}
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return ""
+	}
+	defer windows.CloseHandle(handle)
+
+	var created, exited, kernel, user windows.Filetime
+	if err := windows.GetProcessTimes(handle, &created, &exited, &kernel, &user); err != nil {
+		return ""
+	}
+	return strconv.FormatInt(created.Nanoseconds(), 10)
+}
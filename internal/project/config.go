@@ -0,0 +1,35 @@
+package project
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// ConfigPath returns the project configuration file, .kamui/config.json at the project root
+func ConfigPath(projectPath string) string {
+	return filepath.Join(projectPath, ".kamui", "config.json")
+}
+
+// LoadConfig reads the project configuration; a project without one gets an empty configuration
+func LoadConfig(projectPath string) (*types.ProjectConfig, error) {
+	config := &types.ProjectConfig{}
+
+	data, err := os.ReadFile(ConfigPath(projectPath))
+	// .kamui may also be a plain marker file rather than a directory
+	if os.IsNotExist(err) || errors.Is(err, syscall.ENOTDIR) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStoragePermission, "failed to read project config", err)
+	}
+
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStorageCorrupted, "failed to parse project config "+ConfigPath(projectPath), err)
+	}
+	return config, nil
+}
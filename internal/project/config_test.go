@@ -0,0 +1,45 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestLoadConfig(t *testing.T) {
+	root := t.TempDir()
+
+	config, err := LoadConfig(root)
+	require.NoError(t, err)
+	assert.Equal(t, &types.ProjectConfig{}, config)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".kamui"), 0o755))
+	require.NoError(t, os.WriteFile(ConfigPath(root), []byte(`{
+  "project": {"defaultSessionVariant": "main"},
+  "session": {"prompts": {"review": {"systemPrompt": "Review only.", "claudeMd": "# Reviewing"}}}
+}`), 0o644))
+	config, err = LoadConfig(root)
+	require.NoError(t, err)
+	assert.Equal(t, "main", config.Project.DefaultSessionVariant)
+	assert.Equal(t, types.VariantPrompt{SystemPrompt: "Review only.", ClaudeMD: "# Reviewing"}, config.Session.Prompts["review"])
+
+	require.NoError(t, os.WriteFile(ConfigPath(root), []byte("{"), 0o644))
+	_, err = LoadConfig(root)
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeStorageCorrupted, agxErr.Code)
+}
+
+func TestLoadConfig_MarkerFile(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".kamui"), nil, 0o644))
+
+	config, err := LoadConfig(root)
+	require.NoError(t, err)
+	assert.Equal(t, &types.ProjectConfig{}, config)
+}
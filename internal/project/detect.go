@@ -0,0 +1,132 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitomule/kamui/internal/paths"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Strategy selects how the project root is derived from a directory
+type Strategy string
+
+const (
+	// StrategyAuto uses the enclosing monorepo workspace root, and otherwise the git repository root
+	StrategyAuto Strategy = "auto"
+	// StrategyCwd uses the directory itself as the project root
+	StrategyCwd Strategy = "cwd"
+	// StrategyGit uses the nearest enclosing git repository root
+	StrategyGit Strategy = "git"
+	// StrategyMarker uses the nearest directory containing one of the marker files
+	StrategyMarker Strategy = "marker"
+)
+
+// DefaultStrategy is used when no strategy is configured
+const DefaultStrategy = StrategyAuto
+
+// DefaultMarkers are the marker files used by StrategyMarker when none are configured
+var DefaultMarkers = []string{".kamui", ".git", "go.mod", "package.json", "Cargo.toml", "pyproject.toml"}
+
+// Detector resolves project roots with a configured strategy
+type Detector struct {
+	Strategy Strategy
+	Markers  []string
+}
+
+// NewDetector builds a detector from the configured strategy name and markers
+func NewDetector(strategy string, markers []string) (Detector, error) {
+	parsed, err := ParseStrategy(strategy)
+	if err != nil {
+		return Detector{}, err
+	}
+	return Detector{Strategy: parsed, Markers: markers}, nil
+}
+
+// ParseStrategy validates a strategy name; empty selects the default
+func ParseStrategy(name string) (Strategy, error) {
+	switch Strategy(strings.ToLower(strings.TrimSpace(name))) {
+	case "":
+		return DefaultStrategy, nil
+	case StrategyAuto:
+		return StrategyAuto, nil
+	case StrategyCwd:
+		return StrategyCwd, nil
+	case StrategyGit:
+		return StrategyGit, nil
+	case StrategyMarker:
+		return StrategyMarker, nil
+	default:
+		return "", types.NewConfigError(
+			types.ErrCodeConfigInvalid,
+			fmt.Sprintf("unknown project detection strategy %q (expected auto, cwd, git or marker)", name),
+			nil,
+		)
+	}
+}
+
+// UsesWorkspaces reports whether the strategy makes a monorepo workspace root the project;
+// only auto does, so an explicit git, marker or cwd is applied as configured
+func (d Detector) UsesWorkspaces() bool {
+	return d.Strategy == "" || d.Strategy == StrategyAuto
+}
+
+// Root returns the project root for dir, leaving workspaces to the caller (see UsesWorkspaces)
+// Strategies that find nothing fall back to dir itself
+func (d Detector) Root(dir string) (string, error) {
+	dir = paths.Canonical(dir)
+
+	strategy := d.Strategy
+	if strategy == "" {
+		strategy = DefaultStrategy
+	}
+
+	var root string
+	switch strategy {
+	case StrategyCwd:
+		return dir, nil
+	case StrategyAuto, StrategyGit:
+		root = findUp(dir, []string{".git"})
+	case StrategyMarker:
+		markers := d.Markers
+		if len(markers) == 0 {
+			markers = DefaultMarkers
+		}
+		root = findUp(dir, markers)
+	default:
+		_, err := ParseStrategy(string(strategy))
+		return "", err
+	}
+
+	if root == "" {
+		return dir, nil
+	}
+	return root, nil
+}
+
+// kamuiMarker is the project marker that Kamui's global data directory, ~/.kamui, also matches
+const kamuiMarker = ".kamui"
+
+// findUp returns the nearest directory at or above dir containing any of the names
+// ~/.kamui holds Kamui's own data, not project config, so it never makes the home directory a root
+func findUp(dir string, names []string) string {
+	homeDir, _ := os.UserHomeDir()
+	for {
+		for _, name := range names {
+			if name == kamuiMarker && homeDir != "" && paths.Equal(dir, homeDir) {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				return dir
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,122 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestParseStrategy(t *testing.T) {
+	for input, expected := range map[string]Strategy{
+		"":       DefaultStrategy,
+		"auto":   StrategyAuto,
+		"cwd":    StrategyCwd,
+		"GIT":    StrategyGit,
+		"marker": StrategyMarker,
+	} {
+		strategy, err := ParseStrategy(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, strategy, input)
+	}
+
+	_, err := ParseStrategy("nearest")
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeConfigInvalid, agxErr.Code)
+}
+
+func TestDetectorRoot_Cwd(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root, ".git", "src/pkg")
+	dir := filepath.Join(root, "src", "pkg")
+
+	got, err := Detector{Strategy: StrategyCwd}.Root(dir)
+	require.NoError(t, err)
+	assert.Equal(t, dir, got)
+}
+
+func TestDetectorRoot_Git(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root, ".git", "src/pkg")
+
+	got, err := Detector{Strategy: StrategyGit}.Root(filepath.Join(root, "src", "pkg"))
+	require.NoError(t, err)
+	assert.Equal(t, root, got)
+}
+
+func TestDetector_UsesWorkspaces(t *testing.T) {
+	assert.True(t, Detector{}.UsesWorkspaces())
+	assert.True(t, Detector{Strategy: StrategyAuto}.UsesWorkspaces())
+	for _, strategy := range []Strategy{StrategyCwd, StrategyGit, StrategyMarker} {
+		assert.False(t, Detector{Strategy: strategy}.UsesWorkspaces(), strategy)
+	}
+
+	// outside a workspace auto finds the repository root
+	root := t.TempDir()
+	mkdirs(t, root, ".git", "src/pkg")
+	got, err := Detector{Strategy: StrategyAuto}.Root(filepath.Join(root, "src", "pkg"))
+	require.NoError(t, err)
+	assert.Equal(t, root, got)
+}
+
+func TestDetectorRoot_GitWorktreeFile(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root, "src")
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".git"), []byte("gitdir: /elsewhere\n"), 0o644))
+
+	got, err := Detector{Strategy: StrategyGit}.Root(filepath.Join(root, "src"))
+	require.NoError(t, err)
+	assert.Equal(t, root, got)
+}
+
+func TestDetectorRoot_Marker(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root, "app/lib")
+	writeFile(t, filepath.Join(root, "app", "mix.exs"), "")
+
+	got, err := Detector{Strategy: StrategyMarker, Markers: []string{"mix.exs"}}.Root(filepath.Join(root, "app", "lib"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "app"), got)
+}
+
+func TestDetectorRoot_IgnoresGlobalDataDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	mkdirs(t, home, ".kamui", "notes/drafts", "app/.kamui", "app/src")
+	detector := Detector{Strategy: StrategyMarker, Markers: []string{".kamui"}}
+
+	got, err := detector.Root(filepath.Join(home, "notes", "drafts"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, "notes", "drafts"), got, "~/.kamui doesn't make the home directory a project")
+
+	got, err = detector.Root(filepath.Join(home, "app", "src"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, "app"), got)
+}
+
+func TestDetectorRoot_FallsBackToDir(t *testing.T) {
+	dir := t.TempDir()
+
+	got, err := Detector{Strategy: StrategyMarker, Markers: []string{"does-not-exist.marker"}}.Root(dir)
+	require.NoError(t, err)
+	assert.Equal(t, dir, got)
+}
+
+func TestDetectorRoot_DefaultsToGitRoot(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root, ".git", "cmd/tool")
+
+	fromSubdir, err := Detector{}.Root(filepath.Join(root, "cmd", "tool"))
+	require.NoError(t, err)
+	fromRoot, err := Detector{}.Root(root)
+	require.NoError(t, err)
+
+	assert.Equal(t, root, fromSubdir)
+	assert.Equal(t, fromRoot, fromSubdir)
+}
@@ -0,0 +1,338 @@
+// Package project detects project roots and monorepo workspaces
+package project
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	toml "github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/bitomule/kamui/internal/paths"
+)
+
+// WorkspaceKind identifies the tool that defines a workspace
+type WorkspaceKind string
+
+const (
+	WorkspaceGo    WorkspaceKind = "go.work"
+	WorkspacePnpm  WorkspaceKind = "pnpm"
+	WorkspaceCargo WorkspaceKind = "cargo"
+)
+
+// manifests names the file marking a package of each kind of workspace
+var manifests = map[WorkspaceKind]string{
+	WorkspaceGo:    "go.mod",
+	WorkspacePnpm:  "package.json",
+	WorkspaceCargo: "Cargo.toml",
+}
+
+// Package is a member of a workspace
+type Package struct {
+	Name string
+	Path string
+}
+
+// Workspace is a monorepo root with its member packages
+type Workspace struct {
+	Root     string
+	Kind     WorkspaceKind
+	Packages []Package
+}
+
+// DetectWorkspace walks up from dir looking for a workspace definition
+// It returns nil when dir is not inside a workspace
+func DetectWorkspace(dir string) (*Workspace, error) {
+	dir = paths.Canonical(dir)
+
+	for {
+		if ws, err := loadWorkspace(dir); err != nil || ws != nil {
+			return ws, err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// PackageFor returns the workspace package containing dir, preferring the most specific match
+func (w *Workspace) PackageFor(dir string) *Package {
+	var best *Package
+	for i := range w.Packages {
+		pkg := &w.Packages[i]
+		if isWithin(dir, pkg.Path) && (best == nil || len(pkg.Path) > len(best.Path)) {
+			best = pkg
+		}
+	}
+	return best
+}
+
+// PackageNameFor returns the name of the package containing dir, or "" for the workspace root
+func (w *Workspace) PackageNameFor(dir string) string {
+	if pkg := w.PackageFor(dir); pkg != nil {
+		return pkg.Name
+	}
+	return ""
+}
+
+// loadWorkspace checks a single directory for a workspace definition
+func loadWorkspace(dir string) (*Workspace, error) {
+	if patterns, err := goWorkUses(filepath.Join(dir, "go.work")); err != nil || patterns != nil {
+		return newWorkspace(dir, WorkspaceGo, patterns, err)
+	}
+	if patterns, err := pnpmPackages(filepath.Join(dir, "pnpm-workspace.yaml")); err != nil || patterns != nil {
+		return newWorkspace(dir, WorkspacePnpm, patterns, err)
+	}
+	if patterns, err := cargoMembers(filepath.Join(dir, "Cargo.toml")); err != nil || patterns != nil {
+		return newWorkspace(dir, WorkspaceCargo, patterns, err)
+	}
+	return nil, nil
+}
+
+// newWorkspace expands member patterns into packages
+func newWorkspace(root string, kind WorkspaceKind, patterns []string, err error) (*Workspace, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	ws := &Workspace{Root: root, Kind: kind}
+	seen := make(map[string]bool)
+	var excluded []string
+
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "!") {
+			excluded = append(excluded, strings.TrimPrefix(pattern, "!"))
+			continue
+		}
+
+		matches, globErr := expandPattern(root, pattern, manifests[kind])
+		if globErr != nil {
+			continue // malformed patterns are ignored, like the tools themselves do
+		}
+		for _, match := range matches {
+			if info, statErr := os.Stat(match); statErr != nil || !info.IsDir() || seen[match] {
+				continue
+			}
+			seen[match] = true
+			ws.Packages = append(ws.Packages, Package{Name: packageName(root, match), Path: match})
+		}
+	}
+
+	if len(excluded) > 0 {
+		kept := ws.Packages[:0]
+		for _, pkg := range ws.Packages {
+			if !matchesAny(packageName(root, pkg.Path), excluded) {
+				kept = append(kept, pkg)
+			}
+		}
+		ws.Packages = kept
+	}
+
+	sort.Slice(ws.Packages, func(i, j int) bool {
+		return ws.Packages[i].Name < ws.Packages[j].Name
+	})
+	return ws, nil
+}
+
+// goWorkUses returns the directories listed in use directives of a go.work file
+func goWorkUses(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	uses := []string{}
+	inBlock := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			line = strings.TrimSpace(line[:idx])
+		}
+
+		switch {
+		case inBlock && line == ")":
+			inBlock = false
+		case inBlock && line != "":
+			uses = append(uses, strings.Trim(line, `"`))
+		case line == "use (":
+			inBlock = true
+		case strings.HasPrefix(line, "use "):
+			uses = append(uses, strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "use ")), `"`))
+		}
+	}
+
+	return uses, scanner.Err()
+}
+
+// pnpmPackages returns the package globs of a pnpm-workspace.yaml file
+func pnpmPackages(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var doc struct {
+		Packages []string `yaml:"packages"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Packages == nil {
+		doc.Packages = []string{}
+	}
+	return doc.Packages, nil
+}
+
+// cargoMembers returns the workspace members of a Cargo.toml, or nil if it isn't a workspace
+func cargoMembers(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var doc struct {
+		Workspace *struct {
+			Members []string `toml:"members"`
+			Exclude []string `toml:"exclude"`
+		} `toml:"workspace"`
+	}
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Workspace == nil {
+		return nil, nil
+	}
+
+	members := append([]string{}, doc.Workspace.Members...)
+	for _, exclude := range doc.Workspace.Exclude {
+		members = append(members, "!"+exclude)
+	}
+	return members, nil
+}
+
+// packageName returns the display name of a package: its path relative to the workspace root
+func packageName(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return filepath.Base(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// isWithin reports whether path is dir or inside it
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// expandPattern returns the paths under root matching a member pattern
+// filepath.Glob has no "**", so patterns using it are matched against the directories below the
+// pattern's literal prefix; like the tools, they only match directories holding the workspace's
+// manifest, and dependencies (node_modules) and hidden directories are skipped
+func expandPattern(root, pattern, manifest string) ([]string, error) {
+	segments := patternSegments(pattern)
+	if !containsGlobstar(segments) {
+		return filepath.Glob(filepath.Join(root, filepath.FromSlash(pattern)))
+	}
+	for _, segment := range segments {
+		if _, err := path.Match(segment, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	base := root
+	for _, segment := range segments {
+		if strings.ContainsAny(segment, `*?[\`) {
+			break
+		}
+		base = filepath.Join(base, segment)
+	}
+
+	var matches []string
+	err := filepath.WalkDir(base, func(dir string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.IsDir() {
+			return nil // unreadable directories are skipped
+		}
+		if dir != base && (entry.Name() == "node_modules" || strings.HasPrefix(entry.Name(), ".")) {
+			return filepath.SkipDir
+		}
+		rel, relErr := filepath.Rel(root, dir)
+		if relErr != nil || rel == "." {
+			return nil
+		}
+		if matchSegments(segments, strings.Split(filepath.ToSlash(rel), "/")) {
+			if _, statErr := os.Stat(filepath.Join(dir, manifest)); statErr == nil {
+				matches = append(matches, dir)
+			}
+		}
+		return nil
+	})
+	return matches, err
+}
+
+// patternSegments splits a slash-separated pattern, dropping "." segments such as a leading "./"
+func patternSegments(pattern string) []string {
+	return strings.Split(path.Clean(filepath.ToSlash(pattern)), "/")
+}
+
+func containsGlobstar(segments []string) bool {
+	for _, segment := range segments {
+		if segment == "**" {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments matches path segments against pattern segments, where "**" matches any number
+// of segments, none included
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(segments); i++ {
+				if matchSegments(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
+
+// matchesAny reports whether a package, named by its slash-separated path from the workspace
+// root, matches any of the glob patterns
+func matchesAny(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matchSegments(patternSegments(pattern), strings.Split(name, "/")) {
+			return true
+		}
+	}
+	return false
+}
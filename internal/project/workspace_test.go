@@ -0,0 +1,145 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mkdirs(t *testing.T, root string, dirs ...string) {
+	t.Helper()
+	for _, dir := range dirs {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0o755))
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func packageNames(ws *Workspace) []string {
+	names := make([]string, 0, len(ws.Packages))
+	for _, pkg := range ws.Packages {
+		names = append(names, pkg.Name)
+	}
+	return names
+}
+
+func TestDetectWorkspace_GoWork(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root, "api/internal", "tools/gen")
+	writeFile(t, filepath.Join(root, "go.work"), "go 1.22\n\nuse (\n\t./api // service\n\t./tools/gen\n)\n")
+
+	ws, err := DetectWorkspace(filepath.Join(root, "api", "internal"))
+	require.NoError(t, err)
+	require.NotNil(t, ws)
+
+	assert.Equal(t, root, ws.Root)
+	assert.Equal(t, WorkspaceGo, ws.Kind)
+	assert.Equal(t, []string{"api", "tools/gen"}, packageNames(ws))
+	assert.Equal(t, "api", ws.PackageNameFor(filepath.Join(root, "api", "internal")))
+	assert.Equal(t, "", ws.PackageNameFor(root))
+}
+
+func TestDetectWorkspace_GoWorkSingleUse(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root, "svc")
+	writeFile(t, filepath.Join(root, "go.work"), "go 1.22\nuse ./svc\n")
+
+	ws, err := DetectWorkspace(root)
+	require.NoError(t, err)
+	require.NotNil(t, ws)
+	assert.Equal(t, []string{"svc"}, packageNames(ws))
+}
+
+func TestDetectWorkspace_Pnpm(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root, "packages/ui", "packages/core", "packages/legacy", "apps/web/src")
+	writeFile(t, filepath.Join(root, "pnpm-workspace.yaml"), "packages:\n  - 'packages/*'\n  - 'apps/*'\n  - '!packages/legacy'\n")
+
+	ws, err := DetectWorkspace(filepath.Join(root, "apps", "web", "src"))
+	require.NoError(t, err)
+	require.NotNil(t, ws)
+
+	assert.Equal(t, WorkspacePnpm, ws.Kind)
+	assert.Equal(t, []string{"apps/web", "packages/core", "packages/ui"}, packageNames(ws))
+	assert.Equal(t, "apps/web", ws.PackageNameFor(filepath.Join(root, "apps", "web", "src")))
+}
+
+func TestDetectWorkspace_PnpmGlobstar(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root, "packages/ui/src", "packages/tools/lint", "packages/tools/fixtures/demo", "packages/ui/node_modules/react")
+	for _, pkg := range []string{"packages/ui", "packages/tools/lint", "packages/tools/fixtures/demo", "packages/ui/node_modules/react"} {
+		writeFile(t, filepath.Join(root, filepath.FromSlash(pkg), "package.json"), "{}")
+	}
+	writeFile(t, filepath.Join(root, "pnpm-workspace.yaml"), "packages:\n  - 'packages/**'\n  - '!**/fixtures/**'\n")
+
+	ws, err := DetectWorkspace(filepath.Join(root, "packages", "ui", "src"))
+	require.NoError(t, err)
+	require.NotNil(t, ws)
+
+	// directories without a package.json and dependencies aren't packages
+	assert.Equal(t, []string{"packages/tools/lint", "packages/ui"}, packageNames(ws))
+	assert.Equal(t, "packages/ui", ws.PackageNameFor(filepath.Join(root, "packages", "ui", "src")))
+}
+
+func TestMatchSegments(t *testing.T) {
+	for _, tc := range []struct {
+		pattern, name string
+		match         bool
+	}{
+		{"packages/*", "packages/ui", true},
+		{"packages/*", "packages/ui/src", false},
+		{"packages/**", "packages/ui/src", true},
+		{"packages/**", "packages", true},
+		{"**/fixtures/**", "packages/tools/fixtures/demo", true},
+		{"./apps/**/web", "apps/web", true},
+		{"./apps/**/web", "apps/site/web", true},
+		{"apps/**/web", "apps/site/api", false},
+	} {
+		assert.Equal(t, tc.match, matchSegments(patternSegments(tc.pattern), strings.Split(tc.name, "/")), "%s ~ %s", tc.pattern, tc.name)
+	}
+}
+
+func TestDetectWorkspace_Cargo(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root, "crates/core", "crates/cli", "crates/old")
+	writeFile(t, filepath.Join(root, "Cargo.toml"), "[workspace]\nmembers = [\"crates/*\"]\nexclude = [\"crates/old\"]\n")
+
+	ws, err := DetectWorkspace(filepath.Join(root, "crates", "cli"))
+	require.NoError(t, err)
+	require.NotNil(t, ws)
+
+	assert.Equal(t, WorkspaceCargo, ws.Kind)
+	assert.Equal(t, []string{"crates/cli", "crates/core"}, packageNames(ws))
+}
+
+func TestDetectWorkspace_CargoPackageIsNotWorkspace(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "Cargo.toml"), "[package]\nname = \"single\"\n")
+
+	ws, err := DetectWorkspace(root)
+	require.NoError(t, err)
+	assert.Nil(t, ws)
+}
+
+func TestPackageFor_PrefersMostSpecific(t *testing.T) {
+	ws := &Workspace{
+		Root: "/repo",
+		Packages: []Package{
+			{Name: "apps", Path: "/repo/apps"},
+			{Name: "apps/web", Path: "/repo/apps/web"},
+		},
+	}
+
+	pkg := ws.PackageFor("/repo/apps/web/src")
+	require.NotNil(t, pkg)
+	assert.Equal(t, "apps/web", pkg.Name)
+
+	assert.Nil(t, ws.PackageFor("/repo/application"))
+}
@@ -0,0 +1,414 @@
+// Package query parses and evaluates the filter expressions behind kam list --filter, such as
+// state=active && tag=backend && lastAccessed>7d
+package query
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Kind is the type of a field, which decides the operators and values it takes
+type Kind int
+
+const (
+	// String fields compare with = and != (where * and ? are wildcards) and ~ (contains)
+	String Kind = iota
+	// List fields match when any of their values does; != matches when none does
+	List
+	// Time fields compare with <, <=, > and >= against a date or an age such as 7d
+	Time
+	// Bool fields compare with = and != against true or false, or stand alone as a condition
+	Bool
+)
+
+// Value is a field's value in a record
+type Value struct {
+	Str  string
+	List []string
+	Time time.Time
+	Bool bool
+}
+
+// Record is what an expression is evaluated against
+type Record interface {
+	Field(name string) Value
+}
+
+// Expr is a parsed filter expression
+type Expr struct {
+	root node
+}
+
+// Parse parses an expression over the given fields; field names are case-insensitive
+// Conditions are joined with && and ||, negated with ! and grouped with parentheses; && binds
+// tighter than ||
+func Parse(expr string, fields map[string]Kind) (*Expr, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("invalid filter: empty expression")
+	}
+	p := &parser{tokens: tokens, fields: fields}
+	root, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("invalid filter: unexpected '%s'", p.tokens[p.pos].text)
+	}
+	return &Expr{root: root}, nil
+}
+
+// Match reports whether a record satisfies the expression; ages are measured from now
+func (e *Expr) Match(record Record, now time.Time) bool {
+	return e.root.match(record, now)
+}
+
+type node interface {
+	match(record Record, now time.Time) bool
+}
+
+type andNode struct{ left, right node }
+
+func (n andNode) match(r Record, now time.Time) bool {
+	return n.left.match(r, now) && n.right.match(r, now)
+}
+
+type orNode struct{ left, right node }
+
+func (n orNode) match(r Record, now time.Time) bool {
+	return n.left.match(r, now) || n.right.match(r, now)
+}
+
+type notNode struct{ operand node }
+
+func (n notNode) match(r Record, now time.Time) bool { return !n.operand.match(r, now) }
+
+// comparison is one field compared with a value
+type comparison struct {
+	field string
+	kind  Kind
+	op    string
+	value string
+
+	// for time fields: either a date, or an age measured back from now
+	date time.Time
+	age  time.Duration
+	// for bool fields
+	flag bool
+}
+
+func (c comparison) match(r Record, now time.Time) bool {
+	value := r.Field(c.field)
+	switch c.kind {
+	case String:
+		return c.matchString(value.Str)
+	case List:
+		if c.op == "!=" {
+			return !slices.ContainsFunc(value.List, func(item string) bool { return globMatch(c.value, item) })
+		}
+		return slices.ContainsFunc(value.List, c.matchString)
+	case Time:
+		if value.Time.IsZero() {
+			return false
+		}
+		if c.date.IsZero() {
+			// an age compares the other way round: older than 7d is before now-7d
+			return compareTimes(value.Time, flip(c.op), now.Add(-c.age))
+		}
+		return compareTimes(value.Time, c.op, c.date)
+	case Bool:
+		return (value.Bool == c.flag) == (c.op == "=")
+	}
+	return false
+}
+
+func (c comparison) matchString(text string) bool {
+	switch c.op {
+	case "~":
+		return strings.Contains(strings.ToLower(text), strings.ToLower(c.value))
+	case "!=":
+		return !globMatch(c.value, text)
+	default:
+		return globMatch(c.value, text)
+	}
+}
+
+// globMatch matches text against a pattern where * and ? are wildcards, ignoring case
+func globMatch(pattern, text string) bool {
+	matched, err := path.Match(strings.ToLower(pattern), strings.ToLower(text))
+	return err == nil && matched
+}
+
+// compareTimes evaluates "a op b"
+func compareTimes(a time.Time, op string, b time.Time) bool {
+	switch op {
+	case "<":
+		return a.Before(b)
+	case "<=":
+		return !a.After(b)
+	case ">":
+		return a.After(b)
+	case ">=":
+		return !a.Before(b)
+	}
+	return false
+}
+
+// flip returns the operator with its operands swapped
+func flip(op string) string {
+	switch op {
+	case "<":
+		return ">"
+	case "<=":
+		return ">="
+	case ">":
+		return "<"
+	case ">=":
+		return "<="
+	}
+	return op
+}
+
+// token is a piece of an expression: an operator, a parenthesis or a word
+type token struct {
+	text string
+	word bool
+}
+
+// operators are the symbols the tokenizer recognizes, longest first
+var operators = []string{"&&", "||", "==", "!=", ">=", "<=", "(", ")", "!", "=", ">", "<", "~"}
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+			continue
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(expr[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("invalid filter: unterminated quote at %d", i+1)
+			}
+			tokens = append(tokens, token{text: expr[i+1 : i+1+end], word: true})
+			i += end + 2
+			continue
+		}
+
+		if operator, ok := operatorAt(expr[i:]); ok {
+			i += len(operator)
+			if operator == "==" {
+				operator = "="
+			}
+			tokens = append(tokens, token{text: operator})
+			continue
+		}
+
+		start := i
+		for i < len(expr) && !strings.ContainsRune(" \t\n\"'()&|!=<>~", rune(expr[i])) {
+			i++
+		}
+		if i == start {
+			return nil, fmt.Errorf("invalid filter: unexpected '%c' at %d", c, i+1)
+		}
+		tokens = append(tokens, token{text: expr[start:i], word: true})
+	}
+	return tokens, nil
+}
+
+// operatorAt returns the operator text starts with
+func operatorAt(text string) (string, bool) {
+	for _, op := range operators {
+		if strings.HasPrefix(text, op) {
+			return op, true
+		}
+	}
+	return "", false
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	fields map[string]Kind
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// accept consumes the next token when it is the given operator
+func (p *parser) accept(operator string) bool {
+	if next, ok := p.peek(); ok && !next.word && next.text == operator {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) or() (node, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) and() (node, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) unary() (node, error) {
+	if p.accept("!") {
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand}, nil
+	}
+	if p.accept("(") {
+		inner, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("invalid filter: missing ')'")
+		}
+		return inner, nil
+	}
+	return p.comparison()
+}
+
+func (p *parser) comparison() (node, error) {
+	fieldToken, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("invalid filter: expected a condition at the end")
+	}
+	if !fieldToken.word {
+		return nil, fmt.Errorf("invalid filter: expected a field before '%s'", fieldToken.text)
+	}
+	p.pos++
+
+	field, kind, err := p.lookup(fieldToken.text)
+	if err != nil {
+		return nil, err
+	}
+
+	opToken, ok := p.peek()
+	if !ok || opToken.word || !isComparison(opToken.text) {
+		// a bool field on its own is true when set
+		if kind != Bool {
+			return nil, fmt.Errorf("invalid filter: expected an operator after '%s'", fieldToken.text)
+		}
+		return comparison{field: field, kind: Bool, op: "=", flag: true}, nil
+	}
+	p.pos++
+
+	valueToken, ok := p.peek()
+	if !ok || !valueToken.word {
+		return nil, fmt.Errorf("invalid filter: expected a value after '%s%s'", fieldToken.text, opToken.text)
+	}
+	p.pos++
+
+	return newComparison(field, kind, opToken.text, valueToken.text)
+}
+
+// lookup finds a field by its case-insensitive name
+func (p *parser) lookup(name string) (string, Kind, error) {
+	for field, kind := range p.fields {
+		if strings.EqualFold(field, name) {
+			return field, kind, nil
+		}
+	}
+	names := make([]string, 0, len(p.fields))
+	for field := range p.fields {
+		names = append(names, field)
+	}
+	slices.Sort(names)
+	return "", 0, fmt.Errorf("invalid filter: unknown field '%s' (fields: %s)", name, strings.Join(names, ", "))
+}
+
+func isComparison(operator string) bool {
+	switch operator {
+	case "=", "!=", "<", "<=", ">", ">=", "~":
+		return true
+	}
+	return false
+}
+
+// dateLayouts are the date formats time comparisons accept
+var dateLayouts = []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02"}
+
+func newComparison(field string, kind Kind, op, value string) (node, error) {
+	c := comparison{field: field, kind: kind, op: op, value: value}
+	invalid := func(hint string) error {
+		return fmt.Errorf("invalid filter: '%s%s%s': %s", field, op, value, hint)
+	}
+
+	switch kind {
+	case String, List:
+		if op != "=" && op != "!=" && op != "~" {
+			return nil, invalid("use =, != or ~")
+		}
+		if _, err := path.Match(value, ""); err != nil {
+			return nil, invalid("malformed pattern")
+		}
+	case Time:
+		if op == "=" || op == "!=" || op == "~" {
+			return nil, invalid("use <, <=, > or >= with a date or an age such as 7d")
+		}
+		for _, layout := range dateLayouts {
+			if date, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+				c.date = date
+				return c, nil
+			}
+		}
+		age, err := types.ParseDuration(value)
+		if err != nil {
+			return nil, invalid("expected a date (2006-01-02) or an age such as 7d")
+		}
+		c.age = time.Duration(age)
+	case Bool:
+		if op != "=" && op != "!=" {
+			return nil, invalid("use = or !=")
+		}
+		switch strings.ToLower(value) {
+		case "true", "yes":
+			c.flag = true
+		case "false", "no":
+			c.flag = false
+		default:
+			return nil, invalid("expected true or false")
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,92 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testFields = map[string]Kind{
+	"name":         String,
+	"state":        String,
+	"tag":          List,
+	"lastAccessed": Time,
+	"pinned":       Bool,
+}
+
+// record is a Record backed by a map
+type record map[string]Value
+
+func (r record) Field(name string) Value { return r[name] }
+
+func TestMatch(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.Local)
+	session := record{
+		"name":         {Str: "api-refactor"},
+		"state":        {Str: "active"},
+		"tag":          {List: []string{"backend", "infra"}},
+		"lastAccessed": {Time: now.Add(-10 * 24 * time.Hour)},
+		"pinned":       {Bool: true},
+	}
+
+	cases := map[string]bool{
+		"state=active":             true,
+		"state==ACTIVE":            true,
+		"state!=active":            false,
+		"name=api-*":               true,
+		"name='api refactor'":      false,
+		"name~REFACT":              true,
+		"tag=backend":              true,
+		"tag=frontend":             false,
+		"tag!=frontend":            true,
+		"tag!=infra":               false,
+		"lastAccessed>7d":          true,
+		"lastAccessed<7d":          false,
+		"lastAccessed>2w":          false,
+		"lastAccessed>2026-10-01":  true,
+		"lastAccessed<=2026-10-01": false,
+		"pinned":                   true,
+		"!pinned":                  false,
+		"pinned=false":             false,
+		"state=active && tag=backend && lastAccessed>7d": true,
+		"state=paused || tag=infra":                      true,
+		"state=paused || tag=infra && pinned=false":      false,
+		"(state=paused || tag=infra) && pinned":          true,
+		"!(state=active && pinned)":                      false,
+	}
+	for expr, want := range cases {
+		parsed, err := Parse(expr, testFields)
+		require.NoError(t, err, expr)
+		assert.Equal(t, want, parsed.Match(session, now), expr)
+	}
+}
+
+func TestMatch_MissingTime(t *testing.T) {
+	parsed, err := Parse("lastAccessed>1d", testFields)
+	require.NoError(t, err)
+	assert.False(t, parsed.Match(record{}, time.Now()))
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"size=3",
+		"state",
+		"state=",
+		"state>active",
+		"lastAccessed=7d",
+		"lastAccessed>soon",
+		"pinned=maybe",
+		"state=active &&",
+		"state=active & pinned",
+		"(state=active",
+		"state=active)",
+		"name='unterminated",
+		"name=[",
+	} {
+		_, err := Parse(expr, testFields)
+		assert.Error(t, err, expr)
+	}
+}
@@ -0,0 +1,270 @@
+package record
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// castVersion is the asciinema cast format written and played
+const castVersion = 2
+
+// maxEventSize bounds a single event read back when playing a cast
+const maxEventSize = 16 * 1024 * 1024
+
+// Header is the first line of an asciinema v2 cast
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// CastWriter writes terminal output as cast events, timed from when it was created
+type CastWriter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	start   time.Time
+	now     func() time.Time
+	pending []byte // the start of a UTF-8 sequence the last write cut in two
+}
+
+// NewCastWriter writes the cast header to w and returns a writer for the events that follow
+func NewCastWriter(w io.Writer, header Header, start time.Time) (*CastWriter, error) {
+	header.Version = castVersion
+	header.Timestamp = start.Unix()
+	data, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(append(data, '\n')); err != nil {
+		return nil, err
+	}
+	return &CastWriter{w: w, start: start, now: time.Now}, nil
+}
+
+// Write records p as output; a multi-byte character split across writes is kept whole
+func (c *CastWriter) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := append(c.pending, p...)
+	complete := completeUTF8(data)
+	c.pending = append([]byte(nil), data[complete:]...)
+	if complete == 0 {
+		return len(p), nil
+	}
+	if err := c.event("o", string(data[:complete])); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Resize records that the terminal changed size
+func (c *CastWriter) Resize(width, height int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.event("r", fmt.Sprintf("%dx%d", width, height))
+}
+
+// Flush records output still held back waiting for the rest of a character
+func (c *CastWriter) Flush() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.pending) == 0 {
+		return nil
+	}
+	err := c.event("o", string(c.pending))
+	c.pending = nil
+	return err
+}
+
+// event writes one event line: seconds since the start, its kind and its data
+func (c *CastWriter) event(kind, data string) error {
+	elapsed := math.Round(c.now().Sub(c.start).Seconds()*1e6) / 1e6
+	line, err := json.Marshal([]interface{}{elapsed, kind, data})
+	if err != nil {
+		return err
+	}
+	_, err = c.w.Write(append(line, '\n'))
+	return err
+}
+
+// completeUTF8 returns the length of data without a trailing incomplete UTF-8 sequence
+func completeUTF8(data []byte) int {
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				return i
+			}
+			break
+		}
+	}
+	return len(data)
+}
+
+// Event is one entry of a cast: seconds since the start, its kind ("o" for output, "r" for a
+// resize) and its data
+type Event struct {
+	Time float64
+	Kind string
+	Data string
+}
+
+// CastReader reads a cast's events
+type CastReader struct {
+	Header  Header
+	scanner *bufio.Scanner
+	line    int
+}
+
+// NewCastReader reads the header of an asciinema v2 cast
+func NewCastReader(r io.Reader) (*CastReader, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventSize)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("empty cast")
+	}
+	reader := &CastReader{scanner: scanner, line: 1}
+	if err := json.Unmarshal(scanner.Bytes(), &reader.Header); err != nil || reader.Header.Version != castVersion {
+		return nil, errors.New("not an asciinema v2 cast")
+	}
+	return reader, nil
+}
+
+// Next returns the next event, or io.EOF after the last one
+func (c *CastReader) Next() (Event, error) {
+	if !c.scanner.Scan() {
+		if err := c.scanner.Err(); err != nil {
+			return Event{}, err
+		}
+		return Event{}, io.EOF
+	}
+	c.line++
+
+	var fields []json.RawMessage
+	var event Event
+	if json.Unmarshal(c.scanner.Bytes(), &fields) != nil || len(fields) != 3 ||
+		json.Unmarshal(fields[0], &event.Time) != nil ||
+		json.Unmarshal(fields[1], &event.Kind) != nil ||
+		json.Unmarshal(fields[2], &event.Data) != nil {
+		return Event{}, fmt.Errorf("invalid event on line %d", c.line)
+	}
+	return event, nil
+}
+
+// Speed limits for playback controls
+const (
+	minSpeed = 1.0 / 16
+	maxSpeed = 16.0
+)
+
+// PlayOptions control playback
+type PlayOptions struct {
+	Speed   float64
+	MaxIdle time.Duration // longest pause kept; zero keeps them all
+
+	// Keys, when set, control playback: space pauses and resumes, + and - double and halve the
+	// speed, q or ctrl-c stops
+	Keys <-chan byte
+
+	after func(time.Duration) <-chan time.Time // time.After, replaced in tests
+}
+
+// Play writes a cast's output to w with its original timing divided by the speed
+func Play(r io.Reader, w io.Writer, opts PlayOptions) error {
+	if opts.Speed <= 0 {
+		return fmt.Errorf("invalid speed %v", opts.Speed)
+	}
+	if opts.after == nil {
+		opts.after = time.After
+	}
+	reader, err := NewCastReader(r)
+	if err != nil {
+		return err
+	}
+
+	player := &player{opts: opts, speed: opts.Speed}
+	last := 0.0
+	for {
+		event, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if event.Kind != "o" {
+			continue
+		}
+
+		if !player.wait(event.Time - last) {
+			return nil
+		}
+		last = event.Time
+		if _, err := io.WriteString(w, event.Data); err != nil {
+			return err
+		}
+	}
+}
+
+// player keeps the state playback controls change
+type player struct {
+	opts  PlayOptions
+	speed float64
+}
+
+// wait sleeps for seconds of the recording, handling keys meanwhile; it returns false once
+// playback is stopped
+func (p *player) wait(seconds float64) bool {
+	delay := time.Duration(seconds / p.speed * float64(time.Second))
+	if p.opts.MaxIdle > 0 && delay > p.opts.MaxIdle {
+		delay = p.opts.MaxIdle
+	}
+	timer := p.opts.after(max(delay, 0))
+	for {
+		select {
+		case <-timer:
+			return true
+		case key, ok := <-p.opts.Keys:
+			if !ok {
+				p.opts.Keys = nil // no more keys; a nil channel is never ready
+				continue
+			}
+			switch key {
+			case ' ':
+				return p.paused()
+			case '+', '>':
+				p.speed = min(p.speed*2, maxSpeed)
+			case '-', '<':
+				p.speed = max(p.speed/2, minSpeed)
+			case 'q', 0x03:
+				return false
+			}
+		}
+	}
+}
+
+// paused blocks until playback resumes, returning false if it is stopped instead
+func (p *player) paused() bool {
+	for key := range p.opts.Keys {
+		switch key {
+		case ' ':
+			return true
+		case 'q', 0x03:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,122 @@
+package record
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCastWriter(t *testing.T) {
+	var out bytes.Buffer
+	start := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	cast, err := NewCastWriter(&out, Header{Width: 120, Height: 40, Title: "Kamui session api"}, start)
+	require.NoError(t, err)
+	now := start
+	cast.now = func() time.Time { return now }
+
+	now = start.Add(1500 * time.Millisecond)
+	_, err = cast.Write([]byte("hello \xe2\x9c"))
+	require.NoError(t, err)
+	now = start.Add(2 * time.Second)
+	_, err = cast.Write([]byte("\x93 done"))
+	require.NoError(t, err)
+	require.NoError(t, cast.Resize(100, 30))
+	_, err = cast.Write([]byte("\xe2"))
+	require.NoError(t, err)
+	require.NoError(t, cast.Flush())
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 5)
+
+	var header Header
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &header))
+	assert.Equal(t, Header{Version: 2, Width: 120, Height: 40, Timestamp: start.Unix(), Title: "Kamui session api"}, header)
+	// the check mark split across writes is kept whole
+	assert.Equal(t, `[1.5,"o","hello "]`, lines[1])
+	assert.Equal(t, `[2,"o","✓ done"]`, lines[2])
+	assert.Equal(t, `[2,"r","100x30"]`, lines[3])
+	assert.Equal(t, `[2,"o","�"]`, lines[4])
+}
+
+// instantly records the pauses asked for and lets them pass at once
+func instantly(pauses *[]time.Duration) func(time.Duration) <-chan time.Time {
+	return func(d time.Duration) <-chan time.Time {
+		*pauses = append(*pauses, d)
+		ready := make(chan time.Time, 1)
+		ready <- time.Time{}
+		return ready
+	}
+}
+
+func TestPlay(t *testing.T) {
+	cast := `{"version":2,"width":80,"height":24,"timestamp":1}
+[0.5,"o","one "]
+[0.75,"r","100x30"]
+[1,"o","two "]
+[31,"o","three"]
+`
+	var out bytes.Buffer
+	var pauses []time.Duration
+	err := Play(strings.NewReader(cast), &out, PlayOptions{Speed: 2, MaxIdle: 2 * time.Second, after: instantly(&pauses)})
+	require.NoError(t, err)
+
+	assert.Equal(t, "one two three", out.String())
+	assert.Equal(t, []time.Duration{250 * time.Millisecond, 250 * time.Millisecond, 2 * time.Second}, pauses)
+}
+
+func TestPlayControls(t *testing.T) {
+	cast := `{"version":2,"width":80,"height":24,"timestamp":1}
+[1,"o","one "]
+[2,"o","two "]
+[3,"o","three"]
+`
+	keys := make(chan byte, 8)
+	never := func(time.Duration) <-chan time.Time { return nil }
+
+	// paused before the first output, resumed, then stopped before the second
+	keys <- ' '
+	keys <- '+'
+	keys <- ' '
+	keys <- 'q'
+	var out bytes.Buffer
+	require.NoError(t, Play(strings.NewReader(cast), &out, PlayOptions{Speed: 1, Keys: keys, after: never}))
+	assert.Equal(t, "one ", out.String())
+
+	// speed changes apply to the pauses that follow
+	var pauses []time.Duration
+	player := &player{opts: PlayOptions{Speed: 1, Keys: keys, after: instantly(&pauses)}, speed: 1}
+	keys <- '+'
+	require.True(t, player.wait(1))
+	for i := 0; i < 10; i++ {
+		keys <- '-'
+		require.True(t, player.wait(0))
+	}
+	assert.Equal(t, minSpeed, player.speed)
+	close(keys)
+	require.True(t, player.wait(0))
+}
+
+func TestPlayInvalid(t *testing.T) {
+	opts := PlayOptions{Speed: 1}
+	assert.Error(t, Play(strings.NewReader(""), &bytes.Buffer{}, opts))
+	assert.Error(t, Play(strings.NewReader(`{"version":1}`+"\n"), &bytes.Buffer{}, opts))
+	assert.Error(t, Play(strings.NewReader(`{"version":2}`+"\n[1,\"o\"]\n"), &bytes.Buffer{}, opts))
+	assert.Error(t, Play(strings.NewReader(`{"version":2}`+"\n"), &bytes.Buffer{}, PlayOptions{}))
+}
+
+func TestExportText(t *testing.T) {
+	cast := `{"version":2,"width":80,"height":24,"timestamp":1}
+[0.1,"o","\u001b]0;Claude - api\u0007\u001b[1;32m> \u001b[0mfix the tests\r\n"]
+[0.2,"o","Working...\rDone.     \r\n"]
+[0.3,"r","100x30"]
+[0.4,"o","typo\b\b\bests\tok\r\n"]
+`
+	var out bytes.Buffer
+	require.NoError(t, ExportText(strings.NewReader(cast), &out))
+	assert.Equal(t, "> fix the tests\nDone.\ntests\tok\n", out.String())
+}
@@ -0,0 +1,63 @@
+package record
+
+import (
+	"io"
+	"regexp"
+	"strings"
+)
+
+// escapeSequence matches the terminal control sequences plain text leaves out: CSI (colors, cursor
+// movement), OSC (titles, hyperlinks) and the short ESC forms
+var escapeSequence = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)|\x1b[()][0-9A-Za-z]|\x1b[@-Z\\-_]`)
+
+// ExportText writes the output of a cast as plain text, for reading or grepping a recording
+// Screen redraws are not replayed, so text a full-screen program overwrote still shows
+func ExportText(r io.Reader, w io.Writer) error {
+	reader, err := NewCastReader(r)
+	if err != nil {
+		return err
+	}
+
+	var output strings.Builder
+	for {
+		event, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if event.Kind == "o" {
+			output.WriteString(event.Data)
+		}
+	}
+	_, err = io.WriteString(w, PlainText(output.String()))
+	return err
+}
+
+// PlainText strips control sequences from terminal output; a carriage return keeps what follows
+// it on the line and a backspace erases the character before it
+func PlainText(output string) string {
+	output = escapeSequence.ReplaceAllString(output, "")
+	output = strings.ReplaceAll(output, "\r\n", "\n")
+
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		if at := strings.LastIndex(strings.TrimRight(line, "\r"), "\r"); at >= 0 {
+			line = line[at+1:]
+		}
+		var text []rune
+		for _, r := range line {
+			switch {
+			case r == '\b':
+				if len(text) > 0 {
+					text = text[:len(text)-1]
+				}
+			case r == '\t' || r >= ' ' && r != 0x7f:
+				text = append(text, r)
+			}
+		}
+		lines[i] = strings.TrimRight(string(text), " ")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,42 @@
+//go:build darwin
+
+package record
+
+import (
+	"bytes"
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPTY opens a pseudo-terminal, returning its controlling and terminal sides
+func openPTY() (master, slave *os.File, err error) {
+	master, err = os.OpenFile("/dev/ptmx", os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var name [128]byte
+	err = control(master, func(fd int) error {
+		if err := unix.IoctlSetInt(fd, unix.TIOCPTYGRANT, 0); err != nil {
+			return err
+		}
+		if err := unix.IoctlSetInt(fd, unix.TIOCPTYUNLK, 0); err != nil {
+			return err
+		}
+		if _, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), uintptr(unix.TIOCPTYGNAME), uintptr(unsafe.Pointer(&name[0]))); errno != 0 {
+			return errno
+		}
+		return nil
+	})
+	if err == nil {
+		path := string(name[:bytes.IndexByte(name[:], 0)])
+		slave, err = os.OpenFile(path, os.O_RDWR|unix.O_NOCTTY, 0)
+	}
+	if err != nil {
+		master.Close()
+		return nil, nil, err
+	}
+	return master, slave, nil
+}
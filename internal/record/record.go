@@ -0,0 +1,238 @@
+// Package record captures what interactive Claude runs show on the terminal as asciinema v2 casts,
+// so a session can be replayed later with `kam record --play` or asciinema itself
+package record
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/bitomule/kamui/internal/trace"
+)
+
+// Ext is the extension of cast files
+const Ext = ".cast"
+
+// fileTimeFormat names casts after the time their run started
+const fileTimeFormat = "20060102-150405"
+
+var (
+	mu   sync.Mutex
+	dir  string
+	keep int
+)
+
+// Cast is a recorded run
+type Cast struct {
+	Path    string
+	Started time.Time
+	Size    int64
+}
+
+// Recording is an interactive run being recorded
+type Recording struct {
+	Path        string
+	sessionName string
+	file        *os.File
+	cast        *CastWriter
+	tty         *tty
+}
+
+// DefaultDir returns the directory casts are kept in, ~/.kamui/recordings
+func DefaultDir() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+	return filepath.Join(homeDir, ".kamui", "recordings")
+}
+
+// Enable records interactive runs under recordingsDir, in a directory per session keeping its keep
+// most recent casts (all of them when keep isn't positive); an empty recordingsDir turns recording off
+func Enable(recordingsDir string, keepCasts int) {
+	mu.Lock()
+	defer mu.Unlock()
+	dir = recordingsDir
+	keep = keepCasts
+}
+
+// Enabled reports whether interactive runs are recorded
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return dir != ""
+}
+
+// Attach connects cmd, which would otherwise run on this terminal, to a pseudo-terminal whose output
+// is shown and recorded for the session
+// It returns nil, after warning why, when recording is off or isn't possible; the run goes ahead
+// unrecorded
+func Attach(cmd *exec.Cmd, sessionName string) *Recording {
+	mu.Lock()
+	recordingsDir := dir
+	mu.Unlock()
+	if recordingsDir == "" {
+		return nil
+	}
+
+	recording, err := attach(cmd, recordingsDir, sessionName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: not recording this run: %v\n", err)
+		return nil
+	}
+	return recording
+}
+
+func attach(cmd *exec.Cmd, recordingsDir, sessionName string) (*Recording, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return nil, errors.New("recording needs a terminal")
+	}
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sessionDir := filepath.Join(recordingsDir, sessionName)
+	if err := os.MkdirAll(sessionDir, 0o700); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(sessionDir, now.Format(fileTimeFormat)+Ext)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	cast, err := NewCastWriter(file, Header{
+		Width:  width,
+		Height: height,
+		Title:  fmt.Sprintf("Kamui session %s", sessionName),
+		Env:    map[string]string{"SHELL": os.Getenv("SHELL"), "TERM": os.Getenv("TERM")},
+	}, now)
+	if err == nil {
+		var t *tty
+		if t, err = attachTTY(cmd, cast, width, height); err == nil {
+			return &Recording{Path: path, sessionName: sessionName, file: file, cast: cast, tty: t}, nil
+		}
+	}
+	file.Close()
+	os.Remove(path)
+	return nil, err
+}
+
+// Begin starts relaying the terminal once the command has started; it does nothing on nil
+func (r *Recording) Begin() {
+	if r == nil {
+		return
+	}
+	if err := r.tty.begin(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// Close stops relaying once the command has exited, restores the terminal and finishes the cast,
+// dropping the session's oldest casts beyond the configured number; it does nothing on nil
+func (r *Recording) Close() error {
+	if r == nil {
+		return nil
+	}
+	r.tty.close()
+	err := r.cast.Flush()
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write recording %s: %w", r.Path, err)
+	}
+
+	mu.Lock()
+	recordingsDir, keepCasts := dir, keep
+	mu.Unlock()
+	return Prune(recordingsDir, r.sessionName, keepCasts)
+}
+
+// Run runs cmd on this terminal like trace.Run, recording it for the session when recording is on
+func Run(cmd *exec.Cmd, sessionName string) error {
+	recording := Attach(cmd, sessionName)
+	if err := trace.Start(cmd); err != nil {
+		_ = recording.Close()
+		return err
+	}
+	recording.Begin()
+	err := trace.Wait(cmd)
+	if closeErr := recording.Close(); closeErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", closeErr)
+	} else if recording != nil {
+		fmt.Printf("Kamui: Recorded this run to %s\n", recording.Path)
+	}
+	return err
+}
+
+// List returns a session's casts in recordingsDir, oldest first
+func List(recordingsDir, sessionName string) ([]Cast, error) {
+	entries, err := os.ReadDir(filepath.Join(recordingsDir, sessionName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var casts []Cast
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, Ext) {
+			continue
+		}
+		started, err := time.ParseInLocation(fileTimeFormat, strings.TrimSuffix(name, Ext), time.Local)
+		if err != nil {
+			continue // not one of ours
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		casts = append(casts, Cast{
+			Path:    filepath.Join(recordingsDir, sessionName, name),
+			Started: started,
+			Size:    info.Size(),
+		})
+	}
+	sort.Slice(casts, func(i, j int) bool { return casts[i].Started.Before(casts[j].Started) })
+	return casts, nil
+}
+
+// Rename files a session's casts in recordingsDir under its new name
+func Rename(recordingsDir, oldName, newName string) error {
+	err := os.Rename(filepath.Join(recordingsDir, oldName), filepath.Join(recordingsDir, newName))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Prune drops all but a session's keep most recent casts; keep below 1 keeps them all
+func Prune(recordingsDir, sessionName string, keep int) error {
+	if keep < 1 {
+		return nil
+	}
+	casts, err := List(recordingsDir, sessionName)
+	if err != nil {
+		return err
+	}
+	for len(casts) > keep {
+		if err := os.Remove(casts[0].Path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		casts = casts[1:]
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package record
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAndPrune(t *testing.T) {
+	dir := t.TempDir()
+	sessionDir := filepath.Join(dir, "api")
+	require.NoError(t, os.MkdirAll(sessionDir, 0o700))
+	for _, name := range []string{"20261017-100000.cast", "20261015-090000.cast", "20261016-120000.cast", "notes.txt", "broken.cast"} {
+		require.NoError(t, os.WriteFile(filepath.Join(sessionDir, name), []byte("{}\n"), 0o600))
+	}
+
+	casts, err := List(dir, "api")
+	require.NoError(t, err)
+	require.Len(t, casts, 3)
+	assert.Equal(t, filepath.Join(sessionDir, "20261015-090000.cast"), casts[0].Path)
+	assert.Equal(t, filepath.Join(sessionDir, "20261017-100000.cast"), casts[2].Path)
+	assert.Equal(t, int64(3), casts[0].Size)
+
+	require.NoError(t, Prune(dir, "api", 0))
+	casts, err = List(dir, "api")
+	require.NoError(t, err)
+	assert.Len(t, casts, 3)
+
+	require.NoError(t, Prune(dir, "api", 2))
+	casts, err = List(dir, "api")
+	require.NoError(t, err)
+	require.Len(t, casts, 2)
+	assert.Equal(t, filepath.Join(sessionDir, "20261016-120000.cast"), casts[0].Path)
+
+	casts, err = List(dir, "missing")
+	require.NoError(t, err)
+	assert.Empty(t, casts)
+
+	require.NoError(t, Rename(dir, "api", "api-v2"))
+	casts, err = List(dir, "api-v2")
+	require.NoError(t, err)
+	assert.Len(t, casts, 2)
+	require.NoError(t, Rename(dir, "missing", "other"))
+}
+
+func TestAttachDisabled(t *testing.T) {
+	Enable("", 0)
+	assert.False(t, Enabled())
+	assert.Nil(t, Attach(nil, "api"))
+
+	var recording *Recording
+	recording.Begin()
+	assert.NoError(t, recording.Close())
+}
@@ -0,0 +1,20 @@
+//go:build !linux && !darwin
+
+package record
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// tty stands in for the pseudo-terminal relay, which needs Linux or macOS
+type tty struct{}
+
+func attachTTY(*exec.Cmd, *CastWriter, int, int) (*tty, error) {
+	return nil, fmt.Errorf("recording isn't supported on %s", runtime.GOOS)
+}
+
+func (t *tty) begin() error { return nil }
+
+func (t *tty) close() {}
@@ -0,0 +1,175 @@
+//go:build linux || darwin
+
+package record
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+	"golang.org/x/term"
+)
+
+// drainTimeout is how long output is still relayed after the command exits, for what a process it
+// left behind keeps writing
+const drainTimeout = 500 * time.Millisecond
+
+// tty relays this terminal to and from the pseudo-terminal a recorded command runs on
+type tty struct {
+	master  *os.File
+	slave   *os.File
+	cast    *CastWriter
+	state   *term.State
+	stdin   *os.File // duplicate of stdin, non-blocking when its reads can be interrupted
+	resized chan os.Signal
+
+	outputDone chan struct{}
+	inputDone  chan struct{} // nil when relaying input can't be stopped
+}
+
+// attachTTY runs cmd on a new pseudo-terminal of the given size, in a session of its own
+func attachTTY(cmd *exec.Cmd, cast *CastWriter, width, height int) (*tty, error) {
+	master, slave, err := openPTY()
+	if err != nil {
+		return nil, err
+	}
+	t := &tty{master: master, slave: slave, cast: cast}
+	if err := t.setSize(width, height); err != nil {
+		master.Close()
+		slave.Close()
+		return nil, err
+	}
+
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = slave, slave, slave
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true}
+	return t, nil
+}
+
+// begin puts this terminal in raw mode, so keys reach the command unchanged, and starts relaying
+func (t *tty) begin() error {
+	t.slave.Close() // the command holds it now; its output ends when the command closes it
+	t.slave = nil
+
+	t.outputDone = make(chan struct{})
+	go t.relayOutput()
+
+	t.resized = make(chan os.Signal, 1)
+	signal.Notify(t.resized, syscall.SIGWINCH)
+	go func() {
+		for range t.resized {
+			if width, height, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+				_ = t.setSize(width, height)
+				_ = t.cast.Resize(width, height)
+			}
+		}
+	}()
+
+	t.relayInput()
+	state, err := term.MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		return err
+	}
+	t.state = state
+	return nil
+}
+
+// relayInput copies stdin to the command through a duplicate of it; made non-blocking, its reads
+// can be interrupted once the command exits, instead of swallowing the next key typed
+func (t *tty) relayInput() {
+	stdin, stoppable := os.Stdin, false
+	if fd, err := unix.Dup(int(os.Stdin.Fd())); err == nil {
+		stoppable = unix.SetNonblock(fd, true) == nil
+		stdin = os.NewFile(uintptr(fd), "stdin")
+		t.stdin = stdin
+	}
+	if stoppable && stdin.SetReadDeadline(time.Time{}) != nil {
+		// this terminal can't be polled; block instead and leave the last read behind
+		stoppable = false
+		_ = unix.SetNonblock(int(os.Stdin.Fd()), false)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = io.Copy(t.master, stdin)
+	}()
+	if stoppable {
+		t.inputDone = done
+	}
+}
+
+// relayOutput copies what the command writes to stdout and the cast; once the cast fails the
+// command still shows
+func (t *tty) relayOutput() {
+	defer close(t.outputDone)
+	buf := make([]byte, 32*1024)
+	recording := true
+	for {
+		n, err := t.master.Read(buf)
+		if n > 0 {
+			_, _ = os.Stdout.Write(buf[:n])
+			if recording {
+				_, castErr := t.cast.Write(buf[:n])
+				recording = castErr == nil
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// close stops relaying and restores this terminal
+func (t *tty) close() {
+	if t.slave != nil {
+		t.slave.Close() // the command never started
+	}
+	if t.resized != nil {
+		signal.Stop(t.resized)
+		close(t.resized)
+	}
+	if t.outputDone != nil {
+		select {
+		case <-t.outputDone:
+		case <-time.After(drainTimeout):
+		}
+	}
+	t.master.Close()
+	if t.outputDone != nil {
+		<-t.outputDone
+	}
+
+	if t.stdin != nil && t.inputDone != nil {
+		_ = t.stdin.SetReadDeadline(time.Now())
+		<-t.inputDone
+		t.stdin.Close()
+		_ = unix.SetNonblock(int(os.Stdin.Fd()), false) // the duplicate shared stdin's mode
+	}
+	if t.state != nil {
+		_ = term.Restore(int(os.Stdin.Fd()), t.state)
+	}
+}
+
+// setSize sets the size of the pseudo-terminal
+func (t *tty) setSize(width, height int) error {
+	return control(t.master, func(fd int) error {
+		return unix.IoctlSetWinsize(fd, unix.TIOCSWINSZ, &unix.Winsize{Col: uint16(width), Row: uint16(height)})
+	})
+}
+
+// control runs fn on f's descriptor without switching f to blocking mode as Fd does
+func control(f *os.File, fn func(fd int) error) error {
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var fnErr error
+	if err := conn.Control(func(fd uintptr) { fnErr = fn(int(fd)) }); err != nil {
+		return err
+	}
+	return fnErr
+}
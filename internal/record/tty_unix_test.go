@@ -0,0 +1,26 @@
+//go:build linux || darwin
+
+package record
+
+import (
+	"bufio"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenPTY(t *testing.T) {
+	master, slave, err := openPTY()
+	if err != nil {
+		t.Skipf("no pseudo-terminals here: %v", err)
+	}
+	defer master.Close()
+	defer slave.Close()
+
+	_, err = slave.Write([]byte("hello\n"))
+	require.NoError(t, err)
+	line, err := bufio.NewReader(master).ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "hello\r\n", line) // the terminal side translates newlines
+}
@@ -0,0 +1,79 @@
+package registry
+
+import (
+	"sort"
+	"strings"
+)
+
+// Scores of the ways a query can match a project, best first
+const (
+	scoreExactName       = 100
+	scoreNamePrefix      = 80
+	scoreNameContains    = 60
+	scorePathContains    = 40
+	scoreNameSubsequence = 20
+	scoreAny             = 1 // the empty query
+)
+
+// Match is a project matching a query, with how well it matched
+type Match struct {
+	Project Project
+	Score   int
+}
+
+// MatchProjects returns the projects matching query, best first; projects matching equally well
+// keep their order, so a list sorted by activity stays that way among ties
+// Queries are matched case-insensitively as a substring of the name or path, or against the name
+// with characters left out ("kmi" matches "kamui"); an empty query matches every project
+func MatchProjects(projects []Project, query string) []Match {
+	query = strings.ToLower(strings.TrimSpace(query))
+
+	var matches []Match
+	for _, project := range projects {
+		if score := matchScore(project, query); score > 0 {
+			matches = append(matches, Match{Project: project, Score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	return matches
+}
+
+// Unambiguous reports whether the first match is better than every other one
+func Unambiguous(matches []Match) bool {
+	return len(matches) == 1 || (len(matches) > 1 && matches[0].Score > matches[1].Score)
+}
+
+func matchScore(project Project, query string) int {
+	if query == "" {
+		return scoreAny
+	}
+	name := strings.ToLower(project.Name)
+	path := strings.ToLower(project.Path)
+	switch {
+	case name == query:
+		return scoreExactName
+	case strings.HasPrefix(name, query):
+		return scoreNamePrefix
+	case strings.Contains(name, query):
+		return scoreNameContains
+	case strings.Contains(path, query):
+		return scorePathContains
+	case isSubsequence(name, query):
+		return scoreNameSubsequence
+	}
+	return 0
+}
+
+// isSubsequence reports whether every character of query appears in text, in order
+func isSubsequence(text, query string) bool {
+	rest := []rune(query)
+	for _, r := range text {
+		if len(rest) == 0 {
+			break
+		}
+		if r == rest[0] {
+			rest = rest[1:]
+		}
+	}
+	return len(rest) == 0
+}
@@ -0,0 +1,40 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func matchedNames(matches []Match) []string {
+	names := make([]string, 0, len(matches))
+	for _, match := range matches {
+		names = append(names, match.Project.Name)
+	}
+	return names
+}
+
+func TestMatchProjects(t *testing.T) {
+	projects := []Project{
+		{Name: "kamui-web", Path: "/work/kamui-web"},
+		{Name: "api", Path: "/work/clients/acme/api"},
+		{Name: "kamui", Path: "/work/kamui"},
+		{Name: "notes", Path: "/home/me/notes"},
+	}
+
+	assert.Equal(t, []string{"kamui", "kamui-web"}, matchedNames(MatchProjects(projects, "Kamui")), "exact name first")
+	assert.Equal(t, []string{"kamui-web"}, matchedNames(MatchProjects(projects, "web")))
+	assert.Equal(t, []string{"api"}, matchedNames(MatchProjects(projects, "acme")), "path")
+	assert.Equal(t, []string{"kamui-web", "kamui"}, matchedNames(MatchProjects(projects, "kmi")), "ties keep their order")
+	assert.Empty(t, MatchProjects(projects, "zzz"))
+	assert.Len(t, MatchProjects(projects, ""), 4)
+}
+
+func TestUnambiguous(t *testing.T) {
+	projects := []Project{{Name: "kamui-web"}, {Name: "kamui"}, {Name: "kamui-cli"}}
+
+	assert.True(t, Unambiguous(MatchProjects(projects, "kamui")))
+	assert.False(t, Unambiguous(MatchProjects(projects, "kamui-")))
+	assert.True(t, Unambiguous(MatchProjects(projects, "cli")))
+	assert.False(t, Unambiguous(nil))
+}
@@ -0,0 +1,215 @@
+// Package registry keeps track of every project Kamui has seen, for commands that work across
+// projects
+package registry
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/bitomule/kamui/internal/paths"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Project is a project Kamui has had sessions in
+type Project struct {
+	Path     string `json:"path"`
+	Name     string `json:"name"`
+	Sessions int    `json:"sessions"`
+	// Archived counts the sessions among Sessions that are archived
+	Archived     int       `json:"archived"`
+	LastActivity time.Time `json:"lastActivity"`
+	FirstSeen    time.Time `json:"firstSeen"`
+}
+
+// Summarize describes the project at projectPath from its sessions
+// LastActivity is the latest time one of them was accessed and FirstSeen the earliest one was
+// created, both zero without sessions
+func Summarize(projectPath string, sessions []*types.Session) Project {
+	project := Project{Path: projectPath, Name: filepath.Base(projectPath), Sessions: len(sessions)}
+	for _, session := range sessions {
+		if session.Lifecycle.State == types.SessionStateArchived {
+			project.Archived++
+		}
+		if session.LastAccessed.After(project.LastActivity) {
+			project.LastActivity = session.LastAccessed
+		}
+		if !session.Created.IsZero() && (project.FirstSeen.IsZero() || session.Created.Before(project.FirstSeen)) {
+			project.FirstSeen = session.Created
+		}
+	}
+	return project
+}
+
+// Store persists the registry in a single JSON file keyed by project path
+type Store struct {
+	path string
+}
+
+// NewStore creates a store backed by ~/.kamui/projects.json
+func NewStore() *Store {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+	return NewStoreWithPath(filepath.Join(homeDir, ".kamui", "projects.json"))
+}
+
+// NewStoreWithPath creates a store backed by the given file
+func NewStoreWithPath(path string) *Store {
+	return &Store{path: path}
+}
+
+// List returns the registered projects, most recently active first
+func (s *Store) List() ([]Project, error) {
+	projects, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]Project, 0, len(projects))
+	for _, project := range projects {
+		list = append(list, project)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].LastActivity.Equal(list[j].LastActivity) {
+			return list[i].LastActivity.After(list[j].LastActivity)
+		}
+		return list[i].Path < list[j].Path
+	})
+	return list, nil
+}
+
+// Record adds a project or updates its entry, keeping when it was first seen
+// A zero LastActivity keeps the one already recorded; projects new to the registry without a
+// FirstSeen are stamped with now
+func (s *Store) Record(project Project, now time.Time) error {
+	projects, err := s.load()
+	if err != nil {
+		return err
+	}
+	s.merge(projects, project, now)
+	return s.save(projects)
+}
+
+// Rebuild recounts every project from all the sessions in storage
+// Registered projects without sessions left are kept with zero counts
+func (s *Store) Rebuild(sessions []*types.Session, now time.Time) error {
+	projects, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	byProject := map[string][]*types.Session{}
+	for _, session := range sessions {
+		key := paths.Canonical(session.Project.Path)
+		byProject[key] = append(byProject[key], session)
+	}
+	for path, project := range projects {
+		if _, ok := byProject[path]; !ok {
+			project.Sessions, project.Archived = 0, 0
+			projects[path] = project
+		}
+	}
+	for path, projectSessions := range byProject {
+		s.merge(projects, Summarize(path, projectSessions), now)
+	}
+	return s.save(projects)
+}
+
+// Remove drops a project from the registry; its sessions are left alone
+func (s *Store) Remove(projectPath string) error {
+	projects, err := s.load()
+	if err != nil {
+		return err
+	}
+	delete(projects, paths.Canonical(projectPath))
+	return s.save(projects)
+}
+
+// merge records project in projects under its canonical path
+func (s *Store) merge(projects map[string]Project, project Project, now time.Time) {
+	project.Path = paths.Canonical(project.Path)
+	existing, ok := projects[project.Path]
+	if ok {
+		if !existing.FirstSeen.IsZero() {
+			project.FirstSeen = existing.FirstSeen
+		}
+		if project.LastActivity.IsZero() {
+			project.LastActivity = existing.LastActivity
+		}
+	}
+	if project.FirstSeen.IsZero() {
+		project.FirstSeen = now
+	}
+	if project.Name == "" {
+		project.Name = filepath.Base(project.Path)
+	}
+	projects[project.Path] = project
+}
+
+func (s *Store) load() (map[string]Project, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return map[string]Project{}, nil
+	}
+	if err != nil {
+		return nil, types.NewStorageError(
+			types.ErrCodeStoragePermission,
+			"failed to read projects registry",
+			err,
+		)
+	}
+
+	projects := map[string]Project{}
+	if err := json.Unmarshal(data, &projects); err != nil {
+		return nil, types.NewStorageError(
+			types.ErrCodeStorageCorrupted,
+			"failed to parse projects registry",
+			err,
+		)
+	}
+	return projects, nil
+}
+
+// save writes the registry atomically
+func (s *Store) save(projects map[string]Project) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return types.NewStorageError(
+			types.ErrCodeStoragePermission,
+			"failed to create projects registry directory",
+			err,
+		)
+	}
+
+	data, err := json.MarshalIndent(projects, "", "  ")
+	if err != nil {
+		return types.NewStorageError(
+			types.ErrCodeStorageCorrupted,
+			"failed to marshal projects registry",
+			err,
+		)
+	}
+
+	tempFile := s.path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0o600); err != nil {
+		return types.NewStorageError(
+			types.ErrCodeStoragePermission,
+			"failed to write projects registry",
+			err,
+		)
+	}
+
+	if err := os.Rename(tempFile, s.path); err != nil {
+		os.Remove(tempFile) // cleanup temp file
+		return types.NewStorageError(
+			types.ErrCodeStoragePermission,
+			"failed to save projects registry",
+			err,
+		)
+	}
+
+	return nil
+}
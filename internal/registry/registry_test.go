@@ -0,0 +1,108 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func testSession(projectPath string, state types.SessionState, lastAccessed time.Time) *types.Session {
+	return &types.Session{
+		Created:      lastAccessed.Add(-time.Hour),
+		LastAccessed: lastAccessed,
+		Project:      types.ProjectInfo{Path: projectPath},
+		Lifecycle:    types.LifecycleInfo{State: state},
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	project := Summarize("/work/api", []*types.Session{
+		testSession("/work/api", types.SessionStateActive, start),
+		testSession("/work/api", types.SessionStateArchived, start.Add(time.Hour)),
+	})
+
+	assert.Equal(t, "api", project.Name)
+	assert.Equal(t, 2, project.Sessions)
+	assert.Equal(t, 1, project.Archived)
+	assert.Equal(t, start.Add(time.Hour), project.LastActivity)
+	assert.Equal(t, start.Add(-time.Hour), project.FirstSeen)
+
+	assert.True(t, Summarize("/work/empty", nil).LastActivity.IsZero())
+}
+
+func TestStoreRecord(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStoreWithPath(filepath.Join(dir, "projects.json"))
+	api := filepath.Join(dir, "api")
+	web := filepath.Join(dir, "web")
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	projects, err := store.List()
+	require.NoError(t, err)
+	assert.Empty(t, projects)
+
+	require.NoError(t, store.Record(Project{Path: api, Sessions: 1, LastActivity: start}, start))
+	require.NoError(t, store.Record(Project{Path: web, Sessions: 3, LastActivity: start.Add(time.Hour)}, start.Add(time.Hour)))
+	// updating keeps when the project was first seen, and its activity when none is given
+	require.NoError(t, store.Record(Project{Path: api, Sessions: 2}, start.Add(2*time.Hour)))
+
+	projects, err = store.List()
+	require.NoError(t, err)
+	require.Len(t, projects, 2)
+	assert.Equal(t, "web", projects[0].Name, "most recently active first")
+	assert.Equal(t, "api", projects[1].Name)
+	assert.Equal(t, 2, projects[1].Sessions)
+	assert.Equal(t, start, projects[1].FirstSeen)
+	assert.Equal(t, start, projects[1].LastActivity)
+
+	require.NoError(t, store.Remove(web))
+	projects, err = store.List()
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	assert.Equal(t, "api", projects[0].Name)
+}
+
+func TestStoreRebuild(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStoreWithPath(filepath.Join(dir, "projects.json"))
+	api := filepath.Join(dir, "api")
+	web := filepath.Join(dir, "web")
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	require.NoError(t, store.Record(Project{Path: web, Sessions: 4, LastActivity: start}, start))
+	require.NoError(t, store.Rebuild([]*types.Session{
+		testSession(api, types.SessionStateActive, start.Add(time.Hour)),
+		testSession(api, types.SessionStateArchived, start),
+	}, start.Add(2*time.Hour)))
+
+	projects, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, projects, 2)
+
+	assert.Equal(t, "api", projects[0].Name)
+	assert.Equal(t, 2, projects[0].Sessions)
+	assert.Equal(t, 1, projects[0].Archived)
+	assert.Equal(t, start.Add(-time.Hour), projects[0].FirstSeen, "when its first session was created")
+
+	// a registered project whose sessions are gone is kept with no sessions
+	assert.Equal(t, "web", projects[1].Name)
+	assert.Zero(t, projects[1].Sessions)
+	assert.Equal(t, start, projects[1].LastActivity)
+}
+
+func TestStoreCorrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "projects.json")
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0o600))
+
+	_, err := NewStoreWithPath(path).List()
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeStorageCorrupted, agxErr.Code)
+}
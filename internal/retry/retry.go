@@ -0,0 +1,157 @@
+// Package retry runs operations again after transient failures, backing off between attempts
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Policy decides how often and how patiently an operation is retried
+type Policy struct {
+	MaxAttempts  int           // attempts including the first; 0 means until MaxElapsed runs out
+	MaxElapsed   time.Duration // give up once this much time has passed; 0 means no limit
+	InitialDelay time.Duration
+	MaxDelay     time.Duration // cap on a single delay; 0 means no cap
+	Multiplier   float64       // growth of the delay per attempt; below 1 keeps it constant
+	Jitter       float64       // fraction of each delay that is randomized, 0..1
+	// Codes limits retries to errors carrying one of these codes; empty retries every error
+	Codes []types.ErrorCode
+}
+
+// Default backs off from 100ms to 2s over up to 3 attempts
+var Default = Policy{
+	MaxAttempts:  3,
+	InitialDelay: 100 * time.Millisecond,
+	MaxDelay:     2 * time.Second,
+	Multiplier:   2,
+	Jitter:       0.2,
+}
+
+// sleep and now are replaced in tests
+var (
+	sleep = sleepContext
+	now   = time.Now
+)
+
+// sleepContext waits for d, returning early with ctx's error when it is canceled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return types.ContextError(ctx)
+	}
+}
+
+// permanentError stops retries regardless of the policy
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying; Do returns the wrapped error
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Retryable reports whether the policy retries err
+func (p Policy) Retryable(err error) bool {
+	var permanent *permanentError
+	if errors.As(err, &permanent) {
+		return false
+	}
+	if len(p.Codes) == 0 {
+		return true
+	}
+	var agxErr *types.AGXError
+	if !errors.As(err, &agxErr) {
+		return false
+	}
+	for _, code := range p.Codes {
+		if agxErr.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Delay returns the wait before attempt+1, without jitter
+func (p Policy) Delay(attempt int) time.Duration {
+	delay := float64(p.InitialDelay)
+	for i := 1; i < attempt && p.Multiplier > 1; i++ {
+		delay *= p.Multiplier
+		if p.MaxDelay > 0 && delay >= float64(p.MaxDelay) {
+			break
+		}
+	}
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+	return time.Duration(delay)
+}
+
+// jittered randomizes the Jitter fraction of delay
+func (p Policy) jittered(delay time.Duration) time.Duration {
+	if p.Jitter <= 0 || delay <= 0 {
+		return delay
+	}
+	jitter := p.Jitter
+	if jitter > 1 {
+		jitter = 1
+	}
+	spread := float64(delay) * jitter
+	return time.Duration(float64(delay) - spread + rand.Float64()*spread) // #nosec G404 -- jitter needs no crypto randomness
+}
+
+// Do calls fn until it succeeds, returns an error the policy doesn't retry, the policy runs out
+// or ctx is canceled; fn receives the attempt number starting at 1, and the last error is returned
+func Do(ctx context.Context, policy Policy, fn func(attempt int) error) error {
+	start := now()
+	for attempt := 1; ; attempt++ {
+		if err := types.ContextError(ctx); err != nil {
+			return err
+		}
+		err := fn(attempt)
+		if err == nil {
+			return nil
+		}
+		if !policy.Retryable(err) {
+			var permanent *permanentError
+			if errors.As(err, &permanent) {
+				return permanent.err
+			}
+			return err
+		}
+		if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+			return err
+		}
+		if policy.MaxAttempts <= 0 && policy.MaxElapsed <= 0 {
+			return err // an unbounded policy would never give up
+		}
+
+		delay := policy.jittered(policy.Delay(attempt))
+		if policy.MaxElapsed > 0 {
+			remaining := policy.MaxElapsed - now().Sub(start)
+			if remaining <= 0 {
+				return err
+			}
+			if delay > remaining {
+				delay = remaining
+			}
+		}
+		if sleepErr := sleep(ctx, delay); sleepErr != nil {
+			return sleepErr
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// fakeClock records sleeps and advances a fake now instead of waiting
+func fakeClock(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var slept []time.Duration
+	current := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	sleep = func(_ context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		current = current.Add(d)
+		return nil
+	}
+	now = func() time.Time { return current }
+	t.Cleanup(func() {
+		sleep = sleepContext
+		now = time.Now
+	})
+	return &slept
+}
+
+func TestDo_SucceedsAfterRetries(t *testing.T) {
+	slept := fakeClock(t)
+	policy := Policy{MaxAttempts: 5, InitialDelay: 100 * time.Millisecond, Multiplier: 2}
+
+	calls := 0
+	err := Do(context.Background(), policy, func(attempt int) error {
+		calls++
+		assert.Equal(t, calls, attempt)
+		if attempt < 3 {
+			return errors.New("busy")
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, *slept)
+}
+
+func TestDo_GivesUpAfterMaxAttempts(t *testing.T) {
+	fakeClock(t)
+	policy := Policy{MaxAttempts: 3, InitialDelay: time.Millisecond}
+
+	calls := 0
+	err := Do(context.Background(), policy, func(int) error {
+		calls++
+		return errors.New("busy")
+	})
+
+	assert.EqualError(t, err, "busy")
+	assert.Equal(t, 3, calls)
+}
+
+func TestDo_StopsAtMaxElapsed(t *testing.T) {
+	slept := fakeClock(t)
+	policy := Policy{MaxElapsed: 2500 * time.Millisecond, InitialDelay: time.Second}
+
+	calls := 0
+	err := Do(context.Background(), policy, func(int) error {
+		calls++
+		return errors.New("not yet")
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, 4, calls)
+	assert.Equal(t, []time.Duration{time.Second, time.Second, 500 * time.Millisecond}, *slept, "the last wait is cut to the deadline")
+}
+
+func TestDo_OnlyRetriesListedCodes(t *testing.T) {
+	fakeClock(t)
+	policy := Policy{MaxAttempts: 5, Codes: []types.ErrorCode{types.ErrCodeStorageLocked}}
+
+	calls := 0
+	err := Do(context.Background(), policy, func(int) error {
+		calls++
+		return types.NewStorageError(types.ErrCodeStoragePermission, "denied", nil)
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+
+	calls = 0
+	err = Do(context.Background(), policy, func(int) error {
+		calls++
+		return types.NewStorageError(types.ErrCodeStorageLocked, "locked", nil)
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 5, calls)
+}
+
+func TestDo_PermanentStopsRetries(t *testing.T) {
+	fakeClock(t)
+	cause := errors.New("bad input")
+
+	calls := 0
+	err := Do(context.Background(), Default, func(int) error {
+		calls++
+		return Permanent(cause)
+	})
+
+	assert.Same(t, cause, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestDo_UnboundedPolicyDoesNotLoop(t *testing.T) {
+	fakeClock(t)
+
+	calls := 0
+	err := Do(context.Background(), Policy{}, func(int) error {
+		calls++
+		return errors.New("busy")
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestDo_StopsWhenCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	policy := Policy{MaxAttempts: 5, InitialDelay: time.Hour}
+
+	calls := 0
+	err := Do(ctx, policy, func(int) error {
+		calls++
+		cancel()
+		return errors.New("busy")
+	})
+
+	var agxErr *types.AGXError
+	assert.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeInterrupted, agxErr.Code)
+	assert.Equal(t, 1, calls, "the hour-long wait is cut short")
+}
+
+func TestDelay(t *testing.T) {
+	policy := Policy{InitialDelay: 100 * time.Millisecond, MaxDelay: time.Second, Multiplier: 3}
+
+	assert.Equal(t, 100*time.Millisecond, policy.Delay(1))
+	assert.Equal(t, 300*time.Millisecond, policy.Delay(2))
+	assert.Equal(t, 900*time.Millisecond, policy.Delay(3))
+	assert.Equal(t, time.Second, policy.Delay(4))
+	assert.Equal(t, time.Second, policy.Delay(50))
+}
+
+func TestJitterStaysWithinBounds(t *testing.T) {
+	policy := Policy{Jitter: 0.5}
+
+	for i := 0; i < 100; i++ {
+		delay := policy.jittered(time.Second)
+		assert.GreaterOrEqual(t, delay, 500*time.Millisecond)
+		assert.LessOrEqual(t, delay, time.Second)
+	}
+}
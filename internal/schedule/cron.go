@@ -0,0 +1,169 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a parsed five-field cron expression (minute hour day-of-month month day-of-week)
+type Cron struct {
+	expr    string
+	minute  fieldSet
+	hour    fieldSet
+	dom     fieldSet
+	month   fieldSet
+	dow     fieldSet
+	domStar bool
+	dowStar bool
+}
+
+// fieldSet records which values of a cron field match
+type fieldSet map[int]bool
+
+// maxSearchYears bounds the search for the next matching time
+const maxSearchYears = 5
+
+// ParseCron parses a standard five-field cron expression
+// Supported syntax per field: "*", single values, ranges (1-5), lists (1,3,5) and steps (*/15, 0-30/10)
+func ParseCron(expr string) (*Cron, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields, got %d", expr, len(fields))
+	}
+
+	c := &Cron{expr: expr}
+	var err error
+	if c.minute, err = parseField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("invalid minute field: %w", err)
+	}
+	if c.hour, err = parseField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("invalid hour field: %w", err)
+	}
+	if c.dom, err = parseField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("invalid day-of-month field: %w", err)
+	}
+	if c.month, err = parseField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("invalid month field: %w", err)
+	}
+	if c.dow, err = parseField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("invalid day-of-week field: %w", err)
+	}
+
+	// Both 0 and 7 mean Sunday
+	if c.dow[7] {
+		c.dow[0] = true
+	}
+	c.domStar = fields[2] == "*"
+	c.dowStar = fields[4] == "*"
+
+	return c, nil
+}
+
+// String returns the original expression
+func (c *Cron) String() string {
+	return c.expr
+}
+
+// Matches reports whether t (truncated to the minute) matches the expression
+func (c *Cron) Matches(t time.Time) bool {
+	return c.minute[t.Minute()] && c.hour[t.Hour()] && c.month[int(t.Month())] && c.dayMatches(t)
+}
+
+// Next returns the first matching time strictly after t, or the zero time if none exists
+func (c *Cron) Next(t time.Time) time.Time {
+	next := t.Truncate(time.Minute).Add(time.Minute)
+	limit := next.AddDate(maxSearchYears, 0, 0)
+
+	for next.Before(limit) {
+		if !c.month[int(next.Month())] {
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, next.Location())
+			continue
+		}
+		if !c.dayMatches(next) {
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, next.Location())
+			continue
+		}
+		if !c.hour[next.Hour()] {
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, next.Location())
+			continue
+		}
+		if !c.minute[next.Minute()] {
+			next = next.Add(time.Minute)
+			continue
+		}
+		return next
+	}
+
+	return time.Time{}
+}
+
+// dayMatches applies cron's day-of-month / day-of-week semantics:
+// when both fields are restricted, either one matching is enough
+func (c *Cron) dayMatches(t time.Time) bool {
+	domMatch := c.dom[t.Day()]
+	dowMatch := c.dow[int(t.Weekday())]
+
+	switch {
+	case c.domStar && c.dowStar:
+		return true
+	case c.domStar:
+		return dowMatch
+	case c.dowStar:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
+
+// parseField parses a single cron field into the set of matching values
+func parseField(field string, minValue, maxValue int) (fieldSet, error) {
+	set := make(fieldSet)
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			rangePart = part[:idx]
+			parsedStep, err := strconv.Atoi(part[idx+1:])
+			if err != nil || parsedStep <= 0 {
+				return nil, fmt.Errorf("invalid step in %q", part)
+			}
+			step = parsedStep
+		}
+
+		low, high := minValue, maxValue
+		switch {
+		case rangePart == "*":
+			// full range
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if low, err = strconv.Atoi(bounds[0]); err != nil {
+				return nil, fmt.Errorf("invalid range start in %q", part)
+			}
+			if high, err = strconv.Atoi(bounds[1]); err != nil {
+				return nil, fmt.Errorf("invalid range end in %q", part)
+			}
+		default:
+			value, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %q", part)
+			}
+			low = value
+			if step == 1 {
+				high = value
+			}
+		}
+
+		if low < minValue || high > maxValue || low > high {
+			return nil, fmt.Errorf("value %q out of range %d-%d", part, minValue, maxValue)
+		}
+
+		for v := low; v <= high; v += step {
+			set[v] = true
+		}
+	}
+
+	return set, nil
+}
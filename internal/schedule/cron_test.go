@@ -0,0 +1,76 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron_Invalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+	}
+
+	for _, expr := range invalid {
+		_, err := ParseCron(expr)
+		assert.Error(t, err, "expected error for %q", expr)
+	}
+}
+
+func TestCronNext_WeeklyMonday(t *testing.T) {
+	cron, err := ParseCron("0 9 * * 1")
+	require.NoError(t, err)
+
+	// Wednesday 2025-01-01 12:00 -> Monday 2025-01-06 09:00
+	from := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC), cron.Next(from))
+
+	// Exactly at a match returns the following week
+	at := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 1, 13, 9, 0, 0, 0, time.UTC), cron.Next(at))
+}
+
+func TestCronNext_Steps(t *testing.T) {
+	cron, err := ParseCron("*/15 * * * *")
+	require.NoError(t, err)
+
+	from := time.Date(2025, 3, 10, 10, 7, 30, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 3, 10, 10, 15, 0, 0, time.UTC), cron.Next(from))
+}
+
+func TestCronNext_DayOfMonthOrDayOfWeek(t *testing.T) {
+	// Fires on the 1st of the month or on Fridays
+	cron, err := ParseCron("0 0 1 * 5")
+	require.NoError(t, err)
+
+	// Thursday 2025-01-02 -> Friday 2025-01-03
+	from := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC), cron.Next(from))
+}
+
+func TestCronNext_SundayAsSeven(t *testing.T) {
+	cron, err := ParseCron("30 8 * * 7")
+	require.NoError(t, err)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 1, 5, 8, 30, 0, 0, time.UTC), cron.Next(from))
+}
+
+func TestCronMatches(t *testing.T) {
+	cron, err := ParseCron("0-10/5 9,17 * 1-6 *")
+	require.NoError(t, err)
+
+	assert.True(t, cron.Matches(time.Date(2025, 2, 3, 9, 5, 0, 0, time.UTC)))
+	assert.True(t, cron.Matches(time.Date(2025, 2, 3, 17, 10, 0, 0, time.UTC)))
+	assert.False(t, cron.Matches(time.Date(2025, 2, 3, 9, 7, 0, 0, time.UTC)))
+	assert.False(t, cron.Matches(time.Date(2025, 8, 3, 9, 5, 0, 0, time.UTC)))
+}
@@ -0,0 +1,235 @@
+// Package schedule manages recurring headless prompts executed by the Kamui daemon
+package schedule
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Schedule is a recurring headless prompt bound to a session
+type Schedule struct {
+	ID          string     `json:"id"`
+	SessionID   string     `json:"sessionId"`
+	ProjectPath string     `json:"projectPath"`
+	Cron        string     `json:"cron"`
+	Prompt      string     `json:"prompt"`
+	Created     time.Time  `json:"created"`
+	LastRun     *time.Time `json:"lastRun,omitempty"`
+	LastStatus  string     `json:"lastStatus,omitempty"`
+}
+
+// NextRun returns the next time the schedule should fire after its last run (or creation)
+func (s *Schedule) NextRun() (time.Time, error) {
+	cron, err := ParseCron(s.Cron)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	from := s.Created
+	if s.LastRun != nil {
+		from = *s.LastRun
+	}
+	return cron.Next(from), nil
+}
+
+// IsDue reports whether the schedule should fire at now
+func (s *Schedule) IsDue(now time.Time) bool {
+	next, err := s.NextRun()
+	if err != nil || next.IsZero() {
+		return false
+	}
+	return !next.After(now)
+}
+
+// Store persists schedules in a single JSON file
+type Store struct {
+	path string
+}
+
+// NewStore creates a store backed by ~/.kamui/schedules.json
+func NewStore() *Store {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+	return NewStoreWithPath(filepath.Join(homeDir, ".kamui", "schedules.json"))
+}
+
+// NewStoreWithPath creates a store backed by the given file
+func NewStoreWithPath(path string) *Store {
+	return &Store{path: path}
+}
+
+// List returns all schedules ordered by creation time
+func (s *Store) List() ([]*Schedule, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return []*Schedule{}, nil
+	}
+	if err != nil {
+		return nil, types.NewStorageError(
+			types.ErrCodeStoragePermission,
+			"failed to read schedules file",
+			err,
+		)
+	}
+
+	var schedules []*Schedule
+	if err := json.Unmarshal(data, &schedules); err != nil {
+		return nil, types.NewStorageError(
+			types.ErrCodeStorageCorrupted,
+			"failed to parse schedules file",
+			err,
+		)
+	}
+
+	sort.SliceStable(schedules, func(i, j int) bool {
+		return schedules[i].Created.Before(schedules[j].Created)
+	})
+	return schedules, nil
+}
+
+// Add validates and stores a new schedule, assigning it an ID
+func (s *Store) Add(schedule *Schedule) error {
+	if _, err := ParseCron(schedule.Cron); err != nil {
+		return types.NewSessionError(types.ErrCodeInvalidInput, "invalid cron expression", err)
+	}
+
+	schedules, err := s.List()
+	if err != nil {
+		return err
+	}
+
+	if schedule.ID == "" {
+		schedule.ID = newID()
+	}
+	if schedule.Created.IsZero() {
+		schedule.Created = time.Now()
+	}
+
+	return s.save(append(schedules, schedule))
+}
+
+// Remove deletes the schedule with the given ID
+func (s *Store) Remove(id string) error {
+	schedules, err := s.List()
+	if err != nil {
+		return err
+	}
+
+	kept := make([]*Schedule, 0, len(schedules))
+	for _, schedule := range schedules {
+		if schedule.ID != id {
+			kept = append(kept, schedule)
+		}
+	}
+
+	if len(kept) == len(schedules) {
+		return types.NewSessionError(
+			types.ErrCodeInvalidInput,
+			fmt.Sprintf("schedule '%s' not found", id),
+			nil,
+		)
+	}
+
+	return s.save(kept)
+}
+
+// RecordRun stores the outcome of a schedule execution
+func (s *Store) RecordRun(id string, ranAt time.Time, status string) error {
+	schedules, err := s.List()
+	if err != nil {
+		return err
+	}
+
+	for _, schedule := range schedules {
+		if schedule.ID == id {
+			schedule.LastRun = &ranAt
+			schedule.LastStatus = status
+			return s.save(schedules)
+		}
+	}
+
+	return types.NewSessionError(
+		types.ErrCodeInvalidInput,
+		fmt.Sprintf("schedule '%s' not found", id),
+		nil,
+	)
+}
+
+// Rename points the schedules of a project's session at its new name and reports how many were moved
+func (s *Store) Rename(projectPath, sessionID, newID string) (int, error) {
+	schedules, err := s.List()
+	if err != nil {
+		return 0, err
+	}
+
+	moved := 0
+	for _, schedule := range schedules {
+		if schedule.ProjectPath == projectPath && schedule.SessionID == sessionID {
+			schedule.SessionID = newID
+			moved++
+		}
+	}
+	if moved == 0 {
+		return 0, nil
+	}
+	return moved, s.save(schedules)
+}
+
+// save writes all schedules atomically
+func (s *Store) save(schedules []*Schedule) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return types.NewStorageError(
+			types.ErrCodeStoragePermission,
+			"failed to create schedules directory",
+			err,
+		)
+	}
+
+	data, err := json.MarshalIndent(schedules, "", "  ")
+	if err != nil {
+		return types.NewStorageError(
+			types.ErrCodeStorageCorrupted,
+			"failed to marshal schedules",
+			err,
+		)
+	}
+
+	tempFile := s.path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0o600); err != nil {
+		return types.NewStorageError(
+			types.ErrCodeStoragePermission,
+			"failed to write schedules file",
+			err,
+		)
+	}
+
+	if err := os.Rename(tempFile, s.path); err != nil {
+		os.Remove(tempFile) // cleanup temp file
+		return types.NewStorageError(
+			types.ErrCodeStoragePermission,
+			"failed to save schedules file",
+			err,
+		)
+	}
+
+	return nil
+}
+
+// newID returns a short random schedule identifier
+func newID() string {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
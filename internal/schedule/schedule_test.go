@@ -0,0 +1,102 @@
+package schedule
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestStore_AddListRemove(t *testing.T) {
+	store := NewStoreWithPath(filepath.Join(t.TempDir(), "schedules.json"))
+
+	schedules, err := store.List()
+	require.NoError(t, err)
+	assert.Empty(t, schedules)
+
+	schedule := &Schedule{SessionID: "weekly", Cron: "0 9 * * 1", Prompt: "summarize open TODOs"}
+	require.NoError(t, store.Add(schedule))
+	assert.NotEmpty(t, schedule.ID)
+
+	schedules, err = store.List()
+	require.NoError(t, err)
+	require.Len(t, schedules, 1)
+	assert.Equal(t, "weekly", schedules[0].SessionID)
+
+	require.NoError(t, store.Remove(schedule.ID))
+	schedules, err = store.List()
+	require.NoError(t, err)
+	assert.Empty(t, schedules)
+}
+
+func TestStore_AddInvalidCron(t *testing.T) {
+	store := NewStoreWithPath(filepath.Join(t.TempDir(), "schedules.json"))
+
+	err := store.Add(&Schedule{SessionID: "s", Cron: "every monday", Prompt: "p"})
+	require.Error(t, err)
+
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeInvalidInput, agxErr.Code)
+}
+
+func TestStore_RemoveUnknown(t *testing.T) {
+	store := NewStoreWithPath(filepath.Join(t.TempDir(), "schedules.json"))
+	require.Error(t, store.Remove("missing"))
+}
+
+func TestStore_RecordRun(t *testing.T) {
+	store := NewStoreWithPath(filepath.Join(t.TempDir(), "schedules.json"))
+
+	schedule := &Schedule{SessionID: "s", Cron: "0 * * * *", Prompt: "p"}
+	require.NoError(t, store.Add(schedule))
+
+	ranAt := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, store.RecordRun(schedule.ID, ranAt, "success"))
+
+	schedules, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, schedules, 1)
+	require.NotNil(t, schedules[0].LastRun)
+	assert.True(t, ranAt.Equal(*schedules[0].LastRun))
+	assert.Equal(t, "success", schedules[0].LastStatus)
+}
+
+func TestStore_Rename(t *testing.T) {
+	store := NewStoreWithPath(filepath.Join(t.TempDir(), "schedules.json"))
+
+	moved, err := store.Rename("/work/api", "auth", "login")
+	require.NoError(t, err)
+	assert.Zero(t, moved, "a missing schedules file has nothing to move")
+
+	require.NoError(t, store.Add(&Schedule{SessionID: "auth", ProjectPath: "/work/api", Cron: "0 * * * *", Prompt: "p"}))
+	// the same session name in another project keeps its schedule
+	require.NoError(t, store.Add(&Schedule{SessionID: "auth", ProjectPath: "/work/web", Cron: "0 * * * *", Prompt: "p"}))
+
+	moved, err = store.Rename("/work/api", "auth", "login")
+	require.NoError(t, err)
+	assert.Equal(t, 1, moved)
+
+	schedules, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, schedules, 2)
+	assert.Equal(t, "login", schedules[0].SessionID)
+	assert.Equal(t, "auth", schedules[1].SessionID)
+}
+
+func TestScheduleIsDue(t *testing.T) {
+	created := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	schedule := &Schedule{Cron: "0 9 * * 1", Created: created}
+
+	assert.False(t, schedule.IsDue(time.Date(2025, 1, 6, 8, 59, 0, 0, time.UTC)))
+	assert.True(t, schedule.IsDue(time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)))
+
+	lastRun := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	schedule.LastRun = &lastRun
+	assert.False(t, schedule.IsDue(time.Date(2025, 1, 6, 9, 30, 0, 0, time.UTC)))
+	assert.True(t, schedule.IsDue(time.Date(2025, 1, 13, 9, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,209 @@
+// Package schema derives JSON Schemas for Kamui's file formats from their Go types
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Draft is the JSON Schema dialect generated
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema node
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 interface{}        `json:"type,omitempty"` // a type name, or a list of them
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// Document describes one of Kamui's file formats
+type Document struct {
+	Name  string
+	Title string
+	Value interface{}
+	// Required marks fields Kamui always writes as required; off for hand-written files
+	Required bool
+}
+
+// Documents are the formats a schema can be generated for
+var Documents = []Document{
+	{Name: "session", Title: "Kamui session", Value: types.Session{}, Required: true},
+	{Name: "index", Title: "Kamui global session index", Value: types.GlobalIndex{}, Required: true},
+	{Name: "config", Title: "Kamui configuration", Value: types.Config{}},
+}
+
+// enums lists the allowed values of string types backed by constants
+var enums = map[reflect.Type][]interface{}{
+	reflect.TypeOf(types.SessionState("")): {
+		types.SessionStateActive,
+		types.SessionStatePaused,
+		types.SessionStateCompleted,
+		types.SessionStateArchived,
+		types.SessionStateError,
+	},
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// durationType is written as a string such as "30d"; durationPattern matches it, or "" for zero
+var durationType = reflect.TypeOf(types.Duration(0))
+
+const durationPattern = `^(0|([0-9]+(\.[0-9]+)?(w|d|h|m|s|ms|us|µs|ns))+)?$`
+
+// Find returns the document with the given name
+func Find(name string) (Document, bool) {
+	for _, document := range Documents {
+		if document.Name == name {
+			return document, true
+		}
+	}
+	return Document{}, false
+}
+
+// Names lists the documents' names
+func Names() []string {
+	names := make([]string, 0, len(Documents))
+	for _, document := range Documents {
+		names = append(names, document.Name)
+	}
+	return names
+}
+
+// Generate builds the schema for a document; nested structs become $defs referenced by type name
+func Generate(document Document) *Schema {
+	g := &generator{required: document.Required, defs: map[string]*Schema{}}
+	root := g.structSchema(reflect.TypeOf(document.Value))
+	root.Schema = Draft
+	root.ID = fmt.Sprintf("https://github.com/bitomule/kamui/schemas/%s.schema.json", document.Name)
+	root.Title = document.Title
+	if len(g.defs) > 0 {
+		root.Defs = g.defs
+	}
+	return root
+}
+
+type generator struct {
+	required bool
+	defs     map[string]*Schema
+}
+
+// schemaFor returns the schema of a field's type; nullable is set for values encoding/json may write as null
+func (g *generator) schemaFor(t reflect.Type, nullable bool) *Schema {
+	if t.Kind() == reflect.Ptr {
+		return orNull(g.schemaFor(t.Elem(), false))
+	}
+	if values, ok := enums[t]; ok {
+		return &Schema{Type: "string", Enum: values}
+	}
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+	if t == durationType {
+		return &Schema{Type: "string", Pattern: durationPattern}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		schema := &Schema{Type: "array", Items: g.schemaFor(t.Elem(), false)}
+		if nullable && t.Kind() == reflect.Slice {
+			schema.Type = []string{"array", "null"}
+		}
+		return schema
+	case reflect.Map:
+		schema := &Schema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem(), false)}
+		if nullable {
+			schema.Type = []string{"object", "null"}
+		}
+		return schema
+	case reflect.Struct:
+		name := t.Name()
+		if _, done := g.defs[name]; !done {
+			g.defs[name] = nil // placeholder so self-referencing types terminate
+			g.defs[name] = g.structSchema(t)
+		}
+		return &Schema{Ref: "#/$defs/" + name}
+	default:
+		return &Schema{} // interface{} and anything else: any value
+	}
+}
+
+// structSchema describes a struct's JSON fields
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, omitEmpty, skip := jsonName(field)
+		if skip {
+			continue
+		}
+
+		// Without omitempty, nil slices and maps are written as null
+		schema.Properties[name] = g.schemaFor(field.Type, !omitEmpty)
+		if g.required && !omitEmpty {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	sort.Strings(schema.Required)
+
+	return schema
+}
+
+// jsonName returns the field's JSON key and whether it is omitted when empty or skipped entirely
+func jsonName(field reflect.StructField) (string, bool, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	omitEmpty := false
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty, false
+}
+
+// orNull allows null in addition to the schema
+func orNull(schema *Schema) *Schema {
+	if schema.Ref == "" && schema.Type != nil && schema.Enum == nil {
+		if name, ok := schema.Type.(string); ok {
+			schema.Type = []string{name, "null"}
+			return schema
+		}
+	}
+	return &Schema{AnyOf: []*Schema{schema, {Type: "null"}}}
+}
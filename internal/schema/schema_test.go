@@ -0,0 +1,87 @@
+package schema
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestGenerate_Session(t *testing.T) {
+	document, ok := Find("session")
+	require.True(t, ok)
+	schema := Generate(document)
+
+	assert.Equal(t, Draft, schema.Schema)
+	assert.Equal(t, "https://github.com/bitomule/kamui/schemas/session.schema.json", schema.ID)
+	assert.Equal(t, "string", schema.Properties["sessionId"].Type)
+	assert.Equal(t, &Schema{Type: "string", Format: "date-time"}, schema.Properties["created"])
+	assert.Equal(t, "#/$defs/SessionMeta", schema.Properties["metadata"].Ref)
+	assert.Contains(t, schema.Required, "sessionId")
+
+	meta := schema.Defs["SessionMeta"]
+	require.NotNil(t, meta)
+	assert.Equal(t, []string{"array", "null"}, meta.Properties["tags"].Type)
+	assert.Equal(t, &Schema{}, meta.Properties["customData"].AdditionalProperties)
+	assert.Equal(t, &Schema{AnyOf: []*Schema{{Ref: "#/$defs/Budget"}, {Type: "null"}}}, meta.Properties["budget"])
+	assert.NotContains(t, meta.Required, "notes", "omitempty fields are optional")
+	assert.Equal(t, "array", meta.Properties["notes"].Type)
+
+	lifecycle := schema.Defs["LifecycleInfo"]
+	require.NotNil(t, lifecycle)
+	assert.Contains(t, lifecycle.Properties["state"].Enum, types.SessionStateActive)
+
+	cleanup := schema.Defs["CleanupConfig"]
+	require.NotNil(t, cleanup)
+	threshold := cleanup.Properties["inactiveThreshold"]
+	assert.Equal(t, "string", threshold.Type)
+	pattern := regexp.MustCompile(threshold.Pattern)
+	for _, value := range []string{"", "0", "30d", "1w2d", "1h30m", "1.5h"} {
+		assert.True(t, pattern.MatchString(value), value)
+	}
+	assert.False(t, pattern.MatchString("30 days"))
+
+	resume := schema.Defs["ResumeInfo"]
+	require.NotNil(t, resume)
+	assert.Equal(t, &Schema{Type: []string{"string", "null"}, Format: "date-time"}, resume.Properties["lastResumeAttempt"])
+}
+
+func TestGenerate_ConfigHasNoRequiredFields(t *testing.T) {
+	document, ok := Find("config")
+	require.True(t, ok)
+	schema := Generate(document)
+
+	assert.Empty(t, schema.Required)
+	for name, def := range schema.Defs {
+		assert.Empty(t, def.Required, name)
+	}
+}
+
+func TestGenerate_CoversWrittenSessions(t *testing.T) {
+	document, _ := Find("session")
+	schema := Generate(document)
+
+	session := types.Session{SessionID: "api", Created: time.Now()}
+	data, err := json.Marshal(session)
+	require.NoError(t, err)
+	var written map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &written))
+
+	for key := range written {
+		assert.Contains(t, schema.Properties, key)
+	}
+	for _, key := range schema.Required {
+		assert.Contains(t, written, key)
+	}
+}
+
+func TestFindUnknown(t *testing.T) {
+	_, ok := Find("nope")
+	assert.False(t, ok)
+	assert.Equal(t, []string{"session", "index", "config"}, Names())
+}
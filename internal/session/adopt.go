@@ -0,0 +1,111 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bitomule/kamui/internal/paths"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// adoptedDescriptionMax bounds the description taken from an adopted conversation's first prompt
+const adoptedDescriptionMax = 120
+
+// AdoptClaudeSession creates a session bound to a Claude conversation that was started without Kamui
+// The session is named after the conversation's first prompt and dated from when it started
+func (m *Manager) AdoptClaudeSession(ctx context.Context, claudeSessionID, workingDir, firstPrompt string, started time.Time) (*types.Session, error) {
+	if claudeSessionID == "" {
+		return nil, types.NewSessionError(types.ErrCodeInvalidInput, "Claude session ID cannot be empty", nil)
+	}
+
+	description := adoptedDescription(firstPrompt)
+	slug := Slugify(description)
+	if slug == "" {
+		slug = "adopted-" + shortID(claudeSessionID)
+	}
+
+	sessionName, err := m.availableName(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	session, err := m.newSession(sessionName)
+	if err != nil {
+		return nil, err
+	}
+
+	session.Project.WorkingDirectory = paths.Canonical(workingDir)
+	session.Claude.SessionID = claudeSessionID
+	session.Metadata.Description = description
+	session.Lifecycle.StateHistory[0].Reason = "session_adopted"
+	if !started.IsZero() {
+		session.Created = started
+		session.Lifecycle.StateHistory[0].Timestamp = started
+	}
+
+	if err := m.saveSession(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// BindClaudeSession binds a session to a Claude conversation in its working directory, for when
+// Kamui didn't spot the conversation Claude started for it
+// A session already bound to another conversation is only rebound with replace
+func (m *Manager) BindClaudeSession(ctx context.Context, sessionName, claudeSessionID string, replace bool) error {
+	if claudeSessionID == "" {
+		return types.NewSessionError(types.ErrCodeInvalidInput, "Claude session ID cannot be empty", nil)
+	}
+
+	session, err := m.loadSession(ctx, sessionName)
+	if err != nil {
+		return err
+	}
+	if !replace && !session.Claude.Pending && session.Claude.SessionID != "" && session.Claude.SessionID != claudeSessionID {
+		return types.NewSessionError(
+			types.ErrCodeInvalidInput,
+			fmt.Sprintf("session '%s' already continues conversation %s", session.SessionID, session.Claude.SessionID),
+			nil,
+		)
+	}
+
+	exists, err := m.claudeClient.HasSession(ctx, claudeSessionID, session.Project.WorkingDirectory)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return types.NewClaudeError(
+			types.ErrCodeClaudeSessionNotFound,
+			fmt.Sprintf("Claude conversation %s not found for %s", claudeSessionID, session.Project.WorkingDirectory),
+			nil,
+		)
+	}
+
+	session.Claude.SessionID = claudeSessionID
+	session.Claude.Pending = false
+	session.Claude.HasActiveContext = true
+	session.Claude.ResumeInfo.CanResume = true
+	session.Claude.ResumeInfo.ResumeCommand = ""
+	session.LastModified = m.clock.Now()
+
+	return m.saveSession(ctx, session)
+}
+
+// adoptedDescription returns the first line of a prompt, shortened to adoptedDescriptionMax runes
+func adoptedDescription(prompt string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(prompt), "\n")
+	line = strings.TrimSpace(line)
+	if runes := []rune(line); len(runes) > adoptedDescriptionMax {
+		line = strings.TrimSpace(string(runes[:adoptedDescriptionMax-1])) + "…"
+	}
+	return line
+}
+
+// shortID returns the first eight characters of an ID
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
@@ -0,0 +1,98 @@
+package session
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestAdoptClaudeSession(t *testing.T) {
+	manager, testStorage := newNamesTestManager(t)
+	ctx := context.Background()
+	started := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	session, err := manager.AdoptClaudeSession(ctx, "claude-1", manager.GetProjectPath(), "Fix the login bug\nIt fails after a redirect", started)
+	require.NoError(t, err)
+	assert.Equal(t, "fix-login-bug", session.SessionID)
+	assert.Equal(t, "Fix the login bug", session.Metadata.Description)
+	assert.True(t, started.Equal(session.Created))
+
+	saved, err := testStorage.LoadSession(ctx, "fix-login-bug")
+	require.NoError(t, err)
+	assert.Equal(t, "claude-1", saved.Claude.SessionID)
+	assert.Equal(t, manager.GetProjectPath(), saved.Project.WorkingDirectory)
+	assert.Equal(t, "session_adopted", saved.Lifecycle.StateHistory[0].Reason)
+
+	again, err := manager.AdoptClaudeSession(ctx, "claude-2", manager.GetProjectPath(), "Fix the login bug for real", time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, "fix-login-bug-2", again.SessionID)
+}
+
+func TestAdoptClaudeSession_WithoutPrompt(t *testing.T) {
+	manager, _ := newNamesTestManager(t)
+
+	session, err := manager.AdoptClaudeSession(context.Background(), "0123456789abcdef", manager.GetProjectPath(), "", time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, "adopted-01234567", session.SessionID)
+
+	_, err = manager.AdoptClaudeSession(context.Background(), "", manager.GetProjectPath(), "hi", time.Time{})
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeInvalidInput, agxErr.Code)
+}
+
+func TestBindClaudeSession(t *testing.T) {
+	manager, testStorage := newNamesTestManager(t)
+	mockClient := manager.claudeClient.(*MockClaudeClient)
+	ctx := context.Background()
+
+	session, err := manager.newSession("api")
+	require.NoError(t, err)
+	session.Claude.SessionID = "pre-assigned"
+	session.Claude.Pending = true
+	require.NoError(t, manager.saveSession(ctx, session))
+
+	mockClient.On("HasSession", "claude-1", session.Project.WorkingDirectory).Return(true, nil)
+	mockClient.On("HasSession", "claude-2", session.Project.WorkingDirectory).Return(true, nil)
+	mockClient.On("HasSession", "elsewhere", session.Project.WorkingDirectory).Return(false, nil)
+
+	// a conversation Claude never wrote is simply replaced
+	require.NoError(t, manager.BindClaudeSession(ctx, "api", "claude-1", false))
+	saved, err := testStorage.LoadSession(ctx, "api")
+	require.NoError(t, err)
+	assert.Equal(t, "claude-1", saved.Claude.SessionID)
+	assert.False(t, saved.Claude.Pending)
+	assert.True(t, saved.Claude.HasActiveContext)
+	assert.True(t, saved.Claude.ResumeInfo.CanResume)
+
+	// binding again to the same conversation is harmless, another one needs replace
+	require.NoError(t, manager.BindClaudeSession(ctx, "api", "claude-1", false))
+	err = manager.BindClaudeSession(ctx, "api", "claude-2", false)
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeInvalidInput, agxErr.Code)
+	require.NoError(t, manager.BindClaudeSession(ctx, "api", "claude-2", true))
+	saved, err = testStorage.LoadSession(ctx, "api")
+	require.NoError(t, err)
+	assert.Equal(t, "claude-2", saved.Claude.SessionID)
+
+	// the conversation must exist for the session's directory
+	err = manager.BindClaudeSession(ctx, "api", "elsewhere", true)
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeClaudeSessionNotFound, agxErr.Code)
+	assert.Error(t, manager.BindClaudeSession(ctx, "api", "", false))
+}
+
+func TestAdoptedDescription(t *testing.T) {
+	assert.Equal(t, "First line", adoptedDescription("  First line  \nsecond"))
+
+	long := adoptedDescription(strings.Repeat("word ", 60))
+	assert.Len(t, []rune(long), adoptedDescriptionMax)
+	assert.True(t, strings.HasSuffix(long, "…"))
+}
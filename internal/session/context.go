@@ -0,0 +1,65 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bitomule/kamui/internal/claude"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// maxContextTodos caps the open todos listed in the session context
+const maxContextTodos = 10
+
+// SetInjectContext controls whether Claude launches with a description of the session appended
+// to its system prompt (claude.injectContext)
+func (m *Manager) SetInjectContext(enabled bool) {
+	m.injectContext = enabled
+}
+
+// ContextPrompt returns the short description of the session Claude is told it works in: its
+// name, description, linked issues and the conversation's open todos
+func ContextPrompt(session *types.Session, todos []claude.Todo) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "You are working in the Kamui session %q of project %s.", session.SessionID, session.Project.Name)
+	if session.Metadata.Description != "" {
+		fmt.Fprintf(&b, "\nSession goal: %s", session.Metadata.Description)
+	}
+	for _, link := range session.Metadata.Links {
+		title := ""
+		if link.Title != "" {
+			title = " " + link.Title
+		}
+		fmt.Fprintf(&b, "\nLinked %s: %s%s (%s)", link.Kind, link.Label, title, link.URL)
+	}
+	if len(todos) > 0 {
+		b.WriteString("\nOpen todos:")
+		for i, todo := range todos {
+			if i == maxContextTodos {
+				fmt.Fprintf(&b, "\n- and %d more", len(todos)-maxContextTodos)
+				break
+			}
+			fmt.Fprintf(&b, "\n- %s", todo.Content)
+		}
+	}
+	return b.String()
+}
+
+// systemPrompt returns what a launch appends to Claude's system prompt: the instructions of the
+// session's variant and, when enabled, the session context
+func (m *Manager) systemPrompt(session *types.Session) string {
+	var parts []string
+	if prompt := m.VariantPrompt(session).SystemPrompt; prompt != "" {
+		parts = append(parts, prompt)
+	}
+	if m.injectContext {
+		// the todos are a bonus; without them the context is still worth giving
+		var todos []claude.Todo
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			todos, _ = claude.OpenTodos(claude.TodosDir(homeDir), session.Claude.SessionID)
+		}
+		parts = append(parts, ContextPrompt(session, todos))
+	}
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,58 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/claude"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestContextPrompt(t *testing.T) {
+	session := &types.Session{SessionID: "api"}
+	session.Project.Name = "kamui"
+	assert.Equal(t, `You are working in the Kamui session "api" of project kamui.`, ContextPrompt(session, nil))
+
+	session.Metadata.Description = "Rework the parser"
+	session.Metadata.Links = []types.Link{{Kind: "jira", Label: "PROJ-1", Title: "Parser is slow", URL: "https://jira/PROJ-1"}}
+	assert.Equal(t, `You are working in the Kamui session "api" of project kamui.
+Session goal: Rework the parser
+Linked jira: PROJ-1 Parser is slow (https://jira/PROJ-1)
+Open todos:
+- Add tests`, ContextPrompt(session, []claude.Todo{{Content: "Add tests", Status: "pending"}}))
+}
+
+func TestContextPrompt_CapsTodos(t *testing.T) {
+	var todos []claude.Todo
+	for i := 0; i < maxContextTodos+2; i++ {
+		todos = append(todos, claude.Todo{Content: fmt.Sprintf("todo %d", i)})
+	}
+	prompt := ContextPrompt(&types.Session{SessionID: "api"}, todos)
+	assert.Contains(t, prompt, "- todo 9\n- and 2 more")
+	assert.NotContains(t, prompt, "todo 10")
+}
+
+func TestLaunchArgs_InjectContext(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("USERPROFILE", homeDir)
+	require.NoError(t, os.MkdirAll(claude.TodosDir(homeDir), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(claude.TodosDir(homeDir), "c-1-agent-c-1.json"),
+		[]byte(`[{"id": "1", "content": "Add tests", "status": "pending"}]`), 0o600))
+
+	manager, _, _ := newPromptsManager(t)
+	session := &types.Session{SessionID: "api"}
+	session.Project.Name = "kamui"
+	session.Claude.SessionID = "c-1"
+	session.Metadata.Variant = "review"
+	assert.Equal(t, []string{"--append-system-prompt", "Only review, never edit."}, manager.LaunchArgs(session))
+
+	manager.SetInjectContext(true)
+	assert.Equal(t, []string{"--append-system-prompt", "Only review, never edit.\n\n" +
+		"You are working in the Kamui session \"api\" of project kamui.\nOpen todos:\n- Add tests"}, manager.LaunchArgs(session))
+}
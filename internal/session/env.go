@@ -0,0 +1,221 @@
+package session
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// envNamePattern matches portable environment variable names
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ShortClaudeSessionID returns the Claude session ID shortened for display
+func ShortClaudeSessionID(session *types.Session) string {
+	claudeSessionShort := session.Claude.SessionID
+	if len(claudeSessionShort) > 8 {
+		claudeSessionShort = claudeSessionShort[:8] + "..."
+	}
+	return claudeSessionShort
+}
+
+// StatusLine returns the one-line session summary used by the banner and status line
+func StatusLine(session *types.Session) string {
+	return fmt.Sprintf("Kamui: %s | %s | %s",
+		session.SessionID,
+		ShortClaudeSessionID(session),
+		session.Project.Name)
+}
+
+// ReadOnlyPermissionMode is the Claude permission mode read-only sessions run in:
+// Claude can read and plan but not edit files or run commands
+const ReadOnlyPermissionMode = "plan"
+
+// BypassPermissionMode is the Claude permission mode that skips every permission prompt
+const BypassPermissionMode = "bypassPermissions"
+
+// permissionModes are the Claude permission modes a session can be pinned to, by accepted name
+var permissionModes = map[string]string{
+	"default":            "default",
+	"acceptEdits":        "acceptEdits",
+	"plan":               "plan",
+	"bypass":             BypassPermissionMode,
+	BypassPermissionMode: BypassPermissionMode,
+}
+
+// managedClaudeFlags are the Claude flags Kamui sets itself, so sessions can't store them
+var managedClaudeFlags = []string{"--resume", "-r", "--continue", "-c", "--session-id", "--print", "-p", "--permission-mode"}
+
+// ParsePermissionMode returns the Claude permission mode named by value (plan, acceptEdits,
+// default or bypass); an empty value means no mode is pinned
+func ParsePermissionMode(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	mode, ok := permissionModes[value]
+	if !ok {
+		return "", types.NewSessionError(
+			types.ErrCodeInvalidInput,
+			fmt.Sprintf("invalid permission mode '%s' (use plan, acceptEdits, default or bypass)", value),
+			nil,
+		)
+	}
+	return mode, nil
+}
+
+// unquotedArgPattern matches arguments that read the same in a shell without quoting
+var unquotedArgPattern = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// PermissionMode returns the Claude permission mode a session launches in, if any; read-only
+// sessions always run in plan mode whatever mode they were created with
+func PermissionMode(session *types.Session) string {
+	if session.Metadata.ReadOnly {
+		return ReadOnlyPermissionMode
+	}
+	return session.Claude.PermissionMode
+}
+
+// ClaudeArgs returns the extra Claude arguments a session launches with: the configured default
+// arguments, then the session's own, then its permission mode
+func ClaudeArgs(session *types.Session, defaultArgs []string) []string {
+	var args []string
+	args = append(args, defaultArgs...)
+	args = append(args, session.Metadata.ClaudeArgs...)
+	if mode := PermissionMode(session); mode != "" {
+		args = append(args, "--permission-mode", mode)
+	}
+	return args
+}
+
+// ClaudeCommand returns the Claude command line a session launches or resumes with, quoted for a shell
+func ClaudeCommand(session *types.Session, defaultArgs []string) string {
+	return formatCommand(session, ClaudeArgs(session, defaultArgs))
+}
+
+// formatCommand returns the Claude command line resuming the session with args, quoted for a shell
+// A pending conversation is started under its pre-assigned ID instead
+func formatCommand(session *types.Session, args []string) string {
+	command := []string{"claude"}
+	switch {
+	case session.Claude.Pending:
+		command = append(command, "--session-id", session.Claude.SessionID)
+	case session.Claude.SessionID != "":
+		command = append(command, "--resume", session.Claude.SessionID)
+	}
+	for _, arg := range args {
+		if !unquotedArgPattern.MatchString(arg) {
+			arg = shellQuote(arg)
+		}
+		command = append(command, arg)
+	}
+	return strings.Join(command, " ")
+}
+
+// ValidateClaudeArgs checks that args can be stored as a session's Claude arguments
+func ValidateClaudeArgs(args []string) error {
+	for _, arg := range args {
+		flag, _, _ := strings.Cut(arg, "=")
+		for _, managed := range managedClaudeFlags {
+			if flag == managed {
+				return types.NewSessionError(
+					types.ErrCodeInvalidInput,
+					fmt.Sprintf("'%s' is managed by Kamui and can't be stored as a session argument", managed),
+					nil,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// SetDefaultClaudeArgs sets the arguments every Claude launch starts with (claude.defaultArgs)
+func (m *Manager) SetDefaultClaudeArgs(args []string) {
+	m.defaultClaudeArgs = args
+}
+
+// Environment returns the KAMUI_* environment variables describing a session,
+// followed by the session's custom variables in name order
+// These are what the Claude Code status line script and session-aware tools read
+func Environment(session *types.Session) []string {
+	env := []string{
+		fmt.Sprintf("KAMUI_SESSION_ID=%s", session.SessionID),
+		fmt.Sprintf("KAMUI_CLAUDE_SESSION_ID=%s", session.Claude.SessionID),
+		fmt.Sprintf("KAMUI_PROJECT_NAME=%s", session.Project.Name),
+		fmt.Sprintf("KAMUI_PROJECT_PATH=%s", session.Project.Path),
+		fmt.Sprintf("KAMUI_STATUS_LINE=%s", StatusLine(session)),
+		"KAMUI_ACTIVE=1",
+		fmt.Sprintf("KAMUI_SESSION_SHORT=%s", ShortClaudeSessionID(session)),
+	}
+	return append(env, CustomEnvironment(session)...)
+}
+
+// CustomEnvironment returns only the session's custom variables in name order, without the
+// KAMUI_* markers that tell tools they run inside a Kamui-launched Claude
+func CustomEnvironment(session *types.Session) []string {
+	names := make([]string, 0, len(session.Metadata.Env))
+	for name := range session.Metadata.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	env := make([]string, 0, len(names))
+	for _, name := range names {
+		env = append(env, fmt.Sprintf("%s=%s", name, session.Metadata.Env[name]))
+	}
+	return env
+}
+
+// ValidateEnvName checks that name can be used as a custom session variable
+func ValidateEnvName(name string) error {
+	if !envNamePattern.MatchString(name) {
+		return types.NewSessionError(
+			types.ErrCodeInvalidInput,
+			fmt.Sprintf("invalid environment variable name '%s'", name),
+			nil,
+		)
+	}
+	if strings.HasPrefix(name, "KAMUI_") {
+		return types.NewSessionError(
+			types.ErrCodeInvalidInput,
+			fmt.Sprintf("'%s' is reserved: KAMUI_* variables are managed by Kamui", name),
+			nil,
+		)
+	}
+	return nil
+}
+
+// FormatExports renders NAME=value pairs as statements for the given shell (sh, bash, zsh or fish)
+func FormatExports(env []string, shell string) (string, error) {
+	var b strings.Builder
+
+	for _, entry := range env {
+		name, value, _ := strings.Cut(entry, "=")
+		switch shell {
+		case "", "sh", "bash", "zsh":
+			fmt.Fprintf(&b, "export %s=%s\n", name, shellQuote(value))
+		case "fish":
+			fmt.Fprintf(&b, "set -gx %s %s;\n", name, fishQuote(value))
+		default:
+			return "", types.NewSessionError(
+				types.ErrCodeInvalidInput,
+				fmt.Sprintf("unsupported shell '%s' (use sh, bash, zsh or fish)", shell),
+				nil,
+			)
+		}
+	}
+
+	return b.String(), nil
+}
+
+// shellQuote single-quotes a value for POSIX shells
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// fishQuote single-quotes a value for fish, which escapes quotes and backslashes
+func fishQuote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
+}
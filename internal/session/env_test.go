@@ -0,0 +1,147 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestShortClaudeSessionID(t *testing.T) {
+	session := &types.Session{}
+	assert.Empty(t, ShortClaudeSessionID(session))
+
+	session.Claude.SessionID = "abc"
+	assert.Equal(t, "abc", ShortClaudeSessionID(session))
+
+	session.Claude.SessionID = "0123456789abcdef"
+	assert.Equal(t, "01234567...", ShortClaudeSessionID(session))
+}
+
+func TestClaudeArgs(t *testing.T) {
+	session := &types.Session{SessionID: "api"}
+	assert.Empty(t, ClaudeArgs(session, nil))
+
+	session.Metadata.ReadOnly = true
+	assert.Equal(t, []string{"--permission-mode", "plan"}, ClaudeArgs(session, nil))
+
+	session.Metadata.ClaudeArgs = []string{"--add-dir", "../shared"}
+	assert.Equal(t, []string{"--verbose", "--add-dir", "../shared", "--permission-mode", "plan"},
+		ClaudeArgs(session, []string{"--verbose"}))
+}
+
+func TestPermissionMode(t *testing.T) {
+	session := &types.Session{SessionID: "api"}
+	assert.Empty(t, PermissionMode(session))
+
+	session.Claude.PermissionMode = BypassPermissionMode
+	assert.Equal(t, BypassPermissionMode, PermissionMode(session))
+	assert.Equal(t, []string{"--permission-mode", BypassPermissionMode}, ClaudeArgs(session, nil))
+
+	// Read-only wins over the mode the session was created with
+	session.Metadata.ReadOnly = true
+	assert.Equal(t, ReadOnlyPermissionMode, PermissionMode(session))
+}
+
+func TestParsePermissionMode(t *testing.T) {
+	for value, want := range map[string]string{
+		"":                  "",
+		"plan":              "plan",
+		"acceptEdits":       "acceptEdits",
+		"default":           "default",
+		"bypass":            BypassPermissionMode,
+		"bypassPermissions": BypassPermissionMode,
+	} {
+		mode, err := ParsePermissionMode(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, want, mode, value)
+	}
+
+	_, err := ParsePermissionMode("yolo")
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeInvalidInput, agxErr.Code)
+}
+
+func TestClaudeCommand(t *testing.T) {
+	session := &types.Session{SessionID: "api"}
+	assert.Equal(t, "claude", ClaudeCommand(session, nil))
+
+	session.Claude.SessionID = "c-1"
+	session.Metadata.ClaudeArgs = []string{"--add-dir", "../my shared"}
+	assert.Equal(t, "claude --resume c-1 --model opus --add-dir '../my shared'", ClaudeCommand(session, []string{"--model", "opus"}))
+
+	// a pre-assigned conversation Claude hasn't written yet is started under its ID
+	session.Claude.Pending = true
+	assert.Equal(t, "claude --session-id c-1 --add-dir '../my shared'", ClaudeCommand(session, nil))
+}
+
+func TestValidateClaudeArgs(t *testing.T) {
+	assert.NoError(t, ValidateClaudeArgs([]string{"--add-dir", "../shared"}))
+	assert.NoError(t, ValidateClaudeArgs(nil))
+
+	for _, args := range [][]string{{"--resume", "x"}, {"--session-id=x"}, {"-p", "hi"}, {"--permission-mode", "plan"}} {
+		var agxErr *types.AGXError
+		require.ErrorAs(t, ValidateClaudeArgs(args), &agxErr, "%v", args)
+		assert.Equal(t, types.ErrCodeInvalidInput, agxErr.Code)
+	}
+}
+
+func TestEnvironment(t *testing.T) {
+	session := &types.Session{SessionID: "api"}
+	session.Claude.SessionID = "0123456789abcdef"
+	session.Project.Name = "kamui"
+	session.Project.Path = "/src/kamui"
+
+	env := Environment(session)
+
+	assert.Contains(t, env, "KAMUI_SESSION_ID=api")
+	assert.Contains(t, env, "KAMUI_CLAUDE_SESSION_ID=0123456789abcdef")
+	assert.Contains(t, env, "KAMUI_PROJECT_NAME=kamui")
+	assert.Contains(t, env, "KAMUI_PROJECT_PATH=/src/kamui")
+	assert.Contains(t, env, "KAMUI_STATUS_LINE=Kamui: api | 01234567... | kamui")
+	assert.Contains(t, env, "KAMUI_ACTIVE=1")
+	assert.Contains(t, env, "KAMUI_SESSION_SHORT=01234567...")
+}
+
+func TestEnvironment_CustomVariables(t *testing.T) {
+	session := &types.Session{SessionID: "api"}
+	session.Metadata.Env = map[string]string{
+		"ZED":      "last",
+		"API_BASE": "http://localhost:8080",
+	}
+
+	env := Environment(session)
+
+	require.Len(t, env, 9)
+	assert.Equal(t, "API_BASE=http://localhost:8080", env[7])
+	assert.Equal(t, "ZED=last", env[8])
+
+	assert.Equal(t, []string{"API_BASE=http://localhost:8080", "ZED=last"}, CustomEnvironment(session))
+	assert.Empty(t, CustomEnvironment(&types.Session{SessionID: "plain"}))
+}
+
+func TestValidateEnvName(t *testing.T) {
+	require.NoError(t, ValidateEnvName("API_BASE"))
+	require.NoError(t, ValidateEnvName("_private"))
+	require.Error(t, ValidateEnvName("1BAD"))
+	require.Error(t, ValidateEnvName("WITH-DASH"))
+	require.Error(t, ValidateEnvName("KAMUI_SESSION_ID"))
+}
+
+func TestFormatExports(t *testing.T) {
+	env := []string{"PLAIN=value", "QUOTED=it's here", "EQUALS=a=b"}
+
+	posix, err := FormatExports(env, "bash")
+	require.NoError(t, err)
+	assert.Equal(t, "export PLAIN='value'\nexport QUOTED='it'\\''s here'\nexport EQUALS='a=b'\n", posix)
+
+	fish, err := FormatExports(env, "fish")
+	require.NoError(t, err)
+	assert.Equal(t, "set -gx PLAIN 'value';\nset -gx QUOTED 'it\\'s here';\nset -gx EQUALS 'a=b';\n", fish)
+
+	_, err = FormatExports(env, "powershell")
+	require.Error(t, err)
+}
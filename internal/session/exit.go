@@ -0,0 +1,53 @@
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Reasons recorded in the state history when a run moves a session in or out of the error state
+const (
+	reasonClaudeFailed    = "claude_failed"
+	reasonClaudeRecovered = "claude_exited_cleanly"
+)
+
+// RecordExit records how Claude exited from a run of the session in its statistics
+// A non-zero exit (-1 for a crash) moves the session to the error state, with reason describing
+// the failure kept in ResumeInfo.ResumeErrors; a clean exit moves a failed session back to active
+func (m *Manager) RecordExit(ctx context.Context, sessionName string, exitCode int, reason string) error {
+	session, err := m.loadSession(ctx, sessionName)
+	if err != nil {
+		return err
+	}
+
+	now := m.clock.Now()
+	session.Stats.LastExitCode = exitCode
+	session.Stats.LastExitAt = &now
+
+	if exitCode == 0 {
+		session.Stats.ConsecutiveFailures = 0
+		if session.Lifecycle.State == types.SessionStateError {
+			if err := session.ApplyTransition(types.SessionStateActive, reasonClaudeRecovered, now); err != nil {
+				return err
+			}
+		}
+	} else {
+		session.Stats.ConsecutiveFailures++
+		if reason == "" {
+			reason = fmt.Sprintf("Claude exited with status %d", exitCode)
+		}
+		if session.Stats.ConsecutiveFailures > 1 {
+			reason = fmt.Sprintf("%s (%d runs in a row)", reason, session.Stats.ConsecutiveFailures)
+		}
+		resume := &session.Claude.ResumeInfo
+		resume.ResumeErrors = lastN(append(resume.ResumeErrors, reason), maxResumeHistory)
+		if err := session.ApplyTransition(types.SessionStateError, fmt.Sprintf("%s: %s", reasonClaudeFailed, reason), now); err != nil {
+			return err
+		}
+	}
+
+	session.LastModified = now
+	return m.saveSession(ctx, session)
+}
@@ -0,0 +1,51 @@
+package session
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestRecordExit(t *testing.T) {
+	manager, testStorage := newNamesTestManager(t)
+	ctx := context.Background()
+
+	session, err := manager.newSession("api")
+	require.NoError(t, err)
+	require.NoError(t, manager.saveSession(ctx, session))
+
+	// a clean exit is only recorded
+	require.NoError(t, manager.RecordExit(ctx, "api", 0, ""))
+	saved, err := testStorage.LoadSession(ctx, "api")
+	require.NoError(t, err)
+	assert.Equal(t, types.SessionStateActive, saved.Lifecycle.State)
+	require.NotNil(t, saved.Stats.LastExitAt)
+	assert.Len(t, saved.Lifecycle.StateHistory, 1)
+
+	// failures move the session to the error state and are kept with the resume errors
+	require.NoError(t, manager.RecordExit(ctx, "api", 1, ""))
+	require.NoError(t, manager.RecordExit(ctx, "api", -1, "Claude crashed (signal: killed)"))
+	saved, err = testStorage.LoadSession(ctx, "api")
+	require.NoError(t, err)
+	assert.Equal(t, types.SessionStateError, saved.Lifecycle.State)
+	assert.Equal(t, -1, saved.Stats.LastExitCode)
+	assert.Equal(t, 2, saved.Stats.ConsecutiveFailures)
+	assert.Equal(t, []string{
+		"Claude exited with status 1",
+		"Claude crashed (signal: killed) (2 runs in a row)",
+	}, saved.Claude.ResumeInfo.ResumeErrors)
+	last := saved.Lifecycle.StateHistory[len(saved.Lifecycle.StateHistory)-1]
+	assert.Equal(t, "claude_failed: Claude crashed (signal: killed) (2 runs in a row)", last.Reason)
+
+	// the next clean exit recovers it
+	require.NoError(t, manager.RecordExit(ctx, "api", 0, ""))
+	saved, err = testStorage.LoadSession(ctx, "api")
+	require.NoError(t, err)
+	assert.Equal(t, types.SessionStateActive, saved.Lifecycle.State)
+	assert.Zero(t, saved.Stats.ConsecutiveFailures)
+	assert.Equal(t, reasonClaudeRecovered, saved.Lifecycle.StateHistory[len(saved.Lifecycle.StateHistory)-1].Reason)
+}
@@ -0,0 +1,148 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bitomule/kamui/internal/transcript"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// DefaultCompactThreshold is the number of state history entries kept before compaction
+const DefaultCompactThreshold = 100
+
+// minCompactThreshold keeps room for the preserved entries plus a summary and recent history
+const minCompactThreshold = 4
+
+// compactedReasonPrefix marks summary records produced by CompactHistory
+const compactedReasonPrefix = "compacted"
+
+// ParseCompactThreshold parses storage.compactThreshold, a maximum number of history entries
+// An empty value selects DefaultCompactThreshold, as do sizes such as "100MB" left from when the
+// option was a file size
+func ParseCompactThreshold(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || isLegacySize(value) {
+		return DefaultCompactThreshold, nil
+	}
+
+	threshold, err := strconv.Atoi(value)
+	if err != nil || threshold < minCompactThreshold {
+		return 0, types.NewConfigError(
+			types.ErrCodeConfigInvalid,
+			fmt.Sprintf("invalid compact threshold %q: expected a number of history entries of at least %d", value, minCompactThreshold),
+			err,
+		)
+	}
+	return threshold, nil
+}
+
+// isLegacySize reports whether value is a size with a unit, which storage.compactThreshold was
+// before it counted history entries
+func isLegacySize(value string) bool {
+	if _, err := strconv.Atoi(value); err == nil {
+		return false
+	}
+	_, err := transcript.ParseSize(value)
+	return err == nil
+}
+
+// SetCompactThreshold sets the state history length above which sessions are compacted on save
+func (m *Manager) SetCompactThreshold(threshold int) {
+	m.compactThreshold = threshold
+}
+
+// saveSession compacts the session's state history if needed, saves it and indexes it
+func (m *Manager) saveSession(ctx context.Context, session *types.Session) error {
+	threshold := m.compactThreshold
+	if threshold == 0 {
+		threshold = DefaultCompactThreshold
+	}
+	session.Lifecycle.StateHistory = CompactHistory(session.Lifecycle.StateHistory, threshold)
+	if err := m.storage.SaveSession(ctx, session); err != nil {
+		return err
+	}
+	m.indexSession(session)
+	return nil
+}
+
+// CompactHistory collapses older state changes into a single summary record once history exceeds threshold
+// The creation entry and the first activation are always preserved, as are the most recent threshold/2 entries
+func CompactHistory(history []types.StateChange, threshold int) []types.StateChange {
+	if threshold < minCompactThreshold || len(history) <= threshold {
+		return history
+	}
+
+	keepRecent := threshold / 2
+	cutoff := len(history) - keepRecent
+
+	firstActive := -1
+	for i, change := range history {
+		if change.State == types.SessionStateActive {
+			firstActive = i
+			break
+		}
+	}
+
+	var preserved, collapsed []types.StateChange
+	for i, change := range history[:cutoff] {
+		if i == 0 || i == firstActive {
+			preserved = append(preserved, change)
+		} else {
+			collapsed = append(collapsed, change)
+		}
+	}
+	if len(collapsed) == 0 {
+		return history
+	}
+
+	compacted := make([]types.StateChange, 0, len(preserved)+1+keepRecent)
+	compacted = append(compacted, preserved...)
+	compacted = append(compacted, summarizeHistory(collapsed))
+	compacted = append(compacted, history[cutoff:]...)
+	return compacted
+}
+
+// summarizeHistory builds the summary record for collapsed entries, folding in earlier summaries
+func summarizeHistory(collapsed []types.StateChange) types.StateChange {
+	count := 0
+	since := collapsed[0].Timestamp
+	for _, change := range collapsed {
+		if n, from, ok := parseSummary(change.Reason); ok {
+			count += n
+			if from.Before(since) {
+				since = from
+			}
+			continue
+		}
+		count++
+	}
+
+	last := collapsed[len(collapsed)-1]
+	return types.StateChange{
+		State:     last.State,
+		Timestamp: last.Timestamp,
+		Reason:    fmt.Sprintf("%s %d entries since %s", compactedReasonPrefix, count, since.UTC().Format(time.RFC3339)),
+	}
+}
+
+// parseSummary extracts the entry count and start time from a summary record's reason
+func parseSummary(reason string) (int, time.Time, bool) {
+	if !strings.HasPrefix(reason, compactedReasonPrefix+" ") {
+		return 0, time.Time{}, false
+	}
+
+	var count int
+	var since string
+	if _, err := fmt.Sscanf(reason, compactedReasonPrefix+" %d entries since %s", &count, &since); err != nil {
+		return 0, time.Time{}, false
+	}
+	from, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	return count, from, true
+}
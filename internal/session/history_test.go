@@ -0,0 +1,112 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func buildHistory(n int, start time.Time) []types.StateChange {
+	history := make([]types.StateChange, 0, n)
+	for i := 0; i < n; i++ {
+		state := types.SessionStateActive
+		if i%2 == 1 {
+			state = types.SessionStatePaused
+		}
+		history = append(history, types.StateChange{
+			State:     state,
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+			Reason:    fmt.Sprintf("change-%d", i),
+		})
+	}
+	return history
+}
+
+func TestParseCompactThreshold(t *testing.T) {
+	threshold, err := ParseCompactThreshold("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultCompactThreshold, threshold)
+
+	threshold, err = ParseCompactThreshold("50")
+	require.NoError(t, err)
+	assert.Equal(t, 50, threshold)
+
+	threshold, err = ParseCompactThreshold("100MB")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultCompactThreshold, threshold, "sizes from the old format select the default")
+
+	for _, invalid := range []string{"lots", "2", "-1"} {
+		_, err = ParseCompactThreshold(invalid)
+		var agxErr *types.AGXError
+		require.ErrorAs(t, err, &agxErr, invalid)
+		assert.Equal(t, types.ErrCodeConfigInvalid, agxErr.Code)
+	}
+}
+
+func TestCompactHistory_UnderThreshold(t *testing.T) {
+	history := buildHistory(10, time.Now())
+
+	assert.Equal(t, history, CompactHistory(history, 10))
+}
+
+func TestCompactHistory(t *testing.T) {
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	history := buildHistory(30, start)
+
+	compacted := CompactHistory(history, 10)
+
+	// created entry, summary, then the 5 most recent entries
+	require.Len(t, compacted, 7)
+	assert.Equal(t, history[0], compacted[0])
+	assert.Equal(t, "compacted 24 entries since 2025-01-01T10:00:00Z", compacted[1].Reason)
+	assert.Equal(t, history[24].Timestamp, compacted[1].Timestamp)
+	assert.Equal(t, history[25:], compacted[2:])
+}
+
+func TestCompactHistory_PreservesFirstActivation(t *testing.T) {
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	history := buildHistory(30, start)
+	history[0].State = types.SessionStatePaused
+	history[2].State = types.SessionStatePaused
+	history[3].State = types.SessionStateActive
+
+	compacted := CompactHistory(history, 10)
+
+	assert.Equal(t, history[0], compacted[0])
+	assert.Equal(t, history[3], compacted[1])
+	assert.Contains(t, compacted[2].Reason, "compacted 23 entries")
+}
+
+func TestCompactHistory_MergesEarlierSummaries(t *testing.T) {
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	history := CompactHistory(buildHistory(30, start), 10)
+	history = append(history, buildHistory(10, start.Add(100*time.Hour))...)
+
+	compacted := CompactHistory(history, 10)
+
+	require.Len(t, compacted, 7)
+	assert.Equal(t, "compacted 34 entries since 2025-01-01T10:00:00Z", compacted[1].Reason)
+}
+
+func TestCompleteSession_CompactsHistory(t *testing.T) {
+	manager, testStorage := newNamesTestManager(t)
+	manager.SetCompactThreshold(4)
+
+	session, err := testStorage.CreateSession("long-lived", manager.GetProjectPath())
+	require.NoError(t, err)
+	session.Lifecycle.StateHistory = buildHistory(12, time.Now().Add(-24*time.Hour))
+	require.NoError(t, testStorage.SaveSession(context.Background(), session))
+
+	require.NoError(t, manager.CompleteSession(context.Background(), "long-lived"))
+
+	stored, err := manager.GetSession(context.Background(), "long-lived")
+	require.NoError(t, err)
+	assert.Len(t, stored.Lifecycle.StateHistory, 4)
+	assert.Equal(t, types.SessionStateCompleted, stored.Lifecycle.StateHistory[3].State)
+}
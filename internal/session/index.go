@@ -0,0 +1,99 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/bitomule/kamui/internal/index"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// SetIndex sets the global index kept up to date as sessions are saved and deleted, and how
+// long ListIndexed trusts it before syncing it with storage; without one, nothing is indexed
+func (m *Manager) SetIndex(store *index.Store, syncInterval time.Duration) {
+	m.index = store
+	m.indexSyncInterval = syncInterval
+}
+
+// indexSession records a saved session in the global index
+// Index errors don't fail the save: the entry is repaired by the next sync
+func (m *Manager) indexSession(session *types.Session) {
+	if m.index == nil {
+		return
+	}
+	_ = m.index.Put(session, index.SessionFile(m.storage, session.SessionID))
+}
+
+// unindexSession drops a deleted session from the global index, like indexSession
+func (m *Manager) unindexSession(sessionID string) {
+	if m.index == nil {
+		return
+	}
+	_ = m.index.Remove(sessionID)
+}
+
+// SyncIndex rebuilds the global index from storage, adding missing sessions, refreshing outdated
+// entries and removing stale ones
+func (m *Manager) SyncIndex(ctx context.Context) (*index.SyncResult, error) {
+	if m.index == nil {
+		return nil, types.NewSessionError(types.ErrCodeInvalidInput, "no global session index is kept (it is disabled, or sessions aren't stored on this machine)", nil)
+	}
+	_, result, err := m.index.Sync(ctx, m.storage, m.indexSyncInterval, m.clock.Now())
+	return result, err
+}
+
+// ListIndexed returns summaries of every project's sessions matching opts from the global index,
+// syncing it first when it is due or stale
+// Indexed summaries leave out what the index doesn't record: packages, links, permission modes
+// and the pinned, protected and read-only flags. Without an index every session is read instead.
+func (m *Manager) ListIndexed(ctx context.Context, opts ListOptions) ([]Summary, error) {
+	if m.index == nil {
+		opts.AllProjects = true
+		return m.ListSummaries(ctx, opts)
+	}
+	sortKey, filter, locks, err := m.prepareListing(opts)
+	if err != nil {
+		return nil, err
+	}
+	now := m.clock.Now()
+
+	global, err := m.index.Load()
+	if err == nil {
+		// the configured interval applies even before a sync records it
+		global.SyncInterval = types.Duration(m.indexSyncInterval)
+	}
+	if err != nil || global.NeedsSync(now) || global.IsStale(now) {
+		if global, _, err = m.index.Sync(ctx, m.storage, m.indexSyncInterval, now); err != nil {
+			return nil, err
+		}
+	}
+
+	var summaries []Summary
+	for _, entry := range global.Sessions {
+		summary := indexedSummary(entry)
+		if lock, ok := locks[summary.Name]; ok {
+			summary.Lock = &lock
+		}
+		if opts.Matches(summary) && (filter == nil || filter.Match(summary, now)) {
+			summaries = append(summaries, summary)
+		}
+	}
+
+	SortSummaries(summaries, sortKey, opts.Reverse, opts.PinnedFirst)
+	return summaries, nil
+}
+
+// indexedSummary summarizes an index entry
+func indexedSummary(entry types.IndexedSession) Summary {
+	return Summary{
+		Name:             entry.SessionID,
+		Description:      entry.Metadata.Description,
+		Tags:             entry.Metadata.Tags,
+		State:            entry.Status.State,
+		ProjectPath:      entry.ProjectPath,
+		Created:          entry.Metadata.Created,
+		LastAccessed:     entry.Status.LastAccessed,
+		ClaudeSessionID:  entry.Runtime.ClaudeSessionID,
+		HasActiveContext: entry.Runtime.ClaudeActive,
+	}
+}
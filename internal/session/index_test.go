@@ -0,0 +1,93 @@
+package session
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/index"
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestIndex_FollowsSavesAndDeletes(t *testing.T) {
+	manager, testStorage := newNamesTestManager(t)
+	ctx := context.Background()
+	store := index.NewStoreWithPath(filepath.Join(t.TempDir(), "kamui-index.json"))
+	manager.SetIndex(store, time.Hour)
+
+	other, err := NewWithDependencies(t.TempDir(), testStorage, &MockClaudeClient{})
+	require.NoError(t, err)
+	other.SetIndex(store, time.Hour)
+
+	_, err = manager.CreateSession(ctx, "api-fix", "Fix the API", []string{"auth"})
+	require.NoError(t, err)
+	_, err = other.CreateSession(ctx, "web-fix", "", nil)
+	require.NoError(t, err)
+	_, err = manager.RenameSession(ctx, "api-fix", "api-login")
+	require.NoError(t, err)
+
+	global, err := store.Load()
+	require.NoError(t, err)
+	require.Len(t, global.Sessions, 2)
+
+	// the first listing syncs the index, which computes its statistics
+	summaries, err := manager.ListIndexed(ctx, ListOptions{Sort: SortName})
+	require.NoError(t, err)
+	global, err = store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, 2, global.Statistics.TotalProjects)
+	assert.Equal(t, []string{"api-login", "web-fix"}, summaryNames(summaries), "every project is listed")
+	assert.Equal(t, "Fix the API", summaries[0].Description)
+	assert.Equal(t, []string{"auth"}, summaries[0].Tags)
+
+	summaries, err = manager.ListIndexed(ctx, ListOptions{Filter: "tag=auth"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api-login"}, summaryNames(summaries))
+
+	require.NoError(t, other.DeleteSession(ctx, "web-fix", false))
+	summaries, err = manager.ListIndexed(ctx, ListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api-login"}, summaryNames(summaries))
+}
+
+func TestSyncIndex_RepairsStaleEntries(t *testing.T) {
+	manager, testStorage := newNamesTestManager(t)
+	ctx := context.Background()
+
+	var agxErr *types.AGXError
+	_, err := manager.SyncIndex(ctx)
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeInvalidInput, agxErr.Code)
+
+	_, err = manager.CreateSession(ctx, "unindexed", "", nil)
+	require.NoError(t, err)
+	summaries, err := manager.ListIndexed(ctx, ListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"unindexed"}, summaryNames(summaries), "without an index every session is read")
+
+	store := index.NewStoreWithPath(filepath.Join(t.TempDir(), "kamui-index.json"))
+	manager.SetIndex(store, time.Hour)
+	_, err = manager.CreateSession(ctx, "indexed", "", nil)
+	require.NoError(t, err)
+	require.NoError(t, store.Put(storage.NewSession("gone", "/work/old", time.Now()), ""))
+
+	result, err := manager.SyncIndex(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"unindexed"}, result.Added)
+	assert.Equal(t, []string{"gone"}, result.Removed)
+
+	require.NoError(t, testStorage.DeleteSession(ctx, "unindexed"))
+	summaries, err = manager.ListIndexed(ctx, ListOptions{Sort: SortName})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"indexed", "unindexed"}, summaryNames(summaries), "the index is trusted until it is due a sync")
+
+	manager.SetIndex(store, 0)
+	summaries, err = manager.ListIndexed(ctx, ListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"indexed"}, summaryNames(summaries))
+}
@@ -0,0 +1,296 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bitomule/kamui/internal/paths"
+	"github.com/bitomule/kamui/internal/query"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// SortKey orders the sessions a listing returns
+type SortKey string
+
+const (
+	// SortLastAccessed lists the most recently used sessions first
+	SortLastAccessed SortKey = "last-accessed"
+	// SortCreated lists the newest sessions first
+	SortCreated SortKey = "created"
+	// SortName lists sessions alphabetically
+	SortName SortKey = "name"
+)
+
+// SortKeys lists the supported sort keys
+var SortKeys = []SortKey{SortLastAccessed, SortCreated, SortName}
+
+// ParseSortKey validates a sort key; an empty one is SortLastAccessed
+func ParseSortKey(key string) (SortKey, error) {
+	if key == "" {
+		return SortLastAccessed, nil
+	}
+	for _, known := range SortKeys {
+		if strings.EqualFold(key, string(known)) {
+			return known, nil
+		}
+	}
+	names := make([]string, 0, len(SortKeys))
+	for _, known := range SortKeys {
+		names = append(names, string(known))
+	}
+	return "", types.NewSessionError(
+		types.ErrCodeInvalidInput,
+		fmt.Sprintf("invalid sort key '%s' (use %s)", key, strings.Join(names, ", ")),
+		nil,
+	)
+}
+
+// Binding filters sessions by whether they are bound to a Claude conversation
+type Binding int
+
+const (
+	// BindingAny keeps every session
+	BindingAny Binding = iota
+	// BindingBound keeps sessions bound to a Claude conversation, pending ones included
+	BindingBound
+	// BindingUnbound keeps sessions without a Claude conversation yet
+	BindingUnbound
+)
+
+// ListOptions selects and orders the sessions ListSummaries returns
+type ListOptions struct {
+	// Sort is the order sessions are returned in (defaults to SortLastAccessed)
+	Sort SortKey
+	// Reverse flips the order
+	Reverse bool
+	// PinnedFirst puts pinned sessions ahead of the others, each group in Sort order
+	PinnedFirst bool
+
+	// AllProjects lists every project's sessions instead of the manager's project only
+	AllProjects bool
+	// States keeps sessions in any of these states; empty keeps every state
+	States []types.SessionState
+	// Tags keeps sessions carrying every one of these tags
+	Tags []string
+	// Binding keeps sessions by whether they have a Claude conversation
+	Binding Binding
+	// Filter is a query expression over SummaryFields, e.g. "state=active && lastAccessed>7d"
+	Filter string
+}
+
+// Summary is the part of a session listings show, without its history, notes and settings
+type Summary struct {
+	Name             string
+	Description      string
+	Tags             []string
+	State            types.SessionState
+	ProjectPath      string
+	Package          string
+	WorkingDirectory string
+	Created          time.Time
+	LastAccessed     time.Time
+	ClaudeSessionID  string
+	HasActiveContext bool
+	PermissionMode   string
+	Links            []types.Link
+	Pinned           bool
+	Protected        bool
+	ReadOnly         bool
+	// Lock is the session's manual lock on this machine, nil when it has none
+	Lock *types.ManualLock
+}
+
+// NewSummary summarizes a session
+func NewSummary(session *types.Session) Summary {
+	return Summary{
+		Name:             session.SessionID,
+		Description:      session.Metadata.Description,
+		Tags:             session.Metadata.Tags,
+		State:            session.Lifecycle.State,
+		ProjectPath:      session.Project.Path,
+		Package:          session.Project.Package,
+		WorkingDirectory: session.Project.WorkingDirectory,
+		Created:          session.Created,
+		LastAccessed:     session.LastAccessed,
+		ClaudeSessionID:  session.Claude.SessionID,
+		HasActiveContext: session.Claude.HasActiveContext,
+		PermissionMode:   session.Claude.PermissionMode,
+		Links:            session.Metadata.Links,
+		Pinned:           session.Metadata.Pinned,
+		Protected:        session.Metadata.Protected,
+		ReadOnly:         session.Metadata.ReadOnly,
+	}
+}
+
+// SummaryFields are the fields filter expressions can use
+var SummaryFields = map[string]query.Kind{
+	"name":         query.String,
+	"description":  query.String,
+	"state":        query.String,
+	"tag":          query.List,
+	"project":      query.String,
+	"package":      query.String,
+	"directory":    query.String,
+	"claude":       query.String,
+	"created":      query.Time,
+	"lastAccessed": query.Time,
+	"bound":        query.Bool,
+	"pinned":       query.Bool,
+	"protected":    query.Bool,
+	"readOnly":     query.Bool,
+	"locked":       query.Bool,
+}
+
+// Field returns a field of SummaryFields, for evaluating filter expressions
+func (s Summary) Field(name string) query.Value {
+	switch name {
+	case "name":
+		return query.Value{Str: s.Name}
+	case "description":
+		return query.Value{Str: s.Description}
+	case "state":
+		return query.Value{Str: string(s.State)}
+	case "tag":
+		return query.Value{List: s.Tags}
+	case "project":
+		return query.Value{Str: s.ProjectPath}
+	case "package":
+		return query.Value{Str: s.Package}
+	case "directory":
+		return query.Value{Str: s.WorkingDirectory}
+	case "claude":
+		return query.Value{Str: s.ClaudeSessionID}
+	case "created":
+		return query.Value{Time: s.Created}
+	case "lastAccessed":
+		return query.Value{Time: s.LastAccessed}
+	case "bound":
+		return query.Value{Bool: s.ClaudeSessionID != ""}
+	case "pinned":
+		return query.Value{Bool: s.Pinned}
+	case "protected":
+		return query.Value{Bool: s.Protected}
+	case "readOnly":
+		return query.Value{Bool: s.ReadOnly}
+	case "locked":
+		return query.Value{Bool: s.Lock != nil}
+	}
+	return query.Value{}
+}
+
+// ParseFilter parses a filter expression over SummaryFields
+func ParseFilter(filter string) (*query.Expr, error) {
+	expr, err := query.Parse(filter, SummaryFields)
+	if err != nil {
+		return nil, types.NewSessionError(types.ErrCodeInvalidInput, err.Error(), nil)
+	}
+	return expr, nil
+}
+
+// Matches reports whether a summary passes the state, tag and binding filters
+func (o ListOptions) Matches(summary Summary) bool {
+	if len(o.States) > 0 && !slices.Contains(o.States, summary.State) {
+		return false
+	}
+	for _, tag := range o.Tags {
+		if !slices.Contains(summary.Tags, tag) {
+			return false
+		}
+	}
+	switch o.Binding {
+	case BindingBound:
+		return summary.ClaudeSessionID != ""
+	case BindingUnbound:
+		return summary.ClaudeSessionID == ""
+	}
+	return true
+}
+
+// ListSummaries returns summaries of the sessions matching opts, in the order it asks for
+// Unreadable sessions are skipped, as in ListProjectSessions
+func (m *Manager) ListSummaries(ctx context.Context, opts ListOptions) ([]Summary, error) {
+	sortKey, filter, locks, err := m.prepareListing(opts)
+	if err != nil {
+		return nil, err
+	}
+	now := m.clock.Now()
+
+	names, err := m.storage.ListSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var summaries []Summary
+	for _, name := range names {
+		session, err := m.storage.LoadSession(ctx, name)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			continue
+		}
+		summary := NewSummary(session)
+		if lock, ok := locks[summary.Name]; ok {
+			summary.Lock = &lock
+		}
+		if !opts.AllProjects && !paths.Equal(summary.ProjectPath, m.projectPath) {
+			continue
+		}
+		if opts.Matches(summary) && (filter == nil || filter.Match(summary, now)) {
+			summaries = append(summaries, summary)
+		}
+	}
+
+	SortSummaries(summaries, sortKey, opts.Reverse, opts.PinnedFirst)
+	return summaries, nil
+}
+
+// prepareListing parses the sort key and filter of opts and reads this machine's manual locks
+func (m *Manager) prepareListing(opts ListOptions) (SortKey, *query.Expr, map[string]types.ManualLock, error) {
+	sortKey, err := ParseSortKey(string(opts.Sort))
+	if err != nil {
+		return "", nil, nil, err
+	}
+	var filter *query.Expr
+	if opts.Filter != "" {
+		if filter, err = ParseFilter(opts.Filter); err != nil {
+			return "", nil, nil, err
+		}
+	}
+	locks := map[string]types.ManualLock{}
+	if m.manualLocks != nil {
+		if locks, err = m.manualLocks.All(); err != nil {
+			return "", nil, nil, err
+		}
+	}
+	return sortKey, filter, locks, nil
+}
+
+// SortSummaries orders summaries by key, optionally reversed and with pinned sessions first
+// Ties are broken by name so the order is stable across calls
+func SortSummaries(summaries []Summary, key SortKey, reverse, pinnedFirst bool) {
+	sort.SliceStable(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		if pinnedFirst && a.Pinned != b.Pinned {
+			return a.Pinned
+		}
+		if reverse {
+			a, b = b, a
+		}
+		switch key {
+		case SortCreated:
+			if !a.Created.Equal(b.Created) {
+				return a.Created.After(b.Created)
+			}
+		case SortLastAccessed, "":
+			if !a.LastAccessed.Equal(b.LastAccessed) {
+				return a.LastAccessed.After(b.LastAccessed)
+			}
+		}
+		return a.Name < b.Name
+	})
+}
@@ -0,0 +1,104 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestListSummaries(t *testing.T) {
+	manager, testStorage := newNamesTestManager(t)
+	ctx := context.Background()
+
+	base := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	for i, name := range []string{"bravo", "alpha", "delta", "charlie"} {
+		session, err := manager.newSession(name)
+		require.NoError(t, err)
+		if name == "delta" {
+			session.Metadata.Tags = []string{"infra"}
+		}
+		session.Created = base.Add(time.Duration(i) * time.Hour)
+		session.LastAccessed = base.Add(-time.Duration(i) * time.Hour)
+		require.NoError(t, manager.saveSession(ctx, session))
+	}
+	require.NoError(t, manager.SetPinned(ctx, "charlie", true))
+	require.NoError(t, manager.CompleteSession(ctx, "alpha"))
+
+	elsewhere, err := testStorage.CreateSession("other-project", t.TempDir())
+	require.NoError(t, err)
+	elsewhere.Claude.SessionID = "claude-1"
+	require.NoError(t, testStorage.SaveSession(ctx, elsewhere))
+
+	summaries, err := manager.ListSummaries(ctx, ListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bravo", "alpha", "delta", "charlie"}, summaryNames(summaries))
+
+	summaries, err = manager.ListSummaries(ctx, ListOptions{Sort: SortCreated, PinnedFirst: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"charlie", "delta", "alpha", "bravo"}, summaryNames(summaries))
+
+	summaries, err = manager.ListSummaries(ctx, ListOptions{Sort: SortName, Reverse: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"delta", "charlie", "bravo", "alpha"}, summaryNames(summaries))
+
+	// filters
+	summaries, err = manager.ListSummaries(ctx, ListOptions{States: []types.SessionState{types.SessionStateCompleted}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alpha"}, summaryNames(summaries))
+
+	summaries, err = manager.ListSummaries(ctx, ListOptions{Tags: []string{"infra"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"delta"}, summaryNames(summaries))
+
+	summaries, err = manager.ListSummaries(ctx, ListOptions{Binding: BindingBound})
+	require.NoError(t, err)
+	assert.Empty(t, summaries)
+
+	summaries, err = manager.ListSummaries(ctx, ListOptions{Sort: SortName, AllProjects: true, Binding: BindingBound})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"other-project"}, summaryNames(summaries))
+
+	summaries, err = manager.ListSummaries(ctx, ListOptions{Sort: SortName, AllProjects: true, Binding: BindingUnbound})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alpha", "bravo", "charlie", "delta"}, summaryNames(summaries))
+
+	summaries, err = manager.ListSummaries(ctx, ListOptions{Filter: "state=active && (tag=infra || pinned)"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"delta", "charlie"}, summaryNames(summaries))
+
+	_, err = manager.ListSummaries(ctx, ListOptions{Filter: "size>3"})
+	var filterErr *types.AGXError
+	require.ErrorAs(t, err, &filterErr)
+	assert.Equal(t, types.ErrCodeInvalidInput, filterErr.Code)
+
+	_, err = manager.ListSummaries(ctx, ListOptions{Sort: "size"})
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeInvalidInput, agxErr.Code)
+}
+
+func TestParseSortKey(t *testing.T) {
+	key, err := ParseSortKey("")
+	require.NoError(t, err)
+	assert.Equal(t, SortLastAccessed, key)
+
+	key, err = ParseSortKey("Name")
+	require.NoError(t, err)
+	assert.Equal(t, SortName, key)
+
+	_, err = ParseSortKey("size")
+	assert.Error(t, err)
+}
+
+func summaryNames(summaries []Summary) []string {
+	names := make([]string, len(summaries))
+	for i, summary := range summaries {
+		names[i] = summary.Name
+	}
+	return names
+}
@@ -2,13 +2,24 @@
 package session
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/bitomule/kamui/internal/claude"
+	"github.com/bitomule/kamui/internal/clock"
+	"github.com/bitomule/kamui/internal/index"
+	"github.com/bitomule/kamui/internal/paths"
+	"github.com/bitomule/kamui/internal/project"
+	"github.com/bitomule/kamui/internal/schedule"
+	"github.com/bitomule/kamui/internal/snapshot"
 	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/internal/timesheet"
+	"github.com/bitomule/kamui/internal/transcript"
 	"github.com/bitomule/kamui/pkg/types"
 )
 
@@ -17,10 +28,34 @@ type Manager struct {
 	storage      storage.Interface
 	claudeClient claude.ClientInterface
 	projectPath  string
+	workspace    *project.Workspace
+	pkg          *project.Package
+	foldCase     bool
+	clock        clock.Clock
+
+	compactThreshold  int
+	defaultClaudeArgs []string
+	projectConfig     *types.ProjectConfig
+	injectContext     bool
+
+	manualLocks *ManualLocks
+	timesheet   *timesheet.Store
+
+	snapshots     *snapshot.Store
+	recordingsDir string
+	schedules     *schedule.Store
+
+	index             *index.Store
+	indexSyncInterval time.Duration
 }
 
 // New creates a new session manager for the current working directory
 func New() (*Manager, error) {
+	return NewWithDetector(project.Detector{})
+}
+
+// NewWithDetector creates a session manager for the project containing the current working directory
+func NewWithDetector(detector project.Detector) (*Manager, error) {
 	// Get current working directory
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -31,17 +66,42 @@ func New() (*Manager, error) {
 		)
 	}
 
-	return NewForPath(cwd)
+	return NewForDir(detector, cwd)
 }
 
-// NewForPath creates a new session manager for a specific project path
-func NewForPath(projectPath string) (*Manager, error) {
-	claudeClient, err := claude.New()
+// NewForDir creates a session manager for the project containing dir
+func NewForDir(detector project.Detector, dir string) (*Manager, error) {
+	// Inside a monorepo, sessions belong to the workspace root and default to the package containing
+	// dir, unless another strategy than auto is configured
+	var workspace *project.Workspace
+	var err error
+	if detector.UsesWorkspaces() {
+		workspace, err = project.DetectWorkspace(dir)
+	}
+	if err != nil || workspace == nil {
+		root, rootErr := detector.Root(dir)
+		if rootErr != nil {
+			return nil, types.NewStorageError(
+				types.ErrCodeProjectInvalid,
+				"failed to detect project root",
+				rootErr,
+			)
+		}
+		return NewForPath(root)
+	}
+
+	manager, err := NewForPath(workspace.Root)
 	if err != nil {
 		return nil, err
 	}
+	manager.SetWorkspace(workspace, dir)
+	return manager, nil
+}
 
-	return NewWithClient(projectPath, claudeClient)
+// NewForPath creates a new session manager for a specific project path
+// Without claude in PATH the manager works offline: everything but launching and running Claude
+func NewForPath(projectPath string) (*Manager, error) {
+	return NewWithClient(projectPath, claude.NewOrOffline())
 }
 
 func NewWithClient(projectPath string, claudeClient claude.ClientInterface) (*Manager, error) {
@@ -58,68 +118,89 @@ func NewWithDependencies(projectPath string, storageImpl storage.Interface, clau
 		)
 	}
 
-	absPath, err := filepath.Abs(projectPath)
-	if err != nil {
-		return nil, types.NewStorageError(
-			types.ErrCodeProjectInvalid,
-			"failed to resolve absolute path",
-			err,
-		)
-	}
-
 	return &Manager{
 		storage:      storageImpl,
 		claudeClient: claudeClient,
-		projectPath:  absPath,
+		projectPath:  paths.Canonical(projectPath),
+		clock:        clock.System,
 	}, nil
 }
 
 // CreateOrResumeSession creates a new session or resumes an existing one
 // Returns session data and whether Claude was already executed (for new sessions)
-func (m *Manager) CreateOrResumeSession(sessionName string) (*types.Session, bool, error) {
+func (m *Manager) CreateOrResumeSession(ctx context.Context, sessionName string) (*types.Session, bool, error) {
+	sessionName, err := m.resolveName(ctx, sessionName)
+	if err != nil {
+		return nil, false, err
+	}
+
 	var session *types.Session
-	var err error
 
 	// Check if session already exists in storage
-	if m.storage.SessionExists(sessionName) {
+	if m.storage.SessionExists(ctx, sessionName) {
 		// Load existing session data
-		session, err = m.storage.LoadSession(sessionName)
+		session, err = m.storage.LoadSession(ctx, sessionName)
 		if err != nil {
 			return nil, false, err
 		}
 	} else {
 		// Create new session
-		session, err = m.storage.CreateSession(sessionName, m.projectPath)
+		session, err = m.newSession(sessionName)
 		if err != nil {
 			return nil, false, err
 		}
 	}
 
-	// Check if this session has a stored Claude session to restore
-	var shouldStartFreshClaude bool
-	if session.Claude.SessionID != "" {
-		// Check if the stored Claude session still exists
-		exists, err := m.claudeClient.HasSession(session.Claude.SessionID, session.Project.WorkingDirectory)
-		if err != nil || !exists {
+	// A stored Claude conversation that can't be found is reported rather than silently replaced
+	// with a blank one, so the caller can fall back deliberately (see ApplyResumeFallback)
+	// A pre-assigned conversation Claude never wrote is started again under the same ID
+	shouldStartFreshClaude := session.Claude.SessionID == ""
+	if !shouldStartFreshClaude {
+		exists, err := m.claudeClient.HasSession(ctx, session.Claude.SessionID, session.Project.WorkingDirectory)
+		if err != nil {
+			if ctxErr := types.ContextError(ctx); ctxErr != nil {
+				return nil, false, ctxErr
+			}
+			return nil, false, types.NewClaudeError(
+				types.ErrCodeClaudeResumeFailed,
+				fmt.Sprintf("couldn't check Claude conversation %s of session '%s'", session.Claude.SessionID, sessionName),
+				err,
+			)
+		}
+		switch {
+		case exists && session.Claude.Pending:
+			session.Claude.Pending = false
+			session.Claude.HasActiveContext = true
+		case !exists && session.Claude.Pending:
 			shouldStartFreshClaude = true
-		} else {
-			shouldStartFreshClaude = false
+		case !exists:
+			return nil, false, types.NewClaudeError(
+				types.ErrCodeClaudeResumeFailed,
+				fmt.Sprintf("Claude conversation %s of session '%s' no longer exists", session.Claude.SessionID, sessionName),
+				nil,
+			).WithContext("missing", true)
 		}
-	} else {
-		shouldStartFreshClaude = true
 	}
 
 	// Set up Claude session
 	if shouldStartFreshClaude {
-		if err := m.setupClaudeSession(session, true); err != nil {
+		if err := m.setupClaudeSession(ctx, session, true); err != nil {
 			return nil, false, fmt.Errorf("failed to setup Claude session: %w", err)
 		}
+		// Ctrl-C pressed while Claude ran was meant for Claude; still record the session afterwards
+		ctx = context.WithoutCancel(ctx)
 	}
 
 	// Update access time and save
-	session.LastAccessed = time.Now()
-	session.LastModified = time.Now()
-	if err := m.storage.SaveSession(session); err != nil {
+	now := m.clock.Now()
+	session.LastAccessed = now
+	session.LastModified = now
+	if session.Lifecycle.State == types.SessionStateArchived {
+		if err := session.ApplyTransition(types.SessionStateActive, reasonReopened, now); err != nil {
+			return nil, false, err
+		}
+	}
+	if err := m.saveSession(ctx, session); err != nil {
 		return nil, false, err
 	}
 
@@ -127,38 +208,635 @@ func (m *Manager) CreateOrResumeSession(sessionName string) (*types.Session, boo
 	return session, shouldStartFreshClaude, nil
 }
 
+// CreateSession creates and saves a new session without launching Claude
+// The Claude conversation is bound the first time the session is resumed
+func (m *Manager) CreateSession(ctx context.Context, sessionName, description string, tags []string) (*types.Session, error) {
+	sessionName, err := m.resolveName(ctx, sessionName)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.storage.SessionExists(ctx, sessionName) {
+		return nil, types.NewSessionError(
+			types.ErrCodeSessionExists,
+			fmt.Sprintf("session '%s' already exists", sessionName),
+			nil,
+		)
+	}
+
+	session, err := m.newSession(sessionName)
+	if err != nil {
+		return nil, err
+	}
+
+	session.Metadata.Description = description
+	session.Metadata.Tags = tags
+
+	if err := m.saveSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// newSession builds a session for the project, scoped to the current workspace package if any
+func (m *Manager) newSession(sessionName string) (*types.Session, error) {
+	session, err := m.storage.CreateSession(sessionName, m.projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	session.Project.Name = filepath.Base(m.projectPath)
+	if m.pkg != nil {
+		session.Project.Package = m.pkg.Name
+		session.Project.WorkingDirectory = m.pkg.Path
+	}
+	if m.projectConfig != nil {
+		session.Metadata.Variant = m.projectConfig.Project.DefaultSessionVariant
+	}
+
+	return session, nil
+}
+
 // GetSession retrieves an existing session
-func (m *Manager) GetSession(sessionName string) (*types.Session, error) {
-	return m.storage.LoadSession(sessionName)
+func (m *Manager) GetSession(ctx context.Context, sessionName string) (*types.Session, error) {
+	return m.loadSession(ctx, sessionName)
 }
 
 // ListSessions returns all sessions for the current project
-func (m *Manager) ListSessions() ([]string, error) {
-	return m.storage.ListSessions()
+func (m *Manager) ListSessions(ctx context.Context) ([]string, error) {
+	return m.storage.ListSessions(ctx)
 }
 
-// CompleteSession marks a session as completed
-func (m *Manager) CompleteSession(sessionName string) error {
-	session, err := m.storage.LoadSession(sessionName)
+// reasonCompleted is recorded in the state history when a session is completed by hand
+const reasonCompleted = "manually_completed"
+
+// CompleteSession marks a session as completed now, recording its total active time from the
+// timesheet in the completion's state change and the session's statistics
+func (m *Manager) CompleteSession(ctx context.Context, sessionName string) error {
+	session, err := m.loadSession(ctx, sessionName)
 	if err != nil {
 		return err
 	}
 
-	// Update session state
-	session.Lifecycle.State = types.SessionStateCompleted
-	session.Lifecycle.StateHistory = append(session.Lifecycle.StateHistory, types.StateChange{
-		State:     types.SessionStateCompleted,
-		Timestamp: session.LastModified,
-		Reason:    "manually_completed",
-	})
+	// Update session state, recording how long the session was worked on
+	now := m.clock.Now()
+	totals, err := m.runTotals(session.SessionID)
+	if err != nil {
+		return err
+	}
+	recorded := len(session.Lifecycle.StateHistory)
+	if err := session.ApplyTransition(types.SessionStateCompleted, reasonCompleted, now); err != nil {
+		return err
+	}
+	if len(session.Lifecycle.StateHistory) == recorded {
+		return nil // already completed
+	}
+	session.Lifecycle.StateHistory[recorded].Duration = types.Duration(totals.Total)
+	totals.apply(&session.Stats)
+	session.Stats.CompletedAt = &now
 
 	// Save updated session
-	return m.storage.SaveSession(session)
+	return m.saveSession(ctx, session)
+}
+
+// DeleteSession removes a session; protected sessions are only removed when forced, and sessions
+// locked with kam lock not at all
+// Sessions whose metadata can't be read are deleted regardless, since they can't be checked
+func (m *Manager) DeleteSession(ctx context.Context, sessionName string, force bool) error {
+	resolved, err := m.resolveName(ctx, sessionName)
+	if err != nil {
+		return err
+	}
+	if err := m.checkHeld(resolved, "delete"); err != nil {
+		return err
+	}
+	// kam would save the session again when Claude exits, and purging would pull its transcript
+	// from under Claude
+	if err := m.checkNotRunning(ctx, resolved, "delete"); err != nil {
+		return err
+	}
+	if session, err := m.storage.LoadSession(ctx, resolved); err == nil {
+		if err := checkProtected(session, force, "delete"); err != nil {
+			return err
+		}
+	}
+	if err := m.storage.DeleteSession(ctx, resolved); err != nil {
+		return err
+	}
+	m.unindexSession(resolved)
+	return nil
+}
+
+// ConversationPurge reports what DeleteSessionAndConversation did with the Claude conversation
+type ConversationPurge struct {
+	// ClaudeSessionID is the conversation the session was bound to, "" if it was unbound
+	ClaudeSessionID string
+	// Deleted is whether the conversation's transcript was deleted
+	Deleted bool
+	// SharedWith lists the other sessions bound to the conversation, which keep it
+	SharedWith []string
+}
+
+// DeleteSessionAndConversation deletes a session like DeleteSession, then the Claude conversation
+// it is bound to, which can't be resumed afterwards
+// The conversation is kept when another session is bound to it
+func (m *Manager) DeleteSessionAndConversation(ctx context.Context, sessionName string, force bool) (*ConversationPurge, error) {
+	session, err := m.loadSession(ctx, sessionName)
+	if err != nil {
+		return nil, err
+	}
+
+	purge := &ConversationPurge{ClaudeSessionID: session.Claude.SessionID}
+	if purge.ClaudeSessionID != "" {
+		// an unreadable session could be bound to the conversation too, so fail before deleting anything
+		purge.SharedWith, err = m.sessionsBoundTo(ctx, purge.ClaudeSessionID, session.SessionID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := m.DeleteSession(ctx, session.SessionID, force); err != nil {
+		return nil, err
+	}
+	if purge.ClaudeSessionID == "" || len(purge.SharedWith) > 0 {
+		return purge, nil
+	}
+
+	err = m.claudeClient.TerminateSession(ctx, purge.ClaudeSessionID, session.Project.WorkingDirectory)
+	var agxErr *types.AGXError
+	if errors.As(err, &agxErr) && agxErr.Code == types.ErrCodeClaudeSessionNotFound {
+		// Claude never wrote the conversation
+		return purge, nil
+	}
+	if err != nil {
+		return purge, err
+	}
+	purge.Deleted = true
+	return purge, nil
+}
+
+// DeletePlan lists what deleting a session would remove, for a dry run
+type DeletePlan struct {
+	// SessionFile is the session's file, "" when storage isn't on this machine
+	SessionFile string
+	// Transcript is the Claude transcript purging would delete, "" when there is none to delete
+	Transcript string
+	// SharedWith lists the other sessions bound to the conversation, which keep it
+	SharedWith []string
+	// Running is the lock of the kam running the session, which makes deleting it fail
+	Running *types.SessionLock
+}
+
+// PlanDelete reports what DeleteSession, or DeleteSessionAndConversation when purgeClaude is set,
+// would remove without removing anything; it refuses locked and protected sessions as they
+// would, and reports a running one in the plan
+func (m *Manager) PlanDelete(ctx context.Context, sessionName string, force, purgeClaude bool) (*DeletePlan, error) {
+	session, err := m.loadSession(ctx, sessionName)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.checkHeld(session.SessionID, "delete"); err != nil {
+		return nil, err
+	}
+	if err := checkProtected(session, force, "delete"); err != nil {
+		return nil, err
+	}
+
+	plan := &DeletePlan{SessionFile: index.SessionFile(m.storage, session.SessionID)}
+	if plan.Running, err = m.RunningLock(ctx, session.SessionID); err != nil {
+		return nil, err
+	}
+	if !purgeClaude || session.Claude.SessionID == "" {
+		return plan, nil
+	}
+	if plan.SharedWith, err = m.sessionsBoundTo(ctx, session.Claude.SessionID, session.SessionID); err != nil || len(plan.SharedWith) > 0 {
+		return plan, err
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	path := transcript.Path(session, homeDir)
+	if _, err := os.Stat(path); err == nil {
+		plan.Transcript = path
+	}
+	return plan, nil
+}
+
+// sessionsBoundTo returns the sessions other than except bound to a Claude conversation
+func (m *Manager) sessionsBoundTo(ctx context.Context, claudeSessionID, except string) ([]string, error) {
+	names, err := m.storage.ListSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var bound []string
+	for _, name := range names {
+		if name == except {
+			continue
+		}
+		other, err := m.storage.LoadSession(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if other.Claude.SessionID == claudeSessionID {
+			bound = append(bound, name)
+		}
+	}
+	return bound, nil
+}
+
+// checkProtected refuses a destructive action on a protected session unless forced
+func checkProtected(session *types.Session, force bool, action string) error {
+	if !session.Metadata.Protected || force {
+		return nil
+	}
+	return types.NewSessionError(
+		types.ErrCodeSessionProtected,
+		fmt.Sprintf("session '%s' is protected; refusing to %s it without --force", session.SessionID, action),
+		nil,
+	)
+}
+
+// RunHeadless runs a prompt non-interactively against an existing session
+// If the session has no Claude binding yet, the session created by the run is bound to it
+// It fails with ErrCodeSessionHeld when the session was locked with kam lock
+func (m *Manager) RunHeadless(ctx context.Context, sessionName, prompt string) (*claude.HeadlessResult, error) {
+	session, err := m.loadSession(ctx, sessionName)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.checkHeld(session.SessionID, "run"); err != nil {
+		return nil, err
+	}
+	// Claude appends to the conversation, so the session can't be running in another kam meanwhile
+	if err := m.storage.LockSession(ctx, session.SessionID); err != nil {
+		return nil, err
+	}
+	defer func() { _ = m.storage.UnlockSession(context.WithoutCancel(ctx), session.SessionID) }()
+
+	// A pre-assigned conversation Claude never wrote can't be resumed; the run starts a new one
+	resumeID := session.Claude.SessionID
+	if session.Claude.Pending {
+		resumeID = ""
+	}
+	// the run gets the arguments an interactive resume would: claude.defaultArgs, the session's
+	// own, its permission mode (plan for read-only sessions) and its variant's system prompt
+	result, err := m.claudeClient.RunHeadless(ctx, session.Project.WorkingDirectory, resumeID, prompt, m.LaunchArgs(session)...)
+	if err != nil {
+		return nil, err
+	}
+
+	now := m.clock.Now()
+	if resumeID == "" && result.SessionID != "" {
+		session.Claude.SessionID = result.SessionID
+		session.Claude.Pending = false
+		session.Claude.HasActiveContext = true
+	}
+	session.Claude.LastInteraction = now
+	session.LastAccessed = now
+	session.LastModified = now
+
+	if err := m.saveSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// BatchResult is the outcome of a headless run against one session in a batch
+type BatchResult struct {
+	SessionID string
+	Result    *claude.HeadlessResult
+	Err       error
+	Duration  time.Duration
+}
+
+// RunHeadlessBatch runs the same prompt against several sessions in parallel
+// At most concurrency runs execute at once; results are returned in input order
+func (m *Manager) RunHeadlessBatch(ctx context.Context, sessionNames []string, prompt string, concurrency int) []BatchResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]BatchResult, len(sessionNames))
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, sessionName := range sessionNames {
+		wg.Add(1)
+		go func(i int, sessionName string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			start := m.clock.Now()
+			result, err := m.RunHeadless(ctx, sessionName, prompt)
+			results[i] = BatchResult{
+				SessionID: sessionName,
+				Result:    result,
+				Err:       err,
+				Duration:  m.clock.Now().Sub(start),
+			}
+		}(i, sessionName)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// FindSessionsByTag returns the names of all sessions carrying the given tag
+func (m *Manager) FindSessionsByTag(ctx context.Context, tag string) ([]string, error) {
+	sessionNames, err := m.storage.ListSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []string
+	for _, sessionName := range sessionNames {
+		session, err := m.storage.LoadSession(ctx, sessionName)
+		if err != nil {
+			continue // skip unreadable sessions
+		}
+		for _, sessionTag := range session.Metadata.Tags {
+			if sessionTag == tag {
+				matches = append(matches, sessionName)
+				break
+			}
+		}
+	}
+
+	return matches, nil
+}
+
+// AddNote appends a note to a session
+func (m *Manager) AddNote(ctx context.Context, sessionName, source, text string) error {
+	session, err := m.loadSession(ctx, sessionName)
+	if err != nil {
+		return err
+	}
+
+	now := m.clock.Now()
+	session.Metadata.Notes = append(session.Metadata.Notes, types.Note{
+		Created: now,
+		Source:  source,
+		Text:    text,
+	})
+	session.LastModified = now
+
+	return m.saveSession(ctx, session)
+}
+
+// SetEnv sets a custom environment variable exported for a session
+func (m *Manager) SetEnv(ctx context.Context, sessionName, name, value string) error {
+	if err := ValidateEnvName(name); err != nil {
+		return err
+	}
+
+	session, err := m.loadSession(ctx, sessionName)
+	if err != nil {
+		return err
+	}
+
+	if session.Metadata.Env == nil {
+		session.Metadata.Env = make(map[string]string)
+	}
+	session.Metadata.Env[name] = value
+	session.LastModified = m.clock.Now()
+
+	return m.saveSession(ctx, session)
+}
+
+// UnsetEnv removes a custom environment variable from a session
+func (m *Manager) UnsetEnv(ctx context.Context, sessionName, name string) error {
+	session, err := m.loadSession(ctx, sessionName)
+	if err != nil {
+		return err
+	}
+
+	delete(session.Metadata.Env, name)
+	session.LastModified = m.clock.Now()
+
+	return m.saveSession(ctx, session)
+}
+
+// SetClaudeArgs replaces the extra arguments Claude launches with for a session; none clears them
+func (m *Manager) SetClaudeArgs(ctx context.Context, sessionName string, args []string) error {
+	if err := ValidateClaudeArgs(args); err != nil {
+		return err
+	}
+
+	session, err := m.loadSession(ctx, sessionName)
+	if err != nil {
+		return err
+	}
+
+	session.Metadata.ClaudeArgs = nil
+	if len(args) > 0 {
+		session.Metadata.ClaudeArgs = args
+	}
+	session.LastModified = m.clock.Now()
+
+	return m.saveSession(ctx, session)
+}
+
+// AddLink attaches a link to a session; a URL that is already linked is left as is
+func (m *Manager) AddLink(ctx context.Context, sessionName string, link types.Link) error {
+	session, err := m.loadSession(ctx, sessionName)
+	if err != nil {
+		return err
+	}
+
+	for _, existing := range session.Metadata.Links {
+		if existing.URL == link.URL {
+			return nil
+		}
+	}
+
+	now := m.clock.Now()
+	if link.Added.IsZero() {
+		link.Added = now
+	}
+	session.Metadata.Links = append(session.Metadata.Links, link)
+	session.LastModified = now
+
+	return m.saveSession(ctx, session)
+}
+
+// RemoveLink detaches the links accepted by matches and returns how many were removed
+func (m *Manager) RemoveLink(ctx context.Context, sessionName string, matches func(types.Link) bool) (int, error) {
+	session, err := m.loadSession(ctx, sessionName)
+	if err != nil {
+		return 0, err
+	}
+
+	kept := make([]types.Link, 0, len(session.Metadata.Links))
+	for _, link := range session.Metadata.Links {
+		if !matches(link) {
+			kept = append(kept, link)
+		}
+	}
+
+	removed := len(session.Metadata.Links) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+	session.Metadata.Links = kept
+	session.LastModified = m.clock.Now()
+
+	return removed, m.saveSession(ctx, session)
+}
+
+// SetBudget sets or, with a nil budget, clears a session's budget
+func (m *Manager) SetBudget(ctx context.Context, sessionName string, budget *types.Budget) error {
+	session, err := m.loadSession(ctx, sessionName)
+	if err != nil {
+		return err
+	}
+
+	session.Metadata.Budget = budget
+	session.LastModified = m.clock.Now()
+
+	return m.saveSession(ctx, session)
+}
+
+// LockSession marks the session as running in this process and returns its resolved name
+// It fails with ErrCodeSessionLocked when another kam process is running it, and with
+// ErrCodeSessionHeld when it was locked with kam lock
+func (m *Manager) LockSession(ctx context.Context, sessionName string) (string, error) {
+	resolved, err := m.resolveName(ctx, sessionName)
+	if err != nil {
+		return "", err
+	}
+	if err := m.checkHeld(resolved, "use"); err != nil {
+		return "", err
+	}
+	return resolved, m.storage.LockSession(ctx, resolved)
+}
+
+// UnlockSession releases the session's lock, including a stale one left by a crashed process
+func (m *Manager) UnlockSession(ctx context.Context, sessionName string) error {
+	resolved, err := m.resolveName(ctx, sessionName)
+	if err != nil {
+		return err
+	}
+	return m.storage.UnlockSession(ctx, resolved)
+}
+
+// RunningLock returns the lock of the kam process running the session, or nil when none is
+// running it; stale locks left by crashed processes count as not running
+func (m *Manager) RunningLock(ctx context.Context, sessionName string) (*types.SessionLock, error) {
+	resolved, err := m.resolveName(ctx, sessionName)
+	if err != nil {
+		return nil, err
+	}
+	lock, err := m.storage.ReadLock(ctx, resolved)
+	if err != nil || lock == nil || storage.LockIsStale(lock) {
+		return nil, err
+	}
+	return lock, nil
+}
+
+// checkNotRunning refuses an action on a session kam is running
+func (m *Manager) checkNotRunning(ctx context.Context, sessionID, action string) error {
+	lock, err := m.RunningLock(ctx, sessionID)
+	if err != nil || lock == nil {
+		return err
+	}
+	return types.NewSessionError(
+		types.ErrCodeSessionLocked,
+		fmt.Sprintf("session '%s' is running in kam (pid %d); refusing to %s it", sessionID, lock.PID, action),
+		nil,
+	).WithContext("pid", lock.PID)
+}
+
+// ResetClaudeSession unbinds the session's Claude conversation, so the next launch starts a fresh one
+func (m *Manager) ResetClaudeSession(ctx context.Context, sessionName string) error {
+	session, err := m.loadSession(ctx, sessionName)
+	if err != nil {
+		return err
+	}
+
+	session.Claude.SessionID = ""
+	session.Claude.ResumeInfo.CanResume = false
+	session.Claude.ResumeInfo.ResumeCommand = ""
+	session.LastModified = m.clock.Now()
+
+	return m.saveSession(ctx, session)
 }
 
-// DeleteSession removes a session
-func (m *Manager) DeleteSession(sessionName string) error {
-	return m.storage.DeleteSession(sessionName)
+// SetProtected marks a session as protected from delete, prune and trim, or removes the protection
+func (m *Manager) SetProtected(ctx context.Context, sessionName string, protected bool) error {
+	session, err := m.loadSession(ctx, sessionName)
+	if err != nil {
+		return err
+	}
+
+	session.Metadata.Protected = protected
+	session.LastModified = m.clock.Now()
+
+	return m.saveSession(ctx, session)
+}
+
+// SetPinned pins a session to the top of the picker and kam list, or unpins it
+func (m *Manager) SetPinned(ctx context.Context, sessionName string, pinned bool) error {
+	session, err := m.loadSession(ctx, sessionName)
+	if err != nil {
+		return err
+	}
+
+	session.Metadata.Pinned = pinned
+	session.LastModified = m.clock.Now()
+
+	return m.saveSession(ctx, session)
+}
+
+// SetPermissionMode pins the Claude permission mode a session launches in; an empty mode unpins it
+func (m *Manager) SetPermissionMode(ctx context.Context, sessionName, mode string) error {
+	mode, err := ParsePermissionMode(mode)
+	if err != nil {
+		return err
+	}
+
+	session, err := m.loadSession(ctx, sessionName)
+	if err != nil {
+		return err
+	}
+
+	session.Claude.PermissionMode = mode
+	session.LastModified = m.clock.Now()
+
+	return m.saveSession(ctx, session)
+}
+
+// SetReadOnly marks a session as read-only, or makes it editable again
+func (m *Manager) SetReadOnly(ctx context.Context, sessionName string, readOnly bool) error {
+	session, err := m.loadSession(ctx, sessionName)
+	if err != nil {
+		return err
+	}
+
+	session.Metadata.ReadOnly = readOnly
+	session.LastModified = m.clock.Now()
+
+	return m.saveSession(ctx, session)
+}
+
+// SetClock sets the clock used to timestamp session changes
+// Storage keeps its own clock; set both to simulate time end to end
+func (m *Manager) SetClock(c clock.Clock) {
+	m.clock = c
+}
+
+// SetStorage replaces the storage backend sessions are kept in
+func (m *Manager) SetStorage(storageImpl storage.Interface) {
+	m.storage = storageImpl
+}
+
+// SetClaudeClient replaces the client Claude is launched and queried through, e.g. with an
+// offline one
+func (m *Manager) SetClaudeClient(claudeClient claude.ClientInterface) {
+	m.claudeClient = claudeClient
 }
 
 // GetProjectPath returns the current project path
@@ -166,33 +844,127 @@ func (m *Manager) GetProjectPath() string {
 	return m.projectPath
 }
 
+// SetWorkspace scopes the manager to a monorepo workspace and the package containing dir
+func (m *Manager) SetWorkspace(workspace *project.Workspace, dir string) {
+	m.workspace = workspace
+	m.pkg = workspace.PackageFor(dir)
+}
+
+// GetWorkspace returns the detected monorepo workspace, or nil
+func (m *Manager) GetWorkspace() *project.Workspace {
+	return m.workspace
+}
+
+// GetWorkingDirectory returns the directory new sessions start in
+func (m *Manager) GetWorkingDirectory() string {
+	if m.pkg != nil {
+		return m.pkg.Path
+	}
+	return m.projectPath
+}
+
+// ListProjectSessions returns the sessions that belong to this project
+func (m *Manager) ListProjectSessions(ctx context.Context) ([]*types.Session, error) {
+	names, err := m.storage.ListSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []*types.Session
+	for _, name := range names {
+		session, err := m.storage.LoadSession(ctx, name)
+		if err != nil {
+			continue // unreadable sessions are skipped rather than failing the listing
+		}
+		if paths.Equal(session.Project.Path, m.projectPath) {
+			sessions = append(sessions, session)
+		}
+	}
+
+	return sessions, nil
+}
+
 // GetProjectName returns the current project name
 func (m *Manager) GetProjectName() string {
 	return filepath.Base(m.projectPath)
 }
 
-// setupClaudeSession configures the Claude session using subprocess monitoring
-func (m *Manager) setupClaudeSession(session *types.Session, startFresh bool) error {
+// setupClaudeSession launches Claude for a session without a conversation yet
+// When the installed Claude accepts --session-id the conversation ID is chosen up front and stored
+// before Claude starts; older versions fall back to a monitor subprocess that discovers it
+func (m *Manager) setupClaudeSession(ctx context.Context, session *types.Session, startFresh bool) error {
 	if startFresh {
-		// Launch Claude with monitor subprocess - this blocks until Claude exits
-		if err := m.claudeClient.LaunchClaudeInteractively(session.Project.WorkingDirectory, session.SessionID); err != nil {
+		// Launch Claude - this blocks until Claude exits
+		if err := m.PrepareLaunch(session); err != nil {
+			return err
+		}
+		args := m.LaunchArgs(session)
+		if m.canAssignSessionID(ctx) {
+			if err := m.assignClaudeSession(ctx, session); err != nil {
+				return err
+			}
+			args = append([]string{claude.FeatureSessionID.Flag, session.Claude.SessionID}, args...)
+		} else if session.Claude.Pending {
+			// Claude was downgraded since the ID was chosen; let the monitor discover a new one
+			session.Claude.SessionID = ""
+			session.Claude.Pending = false
+			session.LastModified = m.clock.Now()
+			if err := m.saveSession(ctx, session); err != nil {
+				return err
+			}
+		}
+		if err := m.claudeClient.LaunchClaudeInteractively(ctx, session.Project.WorkingDirectory, session.SessionID, args...); err != nil {
 			return err
 		}
 
 		// After Claude exits, the monitor subprocess should have saved the mapping
 		// Try to reload the session to get the updated Claude session ID
-		if updatedSession, err := m.storage.LoadSession(session.SessionID); err == nil {
+		ctx = context.WithoutCancel(ctx)
+		if updatedSession, err := m.storage.LoadSession(ctx, session.SessionID); err == nil {
 			session.Claude = updatedSession.Claude
 		}
+		if session.Claude.Pending {
+			// Claude only writes the conversation once it is sent a prompt
+			if exists, err := m.claudeClient.HasSession(ctx, session.Claude.SessionID, session.Project.WorkingDirectory); err == nil && exists {
+				session.Claude.Pending = false
+				session.Claude.HasActiveContext = true
+			}
+		}
 	}
 
 	return nil
 }
 
-// GetClaudeCommand returns the command to resume the Claude session
-func (m *Manager) GetClaudeCommand(session *types.Session) string {
+// canAssignSessionID reports whether the installed Claude is known to accept --session-id
+func (m *Manager) canAssignSessionID(ctx context.Context) bool {
+	version := m.claudeClient.Version(ctx)
+	return !version.IsZero() && claude.Check(version, claude.FeatureSessionID) == nil
+}
+
+// assignClaudeSession chooses the session's conversation ID, unless a pending one was already
+// chosen, and stores it before Claude starts
+func (m *Manager) assignClaudeSession(ctx context.Context, session *types.Session) error {
 	if session.Claude.SessionID == "" {
-		return "claude"
+		claudeSessionID, err := transcript.NewSessionID()
+		if err != nil {
+			return err
+		}
+		session.Claude.SessionID = claudeSessionID
+		session.Claude.Pending = true
+	}
+	session.LastModified = m.clock.Now()
+	return m.saveSession(ctx, session)
+}
+
+// HasClaudeSession reports whether the session's bound Claude conversation still exists
+func (m *Manager) HasClaudeSession(ctx context.Context, session *types.Session) (bool, error) {
+	if session.Claude.SessionID == "" {
+		return false, nil
 	}
-	return fmt.Sprintf("claude --resume %s", session.Claude.SessionID)
+	return m.claudeClient.HasSession(ctx, session.Claude.SessionID, session.Project.WorkingDirectory)
+}
+
+// GetClaudeCommand returns the command to launch or resume the Claude session
+func (m *Manager) GetClaudeCommand(session *types.Session) string {
+	return formatCommand(session, m.LaunchArgs(session))
 }
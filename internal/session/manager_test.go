@@ -1,39 +1,49 @@
 package session
 
 import (
+	"context"
+	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/bitomule/kamui/internal/claude"
+	"github.com/bitomule/kamui/internal/clock"
+	"github.com/bitomule/kamui/internal/paths"
+	"github.com/bitomule/kamui/internal/project"
 	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/internal/transcript"
 	"github.com/bitomule/kamui/pkg/types"
 )
 
 // MockClaudeClient is a mock implementation of claude.ClientInterface
 type MockClaudeClient struct {
 	mock.Mock
+
+	// ClaudeVersion is reported by Version; zero (unknown) by default
+	ClaudeVersion claude.Version
 }
 
-func (m *MockClaudeClient) HasSession(sessionID, workingDir string) (bool, error) {
+func (m *MockClaudeClient) HasSession(_ context.Context, sessionID, workingDir string) (bool, error) {
 	args := m.Called(sessionID, workingDir)
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *MockClaudeClient) StartSession(workingDir string) (string, error) {
+func (m *MockClaudeClient) StartSession(_ context.Context, workingDir string) (string, error) {
 	args := m.Called(workingDir)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockClaudeClient) ResumeSession(sessionID, workingDir string) error {
+func (m *MockClaudeClient) ResumeSession(_ context.Context, sessionID, workingDir string) error {
 	args := m.Called(sessionID, workingDir)
 	return args.Error(0)
 }
 
-func (m *MockClaudeClient) ListSessions() ([]string, error) {
+func (m *MockClaudeClient) ListSessions(_ context.Context) ([]string, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -45,7 +55,7 @@ func (m *MockClaudeClient) ListSessions() ([]string, error) {
 	return sessions, args.Error(1)
 }
 
-func (m *MockClaudeClient) GetSessionInfo(sessionID, workingDir string) (*claude.SessionInfo, error) {
+func (m *MockClaudeClient) GetSessionInfo(_ context.Context, sessionID, workingDir string) (*claude.SessionInfo, error) {
 	args := m.Called(sessionID, workingDir)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -57,12 +67,12 @@ func (m *MockClaudeClient) GetSessionInfo(sessionID, workingDir string) (*claude
 	return info, args.Error(1)
 }
 
-func (m *MockClaudeClient) TerminateSession(sessionID, workingDir string) error {
+func (m *MockClaudeClient) TerminateSession(_ context.Context, sessionID, workingDir string) error {
 	args := m.Called(sessionID, workingDir)
 	return args.Error(0)
 }
 
-func (m *MockClaudeClient) DiscoverExistingSessions(workingDir string) ([]string, error) {
+func (m *MockClaudeClient) DiscoverExistingSessions(_ context.Context, workingDir string) ([]string, error) {
 	args := m.Called(workingDir)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -74,16 +84,32 @@ func (m *MockClaudeClient) DiscoverExistingSessions(workingDir string) ([]string
 	return sessions, args.Error(1)
 }
 
-func (m *MockClaudeClient) DiscoverNewestSession(workingDir string) (string, error) {
+func (m *MockClaudeClient) DiscoverNewestSession(_ context.Context, workingDir string) (string, error) {
 	args := m.Called(workingDir)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockClaudeClient) LaunchClaudeInteractively(workingDir string, sessionName string) error {
-	args := m.Called(workingDir, sessionName)
+func (m *MockClaudeClient) LaunchClaudeInteractively(_ context.Context, workingDir string, sessionName string, claudeArgs ...string) error {
+	args := m.Called(workingDir, sessionName, claudeArgs)
 	return args.Error(0)
 }
 
+func (m *MockClaudeClient) Version(_ context.Context) claude.Version {
+	return m.ClaudeVersion
+}
+
+func (m *MockClaudeClient) RunHeadless(_ context.Context, workingDir, sessionID, prompt string, claudeArgs ...string) (*claude.HeadlessResult, error) {
+	args := m.Called(workingDir, sessionID, prompt, claudeArgs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	result, ok := args.Get(0).(*claude.HeadlessResult)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return result, args.Error(1)
+}
+
 func TestNewWithClient(t *testing.T) {
 	tempDir := t.TempDir()
 	mockClient := &MockClaudeClient{}
@@ -122,9 +148,9 @@ func TestCreateOrResumeSession_NewSession(t *testing.T) {
 	// HasSession should return false for the stored session check
 	mockClient.On("HasSession", "", tempDir).Return(false, nil).Maybe()
 	// LaunchClaudeInteractively should be called to create new session
-	mockClient.On("LaunchClaudeInteractively", tempDir, sessionName).Return(nil)
+	mockClient.On("LaunchClaudeInteractively", tempDir, sessionName, []string(nil)).Return(nil)
 
-	session, claudeWasExecuted, err := manager.CreateOrResumeSession(sessionName)
+	session, claudeWasExecuted, err := manager.CreateOrResumeSession(context.Background(), sessionName)
 	require.NoError(t, err)
 
 	assert.Equal(t, sessionName, session.SessionID)
@@ -149,13 +175,13 @@ func TestCreateOrResumeSession_ResumeExisting(t *testing.T) {
 	session, err := testStorage.CreateSession(sessionName, tempDir)
 	require.NoError(t, err)
 	session.Claude.SessionID = claudeSessionID
-	err = testStorage.SaveSession(session)
+	err = testStorage.SaveSession(context.Background(), session)
 	require.NoError(t, err)
 
 	// Mock expectations for resuming existing session
 	mockClient.On("HasSession", claudeSessionID, tempDir).Return(true, nil)
 
-	resumedSession, claudeWasExecuted, err := manager.CreateOrResumeSession(sessionName)
+	resumedSession, claudeWasExecuted, err := manager.CreateOrResumeSession(context.Background(), sessionName)
 	require.NoError(t, err)
 
 	assert.Equal(t, sessionName, resumedSession.SessionID)
@@ -181,22 +207,196 @@ func TestCreateOrResumeSession_StoredSessionMissing(t *testing.T) {
 	session, err := testStorage.CreateSession(sessionName, tempDir)
 	require.NoError(t, err)
 	session.Claude.SessionID = claudeSessionID
-	err = testStorage.SaveSession(session)
+	err = testStorage.SaveSession(context.Background(), session)
 	require.NoError(t, err)
 
 	// Mock expectations - stored Claude session no longer exists
 	mockClient.On("HasSession", claudeSessionID, tempDir).Return(false, nil)
-	mockClient.On("LaunchClaudeInteractively", tempDir, sessionName).Return(nil)
 
-	resumedSession, claudeWasExecuted, err := manager.CreateOrResumeSession(sessionName)
+	// A missing conversation is reported instead of silently starting a blank one
+	_, _, err = manager.CreateOrResumeSession(context.Background(), sessionName)
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeClaudeResumeFailed, agxErr.Code)
+	assert.Equal(t, true, agxErr.Context["missing"])
+	mockClient.AssertNotCalled(t, "LaunchClaudeInteractively", mock.Anything, mock.Anything, mock.Anything)
+
+	// Falling back to a fresh conversation launches Claude
+	require.NoError(t, manager.ApplyResumeFallback(context.Background(), sessionName, types.ResumeChoiceFresh, "", agxErr.Message))
+	mockClient.On("LaunchClaudeInteractively", tempDir, sessionName, []string(nil)).Return(nil)
+
+	resumedSession, claudeWasExecuted, err := manager.CreateOrResumeSession(context.Background(), sessionName)
 	require.NoError(t, err)
 
 	assert.Equal(t, sessionName, resumedSession.SessionID)
-	assert.True(t, claudeWasExecuted) // Should execute Claude since stored session was missing
+	assert.True(t, claudeWasExecuted)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestCreateOrResumeSession_PreassignedID(t *testing.T) {
+	tempDir := t.TempDir()
+	mockClient := &MockClaudeClient{ClaudeVersion: claude.Version{Major: 1, Minor: 0, Patch: 80}}
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	manager, err := NewWithDependencies(tempDir, testStorage, mockClient)
+	require.NoError(t, err)
+
+	// The conversation ID is chosen and stored before Claude starts
+	var launchedWith []string
+	mockClient.On("LaunchClaudeInteractively", tempDir, "api", mock.Anything).Run(func(args mock.Arguments) {
+		launchedWith = args.Get(2).([]string)
+		stored, err := testStorage.LoadSession(context.Background(), "api")
+		require.NoError(t, err)
+		assert.True(t, stored.Claude.Pending)
+		assert.Equal(t, []string{"--session-id", stored.Claude.SessionID}, launchedWith)
+	}).Return(nil).Twice()
+	// Claude exits before it is sent a prompt, so the conversation isn't written
+	mockClient.On("HasSession", mock.Anything, tempDir).Return(false, nil).Times(3)
+
+	session, claudeWasExecuted, err := manager.CreateOrResumeSession(context.Background(), "api")
+	require.NoError(t, err)
+	assert.True(t, claudeWasExecuted)
+	assert.Len(t, session.Claude.SessionID, 36)
+	assert.True(t, session.Claude.Pending)
+	claudeSessionID := session.Claude.SessionID
+
+	// The next launch starts the same conversation again instead of reporting it missing
+	session, claudeWasExecuted, err = manager.CreateOrResumeSession(context.Background(), "api")
+	require.NoError(t, err)
+	assert.True(t, claudeWasExecuted)
+	assert.Equal(t, claudeSessionID, session.Claude.SessionID)
+	assert.Equal(t, []string{"--session-id", claudeSessionID}, launchedWith)
+
+	// Once Claude has written it, the conversation is resumed
+	mockClient.On("HasSession", claudeSessionID, tempDir).Return(true, nil)
+	session, claudeWasExecuted, err = manager.CreateOrResumeSession(context.Background(), "api")
+	require.NoError(t, err)
+	assert.False(t, claudeWasExecuted)
+	assert.False(t, session.Claude.Pending)
+	assert.True(t, session.Claude.HasActiveContext)
 
 	mockClient.AssertExpectations(t)
 }
 
+func TestCreateOrResumeSession_PendingWithOldClaude(t *testing.T) {
+	tempDir := t.TempDir()
+	mockClient := &MockClaudeClient{ClaudeVersion: claude.Version{Major: 1, Minor: 0, Patch: 40}}
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	manager, err := NewWithDependencies(tempDir, testStorage, mockClient)
+	require.NoError(t, err)
+
+	session, err := testStorage.CreateSession("api", tempDir)
+	require.NoError(t, err)
+	session.Claude.SessionID = "never-written"
+	session.Claude.Pending = true
+	require.NoError(t, testStorage.SaveSession(context.Background(), session))
+
+	// Claude no longer accepts --session-id, so the monitor discovers the conversation instead
+	mockClient.On("HasSession", "never-written", tempDir).Return(false, nil)
+	mockClient.On("LaunchClaudeInteractively", tempDir, "api", []string(nil)).Return(nil)
+
+	session, claudeWasExecuted, err := manager.CreateOrResumeSession(context.Background(), "api")
+	require.NoError(t, err)
+	assert.True(t, claudeWasExecuted)
+	assert.Empty(t, session.Claude.SessionID)
+	assert.False(t, session.Claude.Pending)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestCreateSession(t *testing.T) {
+	tempDir := t.TempDir()
+	mockClient := &MockClaudeClient{}
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	manager, err := NewWithDependencies(tempDir, testStorage, mockClient)
+	require.NoError(t, err)
+
+	session, err := manager.CreateSession(context.Background(), "sdk-session", "created programmatically", []string{"bot"})
+	require.NoError(t, err)
+	assert.Equal(t, "sdk-session", session.SessionID)
+	assert.Equal(t, filepath.Base(tempDir), session.Project.Name)
+	assert.Empty(t, session.Claude.SessionID)
+
+	stored, err := manager.GetSession(context.Background(), "sdk-session")
+	require.NoError(t, err)
+	assert.Equal(t, "created programmatically", stored.Metadata.Description)
+	assert.Equal(t, []string{"bot"}, stored.Metadata.Tags)
+
+	// Creating the same session twice is rejected
+	_, err = manager.CreateSession(context.Background(), "sdk-session", "", nil)
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeSessionExists, agxErr.Code)
+
+	// Claude is never launched
+	mockClient.AssertNotCalled(t, "LaunchClaudeInteractively", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateSession_WorkspacePackage(t *testing.T) {
+	tempDir := t.TempDir()
+	mockClient := &MockClaudeClient{}
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	manager, err := NewWithDependencies(tempDir, testStorage, mockClient)
+	require.NoError(t, err)
+
+	apiPath := filepath.Join(tempDir, "services", "api")
+	manager.SetWorkspace(&project.Workspace{
+		Root:     tempDir,
+		Kind:     project.WorkspaceGo,
+		Packages: []project.Package{{Name: "services/api", Path: apiPath}},
+	}, filepath.Join(apiPath, "handlers"))
+
+	assert.Equal(t, apiPath, manager.GetWorkingDirectory())
+
+	session, err := manager.CreateSession(context.Background(), "api-work", "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, tempDir, session.Project.Path)
+	assert.Equal(t, apiPath, session.Project.WorkingDirectory)
+	assert.Equal(t, "services/api", session.Project.Package)
+}
+
+func TestNewForDir_WorkspaceOnlyWithAutoDetection(t *testing.T) {
+	root := paths.Canonical(t.TempDir())
+	apiPath := filepath.Join(root, "services", "api")
+	require.NoError(t, os.MkdirAll(apiPath, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.work"), []byte("go 1.22\nuse ./services/api\n"), 0o644))
+
+	manager, err := NewForDir(project.Detector{Strategy: project.StrategyAuto}, apiPath)
+	require.NoError(t, err)
+	assert.Equal(t, root, manager.GetProjectPath())
+	require.NotNil(t, manager.GetWorkspace())
+
+	manager, err = NewForDir(project.Detector{Strategy: project.StrategyCwd}, apiPath)
+	require.NoError(t, err)
+	assert.Equal(t, apiPath, manager.GetProjectPath(), "an explicit strategy ignores the workspace")
+	assert.Nil(t, manager.GetWorkspace())
+}
+
+func TestListProjectSessions(t *testing.T) {
+	tempDir := t.TempDir()
+	mockClient := &MockClaudeClient{}
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	manager, err := NewWithDependencies(tempDir, testStorage, mockClient)
+	require.NoError(t, err)
+
+	_, err = manager.CreateSession(context.Background(), "mine", "", nil)
+	require.NoError(t, err)
+
+	other, err := testStorage.CreateSession("theirs", "/elsewhere")
+	require.NoError(t, err)
+	require.NoError(t, testStorage.SaveSession(context.Background(), other))
+
+	sessions, err := manager.ListProjectSessions(context.Background())
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "mine", sessions[0].SessionID)
+}
+
 func TestGetSession(t *testing.T) {
 	tempDir := t.TempDir()
 	mockClient := &MockClaudeClient{}
@@ -210,11 +410,11 @@ func TestGetSession(t *testing.T) {
 	// Create and save a session
 	originalSession, err := testStorage.CreateSession(sessionName, tempDir)
 	require.NoError(t, err)
-	err = testStorage.SaveSession(originalSession)
+	err = testStorage.SaveSession(context.Background(), originalSession)
 	require.NoError(t, err)
 
 	// Retrieve the session
-	retrievedSession, err := manager.GetSession(sessionName)
+	retrievedSession, err := manager.GetSession(context.Background(), sessionName)
 	require.NoError(t, err)
 
 	assert.Equal(t, sessionName, retrievedSession.SessionID)
@@ -229,7 +429,7 @@ func TestGetSessionNotFound(t *testing.T) {
 	manager, err := NewWithDependencies(tempDir, testStorage, mockClient)
 	require.NoError(t, err)
 
-	_, err = manager.GetSession("nonexistent")
+	_, err = manager.GetSession(context.Background(), "nonexistent")
 	require.Error(t, err)
 
 	var agxErr *types.AGXError
@@ -246,7 +446,7 @@ func TestListSessions(t *testing.T) {
 	require.NoError(t, err)
 
 	// Should be empty initially
-	sessions, err := manager.ListSessions()
+	sessions, err := manager.ListSessions(context.Background())
 	require.NoError(t, err)
 	assert.Empty(t, sessions)
 
@@ -255,12 +455,12 @@ func TestListSessions(t *testing.T) {
 	for _, name := range sessionNames {
 		session, createErr := testStorage.CreateSession(name, tempDir)
 		require.NoError(t, createErr)
-		saveErr := testStorage.SaveSession(session)
+		saveErr := testStorage.SaveSession(context.Background(), session)
 		require.NoError(t, saveErr)
 	}
 
 	// List should return all sessions
-	sessions, err = manager.ListSessions()
+	sessions, err = manager.ListSessions(context.Background())
 	require.NoError(t, err)
 	assert.Len(t, sessions, 3)
 
@@ -288,15 +488,15 @@ func TestCompleteSession(t *testing.T) {
 	// Create and save a session
 	session, err := testStorage.CreateSession(sessionName, tempDir)
 	require.NoError(t, err)
-	err = testStorage.SaveSession(session)
+	err = testStorage.SaveSession(context.Background(), session)
 	require.NoError(t, err)
 
 	// Complete the session
-	err = manager.CompleteSession(sessionName)
+	err = manager.CompleteSession(context.Background(), sessionName)
 	require.NoError(t, err)
 
 	// Verify session state changed
-	completedSession, err := manager.GetSession(sessionName)
+	completedSession, err := manager.GetSession(context.Background(), sessionName)
 	require.NoError(t, err)
 
 	assert.Equal(t, types.SessionStateCompleted, completedSession.Lifecycle.State)
@@ -318,24 +518,653 @@ func TestDeleteSession(t *testing.T) {
 	// Create and save a session
 	session, err := testStorage.CreateSession(sessionName, tempDir)
 	require.NoError(t, err)
-	err = testStorage.SaveSession(session)
+	err = testStorage.SaveSession(context.Background(), session)
 	require.NoError(t, err)
 
 	// Verify it exists
-	sessions, err := manager.ListSessions()
+	sessions, err := manager.ListSessions(context.Background())
 	require.NoError(t, err)
 	assert.Contains(t, sessions, sessionName)
 
+	// Protected sessions are only deleted when forced
+	require.NoError(t, manager.SetProtected(context.Background(), sessionName, true))
+	err = manager.DeleteSession(context.Background(), sessionName, false)
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeSessionProtected, agxErr.Code)
+
+	// A session running in kam isn't deleted, or the launch would save it again when Claude exits
+	_, err = manager.LockSession(context.Background(), sessionName)
+	require.NoError(t, err)
+	err = manager.DeleteSession(context.Background(), sessionName, true)
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeSessionLocked, agxErr.Code)
+	_, err = manager.DeleteSessionAndConversation(context.Background(), sessionName, true)
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeSessionLocked, agxErr.Code)
+	require.NoError(t, manager.UnlockSession(context.Background(), sessionName))
+
 	// Delete the session
-	err = manager.DeleteSession(sessionName)
+	err = manager.DeleteSession(context.Background(), sessionName, true)
 	require.NoError(t, err)
 
 	// Verify it no longer exists
-	sessions, err = manager.ListSessions()
+	sessions, err = manager.ListSessions(context.Background())
 	require.NoError(t, err)
 	assert.NotContains(t, sessions, sessionName)
 }
 
+func TestPlanDelete(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	sessionsDir := filepath.Join(tempDir, ".claude", "kamui-sessions")
+	testStorage := storage.NewWithSessionsDir(tempDir, sessionsDir)
+	manager, err := NewWithDependencies(tempDir, testStorage, &MockClaudeClient{})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	save := func(name, claudeSessionID string) *types.Session {
+		session, err := testStorage.CreateSession(name, tempDir)
+		require.NoError(t, err)
+		session.Claude.SessionID = claudeSessionID
+		require.NoError(t, testStorage.SaveSession(ctx, session))
+		return session
+	}
+	alone := save("alone", "claude-1")
+	save("shared", "claude-2")
+	save("shared-fork", "claude-2")
+	transcriptPath := transcript.Path(alone, tempDir)
+	require.NoError(t, os.MkdirAll(filepath.Dir(transcriptPath), 0o755))
+	require.NoError(t, os.WriteFile(transcriptPath, []byte("{}\n"), 0o600))
+
+	plan, err := manager.PlanDelete(ctx, "alone", false, true)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(sessionsDir, "alone.json"), plan.SessionFile)
+	assert.Equal(t, transcriptPath, plan.Transcript)
+
+	plan, err = manager.PlanDelete(ctx, "alone", false, false)
+	require.NoError(t, err)
+	assert.Empty(t, plan.Transcript, "the transcript is kept without purging")
+
+	plan, err = manager.PlanDelete(ctx, "shared", false, true)
+	require.NoError(t, err)
+	assert.Empty(t, plan.Transcript)
+	assert.Equal(t, []string{"shared-fork"}, plan.SharedWith)
+
+	require.NoError(t, manager.SetProtected(ctx, "alone", true))
+	_, err = manager.PlanDelete(ctx, "alone", false, false)
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeSessionProtected, agxErr.Code)
+	plan, err = manager.PlanDelete(ctx, "alone", true, false)
+	require.NoError(t, err)
+	assert.Nil(t, plan.Running)
+
+	_, err = manager.LockSession(ctx, "alone")
+	require.NoError(t, err)
+	plan, err = manager.PlanDelete(ctx, "alone", true, false)
+	require.NoError(t, err)
+	require.NotNil(t, plan.Running, "a session running in kam is reported")
+	assert.Equal(t, os.Getpid(), plan.Running.PID)
+
+	sessions, err := manager.ListSessions(ctx)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 3, "planning deletes nothing")
+	_, err = os.Stat(transcriptPath)
+	assert.NoError(t, err)
+}
+
+func TestDeleteSessionAndConversation(t *testing.T) {
+	tempDir := t.TempDir()
+	mockClient := &MockClaudeClient{}
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	manager, err := NewWithDependencies(tempDir, testStorage, mockClient)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	save := func(name, claudeSessionID string) {
+		session, err := testStorage.CreateSession(name, tempDir)
+		require.NoError(t, err)
+		session.Claude.SessionID = claudeSessionID
+		require.NoError(t, testStorage.SaveSession(ctx, session))
+	}
+	save("alone", "claude-1")
+	save("shared", "claude-2")
+	save("shared-fork", "claude-2")
+	save("unbound", "")
+
+	mockClient.On("TerminateSession", "claude-1", tempDir).Return(nil).Once()
+	purge, err := manager.DeleteSessionAndConversation(ctx, "alone", false)
+	require.NoError(t, err)
+	assert.True(t, purge.Deleted)
+
+	// another session still resumes the conversation
+	purge, err = manager.DeleteSessionAndConversation(ctx, "shared", false)
+	require.NoError(t, err)
+	assert.False(t, purge.Deleted)
+	assert.Equal(t, []string{"shared-fork"}, purge.SharedWith)
+
+	purge, err = manager.DeleteSessionAndConversation(ctx, "unbound", false)
+	require.NoError(t, err)
+	assert.Empty(t, purge.ClaudeSessionID)
+	assert.False(t, purge.Deleted)
+
+	sessions, err := manager.ListSessions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"shared-fork"}, sessions)
+	mockClient.AssertExpectations(t)
+}
+
+func TestRunHeadless_BindsNewClaudeSession(t *testing.T) {
+	tempDir := t.TempDir()
+	mockClient := &MockClaudeClient{}
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	manager, err := NewWithDependencies(tempDir, testStorage, mockClient)
+	require.NoError(t, err)
+
+	session, err := testStorage.CreateSession("headless", tempDir)
+	require.NoError(t, err)
+	require.NoError(t, testStorage.SaveSession(context.Background(), session))
+
+	mockClient.On("RunHeadless", tempDir, "", "summarize", []string(nil)).Return(&claude.HeadlessResult{
+		SessionID: "claude-new-123",
+		Result:    "summary",
+	}, nil)
+
+	result, err := manager.RunHeadless(context.Background(), "headless", "summarize")
+	require.NoError(t, err)
+	assert.Equal(t, "summary", result.Result)
+
+	updated, err := manager.GetSession(context.Background(), "headless")
+	require.NoError(t, err)
+	assert.Equal(t, "claude-new-123", updated.Claude.SessionID)
+	assert.True(t, updated.Claude.HasActiveContext)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestRunHeadless_ResumesExistingClaudeSession(t *testing.T) {
+	tempDir := t.TempDir()
+	mockClient := &MockClaudeClient{}
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	manager, err := NewWithDependencies(tempDir, testStorage, mockClient)
+	require.NoError(t, err)
+
+	session, err := testStorage.CreateSession("headless", tempDir)
+	require.NoError(t, err)
+	session.Claude.SessionID = "claude-existing"
+	require.NoError(t, testStorage.SaveSession(context.Background(), session))
+
+	mockClient.On("RunHeadless", tempDir, "claude-existing", "status?", []string(nil)).Return(&claude.HeadlessResult{
+		SessionID: "claude-existing",
+		Result:    "all good",
+	}, nil)
+
+	_, err = manager.RunHeadless(context.Background(), "headless", "status?")
+	require.NoError(t, err)
+	lock, err := manager.RunningLock(context.Background(), "headless")
+	require.NoError(t, err)
+	assert.Nil(t, lock, "the session is unlocked after the run")
+
+	// a session running in another kam isn't resumed a second time
+	_, err = manager.LockSession(context.Background(), "headless")
+	require.NoError(t, err)
+	_, err = manager.RunHeadless(context.Background(), "headless", "status?")
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeSessionLocked, agxErr.Code)
+
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNumberOfCalls(t, "RunHeadless", 1)
+}
+
+func TestRunHeadless_ReadOnlySessionRunsInPlanMode(t *testing.T) {
+	tempDir := t.TempDir()
+	mockClient := &MockClaudeClient{}
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	manager, err := NewWithDependencies(tempDir, testStorage, mockClient)
+	require.NoError(t, err)
+
+	session, err := testStorage.CreateSession("headless", tempDir)
+	require.NoError(t, err)
+	session.Claude.SessionID = "claude-existing"
+	session.Metadata.ReadOnly = true
+	require.NoError(t, testStorage.SaveSession(context.Background(), session))
+
+	mockClient.On("RunHeadless", tempDir, "claude-existing", "status?", []string{"--permission-mode", ReadOnlyPermissionMode}).
+		Return(&claude.HeadlessResult{SessionID: "claude-existing"}, nil)
+
+	_, err = manager.RunHeadless(context.Background(), "headless", "status?")
+	require.NoError(t, err)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestRunHeadless_PassesLaunchArgs(t *testing.T) {
+	tempDir := t.TempDir()
+	mockClient := &MockClaudeClient{}
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	manager, err := NewWithDependencies(tempDir, testStorage, mockClient)
+	require.NoError(t, err)
+	manager.SetDefaultClaudeArgs([]string{"--verbose"})
+
+	session, err := testStorage.CreateSession("headless", tempDir)
+	require.NoError(t, err)
+	session.Claude.SessionID = "claude-existing"
+	session.Claude.PermissionMode = "acceptEdits"
+	session.Metadata.ClaudeArgs = []string{"--model", "opus"}
+	require.NoError(t, testStorage.SaveSession(context.Background(), session))
+
+	mockClient.On("RunHeadless", tempDir, "claude-existing", "status?",
+		[]string{"--verbose", "--model", "opus", "--permission-mode", "acceptEdits"}).
+		Return(&claude.HeadlessResult{SessionID: "claude-existing"}, nil)
+
+	_, err = manager.RunHeadless(context.Background(), "headless", "status?")
+	require.NoError(t, err)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestRunHeadless_PendingClaudeSession(t *testing.T) {
+	tempDir := t.TempDir()
+	mockClient := &MockClaudeClient{}
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	manager, err := NewWithDependencies(tempDir, testStorage, mockClient)
+	require.NoError(t, err)
+
+	session, err := testStorage.CreateSession("headless", tempDir)
+	require.NoError(t, err)
+	session.Claude.SessionID = "never-written"
+	session.Claude.Pending = true
+	require.NoError(t, testStorage.SaveSession(context.Background(), session))
+
+	// A conversation Claude never wrote can't be resumed, so the run starts one and binds it
+	mockClient.On("RunHeadless", tempDir, "", "summarize", []string(nil)).Return(&claude.HeadlessResult{
+		SessionID: "claude-new-123",
+		Result:    "summary",
+	}, nil)
+
+	_, err = manager.RunHeadless(context.Background(), "headless", "summarize")
+	require.NoError(t, err)
+
+	updated, err := manager.GetSession(context.Background(), "headless")
+	require.NoError(t, err)
+	assert.Equal(t, "claude-new-123", updated.Claude.SessionID)
+	assert.False(t, updated.Claude.Pending)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestFindSessionsByTag(t *testing.T) {
+	tempDir := t.TempDir()
+	mockClient := &MockClaudeClient{}
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	manager, err := NewWithDependencies(tempDir, testStorage, mockClient)
+	require.NoError(t, err)
+
+	tagged := map[string][]string{
+		"api":      {"backend"},
+		"frontend": {"web"},
+		"worker":   {"backend", "queue"},
+	}
+	for name, tags := range tagged {
+		session, createErr := testStorage.CreateSession(name, tempDir)
+		require.NoError(t, createErr)
+		session.Metadata.Tags = tags
+		require.NoError(t, testStorage.SaveSession(context.Background(), session))
+	}
+
+	matches, err := manager.FindSessionsByTag(context.Background(), "backend")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"api", "worker"}, matches)
+}
+
+func TestRunHeadlessBatch(t *testing.T) {
+	tempDir := t.TempDir()
+	mockClient := &MockClaudeClient{}
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	manager, err := NewWithDependencies(tempDir, testStorage, mockClient)
+	require.NoError(t, err)
+
+	for _, name := range []string{"one", "two"} {
+		session, createErr := testStorage.CreateSession(name, tempDir)
+		require.NoError(t, createErr)
+		session.Claude.SessionID = "claude-" + name
+		require.NoError(t, testStorage.SaveSession(context.Background(), session))
+	}
+
+	mockClient.On("RunHeadless", tempDir, "claude-one", "test", []string(nil)).Return(&claude.HeadlessResult{Result: "ok"}, nil)
+	mockClient.On("RunHeadless", tempDir, "claude-two", "test", []string(nil)).Return(nil, assert.AnError)
+
+	results := manager.RunHeadlessBatch(context.Background(), []string{"one", "two", "missing"}, "test", 2)
+	require.Len(t, results, 3)
+
+	assert.Equal(t, "one", results[0].SessionID)
+	require.NoError(t, results[0].Err)
+	assert.Equal(t, "ok", results[0].Result.Result)
+
+	assert.Equal(t, "two", results[1].SessionID)
+	require.ErrorIs(t, results[1].Err, assert.AnError)
+
+	assert.Equal(t, "missing", results[2].SessionID)
+	require.Error(t, results[2].Err)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestAddNote(t *testing.T) {
+	tempDir := t.TempDir()
+	mockClient := &MockClaudeClient{}
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	manager, err := NewWithDependencies(tempDir, testStorage, mockClient)
+	require.NoError(t, err)
+
+	session, err := testStorage.CreateSession("noted", tempDir)
+	require.NoError(t, err)
+	require.NoError(t, testStorage.SaveSession(context.Background(), session))
+
+	require.NoError(t, manager.AddNote(context.Background(), "noted", "schedule:abc", "weekly summary"))
+
+	updated, err := manager.GetSession(context.Background(), "noted")
+	require.NoError(t, err)
+	require.Len(t, updated.Metadata.Notes, 1)
+	assert.Equal(t, "schedule:abc", updated.Metadata.Notes[0].Source)
+	assert.Equal(t, "weekly summary", updated.Metadata.Notes[0].Text)
+}
+
+func TestSimulatedClock(t *testing.T) {
+	tempDir := t.TempDir()
+	mockClient := &MockClaudeClient{}
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	manager, err := NewWithDependencies(tempDir, testStorage, mockClient)
+	require.NoError(t, err)
+
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	testStorage.SetClock(fake)
+	manager.SetClock(fake)
+
+	_, err = manager.CreateSession(context.Background(), "timed", "", nil)
+	require.NoError(t, err)
+
+	fake.Advance(72 * time.Hour)
+	require.NoError(t, manager.AddNote(context.Background(), "timed", "user", "three days later"))
+
+	updated, err := manager.GetSession(context.Background(), "timed")
+	require.NoError(t, err)
+	assert.Equal(t, start, updated.Created)
+	assert.Equal(t, start, updated.Lifecycle.StateHistory[0].Timestamp)
+	assert.Equal(t, start.Add(72*time.Hour), updated.LastModified)
+	assert.Equal(t, start.Add(72*time.Hour), updated.Metadata.Notes[0].Created)
+}
+
+func TestSetStorage(t *testing.T) {
+	tempDir := t.TempDir()
+	fileStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	manager, err := NewWithDependencies(tempDir, fileStorage, &MockClaudeClient{})
+	require.NoError(t, err)
+
+	memory := storage.NewMemory(tempDir)
+	manager.SetStorage(memory)
+
+	_, err = manager.CreateSession(context.Background(), "in-memory", "", nil)
+	require.NoError(t, err)
+	assert.True(t, memory.SessionExists(context.Background(), "in-memory"))
+	assert.False(t, fileStorage.SessionExists(context.Background(), "in-memory"))
+}
+
+func TestSetAndUnsetEnv(t *testing.T) {
+	tempDir := t.TempDir()
+	mockClient := &MockClaudeClient{}
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	manager, err := NewWithDependencies(tempDir, testStorage, mockClient)
+	require.NoError(t, err)
+
+	_, err = manager.CreateSession(context.Background(), "env", "", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, manager.SetEnv(context.Background(), "env", "API_BASE", "http://localhost"))
+	require.Error(t, manager.SetEnv(context.Background(), "env", "KAMUI_ACTIVE", "0"))
+
+	session, err := manager.GetSession(context.Background(), "env")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"API_BASE": "http://localhost"}, session.Metadata.Env)
+
+	require.NoError(t, manager.UnsetEnv(context.Background(), "env", "API_BASE"))
+	session, err = manager.GetSession(context.Background(), "env")
+	require.NoError(t, err)
+	assert.Empty(t, session.Metadata.Env)
+}
+
+func TestAddRemoveLink(t *testing.T) {
+	tempDir := t.TempDir()
+	mockClient := &MockClaudeClient{}
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	manager, err := NewWithDependencies(tempDir, testStorage, mockClient)
+	require.NoError(t, err)
+
+	_, err = manager.CreateSession(context.Background(), "linked", "", nil)
+	require.NoError(t, err)
+
+	issue := types.Link{Kind: "github-issue", URL: "https://github.com/org/repo/issues/1", Label: "org/repo#1"}
+	pr := types.Link{Kind: "github-pr", URL: "https://github.com/org/repo/pull/2", Label: "org/repo#2"}
+	require.NoError(t, manager.AddLink(context.Background(), "linked", issue))
+	require.NoError(t, manager.AddLink(context.Background(), "linked", pr))
+	require.NoError(t, manager.AddLink(context.Background(), "linked", issue), "linking the same URL twice is a no-op")
+
+	session, err := manager.GetSession(context.Background(), "linked")
+	require.NoError(t, err)
+	require.Len(t, session.Metadata.Links, 2)
+	assert.False(t, session.Metadata.Links[0].Added.IsZero())
+
+	removed, err := manager.RemoveLink(context.Background(), "linked", func(link types.Link) bool { return link.Kind == "github-pr" })
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	session, err = manager.GetSession(context.Background(), "linked")
+	require.NoError(t, err)
+	require.Len(t, session.Metadata.Links, 1)
+	assert.Equal(t, issue.URL, session.Metadata.Links[0].URL)
+
+	removed, err = manager.RemoveLink(context.Background(), "linked", func(types.Link) bool { return false })
+	require.NoError(t, err)
+	assert.Zero(t, removed)
+}
+
+func TestSetBudget(t *testing.T) {
+	tempDir := t.TempDir()
+	mockClient := &MockClaudeClient{}
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	manager, err := NewWithDependencies(tempDir, testStorage, mockClient)
+	require.NoError(t, err)
+
+	_, err = manager.CreateSession(context.Background(), "budgeted", "", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, manager.SetBudget(context.Background(), "budgeted", &types.Budget{CostUSD: 5}))
+	session, err := manager.GetSession(context.Background(), "budgeted")
+	require.NoError(t, err)
+	require.NotNil(t, session.Metadata.Budget)
+	assert.Equal(t, 5.0, session.Metadata.Budget.CostUSD)
+
+	require.NoError(t, manager.SetBudget(context.Background(), "budgeted", nil))
+	session, err = manager.GetSession(context.Background(), "budgeted")
+	require.NoError(t, err)
+	assert.Nil(t, session.Metadata.Budget)
+
+	require.Error(t, manager.SetBudget(context.Background(), "missing", &types.Budget{Tokens: 1000}))
+}
+
+func TestLockSession(t *testing.T) {
+	tempDir := t.TempDir()
+	mockClient := &MockClaudeClient{}
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	manager, err := NewWithDependencies(tempDir, testStorage, mockClient)
+	require.NoError(t, err)
+
+	resolved, err := manager.LockSession(context.Background(), " api ")
+	require.NoError(t, err)
+	assert.Equal(t, "api", resolved)
+
+	_, err = manager.LockSession(context.Background(), "api")
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeSessionLocked, agxErr.Code)
+
+	running, err := manager.RunningLock(context.Background(), "api")
+	require.NoError(t, err)
+	require.NotNil(t, running)
+	assert.Equal(t, os.Getpid(), running.PID)
+
+	require.NoError(t, manager.UnlockSession(context.Background(), "api"))
+	running, err = manager.RunningLock(context.Background(), "api")
+	require.NoError(t, err)
+	assert.Nil(t, running)
+
+	_, err = manager.LockSession(context.Background(), "api")
+	require.NoError(t, err)
+}
+
+func TestResetClaudeSession(t *testing.T) {
+	tempDir := t.TempDir()
+	mockClient := &MockClaudeClient{}
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	manager, err := NewWithDependencies(tempDir, testStorage, mockClient)
+	require.NoError(t, err)
+
+	session, err := manager.CreateSession(context.Background(), "api", "", nil)
+	require.NoError(t, err)
+	session.Claude.SessionID = "claude-1"
+	require.NoError(t, testStorage.SaveSession(context.Background(), session))
+
+	require.NoError(t, manager.ResetClaudeSession(context.Background(), "api"))
+	session, err = manager.GetSession(context.Background(), "api")
+	require.NoError(t, err)
+	assert.Empty(t, session.Claude.SessionID)
+}
+
+func TestSetReadOnly(t *testing.T) {
+	tempDir := t.TempDir()
+	mockClient := &MockClaudeClient{}
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	manager, err := NewWithDependencies(tempDir, testStorage, mockClient)
+	require.NoError(t, err)
+
+	_, err = manager.CreateSession(context.Background(), "reference", "", nil)
+	require.NoError(t, err)
+	require.NoError(t, manager.SetReadOnly(context.Background(), "reference", true))
+
+	// A read-only session without a conversation still starts Claude in plan mode
+	mockClient.On("LaunchClaudeInteractively", tempDir, "reference", []string{"--permission-mode", "plan"}).Return(nil)
+	session, executed, err := manager.CreateOrResumeSession(context.Background(), "reference")
+	require.NoError(t, err)
+	assert.True(t, executed)
+	assert.True(t, session.Metadata.ReadOnly)
+	mockClient.AssertExpectations(t)
+
+	require.NoError(t, manager.SetReadOnly(context.Background(), "reference", false))
+	session, err = manager.GetSession(context.Background(), "reference")
+	require.NoError(t, err)
+	assert.False(t, session.Metadata.ReadOnly)
+
+	require.Error(t, manager.SetReadOnly(context.Background(), "missing", true))
+}
+
+func TestSetPinned(t *testing.T) {
+	tempDir := t.TempDir()
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	manager, err := NewWithDependencies(tempDir, testStorage, &MockClaudeClient{})
+	require.NoError(t, err)
+
+	_, err = manager.CreateSession(context.Background(), "daily", "", nil)
+	require.NoError(t, err)
+	require.NoError(t, manager.SetPinned(context.Background(), "daily", true))
+	session, err := manager.GetSession(context.Background(), "daily")
+	require.NoError(t, err)
+	assert.True(t, session.Metadata.Pinned)
+
+	require.NoError(t, manager.SetPinned(context.Background(), "daily", false))
+	session, err = manager.GetSession(context.Background(), "daily")
+	require.NoError(t, err)
+	assert.False(t, session.Metadata.Pinned)
+
+	require.Error(t, manager.SetPinned(context.Background(), "missing", true))
+}
+
+func TestSetClaudeArgs(t *testing.T) {
+	tempDir := t.TempDir()
+	mockClient := &MockClaudeClient{}
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	manager, err := NewWithDependencies(tempDir, testStorage, mockClient)
+	require.NoError(t, err)
+	manager.SetDefaultClaudeArgs([]string{"--verbose"})
+
+	_, err = manager.CreateSession(context.Background(), "shared", "", nil)
+	require.NoError(t, err)
+	require.NoError(t, manager.SetClaudeArgs(context.Background(), "shared", []string{"--add-dir", "../shared"}))
+
+	// Every launch gets the configured defaults followed by the session's own arguments
+	mockClient.On("LaunchClaudeInteractively", tempDir, "shared", []string{"--verbose", "--add-dir", "../shared"}).Return(nil)
+	session, _, err := manager.CreateOrResumeSession(context.Background(), "shared")
+	require.NoError(t, err)
+	mockClient.AssertExpectations(t)
+	assert.Equal(t, "claude --verbose --add-dir ../shared", manager.GetClaudeCommand(session))
+
+	require.NoError(t, manager.SetClaudeArgs(context.Background(), "shared", nil))
+	session, err = manager.GetSession(context.Background(), "shared")
+	require.NoError(t, err)
+	assert.Nil(t, session.Metadata.ClaudeArgs)
+
+	require.Error(t, manager.SetClaudeArgs(context.Background(), "shared", []string{"--resume", "x"}))
+	require.Error(t, manager.SetClaudeArgs(context.Background(), "missing", nil))
+}
+
+func TestSetPermissionMode(t *testing.T) {
+	tempDir := t.TempDir()
+	mockClient := &MockClaudeClient{}
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	manager, err := NewWithDependencies(tempDir, testStorage, mockClient)
+	require.NoError(t, err)
+
+	_, err = manager.CreateSession(context.Background(), "risky", "", nil)
+	require.NoError(t, err)
+	require.NoError(t, manager.SetPermissionMode(context.Background(), "risky", "bypass"))
+
+	mockClient.On("LaunchClaudeInteractively", tempDir, "risky", []string{"--permission-mode", "bypassPermissions"}).Return(nil)
+	session, _, err := manager.CreateOrResumeSession(context.Background(), "risky")
+	require.NoError(t, err)
+	assert.Equal(t, "bypassPermissions", session.Claude.PermissionMode)
+	mockClient.AssertExpectations(t)
+
+	require.NoError(t, manager.SetPermissionMode(context.Background(), "risky", ""))
+	session, err = manager.GetSession(context.Background(), "risky")
+	require.NoError(t, err)
+	assert.Empty(t, session.Claude.PermissionMode)
+
+	require.Error(t, manager.SetPermissionMode(context.Background(), "risky", "yolo"))
+	require.Error(t, manager.SetPermissionMode(context.Background(), "missing", "plan"))
+}
+
 func TestGetProjectPath(t *testing.T) {
 	tempDir := t.TempDir()
 	mockClient := &MockClaudeClient{}
@@ -0,0 +1,170 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// ManualLocks persists the locks set with kam lock, as a JSON object of session IDs to locks
+// They live outside the session files so they only hold on this machine, whatever the storage backend
+type ManualLocks struct {
+	path string
+}
+
+// NewManualLocks creates a lock store backed by ~/.kamui/locks.json
+func NewManualLocks() *ManualLocks {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+	return NewManualLocksWithPath(filepath.Join(homeDir, ".kamui", "locks.json"))
+}
+
+// NewManualLocksWithPath creates a lock store backed by the given file
+func NewManualLocksWithPath(path string) *ManualLocks {
+	return &ManualLocks{path: path}
+}
+
+// All returns every manual lock by session ID
+func (l *ManualLocks) All() (map[string]types.ManualLock, error) {
+	data, err := os.ReadFile(l.path)
+	if os.IsNotExist(err) {
+		return map[string]types.ManualLock{}, nil
+	}
+	if err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStoragePermission, "failed to read manual locks file", err)
+	}
+
+	locks := map[string]types.ManualLock{}
+	if err := json.Unmarshal(data, &locks); err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStorageCorrupted, "failed to parse manual locks file", err)
+	}
+	return locks, nil
+}
+
+// Get returns a session's manual lock, or nil when it has none
+func (l *ManualLocks) Get(sessionID string) (*types.ManualLock, error) {
+	locks, err := l.All()
+	if err != nil {
+		return nil, err
+	}
+	lock, ok := locks[sessionID]
+	if !ok {
+		return nil, nil
+	}
+	return &lock, nil
+}
+
+// Set locks a session, replacing any lock it had
+func (l *ManualLocks) Set(sessionID string, lock types.ManualLock) error {
+	locks, err := l.All()
+	if err != nil {
+		return err
+	}
+	locks[sessionID] = lock
+	return l.save(locks)
+}
+
+// Remove releases a session's lock, reporting whether it had one
+func (l *ManualLocks) Remove(sessionID string) (bool, error) {
+	locks, err := l.All()
+	if err != nil {
+		return false, err
+	}
+	if _, ok := locks[sessionID]; !ok {
+		return false, nil
+	}
+	delete(locks, sessionID)
+	return true, l.save(locks)
+}
+
+// save writes the locks atomically
+func (l *ManualLocks) save(locks map[string]types.ManualLock) error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return types.NewStorageError(types.ErrCodeStoragePermission, "failed to create manual locks directory", err)
+	}
+	data, err := json.MarshalIndent(locks, "", "  ")
+	if err != nil {
+		return types.NewStorageError(types.ErrCodeStorageCorrupted, "failed to marshal manual locks", err)
+	}
+
+	tempFile := l.path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0o600); err != nil {
+		return types.NewStorageError(types.ErrCodeStoragePermission, "failed to write manual locks file", err)
+	}
+	if err := os.Rename(tempFile, l.path); err != nil {
+		os.Remove(tempFile) // cleanup temp file
+		return types.NewStorageError(types.ErrCodeStoragePermission, "failed to save manual locks file", err)
+	}
+	return nil
+}
+
+// SetManualLocks sets where manual locks are kept; without a store they are neither set nor checked
+func (m *Manager) SetManualLocks(locks *ManualLocks) {
+	m.manualLocks = locks
+}
+
+// HoldSession locks a session by hand with a reason, so resuming or deleting it is refused until
+// ReleaseSession; it returns the session's resolved name
+func (m *Manager) HoldSession(ctx context.Context, sessionName, reason, user string) (string, error) {
+	if m.manualLocks == nil {
+		return "", types.NewSessionError(types.ErrCodeInvalidInput, "manual locks are not available", nil)
+	}
+	session, err := m.loadSession(ctx, sessionName)
+	if err != nil {
+		return "", err
+	}
+	lock := types.ManualLock{Reason: reason, User: user, Locked: m.clock.Now()}
+	return session.SessionID, m.manualLocks.Set(session.SessionID, lock)
+}
+
+// ReleaseSession removes a session's manual lock, reporting whether it had one
+func (m *Manager) ReleaseSession(ctx context.Context, sessionName string) (bool, error) {
+	if m.manualLocks == nil {
+		return false, nil
+	}
+	resolved, err := m.resolveName(ctx, sessionName)
+	if err != nil {
+		return false, err
+	}
+	return m.manualLocks.Remove(resolved)
+}
+
+// ManualLock returns a session's manual lock, or nil when it has none
+func (m *Manager) ManualLock(sessionID string) (*types.ManualLock, error) {
+	if m.manualLocks == nil {
+		return nil, nil
+	}
+	return m.manualLocks.Get(sessionID)
+}
+
+// checkHeld refuses an action on a session locked with kam lock
+func (m *Manager) checkHeld(sessionID, action string) error {
+	lock, err := m.ManualLock(sessionID)
+	if err != nil || lock == nil {
+		return err
+	}
+	return HeldError(sessionID, lock, action)
+}
+
+// HeldError is the ErrCodeSessionHeld error for an action refused because of a manual lock
+func HeldError(sessionID string, lock *types.ManualLock, action string) *types.AGXError {
+	message := fmt.Sprintf("session '%s' is locked", sessionID)
+	if lock.User != "" {
+		message += " by " + lock.User
+	}
+	message += " since " + lock.Locked.Local().Format("2006-01-02 15:04")
+	if lock.Reason != "" {
+		message += ": " + lock.Reason
+	}
+	message += fmt.Sprintf("; refusing to %s it", action)
+
+	return types.NewSessionError(types.ErrCodeSessionHeld, message, nil).
+		WithContext("reason", lock.Reason).
+		WithContext("user", lock.User)
+}
@@ -0,0 +1,103 @@
+package session
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/clock"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestManualLocks(t *testing.T) {
+	locks := NewManualLocksWithPath(filepath.Join(t.TempDir(), "locks.json"))
+
+	all, err := locks.All()
+	require.NoError(t, err)
+	assert.Empty(t, all)
+
+	lock := types.ManualLock{Reason: "demo at 3pm", User: "sam", Locked: time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)}
+	require.NoError(t, locks.Set("api", lock))
+	got, err := locks.Get("api")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "demo at 3pm", got.Reason)
+	assert.True(t, got.Locked.Equal(lock.Locked))
+
+	got, err = locks.Get("web")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	removed, err := locks.Remove("api")
+	require.NoError(t, err)
+	assert.True(t, removed)
+	removed, err = locks.Remove("api")
+	require.NoError(t, err)
+	assert.False(t, removed)
+}
+
+func TestHoldSession(t *testing.T) {
+	manager, _ := newNamesTestManager(t)
+	manager.SetManualLocks(NewManualLocksWithPath(filepath.Join(t.TempDir(), "locks.json")))
+	manager.SetClock(clock.NewFake(time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)))
+	ctx := context.Background()
+
+	_, err := manager.CreateSession(ctx, "demo", "", nil)
+	require.NoError(t, err)
+
+	_, err = manager.HoldSession(ctx, "missing", "", "")
+	require.Error(t, err)
+
+	resolved, err := manager.HoldSession(ctx, "demo", "demo at 3pm, don't touch", "sam")
+	require.NoError(t, err)
+	assert.Equal(t, "demo", resolved)
+
+	// resuming, running and deleting are refused, even when forced
+	requireHeld := func(err error) {
+		t.Helper()
+		var agxErr *types.AGXError
+		require.ErrorAs(t, err, &agxErr)
+		assert.Equal(t, types.ErrCodeSessionHeld, agxErr.Code)
+		assert.Contains(t, agxErr.Message, "demo at 3pm, don't touch")
+		assert.False(t, agxErr.IsRecoverable())
+	}
+	_, err = manager.LockSession(ctx, "demo")
+	requireHeld(err)
+	_, err = manager.RunHeadless(ctx, "demo", "hello")
+	requireHeld(err)
+	requireHeld(manager.DeleteSession(ctx, "demo", true))
+
+	summaries, err := manager.ListSummaries(ctx, ListOptions{Filter: "locked"})
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	require.NotNil(t, summaries[0].Lock)
+	assert.Equal(t, "sam", summaries[0].Lock.User)
+
+	released, err := manager.ReleaseSession(ctx, "demo")
+	require.NoError(t, err)
+	assert.True(t, released)
+	released, err = manager.ReleaseSession(ctx, "demo")
+	require.NoError(t, err)
+	assert.False(t, released)
+
+	_, err = manager.LockSession(ctx, "demo")
+	require.NoError(t, err)
+	require.NoError(t, manager.UnlockSession(ctx, "demo"))
+	require.NoError(t, manager.DeleteSession(ctx, "demo", false))
+}
+
+func TestHoldSession_WithoutStore(t *testing.T) {
+	manager, _ := newNamesTestManager(t)
+	ctx := context.Background()
+
+	_, err := manager.CreateSession(ctx, "demo", "", nil)
+	require.NoError(t, err)
+	_, err = manager.HoldSession(ctx, "demo", "", "")
+	require.Error(t, err)
+	_, err = manager.LockSession(ctx, "demo")
+	require.NoError(t, err)
+}
@@ -0,0 +1,127 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitomule/kamui/internal/paths"
+	"github.com/bitomule/kamui/internal/transcript"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// TranscriptAction is what MoveSession does with the session's Claude transcript
+type TranscriptAction string
+
+const (
+	// TranscriptCopy copies the transcript to the new project's Claude directory, keeping the original
+	TranscriptCopy TranscriptAction = "copy"
+	// TranscriptMove copies the transcript and removes the original once the session is saved
+	TranscriptMove TranscriptAction = "move"
+	// TranscriptLeave leaves the transcript where it is; the conversation won't resume from the new project
+	TranscriptLeave TranscriptAction = "none"
+)
+
+// ParseTranscriptAction parses copy, move or none
+func ParseTranscriptAction(name string) (TranscriptAction, error) {
+	switch action := TranscriptAction(strings.ToLower(strings.TrimSpace(name))); action {
+	case TranscriptCopy, TranscriptMove, TranscriptLeave:
+		return action, nil
+	default:
+		return "", types.NewSessionError(types.ErrCodeInvalidInput, fmt.Sprintf("unknown transcript action %q (use copy, move or none)", name), nil)
+	}
+}
+
+// MoveResult describes a session moved to another project
+type MoveResult struct {
+	Session *types.Session
+
+	// FromDir and ToDir are the session's old and new working directories
+	FromDir string
+	ToDir   string
+
+	// Transcript is the transcript's path in the new project, "" when it wasn't relocated
+	Transcript string
+
+	// TranscriptMissing is set when the session's conversation had no transcript to relocate
+	TranscriptMissing bool
+
+	// Rewritten is how many transcript records had their working directory rewritten
+	Rewritten int
+}
+
+// MoveSession points a session at the project in projectPath. A working directory inside the old
+// project, such as a workspace package, keeps its place under the new one when it exists there.
+// Claude keeps transcripts per working directory, so unless action is TranscriptLeave the bound
+// transcript is relocated to the new project's Claude directory for the conversation to resume there
+func (m *Manager) MoveSession(ctx context.Context, sessionName, projectPath string, action TranscriptAction) (*MoveResult, error) {
+	session, err := m.loadSession(ctx, sessionName)
+	if err != nil {
+		return nil, err
+	}
+
+	projectPath = paths.Canonical(projectPath)
+	if info, statErr := os.Stat(projectPath); statErr != nil || !info.IsDir() {
+		return nil, types.NewStorageError(types.ErrCodeProjectNotFound, fmt.Sprintf("project directory does not exist: %s", projectPath), statErr)
+	}
+	if paths.Equal(projectPath, session.Project.Path) {
+		return nil, types.NewSessionError(types.ErrCodeInvalidInput, fmt.Sprintf("session '%s' is already in %s", session.SessionID, projectPath), nil)
+	}
+
+	result := &MoveResult{
+		Session: session,
+		FromDir: session.Project.WorkingDirectory,
+		ToDir:   projectPath,
+	}
+	pkg := ""
+	if rel, relErr := filepath.Rel(session.Project.Path, session.Project.WorkingDirectory); relErr == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+		if info, statErr := os.Stat(filepath.Join(projectPath, rel)); statErr == nil && info.IsDir() {
+			result.ToDir = filepath.Join(projectPath, rel)
+			pkg = session.Project.Package
+		}
+	}
+
+	var src string
+	if session.Claude.SessionID != "" && action != TranscriptLeave {
+		homeDir, homeErr := os.UserHomeDir()
+		if homeErr != nil {
+			return nil, homeErr
+		}
+		src = transcript.Path(session, homeDir)
+		dst := filepath.Join(paths.ClaudeProjectDir(homeDir, result.ToDir), session.Claude.SessionID+".jsonl")
+		switch _, statErr := os.Stat(src); {
+		case os.IsNotExist(statErr):
+			result.TranscriptMissing, src = true, ""
+		case paths.Equal(src, dst):
+			src = "" // both directories encode the same way; nothing to relocate
+		default:
+			if result.Rewritten, err = transcript.RelocateFile(ctx, src, dst, result.FromDir, result.ToDir); err != nil {
+				return nil, err
+			}
+			result.Transcript = dst
+		}
+	}
+
+	session.Project.Path = projectPath
+	session.Project.WorkingDirectory = result.ToDir
+	session.Project.Name = filepath.Base(projectPath)
+	session.Project.Package = pkg
+	session.LastModified = m.clock.Now()
+
+	if err := m.saveSession(ctx, session); err != nil {
+		if result.Transcript != "" {
+			os.Remove(result.Transcript)
+		}
+		return nil, err
+	}
+
+	if action == TranscriptMove && src != "" {
+		if err := os.Remove(src); err != nil {
+			return result, types.NewStorageError(types.ErrCodeStoragePermission,
+				fmt.Sprintf("session moved, but the old transcript %s couldn't be removed", src), err)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,98 @@
+package session
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/paths"
+	"github.com/bitomule/kamui/internal/transcript"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestParseTranscriptAction(t *testing.T) {
+	action, err := ParseTranscriptAction(" Move ")
+	require.NoError(t, err)
+	assert.Equal(t, TranscriptMove, action)
+
+	_, err = ParseTranscriptAction("delete")
+	assert.Error(t, err)
+}
+
+func TestMoveSession(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	manager, testStorage := newNamesTestManager(t)
+	ctx := context.Background()
+
+	session, err := manager.CreateSession(ctx, "api", "", nil)
+	require.NoError(t, err)
+	oldDir := session.Project.WorkingDirectory
+	session.Claude.SessionID = "claude-api"
+	require.NoError(t, testStorage.SaveSession(ctx, session))
+
+	_, src, err := manager.TranscriptPath(ctx, "api")
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(src), 0o755))
+	require.NoError(t, os.WriteFile(src, []byte(`{"type":"user","uuid":"u1","cwd":"`+oldDir+`","message":{"role":"user","content":"hi"}}`+"\n"), 0o600))
+
+	newDir := paths.Canonical(t.TempDir())
+	result, err := manager.MoveSession(ctx, "api", newDir, TranscriptMove)
+	require.NoError(t, err)
+	assert.Equal(t, oldDir, result.FromDir)
+	assert.Equal(t, newDir, result.ToDir)
+	assert.Equal(t, 1, result.Rewritten)
+	assert.Equal(t, filepath.Join(paths.ClaudeProjectDir(homeDir, newDir), "claude-api.jsonl"), result.Transcript)
+
+	moved, err := manager.GetSession(ctx, "api")
+	require.NoError(t, err)
+	assert.Equal(t, newDir, moved.Project.Path)
+	assert.Equal(t, newDir, moved.Project.WorkingDirectory)
+	assert.Equal(t, filepath.Base(newDir), moved.Project.Name)
+
+	_, err = os.Stat(src)
+	assert.True(t, os.IsNotExist(err), "moving removes the original transcript")
+	_, dst, err := manager.TranscriptPath(ctx, "api")
+	require.NoError(t, err)
+	records, err := transcript.ReadFile(ctx, dst)
+	require.NoError(t, err)
+	assert.Equal(t, newDir, records[0].CWD)
+
+	// moving to where the session already is fails
+	_, err = manager.MoveSession(ctx, "api", newDir, TranscriptCopy)
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeInvalidInput, agxErr.Code)
+
+	_, err = manager.MoveSession(ctx, "api", filepath.Join(newDir, "missing"), TranscriptCopy)
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeProjectNotFound, agxErr.Code)
+}
+
+func TestMoveSession_KeepsPackageDirectory(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	manager, testStorage := newNamesTestManager(t)
+	ctx := context.Background()
+
+	session, err := manager.CreateSession(ctx, "web", "", nil)
+	require.NoError(t, err)
+	session.Project.WorkingDirectory = filepath.Join(session.Project.Path, "packages", "web")
+	session.Project.Package = "web"
+	session.Claude.SessionID = "claude-web"
+	require.NoError(t, testStorage.SaveSession(ctx, session))
+
+	newDir := paths.Canonical(t.TempDir())
+	require.NoError(t, os.MkdirAll(filepath.Join(newDir, "packages", "web"), 0o755))
+
+	// without a transcript the session still moves
+	result, err := manager.MoveSession(ctx, "web", newDir, TranscriptCopy)
+	require.NoError(t, err)
+	assert.True(t, result.TranscriptMissing)
+	assert.Empty(t, result.Transcript)
+	assert.Equal(t, filepath.Join(newDir, "packages", "web"), result.Session.Project.WorkingDirectory)
+	assert.Equal(t, "web", result.Session.Project.Package)
+}
@@ -0,0 +1,208 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/bitomule/kamui/internal/record"
+	"github.com/bitomule/kamui/internal/schedule"
+	"github.com/bitomule/kamui/internal/snapshot"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// NormalizeName returns the canonical form of a session name: trimmed and NFC-normalized,
+// and lowercased when foldCase is set
+func NormalizeName(name string, foldCase bool) string {
+	normalized := norm.NFC.String(strings.TrimSpace(name))
+	if foldCase {
+		normalized = strings.ToLower(normalized)
+	}
+	return normalized
+}
+
+// SetCaseFolding controls whether session names differing only in case refer to the same session
+func (m *Manager) SetCaseFolding(enabled bool) {
+	m.foldCase = enabled
+}
+
+// resolveName maps a user-supplied session name to the stored session it refers to,
+// or to the normalized name for a new session
+// Names that only differ in case from an existing session are rejected unless case folding is on,
+// since case-insensitive filesystems would map them to the same file
+func (m *Manager) resolveName(ctx context.Context, sessionName string) (string, error) {
+	normalized := NormalizeName(sessionName, false)
+	if normalized == "" {
+		return "", types.NewSessionError(
+			types.ErrCodeInvalidInput,
+			"session name cannot be empty",
+			nil,
+		)
+	}
+
+	existing, err := m.storage.ListSessions(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var caseMatches []string
+	for _, name := range existing {
+		candidate := NormalizeName(name, false)
+		if candidate == normalized {
+			return name, nil
+		}
+		if strings.EqualFold(candidate, normalized) {
+			caseMatches = append(caseMatches, name)
+		}
+	}
+
+	switch {
+	case len(caseMatches) == 1 && m.foldCase:
+		return caseMatches[0], nil
+	case len(caseMatches) > 0:
+		return "", types.NewSessionError(
+			types.ErrCodeSessionExists,
+			fmt.Sprintf("session name '%s' collides with existing session '%s' (names differ only in case)", normalized, strings.Join(caseMatches, "', '")),
+			nil,
+		)
+	}
+
+	if m.foldCase {
+		return NormalizeName(sessionName, true), nil
+	}
+	return normalized, nil
+}
+
+// loadSession resolves a session name and loads the session
+func (m *Manager) loadSession(ctx context.Context, sessionName string) (*types.Session, error) {
+	resolved, err := m.resolveName(ctx, sessionName)
+	if err != nil {
+		return nil, err
+	}
+	return m.storage.LoadSession(ctx, resolved)
+}
+
+// SetRenamedState sets the stores of the state kam files under session names outside the session
+// itself, so renaming a session moves its snapshots, recordings and schedules; stores left unset
+// aren't touched
+func (m *Manager) SetRenamedState(snapshots *snapshot.Store, recordingsDir string, schedules *schedule.Store) {
+	m.snapshots = snapshots
+	m.recordingsDir = recordingsDir
+	m.schedules = schedules
+}
+
+// RenameSession gives a session a new name, keeping everything else about it: its backups,
+// timesheet runs, snapshots, recordings and schedules move to the new name too
+// Sessions running in kam or locked with kam lock aren't renamed, and the new name must be free
+func (m *Manager) RenameSession(ctx context.Context, sessionName, newName string) (*types.Session, error) {
+	session, err := m.loadSession(ctx, sessionName)
+	if err != nil {
+		return nil, err
+	}
+	oldName := session.SessionID
+	if err := m.checkHeld(oldName, "rename"); err != nil {
+		return nil, err
+	}
+	if err := m.checkNotRunning(ctx, oldName, "rename"); err != nil {
+		return nil, err
+	}
+
+	normalized := NormalizeName(newName, m.foldCase)
+	if normalized == "" || normalized == "." || normalized == ".." || strings.ContainsAny(normalized, `/\`) {
+		return nil, types.NewSessionError(types.ErrCodeInvalidInput, fmt.Sprintf("invalid session name '%s'", newName), nil)
+	}
+	if normalized == oldName {
+		return session, nil
+	}
+	target, err := m.resolveName(ctx, normalized)
+	if err != nil {
+		return nil, err
+	}
+	if m.storage.SessionExists(ctx, target) {
+		return nil, types.NewSessionError(types.ErrCodeSessionExists, fmt.Sprintf("session '%s' already exists", target), nil)
+	}
+
+	session.SessionID = target
+	session.LastModified = m.clock.Now()
+	if err := m.saveSession(ctx, session); err != nil {
+		return nil, err
+	}
+	if err := m.storage.DeleteSession(ctx, oldName); err != nil {
+		return nil, err
+	}
+	m.unindexSession(oldName)
+
+	if err := m.moveNamedState(oldName, target); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// moveNamedState moves the state filed under a renamed session's old name to its new one
+func (m *Manager) moveNamedState(oldName, newName string) error {
+	notMoved := func(what string, err error) error {
+		return types.NewStorageError(
+			types.ErrCodeStoragePermission,
+			fmt.Sprintf("session '%s' was renamed to '%s', but its %s weren't moved", oldName, newName, what),
+			err,
+		)
+	}
+
+	if err := moveBackups(m.storage.GetBackupsPath(oldName), m.storage.GetBackupsPath(newName)); err != nil {
+		return notMoved("backups", err)
+	}
+	if m.timesheet != nil {
+		if _, err := m.timesheet.Rename(oldName, newName); err != nil {
+			return notMoved("timesheet runs", err)
+		}
+	}
+	if m.snapshots != nil {
+		if err := m.snapshots.Rename(oldName, newName); err != nil {
+			return notMoved("snapshots", err)
+		}
+	}
+	if m.recordingsDir != "" {
+		if err := record.Rename(m.recordingsDir, oldName, newName); err != nil {
+			return notMoved("recordings", err)
+		}
+	}
+	if m.schedules != nil {
+		if _, err := m.schedules.Rename(m.projectPath, oldName, newName); err != nil {
+			return notMoved("schedules", err)
+		}
+	}
+	return nil
+}
+
+// moveBackups moves a renamed session's backups directory; backups a deleted session left under
+// the new name are replaced where they clash
+func moveBackups(from, to string) error {
+	entries, err := os.ReadDir(from)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(to); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(to), 0o700); err != nil {
+			return err
+		}
+		return os.Rename(from, to)
+	}
+
+	for _, entry := range entries {
+		target := filepath.Join(to, entry.Name())
+		if err := os.RemoveAll(target); err != nil {
+			return err
+		}
+		if err := os.Rename(filepath.Join(from, entry.Name()), target); err != nil {
+			return err
+		}
+	}
+	return os.Remove(from)
+}
@@ -0,0 +1,182 @@
+package session
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/schedule"
+	"github.com/bitomule/kamui/internal/snapshot"
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/internal/timesheet"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func newNamesTestManager(t *testing.T) (*Manager, *storage.Storage) {
+	t.Helper()
+	tempDir := t.TempDir()
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	manager, err := NewWithDependencies(tempDir, testStorage, &MockClaudeClient{})
+	require.NoError(t, err)
+	return manager, testStorage
+}
+
+func TestNormalizeName(t *testing.T) {
+	decomposed := "Cafe\u0301"
+	composed := "Caf\u00e9"
+
+	assert.Equal(t, composed, NormalizeName(decomposed, false))
+	assert.Equal(t, "caf\u00e9", NormalizeName(decomposed, true))
+	assert.Equal(t, "api", NormalizeName("  api ", false))
+}
+
+func TestResolveName_MatchesDecomposedStoredName(t *testing.T) {
+	manager, testStorage := newNamesTestManager(t)
+
+	stored, err := testStorage.CreateSession("Cafe\u0301", manager.GetProjectPath())
+	require.NoError(t, err)
+	require.NoError(t, testStorage.SaveSession(context.Background(), stored))
+
+	session, err := manager.GetSession(context.Background(), "Caf\u00e9")
+	require.NoError(t, err)
+	assert.Equal(t, "Cafe\u0301", session.SessionID)
+}
+
+func TestResolveName_CaseCollision(t *testing.T) {
+	manager, _ := newNamesTestManager(t)
+
+	_, err := manager.CreateSession(context.Background(), "api", "", nil)
+	require.NoError(t, err)
+
+	_, err = manager.CreateSession(context.Background(), "Api", "", nil)
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeSessionExists, agxErr.Code)
+	assert.Contains(t, agxErr.Message, "differ only in case")
+
+	_, err = manager.GetSession(context.Background(), "API")
+	require.ErrorAs(t, err, &agxErr)
+}
+
+func TestResolveName_CaseFolding(t *testing.T) {
+	manager, _ := newNamesTestManager(t)
+	manager.SetCaseFolding(true)
+
+	created, err := manager.CreateSession(context.Background(), "Api", "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "api", created.SessionID)
+
+	session, err := manager.GetSession(context.Background(), "API")
+	require.NoError(t, err)
+	assert.Equal(t, "api", session.SessionID)
+}
+
+func TestResolveName_Empty(t *testing.T) {
+	manager, _ := newNamesTestManager(t)
+
+	_, err := manager.GetSession(context.Background(), "   ")
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeInvalidInput, agxErr.Code)
+}
+
+func TestRenameSession(t *testing.T) {
+	manager, testStorage := newNamesTestManager(t)
+	ctx := context.Background()
+
+	_, err := manager.CreateSession(ctx, "demo", "Fix login", nil)
+	require.NoError(t, err)
+	_, err = manager.CreateSession(ctx, "taken", "", nil)
+	require.NoError(t, err)
+
+	renamed, err := manager.RenameSession(ctx, "demo", " login-fix ")
+	require.NoError(t, err)
+	assert.Equal(t, "login-fix", renamed.SessionID)
+	assert.False(t, testStorage.SessionExists(ctx, "demo"))
+	loaded, err := manager.GetSession(ctx, "login-fix")
+	require.NoError(t, err)
+	assert.Equal(t, "Fix login", loaded.Metadata.Description)
+
+	var agxErr *types.AGXError
+	_, err = manager.RenameSession(ctx, "login-fix", "taken")
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeSessionExists, agxErr.Code)
+
+	_, err = manager.RenameSession(ctx, "login-fix", "../escape")
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeInvalidInput, agxErr.Code)
+
+	// a session running in kam keeps its name
+	_, err = manager.LockSession(ctx, "login-fix")
+	require.NoError(t, err)
+	_, err = manager.RenameSession(ctx, "login-fix", "other")
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeSessionLocked, agxErr.Code)
+}
+
+func TestRenameSession_MovesBackupsAndRuns(t *testing.T) {
+	manager, testStorage := newNamesTestManager(t)
+	ctx := context.Background()
+	store := timesheet.NewStoreWithPath(filepath.Join(t.TempDir(), "timesheet.jsonl"))
+	manager.SetTimesheet(store)
+
+	_, err := manager.CreateSession(ctx, "demo", "", nil)
+	require.NoError(t, err)
+	backup := filepath.Join(testStorage.GetBackupsPath("demo"), "transcript.jsonl")
+	require.NoError(t, os.MkdirAll(filepath.Dir(backup), 0o700))
+	require.NoError(t, os.WriteFile(backup, []byte("{}\n"), 0o600))
+	// a deleted session left backups under the new name
+	stale := filepath.Join(testStorage.GetBackupsPath("login-fix"), "old.jsonl")
+	require.NoError(t, os.MkdirAll(filepath.Dir(stale), 0o700))
+	require.NoError(t, os.WriteFile(stale, []byte("{}\n"), 0o600))
+	start := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, store.Record(timesheet.Entry{SessionID: "demo", Start: start, End: start.Add(time.Hour)}))
+
+	_, err = manager.RenameSession(ctx, "demo", "login-fix")
+	require.NoError(t, err)
+
+	assert.NoDirExists(t, testStorage.GetBackupsPath("demo"))
+	assert.FileExists(t, filepath.Join(testStorage.GetBackupsPath("login-fix"), "transcript.jsonl"))
+	assert.FileExists(t, stale)
+	entries, err := store.Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "login-fix", entries[0].SessionID)
+}
+
+func TestRenameSession_MovesSnapshotsRecordingsAndSchedules(t *testing.T) {
+	manager, _ := newNamesTestManager(t)
+	ctx := context.Background()
+	stateDir := t.TempDir()
+	recordingsDir := filepath.Join(stateDir, "recordings")
+	schedules := schedule.NewStoreWithPath(filepath.Join(stateDir, "schedules.json"))
+	manager.SetRenamedState(snapshot.NewStoreWithDir(filepath.Join(stateDir, "snapshots")), recordingsDir, schedules)
+
+	_, err := manager.CreateSession(ctx, "demo", "", nil)
+	require.NoError(t, err)
+	history := filepath.Join(stateDir, "snapshots", "demo", "history.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(history), 0o700))
+	require.NoError(t, os.WriteFile(history, []byte("[]"), 0o600))
+	cast := filepath.Join(recordingsDir, "demo", "20261001-090000.cast")
+	require.NoError(t, os.MkdirAll(filepath.Dir(cast), 0o700))
+	require.NoError(t, os.WriteFile(cast, []byte("{}\n"), 0o600))
+	require.NoError(t, schedules.Add(&schedule.Schedule{SessionID: "demo", ProjectPath: manager.GetProjectPath(), Cron: "0 9 * * 1", Prompt: "p"}))
+
+	_, err = manager.RenameSession(ctx, "demo", "login-fix")
+	require.NoError(t, err)
+
+	assert.FileExists(t, filepath.Join(stateDir, "snapshots", "login-fix", "history.json"))
+	assert.NoDirExists(t, filepath.Join(stateDir, "snapshots", "demo"))
+	assert.FileExists(t, filepath.Join(recordingsDir, "login-fix", "20261001-090000.cast"))
+	assert.NoDirExists(t, filepath.Join(recordingsDir, "demo"))
+	entries, err := schedules.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "login-fix", entries[0].SessionID)
+}
@@ -0,0 +1,98 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/bitomule/kamui/internal/fence"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// DefaultVariant is the variant of sessions created without one
+const DefaultVariant = "default"
+
+// claudeMDMarkers delimit the variant instructions Kamui keeps in CLAUDE.md
+var claudeMDMarkers = fence.Markers{
+	Begin: "<!-- kamui:variant begin - managed by Kamui, edit the project config instead -->",
+	End:   "<!-- kamui:variant end -->",
+}
+
+// SetProjectConfig applies the project configuration: the variant new sessions get and the
+// instructions each variant launches with
+func (m *Manager) SetProjectConfig(config *types.ProjectConfig) {
+	m.projectConfig = config
+}
+
+// Variant returns the variant of a session, DefaultVariant when it has none
+func Variant(session *types.Session) string {
+	if session.Metadata.Variant == "" {
+		return DefaultVariant
+	}
+	return session.Metadata.Variant
+}
+
+// ValidateVariant checks a variant name; projects that list their variants only accept those
+func (m *Manager) ValidateVariant(variant string) error {
+	if m.projectConfig != nil && len(m.projectConfig.Session.Variants) > 0 && !slices.Contains(m.projectConfig.Session.Variants, variant) {
+		return types.NewSessionError(
+			types.ErrCodeInvalidInput,
+			fmt.Sprintf("unknown variant '%s' (the project config allows %v)", variant, m.projectConfig.Session.Variants),
+			nil,
+		)
+	}
+	return nil
+}
+
+// SetVariant changes the variant of a session
+func (m *Manager) SetVariant(ctx context.Context, sessionName, variant string) error {
+	if err := m.ValidateVariant(variant); err != nil {
+		return err
+	}
+
+	session, err := m.loadSession(ctx, sessionName)
+	if err != nil {
+		return err
+	}
+
+	session.Metadata.Variant = variant
+	session.LastModified = m.clock.Now()
+
+	return m.saveSession(ctx, session)
+}
+
+// VariantPrompt returns the instructions configured for the session's variant
+func (m *Manager) VariantPrompt(session *types.Session) types.VariantPrompt {
+	if m.projectConfig == nil {
+		return types.VariantPrompt{}
+	}
+	return m.projectConfig.Session.Prompts[Variant(session)]
+}
+
+// LaunchArgs returns the Claude arguments a session launches with, including the system prompt
+// of its variant and the session context
+func (m *Manager) LaunchArgs(session *types.Session) []string {
+	args := ClaudeArgs(session, m.defaultClaudeArgs)
+	if prompt := m.systemPrompt(session); prompt != "" {
+		args = append(args, "--append-system-prompt", prompt)
+	}
+	return args
+}
+
+// PrepareLaunch writes the CLAUDE.md instructions of the session's variant into the Kamui block of
+// CLAUDE.md in its working directory, or removes the block when the variant has none
+func (m *Manager) PrepareLaunch(session *types.Session) error {
+	claudeMD := filepath.Join(session.Project.WorkingDirectory, "CLAUDE.md")
+	if body := m.VariantPrompt(session).ClaudeMD; body != "" {
+		if err := fence.UpsertFile(claudeMD, claudeMDMarkers, body, 0o644); err != nil {
+			return fmt.Errorf("failed to update %s: %w", claudeMD, err)
+		}
+		return nil
+	}
+	if _, err := fence.RemoveFromFile(claudeMD, claudeMDMarkers); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to update %s: %w", claudeMD, err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package session
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func newPromptsManager(t *testing.T) (*Manager, *MockClaudeClient, string) {
+	t.Helper()
+	tempDir := t.TempDir()
+	mockClient := &MockClaudeClient{}
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	manager, err := NewWithDependencies(tempDir, testStorage, mockClient)
+	require.NoError(t, err)
+	manager.SetProjectConfig(&types.ProjectConfig{
+		Project: types.ProjectConfigInfo{DefaultSessionVariant: "main"},
+		Session: types.SessionProjectConfig{
+			Variants: []string{"main", "review"},
+			Prompts: map[string]types.VariantPrompt{
+				"review": {SystemPrompt: "Only review, never edit.", ClaudeMD: "# Reviewer\nFocus on correctness."},
+			},
+		},
+	})
+	return manager, mockClient, tempDir
+}
+
+func TestVariant(t *testing.T) {
+	session := &types.Session{}
+	assert.Equal(t, DefaultVariant, Variant(session))
+
+	session.Metadata.Variant = "review"
+	assert.Equal(t, "review", Variant(session))
+}
+
+func TestSetVariant(t *testing.T) {
+	manager, _, _ := newPromptsManager(t)
+
+	session, err := manager.CreateSession(context.Background(), "api", "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "main", session.Metadata.Variant, "new sessions get the project's default variant")
+
+	require.NoError(t, manager.SetVariant(context.Background(), "api", "review"))
+	session, err = manager.GetSession(context.Background(), "api")
+	require.NoError(t, err)
+	assert.Equal(t, "review", session.Metadata.Variant)
+
+	var agxErr *types.AGXError
+	require.ErrorAs(t, manager.SetVariant(context.Background(), "api", "debug"), &agxErr)
+	assert.Equal(t, types.ErrCodeInvalidInput, agxErr.Code)
+}
+
+func TestLaunchArgs(t *testing.T) {
+	manager, _, _ := newPromptsManager(t)
+	manager.SetDefaultClaudeArgs([]string{"--verbose"})
+
+	session := &types.Session{SessionID: "api"}
+	assert.Equal(t, []string{"--verbose"}, manager.LaunchArgs(session))
+
+	session.Metadata.Variant = "review"
+	assert.Equal(t, []string{"--verbose", "--append-system-prompt", "Only review, never edit."}, manager.LaunchArgs(session))
+	assert.Equal(t, "claude --verbose --append-system-prompt 'Only review, never edit.'", manager.GetClaudeCommand(session))
+}
+
+func TestPrepareLaunch(t *testing.T) {
+	manager, mockClient, tempDir := newPromptsManager(t)
+	claudeMD := filepath.Join(tempDir, "CLAUDE.md")
+	require.NoError(t, os.WriteFile(claudeMD, []byte("# Project\n"), 0o644))
+
+	_, err := manager.CreateSession(context.Background(), "reviewer", "", nil)
+	require.NoError(t, err)
+	require.NoError(t, manager.SetVariant(context.Background(), "reviewer", "review"))
+
+	mockClient.On("LaunchClaudeInteractively", tempDir, "reviewer", []string{"--append-system-prompt", "Only review, never edit."}).Return(nil)
+	session, _, err := manager.CreateOrResumeSession(context.Background(), "reviewer")
+	require.NoError(t, err)
+	mockClient.AssertExpectations(t)
+
+	data, err := os.ReadFile(claudeMD)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# Project\n")
+	assert.Contains(t, string(data), "# Reviewer\nFocus on correctness.\n")
+
+	// A variant without CLAUDE.md instructions takes the block out again
+	session.Metadata.Variant = "main"
+	require.NoError(t, manager.PrepareLaunch(session))
+	data, err = os.ReadFile(claudeMD)
+	require.NoError(t, err)
+	assert.Equal(t, "# Project\n", string(data))
+
+	// and leaves a project without CLAUDE.md alone
+	require.NoError(t, os.Remove(claudeMD))
+	require.NoError(t, manager.PrepareLaunch(session))
+	assert.NoFileExists(t, claudeMD)
+}
@@ -0,0 +1,103 @@
+package session
+
+import (
+	"context"
+	"sort"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Reasons recorded in the state history when a session is archived by hand and launched again
+const (
+	reasonArchived = "manually_archived"
+	reasonReopened = "reopened"
+)
+
+// maxQuotaCandidates bounds how many sessions a quota report suggests archiving
+const maxQuotaCandidates = 5
+
+// QuotaReport describes a project that has reached its session quota
+type QuotaReport struct {
+	Max   int // session.maxSessions from the project config
+	Count int // sessions counting against it: all but the archived ones
+
+	// Candidates are the sessions to archive first, least recently used first; pinned sessions
+	// count against the quota but are never suggested
+	Candidates []*types.Session
+}
+
+// CheckQuota reports whether creating another session would go over the project's
+// session.maxSessions, suggesting enough sessions to archive to make room
+// It returns nil when the project has no quota or room is left
+func (m *Manager) CheckQuota(ctx context.Context) (*QuotaReport, error) {
+	if m.projectConfig == nil || m.projectConfig.Session.MaxSessions <= 0 {
+		return nil, nil
+	}
+
+	sessions, err := m.ListProjectSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var counted []*types.Session
+	for _, session := range sessions {
+		if session.Lifecycle.State != types.SessionStateArchived {
+			counted = append(counted, session)
+		}
+	}
+	maxSessions := m.projectConfig.Session.MaxSessions
+	if len(counted) < maxSessions {
+		return nil, nil
+	}
+
+	var candidates []*types.Session
+	for _, session := range counted {
+		if !session.Metadata.Pinned {
+			candidates = append(candidates, session)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].LastAccessed.Before(candidates[j].LastAccessed)
+	})
+	needed := min(len(counted)-maxSessions+1, maxQuotaCandidates, len(candidates))
+	return &QuotaReport{Max: maxSessions, Count: len(counted), Candidates: candidates[:needed]}, nil
+}
+
+// InactiveSessions returns the project's sessions that cleanup considers inactive, least recently
+// used first; archived and pinned sessions are never returned
+func (m *Manager) InactiveSessions(ctx context.Context, cleanup types.CleanupConfig) ([]*types.Session, error) {
+	sessions, err := m.ListProjectSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := m.clock.Now()
+	var inactive []*types.Session
+	for _, session := range sessions {
+		if session.Lifecycle.State == types.SessionStateArchived || session.Metadata.Pinned {
+			continue
+		}
+		if cleanup.IsInactive(session.LastAccessed, now) {
+			inactive = append(inactive, session)
+		}
+	}
+	sort.SliceStable(inactive, func(i, j int) bool {
+		return inactive[i].LastAccessed.Before(inactive[j].LastAccessed)
+	})
+	return inactive, nil
+}
+
+// ArchiveSession moves a session to the archived state, where it no longer counts against the
+// project's session quota; launching it again works as before
+func (m *Manager) ArchiveSession(ctx context.Context, sessionName string) error {
+	session, err := m.loadSession(ctx, sessionName)
+	if err != nil {
+		return err
+	}
+	if session.Lifecycle.State == types.SessionStateArchived {
+		return nil
+	}
+
+	if err := session.ApplyTransition(types.SessionStateArchived, reasonArchived, m.clock.Now()); err != nil {
+		return err
+	}
+	return m.saveSession(ctx, session)
+}
@@ -0,0 +1,126 @@
+package session
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/clock"
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestCheckQuota(t *testing.T) {
+	manager, testStorage := newNamesTestManager(t)
+	ctx := context.Background()
+
+	base := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	for i, name := range []string{"recent", "newer", "older", "oldest"} {
+		session, err := manager.newSession(name)
+		require.NoError(t, err)
+		session.LastAccessed = base.Add(-time.Duration(i*i) * 24 * time.Hour)
+		require.NoError(t, manager.saveSession(ctx, session))
+	}
+
+	// without a quota nothing is reported
+	report, err := manager.CheckQuota(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, report)
+
+	manager.SetProjectConfig(&types.ProjectConfig{Session: types.SessionProjectConfig{MaxSessions: 5}})
+	report, err = manager.CheckQuota(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, report)
+
+	// at the limit, one session has to go for a new one to fit; over it, more
+	manager.SetProjectConfig(&types.ProjectConfig{Session: types.SessionProjectConfig{MaxSessions: 4}})
+	report, err = manager.CheckQuota(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	assert.Equal(t, 4, report.Count)
+	assert.Equal(t, []string{"oldest"}, sessionIDs(report.Candidates))
+
+	manager.SetProjectConfig(&types.ProjectConfig{Session: types.SessionProjectConfig{MaxSessions: 2}})
+	report, err = manager.CheckQuota(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"oldest", "older", "newer"}, sessionIDs(report.Candidates))
+
+	// pinned sessions count but are never suggested
+	require.NoError(t, manager.SetPinned(ctx, "older", true))
+	report, err = manager.CheckQuota(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.Count)
+	assert.Equal(t, []string{"oldest", "newer", "recent"}, sessionIDs(report.Candidates))
+	require.NoError(t, manager.SetPinned(ctx, "older", false))
+
+	// archived sessions don't count
+	require.NoError(t, manager.ArchiveSession(ctx, "oldest"))
+	require.NoError(t, manager.ArchiveSession(ctx, "older"))
+	report, err = manager.CheckQuota(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	assert.Equal(t, 2, report.Count)
+	assert.Equal(t, []string{"newer"}, sessionIDs(report.Candidates))
+
+	saved, err := testStorage.LoadSession(ctx, "older")
+	require.NoError(t, err)
+	assert.Equal(t, types.SessionStateArchived, saved.Lifecycle.State)
+	assert.Equal(t, reasonArchived, saved.Lifecycle.StateHistory[len(saved.Lifecycle.StateHistory)-1].Reason)
+}
+
+func TestInactiveSessions(t *testing.T) {
+	manager, _ := newNamesTestManager(t)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	manager.SetClock(clock.NewFake(now))
+
+	for name, idle := range map[string]int{"today": 0, "stale": 40, "staler": 90, "pinned": 60, "archived": 50} {
+		session, err := manager.newSession(name)
+		require.NoError(t, err)
+		session.LastAccessed = now.Add(-time.Duration(idle) * types.Day)
+		require.NoError(t, manager.saveSession(ctx, session))
+	}
+	require.NoError(t, manager.SetPinned(ctx, "pinned", true))
+	require.NoError(t, manager.ArchiveSession(ctx, "archived"))
+
+	inactive, err := manager.InactiveSessions(ctx, types.CleanupConfig{Enabled: true, InactiveThreshold: types.Duration(30 * types.Day)})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"staler", "stale"}, sessionIDs(inactive))
+
+	inactive, err = manager.InactiveSessions(ctx, types.CleanupConfig{InactiveThreshold: types.Duration(30 * types.Day)})
+	require.NoError(t, err)
+	assert.Empty(t, inactive, "nothing is inactive while cleanup is disabled")
+}
+
+func TestArchivedSessionReopensOnLaunch(t *testing.T) {
+	tempDir := t.TempDir()
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+	claudeClient := &MockClaudeClient{}
+	manager, err := NewWithDependencies(tempDir, testStorage, claudeClient)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	session, err := manager.newSession("api")
+	require.NoError(t, err)
+	session.Claude.SessionID = "conv-1"
+	require.NoError(t, manager.saveSession(ctx, session))
+	require.NoError(t, manager.ArchiveSession(ctx, "api"))
+
+	claudeClient.On("HasSession", "conv-1", session.Project.WorkingDirectory).Return(true, nil)
+	resumed, _, err := manager.CreateOrResumeSession(ctx, "api")
+	require.NoError(t, err)
+	assert.Equal(t, types.SessionStateActive, resumed.Lifecycle.State)
+	assert.Equal(t, reasonReopened, resumed.Lifecycle.StateHistory[len(resumed.Lifecycle.StateHistory)-1].Reason)
+}
+
+func sessionIDs(sessions []*types.Session) []string {
+	ids := make([]string, len(sessions))
+	for i, session := range sessions {
+		ids[i] = session.SessionID
+	}
+	return ids
+}
@@ -0,0 +1,86 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/bitomule/kamui/internal/adopt"
+	"github.com/bitomule/kamui/internal/paths"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// maxResumeHistory bounds the resume errors and fallbacks kept on a session
+const maxResumeHistory = 20
+
+// ResumeCandidates returns the conversations a session whose own conversation won't resume can fall
+// back to: those in its working directory not bound to any session, oldest first
+func (m *Manager) ResumeCandidates(ctx context.Context, sessionName string) ([]adopt.Candidate, error) {
+	session, err := m.loadSession(ctx, sessionName)
+	if err != nil {
+		return nil, err
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	bound, err := adopt.BoundSessions(ctx, m.storage)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := adopt.ScanProject(ctx, paths.ClaudeProjectDir(homeDir, session.Project.WorkingDirectory), bound)
+	if err != nil {
+		return nil, err
+	}
+	return result.Candidates, nil
+}
+
+// ApplyResumeFallback resolves a failed resume: with ResumeChoiceNewest or ResumeChoicePicked the
+// session is bound to claudeSessionID, with ResumeChoiceFresh it is unbound so the next launch starts
+// a new conversation. The failure and the choice are recorded in the session's ResumeInfo
+func (m *Manager) ApplyResumeFallback(ctx context.Context, sessionName string, choice types.ResumeChoice, claudeSessionID, reason string) error {
+	switch {
+	case choice == types.ResumeChoiceFresh:
+		claudeSessionID = ""
+	case choice != types.ResumeChoiceNewest && choice != types.ResumeChoicePicked:
+		return types.NewSessionError(types.ErrCodeInvalidInput, fmt.Sprintf("unknown resume fallback %q", choice), nil)
+	case claudeSessionID == "":
+		return types.NewSessionError(types.ErrCodeInvalidInput, "no Claude conversation to fall back to", nil)
+	}
+
+	session, err := m.loadSession(ctx, sessionName)
+	if err != nil {
+		return err
+	}
+
+	now := m.clock.Now()
+	resume := &session.Claude.ResumeInfo
+	resume.LastResumeAttempt = &now
+	if reason != "" {
+		resume.ResumeErrors = lastN(append(resume.ResumeErrors, reason), maxResumeHistory)
+	}
+	resume.Fallbacks = lastN(append(resume.Fallbacks, types.ResumeFallback{
+		Time:   now,
+		Choice: choice,
+		From:   session.Claude.SessionID,
+		To:     claudeSessionID,
+		Reason: reason,
+	}), maxResumeHistory)
+
+	session.Claude.SessionID = claudeSessionID
+	resume.CanResume = claudeSessionID != ""
+	resume.ResumeCommand = ""
+	session.LastModified = now
+
+	return m.saveSession(ctx, session)
+}
+
+// lastN returns the last n items
+func lastN[T any](items []T, n int) []T {
+	if len(items) > n {
+		return items[len(items)-n:]
+	}
+	return items
+}
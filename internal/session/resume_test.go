@@ -0,0 +1,99 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/paths"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestApplyResumeFallback(t *testing.T) {
+	manager, testStorage := newNamesTestManager(t)
+	ctx := context.Background()
+
+	session, err := manager.CreateSession(ctx, "api", "", nil)
+	require.NoError(t, err)
+	session.Claude.SessionID = "claude-gone"
+	require.NoError(t, testStorage.SaveSession(ctx, session))
+
+	require.NoError(t, manager.ApplyResumeFallback(ctx, "api", types.ResumeChoiceNewest, "claude-new", "conversation no longer exists"))
+	saved, err := manager.GetSession(ctx, "api")
+	require.NoError(t, err)
+	assert.Equal(t, "claude-new", saved.Claude.SessionID)
+	assert.True(t, saved.Claude.ResumeInfo.CanResume)
+	assert.NotNil(t, saved.Claude.ResumeInfo.LastResumeAttempt)
+	assert.Equal(t, []string{"conversation no longer exists"}, saved.Claude.ResumeInfo.ResumeErrors)
+	require.Len(t, saved.Claude.ResumeInfo.Fallbacks, 1)
+	fallback := saved.Claude.ResumeInfo.Fallbacks[0]
+	assert.Equal(t, types.ResumeChoiceNewest, fallback.Choice)
+	assert.Equal(t, "claude-gone", fallback.From)
+	assert.Equal(t, "claude-new", fallback.To)
+
+	require.NoError(t, manager.ApplyResumeFallback(ctx, "api", types.ResumeChoiceFresh, "ignored", ""))
+	saved, err = manager.GetSession(ctx, "api")
+	require.NoError(t, err)
+	assert.Empty(t, saved.Claude.SessionID)
+	assert.False(t, saved.Claude.ResumeInfo.CanResume)
+	require.Len(t, saved.Claude.ResumeInfo.Fallbacks, 2)
+	assert.Empty(t, saved.Claude.ResumeInfo.Fallbacks[1].To)
+}
+
+func TestApplyResumeFallback_RejectsBadChoices(t *testing.T) {
+	manager, _ := newNamesTestManager(t)
+	ctx := context.Background()
+	_, err := manager.CreateSession(ctx, "api", "", nil)
+	require.NoError(t, err)
+
+	var agxErr *types.AGXError
+	require.ErrorAs(t, manager.ApplyResumeFallback(ctx, "api", types.ResumeChoicePicked, "", ""), &agxErr)
+	assert.Equal(t, types.ErrCodeInvalidInput, agxErr.Code)
+	require.ErrorAs(t, manager.ApplyResumeFallback(ctx, "api", "sideways", "claude-1", ""), &agxErr)
+	assert.Equal(t, types.ErrCodeInvalidInput, agxErr.Code)
+}
+
+func TestApplyResumeFallback_KeepsRecentHistory(t *testing.T) {
+	manager, _ := newNamesTestManager(t)
+	ctx := context.Background()
+	_, err := manager.CreateSession(ctx, "api", "", nil)
+	require.NoError(t, err)
+
+	for i := 0; i < maxResumeHistory+5; i++ {
+		require.NoError(t, manager.ApplyResumeFallback(ctx, "api", types.ResumeChoicePicked, fmt.Sprintf("claude-%d", i), "failed"))
+	}
+	saved, err := manager.GetSession(ctx, "api")
+	require.NoError(t, err)
+	assert.Len(t, saved.Claude.ResumeInfo.Fallbacks, maxResumeHistory)
+	assert.Len(t, saved.Claude.ResumeInfo.ResumeErrors, maxResumeHistory)
+	assert.Equal(t, "claude-24", saved.Claude.ResumeInfo.Fallbacks[maxResumeHistory-1].To)
+}
+
+func TestResumeCandidates(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	manager, testStorage := newNamesTestManager(t)
+	ctx := context.Background()
+
+	session, err := manager.CreateSession(ctx, "api", "", nil)
+	require.NoError(t, err)
+	session.Claude.SessionID = "claude-bound"
+	require.NoError(t, testStorage.SaveSession(ctx, session))
+
+	dir := paths.ClaudeProjectDir(home, session.Project.WorkingDirectory)
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	for _, id := range []string{"claude-bound", "claude-free"} {
+		line := fmt.Sprintf(`{"type":"user","sessionId":%q,"timestamp":"2025-03-10T09:00:00Z","message":{"role":"user","content":"hello"}}`+"\n", id)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, id+".jsonl"), []byte(line), 0o600))
+	}
+
+	candidates, err := manager.ResumeCandidates(ctx, "api")
+	require.NoError(t, err)
+	require.Len(t, candidates, 1)
+	assert.Equal(t, "claude-free", candidates[0].ClaudeSessionID)
+}
@@ -0,0 +1,91 @@
+package session
+
+import (
+	"time"
+
+	"github.com/bitomule/kamui/internal/timesheet"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// SetTimesheet sets where a session's runs are read from when it is completed; without one,
+// completion records no durations
+func (m *Manager) SetTimesheet(store *timesheet.Store) {
+	m.timesheet = store
+}
+
+// RunTotals sums the interactive runs of a session
+type RunTotals struct {
+	Runs    int
+	Total   time.Duration
+	Average time.Duration
+	// Last is the duration of the run that ended last
+	Last time.Duration
+	// MostActiveDay is the day, as 2006-01-02, the most time was spent in the session
+	MostActiveDay string
+}
+
+// runTotals sums the timesheet entries of a session; session IDs are global, so the ID alone
+// picks its runs whatever project they were recorded under
+func (m *Manager) runTotals(sessionID string) (RunTotals, error) {
+	if m.timesheet == nil {
+		return RunTotals{}, nil
+	}
+	entries, err := m.timesheet.Entries()
+	if err != nil {
+		return RunTotals{}, err
+	}
+	var runs []timesheet.Entry
+	for _, entry := range entries {
+		if entry.SessionID == sessionID {
+			runs = append(runs, entry)
+		}
+	}
+	return SumRuns(runs), nil
+}
+
+// SumRuns totals runs; days are counted in local time by when each run started
+func SumRuns(runs []timesheet.Entry) RunTotals {
+	var totals RunTotals
+	var lastEnd time.Time
+	perDay := map[string]time.Duration{}
+	for _, run := range runs {
+		duration := run.Duration()
+		if duration <= 0 {
+			continue
+		}
+		totals.Runs++
+		totals.Total += duration
+		if run.End.After(lastEnd) {
+			lastEnd, totals.Last = run.End, duration
+		}
+		perDay[run.Start.Local().Format("2006-01-02")] += duration
+	}
+	if totals.Runs == 0 {
+		return totals
+	}
+	totals.Average = totals.Total / time.Duration(totals.Runs)
+	for day, duration := range perDay {
+		most := perDay[totals.MostActiveDay]
+		if duration > most || (duration == most && day > totals.MostActiveDay) {
+			totals.MostActiveDay = day
+		}
+	}
+	return totals
+}
+
+// apply writes the totals into a session's statistics; sessions without runs keep theirs
+func (t RunTotals) apply(stats *types.SessionStats) {
+	if t.Runs == 0 {
+		return
+	}
+	stats.SessionCount = t.Runs
+	stats.TotalDuration = formatRunDuration(t.Total)
+	stats.AverageSessionLength = formatRunDuration(t.Average)
+	stats.LastSessionDuration = formatRunDuration(t.Last)
+	stats.MostActiveDay = t.MostActiveDay
+}
+
+// formatRunDuration writes a run duration to the second, e.g. "1h30m15s"
+func formatRunDuration(d time.Duration) string {
+	return types.Duration(d.Round(time.Second)).String()
+}
@@ -0,0 +1,85 @@
+package session
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/clock"
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/internal/timesheet"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestSumRuns(t *testing.T) {
+	day1 := time.Date(2025, 3, 10, 9, 0, 0, 0, time.Local)
+	day2 := day1.AddDate(0, 0, 1)
+	runs := []timesheet.Entry{
+		{SessionID: "a", Start: day1, End: day1.Add(30 * time.Minute)},
+		{SessionID: "a", Start: day2, End: day2.Add(45 * time.Minute)},
+		{SessionID: "a", Start: day1.Add(2 * time.Hour), End: day1.Add(2*time.Hour + 20*time.Minute)},
+		{SessionID: "a", Start: day2, End: day2}, // empty runs don't count
+	}
+
+	totals := SumRuns(runs)
+	assert.Equal(t, 3, totals.Runs)
+	assert.Equal(t, 95*time.Minute, totals.Total)
+	assert.Equal(t, 95*time.Minute/3, totals.Average)
+	assert.Equal(t, 45*time.Minute, totals.Last)
+	assert.Equal(t, "2025-03-10", totals.MostActiveDay)
+
+	assert.Equal(t, RunTotals{}, SumRuns(nil))
+}
+
+func TestCompleteSession_RecordsRuns(t *testing.T) {
+	tempDir := t.TempDir()
+	ctx := context.Background()
+	testStorage := storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+	manager, err := NewWithDependencies(tempDir, testStorage, &MockClaudeClient{})
+	require.NoError(t, err)
+
+	completedAt := time.Date(2025, 3, 12, 18, 0, 0, 0, time.UTC)
+	manager.SetClock(clock.NewFake(completedAt))
+	sheet := timesheet.NewStoreWithPath(filepath.Join(tempDir, "timesheet.jsonl"))
+	manager.SetTimesheet(sheet)
+
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, sheet.Record(timesheet.Entry{SessionID: "feature", Start: start, End: start.Add(time.Hour)}))
+	require.NoError(t, sheet.Record(timesheet.Entry{SessionID: "feature", Start: start.Add(24 * time.Hour), End: start.Add(24*time.Hour + 30*time.Minute)}))
+	require.NoError(t, sheet.Record(timesheet.Entry{SessionID: "other", Start: start, End: start.Add(5 * time.Hour)}))
+
+	session, err := testStorage.CreateSession("feature", tempDir)
+	require.NoError(t, err)
+	// a stale modification time must not end up as the completion time
+	session.LastModified = start.Add(-48 * time.Hour)
+	require.NoError(t, testStorage.SaveSession(ctx, session))
+
+	require.NoError(t, manager.CompleteSession(ctx, "feature"))
+
+	completed, err := manager.GetSession(ctx, "feature")
+	require.NoError(t, err)
+	history := completed.Lifecycle.StateHistory
+	last := history[len(history)-1]
+	assert.Equal(t, types.SessionStateCompleted, last.State)
+	assert.True(t, last.Timestamp.Equal(completedAt))
+	assert.Equal(t, types.Duration(90*time.Minute), last.Duration)
+
+	require.NotNil(t, completed.Stats.CompletedAt)
+	assert.True(t, completed.Stats.CompletedAt.Equal(completedAt))
+	assert.Equal(t, 2, completed.Stats.SessionCount)
+	assert.Equal(t, "1h30m", completed.Stats.TotalDuration)
+	assert.Equal(t, "45m", completed.Stats.AverageSessionLength)
+	assert.Equal(t, "30m", completed.Stats.LastSessionDuration)
+
+	// completing it again changes nothing
+	manager.SetClock(clock.NewFake(completedAt.Add(time.Hour)))
+	require.NoError(t, manager.CompleteSession(ctx, "feature"))
+	again, err := manager.GetSession(ctx, "feature")
+	require.NoError(t, err)
+	assert.Len(t, again.Lifecycle.StateHistory, len(history))
+	assert.True(t, again.Stats.CompletedAt.Equal(completedAt))
+}
@@ -0,0 +1,105 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// slugMaxWords is the number of significant words kept in a generated session name
+const slugMaxWords = 3
+
+// slugStopWords are skipped when building a slug from a description
+var slugStopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "or": true, "of": true,
+	"on": true, "in": true, "at": true, "to": true, "for": true, "with": true,
+	"from": true, "by": true, "is": true, "it": true, "into": true,
+}
+
+// Slugify derives a short session name from free text
+// Accents are stripped, stop words skipped and the first significant words joined with "-"
+// e.g. "Investigate flaky CI on main" becomes "investigate-flaky-ci"
+func Slugify(text string) string {
+	var folded strings.Builder
+	for _, r := range norm.NFD.String(text) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// drop combining marks left over from decomposed accents
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			folded.WriteRune(unicode.ToLower(r))
+		default:
+			folded.WriteRune(' ')
+		}
+	}
+
+	words := strings.Fields(folded.String())
+	var significant []string
+	for _, word := range words {
+		if !slugStopWords[word] {
+			significant = append(significant, word)
+		}
+	}
+	if len(significant) == 0 {
+		significant = words
+	}
+	if len(significant) > slugMaxWords {
+		significant = significant[:slugMaxWords]
+	}
+
+	return strings.Join(significant, "-")
+}
+
+// CreateSessionFromDescription creates a session named after a slug of its description
+// A numeric suffix is added when the slug is already taken
+func (m *Manager) CreateSessionFromDescription(ctx context.Context, description string, tags []string) (*types.Session, error) {
+	slug := Slugify(description)
+	if slug == "" {
+		return nil, types.NewSessionError(
+			types.ErrCodeInvalidInput,
+			"description must contain at least one letter or digit",
+			nil,
+		)
+	}
+
+	sessionName, err := m.availableName(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	return m.CreateSession(ctx, sessionName, strings.TrimSpace(description), tags)
+}
+
+// maxNameSuffix bounds the numeric suffixes availableName tries
+const maxNameSuffix = 100
+
+// availableName returns slug, or slug with the first numeric suffix that isn't taken
+// Errors other than a name collision, such as storage that can't be listed, are returned as is
+func (m *Manager) availableName(ctx context.Context, slug string) (string, error) {
+	for i := 1; i <= maxNameSuffix; i++ {
+		sessionName := slug
+		if i > 1 {
+			sessionName = fmt.Sprintf("%s-%d", slug, i)
+		}
+		if err := types.ContextError(ctx); err != nil {
+			return "", err
+		}
+		resolved, err := m.resolveName(ctx, sessionName)
+		var agxErr *types.AGXError
+		switch {
+		case err == nil && !m.storage.SessionExists(ctx, resolved):
+			return sessionName, nil
+		case err != nil && (!errors.As(err, &agxErr) || agxErr.Code != types.ErrCodeSessionExists):
+			return "", err
+		}
+	}
+	return "", types.NewSessionError(
+		types.ErrCodeSessionExists,
+		fmt.Sprintf("sessions '%s' to '%s-%d' already exist; pick a name yourself", slug, slug, maxNameSuffix),
+		nil,
+	)
+}
@@ -0,0 +1,66 @@
+package session
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestSlugify(t *testing.T) {
+	testCases := map[string]string{
+		"Investigate flaky CI on main":      "investigate-flaky-ci",
+		"Fix the login bug":                 "fix-login-bug",
+		"  Refactor: auth/session handling": "refactor-auth-session",
+		"Cafe\u0301 menu redesign":          "cafe-menu-redesign",
+		"The":                               "the",
+		"v2 migration":                      "v2-migration",
+		"!!!":                               "",
+	}
+
+	for input, expected := range testCases {
+		assert.Equal(t, expected, Slugify(input), input)
+	}
+}
+
+func TestCreateSessionFromDescription(t *testing.T) {
+	manager, _ := newNamesTestManager(t)
+
+	first, err := manager.CreateSessionFromDescription(context.Background(), "Investigate flaky CI on main", []string{"ci"})
+	require.NoError(t, err)
+	assert.Equal(t, "investigate-flaky-ci", first.SessionID)
+	assert.Equal(t, "Investigate flaky CI on main", first.Metadata.Description)
+	assert.Equal(t, []string{"ci"}, first.Metadata.Tags)
+
+	second, err := manager.CreateSessionFromDescription(context.Background(), "Investigate flaky CI on release", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "investigate-flaky-ci-2", second.SessionID)
+
+	_, err = manager.CreateSessionFromDescription(context.Background(), "???", nil)
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeInvalidInput, agxErr.Code)
+}
+
+func TestCreateSessionFromDescription_StopsOnErrors(t *testing.T) {
+	manager, _ := newNamesTestManager(t)
+
+	// a canceled command gives up instead of trying suffixes forever
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := manager.CreateSessionFromDescription(ctx, "Investigate flaky CI", nil)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// so does a name every suffix of which is taken
+	for i := 0; i < maxNameSuffix; i++ {
+		_, err := manager.CreateSessionFromDescription(context.Background(), "Fix docs", nil)
+		require.NoError(t, err)
+	}
+	_, err = manager.CreateSessionFromDescription(context.Background(), "Fix docs", nil)
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeSessionExists, agxErr.Code)
+}
@@ -0,0 +1,97 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/bitomule/kamui/internal/transcript"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// TranscriptPath returns the path of the session's bound Claude transcript
+func (m *Manager) TranscriptPath(ctx context.Context, sessionName string) (*types.Session, string, error) {
+	session, err := m.loadSession(ctx, sessionName)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if session.Claude.SessionID == "" {
+		return nil, "", types.NewClaudeError(
+			types.ErrCodeClaudeSessionNotFound,
+			fmt.Sprintf("session '%s' has no Claude conversation yet", session.SessionID),
+			nil,
+		)
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return session, transcript.Path(session, homeDir), nil
+}
+
+// TrimTranscript truncates the session's Claude transcript to its last keepLast exchanges
+// The original transcript is backed up in the session's backups directory first
+// Protected sessions are only trimmed when forced
+func (m *Manager) TrimTranscript(ctx context.Context, sessionName string, keepLast int, force bool) (*transcript.TrimResult, error) {
+	session, path, err := m.TranscriptPath(ctx, sessionName)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkProtected(session, force, "trim"); err != nil {
+		return nil, err
+	}
+
+	return transcript.TrimFile(ctx, path, m.storage.GetBackupsPath(session.SessionID), keepLast, m.clock.Now())
+}
+
+// CheckTranscript checks the session's bound transcript before it is resumed
+func (m *Manager) CheckTranscript(ctx context.Context, session *types.Session) (transcript.Health, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return transcript.Health{}, err
+	}
+	path := transcript.Path(session, homeDir)
+	return transcript.CheckHealth(ctx, path, session.Claude.SessionID, session.Project.WorkingDirectory)
+}
+
+// RepairTranscript drops the broken last line of the session's transcript, backing it up first
+// It returns the backup's path
+func (m *Manager) RepairTranscript(ctx context.Context, sessionName string) (string, error) {
+	session, path, err := m.TranscriptPath(ctx, sessionName)
+	if err != nil {
+		return "", err
+	}
+	return transcript.RepairFile(ctx, path, m.storage.GetBackupsPath(session.SessionID), m.clock.Now())
+}
+
+// ForkClaudeSession copies the session's Claude conversation into a new one and binds the session to
+// it, so a session sharing its conversation with another continues on its own copy
+// It returns the new conversation's ID
+func (m *Manager) ForkClaudeSession(ctx context.Context, sessionName string) (string, error) {
+	session, path, err := m.TranscriptPath(ctx, sessionName)
+	if err != nil {
+		return "", err
+	}
+
+	claudeSessionID, err := transcript.NewSessionID()
+	if err != nil {
+		return "", err
+	}
+	forkPath, err := transcript.ForkFile(ctx, path, claudeSessionID)
+	if err != nil {
+		return "", err
+	}
+
+	session.Claude.SessionID = claudeSessionID
+	session.Claude.ResumeInfo.CanResume = true
+	session.Claude.ResumeInfo.ResumeCommand = ""
+	session.LastModified = m.clock.Now()
+	if err := m.saveSession(ctx, session); err != nil {
+		os.Remove(forkPath)
+		return "", err
+	}
+	return claudeSessionID, nil
+}
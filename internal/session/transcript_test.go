@@ -0,0 +1,127 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/transcript"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestTrimTranscript(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	manager, testStorage := newNamesTestManager(t)
+
+	session, err := manager.CreateSession(context.Background(), "long", "", nil)
+	require.NoError(t, err)
+	session.Claude.SessionID = "claude-long"
+	require.NoError(t, testStorage.SaveSession(context.Background(), session))
+
+	_, path, err := manager.TranscriptPath(context.Background(), "long")
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+
+	var lines []string
+	for i := 0; i < 5; i++ {
+		lines = append(lines,
+			fmt.Sprintf(`{"type":"user","uuid":"u%d","message":{"role":"user","content":"prompt %d"}}`, i, i),
+			fmt.Sprintf(`{"type":"assistant","uuid":"a%d","parentUuid":"u%d","message":{"role":"assistant","content":[{"type":"text","text":"answer"}]}}`, i, i))
+	}
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600))
+
+	// Protected sessions need force
+	require.NoError(t, manager.SetProtected(context.Background(), "long", true))
+	_, err = manager.TrimTranscript(context.Background(), "long", 2, false)
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeSessionProtected, agxErr.Code)
+
+	result, err := manager.TrimTranscript(context.Background(), "long", 2, true)
+	require.NoError(t, err)
+	assert.Equal(t, 5, result.Exchanges)
+	assert.Equal(t, 2, result.KeptExchanges)
+	assert.Equal(t, testStorage.GetBackupsPath("long"), filepath.Dir(result.BackupPath))
+
+	records, err := transcript.ReadFile(context.Background(), path)
+	require.NoError(t, err)
+	assert.Len(t, records, 4)
+}
+
+func TestTrimTranscript_Unbound(t *testing.T) {
+	manager, _ := newNamesTestManager(t)
+
+	_, err := manager.CreateSession(context.Background(), "fresh", "", nil)
+	require.NoError(t, err)
+
+	_, err = manager.TrimTranscript(context.Background(), "fresh", 10, false)
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeClaudeSessionNotFound, agxErr.Code)
+}
+
+func TestCheckAndRepairTranscript(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	manager, testStorage := newNamesTestManager(t)
+
+	session, err := manager.CreateSession(context.Background(), "api", "", nil)
+	require.NoError(t, err)
+	session.Claude.SessionID = "claude-api"
+	require.NoError(t, testStorage.SaveSession(context.Background(), session))
+
+	_, path, err := manager.TranscriptPath(context.Background(), "api")
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	line := `{"type":"user","sessionId":"claude-api","message":{"role":"user","content":"hi"}}`
+	require.NoError(t, os.WriteFile(path, []byte(line+"\n"+line[:20]), 0o600))
+
+	health, err := manager.CheckTranscript(context.Background(), session)
+	require.NoError(t, err)
+	assert.True(t, health.Has(transcript.IssueTruncated))
+
+	backupPath, err := manager.RepairTranscript(context.Background(), "api")
+	require.NoError(t, err)
+	assert.Equal(t, testStorage.GetBackupsPath("api"), filepath.Dir(backupPath))
+
+	health, err = manager.CheckTranscript(context.Background(), session)
+	require.NoError(t, err)
+	assert.True(t, health.OK(), health.Summary())
+}
+
+func TestForkClaudeSession(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	manager, testStorage := newNamesTestManager(t)
+
+	for _, name := range []string{"api", "api-copy"} {
+		session, err := manager.CreateSession(context.Background(), name, "", nil)
+		require.NoError(t, err)
+		session.Claude.SessionID = "claude-api"
+		require.NoError(t, testStorage.SaveSession(context.Background(), session))
+	}
+
+	_, path, err := manager.TranscriptPath(context.Background(), "api")
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(`{"type":"user","sessionId":"claude-api","message":{"role":"user","content":"hi"}}`+"\n"), 0o600))
+
+	forkID, err := manager.ForkClaudeSession(context.Background(), "api-copy")
+	require.NoError(t, err)
+	assert.NotEqual(t, "claude-api", forkID)
+
+	forked, forkPath, err := manager.TranscriptPath(context.Background(), "api-copy")
+	require.NoError(t, err)
+	assert.Equal(t, forkID, forked.Claude.SessionID)
+	records, err := transcript.ReadFile(context.Background(), forkPath)
+	require.NoError(t, err)
+	assert.Equal(t, forkID, records[0].SessionID)
+
+	original, err := manager.GetSession(context.Background(), "api")
+	require.NoError(t, err)
+	assert.Equal(t, "claude-api", original.Claude.SessionID)
+}
@@ -0,0 +1,217 @@
+// Package snapshot records where a session's working tree stood before each run so it can be rolled back
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bitomule/kamui/internal/git"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Reasons a snapshot is taken
+const (
+	ReasonLaunch   = "launch"
+	ReasonRollback = "rollback"
+)
+
+// Snapshot is the state of a session's repository at one point: the commit checked out and,
+// optionally, a patch of the uncommitted changes to tracked files
+type Snapshot struct {
+	ID      int       `json:"id"`
+	Taken   time.Time `json:"taken"`
+	Reason  string    `json:"reason"`
+	Root    string    `json:"root"`
+	Commit  string    `json:"commit"`
+	Branch  string    `json:"branch,omitempty"`
+	Patch   string    `json:"patch,omitempty"`
+	Changes int64     `json:"changes,omitempty"`
+}
+
+// Store keeps each session's snapshot history in its own directory: history.json plus the patches
+type Store struct {
+	dir string
+}
+
+// NewStore creates a store under ~/.kamui/snapshots
+func NewStore() *Store {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+	return NewStoreWithDir(filepath.Join(homeDir, ".kamui", "snapshots"))
+}
+
+// NewStoreWithDir creates a store under the given directory
+func NewStoreWithDir(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Take records the state of the repository containing workingDir for a session, with a patch of
+// the uncommitted changes when includeDirty is set
+// It returns nil without recording anything when workingDir isn't in a git repository
+func (s *Store) Take(ctx context.Context, sessionName, workingDir, reason string, includeDirty bool, now time.Time) (*Snapshot, error) {
+	state, err := git.CurrentState(ctx, workingDir)
+	if err != nil || state == nil {
+		return nil, err
+	}
+
+	history, err := s.List(sessionName)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := Snapshot{
+		ID:     1,
+		Taken:  now,
+		Reason: reason,
+		Root:   state.Root,
+		Commit: state.Commit,
+		Branch: state.Branch,
+	}
+	if len(history) > 0 {
+		snapshot.ID = history[len(history)-1].ID + 1
+	}
+
+	if includeDirty {
+		patch, err := git.Diff(ctx, state.Root)
+		if err != nil {
+			return nil, err
+		}
+		if len(patch) > 0 {
+			snapshot.Patch = fmt.Sprintf("%d.patch", snapshot.ID)
+			snapshot.Changes = int64(len(patch))
+			if err := s.write(filepath.Join(s.sessionDir(sessionName), snapshot.Patch), patch); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := s.save(sessionName, append(history, snapshot)); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// List returns a session's snapshots, oldest first
+func (s *Store) List(sessionName string) ([]Snapshot, error) {
+	data, err := os.ReadFile(s.historyPath(sessionName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStoragePermission, "failed to read snapshot history", err)
+	}
+
+	var history []Snapshot
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStorageCorrupted, "failed to parse snapshot history", err)
+	}
+	return history, nil
+}
+
+// Get returns one of a session's snapshots
+func (s *Store) Get(sessionName string, id int) (*Snapshot, error) {
+	history, err := s.List(sessionName)
+	if err != nil {
+		return nil, err
+	}
+	for i := range history {
+		if history[i].ID == id {
+			return &history[i], nil
+		}
+	}
+	return nil, types.NewSessionError(types.ErrCodeInvalidInput, fmt.Sprintf("session '%s' has no snapshot %d", sessionName, id), nil)
+}
+
+// Prune drops all but a session's keep most recent snapshots
+func (s *Store) Prune(sessionName string, keep int) error {
+	history, err := s.List(sessionName)
+	if err != nil || keep <= 0 || len(history) <= keep {
+		return err
+	}
+
+	dropped := history[:len(history)-keep]
+	if err := s.save(sessionName, history[len(history)-keep:]); err != nil {
+		return err
+	}
+	for _, snapshot := range dropped {
+		if snapshot.Patch != "" {
+			_ = os.Remove(filepath.Join(s.sessionDir(sessionName), snapshot.Patch)) // a leftover patch is harmless
+		}
+	}
+	return nil
+}
+
+// Restore puts the repository's tracked files back the way they were when the snapshot was taken;
+// the checked out branch and untracked files are left alone
+func (s *Store) Restore(ctx context.Context, sessionName string, snapshot *Snapshot) error {
+	var patch []byte
+	if snapshot.Patch != "" {
+		var err error
+		patch, err = os.ReadFile(filepath.Join(s.sessionDir(sessionName), snapshot.Patch))
+		if err != nil {
+			return types.NewStorageError(types.ErrCodeStoragePermission, "failed to read snapshot patch", err)
+		}
+	}
+
+	if err := git.RestoreTree(ctx, snapshot.Root, snapshot.Commit); err != nil {
+		return err
+	}
+	if len(patch) > 0 {
+		if err := git.ApplyPatch(ctx, snapshot.Root, patch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rename files a session's snapshots under its new name; sessions without snapshots are left alone
+func (s *Store) Rename(oldName, newName string) error {
+	if _, err := os.Stat(s.sessionDir(oldName)); os.IsNotExist(err) {
+		return nil
+	}
+	if err := os.Rename(s.sessionDir(oldName), s.sessionDir(newName)); err != nil {
+		return types.NewStorageError(types.ErrCodeStoragePermission, "failed to rename snapshot directory", err)
+	}
+	return nil
+}
+
+// sessionDir returns the directory holding a session's snapshots
+func (s *Store) sessionDir(sessionName string) string {
+	return filepath.Join(s.dir, sessionName)
+}
+
+// historyPath returns the file listing a session's snapshots
+func (s *Store) historyPath(sessionName string) string {
+	return filepath.Join(s.sessionDir(sessionName), "history.json")
+}
+
+// save writes a session's snapshot history
+func (s *Store) save(sessionName string, history []Snapshot) error {
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return types.NewStorageError(types.ErrCodeStorageCorrupted, "failed to marshal snapshot history", err)
+	}
+	return s.write(s.historyPath(sessionName), data)
+}
+
+// write writes a file under the store atomically
+func (s *Store) write(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return types.NewStorageError(types.ErrCodeStoragePermission, "failed to create snapshot directory", err)
+	}
+
+	tempFile := path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0o600); err != nil {
+		return types.NewStorageError(types.ErrCodeStoragePermission, "failed to write snapshot file", err)
+	}
+	if err := os.Rename(tempFile, path); err != nil {
+		os.Remove(tempFile) // cleanup temp file
+		return types.NewStorageError(types.ErrCodeStoragePermission, "failed to save snapshot file", err)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package snapshot
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Ada", "GIT_AUTHOR_EMAIL=ada@example.com",
+		"GIT_COMMITTER_NAME=Ada", "GIT_COMMITTER_EMAIL=ada@example.com")
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+}
+
+// newRepo creates a repository with one committed file, a.txt
+func newRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one\n"), 0o644))
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-q", "-m", "first")
+	return dir
+}
+
+func TestTakeAndRestore(t *testing.T) {
+	ctx := context.Background()
+	repo := newRepo(t)
+	store := NewStoreWithDir(t.TempDir())
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "a.txt"), []byte("one\ndirty\n"), 0o644))
+	snapshot, err := store.Take(ctx, "api", repo, ReasonLaunch, true, now)
+	require.NoError(t, err)
+	require.NotNil(t, snapshot)
+	assert.Equal(t, 1, snapshot.ID)
+	assert.Equal(t, "1.patch", snapshot.Patch)
+	assert.Positive(t, snapshot.Changes)
+
+	clean, err := store.Take(ctx, "api", repo, ReasonLaunch, false, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 2, clean.ID)
+	assert.Empty(t, clean.Patch)
+
+	// Claude's run: a commit and further edits
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "b.txt"), []byte("b\n"), 0o644))
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-q", "-m", "second")
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "a.txt"), []byte("rewritten\n"), 0o644))
+
+	got, err := store.Get("api", 1)
+	require.NoError(t, err)
+	require.NoError(t, store.Restore(ctx, "api", got))
+	data, err := os.ReadFile(filepath.Join(repo, "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "one\ndirty\n", string(data))
+	assert.NoFileExists(t, filepath.Join(repo, "b.txt"))
+
+	history, err := store.List("api")
+	require.NoError(t, err)
+	assert.Len(t, history, 2)
+
+	_, err = store.Get("api", 7)
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeInvalidInput, agxErr.Code)
+}
+
+func TestTake_NotARepository(t *testing.T) {
+	store := NewStoreWithDir(t.TempDir())
+
+	snapshot, err := store.Take(context.Background(), "api", t.TempDir(), ReasonLaunch, true, time.Now())
+	require.NoError(t, err)
+	assert.Nil(t, snapshot)
+
+	history, err := store.List("api")
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func TestPrune(t *testing.T) {
+	ctx := context.Background()
+	repo := newRepo(t)
+	dir := t.TempDir()
+	store := NewStoreWithDir(dir)
+
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "a.txt"), []byte("dirty\n"), 0o644))
+	for i := 0; i < 3; i++ {
+		_, err := store.Take(ctx, "api", repo, ReasonLaunch, true, time.Now())
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, store.Prune("api", 2))
+	history, err := store.List("api")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, 2, history[0].ID)
+	assert.NoFileExists(t, filepath.Join(dir, "api", "1.patch"))
+	assert.FileExists(t, filepath.Join(dir, "api", "2.patch"))
+}
+
+func TestRename(t *testing.T) {
+	ctx := context.Background()
+	repo := newRepo(t)
+	store := NewStoreWithDir(t.TempDir())
+
+	_, err := store.Take(ctx, "api", repo, ReasonLaunch, false, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, store.Rename("api", "api-v2"))
+
+	history, err := store.List("api-v2")
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+	history, err = store.List("api")
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	require.NoError(t, store.Rename("missing", "other"), "sessions without snapshots have nothing to move")
+}
@@ -0,0 +1,264 @@
+// Package stats computes disk usage and session counts across all Kamui projects
+package stats
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/bitomule/kamui/internal/paths"
+	"github.com/bitomule/kamui/internal/pricing"
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/internal/transcript"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// SessionUsage is the storage footprint of one session
+type SessionUsage struct {
+	SessionID       string             `json:"sessionId"`
+	ProjectName     string             `json:"projectName"`
+	ProjectPath     string             `json:"projectPath"`
+	State           types.SessionState `json:"state"`
+	LastAccessed    time.Time          `json:"lastAccessed"`
+	MetadataBytes   int64              `json:"metadataBytes"`
+	BackupBytes     int64              `json:"backupBytes"`
+	TranscriptBytes int64              `json:"transcriptBytes"`
+	Tokens          int64              `json:"tokens"`
+	CostUSD         float64            `json:"estimatedCostUsd"`
+	UnpricedModels  []string           `json:"unpricedModels,omitempty"`
+}
+
+// TotalBytes returns the combined metadata, backup and transcript size
+func (s SessionUsage) TotalBytes() int64 {
+	return s.MetadataBytes + s.BackupBytes + s.TranscriptBytes
+}
+
+// ProjectUsage is the disk usage of one project's sessions
+type ProjectUsage struct {
+	ProjectName     string  `json:"projectName"`
+	ProjectPath     string  `json:"projectPath"`
+	Sessions        int     `json:"sessions"`
+	MetadataBytes   int64   `json:"metadataBytes"`
+	BackupBytes     int64   `json:"backupBytes"`
+	TranscriptBytes int64   `json:"transcriptBytes"`
+	Tokens          int64   `json:"tokens"`
+	CostUSD         float64 `json:"estimatedCostUsd"`
+}
+
+// TotalBytes returns the combined metadata, backup and transcript size
+func (p ProjectUsage) TotalBytes() int64 {
+	return p.MetadataBytes + p.BackupBytes + p.TranscriptBytes
+}
+
+// Report summarizes usage across all projects
+type Report struct {
+	Projects     []ProjectUsage   `json:"projects"`
+	TotalBytes   int64            `json:"totalBytes"`
+	TotalTokens  int64            `json:"totalTokens"`
+	TotalCostUSD float64          `json:"estimatedCostUsd"`
+	Index        types.IndexStats `json:"index"`
+}
+
+// CollectSessions computes the footprint of every stored session: its metadata file,
+// its backups and its bound Claude transcript
+// With a price table, token usage and estimated cost are computed from the transcripts too
+// Sessions are ordered by total size, largest first
+func CollectSessions(ctx context.Context, store storage.Interface, homeDir string, prices pricing.Table) ([]SessionUsage, error) {
+	names, err := store.ListSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	usages := make([]SessionUsage, 0, len(names))
+	for _, name := range names {
+		session, err := store.LoadSession(ctx, name)
+		if err != nil {
+			continue // unreadable sessions are reported by other tools, not counted here
+		}
+		usages = append(usages, CollectSession(ctx, store, session, homeDir, prices))
+	}
+
+	sort.SliceStable(usages, func(i, j int) bool {
+		if usages[i].TotalBytes() != usages[j].TotalBytes() {
+			return usages[i].TotalBytes() > usages[j].TotalBytes()
+		}
+		return usages[i].SessionID < usages[j].SessionID
+	})
+	return usages, nil
+}
+
+// CollectSession computes the footprint of one session loaded from store, like CollectSessions
+func CollectSession(ctx context.Context, store storage.Interface, session *types.Session, homeDir string, prices pricing.Table) SessionUsage {
+	projectPath := paths.Canonical(session.Project.Path)
+	projectName := session.Project.Name
+	if projectName == "" {
+		projectName = filepath.Base(projectPath)
+	}
+
+	usage := SessionUsage{
+		SessionID:       session.SessionID,
+		ProjectName:     projectName,
+		ProjectPath:     projectPath,
+		State:           session.Lifecycle.State,
+		LastAccessed:    session.LastAccessed,
+		MetadataBytes:   fileSize(filepath.Join(store.GetSessionsPath(), session.SessionID+".json")),
+		BackupBytes:     dirSize(store.GetBackupsPath(session.SessionID)),
+		TranscriptBytes: TranscriptBytes(session, homeDir),
+	}
+	if prices != nil {
+		if estimate, err := EstimateCost(ctx, session, homeDir, prices); err == nil {
+			usage.Tokens = estimate.Tokens
+			usage.CostUSD = estimate.CostUSD
+			usage.UnpricedModels = estimate.Unpriced
+		}
+	}
+	return usage
+}
+
+// Collect aggregates session usage per project
+// Projects are ordered by total size, largest first
+func Collect(ctx context.Context, store storage.Interface, homeDir string, prices pricing.Table) (*Report, error) {
+	sessions, err := CollectSessions(ctx, store, homeDir, prices)
+	if err != nil {
+		return nil, err
+	}
+	return Aggregate(sessions), nil
+}
+
+// Aggregate sums session usage per project, filling in the index statistics as well
+// Projects are ordered by total size, largest first
+func Aggregate(sessions []SessionUsage) *Report {
+	byProject := make(map[string]*ProjectUsage)
+	report := &Report{}
+
+	for _, session := range sessions {
+		usage, ok := byProject[session.ProjectPath]
+		if !ok {
+			usage = &ProjectUsage{ProjectName: session.ProjectName, ProjectPath: session.ProjectPath}
+			byProject[session.ProjectPath] = usage
+		}
+
+		usage.Sessions++
+		usage.MetadataBytes += session.MetadataBytes
+		usage.BackupBytes += session.BackupBytes
+		usage.TranscriptBytes += session.TranscriptBytes
+		usage.Tokens += session.Tokens
+		usage.CostUSD += session.CostUSD
+
+		report.Index.TotalSessions++
+		if session.State == types.SessionStateActive {
+			report.Index.ActiveSessionsCount++
+		}
+	}
+
+	for _, usage := range byProject {
+		report.Projects = append(report.Projects, *usage)
+		report.TotalBytes += usage.TotalBytes()
+		report.TotalTokens += usage.Tokens
+		report.TotalCostUSD += usage.CostUSD
+	}
+	sort.Slice(report.Projects, func(i, j int) bool {
+		if report.Projects[i].TotalBytes() != report.Projects[j].TotalBytes() {
+			return report.Projects[i].TotalBytes() > report.Projects[j].TotalBytes()
+		}
+		return report.Projects[i].ProjectPath < report.Projects[j].ProjectPath
+	})
+
+	report.Index.TotalProjects = len(report.Projects)
+	report.Index.DiskUsage = FormatBytes(report.TotalBytes)
+	return report
+}
+
+// EstimateCost prices the token usage recorded in a session's transcript
+// Sessions without a transcript cost nothing
+func EstimateCost(ctx context.Context, session *types.Session, homeDir string, prices pricing.Table) (pricing.Estimate, error) {
+	path := transcript.Path(session, homeDir)
+	if path == "" {
+		return pricing.Estimate{}, nil
+	}
+
+	summary, err := transcript.SummarizeFile(ctx, path, time.Time{})
+	if os.IsNotExist(err) {
+		return pricing.Estimate{}, nil
+	}
+	if err != nil {
+		return pricing.Estimate{}, err
+	}
+
+	return prices.EstimateUsage(summary.UsageByModel), nil
+}
+
+// EstimateProjectCost prices the transcripts of every session in a project
+func EstimateProjectCost(ctx context.Context, store storage.Interface, projectPath, homeDir string, prices pricing.Table) (pricing.Estimate, error) {
+	names, err := store.ListSessions(ctx)
+	if err != nil {
+		return pricing.Estimate{}, err
+	}
+
+	var total pricing.Estimate
+	for _, name := range names {
+		session, err := store.LoadSession(ctx, name)
+		if err != nil || !paths.Equal(session.Project.Path, projectPath) {
+			continue
+		}
+		estimate, err := EstimateCost(ctx, session, homeDir, prices)
+		if err != nil {
+			continue // an unreadable transcript shouldn't hide the rest of the project
+		}
+		total.Add(estimate)
+	}
+	return total, nil
+}
+
+// TranscriptBytes returns the size of the session's bound Claude transcript
+func TranscriptBytes(session *types.Session, homeDir string) int64 {
+	path := transcript.Path(session, homeDir)
+	if path == "" {
+		return 0
+	}
+	return fileSize(path)
+}
+
+// FormatBytes renders a byte count the way the index stores it, e.g. "2.4MB"
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+
+	div, exp := int64(unit), 0
+	for value := n / unit; value >= unit; value /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// fileSize returns the size of a file, or 0 if it can't be read
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// dirSize returns the total size of the files under dir, or 0 if it doesn't exist
+func dirSize(dir string) int64 {
+	var total int64
+	_ = filepath.WalkDir(dir, func(_ string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !entry.IsDir() {
+			if info, infoErr := entry.Info(); infoErr == nil {
+				total += info.Size()
+			}
+		}
+		return nil
+	})
+	return total
+}
@@ -0,0 +1,136 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/paths"
+	"github.com/bitomule/kamui/internal/pricing"
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func saveSession(t *testing.T, store *storage.Storage, name, projectPath, claudeID string, state types.SessionState) *types.Session {
+	t.Helper()
+	session, err := store.CreateSession(name, projectPath)
+	require.NoError(t, err)
+	session.Claude.SessionID = claudeID
+	session.Lifecycle.State = state
+	require.NoError(t, store.SaveSession(context.Background(), session))
+	return session
+}
+
+func TestCollect(t *testing.T) {
+	home := t.TempDir()
+	projectA := filepath.Join(home, "a")
+	projectB := filepath.Join(home, "b")
+	require.NoError(t, os.MkdirAll(projectA, 0o755))
+	require.NoError(t, os.MkdirAll(projectB, 0o755))
+
+	store := storage.NewWithSessionsDir(projectA, filepath.Join(home, ".claude", "kamui-sessions"))
+	withTranscript := saveSession(t, store, "one", projectA, "claude-1", types.SessionStateActive)
+	saveSession(t, store, "two", projectA, "", types.SessionStateCompleted)
+	saveSession(t, store, "three", projectB, "missing-transcript", types.SessionStateActive)
+
+	transcriptDir := paths.ClaudeProjectDir(home, withTranscript.Project.WorkingDirectory)
+	require.NoError(t, os.MkdirAll(transcriptDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(transcriptDir, "claude-1.jsonl"), []byte(strings.Repeat("x", 4096)), 0o644))
+
+	report, err := Collect(context.Background(), store, home, nil)
+	require.NoError(t, err)
+
+	require.Len(t, report.Projects, 2)
+	assert.Equal(t, projectA, report.Projects[0].ProjectPath)
+	assert.Equal(t, 2, report.Projects[0].Sessions)
+	assert.Equal(t, int64(4096), report.Projects[0].TranscriptBytes)
+	assert.Positive(t, report.Projects[0].MetadataBytes)
+	assert.Equal(t, int64(0), report.Projects[1].TranscriptBytes)
+
+	assert.Equal(t, report.Projects[0].TotalBytes()+report.Projects[1].TotalBytes(), report.TotalBytes)
+	assert.Equal(t, 2, report.Index.TotalProjects)
+	assert.Equal(t, 3, report.Index.TotalSessions)
+	assert.Equal(t, 2, report.Index.ActiveSessionsCount)
+	assert.Equal(t, FormatBytes(report.TotalBytes), report.Index.DiskUsage)
+}
+
+func TestFormatBytes(t *testing.T) {
+	testCases := map[int64]string{
+		0:                      "0B",
+		512:                    "512B",
+		1024:                   "1.0KB",
+		2516582:                "2.4MB",
+		3 * 1024 * 1024 * 1024: "3.0GB",
+	}
+
+	for input, expected := range testCases {
+		assert.Equal(t, expected, FormatBytes(input), input)
+	}
+}
+
+func TestCollectSessions(t *testing.T) {
+	home := t.TempDir()
+	store := storage.NewWithSessionsDir(home, filepath.Join(home, ".claude", "kamui-sessions"))
+	saveSession(t, store, "small", home, "", types.SessionStateActive)
+	saveSession(t, store, "backed-up", home, "", types.SessionStateCompleted)
+
+	backups := store.GetBackupsPath("backed-up")
+	require.NoError(t, os.MkdirAll(backups, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(backups, "transcript.jsonl"), []byte(strings.Repeat("x", 2048)), 0o600))
+
+	sessions, err := CollectSessions(context.Background(), store, home, nil)
+	require.NoError(t, err)
+
+	require.Len(t, sessions, 2)
+	assert.Equal(t, "backed-up", sessions[0].SessionID)
+	assert.Equal(t, int64(2048), sessions[0].BackupBytes)
+	assert.Equal(t, types.SessionStateCompleted, sessions[0].State)
+	assert.Equal(t, "small", sessions[1].SessionID)
+	assert.Equal(t, int64(0), sessions[1].BackupBytes)
+}
+
+func TestCollect_EstimatesCost(t *testing.T) {
+	home := t.TempDir()
+	store := storage.NewWithSessionsDir(home, filepath.Join(home, ".claude", "kamui-sessions"))
+	session := saveSession(t, store, "priced", home, "claude-1", types.SessionStateActive)
+
+	transcriptDir := paths.ClaudeProjectDir(home, session.Project.WorkingDirectory)
+	require.NoError(t, os.MkdirAll(transcriptDir, 0o755))
+	line := `{"type":"assistant","timestamp":"2025-03-10T09:00:00Z","message":{"role":"assistant","model":"claude-sonnet-4-20250514","content":[],"usage":{"input_tokens":1000000,"output_tokens":100000}}}` + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(transcriptDir, "claude-1.jsonl"), []byte(line), 0o644))
+
+	report, err := Collect(context.Background(), store, home, pricing.DefaultTable)
+	require.NoError(t, err)
+
+	require.Len(t, report.Projects, 1)
+	assert.Equal(t, int64(1_100_000), report.Projects[0].Tokens)
+	assert.InDelta(t, 4.5, report.Projects[0].CostUSD, 1e-9)
+	assert.InDelta(t, 4.5, report.TotalCostUSD, 1e-9)
+}
+
+func TestEstimateProjectCost(t *testing.T) {
+	home := t.TempDir()
+	projectA := filepath.Join(home, "a")
+	projectB := filepath.Join(home, "b")
+	store := storage.NewWithSessionsDir(home, filepath.Join(home, ".claude", "kamui-sessions"))
+
+	line := `{"type":"assistant","timestamp":"2025-03-10T09:00:00Z","message":{"role":"assistant","model":"claude-sonnet-4-20250514","content":[],"usage":{"input_tokens":1000000}}}` + "\n"
+	for i, project := range []string{projectA, projectA, projectB} {
+		claudeID := fmt.Sprintf("claude-%d", i)
+		session := saveSession(t, store, fmt.Sprintf("s%d", i), project, claudeID, types.SessionStateActive)
+		transcriptDir := paths.ClaudeProjectDir(home, session.Project.WorkingDirectory)
+		require.NoError(t, os.MkdirAll(transcriptDir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(transcriptDir, claudeID+".jsonl"), []byte(line), 0o644))
+	}
+
+	estimate, err := EstimateProjectCost(context.Background(), store, projectA, home, pricing.DefaultTable)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2_000_000), estimate.Tokens)
+	assert.InDelta(t, 6.0, estimate.CostUSD, 1e-9)
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bitomule/kamui/internal/process"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// lockPath returns the path of a session's lock file
+func (s *Storage) lockPath(sessionID string) string {
+	return filepath.Join(s.sessionsDir, sessionID+".lock")
+}
+
+// LockSession marks the session as running in this process
+// It fails with ErrCodeSessionLocked, carrying the holder's pid, host and whether the lock is stale, if already held
+func (s *Storage) LockSession(ctx context.Context, sessionID string) error {
+	if err := types.ContextError(ctx); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.sessionsDir, 0o700); err != nil {
+		return types.NewStorageError(
+			types.ErrCodeStoragePermission,
+			"failed to create sessions directory",
+			err,
+		)
+	}
+
+	hostname, _ := os.Hostname()
+	data, err := json.Marshal(types.SessionLock{PID: os.Getpid(), Host: hostname, Acquired: s.clock.Now()})
+	if err != nil {
+		return types.NewStorageError(types.ErrCodeStorageCorrupted, "failed to marshal session lock", err)
+	}
+
+	file, err := os.OpenFile(s.lockPath(sessionID), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if os.IsExist(err) {
+		held, readErr := s.ReadLock(ctx, sessionID)
+		if readErr != nil || held == nil {
+			held = &types.SessionLock{} // unreadable locks are treated as stale
+		}
+		return LockedError(sessionID, held)
+	}
+	if err != nil {
+		return types.NewStorageError(types.ErrCodeStoragePermission, "failed to create session lock", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(data); err != nil {
+		os.Remove(s.lockPath(sessionID))
+		return types.NewStorageError(types.ErrCodeStoragePermission, "failed to write session lock", err)
+	}
+	return nil
+}
+
+// UnlockSession removes the session's lock, if any
+func (s *Storage) UnlockSession(ctx context.Context, sessionID string) error {
+	if err := types.ContextError(ctx); err != nil {
+		return err
+	}
+	if err := os.Remove(s.lockPath(sessionID)); err != nil && !os.IsNotExist(err) {
+		return types.NewStorageError(types.ErrCodeStoragePermission, "failed to remove session lock", err)
+	}
+	return nil
+}
+
+// ReadLock returns the session's lock, or nil when it isn't locked
+func (s *Storage) ReadLock(ctx context.Context, sessionID string) (*types.SessionLock, error) {
+	if err := types.ContextError(ctx); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(s.lockPath(sessionID))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStoragePermission, "failed to read session lock", err)
+	}
+
+	var lock types.SessionLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStorageCorrupted, "failed to parse session lock", err)
+	}
+	return &lock, nil
+}
+
+// LockIsStale reports whether a lock was left behind by a process that no longer runs
+// Locks held on other hosts can't be checked and are never considered stale
+func LockIsStale(lock *types.SessionLock) bool {
+	if lock.PID <= 0 {
+		return true
+	}
+	hostname, _ := os.Hostname()
+	if lock.Host != hostname {
+		return false
+	}
+	return !process.Alive(lock.PID)
+}
+
+// LockedError is the ErrCodeSessionLocked error backends return for a session held by another process
+func LockedError(sessionID string, lock *types.SessionLock) *types.AGXError {
+	stale := LockIsStale(lock)
+
+	message := fmt.Sprintf("session '%s' is in use by kam (pid %d on %s since %s)",
+		sessionID, lock.PID, lock.Host, lock.Acquired.Local().Format("2006-01-02 15:04"))
+	if stale {
+		message = fmt.Sprintf("session '%s' has a stale lock left by kam (pid %d), which is no longer running", sessionID, lock.PID)
+	}
+
+	return types.NewSessionError(types.ErrCodeSessionLocked, message, nil).
+		WithContext("pid", lock.PID).
+		WithContext("host", lock.Host).
+		WithContext("stale", stale)
+}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestLockSession(t *testing.T) {
+	tempDir := t.TempDir()
+	storage := NewWithSessionsDir(tempDir, filepath.Join(tempDir, "sessions"))
+
+	lock, err := storage.ReadLock(context.Background(), "api")
+	require.NoError(t, err)
+	assert.Nil(t, lock)
+
+	require.NoError(t, storage.LockSession(context.Background(), "api"))
+	lock, err = storage.ReadLock(context.Background(), "api")
+	require.NoError(t, err)
+	require.NotNil(t, lock)
+	assert.Equal(t, os.Getpid(), lock.PID)
+	assert.False(t, LockIsStale(lock), "this process is alive")
+
+	// A second lock is refused while this process holds it
+	err = storage.LockSession(context.Background(), "api")
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeSessionLocked, agxErr.Code)
+	assert.Equal(t, false, agxErr.Context["stale"])
+	assert.True(t, agxErr.IsRecoverable())
+
+	// Locks don't show up as sessions
+	sessions, err := storage.ListSessions(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+
+	require.NoError(t, storage.UnlockSession(context.Background(), "api"))
+	require.NoError(t, storage.UnlockSession(context.Background(), "api"), "unlocking twice is fine")
+	require.NoError(t, storage.LockSession(context.Background(), "api"))
+}
+
+func TestLockSession_Stale(t *testing.T) {
+	tempDir := t.TempDir()
+	sessionsDir := filepath.Join(tempDir, "sessions")
+	storage := NewWithSessionsDir(tempDir, sessionsDir)
+	require.NoError(t, os.MkdirAll(sessionsDir, 0o700))
+
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+	// pids are bounded well below this on Linux and macOS
+	dead := types.SessionLock{PID: 1 << 30, Host: hostname, Acquired: time.Now()}
+	data, err := json.Marshal(dead)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(sessionsDir, "api.lock"), data, 0o600))
+
+	err = storage.LockSession(context.Background(), "api")
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, true, agxErr.Context["stale"])
+	assert.Contains(t, agxErr.Message, "stale lock")
+
+	// Locks from other hosts can't be checked
+	assert.False(t, LockIsStale(&types.SessionLock{PID: 1 << 30, Host: "elsewhere"}))
+	assert.True(t, LockIsStale(&types.SessionLock{}))
+}
@@ -0,0 +1,196 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/bitomule/kamui/internal/clock"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Memory keeps sessions in process memory; it is meant for tests and programs embedding Kamui
+// Sessions are stored as JSON so callers never share data with the store
+type Memory struct {
+	projectPath string
+	clock       clock.Clock
+
+	mu       sync.Mutex
+	sessions map[string][]byte
+	locks    map[string]types.SessionLock
+}
+
+// NewMemory creates an empty in-memory store for a project
+func NewMemory(projectPath string) *Memory {
+	return &Memory{
+		projectPath: projectPath,
+		clock:       clock.System,
+		sessions:    make(map[string][]byte),
+		locks:       make(map[string]types.SessionLock),
+	}
+}
+
+// SetClock sets the clock used to timestamp sessions and locks
+func (s *Memory) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Initialize does nothing; there is nothing to create
+func (s *Memory) Initialize(ctx context.Context) error {
+	return types.ContextError(ctx)
+}
+
+// SaveSession stores a copy of the session
+func (s *Memory) SaveSession(ctx context.Context, session *types.Session) error {
+	if err := types.ContextError(ctx); err != nil {
+		return err
+	}
+	data, err := json.Marshal(session)
+	if err != nil {
+		return types.NewStorageError(types.ErrCodeStorageCorrupted, "failed to marshal session data", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[session.SessionID] = data
+	return nil
+}
+
+// LoadSession returns a copy of the stored session
+func (s *Memory) LoadSession(ctx context.Context, sessionID string) (*types.Session, error) {
+	if err := types.ContextError(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	data, ok := s.sessions[sessionID]
+	s.mu.Unlock()
+	if !ok {
+		return nil, types.NewStorageError(types.ErrCodeSessionNotFound, fmt.Sprintf("session '%s' not found", sessionID), nil)
+	}
+
+	var session types.Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStorageCorrupted, "failed to parse session data", err)
+	}
+	return &session, nil
+}
+
+// SessionExists reports whether the session is stored
+func (s *Memory) SessionExists(ctx context.Context, sessionID string) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.sessions[sessionID]
+	return ok
+}
+
+// ListSessions returns the stored session IDs in sorted order
+func (s *Memory) ListSessions(ctx context.Context) ([]string, error) {
+	if err := types.ContextError(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sessionIDs := make([]string, 0, len(s.sessions))
+	for sessionID := range s.sessions {
+		sessionIDs = append(sessionIDs, sessionID)
+	}
+	sort.Strings(sessionIDs)
+	return sessionIDs, nil
+}
+
+// DeleteSession removes a stored session
+func (s *Memory) DeleteSession(ctx context.Context, sessionID string) error {
+	if err := types.ContextError(ctx); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.sessions[sessionID]; !ok {
+		return types.NewStorageError(types.ErrCodeSessionNotFound, fmt.Sprintf("session '%s' not found", sessionID), nil)
+	}
+	delete(s.sessions, sessionID)
+	return nil
+}
+
+// CreateSession creates a new session with minimal required data; it is not stored until saved
+func (s *Memory) CreateSession(sessionID, projectPath string) (*types.Session, error) {
+	return NewSession(sessionID, projectPath, s.clock.Now()), nil
+}
+
+// UpdateSessionAccess updates the last accessed time for a session
+func (s *Memory) UpdateSessionAccess(ctx context.Context, sessionID string) error {
+	session, err := s.LoadSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	session.LastAccessed = s.clock.Now()
+	return s.SaveSession(ctx, session)
+}
+
+// GetProjectPath returns the project path for this store
+func (s *Memory) GetProjectPath() string {
+	return s.projectPath
+}
+
+// GetSessionsPath returns "", as sessions aren't kept on disk
+func (s *Memory) GetSessionsPath() string {
+	return ""
+}
+
+// GetBackupsPath returns a directory under the system temp directory for a session's backups
+func (s *Memory) GetBackupsPath(sessionID string) string {
+	return filepath.Join(os.TempDir(), "kamui-backups", sessionID)
+}
+
+// LockSession marks the session as running in this process
+func (s *Memory) LockSession(ctx context.Context, sessionID string) error {
+	if err := types.ContextError(ctx); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if held, ok := s.locks[sessionID]; ok {
+		return LockedError(sessionID, &held)
+	}
+	hostname, _ := os.Hostname()
+	s.locks[sessionID] = types.SessionLock{PID: os.Getpid(), Host: hostname, Acquired: s.clock.Now()}
+	return nil
+}
+
+// UnlockSession removes the session's lock, if any
+func (s *Memory) UnlockSession(ctx context.Context, sessionID string) error {
+	if err := types.ContextError(ctx); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.locks, sessionID)
+	return nil
+}
+
+// ReadLock returns the session's lock, or nil when it isn't locked
+func (s *Memory) ReadLock(ctx context.Context, sessionID string) (*types.SessionLock, error) {
+	if err := types.ContextError(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	held, ok := s.locks[sessionID]
+	if !ok {
+		return nil, nil
+	}
+	return &held, nil
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Backend names of the built-in storage backends
+const (
+	BackendJSONFiles = "json-files"
+	BackendMemory    = "memory"
+
+	// DefaultBackend is used when no backend is configured
+	DefaultBackend = BackendJSONFiles
+)
+
+// Options configures a backend when it is opened
+// Backends ignore the options that don't apply to them
+type Options struct {
+	ProjectPath string
+
+	// SessionsDir is where json-files keeps sessions (defaults to ~/.claude/kamui-sessions)
+	SessionsDir string
+
+	// DurableWrites fsyncs every save
+	DurableWrites bool
+
+	// URL and Token locate and authenticate with a Kamui server for the remote backend
+	URL   string
+	Token string
+}
+
+// Factory opens a backend
+type Factory func(opts Options) (Interface, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+func init() {
+	Register(BackendJSONFiles, func(opts Options) (Interface, error) {
+		var store *Storage
+		if opts.SessionsDir != "" {
+			store = NewWithSessionsDir(opts.ProjectPath, opts.SessionsDir)
+		} else {
+			store = New(opts.ProjectPath)
+		}
+		store.SetDurableWrites(opts.DurableWrites)
+		return store, nil
+	})
+	Register(BackendMemory, func(opts Options) (Interface, error) {
+		return NewMemory(opts.ProjectPath), nil
+	})
+}
+
+// Register makes a backend available under name; registering a name twice panics
+// Backends must pass the storagetest conformance suite
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if factory == nil {
+		panic("storage: Register factory is nil")
+	}
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("storage: backend %q registered twice", name))
+	}
+	registry[name] = factory
+}
+
+// Backends returns the names of the registered backends, sorted
+func Backends() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Open opens the named backend; an empty name opens DefaultBackend
+func Open(name string, opts Options) (Interface, error) {
+	if name == "" {
+		name = DefaultBackend
+	}
+
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, types.NewConfigError(
+			types.ErrCodeConfigInvalid,
+			fmt.Sprintf("unknown storage backend %q (available: %s)", name, strings.Join(Backends(), ", ")),
+			nil,
+		)
+	}
+	return factory(opts)
+}
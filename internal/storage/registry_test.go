@@ -0,0 +1,65 @@
+package storage_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/internal/storage/storagetest"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestConformance_JSONFiles(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, projectPath string) storage.Interface {
+		store, err := storage.Open(storage.BackendJSONFiles, storage.Options{
+			ProjectPath: projectPath,
+			SessionsDir: filepath.Join(t.TempDir(), "sessions"),
+		})
+		require.NoError(t, err)
+		return store
+	})
+}
+
+func TestConformance_JSONFilesDurable(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, projectPath string) storage.Interface {
+		store, err := storage.Open(storage.BackendJSONFiles, storage.Options{
+			ProjectPath:   projectPath,
+			SessionsDir:   filepath.Join(t.TempDir(), "sessions"),
+			DurableWrites: true,
+		})
+		require.NoError(t, err)
+		return store
+	})
+}
+
+func TestConformance_Memory(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, projectPath string) storage.Interface {
+		store, err := storage.Open(storage.BackendMemory, storage.Options{ProjectPath: projectPath})
+		require.NoError(t, err)
+		return store
+	})
+}
+
+func TestOpen(t *testing.T) {
+	store, err := storage.Open("", storage.Options{ProjectPath: "/work/app", SessionsDir: t.TempDir()})
+	require.NoError(t, err)
+	assert.IsType(t, &storage.Storage{}, store, "json-files is the default")
+	assert.Equal(t, "/work/app", store.GetProjectPath())
+
+	_, err = storage.Open("sqlite", storage.Options{})
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeConfigInvalid, agxErr.Code)
+	assert.Contains(t, agxErr.Message, "json-files, memory")
+}
+
+func TestRegister(t *testing.T) {
+	assert.Equal(t, []string{"json-files", "memory"}, storage.Backends())
+
+	assert.Panics(t, func() {
+		storage.Register(storage.BackendMemory, func(storage.Options) (storage.Interface, error) { return nil, nil })
+	}, "names are unique")
+}
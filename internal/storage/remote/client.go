@@ -0,0 +1,299 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bitomule/kamui/internal/clock"
+	"github.com/bitomule/kamui/internal/retry"
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// requestPolicy retries requests the server couldn't answer; every API call is idempotent, locks
+// included: a retried lock or unlock is matched to the lock its first attempt named
+var requestPolicy = retry.Policy{
+	MaxAttempts:  3,
+	InitialDelay: 200 * time.Millisecond,
+	MaxDelay:     2 * time.Second,
+	Multiplier:   2,
+	Jitter:       0.2,
+}
+
+// Client is a storage backend keeping sessions on a Kamui server
+type Client struct {
+	baseURL     string
+	token       string
+	projectPath string
+	httpClient  *http.Client
+	clock       clock.Clock
+
+	mu sync.Mutex
+	// held is the locks this client took, released by naming them
+	held map[string]types.SessionLock
+}
+
+// New creates a client for the server at baseURL, e.g. https://kamui.internal:7878
+func New(baseURL, token, projectPath string) (*Client, error) {
+	parsed, err := url.Parse(baseURL)
+	if baseURL == "" || err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, types.NewConfigError(
+			types.ErrCodeConfigInvalid,
+			"storage.remote.url is not configured (e.g. https://kamui.internal:7878)",
+			err,
+		)
+	}
+
+	return &Client{
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		token:       token,
+		projectPath: projectPath,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		clock:       clock.System,
+		held:        make(map[string]types.SessionLock),
+	}, nil
+}
+
+// SetClock sets the clock used to timestamp new sessions and locks
+func (c *Client) SetClock(clk clock.Clock) {
+	c.clock = clk
+}
+
+// Initialize checks that the server is reachable and accepts the token
+func (c *Client) Initialize(ctx context.Context) error {
+	_, err := c.do(ctx, http.MethodGet, sessionsPath, nil, nil)
+	return err
+}
+
+// SaveSession uploads the session, replacing the server's copy
+func (c *Client) SaveSession(ctx context.Context, session *types.Session) error {
+	_, err := c.do(ctx, http.MethodPut, sessionPath(session.SessionID), session, nil)
+	return err
+}
+
+// LoadSession downloads a session
+func (c *Client) LoadSession(ctx context.Context, sessionID string) (*types.Session, error) {
+	var session types.Session
+	if _, err := c.do(ctx, http.MethodGet, sessionPath(sessionID), nil, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// SessionExists reports whether the server has the session; errors count as missing
+func (c *Client) SessionExists(ctx context.Context, sessionID string) bool {
+	status, err := c.do(ctx, http.MethodHead, sessionPath(sessionID), nil, nil)
+	return err == nil && status == http.StatusOK
+}
+
+// ListSessions returns the names of the server's sessions
+func (c *Client) ListSessions(ctx context.Context) ([]string, error) {
+	var names []string
+	if _, err := c.do(ctx, http.MethodGet, sessionsPath, nil, &names); err != nil {
+		return nil, err
+	}
+	if names == nil {
+		names = []string{}
+	}
+	return names, nil
+}
+
+// DeleteSession removes a session from the server
+func (c *Client) DeleteSession(ctx context.Context, sessionID string) error {
+	_, err := c.do(ctx, http.MethodDelete, sessionPath(sessionID), nil, nil)
+	return err
+}
+
+// CreateSession creates a new session with minimal required data; it is uploaded when saved
+func (c *Client) CreateSession(sessionID, projectPath string) (*types.Session, error) {
+	return storage.NewSession(sessionID, projectPath, c.clock.Now()), nil
+}
+
+// UpdateSessionAccess has the server update the session's last accessed time
+func (c *Client) UpdateSessionAccess(ctx context.Context, sessionID string) error {
+	_, err := c.do(ctx, http.MethodPost, sessionPath(sessionID)+"/access", nil, nil)
+	return err
+}
+
+// GetProjectPath returns the project path for this client
+func (c *Client) GetProjectPath() string {
+	return c.projectPath
+}
+
+// GetSessionsPath returns "", as sessions aren't kept on this machine
+func (c *Client) GetSessionsPath() string {
+	return ""
+}
+
+// GetBackupsPath returns a local directory for a session's backups
+// Backups are of Claude transcripts, which stay on the machine Claude ran on
+func (c *Client) GetBackupsPath(sessionID string) string {
+	return filepath.Join(os.TempDir(), "kamui-backups", sessionID)
+}
+
+// LockSession marks the session as running in this process on the server
+func (c *Client) LockSession(ctx context.Context, sessionID string) error {
+	hostname, _ := os.Hostname()
+	lock := types.SessionLock{PID: os.Getpid(), Host: hostname, Acquired: c.clock.Now()}
+	_, err := c.do(ctx, http.MethodPut, sessionPath(sessionID)+"/lock", lock, nil)
+
+	if held, locked := lockedBy(err); locked {
+		// a retried request finds the lock its first attempt took
+		if held == nil || !sameHolder(*held, lock) {
+			return storage.LockedError(sessionID, heldOrUnknown(held))
+		}
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.held[sessionID] = lock
+	c.mu.Unlock()
+	return nil
+}
+
+// UnlockSession releases the session's lock, if any
+// The server only removes the lock the request names: this client's own, or else the one held
+// now, so a lock taken by someone else meanwhile is kept
+func (c *Client) UnlockSession(ctx context.Context, sessionID string) error {
+	c.mu.Lock()
+	lock, ours := c.held[sessionID]
+	c.mu.Unlock()
+	if !ours {
+		current, err := c.ReadLock(ctx, sessionID)
+		if err != nil || current == nil {
+			return err
+		}
+		lock = *current
+	}
+
+	_, err := c.do(ctx, http.MethodDelete, sessionPath(sessionID)+"/lock?"+holderQuery(lock).Encode(), nil, nil)
+	if held, locked := lockedBy(err); locked {
+		if !ours {
+			return storage.LockedError(sessionID, heldOrUnknown(held))
+		}
+		// this client's lock is gone already, and the one there now isn't for it to remove
+		err = nil
+	}
+	if err == nil && ours {
+		c.mu.Lock()
+		delete(c.held, sessionID)
+		c.mu.Unlock()
+	}
+	return err
+}
+
+// ReadLock returns the session's lock, or nil when it isn't locked
+func (c *Client) ReadLock(ctx context.Context, sessionID string) (*types.SessionLock, error) {
+	var lock types.SessionLock
+	status, err := c.do(ctx, http.MethodGet, sessionPath(sessionID)+"/lock", nil, &lock)
+	if err != nil || status == http.StatusNoContent {
+		return nil, err
+	}
+	return &lock, nil
+}
+
+// sessionPath returns the API path of a session
+func sessionPath(sessionID string) string {
+	return sessionsPath + "/" + url.PathEscape(sessionID)
+}
+
+// do sends a request, decoding a successful response into out and a failed one into an
+// AGXError carrying the server's code; unreachable servers and gateway errors are retried
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) (int, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return 0, types.NewStorageError(types.ErrCodeStorageCorrupted, "failed to encode request", err)
+		}
+	}
+
+	var status int
+	err := retry.Do(ctx, requestPolicy, func(int) error {
+		req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(payload))
+		if err != nil {
+			return retry.Permanent(serverError("failed to build request", err))
+		}
+		req.Header.Set("Accept", "application/json")
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			if ctxErr := types.ContextError(ctx); ctxErr != nil {
+				return retry.Permanent(ctxErr)
+			}
+			return serverError(fmt.Sprintf("failed to reach the Kamui server at %s", c.baseURL), err)
+		}
+		defer resp.Body.Close()
+		status = resp.StatusCode
+
+		switch {
+		case status == http.StatusBadGateway || status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout:
+			return serverError(fmt.Sprintf("the Kamui server returned %s", resp.Status), nil)
+		case status >= 400:
+			return retry.Permanent(responseError(resp))
+		case out == nil || status == http.StatusNoContent || method == http.MethodHead:
+			return nil
+		}
+		if decodeErr := json.NewDecoder(resp.Body).Decode(out); decodeErr != nil {
+			return retry.Permanent(serverError("failed to parse the Kamui server's response", decodeErr))
+		}
+		return nil
+	})
+	return status, err
+}
+
+// lockedBy reports whether err is the server refusing a lock, with the holder it reported
+func lockedBy(err error) (*types.SessionLock, bool) {
+	var agxErr *types.AGXError
+	if !errors.As(err, &agxErr) || agxErr.Code != types.ErrCodeSessionLocked {
+		return nil, false
+	}
+	held, _ := agxErr.Context["lock"].(*types.SessionLock)
+	return held, true
+}
+
+// heldOrUnknown stands in for a holder the server didn't report, which counts as stale
+func heldOrUnknown(held *types.SessionLock) *types.SessionLock {
+	if held == nil {
+		return &types.SessionLock{}
+	}
+	return held
+}
+
+// responseError turns an error response into the AGXError the server reported
+func responseError(resp *http.Response) error {
+	var body errorBody
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err := json.Unmarshal(data, &body); err != nil || body.Code == "" {
+		return serverError(fmt.Sprintf("the Kamui server returned %s", resp.Status), errors.New(strings.TrimSpace(string(data))))
+	}
+	agxErr := &types.AGXError{Code: body.Code, Message: body.Message}
+	if body.Lock != nil {
+		agxErr = agxErr.WithContext("lock", body.Lock)
+	}
+	return agxErr
+}
+
+// serverError reports a server that couldn't be reached or answered nonsense
+func serverError(message string, cause error) *types.AGXError {
+	return &types.AGXError{Code: types.ErrCodeDependencyFailed, Message: message, Cause: cause}
+}
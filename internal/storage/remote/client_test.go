@@ -0,0 +1,155 @@
+package remote
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/internal/storage/storagetest"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// newServer serves an empty in-memory store and returns a client for it
+func newServer(t *testing.T, token string) (*Client, *storage.Memory) {
+	t.Helper()
+	backing := storage.NewMemory("/server")
+	server := httptest.NewServer(NewHandler(backing, token))
+	t.Cleanup(server.Close)
+
+	client, err := New(server.URL, token, "/work/app")
+	require.NoError(t, err)
+	return client, backing
+}
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, projectPath string) storage.Interface {
+		client, _ := newServer(t, "secret")
+		client.projectPath = projectPath
+		return client
+	})
+}
+
+func TestRegistered(t *testing.T) {
+	assert.Contains(t, storage.Backends(), BackendName)
+
+	_, err := storage.Open(BackendName, storage.Options{})
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeConfigInvalid, agxErr.Code, "the server URL is required")
+}
+
+func TestClient_SharesSessionsThroughTheServer(t *testing.T) {
+	client, backing := newServer(t, "")
+
+	session, err := client.CreateSession("api", "/work/app")
+	require.NoError(t, err)
+	session.Claude.SessionID = "claude-123"
+	require.NoError(t, client.SaveSession(context.Background(), session))
+
+	stored, err := backing.LoadSession(context.Background(), "api")
+	require.NoError(t, err)
+	assert.Equal(t, "claude-123", stored.Claude.SessionID)
+}
+
+func TestClient_WrongToken(t *testing.T) {
+	client, _ := newServer(t, "secret")
+	client.token = "guess"
+
+	err := client.Initialize(context.Background())
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeConfigInvalid, agxErr.Code)
+	assert.Contains(t, agxErr.Message, "KAMUI_SERVER_TOKEN")
+}
+
+func TestClient_RetriesUnavailableServer(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeJSON(w, http.StatusOK, []string{"api"})
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "", "/work/app")
+	require.NoError(t, err)
+
+	names, err := client.ListSessions(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api"}, names)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestClient_RetriedLockFindsItsOwnLock(t *testing.T) {
+	handler := NewHandler(storage.NewMemory("/server"), "")
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the first lock request is applied, but its answer is lost
+		if r.Method == http.MethodPut && calls.Add(1) == 1 {
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "", "/work/app")
+	require.NoError(t, err)
+
+	require.NoError(t, client.LockSession(context.Background(), "api"))
+	assert.Equal(t, int32(2), calls.Load())
+	require.NoError(t, client.UnlockSession(context.Background(), "api"))
+	lock, err := client.ReadLock(context.Background(), "api")
+	require.NoError(t, err)
+	assert.Nil(t, lock)
+}
+
+func TestClient_UnlockKeepsSomeoneElsesLock(t *testing.T) {
+	client, _ := newServer(t, "")
+	other, err := New(client.baseURL, "", "/work/app")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, client.LockSession(ctx, "api"))
+	// the lock is removed by hand and taken by another kam before this one releases it
+	require.NoError(t, other.UnlockSession(ctx, "api"))
+	require.NoError(t, other.LockSession(ctx, "api"))
+
+	require.NoError(t, client.UnlockSession(ctx, "api"))
+	lock, err := client.ReadLock(ctx, "api")
+	require.NoError(t, err)
+	require.NotNil(t, lock, "the other kam keeps its lock")
+	assert.True(t, sameHolder(other.held["api"], *lock))
+}
+
+func TestClient_UnreachableServer(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	client, err := New(server.URL, "", "/work/app")
+	require.NoError(t, err)
+	client.httpClient.Timeout = time.Second
+
+	_, err = client.ListSessions(context.Background())
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeDependencyFailed, agxErr.Code)
+	assert.Contains(t, agxErr.Message, server.URL)
+}
+
+func TestNew_RequiresHTTPURL(t *testing.T) {
+	for _, url := range []string{"", "kamui.internal:7878", "ftp://kamui.internal"} {
+		_, err := New(url, "", "/work/app")
+		assert.Error(t, err, url)
+	}
+}
@@ -0,0 +1,89 @@
+// Package remote keeps sessions on a Kamui server (kam serve) so machines without a home
+// directory of their own, such as CI runners and cloud dev boxes, share one session catalog
+//
+// The package provides both sides: the "remote" storage backend and the HTTP handler the
+// server runs. Importing it registers the backend.
+package remote
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// BackendName is the storage.backend value selecting this backend
+const BackendName = "remote"
+
+// sessionsPath is the API's collection of sessions
+const sessionsPath = "/v1/sessions"
+
+func init() {
+	storage.Register(BackendName, func(opts storage.Options) (storage.Interface, error) {
+		return New(opts.URL, opts.Token, opts.ProjectPath)
+	})
+}
+
+// errorBody is how the API reports a failed request
+type errorBody struct {
+	Code    types.ErrorCode `json:"code"`
+	Message string          `json:"message"`
+
+	// Lock is the holder of a session, sent with SESSION_LOCKED
+	Lock *types.SessionLock `json:"lock,omitempty"`
+}
+
+// statusFor returns the HTTP status the API uses for an error code
+func statusFor(code types.ErrorCode) int {
+	switch code {
+	case types.ErrCodeSessionNotFound:
+		return http.StatusNotFound
+	case types.ErrCodeSessionLocked:
+		return http.StatusConflict
+	case types.ErrCodeInvalidInput:
+		return http.StatusBadRequest
+	case types.ErrCodeStoragePermission:
+		return http.StatusForbidden
+	case types.ErrCodeInterrupted, types.ErrCodeTimeout:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// sameHolder reports whether two locks were taken by the same acquisition
+func sameHolder(a, b types.SessionLock) bool {
+	return a.PID == b.PID && a.Host == b.Host && a.Acquired.Equal(b.Acquired)
+}
+
+// holderQuery names a lock in the query of an unlock request
+func holderQuery(lock types.SessionLock) url.Values {
+	return url.Values{
+		"pid":      {strconv.Itoa(lock.PID)},
+		"host":     {lock.Host},
+		"acquired": {lock.Acquired.Format(time.RFC3339Nano)},
+	}
+}
+
+// holderFromQuery reads the lock an unlock request names
+func holderFromQuery(query url.Values) (types.SessionLock, error) {
+	pid, err := strconv.Atoi(query.Get("pid"))
+	if err != nil {
+		return types.SessionLock{}, err
+	}
+	acquired, err := time.Parse(time.RFC3339Nano, query.Get("acquired"))
+	if err != nil {
+		return types.SessionLock{}, err
+	}
+	return types.SessionLock{PID: pid, Host: query.Get("host"), Acquired: acquired}, nil
+}
+
+// validSessionID reports whether id can name a session; path separators and dot segments
+// would let a request escape the server's sessions directory
+func validSessionID(id string) bool {
+	return id != "" && id != "." && id != ".." && !strings.ContainsAny(id, `/\`)
+}
@@ -0,0 +1,214 @@
+package remote
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// maxSessionSize bounds a session body accepted by the server
+const maxSessionSize = 8 * 1024 * 1024
+
+// Handler serves a storage backend over HTTP for remote clients
+// Locks are kept by the handler, not the backend, so they record the client holding them
+// and are released when the server restarts
+type Handler struct {
+	store storage.Interface
+	token string
+	mux   *http.ServeMux
+
+	mu    sync.Mutex
+	locks map[string]types.SessionLock
+}
+
+// NewHandler serves store; a non-empty token must be sent as a bearer token on every request
+func NewHandler(store storage.Interface, token string) *Handler {
+	h := &Handler{store: store, token: token, mux: http.NewServeMux(), locks: make(map[string]types.SessionLock)}
+	h.mux.HandleFunc("GET "+sessionsPath, h.list)
+	h.mux.HandleFunc("GET "+sessionsPath+"/{id}", h.load)
+	h.mux.HandleFunc("PUT "+sessionsPath+"/{id}", h.save)
+	h.mux.HandleFunc("DELETE "+sessionsPath+"/{id}", h.delete)
+	h.mux.HandleFunc("POST "+sessionsPath+"/{id}/access", h.access)
+	h.mux.HandleFunc("GET "+sessionsPath+"/{id}/lock", h.readLock)
+	h.mux.HandleFunc("PUT "+sessionsPath+"/{id}/lock", h.lock)
+	h.mux.HandleFunc("DELETE "+sessionsPath+"/{id}/lock", h.unlock)
+	return h
+}
+
+// ServeHTTP authenticates the request and routes it
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.token != "" {
+		sent := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(sent), []byte(h.token)) != 1 {
+			writeJSON(w, http.StatusUnauthorized, errorBody{Code: types.ErrCodeConfigInvalid, Message: "the Kamui server rejected the token; set KAMUI_SERVER_TOKEN or storage.remote.token to the server's token"})
+			return
+		}
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) list(w http.ResponseWriter, r *http.Request) {
+	names, err := h.store.ListSessions(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, names)
+}
+
+func (h *Handler) load(w http.ResponseWriter, r *http.Request) {
+	id, ok := sessionID(w, r)
+	if !ok {
+		return
+	}
+	session, err := h.store.LoadSession(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, session)
+}
+
+func (h *Handler) save(w http.ResponseWriter, r *http.Request) {
+	id, ok := sessionID(w, r)
+	if !ok {
+		return
+	}
+	var session types.Session
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSessionSize)).Decode(&session); err != nil {
+		writeError(w, types.NewSessionError(types.ErrCodeInvalidInput, "invalid session data", err))
+		return
+	}
+	if session.SessionID != id {
+		writeError(w, types.NewSessionError(types.ErrCodeInvalidInput, "session name doesn't match the URL", nil))
+		return
+	}
+	if err := h.store.SaveSession(r.Context(), &session); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := sessionID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.store.DeleteSession(r.Context(), id); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) access(w http.ResponseWriter, r *http.Request) {
+	id, ok := sessionID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.store.UpdateSessionAccess(r.Context(), id); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) readLock(w http.ResponseWriter, r *http.Request) {
+	id, ok := sessionID(w, r)
+	if !ok {
+		return
+	}
+	h.mu.Lock()
+	held, locked := h.locks[id]
+	h.mu.Unlock()
+	if !locked {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusOK, held)
+}
+
+func (h *Handler) lock(w http.ResponseWriter, r *http.Request) {
+	id, ok := sessionID(w, r)
+	if !ok {
+		return
+	}
+	var requested types.SessionLock
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&requested); err != nil {
+		writeError(w, types.NewSessionError(types.ErrCodeInvalidInput, "invalid lock", err))
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if held, locked := h.locks[id]; locked {
+		writeLocked(w, id, held)
+		return
+	}
+	h.locks[id] = requested
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) unlock(w http.ResponseWriter, r *http.Request) {
+	id, ok := sessionID(w, r)
+	if !ok {
+		return
+	}
+	holder, err := holderFromQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, types.NewSessionError(types.ErrCodeInvalidInput, "unlock requests must name the lock's holder", err))
+		return
+	}
+
+	// only the named lock is removed, so a late or retried unlock can't release someone else's
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if held, locked := h.locks[id]; locked && !sameHolder(held, holder) {
+		writeLocked(w, id, held)
+		return
+	}
+	delete(h.locks, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeLocked refuses a request on a session locked by held
+func writeLocked(w http.ResponseWriter, id string, held types.SessionLock) {
+	writeJSON(w, http.StatusConflict, errorBody{
+		Code:    types.ErrCodeSessionLocked,
+		Message: "session '" + id + "' is locked",
+		Lock:    &held,
+	})
+}
+
+// sessionID returns the request's session name, answering 400 when it is unusable
+func sessionID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id := r.PathValue("id")
+	if !validSessionID(id) {
+		writeError(w, types.NewSessionError(types.ErrCodeInvalidInput, "invalid session name", nil))
+		return "", false
+	}
+	return id, true
+}
+
+// writeError reports err with the status matching its code
+func writeError(w http.ResponseWriter, err error) {
+	body := errorBody{Code: types.ErrCodeUnknown, Message: err.Error()}
+	var agxErr *types.AGXError
+	if errors.As(err, &agxErr) {
+		body.Code, body.Message = agxErr.Code, agxErr.Message
+	}
+	writeJSON(w, statusFor(body.Code), body)
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,81 @@
+package remote
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func serve(t *testing.T, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	handler := NewHandler(storage.NewMemory("/server"), "secret")
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer secret")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestHandler_RequiresToken(t *testing.T) {
+	handler := NewHandler(storage.NewMemory("/server"), "secret")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, sessionsPath, nil))
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestHandler_RejectsPathsOutsideTheCatalog(t *testing.T) {
+	for _, path := range []string{sessionsPath + "/..", sessionsPath + "/..%2Fsecrets", sessionsPath + "/a%5Cb"} {
+		recorder := serve(t, http.MethodGet, path, "")
+		assert.NotEqual(t, http.StatusOK, recorder.Code, path)
+	}
+	recorder := serve(t, http.MethodGet, sessionsPath+"/..%2Fsecrets", "")
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestHandler_SaveChecksTheName(t *testing.T) {
+	recorder := serve(t, http.MethodPut, sessionsPath+"/api", `{"sessionId":"web"}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "INVALID_INPUT")
+
+	recorder = serve(t, http.MethodPut, sessionsPath+"/api", `not json`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestHandler_MissingSession(t *testing.T) {
+	recorder := serve(t, http.MethodGet, sessionsPath+"/missing", "")
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "SESSION_NOT_FOUND")
+}
+
+func TestHandler_UnlockChecksTheHolder(t *testing.T) {
+	handler := NewHandler(storage.NewMemory("/server"), "")
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return recorder
+	}
+	lockPath := sessionsPath + "/api/lock"
+	acquired := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	holder := types.SessionLock{PID: 42, Host: "ci-1", Acquired: acquired}
+
+	require.Equal(t, http.StatusNoContent, send(http.MethodPut, lockPath, `{"pid":42,"host":"ci-1","acquired":"2026-10-01T09:00:00Z"}`).Code)
+
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodDelete, lockPath, "").Code, "unlocks name the holder")
+	other := holder
+	other.PID = 43
+	recorder := send(http.MethodDelete, lockPath+"?"+holderQuery(other).Encode(), "")
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "SESSION_LOCKED")
+
+	assert.Equal(t, http.StatusNoContent, send(http.MethodDelete, lockPath+"?"+holderQuery(holder).Encode(), "").Code)
+	assert.Equal(t, http.StatusNoContent, send(http.MethodDelete, lockPath+"?"+holderQuery(holder).Encode(), "").Code, "unlocking twice is fine")
+}
@@ -2,32 +2,46 @@
 package storage
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"syscall"
 	"time"
 
+	"github.com/bitomule/kamui/internal/clock"
+	"github.com/bitomule/kamui/internal/retry"
 	"github.com/bitomule/kamui/pkg/types"
 )
 
 // Interface defines the contract for session storage operations
+// Operations touching the disk take a context and fail with ErrCodeInterrupted or ErrCodeTimeout
+// once it is done; CreateSession and the path getters only build values in memory
 type Interface interface {
-	Initialize() error
-	SaveSession(session *types.Session) error
-	LoadSession(sessionID string) (*types.Session, error)
-	SessionExists(sessionID string) bool
-	ListSessions() ([]string, error)
-	DeleteSession(sessionID string) error
+	Initialize(ctx context.Context) error
+	SaveSession(ctx context.Context, session *types.Session) error
+	LoadSession(ctx context.Context, sessionID string) (*types.Session, error)
+	SessionExists(ctx context.Context, sessionID string) bool
+	ListSessions(ctx context.Context) ([]string, error)
+	DeleteSession(ctx context.Context, sessionID string) error
 	CreateSession(sessionID, projectPath string) (*types.Session, error)
-	UpdateSessionAccess(sessionID string) error
+	UpdateSessionAccess(ctx context.Context, sessionID string) error
 	GetProjectPath() string
 	GetSessionsPath() string
+	GetBackupsPath(sessionID string) string
+	LockSession(ctx context.Context, sessionID string) error
+	UnlockSession(ctx context.Context, sessionID string) error
+	ReadLock(ctx context.Context, sessionID string) (*types.SessionLock, error)
 }
 
 type Storage struct {
 	projectPath string
 	sessionsDir string
+	clock       clock.Clock
+	durable     bool
 }
 
 func New(projectPath string) *Storage {
@@ -42,11 +56,27 @@ func NewWithSessionsDir(projectPath, sessionsDir string) *Storage {
 	return &Storage{
 		projectPath: projectPath,
 		sessionsDir: sessionsDir,
+		clock:       clock.System,
 	}
 }
 
+// SetClock sets the clock used to timestamp sessions and locks
+func (s *Storage) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// SetDurableWrites makes SaveSession fsync the session data before renaming it into place and
+// the sessions directory afterwards, so a crash can't leave a truncated or missing session file
+func (s *Storage) SetDurableWrites(enabled bool) {
+	s.durable = enabled
+}
+
 // Initialize creates the necessary directories for session storage
-func (s *Storage) Initialize() error {
+func (s *Storage) Initialize(ctx context.Context) error {
+	if err := types.ContextError(ctx); err != nil {
+		return err
+	}
+
 	// Create .claude/kamui-sessions directory structure
 	if err := os.MkdirAll(s.sessionsDir, 0o700); err != nil {
 		return types.NewStorageError(
@@ -60,17 +90,14 @@ func (s *Storage) Initialize() error {
 }
 
 // SaveSession saves a session to disk using friendly name as filename
-func (s *Storage) SaveSession(session *types.Session) error {
-	if err := s.Initialize(); err != nil {
+func (s *Storage) SaveSession(ctx context.Context, session *types.Session) error {
+	if err := s.Initialize(ctx); err != nil {
 		return err
 	}
 
 	// Use SessionID (which contains friendly name like "Undolly") as filename
 	sessionFile := filepath.Join(s.sessionsDir, session.SessionID+".json")
 
-	// Create temporary file for atomic write
-	tempFile := sessionFile + ".tmp"
-
 	// Marshal session to JSON
 	data, err := json.MarshalIndent(session, "", "  ")
 	if err != nil {
@@ -81,18 +108,12 @@ func (s *Storage) SaveSession(session *types.Session) error {
 		)
 	}
 
-	// Write to temporary file
-	if err := os.WriteFile(tempFile, data, 0o600); err != nil {
-		return types.NewStorageError(
-			types.ErrCodeStoragePermission,
-			"failed to write session file",
-			err,
-		)
-	}
+	s.keepPreviousVersion(sessionFile, session.SessionID)
 
-	// Atomic move to final location
-	if err := os.Rename(tempFile, sessionFile); err != nil {
-		os.Remove(tempFile) // cleanup temp file
+	// Write to a temporary file and move it into place, retrying briefly while the file is busy
+	if err := retry.Do(ctx, writePolicy, func(int) error {
+		return writeAtomic(sessionFile, data, s.durable)
+	}); err != nil {
 		return types.NewStorageError(
 			types.ErrCodeStoragePermission,
 			"failed to save session file",
@@ -103,8 +124,85 @@ func (s *Storage) SaveSession(session *types.Session) error {
 	return nil
 }
 
+// keepPreviousVersion copies the session file about to be replaced to PreviousVersionPath, so
+// `kam repair` has something to restore; a file that isn't valid JSON doesn't replace the copy
+func (s *Storage) keepPreviousVersion(sessionFile, sessionID string) {
+	data, err := os.ReadFile(sessionFile) // #nosec G304 -- path is inside the sessions directory
+	if err != nil || !json.Valid(data) {
+		return
+	}
+	previous := s.PreviousVersionPath(sessionID)
+	if err := os.MkdirAll(filepath.Dir(previous), 0o700); err != nil {
+		return
+	}
+	_ = writeAtomic(previous, data, false) // the copy is best effort and never fails a save
+}
+
+// writePolicy retries session writes that fail because the file is briefly busy
+var writePolicy = retry.Policy{
+	MaxAttempts:  4,
+	InitialDelay: 50 * time.Millisecond,
+	MaxDelay:     500 * time.Millisecond,
+	Multiplier:   2,
+	Jitter:       0.2,
+}
+
+// writeAtomic writes data to a temporary file and renames it over path; only transient
+// failures are left retryable
+// A durable write also syncs the temporary file before the rename and the directory after it
+func writeAtomic(path string, data []byte, durable bool) error {
+	tempFile := path + ".tmp"
+	if err := writeTemp(tempFile, data, durable); err != nil {
+		os.Remove(tempFile)
+		return permanentUnlessTransient(err)
+	}
+	if err := os.Rename(tempFile, path); err != nil {
+		os.Remove(tempFile) // cleanup temp file
+		return permanentUnlessTransient(err)
+	}
+	if durable {
+		if err := syncDir(filepath.Dir(path)); err != nil {
+			return permanentUnlessTransient(err)
+		}
+	}
+	return nil
+}
+
+// writeTemp writes data to path, syncing it to disk before closing when durable
+func writeTemp(path string, data []byte, durable bool) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600) // #nosec G304 -- path is inside the sessions directory
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if durable {
+		if err := file.Sync(); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	return file.Close()
+}
+
+// permanentUnlessTransient marks errors other than busy or interrupted file operations as not worth retrying
+func permanentUnlessTransient(err error) error {
+	for _, transient := range []error{syscall.EAGAIN, syscall.EBUSY, syscall.EINTR, syscall.ETXTBSY} {
+		if errors.Is(err, transient) {
+			return err
+		}
+	}
+	return retry.Permanent(err)
+}
+
 // LoadSession loads a session from disk
-func (s *Storage) LoadSession(sessionID string) (*types.Session, error) {
+func (s *Storage) LoadSession(ctx context.Context, sessionID string) (*types.Session, error) {
+	if err := types.ContextError(ctx); err != nil {
+		return nil, err
+	}
+
 	sessionFile := filepath.Join(s.sessionsDir, sessionID+".json")
 
 	// Check if file exists
@@ -140,14 +238,21 @@ func (s *Storage) LoadSession(sessionID string) (*types.Session, error) {
 }
 
 // SessionExists checks if a session file exists
-func (s *Storage) SessionExists(sessionID string) bool {
+func (s *Storage) SessionExists(ctx context.Context, sessionID string) bool {
+	if ctx.Err() != nil {
+		return false
+	}
 	sessionFile := filepath.Join(s.sessionsDir, sessionID+".json")
 	_, err := os.Stat(sessionFile)
 	return err == nil
 }
 
 // ListSessions returns a list of all session IDs in the project
-func (s *Storage) ListSessions() ([]string, error) {
+func (s *Storage) ListSessions(ctx context.Context) ([]string, error) {
+	if err := types.ContextError(ctx); err != nil {
+		return nil, err
+	}
+
 	if _, err := os.Stat(s.sessionsDir); os.IsNotExist(err) {
 		return []string{}, nil // no sessions yet
 	}
@@ -174,7 +279,11 @@ func (s *Storage) ListSessions() ([]string, error) {
 }
 
 // DeleteSession removes a session file
-func (s *Storage) DeleteSession(sessionID string) error {
+func (s *Storage) DeleteSession(ctx context.Context, sessionID string) error {
+	if err := types.ContextError(ctx); err != nil {
+		return err
+	}
+
 	sessionFile := filepath.Join(s.sessionsDir, sessionID+".json")
 
 	if err := os.Remove(sessionFile); err != nil {
@@ -197,10 +306,16 @@ func (s *Storage) DeleteSession(sessionID string) error {
 
 // CreateSession creates a new session with minimal required data
 func (s *Storage) CreateSession(sessionID, projectPath string) (*types.Session, error) {
-	now := time.Now()
+	return NewSession(sessionID, projectPath, s.clock.Now()), nil
+}
+
+// FormatVersion is the session file format written by NewSession
+const FormatVersion = "1.0.0"
 
-	session := &types.Session{
-		Version:      "1.0.0",
+// NewSession returns a new active session created at now, for backends implementing CreateSession
+func NewSession(sessionID, projectPath string, now time.Time) *types.Session {
+	return &types.Session{
+		Version:      FormatVersion,
 		SessionID:    sessionID,
 		Created:      now,
 		LastAccessed: now,
@@ -226,19 +341,17 @@ func (s *Storage) CreateSession(sessionID, projectPath string) (*types.Session,
 			},
 		},
 	}
-
-	return session, nil
 }
 
 // UpdateSessionAccess updates the last accessed time for a session
-func (s *Storage) UpdateSessionAccess(sessionID string) error {
-	session, err := s.LoadSession(sessionID)
+func (s *Storage) UpdateSessionAccess(ctx context.Context, sessionID string) error {
+	session, err := s.LoadSession(ctx, sessionID)
 	if err != nil {
 		return err
 	}
 
-	session.LastAccessed = time.Now()
-	return s.SaveSession(session)
+	session.LastAccessed = s.clock.Now()
+	return s.SaveSession(ctx, session)
 }
 
 // GetProjectPath returns the project path for this storage instance
@@ -250,3 +363,48 @@ func (s *Storage) GetProjectPath() string {
 func (s *Storage) GetSessionsPath() string {
 	return s.sessionsDir
 }
+
+// GetBackupsPath returns the directory holding backups made for a session
+func (s *Storage) GetBackupsPath(sessionID string) string {
+	return filepath.Join(s.sessionsDir, "backups", sessionID)
+}
+
+// PreviousVersionPath returns where SaveSession keeps the last valid version of a session file it replaced
+func (s *Storage) PreviousVersionPath(sessionID string) string {
+	return filepath.Join(s.GetBackupsPath(sessionID), "session.previous.json")
+}
+
+// StaleTempAge is how old a temporary file left in the sessions directory must be before
+// RemoveStaleTempFiles treats it as left behind by an interrupted save
+const StaleTempAge = 5 * time.Minute
+
+// RemoveStaleTempFiles removes the *.tmp files in the sessions directory and its backups that are
+// older than olderThan, returning their paths
+// Saves write a temporary file and rename it into place, so a crash mid-save leaves one behind;
+// younger files may belong to a save still in progress and are left alone
+func (s *Storage) RemoveStaleTempFiles(olderThan time.Duration) ([]string, error) {
+	cutoff := s.clock.Now().Add(-olderThan)
+
+	var removed []string
+	err := filepath.WalkDir(s.sessionsDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil // no sessions yet, or removed while walking
+			}
+			return err
+		}
+		if entry.IsDir() || filepath.Ext(path) != ".tmp" {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		removed = append(removed, path)
+		return nil
+	})
+	return removed, err
+}
@@ -1,14 +1,18 @@
 package storage
 
 import (
+	"context"
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/bitomule/kamui/internal/clock"
 	"github.com/bitomule/kamui/pkg/types"
 )
 
@@ -29,7 +33,7 @@ func TestInitialize(t *testing.T) {
 	sessionsDir := filepath.Join(tempDir, ".claude", "kamui-sessions")
 	storage := NewWithSessionsDir(tempDir, sessionsDir)
 
-	err := storage.Initialize()
+	err := storage.Initialize(context.Background())
 	require.NoError(t, err)
 
 	info, err := os.Stat(sessionsDir)
@@ -44,7 +48,7 @@ func TestSessionExists(t *testing.T) {
 	storage := NewWithSessionsDir(tempDir, sessionsDir)
 
 	// Session should not exist initially
-	exists := storage.SessionExists("test-session")
+	exists := storage.SessionExists(context.Background(), "test-session")
 	assert.False(t, exists)
 
 	// Create session directory and file
@@ -54,7 +58,7 @@ func TestSessionExists(t *testing.T) {
 	require.NoError(t, os.WriteFile(sessionFile, []byte("{}"), 0o600))
 
 	// Session should exist now
-	exists = storage.SessionExists("test-session")
+	exists = storage.SessionExists(context.Background(), "test-session")
 	assert.True(t, exists)
 }
 
@@ -101,11 +105,11 @@ func TestSaveAndLoadSession(t *testing.T) {
 	originalSession.Metadata.Tags = []string{"test", "example"}
 
 	// Save the session
-	err = storage.SaveSession(originalSession)
+	err = storage.SaveSession(context.Background(), originalSession)
 	require.NoError(t, err)
 
 	// Load the session back
-	loadedSession, err := storage.LoadSession("test-session")
+	loadedSession, err := storage.LoadSession(context.Background(), "test-session")
 	require.NoError(t, err)
 
 	// Verify all fields match
@@ -128,7 +132,7 @@ func TestSaveSessionAtomic(t *testing.T) {
 	require.NoError(t, err)
 
 	// Save the session
-	err = storage.SaveSession(session)
+	err = storage.SaveSession(context.Background(), session)
 	require.NoError(t, err)
 
 	// Verify the temp file was cleaned up
@@ -140,13 +144,96 @@ func TestSaveSessionAtomic(t *testing.T) {
 	assert.Equal(t, "test-session.json", entries[0].Name())
 }
 
+func TestSaveSessionDurable(t *testing.T) {
+	tempDir := t.TempDir()
+	sessionsDir := filepath.Join(tempDir, ".claude", "kamui-sessions")
+	storage := NewWithSessionsDir(tempDir, sessionsDir)
+	storage.SetDurableWrites(true)
+
+	session, err := storage.CreateSession("test-session", tempDir)
+	require.NoError(t, err)
+	session.Metadata.Description = "first"
+	require.NoError(t, storage.SaveSession(context.Background(), session))
+	session.Metadata.Description = "second"
+	require.NoError(t, storage.SaveSession(context.Background(), session))
+
+	loaded, err := storage.LoadSession(context.Background(), "test-session")
+	require.NoError(t, err)
+	assert.Equal(t, "second", loaded.Metadata.Description)
+
+	tempFiles, err := filepath.Glob(filepath.Join(sessionsDir, "*.tmp"))
+	require.NoError(t, err)
+	assert.Empty(t, tempFiles, "no temp file is left behind")
+
+	info, err := os.Stat(filepath.Join(sessionsDir, "test-session.json"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestWriteAtomic_RemovesTempFileOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	require.NoError(t, os.Mkdir(target, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(target, "child"), nil, 0o600))
+
+	err := writeAtomic(target, []byte("{}"), true)
+
+	assert.Error(t, err, "a non-empty directory can't be replaced")
+	_, statErr := os.Stat(target + ".tmp")
+	assert.True(t, os.IsNotExist(statErr))
+}
+
+func TestRemoveStaleTempFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	sessionsDir := filepath.Join(tempDir, ".claude", "kamui-sessions")
+	storage := NewWithSessionsDir(tempDir, sessionsDir)
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	storage.SetClock(clock.NewFake(now))
+
+	// nothing to clean before the first session is saved
+	removed, err := storage.RemoveStaleTempFiles(StaleTempAge)
+	require.NoError(t, err)
+	assert.Empty(t, removed)
+
+	stale := filepath.Join(sessionsDir, "api.json.tmp")
+	staleBackup := filepath.Join(storage.GetBackupsPath("api"), "session.previous.json.tmp")
+	fresh := filepath.Join(sessionsDir, "web.json.tmp")
+	kept := filepath.Join(sessionsDir, "api.json")
+	for path, age := range map[string]time.Duration{stale: time.Hour, staleBackup: 10 * time.Minute, fresh: time.Minute, kept: time.Hour} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+		require.NoError(t, os.Chtimes(path, now.Add(-age), now.Add(-age)))
+	}
+
+	removed, err = storage.RemoveStaleTempFiles(StaleTempAge)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{stale, staleBackup}, removed)
+	for _, path := range []string{stale, staleBackup} {
+		_, statErr := os.Stat(path)
+		assert.True(t, os.IsNotExist(statErr), path)
+	}
+	for _, path := range []string{fresh, kept} {
+		assert.FileExists(t, path)
+	}
+}
+
+func TestPermanentUnlessTransient(t *testing.T) {
+	busy := &os.PathError{Op: "rename", Path: "a.json", Err: syscall.EBUSY}
+	assert.Same(t, error(busy), permanentUnlessTransient(busy), "busy files are retried")
+	assert.True(t, writePolicy.Retryable(permanentUnlessTransient(busy)))
+
+	denied := &os.PathError{Op: "open", Path: "a.json", Err: syscall.EACCES}
+	assert.False(t, writePolicy.Retryable(permanentUnlessTransient(denied)))
+	assert.ErrorIs(t, permanentUnlessTransient(denied), syscall.EACCES)
+}
+
 func TestLoadSessionNotFound(t *testing.T) {
 	tempDir := t.TempDir()
 	sessionsDir := filepath.Join(tempDir, ".claude", "kamui-sessions")
 	storage := NewWithSessionsDir(tempDir, sessionsDir)
 
 	// Try to load non-existent session
-	_, err := storage.LoadSession("non-existent")
+	_, err := storage.LoadSession(context.Background(), "non-existent")
 	require.Error(t, err)
 
 	// Should be a storage error with correct code
@@ -155,13 +242,35 @@ func TestLoadSessionNotFound(t *testing.T) {
 	assert.Equal(t, types.ErrCodeSessionNotFound, agxErr.Code)
 }
 
+func TestCanceledContext(t *testing.T) {
+	tempDir := t.TempDir()
+	sessionsDir := filepath.Join(tempDir, ".claude", "kamui-sessions")
+	storage := NewWithSessionsDir(tempDir, sessionsDir)
+
+	session, err := storage.CreateSession("test-session", tempDir)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = storage.SaveSession(ctx, session)
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeInterrupted, agxErr.Code)
+	assert.NoDirExists(t, sessionsDir, "nothing is written once canceled")
+
+	_, err = storage.ListSessions(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, storage.SessionExists(ctx, "test-session"))
+}
+
 func TestListSessions(t *testing.T) {
 	tempDir := t.TempDir()
 	sessionsDir := filepath.Join(tempDir, ".claude", "kamui-sessions")
 	storage := NewWithSessionsDir(tempDir, sessionsDir)
 
 	// Initially should return empty slice
-	sessions, err := storage.ListSessions()
+	sessions, err := storage.ListSessions(context.Background())
 	require.NoError(t, err)
 	assert.Empty(t, sessions)
 
@@ -170,12 +279,12 @@ func TestListSessions(t *testing.T) {
 	for _, name := range sessionNames {
 		session, createErr := storage.CreateSession(name, tempDir)
 		require.NoError(t, createErr)
-		saveErr := storage.SaveSession(session)
+		saveErr := storage.SaveSession(context.Background(), session)
 		require.NoError(t, saveErr)
 	}
 
 	// List sessions should return all created sessions
-	sessions, err = storage.ListSessions()
+	sessions, err = storage.ListSessions(context.Background())
 	require.NoError(t, err)
 	assert.Len(t, sessions, 3)
 
@@ -198,19 +307,19 @@ func TestDeleteSession(t *testing.T) {
 	// Create and save a session
 	session, err := storage.CreateSession("test-session", tempDir)
 	require.NoError(t, err)
-	err = storage.SaveSession(session)
+	err = storage.SaveSession(context.Background(), session)
 	require.NoError(t, err)
 
 	// Verify it exists
-	exists := storage.SessionExists("test-session")
+	exists := storage.SessionExists(context.Background(), "test-session")
 	assert.True(t, exists)
 
 	// Delete the session
-	err = storage.DeleteSession("test-session")
+	err = storage.DeleteSession(context.Background(), "test-session")
 	require.NoError(t, err)
 
 	// Verify it no longer exists
-	exists = storage.SessionExists("test-session")
+	exists = storage.SessionExists(context.Background(), "test-session")
 	assert.False(t, exists)
 }
 
@@ -220,7 +329,7 @@ func TestDeleteSessionNotFound(t *testing.T) {
 	storage := NewWithSessionsDir(tempDir, sessionsDir)
 
 	// Try to delete non-existent session
-	err := storage.DeleteSession("non-existent")
+	err := storage.DeleteSession(context.Background(), "non-existent")
 	require.Error(t, err)
 
 	// Should be a storage error with correct code
@@ -238,23 +347,49 @@ func TestUpdateSessionAccess(t *testing.T) {
 	session, err := storage.CreateSession("test-session", tempDir)
 	require.NoError(t, err)
 	originalAccessTime := session.LastAccessed
-	err = storage.SaveSession(session)
+	err = storage.SaveSession(context.Background(), session)
 	require.NoError(t, err)
 
 	// Wait a bit to ensure timestamp difference
 	time.Sleep(10 * time.Millisecond)
 
 	// Update session access
-	err = storage.UpdateSessionAccess("test-session")
+	err = storage.UpdateSessionAccess(context.Background(), "test-session")
 	require.NoError(t, err)
 
 	// Reload and verify access time was updated
-	updatedSession, err := storage.LoadSession("test-session")
+	updatedSession, err := storage.LoadSession(context.Background(), "test-session")
 	require.NoError(t, err)
 
 	assert.True(t, updatedSession.LastAccessed.After(originalAccessTime))
 }
 
+func TestSimulatedClock(t *testing.T) {
+	tempDir := t.TempDir()
+	storage := NewWithSessionsDir(tempDir, filepath.Join(tempDir, ".claude", "kamui-sessions"))
+
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	storage.SetClock(fake)
+
+	session, err := storage.CreateSession("test-session", tempDir)
+	require.NoError(t, err)
+	assert.Equal(t, start, session.Created)
+	require.NoError(t, storage.SaveSession(context.Background(), session))
+
+	fake.Advance(time.Hour)
+	require.NoError(t, storage.UpdateSessionAccess(context.Background(), "test-session"))
+	require.NoError(t, storage.LockSession(context.Background(), "test-session"))
+
+	updated, err := storage.LoadSession(context.Background(), "test-session")
+	require.NoError(t, err)
+	assert.Equal(t, start.Add(time.Hour), updated.LastAccessed)
+
+	lock, err := storage.ReadLock(context.Background(), "test-session")
+	require.NoError(t, err)
+	assert.Equal(t, start.Add(time.Hour), lock.Acquired)
+}
+
 func TestGetProjectPath(t *testing.T) {
 	projectPath := "/tmp/test-project"
 	storage := New(projectPath)
@@ -269,3 +404,44 @@ func TestGetSessionsPath(t *testing.T) {
 
 	assert.Equal(t, sessionsDir, storage.GetSessionsPath())
 }
+
+func TestGetBackupsPath(t *testing.T) {
+	tempDir := t.TempDir()
+	sessionsDir := filepath.Join(tempDir, ".claude", "kamui-sessions")
+	storage := NewWithSessionsDir(tempDir, sessionsDir)
+
+	assert.Equal(t, filepath.Join(sessionsDir, "backups", "my-session"), storage.GetBackupsPath("my-session"))
+}
+
+func TestSaveSessionKeepsPreviousVersion(t *testing.T) {
+	tempDir := t.TempDir()
+	sessionsDir := filepath.Join(tempDir, ".claude", "kamui-sessions")
+	storage := NewWithSessionsDir(tempDir, sessionsDir)
+	ctx := context.Background()
+
+	session, err := storage.CreateSession("test-session", tempDir)
+	require.NoError(t, err)
+	session.Metadata.Description = "first"
+	require.NoError(t, storage.SaveSession(ctx, session))
+	_, err = os.Stat(storage.PreviousVersionPath("test-session"))
+	assert.True(t, os.IsNotExist(err), "nothing is replaced by the first save")
+
+	session.Metadata.Description = "second"
+	require.NoError(t, storage.SaveSession(ctx, session))
+	assert.Equal(t, "first", readDescription(t, storage.PreviousVersionPath("test-session")))
+
+	// a damaged file doesn't replace the last good copy
+	require.NoError(t, os.WriteFile(filepath.Join(sessionsDir, "test-session.json"), []byte(`{"version":`), 0o600))
+	session.Metadata.Description = "third"
+	require.NoError(t, storage.SaveSession(ctx, session))
+	assert.Equal(t, "first", readDescription(t, storage.PreviousVersionPath("test-session")))
+}
+
+func readDescription(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var session types.Session
+	require.NoError(t, json.Unmarshal(data, &session))
+	return session.Metadata.Description
+}
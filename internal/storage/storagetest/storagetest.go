@@ -0,0 +1,216 @@
+// Package storagetest is the conformance suite every storage backend must pass
+//
+// A backend's tests call Run with a function opening a fresh, empty instance:
+//
+//	func TestConformance(t *testing.T) {
+//		storagetest.Run(t, func(t *testing.T, projectPath string) storage.Interface {
+//			return mybackend.New(projectPath)
+//		})
+//	}
+package storagetest
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// OpenFunc returns a new, empty backend for the project
+type OpenFunc func(t *testing.T, projectPath string) storage.Interface
+
+// Run checks that the backend behaves the way the session manager expects
+func Run(t *testing.T, open OpenFunc) {
+	t.Helper()
+
+	tests := []struct {
+		name string
+		test func(t *testing.T, store storage.Interface)
+	}{
+		{"CreateSession", testCreateSession},
+		{"SaveAndLoad", testSaveAndLoad},
+		{"SavedSessionsAreCopies", testSavedSessionsAreCopies},
+		{"LoadMissing", testLoadMissing},
+		{"ListSessions", testListSessions},
+		{"DeleteSession", testDeleteSession},
+		{"UpdateSessionAccess", testUpdateSessionAccess},
+		{"Locks", testLocks},
+		{"CanceledContext", testCanceledContext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectPath := t.TempDir()
+			store := open(t, projectPath)
+			require.NoError(t, store.Initialize(context.Background()))
+			tt.test(t, store)
+		})
+	}
+}
+
+// requireCode fails unless err is an AGXError with code
+func requireCode(t *testing.T, err error, code types.ErrorCode) {
+	t.Helper()
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, code, agxErr.Code)
+}
+
+func testCreateSession(t *testing.T, store storage.Interface) {
+	ctx := context.Background()
+
+	session, err := store.CreateSession("api", store.GetProjectPath())
+	require.NoError(t, err)
+
+	assert.Equal(t, "api", session.SessionID)
+	assert.Equal(t, store.GetProjectPath(), session.Project.Path)
+	assert.Equal(t, types.SessionStateActive, session.Lifecycle.State)
+	assert.False(t, session.Created.IsZero())
+	assert.False(t, store.SessionExists(ctx, "api"), "created sessions are stored only once saved")
+}
+
+func testSaveAndLoad(t *testing.T, store storage.Interface) {
+	ctx := context.Background()
+
+	session, err := store.CreateSession("api", store.GetProjectPath())
+	require.NoError(t, err)
+	session.Claude.SessionID = "claude-123"
+	session.Metadata.Description = "Payment API"
+	session.Metadata.Tags = []string{"backend"}
+	require.NoError(t, store.SaveSession(ctx, session))
+
+	assert.True(t, store.SessionExists(ctx, "api"))
+	loaded, err := store.LoadSession(ctx, "api")
+	require.NoError(t, err)
+	assert.Equal(t, "claude-123", loaded.Claude.SessionID)
+	assert.Equal(t, "Payment API", loaded.Metadata.Description)
+	assert.Equal(t, []string{"backend"}, loaded.Metadata.Tags)
+	assert.True(t, session.Created.Equal(loaded.Created))
+
+	loaded.Metadata.Description = "Billing API"
+	require.NoError(t, store.SaveSession(ctx, loaded))
+	reloaded, err := store.LoadSession(ctx, "api")
+	require.NoError(t, err)
+	assert.Equal(t, "Billing API", reloaded.Metadata.Description, "saving again replaces the session")
+}
+
+func testSavedSessionsAreCopies(t *testing.T, store storage.Interface) {
+	ctx := context.Background()
+
+	session, err := store.CreateSession("api", store.GetProjectPath())
+	require.NoError(t, err)
+	require.NoError(t, store.SaveSession(ctx, session))
+
+	session.Metadata.Description = "changed after saving"
+	loaded, err := store.LoadSession(ctx, "api")
+	require.NoError(t, err)
+	assert.Empty(t, loaded.Metadata.Description)
+
+	loaded.Metadata.Description = "changed after loading"
+	again, err := store.LoadSession(ctx, "api")
+	require.NoError(t, err)
+	assert.Empty(t, again.Metadata.Description)
+}
+
+func testLoadMissing(t *testing.T, store storage.Interface) {
+	_, err := store.LoadSession(context.Background(), "missing")
+	requireCode(t, err, types.ErrCodeSessionNotFound)
+	assert.False(t, store.SessionExists(context.Background(), "missing"))
+}
+
+func testListSessions(t *testing.T, store storage.Interface) {
+	ctx := context.Background()
+
+	names, err := store.ListSessions(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, names)
+
+	for _, name := range []string{"web", "api", "docs"} {
+		session, err := store.CreateSession(name, store.GetProjectPath())
+		require.NoError(t, err)
+		require.NoError(t, store.SaveSession(ctx, session))
+	}
+	require.NoError(t, store.LockSession(ctx, "api"))
+
+	names, err = store.ListSessions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api", "docs", "web"}, names, "sorted, and locks aren't sessions")
+}
+
+func testDeleteSession(t *testing.T, store storage.Interface) {
+	ctx := context.Background()
+
+	session, err := store.CreateSession("api", store.GetProjectPath())
+	require.NoError(t, err)
+	require.NoError(t, store.SaveSession(ctx, session))
+
+	require.NoError(t, store.DeleteSession(ctx, "api"))
+	assert.False(t, store.SessionExists(ctx, "api"))
+	requireCode(t, store.DeleteSession(ctx, "api"), types.ErrCodeSessionNotFound)
+}
+
+func testUpdateSessionAccess(t *testing.T, store storage.Interface) {
+	ctx := context.Background()
+
+	session, err := store.CreateSession("api", store.GetProjectPath())
+	require.NoError(t, err)
+	session.LastAccessed = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, store.SaveSession(ctx, session))
+
+	require.NoError(t, store.UpdateSessionAccess(ctx, "api"))
+	loaded, err := store.LoadSession(ctx, "api")
+	require.NoError(t, err)
+	assert.True(t, loaded.LastAccessed.After(session.LastAccessed))
+
+	requireCode(t, store.UpdateSessionAccess(ctx, "missing"), types.ErrCodeSessionNotFound)
+}
+
+func testLocks(t *testing.T, store storage.Interface) {
+	ctx := context.Background()
+
+	lock, err := store.ReadLock(ctx, "api")
+	require.NoError(t, err)
+	assert.Nil(t, lock)
+
+	require.NoError(t, store.LockSession(ctx, "api"))
+	lock, err = store.ReadLock(ctx, "api")
+	require.NoError(t, err)
+	require.NotNil(t, lock)
+	assert.Equal(t, os.Getpid(), lock.PID)
+
+	err = store.LockSession(ctx, "api")
+	requireCode(t, err, types.ErrCodeSessionLocked)
+	require.NoError(t, store.LockSession(ctx, "web"), "locks are per session")
+
+	require.NoError(t, store.UnlockSession(ctx, "api"))
+	require.NoError(t, store.UnlockSession(ctx, "api"), "unlocking twice is fine")
+	lock, err = store.ReadLock(ctx, "api")
+	require.NoError(t, err)
+	assert.Nil(t, lock)
+	require.NoError(t, store.LockSession(ctx, "api"))
+}
+
+func testCanceledContext(t *testing.T, store storage.Interface) {
+	session, err := store.CreateSession("api", store.GetProjectPath())
+	require.NoError(t, err)
+	require.NoError(t, store.SaveSession(context.Background(), session))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	requireCode(t, store.SaveSession(ctx, session), types.ErrCodeInterrupted)
+	_, err = store.LoadSession(ctx, "api")
+	requireCode(t, err, types.ErrCodeInterrupted)
+	_, err = store.ListSessions(ctx)
+	requireCode(t, err, types.ErrCodeInterrupted)
+	requireCode(t, store.DeleteSession(ctx, "api"), types.ErrCodeInterrupted)
+	requireCode(t, store.LockSession(ctx, "api"), types.ErrCodeInterrupted)
+	assert.False(t, store.SessionExists(ctx, "api"))
+	assert.True(t, store.SessionExists(context.Background(), "api"), "nothing was deleted")
+}
@@ -0,0 +1,23 @@
+//go:build !windows
+
+package storage
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// syncDir flushes a directory entry change such as a rename to disk
+// Filesystems that can't sync directories report EINVAL, which is not treated as a failure
+func syncDir(dir string) error {
+	handle, err := os.Open(dir) // #nosec G304 -- dir is the sessions directory
+	if err != nil {
+		return err
+	}
+	defer handle.Close()
+	if err := handle.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+		return err
+	}
+	return nil
+}
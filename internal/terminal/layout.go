@@ -0,0 +1,46 @@
+package terminal
+
+import (
+	"os"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// ellipsis replaces the text cut out by the Truncate functions
+const ellipsis = "…"
+
+// Width returns the width in columns of the terminal f is attached to, or 0 when it isn't one
+func Width(f *os.File) int {
+	width, _, err := term.GetSize(int(f.Fd()))
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
+// TruncateEnd shortens text to at most width runes, ending it with an ellipsis when cut
+func TruncateEnd(text string, width int) string {
+	if utf8.RuneCountInString(text) <= width {
+		return text
+	}
+	if width < 1 {
+		return ""
+	}
+	return string([]rune(text)[:width-1]) + ellipsis
+}
+
+// TruncateMiddle shortens text to at most width runes by replacing its middle with an ellipsis,
+// which keeps both ends of a path readable
+func TruncateMiddle(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	if width < 1 {
+		return ""
+	}
+	tail := (width - 1) / 2
+	head := width - 1 - tail
+	return string(runes[:head]) + ellipsis + string(runes[len(runes)-tail:])
+}
@@ -0,0 +1,30 @@
+package terminal
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWidth_NotATerminal(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "out")
+	assert.NoError(t, err)
+	defer file.Close()
+	assert.Equal(t, 0, Width(file))
+}
+
+func TestTruncateEnd(t *testing.T) {
+	assert.Equal(t, "refactor", TruncateEnd("refactor", 8))
+	assert.Equal(t, "refac…", TruncateEnd("refactor", 6))
+	assert.Equal(t, "…", TruncateEnd("refactor", 1))
+	assert.Equal(t, "", TruncateEnd("refactor", 0))
+}
+
+func TestTruncateMiddle(t *testing.T) {
+	assert.Equal(t, "/src/app", TruncateMiddle("/src/app", 8))
+	assert.Equal(t, "/Users/…/my.app", TruncateMiddle("/Users/ada/code/my.app", 15))
+	assert.Equal(t, "/U…p", TruncateMiddle("/Users/ada/code/my.app", 4))
+	assert.Equal(t, "…", TruncateMiddle("/Users/ada", 1))
+	assert.Equal(t, "", TruncateMiddle("/Users/ada", 0))
+}
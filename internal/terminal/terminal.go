@@ -0,0 +1,174 @@
+// Package terminal emits terminal-specific escape sequences for tab titles, user variables and
+// notifications, and fits text to the terminal width
+package terminal
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Kind identifies the terminal emulator Kamui is running in
+type Kind string
+
+const (
+	// KindGeneric is any other terminal, which only gets the window title
+	KindGeneric Kind = "generic"
+	// KindKitty is kitty, which takes tab titles and user variables
+	KindKitty Kind = "kitty"
+	// KindWezTerm is WezTerm, which takes tab titles and user variables
+	KindWezTerm Kind = "wezterm"
+	// KindITerm2 is iTerm2, which also shows a badge and switches profiles
+	KindITerm2 Kind = "iterm2"
+)
+
+// SessionInfo is what Kamui publishes to the terminal about the running session
+type SessionInfo struct {
+	Name       string
+	State      string
+	WorkingDir string
+
+	// Badge is shown as the iTerm2 session badge; empty leaves the badge untouched
+	Badge string
+
+	// Profile switches iTerm2 to the named profile; empty keeps the current profile
+	Profile string
+}
+
+// Terminal writes escape sequences appropriate for the detected emulator
+type Terminal struct {
+	kind   Kind
+	inTmux bool
+	out    io.Writer
+}
+
+// Detect inspects the environment to determine the terminal emulator
+func Detect(getenv func(string) string) Kind {
+	switch {
+	case getenv("KITTY_WINDOW_ID") != "" || getenv("TERM") == "xterm-kitty":
+		return KindKitty
+	case getenv("WEZTERM_PANE") != "" || getenv("TERM_PROGRAM") == "WezTerm":
+		return KindWezTerm
+	case getenv("TERM_PROGRAM") == "iTerm.app" || getenv("ITERM_SESSION_ID") != "":
+		return KindITerm2
+	default:
+		return KindGeneric
+	}
+}
+
+// New returns a Terminal for the current process environment writing to out
+func New(out io.Writer) *Terminal {
+	return &Terminal{
+		kind:   Detect(os.Getenv),
+		inTmux: os.Getenv("TMUX") != "",
+		out:    out,
+	}
+}
+
+// NewForKind returns a Terminal for an explicit emulator, mainly for tests
+func NewForKind(kind Kind, inTmux bool, out io.Writer) *Terminal {
+	return &Terminal{kind: kind, inTmux: inTmux, out: out}
+}
+
+// Kind returns the detected terminal emulator
+func (t *Terminal) Kind() Kind {
+	return t.kind
+}
+
+// AnnounceSession sets the window/tab title and, where supported, user variables
+// and the working directory so terminal-native tab pickers can show the session
+func (t *Terminal) AnnounceSession(info SessionInfo) {
+	title := fmt.Sprintf("Claude - %s", info.Name)
+
+	// Window title works everywhere, tmux included, so it is never wrapped
+	fmt.Fprint(t.out, osc("0;"+title))
+
+	switch t.kind {
+	case KindKitty, KindWezTerm, KindITerm2:
+		// Tab title (OSC 1) and user variables (OSC 1337 SetUserVar)
+		t.write(osc("1;" + title))
+		t.write(SetUserVar("kamui_session", info.Name))
+		t.write(SetUserVar("kamui_state", info.State))
+		if info.WorkingDir != "" {
+			t.write(WorkingDirectory(info.WorkingDir))
+		}
+	}
+
+	if t.kind == KindITerm2 {
+		if info.Badge != "" {
+			t.write(SetBadge(info.Badge))
+		}
+		if info.Profile != "" {
+			t.write(SetProfile(info.Profile))
+		}
+	}
+}
+
+// Bell rings the terminal bell; tmux passes it on and flags the window it came from
+func (t *Terminal) Bell() {
+	fmt.Fprint(t.out, "\a")
+}
+
+// Notify posts a desktop notification through the terminal (OSC 9), which iTerm2, kitty,
+// WezTerm and others show
+func (t *Terminal) Notify(message string) {
+	t.write(Notification(message))
+}
+
+// Notification returns the OSC 9 sequence that posts a notification, without control characters
+// that would end it early
+func Notification(message string) string {
+	message = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, message)
+	return osc("9;" + message)
+}
+
+// SetBadge returns the iTerm2 sequence that sets the session badge
+func SetBadge(text string) string {
+	return osc("1337;SetBadgeFormat=" + base64.StdEncoding.EncodeToString([]byte(text)))
+}
+
+// SetProfile returns the iTerm2 sequence that switches the session to a named profile
+func SetProfile(name string) string {
+	return osc("1337;SetProfile=" + name)
+}
+
+// SetUserVar returns the OSC 1337 sequence that sets a terminal user variable
+func SetUserVar(name, value string) string {
+	return osc(fmt.Sprintf("1337;SetUserVar=%s=%s", name, base64.StdEncoding.EncodeToString([]byte(value))))
+}
+
+// WorkingDirectory returns the OSC 7 sequence that reports the current directory
+func WorkingDirectory(dir string) string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = ""
+	}
+	u := url.URL{Scheme: "file", Host: host, Path: dir}
+	return osc("7;" + u.String())
+}
+
+// write emits a sequence, wrapping it for tmux passthrough when needed
+func (t *Terminal) write(sequence string) {
+	if t.inTmux {
+		sequence = tmuxPassthrough(sequence)
+	}
+	fmt.Fprint(t.out, sequence)
+}
+
+// osc builds an Operating System Command sequence terminated by BEL
+func osc(body string) string {
+	return "\033]" + body + "\007"
+}
+
+// tmuxPassthrough wraps a sequence so tmux forwards it to the outer terminal
+func tmuxPassthrough(sequence string) string {
+	return "\033Ptmux;" + strings.ReplaceAll(sequence, "\033", "\033\033") + "\033\\"
+}
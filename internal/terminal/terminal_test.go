@@ -0,0 +1,88 @@
+package terminal
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func envFrom(values map[string]string) func(string) string {
+	return func(key string) string { return values[key] }
+}
+
+func TestDetect(t *testing.T) {
+	assert.Equal(t, KindKitty, Detect(envFrom(map[string]string{"KITTY_WINDOW_ID": "1"})))
+	assert.Equal(t, KindKitty, Detect(envFrom(map[string]string{"TERM": "xterm-kitty"})))
+	assert.Equal(t, KindWezTerm, Detect(envFrom(map[string]string{"TERM_PROGRAM": "WezTerm"})))
+	assert.Equal(t, KindWezTerm, Detect(envFrom(map[string]string{"WEZTERM_PANE": "0"})))
+	assert.Equal(t, KindITerm2, Detect(envFrom(map[string]string{"TERM_PROGRAM": "iTerm.app"})))
+	assert.Equal(t, KindGeneric, Detect(envFrom(map[string]string{"TERM": "xterm-256color"})))
+}
+
+func TestSetUserVar(t *testing.T) {
+	// "api" base64-encodes to "YXBp"
+	assert.Equal(t, "\033]1337;SetUserVar=kamui_session=YXBp\007", SetUserVar("kamui_session", "api"))
+}
+
+func TestAnnounceSession_Generic(t *testing.T) {
+	var out bytes.Buffer
+	NewForKind(KindGeneric, false, &out).AnnounceSession(SessionInfo{Name: "api", State: "active"})
+
+	assert.Equal(t, "\033]0;Claude - api\007", out.String())
+}
+
+func TestAnnounceSession_WezTerm(t *testing.T) {
+	var out bytes.Buffer
+	NewForKind(KindWezTerm, false, &out).AnnounceSession(SessionInfo{Name: "api", State: "active", WorkingDir: "/src/api"})
+
+	s := out.String()
+	assert.Contains(t, s, "\033]0;Claude - api\007")
+	assert.Contains(t, s, "\033]1;Claude - api\007")
+	assert.Contains(t, s, SetUserVar("kamui_session", "api"))
+	assert.Contains(t, s, SetUserVar("kamui_state", "active"))
+	assert.Contains(t, s, "\033]7;file://")
+	assert.Contains(t, s, "/src/api\007")
+}
+
+func TestAnnounceSession_Tmux(t *testing.T) {
+	var out bytes.Buffer
+	NewForKind(KindKitty, true, &out).AnnounceSession(SessionInfo{Name: "api"})
+
+	s := out.String()
+	// The window title goes to tmux itself, everything else is passed through
+	assert.Contains(t, s, "\033]0;Claude - api\007\033Ptmux;")
+	assert.Contains(t, s, "\033Ptmux;\033\033]1;Claude - api\007\033\\")
+}
+
+func TestAnnounceSession_ITerm2BadgeAndProfile(t *testing.T) {
+	var out bytes.Buffer
+	NewForKind(KindITerm2, false, &out).AnnounceSession(SessionInfo{Name: "api", Badge: "api", Profile: "Review"})
+
+	s := out.String()
+	assert.Contains(t, s, "\033]1337;SetBadgeFormat=YXBp\007")
+	assert.Contains(t, s, "\033]1337;SetProfile=Review\007")
+}
+
+func TestAnnounceSession_BadgeIgnoredOutsideITerm2(t *testing.T) {
+	var out bytes.Buffer
+	NewForKind(KindWezTerm, false, &out).AnnounceSession(SessionInfo{Name: "api", Badge: "api", Profile: "Review"})
+
+	assert.NotContains(t, out.String(), "SetBadgeFormat")
+	assert.NotContains(t, out.String(), "SetProfile")
+}
+
+func TestBellAndNotify(t *testing.T) {
+	var out bytes.Buffer
+	term := NewForKind(KindGeneric, false, &out)
+	term.Bell()
+	term.Notify("done\a\033]0;x")
+	assert.Equal(t, "\a\033]9;done]0;x\007", out.String())
+
+	// in tmux the bell flags the window, the notification is passed through
+	out.Reset()
+	term = NewForKind(KindGeneric, true, &out)
+	term.Bell()
+	term.Notify("done")
+	assert.Equal(t, "\a\033Ptmux;\033\033]9;done\007\033\\", out.String())
+}
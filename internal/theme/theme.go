@@ -0,0 +1,286 @@
+// Package theme turns color themes into the terminal styles kam's output is painted with
+package theme
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Role is the part a piece of text plays in kam's output; themes style each role
+type Role string
+
+const (
+	// Accent marks frames and headings, such as the session banner and picker numbers
+	Accent Role = "accent"
+	// Emphasis marks session names
+	Emphasis Role = "emphasis"
+	// Muted marks hints and secondary details
+	Muted Role = "muted"
+	// Success marks active and running sessions
+	Success Role = "success"
+	// Warning marks paused sessions and budgets nearing their limit
+	Warning Role = "warning"
+	// Error marks sessions in error and exceeded budgets
+	Error Role = "error"
+	// Match marks search matches
+	Match Role = "match"
+)
+
+// Roles lists every role, in the order themes list them
+var Roles = []Role{Accent, Emphasis, Muted, Success, Warning, Error, Match}
+
+// Default is the theme used when none is configured
+const Default = "default"
+
+// builtins are the themes every install has; each styles every role
+var builtins = map[string]types.ThemeConfig{
+	Default: {
+		Accent: "bright-cyan", Emphasis: "bold", Muted: "gray",
+		Success: "green", Warning: "yellow", Error: "red", Match: "bold red",
+	},
+	"solarized": {
+		Accent: "#2aa198", Emphasis: "bold #268bd2", Muted: "#586e75",
+		Success: "#859900", Warning: "#b58900", Error: "#dc322f", Match: "bold #d33682",
+	},
+	"high-contrast": {
+		Accent: "bold bright-cyan", Emphasis: "bold bright-white", Muted: "white",
+		Success: "bold bright-green", Warning: "bold bright-yellow", Error: "bold bright-red", Match: "bold reverse",
+	},
+	"monochrome": {
+		Accent: "bold", Emphasis: "bold", Muted: "dim",
+		Success: "none", Warning: "bold", Error: "bold underline", Match: "reverse",
+	},
+}
+
+// Builtins lists the names of the built-in themes
+func Builtins() []string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolve returns the named theme with every role styled, looking in custom before the built-in
+// themes; names are case-insensitive and an empty name is the default theme
+// Roles a custom theme leaves empty take their style from its base, the default theme unless set
+func Resolve(name string, custom map[string]types.ThemeConfig) (types.ThemeConfig, error) {
+	return resolve(name, custom, map[string]bool{})
+}
+
+func resolve(name string, custom map[string]types.ThemeConfig, seen map[string]bool) (types.ThemeConfig, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = Default
+	}
+	if seen[name] {
+		return types.ThemeConfig{}, fmt.Errorf("theme '%s' is its own base", name)
+	}
+	seen[name] = true
+
+	for customName, theme := range custom {
+		if strings.ToLower(customName) != name {
+			continue
+		}
+		base := strings.ToLower(strings.TrimSpace(theme.Base))
+		if builtin, ok := builtins[name]; ok && (base == "" || base == name) {
+			// a custom theme named like a built-in one refines it
+			return merge(theme, builtin), nil
+		}
+		baseTheme, err := resolve(base, custom, seen)
+		if err != nil {
+			return types.ThemeConfig{}, err
+		}
+		return merge(theme, baseTheme), nil
+	}
+
+	theme, ok := builtins[name]
+	if !ok {
+		return types.ThemeConfig{}, fmt.Errorf("unknown theme '%s' (built-in themes: %s)", name, strings.Join(Builtins(), ", "))
+	}
+	return theme, nil
+}
+
+// merge fills the roles theme leaves empty from base
+func merge(theme, base types.ThemeConfig) types.ThemeConfig {
+	merged := types.ThemeConfig{}
+	for _, role := range Roles {
+		value := Style(theme, role)
+		if value == "" {
+			value = Style(base, role)
+		}
+		setStyle(&merged, role, value)
+	}
+	return merged
+}
+
+// Style returns the style a theme gives a role
+func Style(theme types.ThemeConfig, role Role) string {
+	switch role {
+	case Accent:
+		return theme.Accent
+	case Emphasis:
+		return theme.Emphasis
+	case Muted:
+		return theme.Muted
+	case Success:
+		return theme.Success
+	case Warning:
+		return theme.Warning
+	case Error:
+		return theme.Error
+	case Match:
+		return theme.Match
+	}
+	return ""
+}
+
+func setStyle(theme *types.ThemeConfig, role Role, style string) {
+	switch role {
+	case Accent:
+		theme.Accent = style
+	case Emphasis:
+		theme.Emphasis = style
+	case Muted:
+		theme.Muted = style
+	case Success:
+		theme.Success = style
+	case Warning:
+		theme.Warning = style
+	case Error:
+		theme.Error = style
+	case Match:
+		theme.Match = style
+	}
+}
+
+// attributes are the style words for text attributes, by SGR parameter
+var attributes = map[string]string{
+	"bold":      "1",
+	"dim":       "2",
+	"italic":    "3",
+	"underline": "4",
+	"reverse":   "7",
+}
+
+// colors are the basic color names by their SGR offset; gray is bright black
+var colors = map[string]int{
+	"black": 0, "red": 1, "green": 2, "yellow": 3, "blue": 4, "magenta": 5, "cyan": 6, "white": 7,
+}
+
+var (
+	rawPattern = regexp.MustCompile(`^[0-9]+(;[0-9]+)*$`)
+	hexPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+)
+
+// ParseStyle turns a style into SGR parameters: space-separated words among bold, dim, italic,
+// underline, reverse, a color (black, red, green, yellow, blue, magenta, cyan, white, gray, their
+// bright- variants or #rrggbb) and a background color prefixed with on-; raw SGR parameters such as
+// "38;5;33" are taken as is, and "none" or "" leave text unstyled
+func ParseStyle(style string) (string, error) {
+	style = strings.TrimSpace(style)
+	if style == "" || strings.EqualFold(style, "none") {
+		return "", nil
+	}
+	if rawPattern.MatchString(style) {
+		return style, nil
+	}
+
+	var params []string
+	for _, word := range strings.Fields(strings.ToLower(style)) {
+		if code, ok := attributes[word]; ok {
+			params = append(params, code)
+			continue
+		}
+		background, color := false, word
+		if rest, ok := strings.CutPrefix(word, "on-"); ok {
+			background, color = true, rest
+		}
+		code, ok := colorCode(color, background)
+		if !ok {
+			return "", fmt.Errorf("invalid style '%s': unknown word '%s'", style, word)
+		}
+		params = append(params, code)
+	}
+	return strings.Join(params, ";"), nil
+}
+
+// colorCode returns the SGR parameters for a color name or #rrggbb
+func colorCode(color string, background bool) (string, bool) {
+	base := 30
+	if background {
+		base = 40
+	}
+	if hexPattern.MatchString(color) {
+		rgb, _ := strconv.ParseUint(color[1:], 16, 32)
+		return fmt.Sprintf("%d;2;%d;%d;%d", base+8, rgb>>16, rgb>>8&0xff, rgb&0xff), true
+	}
+	if color == "gray" || color == "grey" {
+		return strconv.Itoa(base + 60), true
+	}
+	bright := false
+	if rest, ok := strings.CutPrefix(color, "bright-"); ok {
+		bright, color = true, rest
+	}
+	offset, ok := colors[color]
+	if !ok {
+		return "", false
+	}
+	if bright {
+		base += 60
+	}
+	return strconv.Itoa(base + offset), true
+}
+
+// Painter styles text by role
+type Painter struct {
+	codes map[Role]string
+}
+
+// New compiles a theme into a painter
+func New(theme types.ThemeConfig) (*Painter, error) {
+	p := &Painter{codes: map[Role]string{}}
+	for _, role := range Roles {
+		code, err := ParseStyle(Style(theme, role))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", role, err)
+		}
+		p.codes[role] = code
+	}
+	return p, nil
+}
+
+// Plain returns a painter that leaves text unstyled, for output without color
+func Plain() *Painter {
+	return &Painter{codes: map[Role]string{}}
+}
+
+// Paint styles text for a role
+func (p *Painter) Paint(role Role, text string) string {
+	code := p.codes[role]
+	if code == "" || text == "" {
+		return text
+	}
+	return "\033[" + code + "m" + text + "\033[0m"
+}
+
+// Code returns the SGR parameters for a role, "" when it is unstyled
+func (p *Painter) Code(role Role) string {
+	return p.codes[role]
+}
+
+// Environment returns KAMUI_COLOR_<ROLE> variables holding each role's SGR parameters, for
+// scripts such as the Claude Code status line to match kam's colors
+func (p *Painter) Environment() []string {
+	env := make([]string, 0, len(Roles))
+	for _, role := range Roles {
+		env = append(env, fmt.Sprintf("KAMUI_COLOR_%s=%s", strings.ToUpper(string(role)), p.codes[role]))
+	}
+	return env
+}
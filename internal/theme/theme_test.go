@@ -0,0 +1,125 @@
+package theme
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestParseStyle(t *testing.T) {
+	tests := map[string]string{
+		"":                     "",
+		"none":                 "",
+		"bold":                 "1",
+		"red":                  "31",
+		"bright-cyan":          "96",
+		"gray":                 "90",
+		"bold underline green": "1;4;32",
+		"on-blue":              "44",
+		"on-bright-white":      "107",
+		"#268bd2":              "38;2;38;139;210",
+		"on-#000000":           "48;2;0;0;0",
+		"38;5;33":              "38;5;33",
+		"Bold Red":             "1;31",
+	}
+	for style, want := range tests {
+		got, err := ParseStyle(style)
+		require.NoError(t, err, style)
+		assert.Equal(t, want, got, style)
+	}
+}
+
+func TestParseStyle_Invalid(t *testing.T) {
+	for _, style := range []string{"purple", "bold #12345", "bright-gray", "on-"} {
+		_, err := ParseStyle(style)
+		assert.Error(t, err, style)
+	}
+}
+
+func TestBuiltinsAreValid(t *testing.T) {
+	assert.Equal(t, []string{"default", "high-contrast", "monochrome", "solarized"}, Builtins())
+	for _, name := range Builtins() {
+		resolved, err := Resolve(name, nil)
+		require.NoError(t, err, name)
+		_, err = New(resolved)
+		assert.NoError(t, err, name)
+	}
+}
+
+func TestResolve_Default(t *testing.T) {
+	resolved, err := Resolve("", nil)
+	require.NoError(t, err)
+	assert.Equal(t, builtins[Default], resolved)
+
+	resolved, err = Resolve("Solarized", nil)
+	require.NoError(t, err)
+	assert.Equal(t, builtins["solarized"], resolved)
+}
+
+func TestResolve_CustomTakesMissingRolesFromBase(t *testing.T) {
+	custom := map[string]types.ThemeConfig{
+		"mine":   {Base: "solarized", Accent: "bold #cb4b16"},
+		"plain":  {Match: "reverse"},
+		"nested": {Base: "mine", Muted: "dim"},
+	}
+
+	resolved, err := Resolve("mine", custom)
+	require.NoError(t, err)
+	assert.Equal(t, "bold #cb4b16", resolved.Accent)
+	assert.Equal(t, builtins["solarized"].Error, resolved.Error)
+	assert.Empty(t, resolved.Base)
+
+	resolved, err = Resolve("plain", custom)
+	require.NoError(t, err)
+	assert.Equal(t, "reverse", resolved.Match)
+	assert.Equal(t, builtins[Default].Accent, resolved.Accent)
+
+	resolved, err = Resolve("nested", custom)
+	require.NoError(t, err)
+	assert.Equal(t, "dim", resolved.Muted)
+	assert.Equal(t, "bold #cb4b16", resolved.Accent)
+	assert.Equal(t, builtins["solarized"].Success, resolved.Success)
+}
+
+func TestResolve_CustomRefinesBuiltinOfTheSameName(t *testing.T) {
+	custom := map[string]types.ThemeConfig{"Monochrome": {Match: "underline"}}
+
+	resolved, err := Resolve("monochrome", custom)
+	require.NoError(t, err)
+	assert.Equal(t, "underline", resolved.Match)
+	assert.Equal(t, builtins["monochrome"].Error, resolved.Error)
+}
+
+func TestResolve_Errors(t *testing.T) {
+	_, err := Resolve("dracula", nil)
+	assert.ErrorContains(t, err, "unknown theme 'dracula'")
+
+	custom := map[string]types.ThemeConfig{
+		"a": {Base: "b"},
+		"b": {Base: "a"},
+	}
+	_, err = Resolve("a", custom)
+	assert.ErrorContains(t, err, "its own base")
+}
+
+func TestPainter(t *testing.T) {
+	painter, err := New(types.ThemeConfig{Accent: "bright-cyan", Success: "none"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "\033[96mKamui\033[0m", painter.Paint(Accent, "Kamui"))
+	assert.Equal(t, "ok", painter.Paint(Success, "ok"))
+	assert.Equal(t, "", painter.Paint(Accent, ""))
+	assert.Equal(t, "96", painter.Code(Accent))
+	assert.Contains(t, painter.Environment(), "KAMUI_COLOR_ACCENT=96")
+	assert.Contains(t, painter.Environment(), "KAMUI_COLOR_SUCCESS=")
+
+	assert.Equal(t, "Kamui", Plain().Paint(Accent, "Kamui"))
+}
+
+func TestNew_InvalidStyle(t *testing.T) {
+	_, err := New(types.ThemeConfig{Warning: "loud"})
+	assert.ErrorContains(t, err, "warning")
+}
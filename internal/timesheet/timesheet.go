@@ -0,0 +1,317 @@
+// Package timesheet records how long interactive sessions run and summarizes the time per project and session
+package timesheet
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Entry is one interactive run of a session, from launch until Claude exits
+type Entry struct {
+	SessionID   string    `json:"sessionId"`
+	Project     string    `json:"project"`
+	ProjectPath string    `json:"projectPath"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+}
+
+// Duration returns how long the entry lasted
+func (e Entry) Duration() time.Duration {
+	return e.End.Sub(e.Start)
+}
+
+// Clip returns the entry trimmed to [from, to); zero bounds are open. ok is false when nothing is left
+func (e Entry) Clip(from, to time.Time) (Entry, bool) {
+	if !from.IsZero() && e.Start.Before(from) {
+		e.Start = from
+	}
+	if !to.IsZero() && e.End.After(to) {
+		e.End = to
+	}
+	return e, e.End.After(e.Start)
+}
+
+// SessionTotal is the time spent in one session
+type SessionTotal struct {
+	SessionID string        `json:"sessionId"`
+	Duration  time.Duration `json:"duration"`
+	Runs      int           `json:"runs"`
+}
+
+// ProjectTotal is the time spent in a project's sessions
+type ProjectTotal struct {
+	Project  string         `json:"project"`
+	Path     string         `json:"path"`
+	Duration time.Duration  `json:"duration"`
+	Sessions []SessionTotal `json:"sessions"`
+}
+
+// Summary totals the entries falling in a period
+type Summary struct {
+	From     time.Time      `json:"from"`
+	To       time.Time      `json:"to"`
+	Total    time.Duration  `json:"total"`
+	Projects []ProjectTotal `json:"projects"`
+	Entries  []Entry        `json:"entries"`
+}
+
+// Summarize totals entries overlapping [from, to), clipped to the period, per project and session
+// Projects and sessions are sorted by time spent, longest first
+func Summarize(entries []Entry, from, to time.Time) Summary {
+	summary := Summary{From: from, To: to}
+	projects := map[string]*ProjectTotal{}
+	sessions := map[string]map[string]*SessionTotal{}
+
+	for _, entry := range entries {
+		clipped, ok := entry.Clip(from, to)
+		if !ok {
+			continue
+		}
+		summary.Entries = append(summary.Entries, clipped)
+		duration := clipped.Duration()
+		summary.Total += duration
+
+		project, found := projects[entry.ProjectPath]
+		if !found {
+			project = &ProjectTotal{Project: entry.Project, Path: entry.ProjectPath}
+			projects[entry.ProjectPath] = project
+			sessions[entry.ProjectPath] = map[string]*SessionTotal{}
+		}
+		project.Duration += duration
+
+		session, found := sessions[entry.ProjectPath][entry.SessionID]
+		if !found {
+			session = &SessionTotal{SessionID: entry.SessionID}
+			sessions[entry.ProjectPath][entry.SessionID] = session
+		}
+		session.Duration += duration
+		session.Runs++
+	}
+
+	for path, project := range projects {
+		for _, session := range sessions[path] {
+			project.Sessions = append(project.Sessions, *session)
+		}
+		sort.Slice(project.Sessions, func(i, j int) bool {
+			if project.Sessions[i].Duration != project.Sessions[j].Duration {
+				return project.Sessions[i].Duration > project.Sessions[j].Duration
+			}
+			return project.Sessions[i].SessionID < project.Sessions[j].SessionID
+		})
+		summary.Projects = append(summary.Projects, *project)
+	}
+	sort.Slice(summary.Projects, func(i, j int) bool {
+		if summary.Projects[i].Duration != summary.Projects[j].Duration {
+			return summary.Projects[i].Duration > summary.Projects[j].Duration
+		}
+		return summary.Projects[i].Path < summary.Projects[j].Path
+	})
+	sort.Slice(summary.Entries, func(i, j int) bool {
+		return summary.Entries[i].Start.Before(summary.Entries[j].Start)
+	})
+
+	return summary
+}
+
+// WeekStart returns midnight on the Monday of t's week, in t's location
+func WeekStart(t time.Time) time.Time {
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, t.Location())
+}
+
+// Hours formats a duration as decimal hours, the unit timesheets are usually kept in
+func Hours(d time.Duration) string {
+	return fmt.Sprintf("%.2f", d.Hours())
+}
+
+// WriteCSV writes the summary's entries as CSV, one row per run
+func WriteCSV(w io.Writer, summary Summary) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"date", "start", "end", "project", "session", "hours"}); err != nil {
+		return err
+	}
+	for _, entry := range summary.Entries {
+		start := entry.Start.Local()
+		row := []string{
+			start.Format("2006-01-02"),
+			start.Format("15:04"),
+			entry.End.Local().Format("15:04"),
+			entry.Project,
+			entry.SessionID,
+			Hours(entry.Duration()),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Store appends time entries to a JSON Lines file, so concurrent sessions never rewrite each other's entries
+type Store struct {
+	path string
+}
+
+// NewStore creates a store backed by ~/.kamui/timesheet.jsonl
+func NewStore() *Store {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+	return NewStoreWithPath(filepath.Join(homeDir, ".kamui", "timesheet.jsonl"))
+}
+
+// NewStoreWithPath creates a store backed by the given file
+func NewStoreWithPath(path string) *Store {
+	return &Store{path: path}
+}
+
+// Record appends an entry
+func (s *Store) Record(entry Entry) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return types.NewStorageError(
+			types.ErrCodeStoragePermission,
+			"failed to create timesheet directory",
+			err,
+		)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return types.NewStorageError(
+			types.ErrCodeStorageCorrupted,
+			"failed to marshal time entry",
+			err,
+		)
+	}
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return types.NewStorageError(
+			types.ErrCodeStoragePermission,
+			"failed to open timesheet file",
+			err,
+		)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return types.NewStorageError(
+			types.ErrCodeStoragePermission,
+			"failed to write time entry",
+			err,
+		)
+	}
+	return nil
+}
+
+// Entries returns all recorded entries; lines that can't be parsed (e.g. a torn write) are skipped
+func (s *Store) Entries() ([]Entry, error) {
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, types.NewStorageError(
+			types.ErrCodeStoragePermission,
+			"failed to read timesheet file",
+			err,
+		)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, types.NewStorageError(
+			types.ErrCodeStoragePermission,
+			"failed to read timesheet file",
+			err,
+		)
+	}
+	return entries, nil
+}
+
+// Rename moves a session's entries to its new name and reports how many were moved
+// It rewrites the file, so an entry appended meanwhile could be lost; sessions are only renamed
+// while they aren't running
+func (s *Store) Rename(sessionID, newID string) (int, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, types.NewStorageError(
+			types.ErrCodeStoragePermission,
+			"failed to read timesheet file",
+			err,
+		)
+	}
+
+	var rewritten bytes.Buffer
+	moved := 0
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		var entry Entry
+		if err := json.Unmarshal(line, &entry); err == nil && entry.SessionID == sessionID {
+			entry.SessionID = newID
+			if line, err = json.Marshal(entry); err != nil {
+				return 0, types.NewStorageError(
+					types.ErrCodeStorageCorrupted,
+					"failed to marshal time entry",
+					err,
+				)
+			}
+			moved++
+		}
+		rewritten.Write(line)
+		rewritten.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, types.NewStorageError(
+			types.ErrCodeStoragePermission,
+			"failed to read timesheet file",
+			err,
+		)
+	}
+	if moved == 0 {
+		return 0, nil
+	}
+
+	tempFile := s.path + ".tmp"
+	if err := os.WriteFile(tempFile, rewritten.Bytes(), 0o600); err != nil {
+		return 0, types.NewStorageError(
+			types.ErrCodeStoragePermission,
+			"failed to write timesheet file",
+			err,
+		)
+	}
+	if err := os.Rename(tempFile, s.path); err != nil {
+		os.Remove(tempFile) // cleanup temp file
+		return 0, types.NewStorageError(
+			types.ErrCodeStoragePermission,
+			"failed to replace timesheet file",
+			err,
+		)
+	}
+	return moved, nil
+}
@@ -0,0 +1,126 @@
+package timesheet
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func at(hour, minute int) time.Time {
+	return time.Date(2025, 3, 12, hour, minute, 0, 0, time.UTC)
+}
+
+func TestSummarize(t *testing.T) {
+	entries := []Entry{
+		{SessionID: "auth", Project: "api", ProjectPath: "/src/api", Start: at(9, 0), End: at(10, 30)},
+		{SessionID: "docs", Project: "web", ProjectPath: "/src/web", Start: at(11, 0), End: at(11, 15)},
+		{SessionID: "auth", Project: "api", ProjectPath: "/src/api", Start: at(13, 0), End: at(14, 0)},
+		{SessionID: "bugs", Project: "api", ProjectPath: "/src/api", Start: at(14, 0), End: at(14, 30)},
+	}
+
+	summary := Summarize(entries, time.Time{}, time.Time{})
+	assert.Equal(t, 3*time.Hour+15*time.Minute, summary.Total)
+	require.Len(t, summary.Projects, 2)
+
+	api := summary.Projects[0]
+	assert.Equal(t, "/src/api", api.Path)
+	assert.Equal(t, 3*time.Hour, api.Duration)
+	assert.Equal(t, []SessionTotal{
+		{SessionID: "auth", Duration: 150 * time.Minute, Runs: 2},
+		{SessionID: "bugs", Duration: 30 * time.Minute, Runs: 1},
+	}, api.Sessions)
+	assert.Equal(t, "web", summary.Projects[1].Project)
+}
+
+func TestSummarize_ClipsToPeriod(t *testing.T) {
+	entries := []Entry{
+		{SessionID: "auth", ProjectPath: "/src/api", Start: at(9, 0), End: at(10, 0)},
+		{SessionID: "auth", ProjectPath: "/src/api", Start: at(7, 0), End: at(8, 0)},
+	}
+
+	summary := Summarize(entries, at(9, 30), time.Time{})
+	assert.Equal(t, 30*time.Minute, summary.Total)
+	require.Len(t, summary.Entries, 1)
+	assert.Equal(t, at(9, 30), summary.Entries[0].Start)
+}
+
+func TestWeekStart(t *testing.T) {
+	wednesday := time.Date(2025, 3, 12, 15, 4, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), WeekStart(wednesday))
+
+	sunday := time.Date(2025, 3, 16, 23, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), WeekStart(sunday))
+
+	monday := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, monday, WeekStart(monday))
+}
+
+func TestWriteCSV(t *testing.T) {
+	summary := Summarize([]Entry{
+		{SessionID: "auth", Project: "api", ProjectPath: "/src/api", Start: at(9, 0), End: at(10, 30)},
+	}, time.Time{}, time.Time{})
+
+	var out bytes.Buffer
+	require.NoError(t, WriteCSV(&out, summary))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, "date,start,end,project,session,hours", lines[0])
+	assert.True(t, strings.HasSuffix(lines[1], ",api,auth,1.50"), lines[1])
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kamui", "timesheet.jsonl")
+	store := NewStoreWithPath(path)
+
+	entries, err := store.Entries()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	first := Entry{SessionID: "auth", Project: "api", ProjectPath: "/src/api", Start: at(9, 0), End: at(10, 0)}
+	second := Entry{SessionID: "docs", Project: "web", ProjectPath: "/src/web", Start: at(11, 0), End: at(12, 0)}
+	require.NoError(t, store.Record(first))
+	require.NoError(t, store.Record(second))
+
+	// A torn line doesn't lose the other entries
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"sessionId":"par`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	entries, err = store.Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.True(t, entries[0].Start.Equal(first.Start))
+	assert.Equal(t, "docs", entries[1].SessionID)
+}
+
+func TestStore_Rename(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timesheet.jsonl")
+	store := NewStoreWithPath(path)
+
+	moved, err := store.Rename("auth", "login")
+	require.NoError(t, err)
+	assert.Zero(t, moved, "a missing timesheet has nothing to move")
+
+	require.NoError(t, store.Record(Entry{SessionID: "auth", Project: "api", Start: at(9, 0), End: at(10, 0)}))
+	require.NoError(t, store.Record(Entry{SessionID: "docs", Project: "web", Start: at(11, 0), End: at(12, 0)}))
+	require.NoError(t, store.Record(Entry{SessionID: "auth", Project: "api", Start: at(13, 0), End: at(14, 0)}))
+
+	moved, err = store.Rename("auth", "login")
+	require.NoError(t, err)
+	assert.Equal(t, 2, moved)
+
+	entries, err := store.Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, []string{"login", "docs", "login"}, []string{entries[0].SessionID, entries[1].SessionID, entries[2].SessionID})
+	assert.True(t, entries[2].Start.Equal(at(13, 0)), "entries keep their order and times")
+}
@@ -0,0 +1,140 @@
+// Package trace reports the external commands Kamui runs (Claude, git, editors, plugins) when
+// tracing is on, so a launch that behaves strangely can be reproduced command by command
+package trace
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxArgLength is how much of a single argument, such as a headless prompt, a trace line shows
+const maxArgLength = 200
+
+var (
+	mu      sync.Mutex
+	output  io.Writer
+	started = make(map[*exec.Cmd]time.Time)
+)
+
+// Enable writes a trace line to w for every command run through this package; nil turns tracing off
+func Enable(w io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+	output = w
+}
+
+// Enabled reports whether commands are being traced
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return output != nil
+}
+
+// Run runs cmd like cmd.Run and traces it
+func Run(cmd *exec.Cmd) error {
+	begin := time.Now()
+	err := cmd.Run()
+	finished(cmd, begin, err)
+	return err
+}
+
+// Output runs cmd like cmd.Output and traces it
+func Output(cmd *exec.Cmd) ([]byte, error) {
+	begin := time.Now()
+	out, err := cmd.Output()
+	finished(cmd, begin, err)
+	return out, err
+}
+
+// Start starts cmd like cmd.Start and traces it; commands that are waited for should use Wait, which
+// traces how they ended
+func Start(cmd *exec.Cmd) error {
+	begin := time.Now()
+	if err := cmd.Start(); err != nil {
+		finished(cmd, begin, err)
+		return err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if output != nil {
+		started[cmd] = begin
+		fmt.Fprintf(output, "kam trace: %s  dir=%s  started pid=%d\n", commandLine(cmd), dir(cmd), cmd.Process.Pid)
+	}
+	return nil
+}
+
+// Wait waits for a command started with Start like cmd.Wait and traces how it ended
+func Wait(cmd *exec.Cmd) error {
+	err := cmd.Wait()
+	mu.Lock()
+	begin, ok := started[cmd]
+	delete(started, cmd)
+	mu.Unlock()
+	if ok {
+		finished(cmd, begin, err)
+	}
+	return err
+}
+
+// finished writes the trace line for a command that ended, or failed to start, with err
+func finished(cmd *exec.Cmd, begin time.Time, err error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if output == nil {
+		return
+	}
+	fmt.Fprintf(output, "kam trace: %s  dir=%s  %s  took=%s\n",
+		commandLine(cmd), dir(cmd), result(cmd, err), time.Since(begin).Round(time.Millisecond))
+}
+
+// result describes how a command ended
+func result(cmd *exec.Cmd, err error) string {
+	if cmd.ProcessState == nil {
+		return fmt.Sprintf("error=%q", fmt.Sprint(err)) // never started, or couldn't be waited for
+	}
+	if code := cmd.ProcessState.ExitCode(); code >= 0 {
+		return fmt.Sprintf("exit=%d", code)
+	}
+	return fmt.Sprintf("exit=-1 (%s)", cmd.ProcessState)
+}
+
+// commandLine renders the resolved executable and its arguments, quoting those a shell would split
+func commandLine(cmd *exec.Cmd) string {
+	parts := make([]string, 0, len(cmd.Args))
+	parts = append(parts, quote(cmd.Path))
+	if len(cmd.Args) > 1 {
+		for _, arg := range cmd.Args[1:] {
+			if len(arg) > maxArgLength {
+				parts = append(parts, fmt.Sprintf("%s…(%d bytes)", quote(arg[:maxArgLength]), len(arg)))
+				continue
+			}
+			parts = append(parts, quote(arg))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// quote quotes an argument when it is empty or contains whitespace, quotes or control characters
+func quote(arg string) string {
+	if arg == "" || strings.ContainsAny(arg, " \t\n\r\"'\\$`") || strconv.Quote(arg) != `"`+arg+`"` {
+		return strconv.Quote(arg)
+	}
+	return arg
+}
+
+// dir returns the directory a command runs in
+func dir(cmd *exec.Cmd) string {
+	if cmd.Dir != "" {
+		return quote(cmd.Dir)
+	}
+	if cwd, err := os.Getwd(); err == nil {
+		return quote(cwd)
+	}
+	return "."
+}
@@ -0,0 +1,74 @@
+package trace
+
+import (
+	"bytes"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	var out bytes.Buffer
+	Enable(&out)
+	t.Cleanup(func() { Enable(nil) })
+
+	dir := t.TempDir()
+	cmd := exec.Command("sh", "-c", "exit 3", "two words")
+	cmd.Dir = dir
+	err := Run(cmd)
+	require.Error(t, err)
+
+	line := out.String()
+	assert.True(t, strings.HasPrefix(line, "kam trace: "+cmd.Path+` -c "exit 3" "two words"  dir=`+dir+"  exit=3  took="), line)
+}
+
+func TestStartWait(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	var out bytes.Buffer
+	Enable(&out)
+	t.Cleanup(func() { Enable(nil) })
+
+	cmd := exec.Command("sh", "-c", "true")
+	require.NoError(t, Start(cmd))
+	require.NoError(t, Wait(cmd))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "started pid=")
+	assert.Contains(t, lines[1], "exit=0")
+	assert.Empty(t, started)
+}
+
+func TestNotStarted(t *testing.T) {
+	var out bytes.Buffer
+	Enable(&out)
+	t.Cleanup(func() { Enable(nil) })
+
+	_, err := Output(exec.Command("kamui-no-such-command"))
+	require.Error(t, err)
+	assert.Contains(t, out.String(), `error="exec: `)
+}
+
+func TestDisabled(t *testing.T) {
+	Enable(nil)
+	assert.False(t, Enabled())
+
+	_, err := Output(exec.Command("kamui-no-such-command"))
+	require.Error(t, err)
+	assert.Empty(t, started)
+}
+
+func TestCommandLine(t *testing.T) {
+	long := strings.Repeat("x", maxArgLength+10)
+	cmd := &exec.Cmd{Path: "/usr/bin/claude", Args: []string{"claude", "--resume", "abc", "", long}}
+	assert.Equal(t, `/usr/bin/claude --resume abc "" `+strings.Repeat("x", maxArgLength)+"…(210 bytes)", commandLine(cmd))
+}
@@ -0,0 +1,295 @@
+package transcript
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Format is an export document format
+type Format string
+
+const (
+	FormatMarkdown Format = "md"
+	FormatHTML     Format = "html"
+)
+
+// ParseFormat validates an export format name
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "md", "markdown":
+		return FormatMarkdown, nil
+	case "html":
+		return FormatHTML, nil
+	default:
+		return "", types.NewSessionError(
+			types.ErrCodeInvalidInput,
+			fmt.Sprintf("unknown export format %q (expected md or html)", name),
+			nil,
+		)
+	}
+}
+
+// Turn is one message as a reader sees it: who spoke, their text and the tool calls they made
+type Turn struct {
+	Role      string
+	Timestamp time.Time
+	Text      string
+	Tools     []*ToolCall
+}
+
+// ToolCall pairs a tool_use block with its result
+type ToolCall struct {
+	Name    string
+	Input   string
+	Result  string
+	IsError bool
+}
+
+// Export renders a transcript as a readable document
+// Tool calls are rendered as collapsible sections containing their input and result
+func Export(w io.Writer, title string, records []*Record, format Format) error {
+	return ExportTurns(w, title, Turns(records), format)
+}
+
+// ExportTurns renders already grouped turns as a readable document
+func ExportTurns(w io.Writer, title string, turns []*Turn, format Format) error {
+	switch format {
+	case FormatHTML:
+		return exportHTML(w, title, turns)
+	default:
+		return exportMarkdown(w, title, turns)
+	}
+}
+
+// Turns groups records into user and assistant turns, attaching tool results to their calls
+func Turns(records []*Record) []*Turn {
+	builder := newTurnBuilder()
+	for _, record := range records {
+		builder.add(record)
+	}
+	return builder.turns
+}
+
+// ReadTurns reads the turns of the transcript at path without holding its raw records in memory
+// The returned stats say whether DefaultLimits left part of the transcript out
+func ReadTurns(ctx context.Context, path string) ([]*Turn, ReadStats, error) {
+	builder := newTurnBuilder()
+	stats, err := Scan(ctx, path, DefaultLimits, func(record *Record) error {
+		builder.add(record)
+		return nil
+	})
+	if err != nil {
+		return nil, stats, err
+	}
+	return builder.turns, stats, nil
+}
+
+// turnBuilder groups records into turns as they are read
+type turnBuilder struct {
+	turns []*Turn
+	calls map[string]*ToolCall
+}
+
+func newTurnBuilder() *turnBuilder {
+	return &turnBuilder{calls: make(map[string]*ToolCall)}
+}
+
+func (b *turnBuilder) add(record *Record) {
+	if record.Message == nil || (record.Type != "user" && record.Type != "assistant") {
+		return
+	}
+
+	if !record.IsPrompt() && record.Type == "user" {
+		for _, block := range record.Message.Content {
+			if call, ok := b.calls[block.ToolUseID]; ok && block.Type == "tool_result" {
+				call.Result = resultText(block.Content)
+				call.IsError = block.IsError
+			}
+		}
+		return
+	}
+
+	current := &Turn{Role: record.Type, Timestamp: record.Timestamp, Text: record.Text()}
+	for _, block := range record.Message.Content {
+		if block.Type != "tool_use" {
+			continue
+		}
+		call := &ToolCall{Name: block.Name, Input: prettyJSON(block.Input)}
+		current.Tools = append(current.Tools, call)
+		b.calls[block.ID] = call
+	}
+
+	// consecutive assistant records belong to the same response
+	if last := lastTurn(b.turns); last != nil && last.Role == "assistant" && current.Role == "assistant" {
+		last.Text = joinText(last.Text, current.Text)
+		last.Tools = append(last.Tools, current.Tools...)
+		return
+	}
+	b.turns = append(b.turns, current)
+}
+
+func exportMarkdown(w io.Writer, title string, turns []*Turn) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s\n", title)
+
+	for _, t := range turns {
+		fmt.Fprintf(&buf, "\n## %s", RoleLabel(t.Role))
+		if !t.Timestamp.IsZero() {
+			fmt.Fprintf(&buf, " · %s", t.Timestamp.Format("2006-01-02 15:04"))
+		}
+		buf.WriteString("\n\n")
+		if t.Text != "" {
+			buf.WriteString(t.Text)
+			buf.WriteString("\n")
+		}
+
+		for _, call := range t.Tools {
+			summary := "Tool: " + call.Name
+			if call.IsError {
+				summary += " (error)"
+			}
+			fmt.Fprintf(&buf, "\n<details>\n<summary>%s</summary>\n\n", html.EscapeString(summary))
+			fmt.Fprintf(&buf, "%s\n", fence("json", call.Input))
+			if call.Result != "" {
+				fmt.Fprintf(&buf, "\nResult:\n\n%s\n", fence("", call.Result))
+			}
+			buf.WriteString("\n</details>\n")
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func exportHTML(w io.Writer, title string, turns []*Turn) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body { font-family: -apple-system, system-ui, sans-serif; max-width: 60rem; margin: 2rem auto; padding: 0 1rem; line-height: 1.5; }
+.turn { border-left: 4px solid #ccc; margin: 1.5rem 0; padding: 0.25rem 1rem; }
+.user { border-color: #4a90d9; }
+.assistant { border-color: #d97a4a; }
+.meta { color: #777; font-size: 0.85rem; }
+.text { white-space: pre-wrap; }
+details { margin: 0.5rem 0; }
+summary { cursor: pointer; font-family: monospace; }
+pre { background: #f5f5f5; padding: 0.75rem; overflow-x: auto; }
+.error summary { color: #c0392b; }
+</style>
+</head>
+<body>
+<h1>%s</h1>
+`, html.EscapeString(title), html.EscapeString(title))
+
+	for _, t := range turns {
+		fmt.Fprintf(&buf, "<div class=\"turn %s\">\n<p class=\"meta\"><strong>%s</strong>", t.Role, RoleLabel(t.Role))
+		if !t.Timestamp.IsZero() {
+			fmt.Fprintf(&buf, " · %s", t.Timestamp.Format("2006-01-02 15:04"))
+		}
+		buf.WriteString("</p>\n")
+		if t.Text != "" {
+			fmt.Fprintf(&buf, "<div class=\"text\">%s</div>\n", html.EscapeString(t.Text))
+		}
+
+		for _, call := range t.Tools {
+			class := ""
+			if call.IsError {
+				class = ` class="error"`
+			}
+			fmt.Fprintf(&buf, "<details%s>\n<summary>Tool: %s</summary>\n<pre>%s</pre>\n", class, html.EscapeString(call.Name), html.EscapeString(call.Input))
+			if call.Result != "" {
+				fmt.Fprintf(&buf, "<p class=\"meta\">Result</p>\n<pre>%s</pre>\n", html.EscapeString(call.Result))
+			}
+			buf.WriteString("</details>\n")
+		}
+		buf.WriteString("</div>\n")
+	}
+	buf.WriteString("</body>\n</html>\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// resultText extracts readable text from a tool_result content, which is a string or a block list
+func resultText(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+
+	var blocks []Block
+	if err := json.Unmarshal(raw, &blocks); err == nil {
+		var parts []string
+		for _, block := range blocks {
+			if block.Text != "" {
+				parts = append(parts, block.Text)
+			}
+		}
+		return strings.Join(parts, "\n")
+	}
+
+	return string(raw)
+}
+
+// prettyJSON indents a JSON value, falling back to the raw text
+func prettyJSON(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return "{}"
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, raw, "", "  "); err != nil {
+		return string(raw)
+	}
+	return buf.String()
+}
+
+// fence wraps text in a Markdown code fence long enough not to clash with backticks inside it
+func fence(lang, text string) string {
+	marker := "```"
+	for strings.Contains(text, marker) {
+		marker += "`"
+	}
+	return marker + lang + "\n" + strings.TrimRight(text, "\n") + "\n" + marker
+}
+
+// RoleLabel returns the display name of a turn's role
+func RoleLabel(role string) string {
+	if role == "user" {
+		return "User"
+	}
+	return "Claude"
+}
+
+func joinText(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	default:
+		return a + "\n\n" + b
+	}
+}
+
+func lastTurn(turns []*Turn) *Turn {
+	if len(turns) == 0 {
+		return nil
+	}
+	return turns[len(turns)-1]
+}
@@ -0,0 +1,107 @@
+package transcript
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func exportFixture(t *testing.T, format Format) string {
+	t.Helper()
+	records, err := ReadFile(context.Background(), filepath.Join("testdata", "conversation.jsonl"))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, Export(&buf, "login-fix", records, format))
+	return buf.String()
+}
+
+func TestParseFormat(t *testing.T) {
+	for input, expected := range map[string]Format{"md": FormatMarkdown, "markdown": FormatMarkdown, "HTML": FormatHTML} {
+		format, err := ParseFormat(input)
+		require.NoError(t, err)
+		assert.Equal(t, expected, format)
+	}
+
+	_, err := ParseFormat("pdf")
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeInvalidInput, agxErr.Code)
+}
+
+func TestExport_Markdown(t *testing.T) {
+	out := exportFixture(t, FormatMarkdown)
+
+	assert.True(t, strings.HasPrefix(out, "# login-fix\n"))
+	assert.Contains(t, out, "## User · 2025-03-10 09:00\n\nWhy does login fail?")
+	// consecutive assistant records are merged into one response
+	assert.Equal(t, 3, strings.Count(out, "## Claude"))
+	assert.Contains(t, out, "Let me look.\n\nThe token is never refreshed.")
+	assert.Contains(t, out, "<summary>Tool: Read</summary>")
+	assert.Contains(t, out, "\"file_path\": \"/work/app/login.go\"")
+	assert.Contains(t, out, "Result:\n\n```\npackage main\n```")
+	assert.Equal(t, 3, strings.Count(out, "<details>"))
+}
+
+func TestExport_HTML(t *testing.T) {
+	out := exportFixture(t, FormatHTML)
+
+	assert.Contains(t, out, "<title>login-fix</title>")
+	assert.Contains(t, out, `<div class="turn user">`)
+	assert.Contains(t, out, "<summary>Tool: Bash</summary>")
+	assert.Contains(t, out, "go test ./...")
+	assert.Equal(t, 3, strings.Count(out, "<details"))
+}
+
+func TestExport_EscapesHTML(t *testing.T) {
+	records := []*Record{{
+		Type:    "user",
+		Message: &Message{Role: "user", Content: Content{{Type: "text", Text: "<script>alert(1)</script>"}}},
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, Export(&buf, "x", records, FormatHTML))
+	assert.NotContains(t, buf.String(), "<script>")
+	assert.Contains(t, buf.String(), "&lt;script&gt;")
+}
+
+func TestFence(t *testing.T) {
+	assert.Equal(t, "```go\nx\n```", fence("go", "x\n"))
+	assert.Equal(t, "````\na ```b``` c\n````", fence("", "a ```b``` c"))
+}
+
+func TestTurns(t *testing.T) {
+	records, err := ReadFile(context.Background(), filepath.Join("testdata", "conversation.jsonl"))
+	require.NoError(t, err)
+
+	turns := Turns(records)
+	require.Len(t, turns, 6)
+
+	assert.Equal(t, "user", turns[0].Role)
+	assert.Equal(t, "Why does login fail?", turns[0].Text)
+
+	// the tool result and the follow-up text belong to the same response
+	assert.Equal(t, "assistant", turns[1].Role)
+	assert.Equal(t, "Let me look.\n\nThe token is never refreshed.", turns[1].Text)
+	require.Len(t, turns[1].Tools, 1)
+	assert.Equal(t, "Read", turns[1].Tools[0].Name)
+	assert.Equal(t, "package main", turns[1].Tools[0].Result)
+}
+
+func TestReadTurns(t *testing.T) {
+	path := filepath.Join("testdata", "conversation.jsonl")
+	records, err := ReadFile(context.Background(), path)
+	require.NoError(t, err)
+
+	turns, stats, err := ReadTurns(context.Background(), path)
+	require.NoError(t, err)
+	assert.True(t, stats.Complete())
+	assert.Equal(t, Turns(records), turns)
+}
@@ -0,0 +1,60 @@
+package transcript
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// NewSessionID returns a random Claude conversation ID (a version 4 UUID)
+func NewSessionID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	buf[6] = buf[6]&0x0f | 0x40
+	buf[8] = buf[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", buf[0:4], buf[4:6], buf[6:8], buf[8:10], buf[10:]), nil
+}
+
+// ForkFile copies the transcript at path into a new conversation claudeSessionID next to it, so
+// two sessions can continue the same history separately; it returns the copy's path
+func ForkFile(ctx context.Context, path, claudeSessionID string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", types.NewClaudeError(types.ErrCodeClaudeSessionNotFound, fmt.Sprintf("transcript not found: %s", path), err)
+	}
+	forkPath := filepath.Join(filepath.Dir(path), claudeSessionID+".jsonl")
+	if _, err := os.Stat(forkPath); err == nil {
+		return "", types.NewSessionError(types.ErrCodeInvalidInput, fmt.Sprintf("a transcript already exists at %s", forkPath), nil)
+	}
+
+	records, err := ReadFile(ctx, path)
+	if ctxErr := types.ContextError(ctx); ctxErr != nil {
+		return "", ctxErr
+	}
+	if err != nil {
+		return "", types.NewStorageError(types.ErrCodeStorageCorrupted, "failed to read transcript", err)
+	}
+
+	for i, record := range records {
+		if record.SessionID == "" {
+			continue
+		}
+		forked, err := withField(record, "sessionId", claudeSessionID)
+		if err != nil {
+			return "", types.NewStorageError(types.ErrCodeStorageCorrupted, "failed to rewrite transcript", err)
+		}
+		forked.SessionID = claudeSessionID
+		records[i] = forked
+	}
+
+	if err := WriteFile(forkPath, records, info.Mode().Perm()); err != nil {
+		return "", types.NewStorageError(types.ErrCodeStoragePermission, "failed to write forked transcript", err)
+	}
+	return forkPath, nil
+}
@@ -0,0 +1,51 @@
+package transcript
+
+import (
+	"context"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestNewSessionID(t *testing.T) {
+	id, err := NewSessionID()
+	require.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), id)
+
+	other, err := NewSessionID()
+	require.NoError(t, err)
+	assert.NotEqual(t, id, other)
+}
+
+func TestForkFile(t *testing.T) {
+	path := copyFixture(t)
+
+	forkPath, err := ForkFile(context.Background(), path, "s-2")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(filepath.Dir(path), "s-2.jsonl"), forkPath)
+
+	original, err := ReadFile(context.Background(), path)
+	require.NoError(t, err)
+	forked, err := ReadFile(context.Background(), forkPath)
+	require.NoError(t, err)
+	require.Len(t, forked, len(original))
+	for i, record := range forked {
+		if original[i].SessionID == "" {
+			assert.Equal(t, original[i].Raw, record.Raw, "records without a conversation ID are copied as they are")
+			continue
+		}
+		assert.Equal(t, "s-1", original[i].SessionID)
+		assert.Equal(t, "s-2", record.SessionID)
+		assert.Equal(t, original[i].UUID, record.UUID)
+	}
+
+	_, err = ForkFile(context.Background(), path, "s-2")
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeInvalidInput, agxErr.Code)
+}
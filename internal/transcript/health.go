@@ -0,0 +1,194 @@
+package transcript
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bitomule/kamui/internal/paths"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// healthChunkSize is how much of a transcript's tail is read at a time to find its last line
+const healthChunkSize = 64 * 1024
+
+// Issue is a problem a health check found in a transcript
+type Issue string
+
+// Issues found by CheckHealth
+const (
+	IssueMissing         Issue = "missing"
+	IssueEmpty           Issue = "empty"
+	IssueTruncated       Issue = "truncated"
+	IssueCorrupt         Issue = "corrupt"
+	IssueSessionMismatch Issue = "session-mismatch"
+	IssueCWDMismatch     Issue = "cwd-mismatch"
+)
+
+// Finding is one issue and what was seen
+type Finding struct {
+	Issue   Issue
+	Message string
+}
+
+// Health is the result of checking a transcript before resuming it
+type Health struct {
+	Path     string
+	Size     int64
+	Findings []Finding
+}
+
+// OK reports whether nothing was found
+func (h Health) OK() bool {
+	return len(h.Findings) == 0
+}
+
+// Has reports whether the check found issue
+func (h Health) Has(issue Issue) bool {
+	for _, finding := range h.Findings {
+		if finding.Issue == issue {
+			return true
+		}
+	}
+	return false
+}
+
+// Repairable reports whether RepairFile can fix the transcript: its last line is broken
+func (h Health) Repairable() bool {
+	return h.Has(IssueTruncated) || h.Has(IssueCorrupt)
+}
+
+// Summary describes the findings in one line
+func (h Health) Summary() string {
+	messages := make([]string, len(h.Findings))
+	for i, finding := range h.Findings {
+		messages[i] = finding.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// CheckHealth checks the transcript of Claude conversation claudeSessionID before it is resumed:
+// it must exist and not be empty, its last line must be complete JSON, and that record must belong
+// to the conversation and, when it records one, to workingDir
+func CheckHealth(ctx context.Context, path, claudeSessionID, workingDir string) (Health, error) {
+	health := Health{Path: path}
+	if err := types.ContextError(ctx); err != nil {
+		return health, err
+	}
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		health.Findings = append(health.Findings, Finding{IssueMissing, "the transcript doesn't exist"})
+		return health, nil
+	}
+	if err != nil {
+		return health, err
+	}
+	health.Size = info.Size()
+	if health.Size == 0 {
+		health.Findings = append(health.Findings, Finding{IssueEmpty, "the transcript is empty"})
+		return health, nil
+	}
+
+	line, _, terminated, err := lastLine(path, health.Size)
+	if err != nil {
+		return health, err
+	}
+	if line == nil {
+		return health, nil // a last line too long to check isn't reported
+	}
+
+	var record Record
+	if err := json.Unmarshal(line, &record); err != nil {
+		if terminated {
+			health.Findings = append(health.Findings, Finding{IssueCorrupt, "the transcript's last line isn't valid JSON"})
+		} else {
+			health.Findings = append(health.Findings, Finding{IssueTruncated, "the transcript's last line is incomplete"})
+		}
+		return health, nil
+	}
+
+	if record.SessionID != "" && claudeSessionID != "" && record.SessionID != claudeSessionID {
+		health.Findings = append(health.Findings, Finding{IssueSessionMismatch,
+			fmt.Sprintf("the transcript's last record belongs to conversation %s, not %s", record.SessionID, claudeSessionID)})
+	}
+	if record.CWD != "" && workingDir != "" && !paths.Equal(record.CWD, workingDir) {
+		health.Findings = append(health.Findings, Finding{IssueCWDMismatch,
+			fmt.Sprintf("the transcript was last written in %s, not %s", record.CWD, workingDir)})
+	}
+	return health, nil
+}
+
+// RepairFile drops the broken last line of the transcript at path, copying the original into
+// backupDir first; it returns the backup's path
+func RepairFile(ctx context.Context, path, backupDir string, now time.Time) (string, error) {
+	if err := types.ContextError(ctx); err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", types.NewClaudeError(types.ErrCodeClaudeSessionNotFound, fmt.Sprintf("transcript not found: %s", path), err)
+	}
+	line, offset, _, err := lastLine(path, info.Size())
+	if err != nil {
+		return "", types.NewStorageError(types.ErrCodeStorageCorrupted, "failed to read transcript", err)
+	}
+	if line == nil || json.Valid(line) {
+		return "", types.NewSessionError(types.ErrCodeInvalidInput, "the transcript's last line isn't broken", nil)
+	}
+
+	backupPath := filepath.Join(backupDir, fmt.Sprintf("%s.%s.jsonl", trimExtension(filepath.Base(path)), now.UTC().Format("20060102T150405Z")))
+	if err := copyFile(path, backupPath); err != nil {
+		return "", types.NewStorageError(types.ErrCodeStoragePermission, "failed to back up transcript", err)
+	}
+	if err := os.Truncate(path, offset); err != nil {
+		return "", types.NewStorageError(types.ErrCodeStoragePermission, "failed to repair transcript", err)
+	}
+	return backupPath, nil
+}
+
+// lastLine returns the last line of the file at path without its newline, the offset it starts at and
+// whether it ends with a newline; the line is nil when it is longer than maxLineSize
+func lastLine(path string, size int64) (line []byte, offset int64, terminated bool, err error) {
+	file, err := os.Open(path) // #nosec G304 -- transcript paths come from Claude's project directory
+	if err != nil {
+		return nil, 0, false, err
+	}
+	defer file.Close()
+
+	// read backwards until the newline before the last line, or the start of the file
+	end := size
+	var tail []byte
+	for {
+		start := end - healthChunkSize
+		if start < 0 {
+			start = 0
+		}
+		chunk := make([]byte, end-start)
+		if _, readErr := file.ReadAt(chunk, start); readErr != nil && readErr != io.EOF {
+			return nil, 0, false, readErr
+		}
+		tail = append(chunk, tail...)
+
+		// blank lines after the last record don't count
+		body := bytes.TrimRight(tail, "\r\n")
+		terminated = len(body) < len(tail)
+		if i := bytes.LastIndexByte(body, '\n'); i >= 0 {
+			return body[i+1:], size - int64(len(tail)) + int64(i) + 1, terminated, nil
+		}
+		if start == 0 {
+			return body, 0, terminated, nil
+		}
+		if len(tail) > maxLineSize {
+			return nil, 0, terminated, nil
+		}
+		end = start
+	}
+}
@@ -0,0 +1,94 @@
+package transcript
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	healthyLine = `{"type":"user","sessionId":"s-1","cwd":"/work/app","message":{"role":"user","content":"hi"}}`
+	otherLine   = `{"type":"assistant","sessionId":"s-2","cwd":"/work/other","message":{"role":"assistant","content":"hello"}}`
+)
+
+func writeHealthFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "s-1.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestCheckHealth(t *testing.T) {
+	testCases := map[string]struct {
+		content string
+		issues  []Issue
+	}{
+		"healthy":            {healthyLine + "\n" + healthyLine + "\n", nil},
+		"trailing blank":     {healthyLine + "\n\n", nil},
+		"no final newline":   {healthyLine + "\n" + healthyLine, nil},
+		"empty":              {"", []Issue{IssueEmpty}},
+		"truncated":          {healthyLine + "\n" + healthyLine[:30], []Issue{IssueTruncated}},
+		"corrupt":            {healthyLine + "\n{oops}\n", []Issue{IssueCorrupt}},
+		"other conversation": {healthyLine + "\n" + otherLine + "\n", []Issue{IssueSessionMismatch, IssueCWDMismatch}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			health, err := CheckHealth(context.Background(), writeHealthFile(t, tc.content), "s-1", "/work/app")
+			require.NoError(t, err)
+			var issues []Issue
+			for _, finding := range health.Findings {
+				issues = append(issues, finding.Issue)
+			}
+			assert.Equal(t, tc.issues, issues)
+			assert.Equal(t, len(tc.issues) == 0, health.OK())
+		})
+	}
+}
+
+func TestCheckHealth_Missing(t *testing.T) {
+	health, err := CheckHealth(context.Background(), filepath.Join(t.TempDir(), "gone.jsonl"), "s-1", "")
+	require.NoError(t, err)
+	assert.True(t, health.Has(IssueMissing))
+	assert.False(t, health.Repairable())
+}
+
+func TestCheckHealth_LastLineSpansChunks(t *testing.T) {
+	long := `{"type":"user","sessionId":"s-1","message":{"role":"user","content":"` + strings.Repeat("x", 3*healthChunkSize) + `"}}`
+	health, err := CheckHealth(context.Background(), writeHealthFile(t, healthyLine+"\n"+long+"\n"), "s-1", "")
+	require.NoError(t, err)
+	assert.True(t, health.OK(), health.Summary())
+
+	health, err = CheckHealth(context.Background(), writeHealthFile(t, healthyLine+"\n"+long[:2*healthChunkSize]), "s-1", "")
+	require.NoError(t, err)
+	assert.True(t, health.Has(IssueTruncated))
+}
+
+func TestRepairFile(t *testing.T) {
+	path := writeHealthFile(t, healthyLine+"\n"+healthyLine[:30])
+	backupDir := filepath.Join(t.TempDir(), "backups")
+
+	backupPath, err := RepairFile(context.Background(), path, backupDir, time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(backupDir, "s-1.20250310T090000Z.jsonl"), backupPath)
+
+	repaired, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, healthyLine+"\n", string(repaired))
+	backup, err := os.ReadFile(backupPath)
+	require.NoError(t, err)
+	assert.Equal(t, healthyLine+"\n"+healthyLine[:30], string(backup))
+
+	health, err := CheckHealth(context.Background(), path, "s-1", "")
+	require.NoError(t, err)
+	assert.True(t, health.OK())
+
+	_, err = RepairFile(context.Background(), path, backupDir, time.Now())
+	require.Error(t, err, "a healthy transcript isn't repaired")
+}
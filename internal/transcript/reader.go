@@ -0,0 +1,240 @@
+package transcript
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Limits bounds how much of a transcript is read
+// Zero MaxBytes and MaxLines mean no limit; a zero MaxLineSize uses the built-in line cap
+type Limits struct {
+	MaxLineSize int   // lines longer than this are skipped rather than buffered
+	MaxBytes    int64 // stop after this many bytes of the file
+	MaxLines    int   // stop after this many records
+}
+
+// DefaultLimits are used by the readers that don't take explicit limits; kam sets them from config
+var DefaultLimits = Limits{MaxLineSize: defaultMaxLineSize}
+
+// ReadStats describes what a read covered and what it left out
+type ReadStats struct {
+	Records      int
+	Bytes        int64
+	SkippedLines int  // lines over MaxLineSize
+	Truncated    bool // MaxBytes or MaxLines stopped the read before the end of the file
+	Partial      bool // the file ended mid-line, usually because Claude is still writing it
+}
+
+// Complete reports whether every line of the file was returned
+func (s ReadStats) Complete() bool {
+	return s.SkippedLines == 0 && !s.Truncated && !s.Partial
+}
+
+// Warning describes lines the read left out, or "" when nothing worth mentioning was lost
+// A partial last line is expected while Claude is writing and isn't reported
+func (s ReadStats) Warning() string {
+	var parts []string
+	if s.Truncated {
+		parts = append(parts, fmt.Sprintf("only the first %d records (%d bytes) were read; raise transcript.maxBytes or transcript.maxLines to see the rest", s.Records, s.Bytes))
+	}
+	if s.SkippedLines > 0 {
+		parts = append(parts, fmt.Sprintf("%d oversized lines were skipped; raise transcript.maxLineSize to include them", s.SkippedLines))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Reader streams records from a transcript one line at a time
+type Reader struct {
+	buf     *bufio.Reader
+	bounded *boundedReader
+	limits  Limits
+	stats   ReadStats
+	done    bool
+}
+
+// boundedReader stops after remaining bytes, noting whether the source had more
+type boundedReader struct {
+	r         io.Reader
+	remaining int64
+	exceeded  bool
+}
+
+func (b *boundedReader) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		var probe [1]byte
+		if n, _ := b.r.Read(probe[:]); n > 0 {
+			b.exceeded = true
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.r.Read(p)
+	b.remaining -= int64(n)
+	return n, err
+}
+
+// NewReader returns a Reader over r
+func NewReader(r io.Reader, limits Limits) *Reader {
+	if limits.MaxLineSize <= 0 {
+		limits.MaxLineSize = maxLineSize
+	}
+	reader := &Reader{limits: limits}
+	if limits.MaxBytes > 0 {
+		reader.bounded = &boundedReader{r: r, remaining: limits.MaxBytes}
+		r = reader.bounded
+	}
+	reader.buf = bufio.NewReaderSize(r, 64*1024)
+	return reader
+}
+
+// Stats returns what has been read so far
+func (r *Reader) Stats() ReadStats {
+	return r.stats
+}
+
+// Next returns the next record, or io.EOF when there are no more
+// Lines that are not valid JSON are returned verbatim with an empty Type so rewrites don't lose
+// data, except for an unterminated last line, which is treated as still being written
+func (r *Reader) Next() (*Record, error) {
+	for !r.done {
+		if r.limits.MaxLines > 0 && r.stats.Records >= r.limits.MaxLines {
+			r.done = true
+			if _, err := r.buf.Peek(1); err == nil {
+				r.stats.Truncated = true
+			}
+			break
+		}
+
+		line, terminated, oversized, err := r.readLine()
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		if errors.Is(err, io.EOF) {
+			r.done = true
+			r.stats.Truncated = r.bounded != nil && r.bounded.exceeded
+		}
+
+		if oversized {
+			r.stats.SkippedLines++
+			continue
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		record := &Record{}
+		if unmarshalErr := json.Unmarshal(line, record); unmarshalErr != nil {
+			if !terminated {
+				r.stats.Partial = !r.stats.Truncated
+				break
+			}
+			record = &Record{}
+		}
+		record.Raw = line
+		r.stats.Records++
+		return record, nil
+	}
+	return nil, io.EOF
+}
+
+// readLine reads up to and including the next newline; a line longer than MaxLineSize is
+// consumed without being kept and reported as oversized
+func (r *Reader) readLine() (line []byte, terminated, oversized bool, err error) {
+	for {
+		chunk, readErr := r.buf.ReadSlice('\n')
+		r.stats.Bytes += int64(len(chunk))
+		if !oversized {
+			if len(line)+len(chunk) > r.limits.MaxLineSize+1 {
+				oversized = true
+				line = nil
+			} else {
+				line = append(line, chunk...)
+			}
+		}
+
+		switch {
+		case readErr == nil:
+			return line, true, oversized, nil
+		case errors.Is(readErr, bufio.ErrBufferFull):
+			continue
+		default:
+			return line, false, oversized, readErr
+		}
+	}
+}
+
+// Scan streams the records of the transcript at path into fn, stopping early if fn fails
+// Only the bytes present when the file is opened are read, so a transcript Claude keeps
+// appending to yields a consistent snapshot
+func Scan(ctx context.Context, path string, limits Limits, fn func(*Record) error) (ReadStats, error) {
+	file, err := os.Open(path) // #nosec G304 -- transcript paths come from Claude's project directory
+	if err != nil {
+		return ReadStats{}, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return ReadStats{}, err
+	}
+	reader := NewReader(io.LimitReader(file, info.Size()), limits)
+	for {
+		if reader.stats.Records%1024 == 0 {
+			if err := types.ContextError(ctx); err != nil {
+				return reader.Stats(), err
+			}
+		}
+		record, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return reader.Stats(), err
+		}
+		if err := fn(record); err != nil {
+			return reader.Stats(), err
+		}
+	}
+
+	return reader.Stats(), nil
+}
+
+// ParseSize parses a byte size such as "512", "64KB", "16MB" or "1GB" (powers of 1024)
+// An empty value or "0" means no limit
+func ParseSize(size string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(size))
+	if value == "" {
+		return 0, nil
+	}
+
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		scale  int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(value, unit.suffix) {
+			value = strings.TrimSpace(strings.TrimSuffix(value, unit.suffix))
+			multiplier = unit.scale
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0, types.NewConfigError(types.ErrCodeConfigInvalid, fmt.Sprintf("invalid size %q: expected bytes or a number with KB, MB or GB", size), err)
+	}
+	return n * multiplier, nil
+}
@@ -0,0 +1,155 @@
+package transcript
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// readAll drains a Reader over input
+func readAll(t *testing.T, input string, limits Limits) ([]*Record, ReadStats) {
+	t.Helper()
+	reader := NewReader(strings.NewReader(input), limits)
+	var records []*Record
+	for {
+		record, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		records = append(records, record)
+	}
+	return records, reader.Stats()
+}
+
+func TestReader_SkipsOversizedLines(t *testing.T) {
+	input := `{"type":"user"}` + "\n" + `{"type":"assistant","uuid":"` + strings.Repeat("x", 200*1024) + `"}` + "\n" + `{"type":"summary"}` + "\n"
+
+	records, stats := readAll(t, input, Limits{MaxLineSize: 1024})
+
+	require.Len(t, records, 2)
+	assert.Equal(t, "user", records[0].Type)
+	assert.Equal(t, "summary", records[1].Type)
+	assert.Equal(t, 1, stats.SkippedLines)
+	assert.Equal(t, int64(len(input)), stats.Bytes)
+	assert.False(t, stats.Complete())
+}
+
+func TestReader_ToleratesUnfinishedLastLine(t *testing.T) {
+	records, stats := readAll(t, "{\"type\":\"user\"}\n{\"type\":\"assist", Limits{})
+
+	require.Len(t, records, 1)
+	assert.True(t, stats.Partial)
+	assert.Empty(t, stats.Warning(), "a line still being written isn't worth a warning")
+
+	records, stats = readAll(t, "{\"type\":\"user\"}\n{\"type\":\"assistant\"}", Limits{})
+	require.Len(t, records, 2, "a complete line without a trailing newline is kept")
+	assert.True(t, stats.Complete())
+}
+
+func TestReader_MaxBytes(t *testing.T) {
+	input := "{\"type\":\"user\"}\n{\"type\":\"assistant\"}\n"
+
+	records, stats := readAll(t, input, Limits{MaxBytes: 20})
+	require.Len(t, records, 1)
+	assert.True(t, stats.Truncated)
+	assert.False(t, stats.Partial, "the cut line is left out because of the limit, not a writer")
+	assert.Contains(t, stats.Warning(), "transcript.maxBytes")
+
+	_, stats = readAll(t, input, Limits{MaxBytes: int64(len(input))})
+	assert.False(t, stats.Truncated, "a limit the file fits in truncates nothing")
+}
+
+func TestReader_MaxLines(t *testing.T) {
+	input := "{\"type\":\"user\"}\n{\"type\":\"assistant\"}\n{\"type\":\"user\"}\n"
+
+	records, stats := readAll(t, input, Limits{MaxLines: 2})
+	assert.Len(t, records, 2)
+	assert.True(t, stats.Truncated)
+
+	records, stats = readAll(t, input, Limits{MaxLines: 3})
+	assert.Len(t, records, 3)
+	assert.False(t, stats.Truncated)
+}
+
+func TestScan(t *testing.T) {
+	path := filepath.Join("testdata", "conversation.jsonl")
+
+	var kinds []string
+	stats, err := Scan(context.Background(), path, Limits{}, func(record *Record) error {
+		kinds = append(kinds, record.Type)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, kinds, 10)
+	assert.Equal(t, 10, stats.Records)
+	assert.True(t, stats.Complete())
+
+	stop := errors.New("stop")
+	calls := 0
+	_, err = Scan(context.Background(), path, Limits{}, func(*Record) error {
+		calls++
+		return stop
+	})
+	assert.Same(t, stop, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestScan_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Scan(ctx, filepath.Join("testdata", "conversation.jsonl"), Limits{}, func(*Record) error { return nil })
+
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeInterrupted, agxErr.Code)
+}
+
+func TestScan_ReadsTheFileAsOpened(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "live.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("{\"type\":\"user\"}\n"), 0o644))
+
+	var count int
+	_, err := Scan(context.Background(), path, Limits{}, func(*Record) error {
+		count++
+		file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+		require.NoError(t, err)
+		defer file.Close()
+		_, err = file.WriteString("{\"type\":\"assistant\"}\n")
+		return err
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, count, "lines appended during the read belong to the next one")
+}
+
+func TestParseSize(t *testing.T) {
+	for input, want := range map[string]int64{
+		"":      0,
+		"0":     0,
+		"512":   512,
+		"512B":  512,
+		"64KB":  64 << 10,
+		"16 mb": 16 << 20,
+		" 2GB ": 2 << 30,
+	} {
+		got, err := ParseSize(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	for _, input := range []string{"lots", "-1MB", "1.5GB", "10TB"} {
+		_, err := ParseSize(input)
+		assert.Error(t, err, input)
+	}
+}
@@ -0,0 +1,94 @@
+package transcript
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitomule/kamui/internal/paths"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// RelocateFile copies the transcript at src to dst for a conversation moving from oldDir to newDir:
+// records written in oldDir, or a directory below it, are rewritten to the matching directory under
+// newDir so the copy reads as if Claude had run there. It returns how many records were rewritten
+func RelocateFile(ctx context.Context, src, dst, oldDir, newDir string) (int, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return 0, types.NewClaudeError(types.ErrCodeClaudeSessionNotFound, fmt.Sprintf("transcript not found: %s", src), err)
+	}
+	if _, err := os.Stat(dst); err == nil {
+		return 0, types.NewSessionError(types.ErrCodeInvalidInput, fmt.Sprintf("a transcript already exists at %s", dst), nil)
+	}
+
+	records, err := ReadFile(ctx, src)
+	if ctxErr := types.ContextError(ctx); ctxErr != nil {
+		return 0, ctxErr
+	}
+	if err != nil {
+		return 0, types.NewStorageError(types.ErrCodeStorageCorrupted, "failed to read transcript", err)
+	}
+
+	rewritten := 0
+	for i, record := range records {
+		cwd, ok := relocatePath(record.CWD, oldDir, newDir)
+		if !ok {
+			continue
+		}
+		relocated, err := withField(record, "cwd", cwd)
+		if err != nil {
+			return 0, types.NewStorageError(types.ErrCodeStorageCorrupted, "failed to rewrite transcript", err)
+		}
+		relocated.CWD = cwd
+		records[i] = relocated
+		rewritten++
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o700); err != nil {
+		return 0, types.NewStorageError(types.ErrCodeStoragePermission, "failed to create Claude project directory", err)
+	}
+	if err := WriteFile(dst, records, info.Mode().Perm()); err != nil {
+		return 0, types.NewStorageError(types.ErrCodeStoragePermission, "failed to write relocated transcript", err)
+	}
+	return rewritten, nil
+}
+
+// relocatePath maps path from under oldDir to under newDir; ok is false when path isn't within oldDir
+func relocatePath(path, oldDir, newDir string) (string, bool) {
+	if path == "" {
+		return "", false
+	}
+	if paths.Equal(path, oldDir) {
+		return newDir, true
+	}
+	rel, err := filepath.Rel(paths.Canonical(oldDir), paths.Canonical(path))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.Join(newDir, rel), true
+}
+
+// withField returns a copy of record whose raw line has the string field set to value
+func withField(record *Record, field, value string) (*Record, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(record.Raw, &fields); err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	fields[field] = encoded
+
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := *record
+	changed.Raw = raw
+	return &changed, nil
+}
@@ -0,0 +1,55 @@
+package transcript
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestRelocateFile(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "s-1.jsonl")
+	require.NoError(t, os.WriteFile(src, []byte(
+		`{"type":"summary","summary":"Moving"}`+"\n"+
+			`{"type":"user","uuid":"u1","cwd":"/work/app","message":{"role":"user","content":"hi"}}`+"\n"+
+			`{"type":"user","uuid":"u2","cwd":"/work/app/web","message":{"role":"user","content":"hi"}}`+"\n"+
+			`{"type":"user","uuid":"u3","cwd":"/work/apps","message":{"role":"user","content":"hi"}}`+"\n",
+	), 0o644))
+	dst := filepath.Join(t.TempDir(), "new-project", "s-1.jsonl")
+
+	rewritten, err := RelocateFile(context.Background(), src, dst, "/work/app", "/home/me/app")
+	require.NoError(t, err)
+	assert.Equal(t, 2, rewritten)
+
+	records, err := ReadFile(context.Background(), dst)
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	assert.Equal(t, "summary", records[0].Type)
+	assert.Equal(t, "/home/me/app", records[1].CWD)
+	assert.Equal(t, filepath.Join("/home/me/app", "web"), records[2].CWD)
+	assert.Equal(t, "/work/apps", records[3].CWD, "a sibling directory sharing the prefix is left alone")
+	assert.Equal(t, "hi", records[1].Text())
+
+	// the original is untouched
+	original, err := ReadFile(context.Background(), src)
+	require.NoError(t, err)
+	assert.Equal(t, "/work/app", original[1].CWD)
+
+	// an existing transcript isn't overwritten
+	_, err = RelocateFile(context.Background(), src, dst, "/work/app", "/home/me/app")
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeInvalidInput, agxErr.Code)
+}
+
+func TestRelocateFile_Missing(t *testing.T) {
+	_, err := RelocateFile(context.Background(), filepath.Join(t.TempDir(), "missing.jsonl"), filepath.Join(t.TempDir(), "s.jsonl"), "/a", "/b")
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeClaudeSessionNotFound, agxErr.Code)
+}
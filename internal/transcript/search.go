@@ -0,0 +1,112 @@
+package transcript
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Entry is one searchable message: a turn's text or one of its tool calls
+type Entry struct {
+	Role      string
+	Timestamp time.Time
+	Tool      string
+	Text      string
+}
+
+// Header is the one-line label of an entry, e.g. "Claude · Tool: Bash"
+func (e Entry) Header() string {
+	header := RoleLabel(e.Role)
+	if e.Tool != "" {
+		header += " · Tool: " + e.Tool
+	}
+	return header
+}
+
+// Hit is an entry in a search result; Match is false for entries shown only as context
+type Hit struct {
+	Index int
+	Entry Entry
+	Match bool
+}
+
+// Entries flattens turns into searchable entries in conversation order
+func Entries(turns []*Turn) []Entry {
+	var entries []Entry
+	for _, turn := range turns {
+		if turn.Text != "" {
+			entries = append(entries, Entry{Role: turn.Role, Timestamp: turn.Timestamp, Text: turn.Text})
+		}
+		for _, call := range turn.Tools {
+			text := call.Input
+			if call.Result != "" {
+				text += "\n" + call.Result
+			}
+			entries = append(entries, Entry{Role: turn.Role, Timestamp: turn.Timestamp, Tool: call.Name, Text: text})
+		}
+	}
+	return entries
+}
+
+// Grep finds entries matching pattern and returns them with up to context entries
+// on either side, grouped so that overlapping neighbourhoods merge
+func Grep(entries []Entry, pattern *regexp.Regexp, context int) [][]Hit {
+	if context < 0 {
+		context = 0
+	}
+
+	var groups [][]Hit
+	var current []Hit
+	last := -1 // index of the last entry added to current
+
+	for i, entry := range entries {
+		if !pattern.MatchString(entry.Text) && !(entry.Tool != "" && pattern.MatchString(entry.Tool)) {
+			continue
+		}
+
+		start := i - context
+		if start <= last {
+			start = last + 1
+		} else if current != nil {
+			groups = append(groups, current)
+			current = nil
+		}
+		if start < 0 {
+			start = 0
+		}
+
+		for j := start; j < i; j++ {
+			current = append(current, Hit{Index: j, Entry: entries[j]})
+		}
+		if last >= i {
+			// already added as trailing context of the previous match
+			current[len(current)-(last-i)-1].Match = true
+		} else {
+			current = append(current, Hit{Index: i, Entry: entry, Match: true})
+			last = i
+		}
+
+		for j := i + 1; j <= i+context && j < len(entries); j++ {
+			if j > last {
+				current = append(current, Hit{Index: j, Entry: entries[j]})
+				last = j
+			}
+		}
+	}
+
+	if current != nil {
+		groups = append(groups, current)
+	}
+	return groups
+}
+
+// MatchingLines returns the lines of text that match pattern
+func MatchingLines(text string, pattern *regexp.Regexp) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if pattern.MatchString(line) {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,83 @@
+package transcript
+
+import (
+	"context"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fixtureEntries(t *testing.T) []Entry {
+	t.Helper()
+	records, err := ReadFile(context.Background(), filepath.Join("testdata", "conversation.jsonl"))
+	require.NoError(t, err)
+	return Entries(Turns(records))
+}
+
+func hitIndexes(groups [][]Hit) [][]int {
+	var indexes [][]int
+	for _, group := range groups {
+		var indexesInGroup []int
+		for _, hit := range group {
+			indexesInGroup = append(indexesInGroup, hit.Index)
+		}
+		indexes = append(indexes, indexesInGroup)
+	}
+	return indexes
+}
+
+func TestEntries(t *testing.T) {
+	entries := fixtureEntries(t)
+
+	var headers []string
+	for _, entry := range entries {
+		headers = append(headers, entry.Header())
+	}
+	assert.Equal(t, []string{
+		"User",
+		"Claude",
+		"Claude · Tool: Read",
+		"User",
+		"Claude · Tool: Edit",
+		"User",
+		"Claude",
+		"Claude · Tool: Bash",
+	}, headers[:8])
+	assert.Contains(t, entries[2].Text, "package main", "tool results are searchable")
+}
+
+func TestGrep_Context(t *testing.T) {
+	entries := []Entry{{Text: "a"}, {Text: "match"}, {Text: "b"}, {Text: "c"}, {Text: "d"}, {Text: "match"}, {Text: "e"}}
+	pattern := regexp.MustCompile("match")
+
+	assert.Equal(t, [][]int{{1}, {5}}, hitIndexes(Grep(entries, pattern, 0)))
+	assert.Equal(t, [][]int{{0, 1, 2}, {4, 5, 6}}, hitIndexes(Grep(entries, pattern, 1)))
+	assert.Equal(t, [][]int{{0, 1, 2, 3, 4, 5, 6}}, hitIndexes(Grep(entries, pattern, 2)), "overlapping context merges")
+}
+
+func TestGrep_MatchInsideContext(t *testing.T) {
+	entries := []Entry{{Text: "match"}, {Text: "match"}, {Text: "x"}}
+	groups := Grep(entries, regexp.MustCompile("match"), 1)
+
+	require.Len(t, groups, 1)
+	require.Len(t, groups[0], 3)
+	assert.True(t, groups[0][0].Match)
+	assert.True(t, groups[0][1].Match)
+	assert.False(t, groups[0][2].Match)
+}
+
+func TestGrep_ToolNames(t *testing.T) {
+	groups := Grep(fixtureEntries(t), regexp.MustCompile("^Edit$"), 0)
+
+	require.Len(t, groups, 1)
+	assert.Equal(t, "Edit", groups[0][0].Entry.Tool)
+}
+
+func TestMatchingLines(t *testing.T) {
+	pattern := regexp.MustCompile("token")
+	assert.Equal(t, []string{"the token expired", "refresh token"}, MatchingLines("the token expired\nretrying\nrefresh token", pattern))
+	assert.Empty(t, MatchingLines("nothing here", pattern))
+}
@@ -0,0 +1,167 @@
+package transcript
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// IdleGap is the pause after which time between records no longer counts as active work
+const IdleGap = 30 * time.Minute
+
+// editTools are the tools whose file_path input is a file Claude changed
+var editTools = map[string]bool{"Edit": true, "MultiEdit": true, "Write": true, "NotebookEdit": true}
+
+// Summary is what happened in a transcript over a period
+type Summary struct {
+	Start       time.Time
+	End         time.Time
+	Active      time.Duration
+	Prompts     int
+	FilesEdited []string
+	Commands    []string
+	Usage       Usage
+	Models      []string
+
+	// FirstPrompt is the text of the first prompt in the period
+	FirstPrompt string
+
+	// UsageByModel splits Usage by the model that produced it
+	UsageByModel map[string]Usage
+}
+
+// TotalTokens returns input, output and cache tokens combined
+func (u Usage) TotalTokens() int64 {
+	return u.InputTokens + u.OutputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+}
+
+// Add accumulates another usage record
+func (u *Usage) Add(other Usage) {
+	u.InputTokens += other.InputTokens
+	u.OutputTokens += other.OutputTokens
+	u.CacheCreationInputTokens += other.CacheCreationInputTokens
+	u.CacheReadInputTokens += other.CacheReadInputTokens
+}
+
+// Summarize collects edited files, commands, token usage and active time from records at or after since
+// A zero since includes the whole transcript
+func Summarize(records []*Record, since time.Time) Summary {
+	summarizer := NewSummarizer(since)
+	for _, record := range records {
+		summarizer.Add(record)
+	}
+	return summarizer.Summary()
+}
+
+// SummarizeFile summarizes the transcript at path without holding its records in memory
+func SummarizeFile(ctx context.Context, path string, since time.Time) (Summary, error) {
+	summarizer := NewSummarizer(since)
+	if _, err := Scan(ctx, path, DefaultLimits, func(record *Record) error {
+		summarizer.Add(record)
+		return nil
+	}); err != nil {
+		return Summary{}, err
+	}
+	return summarizer.Summary(), nil
+}
+
+// Summarizer builds a Summary one record at a time
+type Summarizer struct {
+	since   time.Time
+	summary Summary
+	files   map[string]bool
+	models  map[string]bool
+	last    time.Time
+}
+
+// NewSummarizer returns a Summarizer for records at or after since
+func NewSummarizer(since time.Time) *Summarizer {
+	return &Summarizer{
+		since:   since,
+		summary: Summary{UsageByModel: make(map[string]Usage)},
+		files:   make(map[string]bool),
+		models:  make(map[string]bool),
+	}
+}
+
+// Add accounts for one record
+func (s *Summarizer) Add(record *Record) {
+	summary := &s.summary
+	if record.Timestamp.IsZero() || record.Timestamp.Before(s.since) {
+		return
+	}
+
+	if summary.Start.IsZero() || record.Timestamp.Before(summary.Start) {
+		summary.Start = record.Timestamp
+	}
+	if record.Timestamp.After(summary.End) {
+		summary.End = record.Timestamp
+	}
+	if !s.last.IsZero() {
+		if gap := record.Timestamp.Sub(s.last); gap > 0 && gap < IdleGap {
+			summary.Active += gap
+		}
+	}
+	s.last = record.Timestamp
+
+	if record.IsPrompt() {
+		summary.Prompts++
+		if summary.FirstPrompt == "" {
+			summary.FirstPrompt = record.Text()
+		}
+	}
+	if record.Type != "assistant" || record.Message == nil {
+		return
+	}
+
+	if record.Message.Usage != nil {
+		summary.Usage.Add(*record.Message.Usage)
+		modelUsage := summary.UsageByModel[record.Message.Model]
+		modelUsage.Add(*record.Message.Usage)
+		summary.UsageByModel[record.Message.Model] = modelUsage
+	}
+	if record.Message.Model != "" && record.Message.Model != "<synthetic>" {
+		s.models[record.Message.Model] = true
+	}
+
+	for _, block := range record.Message.Content {
+		if block.Type != "tool_use" {
+			continue
+		}
+		var input struct {
+			FilePath     string `json:"file_path"`
+			NotebookPath string `json:"notebook_path"`
+			Command      string `json:"command"`
+		}
+		if err := json.Unmarshal(block.Input, &input); err != nil {
+			continue
+		}
+
+		switch {
+		case editTools[block.Name] && input.FilePath != "":
+			s.files[input.FilePath] = true
+		case editTools[block.Name] && input.NotebookPath != "":
+			s.files[input.NotebookPath] = true
+		case block.Name == "Bash" && input.Command != "":
+			summary.Commands = append(summary.Commands, input.Command)
+		}
+	}
+}
+
+// Summary returns the summary of the records added so far
+func (s *Summarizer) Summary() Summary {
+	summary := s.summary
+	summary.FilesEdited = sortedKeys(s.files)
+	summary.Models = sortedKeys(s.models)
+	return summary
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,65 @@
+package transcript
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarize(t *testing.T) {
+	records, err := ReadFile(context.Background(), filepath.Join("testdata", "conversation.jsonl"))
+	require.NoError(t, err)
+
+	summary := Summarize(records, time.Time{})
+
+	assert.Equal(t, time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC), summary.Start)
+	assert.Equal(t, time.Date(2025, 3, 10, 9, 10, 30, 0, time.UTC), summary.End)
+	assert.Equal(t, 10*time.Minute+30*time.Second, summary.Active)
+	assert.Equal(t, 3, summary.Prompts)
+	assert.Equal(t, []string{"/work/app/login.go"}, summary.FilesEdited)
+	assert.Equal(t, []string{"go test ./..."}, summary.Commands)
+	assert.Equal(t, []string{"claude-sonnet-4"}, summary.Models)
+	assert.Equal(t, Usage{InputTokens: 1020, OutputTokens: 200, CacheReadInputTokens: 1000}, summary.Usage)
+	assert.Equal(t, int64(2220), summary.Usage.TotalTokens())
+	assert.Equal(t, map[string]Usage{"claude-sonnet-4": summary.Usage}, summary.UsageByModel)
+}
+
+func TestSummarize_Since(t *testing.T) {
+	records, err := ReadFile(context.Background(), filepath.Join("testdata", "conversation.jsonl"))
+	require.NoError(t, err)
+
+	summary := Summarize(records, time.Date(2025, 3, 10, 9, 8, 0, 0, time.UTC))
+
+	assert.Equal(t, 1, summary.Prompts)
+	assert.Empty(t, summary.FilesEdited)
+	assert.Equal(t, []string{"go test ./..."}, summary.Commands)
+	assert.Equal(t, int64(400), summary.Usage.InputTokens)
+}
+
+func TestSummarize_IdleGapsAreNotActive(t *testing.T) {
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	records := []*Record{
+		{Type: "user", Timestamp: start, Message: &Message{Content: Content{{Type: "text", Text: "a"}}}},
+		{Type: "assistant", Timestamp: start.Add(5 * time.Minute), Message: &Message{}},
+		{Type: "user", Timestamp: start.Add(3 * time.Hour), Message: &Message{Content: Content{{Type: "text", Text: "b"}}}},
+	}
+
+	summary := Summarize(records, time.Time{})
+	assert.Equal(t, 5*time.Minute, summary.Active)
+	assert.Equal(t, 2, summary.Prompts)
+}
+
+func TestSummarizeFile(t *testing.T) {
+	path := filepath.Join("testdata", "conversation.jsonl")
+	records, err := ReadFile(context.Background(), path)
+	require.NoError(t, err)
+
+	summary, err := SummarizeFile(context.Background(), path, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, Summarize(records, time.Time{}), summary)
+	assert.Equal(t, "Why does login fail?", summary.FirstPrompt)
+}
@@ -0,0 +1,165 @@
+// Package transcript reads and rewrites Claude Code conversation transcripts (JSONL)
+package transcript
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bitomule/kamui/internal/paths"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// maxLineSize bounds a single transcript line; tool results can be large
+// Streaming reads default to a lower cap, see DefaultLimits
+const maxLineSize = 64 * 1024 * 1024
+
+// defaultMaxLineSize is the line cap for reads that only derive data from a transcript
+const defaultMaxLineSize = 16 * 1024 * 1024
+
+// Record is one line of a Claude transcript
+type Record struct {
+	Type       string    `json:"type"`
+	UUID       string    `json:"uuid"`
+	ParentUUID *string   `json:"parentUuid"`
+	SessionID  string    `json:"sessionId"`
+	Timestamp  time.Time `json:"timestamp"`
+	CWD        string    `json:"cwd"`
+	GitBranch  string    `json:"gitBranch"`
+	Message    *Message  `json:"message"`
+
+	// Raw is the original line, written back unchanged unless a rewrite is needed
+	Raw json.RawMessage `json:"-"`
+}
+
+// Message is the conversational payload of a user or assistant record
+type Message struct {
+	Role    string  `json:"role"`
+	Model   string  `json:"model"`
+	Content Content `json:"content"`
+	Usage   *Usage  `json:"usage"`
+}
+
+// Usage is the token accounting Claude reports on assistant messages
+type Usage struct {
+	InputTokens              int64 `json:"input_tokens"`
+	OutputTokens             int64 `json:"output_tokens"`
+	CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+}
+
+// Block is one content block: text, tool_use, tool_result or thinking
+type Block struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	Thinking  string          `json:"thinking,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	Content   json.RawMessage `json:"content,omitempty"`
+	IsError   bool            `json:"is_error,omitempty"`
+}
+
+// Content is a message body; Claude writes either a plain string or a list of blocks
+type Content []Block
+
+// UnmarshalJSON accepts both the string and the block list forms
+func (c *Content) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*c = Content{{Type: "text", Text: text}}
+		return nil
+	}
+
+	var blocks []Block
+	if err := json.Unmarshal(data, &blocks); err != nil {
+		return err
+	}
+	*c = blocks
+	return nil
+}
+
+// IsPrompt reports whether the record is a prompt typed by the user, which starts an exchange
+// Tool results are also recorded as user messages but continue the current exchange
+func (r *Record) IsPrompt() bool {
+	if r.Type != "user" || r.Message == nil {
+		return false
+	}
+	for _, block := range r.Message.Content {
+		if block.Type == "tool_result" {
+			return false
+		}
+	}
+	return true
+}
+
+// Text returns the concatenated text blocks of the record's message
+func (r *Record) Text() string {
+	if r.Message == nil {
+		return ""
+	}
+	var buf bytes.Buffer
+	for _, block := range r.Message.Content {
+		if block.Type == "text" && block.Text != "" {
+			if buf.Len() > 0 {
+				buf.WriteString("\n\n")
+			}
+			buf.WriteString(block.Text)
+		}
+	}
+	return buf.String()
+}
+
+// Path returns the transcript file for a session's bound Claude conversation, or "" if unbound
+func Path(session *types.Session, homeDir string) string {
+	if session.Claude.SessionID == "" {
+		return ""
+	}
+	return filepath.Join(paths.ClaudeProjectDir(homeDir, session.Project.WorkingDirectory), session.Claude.SessionID+".jsonl")
+}
+
+// ReadFile parses a whole transcript so it can be rewritten
+// Lines that are not valid JSON are kept verbatim with an empty Type; a line over the hard
+// line cap or an unfinished last line fails the read instead of being dropped
+func ReadFile(ctx context.Context, path string) ([]*Record, error) {
+	var records []*Record
+	stats, err := Scan(ctx, path, Limits{MaxLineSize: maxLineSize}, func(record *Record) error {
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if stats.SkippedLines > 0 {
+		return nil, fmt.Errorf("%d lines exceed %d bytes", stats.SkippedLines, maxLineSize)
+	}
+	if stats.Partial {
+		return nil, errors.New("the last line is incomplete; Claude may still be writing the transcript")
+	}
+	return records, nil
+}
+
+// WriteFile atomically writes records to path, one raw line each
+func WriteFile(path string, records []*Record, perm os.FileMode) error {
+	var buf bytes.Buffer
+	for _, record := range records {
+		buf.Write(record.Raw)
+		buf.WriteByte('\n')
+	}
+
+	tempFile := path + ".tmp"
+	if err := os.WriteFile(tempFile, buf.Bytes(), perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tempFile, path); err != nil {
+		os.Remove(tempFile)
+		return err
+	}
+	return nil
+}
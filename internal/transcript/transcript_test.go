@@ -0,0 +1,64 @@
+package transcript
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/paths"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func TestReadFile(t *testing.T) {
+	records, err := ReadFile(context.Background(), filepath.Join("testdata", "conversation.jsonl"))
+	require.NoError(t, err)
+	require.Len(t, records, 10)
+
+	assert.Equal(t, "summary", records[0].Type)
+	assert.True(t, records[1].IsPrompt())
+	assert.Equal(t, "Why does login fail?", records[1].Text())
+	assert.Nil(t, records[1].ParentUUID)
+
+	assistant := records[2]
+	assert.Equal(t, "claude-sonnet-4", assistant.Message.Model)
+	assert.Equal(t, "Let me look.", assistant.Text())
+	require.Len(t, assistant.Message.Content, 2)
+	assert.Equal(t, "Read", assistant.Message.Content[1].Name)
+	assert.Equal(t, int64(1000), assistant.Message.Usage.CacheReadInputTokens)
+
+	assert.False(t, records[3].IsPrompt(), "tool results continue the exchange")
+	assert.True(t, records[5].IsPrompt(), "block-form prompts start an exchange")
+}
+
+func TestReadFile_KeepsInvalidLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "broken.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("{\"type\":\"user\"}\nnot json\n\n"), 0o644))
+
+	records, err := ReadFile(context.Background(), path)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "", records[1].Type)
+	assert.Equal(t, "not json", string(records[1].Raw))
+}
+
+func TestReadFile_RefusesUnfinishedLastLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "live.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("{\"type\":\"user\"}\n{\"type\":\"assist"), 0o644))
+
+	_, err := ReadFile(context.Background(), path)
+	assert.ErrorContains(t, err, "still be writing")
+}
+
+func TestPath(t *testing.T) {
+	session := &types.Session{
+		Project: types.ProjectInfo{WorkingDirectory: "/work/app"},
+	}
+	assert.Empty(t, Path(session, "/home/me"))
+
+	session.Claude.SessionID = "abc"
+	assert.Equal(t, filepath.Join(paths.ClaudeProjectDir("/home/me", "/work/app"), "abc.jsonl"), Path(session, "/home/me"))
+}
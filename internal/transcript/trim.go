@@ -0,0 +1,160 @@
+package transcript
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// TrimResult describes a transcript trim
+type TrimResult struct {
+	Path           string
+	BackupPath     string
+	Exchanges      int
+	KeptExchanges  int
+	RemovedRecords int
+	BytesBefore    int64
+	BytesAfter     int64
+}
+
+// Trim keeps the last keepLast exchanges of a transcript
+// Summary records before the cut are kept, and the first kept message is detached from
+// its now-missing parent so Claude can still resume the conversation
+func Trim(records []*Record, keepLast int) ([]*Record, error) {
+	starts := exchangeStarts(records)
+	if keepLast <= 0 || len(starts) <= keepLast {
+		return records, nil
+	}
+	cut := starts[len(starts)-keepLast]
+
+	kept := make([]*Record, 0, len(records)-cut+1)
+	for _, record := range records[:cut] {
+		if record.Type == "summary" {
+			kept = append(kept, record)
+		}
+	}
+
+	first, err := detach(records[cut])
+	if err != nil {
+		return nil, err
+	}
+	kept = append(kept, first)
+	kept = append(kept, records[cut+1:]...)
+	return kept, nil
+}
+
+// TrimFile trims the transcript at path to its last keepLast exchanges, copying the original into backupDir first
+func TrimFile(ctx context.Context, path, backupDir string, keepLast int, now time.Time) (*TrimResult, error) {
+	if keepLast <= 0 {
+		return nil, types.NewSessionError(types.ErrCodeInvalidInput, "--keep-last must be at least 1", nil)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, types.NewClaudeError(types.ErrCodeClaudeSessionNotFound, fmt.Sprintf("transcript not found: %s", path), err)
+	}
+
+	records, err := ReadFile(ctx, path)
+	if ctxErr := types.ContextError(ctx); ctxErr != nil {
+		return nil, ctxErr
+	}
+	if err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStorageCorrupted, "failed to read transcript", err)
+	}
+
+	result := &TrimResult{
+		Path:        path,
+		Exchanges:   len(exchangeStarts(records)),
+		BytesBefore: info.Size(),
+		BytesAfter:  info.Size(),
+	}
+
+	trimmed, err := Trim(records, keepLast)
+	if err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStorageCorrupted, "failed to trim transcript", err)
+	}
+	result.KeptExchanges = len(exchangeStarts(trimmed))
+	result.RemovedRecords = len(records) - len(trimmed)
+	if result.RemovedRecords == 0 {
+		return result, nil
+	}
+
+	backupPath := filepath.Join(backupDir, fmt.Sprintf("%s.%s.jsonl", trimExtension(filepath.Base(path)), now.UTC().Format("20060102T150405Z")))
+	if err := copyFile(path, backupPath); err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStoragePermission, "failed to back up transcript", err)
+	}
+	result.BackupPath = backupPath
+
+	if err := WriteFile(path, trimmed, info.Mode().Perm()); err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStoragePermission, "failed to write trimmed transcript", err)
+	}
+	if trimmedInfo, err := os.Stat(path); err == nil {
+		result.BytesAfter = trimmedInfo.Size()
+	}
+
+	return result, nil
+}
+
+// exchangeStarts returns the indexes of records that start an exchange
+func exchangeStarts(records []*Record) []int {
+	var starts []int
+	for i, record := range records {
+		if record.IsPrompt() {
+			starts = append(starts, i)
+		}
+	}
+	return starts
+}
+
+// detach returns a copy of record with its parentUuid cleared
+func detach(record *Record) (*Record, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(record.Raw, &fields); err != nil {
+		return nil, err
+	}
+	fields["parentUuid"] = json.RawMessage("null")
+
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	detached := *record
+	detached.ParentUUID = nil
+	detached.Raw = raw
+	return &detached, nil
+}
+
+// copyFile copies src to dst, creating dst's directory
+func copyFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o700); err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// trimExtension strips the file extension from name
+func trimExtension(name string) string {
+	return name[:len(name)-len(filepath.Ext(name))]
+}
@@ -0,0 +1,101 @@
+package transcript
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func copyFixture(t *testing.T) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "conversation.jsonl"))
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "s-1.jsonl")
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+	return path
+}
+
+func TestTrim(t *testing.T) {
+	records, err := ReadFile(context.Background(), filepath.Join("testdata", "conversation.jsonl"))
+	require.NoError(t, err)
+
+	trimmed, err := Trim(records, 2)
+	require.NoError(t, err)
+
+	// summary + exchanges starting at u2
+	require.Len(t, trimmed, 6)
+	assert.Equal(t, "summary", trimmed[0].Type)
+	assert.Equal(t, "u2", trimmed[1].UUID)
+	assert.Nil(t, trimmed[1].ParentUUID)
+	assert.Contains(t, string(trimmed[1].Raw), `"parentUuid":null`)
+	assert.Equal(t, "a4", trimmed[5].UUID)
+
+	// the original record is left untouched
+	assert.Contains(t, string(records[5].Raw), `"parentUuid":"a2"`)
+}
+
+func TestTrim_NothingToRemove(t *testing.T) {
+	records, err := ReadFile(context.Background(), filepath.Join("testdata", "conversation.jsonl"))
+	require.NoError(t, err)
+
+	trimmed, err := Trim(records, 3)
+	require.NoError(t, err)
+	assert.Equal(t, records, trimmed)
+}
+
+func TestTrimFile(t *testing.T) {
+	path := copyFixture(t)
+	backupDir := filepath.Join(t.TempDir(), "backups")
+	now := time.Date(2025, 3, 11, 8, 0, 0, 0, time.UTC)
+
+	result, err := TrimFile(context.Background(), path, backupDir, 1, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, result.Exchanges)
+	assert.Equal(t, 1, result.KeptExchanges)
+	assert.Equal(t, 7, result.RemovedRecords)
+	assert.Less(t, result.BytesAfter, result.BytesBefore)
+	assert.Equal(t, filepath.Join(backupDir, "s-1.20250311T080000Z.jsonl"), result.BackupPath)
+
+	backup, err := os.ReadFile(result.BackupPath)
+	require.NoError(t, err)
+	original, err := os.ReadFile(filepath.Join("testdata", "conversation.jsonl"))
+	require.NoError(t, err)
+	assert.Equal(t, original, backup)
+
+	records, err := ReadFile(context.Background(), path)
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, "u3", records[1].UUID)
+}
+
+func TestTrimFile_NoChangeMakesNoBackup(t *testing.T) {
+	path := copyFixture(t)
+	backupDir := filepath.Join(t.TempDir(), "backups")
+
+	result, err := TrimFile(context.Background(), path, backupDir, 10, time.Now())
+	require.NoError(t, err)
+	assert.Zero(t, result.RemovedRecords)
+	assert.Empty(t, result.BackupPath)
+	assert.NoDirExists(t, backupDir)
+}
+
+func TestTrimFile_Errors(t *testing.T) {
+	var agxErr *types.AGXError
+
+	_, err := TrimFile(context.Background(), copyFixture(t), t.TempDir(), 0, time.Now())
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeInvalidInput, agxErr.Code)
+
+	_, err = TrimFile(context.Background(), filepath.Join(t.TempDir(), "missing.jsonl"), t.TempDir(), 5, time.Now())
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeClaudeSessionNotFound, agxErr.Code)
+}
@@ -0,0 +1,359 @@
+package validate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Fix is one change Repair made to a session file
+type Fix struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// RepairInput is a damaged session file and what Repair may draw on to fix it
+type RepairInput struct {
+	// Name is the session the file belongs to, from its file name
+	Name string
+	Data []byte
+
+	// ModTime is when the file was last written, the fallback for missing timestamps
+	ModTime time.Time
+
+	// Previous is the last good version of the file kept by storage, if any
+	Previous []byte
+
+	// ProjectPath is used when the file records no usable project path
+	ProjectPath string
+}
+
+// RepairReport is what RepairFile did to a session file
+type RepairReport struct {
+	Session string `json:"session"`
+	File    string `json:"file"`
+	Backup  string `json:"backup,omitempty"`
+	Fixes   []Fix  `json:"fixes"`
+
+	// Remaining are the problems repair couldn't fix
+	Remaining []Problem `json:"remaining"`
+}
+
+// sectionOrder lists the top-level fields of a session file in the order they are written
+var sectionOrder = []string{
+	"version", "sessionId", "created", "lastAccessed", "lastModified",
+	"project", "claude", "metadata", "statistics", "lifecycle",
+}
+
+// derivedSections are re-derived by Repair when missing, so their absence isn't reported on its own
+var derivedSections = map[string]bool{
+	"version": true, "sessionId": true, "created": true, "lastAccessed": true, "lastModified": true,
+}
+
+// section decodes one top-level field into a session, rejecting unknown nested fields when strict
+type section func(raw json.RawMessage, strict bool) error
+
+// RepairFile repairs the file of session name in store; the damaged file is moved into the session's
+// backups before the repaired one is saved, and with dryRun nothing is written
+func RepairFile(ctx context.Context, store *storage.Storage, name, projectPath string, now time.Time, dryRun bool) (*RepairReport, error) {
+	if err := types.ContextError(ctx); err != nil {
+		return nil, err
+	}
+
+	file := filepath.Join(store.GetSessionsPath(), name+".json")
+	info, err := os.Stat(file)
+	if os.IsNotExist(err) {
+		return nil, types.NewStorageError(types.ErrCodeSessionNotFound, fmt.Sprintf("session '%s' not found", name), err)
+	}
+	if err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStoragePermission, "failed to read session file", err)
+	}
+	data, err := os.ReadFile(file) // #nosec G304 -- path is inside the sessions directory
+	if err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStoragePermission, "failed to read session file", err)
+	}
+	previous, _ := os.ReadFile(store.PreviousVersionPath(name)) // #nosec G304 -- path is inside the sessions directory
+
+	session, fixes := Repair(RepairInput{
+		Name:        name,
+		Data:        data,
+		ModTime:     info.ModTime(),
+		Previous:    previous,
+		ProjectPath: projectPath,
+	}, now)
+	report := &RepairReport{
+		Session:   name,
+		File:      file,
+		Fixes:     fixes,
+		Remaining: Session(session, name, "", now),
+	}
+	if report.Fixes == nil {
+		report.Fixes = []Fix{}
+	}
+	if report.Remaining == nil {
+		report.Remaining = []Problem{}
+	}
+	if dryRun || len(fixes) == 0 {
+		return report, nil
+	}
+
+	backupDir := store.GetBackupsPath(name)
+	report.Backup = filepath.Join(backupDir, fmt.Sprintf("session.%s.damaged.json", now.UTC().Format("20060102T150405Z")))
+	if err := os.MkdirAll(backupDir, 0o700); err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStoragePermission, "failed to create backups directory", err)
+	}
+	// moving the damaged file aside keeps SaveSession from taking it for the last good version
+	if err := os.Rename(file, report.Backup); err != nil {
+		return nil, types.NewStorageError(types.ErrCodeStoragePermission, "failed to back up session file", err)
+	}
+	if err := store.SaveSession(ctx, session); err != nil {
+		_ = os.Rename(report.Backup, file)
+		return nil, err
+	}
+	return report, nil
+}
+
+// Repair rebuilds a session from a damaged file. Sections that are missing or don't decode are
+// restored from the previous version where it has them and dropped otherwise, unknown fields are
+// stripped, and missing or inconsistent required fields are re-derived from the rest
+// It returns the session and each fix made, none when the file was sound
+func Repair(input RepairInput, now time.Time) (*types.Session, []Fix) {
+	var fixes []Fix
+	fix := func(field, format string, args ...interface{}) {
+		fixes = append(fixes, Fix{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	previous, _ := parseSections(input.Previous)
+	sections, err := parseSections(input.Data)
+	if err != nil {
+		if previous != nil {
+			fix("", "the file isn't valid JSON (%v); restored the last good version", err)
+			sections, previous = previous, nil
+		} else {
+			fix("", "the file isn't valid JSON (%v) and there's no backup; rebuilt it from what could be derived", err)
+			sections = map[string]json.RawMessage{}
+		}
+	}
+
+	session := &types.Session{}
+	decoders := sectionDecoders(session)
+
+	keys := make([]string, 0, len(sections))
+	for key := range sections {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if decoders[key] == nil {
+			fix(key, "removed unknown field")
+		}
+	}
+
+	for _, key := range sectionOrder {
+		decode := decoders[key]
+		raw, present := sections[key]
+		var problem string
+		if present {
+			decodeErr := decode(raw, true)
+			if decodeErr == nil {
+				continue
+			}
+			if decode(raw, false) == nil {
+				fix(key, "removed unknown fields (%v)", decodeErr)
+				continue
+			}
+			problem = fmt.Sprintf("corrupt section (%v)", decodeErr)
+		} else {
+			problem = "missing section"
+		}
+
+		if previousRaw, ok := previous[key]; ok && decode(previousRaw, false) == nil {
+			fix(key, "%s; restored from the last good version", problem)
+			continue
+		}
+		if present {
+			fix(key, "removed %s", problem)
+		} else if !derivedSections[key] && len(sections) > 0 {
+			fix(key, "%s; left empty", problem)
+		}
+	}
+
+	if session.Version == "" {
+		session.Version = storage.FormatVersion
+		fix("version", "set the missing format version to %s", storage.FormatVersion)
+	}
+	if session.SessionID != input.Name {
+		if session.SessionID == "" {
+			fix("sessionId", "set the missing session ID to '%s' from the file name", input.Name)
+		} else {
+			fix("sessionId", "changed '%s' to '%s' to match the file name", session.SessionID, input.Name)
+		}
+		session.SessionID = input.Name
+	}
+	repairProject(&session.Project, input.ProjectPath, fix)
+	repairTimestamps(session, input.ModTime, now, fix)
+	repairLifecycle(&session.Lifecycle, fix)
+
+	return session, fixes
+}
+
+// parseSections splits a session file into its top-level fields; nil data has none
+func parseSections(data []byte) (map[string]json.RawMessage, error) {
+	if data == nil {
+		return nil, nil
+	}
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(data, &sections); err != nil {
+		return nil, err
+	}
+	if sections == nil {
+		return nil, fmt.Errorf("the file holds null")
+	}
+	return sections, nil
+}
+
+// sectionDecoders maps each top-level field of a session file to the session field it decodes into
+func sectionDecoders(session *types.Session) map[string]section {
+	return map[string]section{
+		"version":      decodeInto(&session.Version),
+		"sessionId":    decodeInto(&session.SessionID),
+		"created":      decodeInto(&session.Created),
+		"lastAccessed": decodeInto(&session.LastAccessed),
+		"lastModified": decodeInto(&session.LastModified),
+		"project":      decodeInto(&session.Project),
+		"claude":       decodeInto(&session.Claude),
+		"metadata":     decodeInto(&session.Metadata),
+		"statistics":   decodeInto(&session.Stats),
+		"lifecycle":    decodeInto(&session.Lifecycle),
+	}
+}
+
+// decodeInto returns a section that sets field only when the whole value decodes
+func decodeInto[T any](field *T) section {
+	return func(raw json.RawMessage, strict bool) error {
+		var value T
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		if strict {
+			decoder.DisallowUnknownFields()
+		}
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+		*field = value
+		return nil
+	}
+}
+
+// repairProject fills in a missing or relative project path and working directory from each other,
+// falling back to projectPath
+func repairProject(project *types.ProjectInfo, projectPath string, fix func(field, format string, args ...interface{})) {
+	if !filepath.IsAbs(project.Path) {
+		derived, source := "", ""
+		switch {
+		case filepath.IsAbs(project.WorkingDirectory):
+			derived, source = project.WorkingDirectory, "the working directory"
+		case filepath.IsAbs(projectPath):
+			derived, source = projectPath, "the current project"
+		}
+		if derived != "" {
+			fix("project.path", "set to %s from %s (was '%s')", derived, source, project.Path)
+			project.Path = derived
+		}
+	}
+	if !filepath.IsAbs(project.WorkingDirectory) && filepath.IsAbs(project.Path) {
+		fix("project.workingDirectory", "set to the project path %s (was '%s')", project.Path, project.WorkingDirectory)
+		project.WorkingDirectory = project.Path
+	}
+	if project.Name == "" && filepath.IsAbs(project.Path) {
+		project.Name = filepath.Base(project.Path)
+		fix("project.name", "set the missing project name to '%s'", project.Name)
+	}
+}
+
+// repairTimestamps replaces missing and future timestamps with the latest time the session records,
+// or the file's modification time, and moves created back to the earliest so nothing precedes it
+func repairTimestamps(session *types.Session, modTime, now time.Time, fix func(field, format string, args ...interface{})) {
+	valid := func(t time.Time) bool { return !t.IsZero() && !t.After(now.Add(clockSkew)) }
+
+	var earliest, latest time.Time
+	record := func(t time.Time) {
+		if !valid(t) {
+			return
+		}
+		if earliest.IsZero() || t.Before(earliest) {
+			earliest = t
+		}
+		if latest.IsZero() || t.After(latest) {
+			latest = t
+		}
+	}
+	record(session.Created)
+	record(session.LastAccessed)
+	record(session.LastModified)
+	for _, change := range session.Lifecycle.StateHistory {
+		record(change.Timestamp)
+	}
+	record(modTime)
+	if latest.IsZero() {
+		earliest, latest = now, now
+	}
+
+	timestamps := []struct {
+		field string
+		value *time.Time
+		with  time.Time
+	}{
+		{"created", &session.Created, earliest},
+		{"lastAccessed", &session.LastAccessed, latest},
+		{"lastModified", &session.LastModified, latest},
+	}
+	for _, timestamp := range timestamps {
+		switch {
+		case timestamp.value.IsZero():
+			fix(timestamp.field, "set the missing timestamp to %s", timestamp.with.Format(time.RFC3339))
+			*timestamp.value = timestamp.with
+		case !valid(*timestamp.value):
+			fix(timestamp.field, "replaced future timestamp %s with %s", timestamp.value.Format(time.RFC3339), timestamp.with.Format(time.RFC3339))
+			*timestamp.value = timestamp.with
+		}
+	}
+
+	if session.LastAccessed.Before(session.Created) || session.LastModified.Before(session.Created) {
+		fix("created", "moved back from %s to %s, the earliest time the session records",
+			session.Created.Format(time.RFC3339), earliest.Format(time.RFC3339))
+		session.Created = earliest
+	}
+}
+
+// repairLifecycle drops history entries with unknown states and replaces an unknown current state
+// with the last known one, or active
+func repairLifecycle(lifecycle *types.LifecycleInfo, fix func(field, format string, args ...interface{})) {
+	var history []types.StateChange
+	for i, change := range lifecycle.StateHistory {
+		if !change.State.IsValid() {
+			fix(fmt.Sprintf("lifecycle.stateHistory[%d]", i), "removed change to unknown state '%s'", change.State)
+			continue
+		}
+		history = append(history, change)
+	}
+	if len(history) != len(lifecycle.StateHistory) {
+		lifecycle.StateHistory = history
+	}
+
+	if !lifecycle.State.IsValid() {
+		state := types.SessionStateActive
+		if len(history) > 0 {
+			state = history[len(history)-1].State
+		}
+		fix("lifecycle.state", "replaced unknown state '%s' with '%s'", lifecycle.State, state)
+		lifecycle.State = state
+	}
+}
@@ -0,0 +1,205 @@
+package validate
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+func soundSession(t *testing.T, name string) *types.Session {
+	t.Helper()
+	session := validSession(t, name)
+	session.Project.Name = filepath.Base(session.Project.Path)
+	return session
+}
+
+func marshal(t *testing.T, value interface{}) []byte {
+	t.Helper()
+	data, err := json.Marshal(value)
+	require.NoError(t, err)
+	return data
+}
+
+// edit decodes a session file's top-level fields, lets change edit them and encodes the result
+func edit(t *testing.T, session *types.Session, change func(sections map[string]interface{})) []byte {
+	t.Helper()
+	var sections map[string]interface{}
+	require.NoError(t, json.Unmarshal(marshal(t, session), &sections))
+	change(sections)
+	return marshal(t, sections)
+}
+
+func fields(fixes []Fix) []string {
+	found := make([]string, len(fixes))
+	for i, fix := range fixes {
+		found[i] = fix.Field
+	}
+	return found
+}
+
+func TestRepair_SoundFile(t *testing.T) {
+	session := soundSession(t, "api")
+	repaired, fixes := Repair(RepairInput{Name: "api", Data: marshal(t, session)}, now)
+	assert.Empty(t, fixes)
+	assert.Equal(t, session.Project, repaired.Project)
+	assert.True(t, session.Created.Equal(repaired.Created))
+}
+
+func TestRepair_DerivesMissingFields(t *testing.T) {
+	session := soundSession(t, "api")
+	modTime := now.Add(-time.Minute)
+	data := edit(t, session, func(sections map[string]interface{}) {
+		delete(sections, "version")
+		delete(sections, "created")
+		delete(sections, "lastModified")
+		sections["sessionId"] = "other"
+		sections["project"] = map[string]interface{}{"workingDirectory": session.Project.Path}
+		sections["lifecycle"] = map[string]interface{}{
+			"state": "sleeping",
+			"stateHistory": []interface{}{
+				map[string]interface{}{"state": "paused", "timestamp": session.Created},
+				map[string]interface{}{"state": "dreaming", "timestamp": session.Created},
+			},
+		}
+	})
+
+	repaired, fixes := Repair(RepairInput{Name: "api", Data: data, ModTime: modTime}, now)
+	assert.Equal(t, []string{
+		"version", "sessionId", "project.path", "project.name", "created", "lastModified",
+		"lifecycle.stateHistory[1]", "lifecycle.state",
+	}, fields(fixes))
+
+	assert.Equal(t, storage.FormatVersion, repaired.Version)
+	assert.Equal(t, "api", repaired.SessionID)
+	assert.Equal(t, session.Project.Path, repaired.Project.Path)
+	assert.Equal(t, filepath.Base(session.Project.Path), repaired.Project.Name)
+	assert.True(t, session.Created.Equal(repaired.Created), "created is the earliest recorded time")
+	assert.True(t, modTime.Equal(repaired.LastModified), "lastModified falls back to the file's modification time")
+	assert.Equal(t, types.SessionStatePaused, repaired.Lifecycle.State)
+	assert.Len(t, repaired.Lifecycle.StateHistory, 1)
+	assert.Empty(t, Session(repaired, "api", "", now))
+}
+
+func TestRepair_StripsUnknownAndCorruptSections(t *testing.T) {
+	session := soundSession(t, "api")
+	session.Metadata.Description = "keep me"
+	data := edit(t, session, func(sections map[string]interface{}) {
+		sections["legacy"] = true
+		sections["statistics"] = "not an object"
+		sections["metadata"].(map[string]interface{})["color"] = "blue"
+	})
+
+	repaired, fixes := Repair(RepairInput{Name: "api", Data: data}, now)
+	assert.Equal(t, []string{"legacy", "metadata", "statistics"}, fields(fixes))
+	assert.Equal(t, "keep me", repaired.Metadata.Description)
+	assert.Equal(t, types.SessionStats{}, repaired.Stats)
+}
+
+func TestRepair_RestoresFromPreviousVersion(t *testing.T) {
+	session := soundSession(t, "api")
+	session.Claude.SessionID = "claude-1"
+	previous := marshal(t, session)
+
+	// a corrupt section comes back from the previous version
+	data := edit(t, session, func(sections map[string]interface{}) {
+		sections["claude"] = []interface{}{1, 2}
+	})
+	repaired, fixes := Repair(RepairInput{Name: "api", Data: data, Previous: previous}, now)
+	require.Len(t, fixes, 1)
+	assert.Equal(t, "claude", fixes[0].Field)
+	assert.Contains(t, fixes[0].Message, "restored from the last good version")
+	assert.Equal(t, "claude-1", repaired.Claude.SessionID)
+
+	// so does the whole file when it isn't JSON
+	repaired, fixes = Repair(RepairInput{Name: "api", Data: previous[:40], Previous: previous}, now)
+	require.Len(t, fixes, 1)
+	assert.Empty(t, fixes[0].Field)
+	assert.Equal(t, "claude-1", repaired.Claude.SessionID)
+}
+
+func TestRepair_RebuildsWithoutBackup(t *testing.T) {
+	projectPath := t.TempDir()
+	modTime := now.Add(-time.Hour)
+	repaired, fixes := Repair(RepairInput{Name: "api", Data: []byte(`{"version":`), ModTime: modTime, ProjectPath: projectPath}, now)
+
+	require.NotEmpty(t, fixes)
+	assert.Contains(t, fixes[0].Message, "no backup")
+	assert.Equal(t, "api", repaired.SessionID)
+	assert.Equal(t, projectPath, repaired.Project.Path)
+	assert.Equal(t, projectPath, repaired.Project.WorkingDirectory)
+	assert.True(t, modTime.Equal(repaired.Created))
+	assert.Equal(t, types.SessionStateActive, repaired.Lifecycle.State)
+	assert.Empty(t, Session(repaired, "api", "", now))
+}
+
+func TestRepair_FutureTimestamps(t *testing.T) {
+	session := soundSession(t, "api")
+	session.LastAccessed = now.Add(48 * time.Hour)
+
+	repaired, fixes := Repair(RepairInput{Name: "api", Data: marshal(t, session)}, now)
+	assert.Equal(t, []string{"lastAccessed"}, fields(fixes))
+	assert.True(t, session.LastModified.Equal(repaired.LastAccessed))
+}
+
+func TestRepairFile(t *testing.T) {
+	ctx := context.Background()
+	sessionsDir := filepath.Join(t.TempDir(), "sessions")
+	store := storage.NewWithSessionsDir(t.TempDir(), sessionsDir)
+	session := soundSession(t, "api")
+	session.Metadata.Description = "first"
+	require.NoError(t, store.SaveSession(ctx, session))
+	session.Metadata.Description = "second"
+	require.NoError(t, store.SaveSession(ctx, session))
+
+	file := filepath.Join(sessionsDir, "api.json")
+	damaged := []byte(`{"version": "1.0.0", "sessionId": "api"`)
+	require.NoError(t, os.WriteFile(file, damaged, 0o600))
+
+	_, err := RepairFile(ctx, store, "missing", "", now, false)
+	var agxErr *types.AGXError
+	require.ErrorAs(t, err, &agxErr)
+	assert.Equal(t, types.ErrCodeSessionNotFound, agxErr.Code)
+
+	// a dry run reports without writing
+	report, err := RepairFile(ctx, store, "api", "", now, true)
+	require.NoError(t, err)
+	assert.NotEmpty(t, report.Fixes)
+	assert.Empty(t, report.Backup)
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, damaged, data)
+
+	// the file is restored from the version the second save replaced
+	report, err = RepairFile(ctx, store, "api", "", now, false)
+	require.NoError(t, err)
+	require.Len(t, report.Fixes, 1)
+	assert.Empty(t, report.Remaining)
+	backup, err := os.ReadFile(report.Backup)
+	require.NoError(t, err)
+	assert.Equal(t, damaged, backup)
+
+	loaded, err := store.LoadSession(ctx, "api")
+	require.NoError(t, err)
+	assert.Equal(t, "first", loaded.Metadata.Description)
+	assert.Equal(t, session.Project.Path, loaded.Project.Path)
+
+	// the damaged file doesn't become the last good version
+	previous, err := os.ReadFile(store.PreviousVersionPath("api"))
+	require.NoError(t, err)
+	assert.True(t, json.Valid(previous))
+
+	// a repaired file needs nothing more
+	report, err = RepairFile(ctx, store, "api", "", now, false)
+	require.NoError(t, err)
+	assert.Empty(t, report.Fixes)
+	assert.Empty(t, report.Backup)
+}
@@ -0,0 +1,244 @@
+// Package validate checks session files for missing fields, inconsistent timestamps and broken Claude bindings
+package validate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/internal/transcript"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Severity tells problems that make a session unusable from ones worth a look
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// clockSkew is how far in the future a timestamp may be before it is reported
+const clockSkew = time.Hour
+
+// Problem is one finding in a session file
+type Problem struct {
+	Session  string          `json:"session"`
+	File     string          `json:"file"`
+	Severity Severity        `json:"severity"`
+	Code     types.ErrorCode `json:"code"`
+	Field    string          `json:"field,omitempty"`
+	Message  string          `json:"message"`
+}
+
+// Report is the result of validating every session file
+type Report struct {
+	Files    int       `json:"files"`
+	Errors   int       `json:"errors"`
+	Warnings int       `json:"warnings"`
+	Problems []Problem `json:"problems"`
+
+	// Duplicates are the Claude conversations bound to more than one session
+	Duplicates []DuplicateBinding `json:"duplicates,omitempty"`
+}
+
+// DuplicateBinding is a Claude conversation more than one session is bound to
+type DuplicateBinding struct {
+	ClaudeSessionID string `json:"claudeSessionId"`
+
+	// Sessions are ordered oldest first, so the first is most likely the conversation's original owner
+	Sessions []string `json:"sessions"`
+}
+
+// Store validates every session file in the store; homeDir locates Claude transcripts
+// Sessions sharing a Claude conversation are reported as well, as they would talk over each other
+func Store(ctx context.Context, store storage.Interface, homeDir string, now time.Time) (*Report, error) {
+	names, err := store.ListSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	type file struct {
+		name     string
+		problems []Problem
+	}
+	files := make([]file, 0, len(names))
+	var sessions []*types.Session
+	for _, name := range names {
+		session, err := store.LoadSession(ctx, name)
+		if err != nil {
+			code, message := types.ErrCodeSessionCorrupted, err.Error()
+			var agxErr *types.AGXError
+			if errors.As(err, &agxErr) {
+				if agxErr.Code != types.ErrCodeStorageCorrupted {
+					code = agxErr.Code
+				}
+				message = agxErr.Message
+				if agxErr.Cause != nil {
+					message += ": " + agxErr.Cause.Error()
+				}
+			}
+			files = append(files, file{name, []Problem{{Severity: SeverityError, Code: code, Message: message}}})
+			continue
+		}
+		files = append(files, file{name, Session(session, name, homeDir, now)})
+		sessions = append(sessions, session)
+	}
+
+	report := &Report{Problems: []Problem{}, Duplicates: DuplicateBindings(sessions)}
+	shared := make(map[string][]string)
+	for _, duplicate := range report.Duplicates {
+		for _, name := range duplicate.Sessions {
+			shared[name] = duplicate.Sessions
+		}
+	}
+
+	for _, f := range files {
+		report.Files++
+		problems := f.problems
+		if others := shared[f.name]; others != nil {
+			problems = append(problems, Problem{
+				Severity: SeverityError,
+				Code:     types.ErrCodeClaudeSessionInvalid,
+				Field:    "claude.sessionId",
+				Message: fmt.Sprintf("Claude conversation is shared with %s; run 'kam doctor' to fork or rebind",
+					strings.Join(without(others, f.name), ", ")),
+			})
+		}
+
+		for _, problem := range problems {
+			problem.Session = f.name
+			problem.File = filepath.Join(store.GetSessionsPath(), f.name+".json")
+			if problem.Severity == SeverityError {
+				report.Errors++
+			} else {
+				report.Warnings++
+			}
+			report.Problems = append(report.Problems, problem)
+		}
+	}
+
+	return report, nil
+}
+
+// DuplicateBindings finds the Claude conversations bound to more than one of sessions, by conversation ID
+func DuplicateBindings(sessions []*types.Session) []DuplicateBinding {
+	bound := make(map[string][]*types.Session)
+	for _, session := range sessions {
+		if session.Claude.SessionID != "" {
+			bound[session.Claude.SessionID] = append(bound[session.Claude.SessionID], session)
+		}
+	}
+
+	var duplicates []DuplicateBinding
+	for claudeSessionID, owners := range bound {
+		if len(owners) < 2 {
+			continue
+		}
+		sort.SliceStable(owners, func(i, j int) bool {
+			if !owners[i].Created.Equal(owners[j].Created) {
+				return owners[i].Created.Before(owners[j].Created)
+			}
+			return owners[i].SessionID < owners[j].SessionID
+		})
+		duplicate := DuplicateBinding{ClaudeSessionID: claudeSessionID}
+		for _, owner := range owners {
+			duplicate.Sessions = append(duplicate.Sessions, owner.SessionID)
+		}
+		duplicates = append(duplicates, duplicate)
+	}
+	sort.Slice(duplicates, func(i, j int) bool {
+		return duplicates[i].ClaudeSessionID < duplicates[j].ClaudeSessionID
+	})
+	return duplicates
+}
+
+// without returns names other than name
+func without(names []string, name string) []string {
+	var others []string
+	for _, other := range names {
+		if other != name {
+			others = append(others, other)
+		}
+	}
+	return others
+}
+
+// Session checks one session loaded from the file named name; an empty homeDir skips the Claude binding check
+func Session(session *types.Session, name, homeDir string, now time.Time) []Problem {
+	var problems []Problem
+	report := func(severity Severity, code types.ErrorCode, field, format string, args ...interface{}) {
+		problems = append(problems, Problem{Severity: severity, Code: code, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if session.Version == "" {
+		report(SeverityWarning, types.ErrCodeSessionInvalid, "version", "missing format version")
+	}
+	switch session.SessionID {
+	case "":
+		report(SeverityError, types.ErrCodeSessionInvalid, "sessionId", "missing session ID")
+	case name:
+	default:
+		report(SeverityError, types.ErrCodeSessionInvalid, "sessionId", "session ID '%s' doesn't match the file name", session.SessionID)
+	}
+
+	switch {
+	case session.Project.Path == "":
+		report(SeverityError, types.ErrCodeSessionInvalid, "project.path", "missing project path")
+	case !filepath.IsAbs(session.Project.Path):
+		report(SeverityError, types.ErrCodeSessionInvalid, "project.path", "project path '%s' is not absolute", session.Project.Path)
+	default:
+		if _, err := os.Stat(session.Project.Path); os.IsNotExist(err) {
+			report(SeverityWarning, types.ErrCodeProjectNotFound, "project.path", "project directory %s no longer exists", session.Project.Path)
+		}
+	}
+	if session.Project.WorkingDirectory == "" {
+		report(SeverityError, types.ErrCodeSessionInvalid, "project.workingDirectory", "missing working directory")
+	}
+
+	timestamps := []struct {
+		field string
+		value time.Time
+	}{
+		{"created", session.Created},
+		{"lastAccessed", session.LastAccessed},
+		{"lastModified", session.LastModified},
+	}
+	for _, timestamp := range timestamps {
+		switch {
+		case timestamp.value.IsZero():
+			report(SeverityError, types.ErrCodeSessionInvalid, timestamp.field, "missing timestamp")
+		case timestamp.value.After(now.Add(clockSkew)):
+			report(SeverityWarning, types.ErrCodeSessionInvalid, timestamp.field, "timestamp %s is in the future", timestamp.value.Format(time.RFC3339))
+		case !session.Created.IsZero() && timestamp.value.Before(session.Created):
+			report(SeverityWarning, types.ErrCodeSessionInvalid, timestamp.field, "timestamp %s is before the session was created", timestamp.value.Format(time.RFC3339))
+		}
+	}
+
+	if !session.Lifecycle.State.IsValid() {
+		report(SeverityError, types.ErrCodeSessionInvalid, "lifecycle.state", "unknown state '%s'", session.Lifecycle.State)
+	}
+	for i, change := range session.Lifecycle.StateHistory {
+		if !change.State.IsValid() {
+			report(SeverityWarning, types.ErrCodeSessionInvalid, fmt.Sprintf("lifecycle.stateHistory[%d]", i), "unknown state '%s'", change.State)
+		}
+	}
+
+	// A pending conversation has no transcript until Claude is sent its first prompt
+	if session.Claude.SessionID != "" && !session.Claude.Pending && homeDir != "" {
+		path := transcript.Path(session, homeDir)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			report(SeverityWarning, types.ErrCodeClaudeSessionNotFound, "claude.sessionId",
+				"Claude conversation %s not found (%s); the next resume starts a new one", session.Claude.SessionID, path)
+		}
+	}
+
+	return problems
+}
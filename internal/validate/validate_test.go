@@ -0,0 +1,148 @@
+package validate
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/internal/transcript"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+var now = time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+func validSession(t *testing.T, name string) *types.Session {
+	t.Helper()
+	projectDir := t.TempDir()
+	created := now.Add(-24 * time.Hour)
+	return &types.Session{
+		Version:      "1.0.0",
+		SessionID:    name,
+		Created:      created,
+		LastAccessed: created.Add(time.Hour),
+		LastModified: created.Add(time.Hour),
+		Project:      types.ProjectInfo{Path: projectDir, WorkingDirectory: projectDir},
+		Lifecycle: types.LifecycleInfo{
+			State:        types.SessionStateActive,
+			StateHistory: []types.StateChange{{State: types.SessionStateActive, Timestamp: created}},
+		},
+	}
+}
+
+func codes(problems []Problem) map[string]types.ErrorCode {
+	found := map[string]types.ErrorCode{}
+	for _, problem := range problems {
+		found[problem.Field] = problem.Code
+	}
+	return found
+}
+
+func TestSession_Valid(t *testing.T) {
+	assert.Empty(t, Session(validSession(t, "api"), "api", "", now))
+}
+
+func TestSession_Problems(t *testing.T) {
+	session := validSession(t, "api")
+	session.SessionID = "other"
+	session.Project.Path = "relative/path"
+	session.LastModified = time.Time{}
+	session.LastAccessed = now.Add(48 * time.Hour)
+	session.Lifecycle.State = "sleeping"
+
+	problems := Session(session, "api", "", now)
+	assert.Equal(t, map[string]types.ErrorCode{
+		"sessionId":       types.ErrCodeSessionInvalid,
+		"project.path":    types.ErrCodeSessionInvalid,
+		"lastModified":    types.ErrCodeSessionInvalid,
+		"lastAccessed":    types.ErrCodeSessionInvalid,
+		"lifecycle.state": types.ErrCodeSessionInvalid,
+	}, codes(problems))
+
+	for _, problem := range problems {
+		if problem.Field == "lastAccessed" {
+			assert.Equal(t, SeverityWarning, problem.Severity)
+		} else {
+			assert.Equal(t, SeverityError, problem.Severity, problem.Field)
+		}
+	}
+}
+
+func TestSession_ClaudeBinding(t *testing.T) {
+	homeDir := t.TempDir()
+	session := validSession(t, "api")
+	session.Claude.SessionID = "claude-1"
+
+	problems := Session(session, "api", homeDir, now)
+	require.Len(t, problems, 1)
+	assert.Equal(t, types.ErrCodeClaudeSessionNotFound, problems[0].Code)
+
+	path := transcript.Path(session, homeDir)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte("{}\n"), 0o600))
+	assert.Empty(t, Session(session, "api", homeDir, now))
+}
+
+func TestStore(t *testing.T) {
+	sessionsDir := filepath.Join(t.TempDir(), "sessions")
+	store := storage.NewWithSessionsDir(t.TempDir(), sessionsDir)
+	require.NoError(t, store.Initialize(context.Background()))
+
+	require.NoError(t, store.SaveSession(context.Background(), validSession(t, "good")))
+	missingState := validSession(t, "stateless")
+	missingState.Lifecycle.State = ""
+	require.NoError(t, store.SaveSession(context.Background(), missingState))
+	require.NoError(t, os.WriteFile(filepath.Join(sessionsDir, "broken.json"), []byte("{not json"), 0o600))
+
+	report, err := Store(context.Background(), store, "", now)
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Files)
+	assert.Equal(t, 2, report.Errors)
+	require.Len(t, report.Problems, 2)
+
+	assert.Equal(t, "broken", report.Problems[0].Session)
+	assert.Equal(t, types.ErrCodeSessionCorrupted, report.Problems[0].Code)
+	assert.Equal(t, filepath.Join(sessionsDir, "broken.json"), report.Problems[0].File)
+	assert.Equal(t, "stateless", report.Problems[1].Session)
+	assert.Equal(t, "lifecycle.state", report.Problems[1].Field)
+}
+
+func TestDuplicateBindings(t *testing.T) {
+	first := validSession(t, "api")
+	first.Claude.SessionID = "claude-1"
+	copied := validSession(t, "api-copy")
+	copied.Claude.SessionID = "claude-1"
+	copied.Created = first.Created.Add(time.Hour)
+	other := validSession(t, "web")
+	other.Claude.SessionID = "claude-2"
+	unbound := validSession(t, "fresh")
+
+	assert.Equal(t, []DuplicateBinding{{ClaudeSessionID: "claude-1", Sessions: []string{"api", "api-copy"}}},
+		DuplicateBindings([]*types.Session{copied, unbound, other, first}))
+	assert.Empty(t, DuplicateBindings([]*types.Session{first, other, unbound}))
+}
+
+func TestStore_DuplicateBindings(t *testing.T) {
+	sessionsDir := filepath.Join(t.TempDir(), "sessions")
+	store := storage.NewWithSessionsDir(t.TempDir(), sessionsDir)
+	for _, name := range []string{"api", "api-copy"} {
+		session := validSession(t, name)
+		session.Claude.SessionID = "claude-1"
+		require.NoError(t, store.SaveSession(context.Background(), session))
+	}
+
+	report, err := Store(context.Background(), store, "", now)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Errors)
+	require.Len(t, report.Problems, 2)
+	assert.Equal(t, "api", report.Problems[0].Session)
+	assert.Equal(t, types.ErrCodeClaudeSessionInvalid, report.Problems[0].Code)
+	assert.Contains(t, report.Problems[0].Message, "api-copy")
+	assert.Contains(t, report.Problems[1].Message, "shared with api;")
+	assert.Equal(t, []DuplicateBinding{{ClaudeSessionID: "claude-1", Sessions: []string{"api", "api-copy"}}}, report.Duplicates)
+}
@@ -0,0 +1,142 @@
+package viewer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Run shows the model full-screen until the user quits
+// The terminal is switched to raw mode on the alternate screen and restored afterwards
+func Run(m *Model, in, out *os.File) error {
+	if !term.IsTerminal(int(in.Fd())) || !term.IsTerminal(int(out.Fd())) {
+		return types.NewSessionError(types.ErrCodeInvalidInput, "the transcript viewer needs an interactive terminal", nil)
+	}
+
+	state, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return fmt.Errorf("failed to enter raw mode: %w", err)
+	}
+	defer term.Restore(int(in.Fd()), state)
+
+	fmt.Fprint(out, "\033[?1049h\033[?25l")
+	defer fmt.Fprint(out, "\033[?25h\033[?1049l")
+
+	reader := bufio.NewReader(in)
+	for {
+		// Size is re-read on every keypress so resizes apply on the next redraw
+		if width, height, err := term.GetSize(int(out.Fd())); err == nil {
+			m.SetSize(width, height)
+		}
+		fmt.Fprint(out, m.View())
+
+		key, err := ReadKey(reader)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if m.HandleKey(key) {
+			return nil
+		}
+	}
+}
+
+// ReadKey decodes one keypress, including the common ANSI escape sequences
+// The dashboard reads keys the same way
+func ReadKey(r *bufio.Reader) (Key, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return "", err
+	}
+
+	switch b {
+	case 0x1b:
+		// A lone escape has nothing queued behind it; sequences arrive in one read
+		if r.Buffered() == 0 {
+			return KeyEscape, nil
+		}
+		return readEscape(r)
+	case '\r', '\n':
+		return KeyEnter, nil
+	case 0x7f, 0x08:
+		return KeyBackspace, nil
+	case 0x03:
+		return KeyInterrupt, nil
+	case 0x04, 0x06: // ctrl-d, ctrl-f
+		return KeyPageDown, nil
+	case 0x15, 0x02: // ctrl-u, ctrl-b
+		return KeyPageUp, nil
+	}
+
+	if b < utf8.RuneSelf {
+		return Key(string(rune(b))), nil
+	}
+	if err := r.UnreadByte(); err != nil {
+		return "", err
+	}
+	ch, _, err := r.ReadRune()
+	if err != nil {
+		return "", err
+	}
+	return Key(string(ch)), nil
+}
+
+func readEscape(r *bufio.Reader) (Key, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return "", err
+	}
+	if b != '[' && b != 'O' {
+		return KeyEscape, nil
+	}
+
+	b, err = r.ReadByte()
+	if err != nil {
+		return "", err
+	}
+	switch b {
+	case 'A':
+		return KeyUp, nil
+	case 'B':
+		return KeyDown, nil
+	case 'H':
+		return KeyHome, nil
+	case 'F':
+		return KeyEnd, nil
+	}
+	if isFinalByte(b) {
+		return "", nil // other keys such as left and right are ignored
+	}
+
+	// Numbered sequences such as ESC [5~ end with a tilde, modified keys with a letter
+	sequence := []byte{b}
+	for b != '~' && !isFinalByte(b) && len(sequence) < 8 {
+		if b, err = r.ReadByte(); err != nil {
+			return "", err
+		}
+		sequence = append(sequence, b)
+	}
+	switch string(sequence) {
+	case "5~":
+		return KeyPageUp, nil
+	case "6~":
+		return KeyPageDown, nil
+	case "1~", "7~":
+		return KeyHome, nil
+	case "4~", "8~":
+		return KeyEnd, nil
+	}
+	return "", nil
+}
+
+func isFinalByte(b byte) bool {
+	return (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z')
+}
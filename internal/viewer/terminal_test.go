@@ -0,0 +1,61 @@
+package viewer
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadKey(t *testing.T) {
+	tests := map[string]Key{
+		"j":        "j",
+		"/":        "/",
+		"ñ":        "ñ",
+		"\r":       KeyEnter,
+		"\x7f":     KeyBackspace,
+		"\x03":     KeyInterrupt,
+		"\x1b":     KeyEscape,
+		"\x1b[A":   KeyUp,
+		"\x1b[B":   KeyDown,
+		"\x1bOA":   KeyUp,
+		"\x1b[5~":  KeyPageUp,
+		"\x1b[6~":  KeyPageDown,
+		"\x1b[H":   KeyHome,
+		"\x1b[4~":  KeyEnd,
+		"\x1b[C":   "",
+		"\x1b[1;5": "",
+	}
+
+	for input, expected := range tests {
+		key, err := ReadKey(bufio.NewReader(strings.NewReader(input)))
+		if expected == "" && err != nil {
+			continue // truncated sequences may end in EOF
+		}
+		require.NoError(t, err, "%q", input)
+		assert.Equal(t, expected, key, "%q", input)
+	}
+}
+
+func TestReadKey_SequenceDoesNotSwallowNextKey(t *testing.T) {
+	reader := bufio.NewReader(strings.NewReader("\x1b[Dq"))
+
+	key, err := ReadKey(reader)
+	require.NoError(t, err)
+	assert.Equal(t, Key(""), key)
+
+	key, err = ReadKey(reader)
+	require.NoError(t, err)
+	assert.Equal(t, Key("q"), key)
+}
+
+func TestRun_RequiresTerminal(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "not-a-tty")
+	require.NoError(t, err)
+	defer file.Close()
+
+	assert.Error(t, Run(New("empty", nil), file, file))
+}
@@ -0,0 +1,445 @@
+// Package viewer is a read-only terminal pager for Claude transcripts
+package viewer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bitomule/kamui/internal/transcript"
+)
+
+// Key is a decoded keypress: a named key such as KeyUp, or a single printable character
+type Key string
+
+const (
+	KeyUp        Key = "up"
+	KeyDown      Key = "down"
+	KeyPageUp    Key = "pgup"
+	KeyPageDown  Key = "pgdown"
+	KeyHome      Key = "home"
+	KeyEnd       Key = "end"
+	KeyEnter     Key = "enter"
+	KeyEscape    Key = "esc"
+	KeyBackspace Key = "backspace"
+	KeyInterrupt Key = "ctrl-c"
+)
+
+// Help is the key summary shown in the status bar
+const Help = "j/k move · enter fold · z/Z fold/unfold all · t/T tool call · / search · n/N match · q quit"
+
+// item is a foldable unit of the transcript: a message or a tool call
+type item struct {
+	header string
+	body   []string
+	tool   bool
+	folded bool
+}
+
+// line is one rendered row and the item it belongs to
+type line struct {
+	item   int
+	text   string
+	header bool
+}
+
+// Model is the viewer state; it does no I/O so it can be driven by tests
+type Model struct {
+	title  string
+	items  []*item
+	cursor int
+	offset int
+	width  int
+	height int
+
+	search    *regexp.Regexp
+	searching bool
+	input     string
+	message   string
+}
+
+// New builds a viewer over a conversation; tool calls start folded
+func New(title string, turns []*transcript.Turn) *Model {
+	m := &Model{title: title, width: 80, height: 24}
+
+	for _, turn := range turns {
+		header := transcript.RoleLabel(turn.Role)
+		if !turn.Timestamp.IsZero() {
+			header += " · " + turn.Timestamp.Local().Format("2006-01-02 15:04")
+		}
+		if turn.Text != "" || len(turn.Tools) == 0 {
+			m.items = append(m.items, &item{header: header, body: strings.Split(turn.Text, "\n")})
+		} else {
+			m.items = append(m.items, &item{header: header})
+		}
+
+		for _, call := range turn.Tools {
+			header := "Tool: " + call.Name
+			if call.IsError {
+				header += " (error)"
+			}
+			body := append([]string{"Input:"}, strings.Split(call.Input, "\n")...)
+			if call.Result != "" {
+				body = append(body, "", "Result:")
+				body = append(body, strings.Split(strings.TrimRight(call.Result, "\n"), "\n")...)
+			}
+			m.items = append(m.items, &item{header: header, body: body, tool: true, folded: true})
+		}
+	}
+
+	return m
+}
+
+// SetSize sets the terminal size the view is rendered for
+func (m *Model) SetSize(width, height int) {
+	if width < 20 {
+		width = 20
+	}
+	if height < 3 {
+		height = 3
+	}
+	m.width, m.height = width, height
+	m.scrollToCursor()
+}
+
+// HandleKey applies a keypress and reports whether the viewer should quit
+func (m *Model) HandleKey(key Key) bool {
+	if m.searching {
+		m.handleSearchKey(key)
+		return false
+	}
+
+	m.message = ""
+	switch key {
+	case "q", KeyInterrupt:
+		return true
+	case "j", KeyDown:
+		m.moveCursor(m.cursor + 1)
+	case "k", KeyUp:
+		m.moveCursor(m.cursor - 1)
+	case "g", KeyHome:
+		m.moveCursor(0)
+	case "G", KeyEnd:
+		m.moveCursor(len(m.items) - 1)
+		m.scrollToEnd()
+	case KeyPageDown, " ":
+		m.page(1)
+	case KeyPageUp, "b":
+		m.page(-1)
+	case KeyEnter, "o":
+		if m.cursor < len(m.items) {
+			m.items[m.cursor].folded = !m.items[m.cursor].folded
+			m.scrollToCursor()
+		}
+	case "z":
+		m.foldAll(true)
+	case "Z":
+		m.foldAll(false)
+	case "t":
+		m.jumpToTool(1)
+	case "T":
+		m.jumpToTool(-1)
+	case "/":
+		m.searching, m.input = true, ""
+	case "n":
+		m.nextMatch(1)
+	case "N":
+		m.nextMatch(-1)
+	}
+	return false
+}
+
+// View renders the visible page, title and status bar included
+func (m *Model) View() string {
+	var b strings.Builder
+	b.WriteString("\033[H")
+
+	title := fmt.Sprintf(" %s (read-only) ", m.title)
+	fmt.Fprintf(&b, "\033[7m%s\033[0m\033[K\r\n", pad(truncate(title, m.width), m.width))
+
+	lines := m.lines()
+	rows := m.pageSize()
+	for row := 0; row < rows; row++ {
+		index := m.offset + row
+		if index >= len(lines) {
+			b.WriteString("\033[K\r\n")
+			continue
+		}
+
+		l := lines[index]
+		text := m.highlight(truncate(l.text, m.width))
+		switch {
+		case l.header && l.item == m.cursor:
+			fmt.Fprintf(&b, "\033[1;7m%s\033[0m", text)
+		case l.header && m.items[l.item].tool:
+			fmt.Fprintf(&b, "\033[33m%s\033[0m", text)
+		case l.header:
+			fmt.Fprintf(&b, "\033[1;96m%s\033[0m", text)
+		default:
+			b.WriteString(text)
+		}
+		b.WriteString("\033[K\r\n")
+	}
+
+	status := Help
+	switch {
+	case m.searching:
+		status = "/" + m.input
+	case m.message != "":
+		status = m.message
+	}
+	fmt.Fprintf(&b, "\033[7m%s\033[0m\033[K", pad(truncate(" "+status, m.width), m.width))
+	return b.String()
+}
+
+// lines renders all items at the current width
+func (m *Model) lines() []line {
+	var lines []line
+	for i, it := range m.items {
+		marker := "▾ "
+		if it.folded {
+			marker = "▸ "
+		}
+		if len(it.body) == 0 {
+			marker = "  "
+		}
+		indent := ""
+		if it.tool {
+			indent = "  "
+		}
+		lines = append(lines, line{item: i, text: indent + marker + it.header, header: true})
+
+		if it.folded {
+			continue
+		}
+		bodyIndent := indent + "  "
+		for _, text := range it.body {
+			for _, wrapped := range wrap(text, m.width-len(bodyIndent)) {
+				lines = append(lines, line{item: i, text: bodyIndent + wrapped})
+			}
+		}
+		lines = append(lines, line{item: i})
+	}
+	return lines
+}
+
+func (m *Model) pageSize() int {
+	return m.height - 2
+}
+
+// moveCursor selects an item and scrolls it into view
+func (m *Model) moveCursor(index int) {
+	if index < 0 || index >= len(m.items) {
+		return
+	}
+	m.cursor = index
+	m.scrollToCursor()
+}
+
+// scrollToCursor keeps the selected item's header on screen and the offset in range
+func (m *Model) scrollToCursor() {
+	lines := m.lines()
+	header := 0
+	for i, l := range lines {
+		if l.header && l.item == m.cursor {
+			header = i
+			break
+		}
+	}
+
+	if header < m.offset {
+		m.offset = header
+	}
+	if header >= m.offset+m.pageSize() {
+		m.offset = header - m.pageSize() + 1
+	}
+	if maxOffset := len(lines) - m.pageSize(); m.offset > maxOffset {
+		m.offset = maxOffset
+	}
+	if m.offset < 0 {
+		m.offset = 0
+	}
+}
+
+// scrollToEnd shows the last page, unless that would hide the selected item's header
+func (m *Model) scrollToEnd() {
+	lines := m.lines()
+	end := len(lines) - m.pageSize()
+	for i, l := range lines {
+		if l.header && l.item == m.cursor && i < end {
+			end = i
+			break
+		}
+	}
+	if end > m.offset {
+		m.offset = end
+	}
+}
+
+// page scrolls a screen up or down and selects the first item header on the new page
+func (m *Model) page(direction int) {
+	lines := m.lines()
+	m.offset += direction * m.pageSize()
+	if maxOffset := len(lines) - m.pageSize(); m.offset > maxOffset {
+		m.offset = maxOffset
+	}
+	if m.offset < 0 {
+		m.offset = 0
+	}
+
+	for i := m.offset; i < len(lines) && i < m.offset+m.pageSize(); i++ {
+		if lines[i].header {
+			m.cursor = lines[i].item
+			return
+		}
+	}
+	if m.offset < len(lines) {
+		m.cursor = lines[m.offset].item
+	}
+}
+
+func (m *Model) foldAll(folded bool) {
+	for _, it := range m.items {
+		if it.tool || !folded {
+			it.folded = folded
+		}
+	}
+	m.scrollToCursor()
+}
+
+// jumpToTool selects the next (or previous) tool call from the cursor
+func (m *Model) jumpToTool(direction int) {
+	for i := m.cursor + direction; i >= 0 && i < len(m.items); i += direction {
+		if m.items[i].tool {
+			m.moveCursor(i)
+			return
+		}
+	}
+	m.message = "No more tool calls"
+}
+
+func (m *Model) handleSearchKey(key Key) {
+	switch key {
+	case KeyEscape, KeyInterrupt:
+		m.searching = false
+	case KeyEnter:
+		m.searching = false
+		if m.input == "" {
+			m.search = nil
+			return
+		}
+		pattern, err := regexp.Compile("(?i)" + m.input)
+		if err != nil {
+			pattern = regexp.MustCompile("(?i)" + regexp.QuoteMeta(m.input))
+		}
+		m.search = pattern
+		if m.matches(m.cursor) {
+			m.reveal(m.cursor)
+			return
+		}
+		m.nextMatch(1)
+	case KeyBackspace:
+		if m.input != "" {
+			_, size := utf8.DecodeLastRuneInString(m.input)
+			m.input = m.input[:len(m.input)-size]
+		}
+	default:
+		if utf8.RuneCountInString(string(key)) == 1 {
+			m.input += string(key)
+		}
+	}
+}
+
+// nextMatch selects the next (or previous) item matching the search, wrapping around
+func (m *Model) nextMatch(direction int) {
+	if m.search == nil {
+		m.message = "No search (press /)"
+		return
+	}
+
+	for step := 1; step <= len(m.items); step++ {
+		index := ((m.cursor+direction*step)%len(m.items) + len(m.items)) % len(m.items)
+		if m.matches(index) {
+			m.reveal(index)
+			return
+		}
+	}
+	m.message = fmt.Sprintf("Pattern not found: %s", m.input)
+}
+
+func (m *Model) matches(index int) bool {
+	if m.search == nil || index >= len(m.items) {
+		return false
+	}
+	it := m.items[index]
+	if m.search.MatchString(it.header) {
+		return true
+	}
+	for _, text := range it.body {
+		if m.search.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// reveal unfolds and selects an item, scrolling its first match into view
+func (m *Model) reveal(index int) {
+	m.items[index].folded = false
+	m.moveCursor(index)
+
+	lines := m.lines()
+	for i, l := range lines {
+		if l.item == index && m.search.MatchString(l.text) {
+			if i >= m.offset+m.pageSize() {
+				m.offset = i - m.pageSize()/2
+			}
+			return
+		}
+	}
+}
+
+// highlight marks search matches in reverse video
+func (m *Model) highlight(text string) string {
+	if m.search == nil || text == "" {
+		return text
+	}
+	return m.search.ReplaceAllStringFunc(text, func(match string) string {
+		return "\033[7m" + match + "\033[27m"
+	})
+}
+
+// wrap splits text into rows of at most width runes
+func wrap(text string, width int) []string {
+	text = strings.ReplaceAll(text, "\t", "    ")
+	if width < 1 {
+		width = 1
+	}
+
+	runes := []rune(text)
+	if len(runes) <= width {
+		return []string{text}
+	}
+
+	var rows []string
+	for len(runes) > width {
+		rows = append(rows, string(runes[:width]))
+		runes = runes[width:]
+	}
+	return append(rows, string(runes))
+}
+
+func truncate(text string, width int) string {
+	if utf8.RuneCountInString(text) <= width {
+		return text
+	}
+	return string([]rune(text)[:width])
+}
+
+func pad(text string, width int) string {
+	if n := utf8.RuneCountInString(text); n < width {
+		return text + strings.Repeat(" ", width-n)
+	}
+	return text
+}
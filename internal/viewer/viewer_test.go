@@ -0,0 +1,150 @@
+package viewer
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/transcript"
+)
+
+func newFixtureModel(t *testing.T) *Model {
+	t.Helper()
+	records, err := transcript.ReadFile(context.Background(), filepath.Join("..", "transcript", "testdata", "conversation.jsonl"))
+	require.NoError(t, err)
+
+	m := New("login-fix", transcript.Turns(records))
+	m.SetSize(80, 40)
+	return m
+}
+
+func keys(m *Model, pressed ...Key) {
+	for _, key := range pressed {
+		m.HandleKey(key)
+	}
+}
+
+func TestNew_FoldsToolCalls(t *testing.T) {
+	m := newFixtureModel(t)
+
+	var tools, messages int
+	for _, it := range m.items {
+		if it.tool {
+			tools++
+			assert.True(t, it.folded, it.header)
+		} else {
+			messages++
+			assert.False(t, it.folded, it.header)
+		}
+	}
+	assert.Equal(t, 6, messages)
+	assert.Equal(t, 3, tools)
+
+	view := m.View()
+	assert.Contains(t, view, "Why does login fail?")
+	assert.Contains(t, view, "▸ Tool: Read")
+	assert.NotContains(t, view, "package main")
+}
+
+func TestHandleKey_ToggleFold(t *testing.T) {
+	m := newFixtureModel(t)
+
+	keys(m, "t")
+	assert.Equal(t, "Tool: Read", m.items[m.cursor].header)
+
+	keys(m, KeyEnter)
+	assert.Contains(t, m.View(), "package main")
+
+	keys(m, KeyEnter)
+	assert.NotContains(t, m.View(), "package main")
+}
+
+func TestHandleKey_JumpToToolCalls(t *testing.T) {
+	m := newFixtureModel(t)
+
+	var visited []string
+	for i := 0; i < 4; i++ {
+		keys(m, "t")
+		visited = append(visited, m.items[m.cursor].header)
+	}
+	assert.Equal(t, []string{"Tool: Read", "Tool: Edit", "Tool: Bash", "Tool: Bash"}, visited)
+	assert.Equal(t, "No more tool calls", m.message)
+
+	keys(m, "T")
+	assert.Equal(t, "Tool: Edit", m.items[m.cursor].header)
+}
+
+func TestHandleKey_FoldAll(t *testing.T) {
+	m := newFixtureModel(t)
+
+	keys(m, "Z")
+	for _, it := range m.items {
+		assert.False(t, it.folded)
+	}
+
+	keys(m, "z")
+	for _, it := range m.items {
+		assert.Equal(t, it.tool, it.folded, it.header)
+	}
+}
+
+func TestHandleKey_Search(t *testing.T) {
+	m := newFixtureModel(t)
+
+	keys(m, "/", "g", "o", " ", "t", "e", "s", "t", KeyEnter)
+	require.NotNil(t, m.search)
+
+	// the match is inside the folded Bash call, which is unfolded to reveal it
+	selected := m.items[m.cursor]
+	assert.Equal(t, "Tool: Bash", selected.header)
+	assert.False(t, selected.folded)
+	assert.Contains(t, m.View(), "\033[7mgo test\033[27m")
+
+	keys(m, "n")
+	assert.Equal(t, "Tool: Bash", m.items[m.cursor].header, "search wraps around to the only match")
+}
+
+func TestHandleKey_SearchNotFound(t *testing.T) {
+	m := newFixtureModel(t)
+
+	keys(m, "/", "x", "y", "z", KeyEnter)
+	assert.Equal(t, 0, m.cursor)
+	assert.Equal(t, "Pattern not found: xyz", m.message)
+}
+
+func TestHandleKey_SearchEscapeCancels(t *testing.T) {
+	m := newFixtureModel(t)
+
+	keys(m, "/", "q", KeyEscape)
+	assert.False(t, m.searching)
+	assert.Nil(t, m.search)
+	assert.True(t, m.HandleKey("q"))
+}
+
+func TestScrolling_KeepsCursorVisible(t *testing.T) {
+	m := newFixtureModel(t)
+	m.SetSize(80, 6)
+
+	keys(m, "Z", "G")
+	lines := m.lines()
+	assert.Equal(t, len(m.items)-1, m.cursor)
+	assert.LessOrEqual(t, m.offset, len(lines)-m.pageSize())
+
+	keys(m, "g")
+	assert.Equal(t, 0, m.offset)
+	assert.Equal(t, 0, m.cursor)
+
+	keys(m, KeyPageDown)
+	assert.Greater(t, m.offset, 0)
+	assert.Equal(t, m.pageSize(), strings.Count(m.View(), "\r\n")-1)
+}
+
+func TestWrap(t *testing.T) {
+	assert.Equal(t, []string{"abc"}, wrap("abc", 10))
+	assert.Equal(t, []string{"abcd", "efgh", "ij"}, wrap("abcdefghij", 4))
+	assert.Equal(t, []string{"ñañ", "a"}, wrap("ñaña", 3))
+}
@@ -0,0 +1,148 @@
+// Package vscode writes Kamui tasks and terminal profiles into a project's .vscode folder
+package vscode
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Task is a VS Code task definition (the subset Kamui writes)
+type Task struct {
+	Label        string                 `json:"label"`
+	Type         string                 `json:"type"`
+	Command      string                 `json:"command"`
+	Args         []string               `json:"args,omitempty"`
+	Presentation map[string]interface{} `json:"presentation,omitempty"`
+	RunOptions   map[string]interface{} `json:"runOptions,omitempty"`
+	Problem      []string               `json:"problemMatcher"`
+}
+
+// ResumeTask returns the task that resumes a Kamui session in a dedicated terminal
+func ResumeTask(sessionName string, runOnOpen bool) Task {
+	task := Task{
+		Label:   TaskLabel(sessionName),
+		Type:    "shell",
+		Command: "kam",
+		Args:    []string{sessionName},
+		Presentation: map[string]interface{}{
+			"reveal": "always",
+			"panel":  "dedicated",
+			"focus":  true,
+		},
+		Problem: []string{},
+	}
+	if runOnOpen {
+		task.RunOptions = map[string]interface{}{"runOn": "folderOpen"}
+	}
+	return task
+}
+
+// TaskLabel returns the label used for a session's resume task
+func TaskLabel(sessionName string) string {
+	return fmt.Sprintf("Kamui: resume %s", sessionName)
+}
+
+// UpsertTask adds or replaces a task (matched by label) in .vscode/tasks.json
+func UpsertTask(projectPath string, task Task) (string, error) {
+	path := filepath.Join(projectPath, ".vscode", "tasks.json")
+
+	doc, err := readJSON(path)
+	if err != nil {
+		return path, err
+	}
+	if _, ok := doc["version"]; !ok {
+		doc["version"] = "2.0.0"
+	}
+
+	encoded, err := toGeneric(task)
+	if err != nil {
+		return path, err
+	}
+
+	existing, _ := doc["tasks"].([]interface{})
+	tasks := make([]interface{}, 0, len(existing)+1)
+	replaced := false
+	for _, entry := range existing {
+		if m, ok := entry.(map[string]interface{}); ok && m["label"] == task.Label {
+			tasks = append(tasks, encoded)
+			replaced = true
+			continue
+		}
+		tasks = append(tasks, entry)
+	}
+	if !replaced {
+		tasks = append(tasks, encoded)
+	}
+	doc["tasks"] = tasks
+
+	return path, writeJSON(path, doc)
+}
+
+// UpsertTerminalProfile adds a terminal profile running `kam <session>` to .vscode/settings.json
+// The profile is registered for both macOS and Linux terminals
+func UpsertTerminalProfile(projectPath, sessionName string) (string, error) {
+	path := filepath.Join(projectPath, ".vscode", "settings.json")
+
+	doc, err := readJSON(path)
+	if err != nil {
+		return path, err
+	}
+
+	profile := map[string]interface{}{
+		"path": "kam",
+		"args": []interface{}{sessionName},
+		"icon": "hubot",
+	}
+	for _, key := range []string{"terminal.integrated.profiles.osx", "terminal.integrated.profiles.linux"} {
+		profiles, _ := doc[key].(map[string]interface{})
+		if profiles == nil {
+			profiles = make(map[string]interface{})
+		}
+		profiles[fmt.Sprintf("Kamui: %s", sessionName)] = profile
+		doc[key] = profiles
+	}
+
+	return path, writeJSON(path, doc)
+}
+
+// readJSON loads a JSON object, treating a missing file as empty
+func readJSON(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return make(map[string]interface{}), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	doc := make(map[string]interface{})
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s is not plain JSON (comments or trailing commas?), update it manually: %w", path, err)
+	}
+	return doc, nil
+}
+
+// writeJSON writes doc as indented JSON, creating the .vscode directory if needed
+func writeJSON(path string, doc map[string]interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// toGeneric converts a value into its generic JSON representation
+func toGeneric(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic map[string]interface{}
+	return generic, json.Unmarshal(data, &generic)
+}
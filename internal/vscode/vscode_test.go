@@ -0,0 +1,85 @@
+package vscode
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readDoc(t *testing.T, path string) map[string]interface{} {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	doc := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(data, &doc))
+	return doc
+}
+
+func TestUpsertTask_CreatesAndReplaces(t *testing.T) {
+	project := t.TempDir()
+
+	path, err := UpsertTask(project, ResumeTask("api", false))
+	require.NoError(t, err)
+
+	doc := readDoc(t, path)
+	assert.Equal(t, "2.0.0", doc["version"])
+	tasks := doc["tasks"].([]interface{})
+	require.Len(t, tasks, 1)
+	assert.Nil(t, tasks[0].(map[string]interface{})["runOptions"])
+
+	// Re-running replaces the existing task instead of duplicating it
+	_, err = UpsertTask(project, ResumeTask("api", true))
+	require.NoError(t, err)
+
+	tasks = readDoc(t, path)["tasks"].([]interface{})
+	require.Len(t, tasks, 1)
+	task := tasks[0].(map[string]interface{})
+	assert.Equal(t, "Kamui: resume api", task["label"])
+	assert.Equal(t, map[string]interface{}{"runOn": "folderOpen"}, task["runOptions"])
+}
+
+func TestUpsertTask_PreservesUserTasks(t *testing.T) {
+	project := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(project, ".vscode"), 0o755))
+	existing := `{"version": "2.0.0", "tasks": [{"label": "build", "type": "shell", "command": "make"}]}`
+	require.NoError(t, os.WriteFile(filepath.Join(project, ".vscode", "tasks.json"), []byte(existing), 0o644))
+
+	path, err := UpsertTask(project, ResumeTask("api", false))
+	require.NoError(t, err)
+
+	tasks := readDoc(t, path)["tasks"].([]interface{})
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "build", tasks[0].(map[string]interface{})["label"])
+}
+
+func TestUpsertTask_RejectsJSONC(t *testing.T) {
+	project := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(project, ".vscode"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(project, ".vscode", "tasks.json"), []byte("// comment\n{}"), 0o644))
+
+	_, err := UpsertTask(project, ResumeTask("api", false))
+	require.Error(t, err)
+}
+
+func TestUpsertTerminalProfile(t *testing.T) {
+	project := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(project, ".vscode"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(project, ".vscode", "settings.json"), []byte(`{"editor.tabSize": 2}`), 0o644))
+
+	path, err := UpsertTerminalProfile(project, "api")
+	require.NoError(t, err)
+
+	doc := readDoc(t, path)
+	assert.InDelta(t, 2, doc["editor.tabSize"], 0)
+
+	for _, key := range []string{"terminal.integrated.profiles.osx", "terminal.integrated.profiles.linux"} {
+		profiles := doc[key].(map[string]interface{})
+		profile := profiles["Kamui: api"].(map[string]interface{})
+		assert.Equal(t, "kam", profile["path"])
+		assert.Equal(t, []interface{}{"api"}, profile["args"])
+	}
+}
@@ -0,0 +1,258 @@
+// Package kamui is the supported Go API for working with Kamui sessions
+//
+// It lets editor plugins, bots and other tools create, list and resolve sessions
+// programmatically instead of shelling out to the kam CLI. Session data is returned
+// using the types defined in github.com/bitomule/kamui/pkg/types. Methods that read or
+// write sessions take a context; canceling it stops file operations and Claude runs.
+package kamui
+
+import (
+	"context"
+	"os"
+
+	"github.com/bitomule/kamui/internal/claude"
+	"github.com/bitomule/kamui/internal/index"
+	"github.com/bitomule/kamui/internal/project"
+	"github.com/bitomule/kamui/internal/session"
+	"github.com/bitomule/kamui/internal/storage"
+	_ "github.com/bitomule/kamui/internal/storage/remote" // registers the remote backend
+	"github.com/bitomule/kamui/internal/timesheet"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// Options configures a Client
+type Options struct {
+	// ProjectPath is the project the client operates on (defaults to the project containing the current directory)
+	ProjectPath string
+
+	// ProjectDetection selects how the project is found when ProjectPath is empty: auto, cwd, git or marker
+	ProjectDetection string
+
+	// ProjectMarkers are the marker files used by the marker detection strategy
+	ProjectMarkers []string
+
+	// CaseFolding makes session names that differ only in case refer to the same session
+	CaseFolding bool
+
+	// StorageBackend selects where sessions are kept: json-files (default), memory or remote
+	StorageBackend string
+
+	// ServerURL and ServerToken locate the kam serve instance used by the remote backend
+	ServerURL   string
+	ServerToken string
+
+	// SessionsDir overrides where json-files stores sessions (defaults to ~/.claude/kamui-sessions)
+	SessionsDir string
+
+	// DurableWrites fsyncs session files and their directory on every save
+	DurableWrites bool
+
+	// IndexPath is the global session index kept up to date as sessions are saved and deleted,
+	// usually ~/.claude/kamui-index.json; empty keeps no index
+	IndexPath string
+
+	// Offline never runs claude: sessions can be listed, edited and exported, but not run
+	// Clients are offline anyway when claude isn't in PATH
+	Offline bool
+}
+
+// Client provides access to Kamui sessions for a project
+type Client struct {
+	manager *session.Manager
+}
+
+// Resolution describes how to resume a session's Claude conversation
+type Resolution struct {
+	// Session is the stored session data
+	Session *types.Session
+
+	// WorkingDirectory is where Claude must be started
+	WorkingDirectory string
+
+	// Command is the full Claude command line, e.g. ["claude", "--resume", "<id>"]
+	Command []string
+
+	// Env contains the KAMUI_* variables Kamui sets for the session
+	Env []string
+
+	// HasClaudeSession reports whether the bound Claude conversation still exists
+	HasClaudeSession bool
+}
+
+// HeadlessResult is the outcome of a non-interactive Claude run
+type HeadlessResult = claude.HeadlessResult
+
+// ListOptions selects and orders the sessions ListSummaries returns
+type ListOptions = session.ListOptions
+
+// Summary is a session as listings show it
+type Summary = session.Summary
+
+// SortKey orders the sessions ListSummaries returns
+type SortKey = session.SortKey
+
+// Sort keys for ListOptions
+const (
+	SortLastAccessed = session.SortLastAccessed
+	SortCreated      = session.SortCreated
+	SortName         = session.SortName
+)
+
+// Binding filters sessions by whether they are bound to a Claude conversation
+type Binding = session.Binding
+
+// Binding filters for ListOptions
+const (
+	BindingAny     = session.BindingAny
+	BindingBound   = session.BindingBound
+	BindingUnbound = session.BindingUnbound
+)
+
+// Open creates a client for the configured project
+func Open(opts Options) (*Client, error) {
+	projectPath := opts.ProjectPath
+	if projectPath == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, types.NewStorageError(
+				types.ErrCodeProjectNotFound,
+				"failed to get current working directory",
+				err,
+			)
+		}
+
+		detector, err := project.NewDetector(opts.ProjectDetection, opts.ProjectMarkers)
+		if err != nil {
+			return nil, err
+		}
+		if projectPath, err = detector.Root(cwd); err != nil {
+			return nil, types.NewStorageError(
+				types.ErrCodeProjectInvalid,
+				"failed to detect project root",
+				err,
+			)
+		}
+	}
+
+	claudeClient := claude.NewOrOffline()
+	if opts.Offline {
+		claudeClient = claude.NewOffline("the client is offline")
+	}
+
+	storageImpl, err := storage.Open(opts.StorageBackend, storage.Options{
+		ProjectPath:   projectPath,
+		SessionsDir:   opts.SessionsDir,
+		DurableWrites: opts.DurableWrites,
+		URL:           opts.ServerURL,
+		Token:         opts.ServerToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newWithDependencies(projectPath, storageImpl, claudeClient)
+	if err != nil {
+		return nil, err
+	}
+	client.manager.SetCaseFolding(opts.CaseFolding)
+	client.manager.SetManualLocks(session.NewManualLocks())
+	client.manager.SetTimesheet(timesheet.NewStore())
+	if opts.IndexPath != "" {
+		client.manager.SetIndex(index.NewStoreWithPath(opts.IndexPath), index.DefaultSyncInterval)
+	}
+	return client, nil
+}
+
+func newWithDependencies(projectPath string, storageImpl storage.Interface, claudeClient claude.ClientInterface) (*Client, error) {
+	manager, err := session.NewWithDependencies(projectPath, storageImpl, claudeClient)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{manager: manager}, nil
+}
+
+// ProjectPath returns the absolute project path the client operates on
+func (c *Client) ProjectPath() string {
+	return c.manager.GetProjectPath()
+}
+
+// ListSessions returns the names of all stored sessions
+func (c *Client) ListSessions(ctx context.Context) ([]string, error) {
+	return c.manager.ListSessions(ctx)
+}
+
+// ListSummaries returns summaries of the project's sessions (every project's with
+// AllProjects), filtered and sorted as opts asks
+// It fails with ErrCodeInvalidInput for an unknown sort key
+func (c *Client) ListSummaries(ctx context.Context, opts ListOptions) ([]Summary, error) {
+	return c.manager.ListSummaries(ctx, opts)
+}
+
+// ListIndexed returns summaries of every project's sessions from the global index, filtered and
+// sorted as opts asks; see Options.IndexPath
+// Without an index it reads every session, as ListSummaries does with AllProjects
+func (c *Client) ListIndexed(ctx context.Context, opts ListOptions) ([]Summary, error) {
+	return c.manager.ListIndexed(ctx, opts)
+}
+
+// GetSession loads a session by name
+func (c *Client) GetSession(ctx context.Context, name string) (*types.Session, error) {
+	return c.manager.GetSession(ctx, name)
+}
+
+// CreateSession creates a session without launching Claude
+// It fails with ErrCodeSessionExists if the name is already taken
+func (c *Client) CreateSession(ctx context.Context, name, description string, tags []string) (*types.Session, error) {
+	return c.manager.CreateSession(ctx, name, description, tags)
+}
+
+// CompleteSession marks a session as completed
+func (c *Client) CompleteSession(ctx context.Context, name string) error {
+	return c.manager.CompleteSession(ctx, name)
+}
+
+// DeleteSession removes a session's metadata
+// It fails with ErrCodeSessionProtected if the session is protected and ErrCodeSessionHeld if it
+// was locked with kam lock
+func (c *Client) DeleteSession(ctx context.Context, name string) error {
+	return c.manager.DeleteSession(ctx, name, false)
+}
+
+// AddNote attaches a note to a session
+func (c *Client) AddNote(ctx context.Context, name, source, text string) error {
+	return c.manager.AddNote(ctx, name, source, text)
+}
+
+// RunHeadless runs a prompt non-interactively against a session
+func (c *Client) RunHeadless(ctx context.Context, name, prompt string) (*HeadlessResult, error) {
+	return c.manager.RunHeadless(ctx, name, prompt)
+}
+
+// Resolve returns everything needed to resume a session outside the CLI
+func (c *Client) Resolve(ctx context.Context, name string) (*Resolution, error) {
+	sessionData, err := c.manager.GetSession(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	hasClaudeSession, err := c.manager.HasClaudeSession(ctx, sessionData)
+	if err != nil {
+		return nil, err
+	}
+
+	command := []string{"claude"}
+	switch {
+	case hasClaudeSession:
+		command = append(command, "--resume", sessionData.Claude.SessionID)
+	case sessionData.Claude.Pending:
+		command = append(command, "--session-id", sessionData.Claude.SessionID)
+	}
+
+	return &Resolution{
+		Session:          sessionData,
+		WorkingDirectory: sessionData.Project.WorkingDirectory,
+		Command:          command,
+		Env:              session.Environment(sessionData),
+		HasClaudeSession: hasClaudeSession,
+	}, nil
+}
@@ -0,0 +1,180 @@
+package kamui
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitomule/kamui/internal/claude"
+	"github.com/bitomule/kamui/internal/storage"
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// fakeClaude is a minimal claude.ClientInterface that knows a fixed set of sessions
+type fakeClaude struct {
+	sessions map[string]bool
+}
+
+func (f *fakeClaude) HasSession(_ context.Context, sessionID, _ string) (bool, error) {
+	return f.sessions[sessionID], nil
+}
+
+func (f *fakeClaude) StartSession(_ context.Context, _ string) (string, error) { return "", nil }
+
+func (f *fakeClaude) ResumeSession(_ context.Context, _, _ string) error { return nil }
+
+func (f *fakeClaude) ListSessions(_ context.Context) ([]string, error) { return nil, nil }
+
+func (f *fakeClaude) GetSessionInfo(_ context.Context, sessionID, _ string) (*claude.SessionInfo, error) {
+	return &claude.SessionInfo{SessionID: sessionID}, nil
+}
+
+func (f *fakeClaude) TerminateSession(_ context.Context, _, _ string) error { return nil }
+
+func (f *fakeClaude) DiscoverExistingSessions(_ context.Context, _ string) ([]string, error) {
+	return nil, nil
+}
+
+func (f *fakeClaude) DiscoverNewestSession(_ context.Context, _ string) (string, error) {
+	return "", nil
+}
+
+func (f *fakeClaude) LaunchClaudeInteractively(_ context.Context, _, _ string, _ ...string) error {
+	return nil
+}
+
+func (f *fakeClaude) Version(_ context.Context) claude.Version { return claude.Version{} }
+
+func (f *fakeClaude) RunHeadless(_ context.Context, _, sessionID, prompt string, _ ...string) (*claude.HeadlessResult, error) {
+	if sessionID == "" {
+		sessionID = "claude-abc"
+	}
+	return &claude.HeadlessResult{SessionID: sessionID, Result: "echo: " + prompt}, nil
+}
+
+func newTestClient(t *testing.T, claudeSessions ...string) *Client {
+	t.Helper()
+	tempDir := t.TempDir()
+
+	fake := &fakeClaude{sessions: make(map[string]bool)}
+	for _, id := range claudeSessions {
+		fake.sessions[id] = true
+	}
+
+	client, err := newWithDependencies(tempDir, storage.NewWithSessionsDir(tempDir, filepath.Join(tempDir, "sessions")), fake)
+	require.NoError(t, err)
+	return client
+}
+
+func TestClient_CreateListGet(t *testing.T) {
+	client := newTestClient(t)
+
+	_, err := client.CreateSession(context.Background(), "bot-task", "opened by a bot", []string{"bot"})
+	require.NoError(t, err)
+
+	names, err := client.ListSessions(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bot-task"}, names)
+
+	sessionData, err := client.GetSession(context.Background(), "bot-task")
+	require.NoError(t, err)
+	assert.Equal(t, "opened by a bot", sessionData.Metadata.Description)
+	assert.Equal(t, client.ProjectPath(), sessionData.Project.Path)
+}
+
+func TestClient_ListSummaries(t *testing.T) {
+	client := newTestClient(t, "claude-abc")
+	ctx := context.Background()
+
+	_, err := client.CreateSession(ctx, "bound", "", []string{"bot"})
+	require.NoError(t, err)
+	_, err = client.RunHeadless(ctx, "bound", "hello")
+	require.NoError(t, err)
+	_, err = client.CreateSession(ctx, "fresh", "", nil)
+	require.NoError(t, err)
+
+	summaries, err := client.ListSummaries(ctx, ListOptions{Sort: SortName})
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	assert.Equal(t, "bound", summaries[0].Name)
+	assert.Equal(t, "claude-abc", summaries[0].ClaudeSessionID)
+	assert.Equal(t, "fresh", summaries[1].Name)
+
+	summaries, err = client.ListSummaries(ctx, ListOptions{Binding: BindingUnbound})
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, "fresh", summaries[0].Name)
+
+	summaries, err = client.ListSummaries(ctx, ListOptions{Tags: []string{"bot"}})
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, "bound", summaries[0].Name)
+}
+
+func TestClient_ResolveUnbound(t *testing.T) {
+	client := newTestClient(t)
+
+	_, err := client.CreateSession(context.Background(), "fresh", "", nil)
+	require.NoError(t, err)
+
+	resolution, err := client.Resolve(context.Background(), "fresh")
+	require.NoError(t, err)
+	assert.False(t, resolution.HasClaudeSession)
+	assert.Equal(t, []string{"claude"}, resolution.Command)
+	assert.Equal(t, client.ProjectPath(), resolution.WorkingDirectory)
+	assert.Contains(t, resolution.Env, "KAMUI_SESSION_ID=fresh")
+}
+
+func TestClient_ResolveBound(t *testing.T) {
+	client := newTestClient(t, "claude-abc")
+
+	_, err := client.CreateSession(context.Background(), "bound", "", nil)
+	require.NoError(t, err)
+
+	// The first headless run binds the Claude session it creates
+	result, err := client.RunHeadless(context.Background(), "bound", "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "echo: hello", result.Result)
+
+	resolution, err := client.Resolve(context.Background(), "bound")
+	require.NoError(t, err)
+	assert.True(t, resolution.HasClaudeSession)
+	assert.Equal(t, []string{"claude", "--resume", "claude-abc"}, resolution.Command)
+	assert.Contains(t, resolution.Env, "KAMUI_CLAUDE_SESSION_ID=claude-abc")
+}
+
+func TestClient_ResolveMissingSession(t *testing.T) {
+	client := newTestClient(t)
+
+	_, err := client.Resolve(context.Background(), "missing")
+	require.Error(t, err)
+}
+
+func TestClient_Status(t *testing.T) {
+	client := newTestClient(t)
+
+	_, err := client.CreateSession(context.Background(), "api", "API work", []string{"backend"})
+	require.NoError(t, err)
+
+	report, err := client.Status(context.Background(), "api")
+	require.NoError(t, err)
+
+	assert.Equal(t, StatusSchemaVersion, report.SchemaVersion)
+	assert.Equal(t, client.ProjectPath(), report.Project.Path)
+	assert.Equal(t, "api", report.CurrentSession)
+	require.Len(t, report.Sessions, 1)
+
+	status := report.Sessions[0]
+	assert.Equal(t, "api", status.Name)
+	assert.Equal(t, types.SessionStateActive, status.State)
+	assert.Equal(t, []string{"backend"}, status.Tags)
+	assert.Equal(t, []string{"kam", "api"}, status.ResumeCommand)
+}
+
+func TestNewSessionStatus_EmptyTagsEncodeAsArray(t *testing.T) {
+	status := NewSessionStatus(&types.Session{SessionID: "x"})
+	assert.NotNil(t, status.Tags)
+}
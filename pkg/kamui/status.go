@@ -0,0 +1,80 @@
+package kamui
+
+import (
+	"context"
+	"time"
+
+	"github.com/bitomule/kamui/pkg/types"
+)
+
+// StatusSchemaVersion is incremented whenever the status document changes incompatibly
+// Fields may be added within a schema version; existing fields are never renamed or removed
+const StatusSchemaVersion = 1
+
+// StatusReport is the document printed by `kam status --json`
+type StatusReport struct {
+	SchemaVersion  int             `json:"schemaVersion"`
+	Project        ProjectStatus   `json:"project"`
+	CurrentSession string          `json:"currentSession,omitempty"`
+	Sessions       []SessionStatus `json:"sessions"`
+}
+
+// ProjectStatus identifies the project a status report describes
+type ProjectStatus struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// SessionStatus summarizes one session in a status report
+type SessionStatus struct {
+	Name             string             `json:"name"`
+	State            types.SessionState `json:"state"`
+	Description      string             `json:"description"`
+	Tags             []string           `json:"tags"`
+	WorkingDirectory string             `json:"workingDirectory"`
+	ClaudeSessionID  string             `json:"claudeSessionId"`
+	Created          time.Time          `json:"created"`
+	LastAccessed     time.Time          `json:"lastAccessed"`
+	ResumeCommand    []string           `json:"resumeCommand"`
+}
+
+// NewSessionStatus builds the status entry for a session
+func NewSessionStatus(session *types.Session) SessionStatus {
+	tags := session.Metadata.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return SessionStatus{
+		Name:             session.SessionID,
+		State:            session.Lifecycle.State,
+		Description:      session.Metadata.Description,
+		Tags:             tags,
+		WorkingDirectory: session.Project.WorkingDirectory,
+		ClaudeSessionID:  session.Claude.SessionID,
+		Created:          session.Created,
+		LastAccessed:     session.LastAccessed,
+		ResumeCommand:    []string{"kam", session.SessionID},
+	}
+}
+
+// Status returns the status report for the client's project
+func (c *Client) Status(ctx context.Context, currentSession string) (*StatusReport, error) {
+	sessions, err := c.manager.ListProjectSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &StatusReport{
+		SchemaVersion:  StatusSchemaVersion,
+		Project:        ProjectStatus{Name: c.manager.GetProjectName(), Path: c.manager.GetProjectPath()},
+		CurrentSession: currentSession,
+		Sessions:       []SessionStatus{},
+	}
+
+	for _, sessionData := range sessions {
+		report.Sessions = append(report.Sessions, NewSessionStatus(sessionData))
+	}
+
+	return report, nil
+}
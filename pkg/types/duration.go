@@ -0,0 +1,133 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Units ParseDuration accepts on top of the ones time.ParseDuration knows
+const (
+	Day  = 24 * time.Hour
+	Week = 7 * Day
+)
+
+// Duration is a time.Duration written the way people write thresholds, e.g. "30d", "2w" or "1h30m"
+// It is stored in JSON as such a string; the zero duration is stored as ""
+type Duration time.Duration
+
+// ParseDuration parses a non-negative duration made of numbers with units, e.g. "30d", "1w2d" or
+// "90m": w and d for weeks and days, plus every unit time.ParseDuration accepts
+func ParseDuration(value string) (Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "0" {
+		return 0, nil
+	}
+	if value == "" {
+		return 0, fmt.Errorf("invalid duration %q: empty", value)
+	}
+
+	var total time.Duration
+	rest := value
+	for rest != "" {
+		number := strings.IndexFunc(rest, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+		if number <= 0 {
+			return 0, fmt.Errorf("invalid duration %q: expected a number before %q", value, rest)
+		}
+		unitEnd := strings.IndexFunc(rest[number:], func(r rune) bool { return (r >= '0' && r <= '9') || r == '.' })
+		if unitEnd < 0 {
+			unitEnd = len(rest) - number
+		}
+		amount, unit := rest[:number], rest[number:number+unitEnd]
+		rest = rest[number+unitEnd:]
+
+		var part time.Duration
+		switch unit {
+		case "w", "d":
+			n, err := strconv.ParseFloat(amount, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q: %w", value, err)
+			}
+			part = Day
+			if unit == "w" {
+				part = Week
+			}
+			part = time.Duration(n * float64(part))
+		case "":
+			return 0, fmt.Errorf("invalid duration %q: missing unit after %s (use w, d, h, m or s)", value, amount)
+		default:
+			parsed, err := time.ParseDuration(amount + unit)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q: unknown unit %q (use w, d, h, m or s)", value, unit)
+			}
+			part = parsed
+		}
+		total += part
+	}
+	return Duration(total), nil
+}
+
+// String formats the duration with the largest whole unit, e.g. "2w", "30d" or "1d12h"
+func (d Duration) String() string {
+	duration := time.Duration(d)
+	switch {
+	case duration == 0:
+		return "0s"
+	case duration < 0:
+		return duration.String()
+	case duration%Week == 0:
+		return fmt.Sprintf("%dw", duration/Week)
+	case duration%Day == 0:
+		return fmt.Sprintf("%dd", duration/Day)
+	}
+
+	var prefix string
+	if duration > Day {
+		prefix = fmt.Sprintf("%dd", duration/Day)
+		duration %= Day
+	}
+	formatted := duration.String()
+	if strings.HasSuffix(formatted, "m0s") {
+		formatted = strings.TrimSuffix(formatted, "0s")
+	}
+	if strings.HasSuffix(formatted, "h0m") {
+		formatted = strings.TrimSuffix(formatted, "0m")
+	}
+	return prefix + formatted
+}
+
+// Std returns the duration as a time.Duration
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
+
+// MarshalJSON writes the duration as a string, empty for zero
+func (d Duration) MarshalJSON() ([]byte, error) {
+	if d == 0 {
+		return json.Marshal("")
+	}
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON reads a duration string; empty and null are zero
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("duration must be a string like \"30d\": %w", err)
+	}
+	if strings.TrimSpace(value) == "" {
+		*d = 0
+		return nil
+	}
+	parsed, err := ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
@@ -0,0 +1,118 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"0", 0},
+		{"30d", 30 * Day},
+		{"2w", 2 * Week},
+		{"1w2d", 9 * Day},
+		{"1.5d", 36 * time.Hour},
+		{"24h", 24 * time.Hour},
+		{"1h30m", 90 * time.Minute},
+		{"1d12h", 36 * time.Hour},
+		{" 5m ", 5 * time.Minute},
+		{"250ms", 250 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		parsed, err := ParseDuration(tt.value)
+		require.NoError(t, err, tt.value)
+		assert.Equal(t, tt.expected, parsed.Std(), tt.value)
+	}
+
+	for _, value := range []string{"", "30", "d", "-5m", "30 days", "3y", "1..5d"} {
+		_, err := ParseDuration(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestDurationString(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		expected string
+	}{
+		{0, "0s"},
+		{2 * Week, "2w"},
+		{30 * Day, "30d"},
+		{36 * time.Hour, "1d12h"},
+		{90 * time.Minute, "1h30m"},
+		{5 * time.Minute, "5m"},
+		{90 * time.Second, "1m30s"},
+		{Day + 30*time.Second, "1d30s"},
+	}
+
+	for _, tt := range tests {
+		formatted := Duration(tt.duration).String()
+		assert.Equal(t, tt.expected, formatted)
+
+		parsed, err := ParseDuration(formatted)
+		require.NoError(t, err)
+		assert.Equal(t, tt.duration, parsed.Std(), "round trip of %s", formatted)
+	}
+}
+
+func TestDurationJSON(t *testing.T) {
+	var cleanup CleanupConfig
+	require.NoError(t, json.Unmarshal([]byte(`{"inactiveThreshold": "30d"}`), &cleanup))
+	assert.Equal(t, 30*Day, cleanup.InactiveThreshold.Std())
+
+	data, err := json.Marshal(cleanup)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"inactiveThreshold":"30d"`)
+
+	// sessions written before thresholds were parsed hold empty strings
+	require.NoError(t, json.Unmarshal([]byte(`{"inactiveThreshold": ""}`), &cleanup))
+	assert.Zero(t, cleanup.InactiveThreshold)
+	data, err = json.Marshal(cleanup)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"inactiveThreshold":""`)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"inactiveThreshold": "30 days"}`), &cleanup))
+	assert.Error(t, json.Unmarshal([]byte(`{"inactiveThreshold": 30}`), &cleanup))
+}
+
+func TestCleanupConfigIsInactive(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	cleanup := CleanupConfig{Enabled: true, InactiveThreshold: Duration(30 * Day)}
+
+	assert.True(t, cleanup.IsInactive(now.Add(-31*Day), now))
+	assert.False(t, cleanup.IsInactive(now.Add(-29*Day), now))
+
+	cleanup.Enabled = false
+	assert.False(t, cleanup.IsInactive(now.Add(-31*Day), now))
+
+	assert.False(t, CleanupConfig{Enabled: true}.IsInactive(now.Add(-365*Day), now), "no threshold")
+}
+
+func TestGlobalIndexSync(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	index := GlobalIndex{
+		LastSync:      now.Add(-10 * time.Minute),
+		SyncInterval:  Duration(5 * time.Minute),
+		Configuration: IndexConfig{MaxIndexAge: Duration(24 * time.Hour)},
+	}
+
+	assert.True(t, index.NeedsSync(now))
+	assert.False(t, index.IsStale(now))
+
+	index.SyncInterval = Duration(time.Hour)
+	assert.False(t, index.NeedsSync(now))
+
+	index.LastSync = now.Add(-2 * Day)
+	assert.True(t, index.IsStale(now))
+
+	assert.True(t, GlobalIndex{}.NeedsSync(now), "never synced")
+	assert.False(t, GlobalIndex{}.IsStale(now), "no maximum age")
+}
@@ -2,6 +2,8 @@
 package types
 
 import (
+	"context"
+	"errors"
 	"fmt"
 )
 
@@ -39,6 +41,8 @@ const (
 	ErrCodeSessionCorrupted ErrorCode = "SESSION_CORRUPTED"
 	ErrCodeSessionLocked    ErrorCode = "SESSION_LOCKED"
 	ErrCodeSessionInvalid   ErrorCode = "SESSION_INVALID"
+	ErrCodeSessionProtected ErrorCode = "SESSION_PROTECTED"
+	ErrCodeSessionHeld      ErrorCode = "SESSION_HELD"
 
 	// Storage errors
 	ErrCodeStoragePermission ErrorCode = "STORAGE_PERMISSION"
@@ -111,6 +115,29 @@ func NewClaudeError(code ErrorCode, message string, cause error) *AGXError {
 	}
 }
 
+// NewConfigError creates a new configuration-related error
+func NewConfigError(code ErrorCode, message string, cause error) *AGXError {
+	return &AGXError{
+		Code:    code,
+		Message: message,
+		Cause:   cause,
+	}
+}
+
+// ContextError returns nil while ctx is live, and otherwise an ErrCodeTimeout error when its
+// deadline passed or an ErrCodeInterrupted error when it was canceled
+func ContextError(ctx context.Context) error {
+	err := ctx.Err()
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, context.DeadlineExceeded):
+		return &AGXError{Code: ErrCodeTimeout, Message: "operation timed out", Cause: err}
+	default:
+		return &AGXError{Code: ErrCodeInterrupted, Message: "operation canceled", Cause: err}
+	}
+}
+
 // WithContext adds context information to an error
 func (e *AGXError) WithContext(key string, value interface{}) *AGXError {
 	if e.Context == nil {
@@ -149,17 +176,62 @@ func (e *AGXError) GetRecoveryHint() string {
 	switch e.Code {
 	case ErrCodeDependencyMissing:
 		return "Install required dependencies (claude)"
+	case ErrCodeSessionNotFound:
+		return "Run 'kam list' to see the project's sessions"
+	case ErrCodeSessionExists:
+		return "Pick another name, or resume the session with 'kam <session>'"
 	case ErrCodeSessionLocked:
 		return "Wait for lock to be released or remove stale lock file"
 	case ErrCodeStoragePermission:
 		return "Check file permissions for AGX directories"
 	case ErrCodeClaudeNotFound:
 		return "Install Claude Code CLI"
+	case ErrCodeClaudeResumeFailed:
+		return "Run 'kam <session>' in a terminal to continue another conversation or start a fresh one"
+	case ErrCodeDependencyVersion:
+		return "Update Claude Code with 'claude update'"
 	case ErrCodeSessionCorrupted:
 		return "Session data may be corrupted, consider creating a new session"
+	case ErrCodeSessionProtected:
+		return "Pass --force, or remove the protection with 'kam protect <session> --off'"
+	case ErrCodeSessionHeld:
+		return "Check with whoever locked it, then release it with 'kam unlock <session>'"
 	case ErrCodeConfigInvalid:
 		return "Check configuration file syntax and values"
+	case ErrCodeInterrupted:
+		return "" // stopped on request; there is nothing to fix
 	default:
 		return "Check the error message for specific details"
 	}
 }
+
+// ErrorReport is the structured form of an error, as printed by commands run with --json
+type ErrorReport struct {
+	Code     ErrorCode              `json:"code"`
+	Message  string                 `json:"message"`
+	Cause    string                 `json:"cause,omitempty"`
+	Context  map[string]interface{} `json:"context,omitempty"`
+	Hint     string                 `json:"hint,omitempty"`
+	ExitCode int                    `json:"exitCode"`
+}
+
+// NewErrorReport describes err using the first AGXError in its chain; other errors
+// are reported with ErrCodeUnknown and their message
+func NewErrorReport(err error) ErrorReport {
+	var agxErr *AGXError
+	if !errors.As(err, &agxErr) {
+		return ErrorReport{Code: ErrCodeUnknown, Message: err.Error(), ExitCode: ExitCodeFor(err)}
+	}
+
+	report := ErrorReport{
+		Code:     agxErr.Code,
+		Message:  agxErr.Message,
+		Context:  agxErr.Context,
+		Hint:     agxErr.GetRecoveryHint(),
+		ExitCode: agxErr.ExitCode(),
+	}
+	if agxErr.Cause != nil {
+		report.Cause = agxErr.Cause.Error()
+	}
+	return report
+}
@@ -1,10 +1,14 @@
 package types
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAGXError_Error(t *testing.T) {
@@ -84,6 +88,32 @@ func TestNewClaudeError(t *testing.T) {
 	assert.Equal(t, cause, err.Cause)
 }
 
+func TestNewConfigError(t *testing.T) {
+	err := NewConfigError(ErrCodeConfigInvalid, "unknown project detection strategy", nil)
+
+	assert.Equal(t, ErrCodeConfigInvalid, err.Code)
+	assert.Equal(t, "unknown project detection strategy", err.Message)
+	assert.Nil(t, err.Cause)
+}
+
+func TestContextError(t *testing.T) {
+	assert.NoError(t, ContextError(context.Background()))
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	var agxErr *AGXError
+	require.ErrorAs(t, ContextError(canceled), &agxErr)
+	assert.Equal(t, ErrCodeInterrupted, agxErr.Code)
+	assert.ErrorIs(t, agxErr, context.Canceled)
+	assert.Equal(t, ExitInterrupted, agxErr.ExitCode())
+
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+	require.ErrorAs(t, ContextError(expired), &agxErr)
+	assert.Equal(t, ErrCodeTimeout, agxErr.Code)
+	assert.ErrorIs(t, agxErr, context.DeadlineExceeded)
+}
+
 func TestAGXError_WithContext(t *testing.T) {
 	err := &AGXError{
 		Code:    ErrCodeSessionNotFound,
@@ -165,8 +195,10 @@ func TestAGXError_GetRecoveryHint(t *testing.T) {
 		{ErrCodeSessionLocked, "Wait for lock to be released or remove stale lock file"},
 		{ErrCodeStoragePermission, "Check file permissions for AGX directories"},
 		{ErrCodeClaudeNotFound, "Install Claude Code CLI"},
+		{ErrCodeDependencyVersion, "Update Claude Code with 'claude update'"},
 		{ErrCodeSessionCorrupted, "Session data may be corrupted, consider creating a new session"},
 		{ErrCodeConfigInvalid, "Check configuration file syntax and values"},
+		{ErrCodeInterrupted, ""},
 		{ErrCodeUnknown, "Check the error message for specific details"},
 	}
 
@@ -176,6 +208,24 @@ func TestAGXError_GetRecoveryHint(t *testing.T) {
 	}
 }
 
+func TestNewErrorReport(t *testing.T) {
+	err := NewSessionError(ErrCodeSessionNotFound, "session 'api' not found", errors.New("no such file")).
+		WithContext("session", "api")
+
+	report := NewErrorReport(fmt.Errorf("resume failed: %w", err))
+	assert.Equal(t, ErrorReport{
+		Code:     ErrCodeSessionNotFound,
+		Message:  "session 'api' not found",
+		Cause:    "no such file",
+		Context:  map[string]interface{}{"session": "api"},
+		Hint:     "Run 'kam list' to see the project's sessions",
+		ExitCode: ExitSessionNotFound,
+	}, report)
+
+	plain := NewErrorReport(errors.New("boom"))
+	assert.Equal(t, ErrorReport{Code: ErrCodeUnknown, Message: "boom", ExitCode: ExitFailure}, plain)
+}
+
 func TestErrorCodes(t *testing.T) {
 	// Test that all error codes are defined as expected
 	allCodes := []ErrorCode{
@@ -190,6 +240,8 @@ func TestErrorCodes(t *testing.T) {
 		ErrCodeSessionCorrupted,
 		ErrCodeSessionLocked,
 		ErrCodeSessionInvalid,
+		ErrCodeSessionProtected,
+		ErrCodeSessionHeld,
 
 		// Storage errors
 		ErrCodeStoragePermission,
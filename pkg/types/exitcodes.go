@@ -0,0 +1,80 @@
+package types
+
+import (
+	"errors"
+)
+
+// Process exit statuses for failures, so scripts can branch on the kind of failure
+const (
+	ExitOK               = 0
+	ExitFailure          = 1 // any error without a more specific status
+	ExitInvalidInput     = 2
+	ExitSessionNotFound  = 3
+	ExitClaudeNotFound   = 4
+	ExitLocked           = 5
+	ExitSessionExists    = 6
+	ExitSessionProtected = 7
+	ExitCorrupted        = 8
+	ExitConfig           = 9
+	ExitStorage          = 10
+	ExitClaude           = 11
+	ExitProject          = 12
+	ExitDependency       = 13
+	ExitTimeout          = 124 // as timeout(1)
+	ExitInterrupted      = 130 // as a shell reports SIGINT
+)
+
+// ExitCodeInfo documents one exit status and the error codes that produce it
+type ExitCodeInfo struct {
+	Status  int
+	Meaning string
+	Codes   []ErrorCode
+}
+
+// ExitCodes lists every exit status Kamui uses, in order
+var ExitCodes = []ExitCodeInfo{
+	{ExitOK, "success", nil},
+	{ExitFailure, "other error", []ErrorCode{ErrCodeUnknown}},
+	{ExitInvalidInput, "invalid input or session data", []ErrorCode{ErrCodeInvalidInput, ErrCodeSessionInvalid}},
+	{ExitSessionNotFound, "session not found", []ErrorCode{ErrCodeSessionNotFound}},
+	{ExitClaudeNotFound, "claude CLI not found", []ErrorCode{ErrCodeClaudeNotFound, ErrCodeDependencyMissing}},
+	{ExitLocked, "session or storage locked", []ErrorCode{ErrCodeSessionLocked, ErrCodeSessionHeld, ErrCodeStorageLocked}},
+	{ExitSessionExists, "session already exists", []ErrorCode{ErrCodeSessionExists}},
+	{ExitSessionProtected, "session is protected", []ErrorCode{ErrCodeSessionProtected}},
+	{ExitCorrupted, "corrupted session or storage data", []ErrorCode{ErrCodeSessionCorrupted, ErrCodeStorageCorrupted}},
+	{ExitConfig, "configuration error", []ErrorCode{ErrCodeConfigInvalid, ErrCodeConfigNotFound, ErrCodeConfigPermission}},
+	{ExitStorage, "storage error", []ErrorCode{ErrCodeStoragePermission, ErrCodeStorageNotFound, ErrCodeStorageFull}},
+	{ExitClaude, "Claude failed", []ErrorCode{
+		ErrCodeClaudeSessionInvalid, ErrCodeClaudeSessionNotFound, ErrCodeClaudeResumeFailed,
+		ErrCodeClaudeStartFailed, ErrCodeClaudeCommandFailed,
+	}},
+	{ExitProject, "project error", []ErrorCode{ErrCodeProjectNotFound, ErrCodeProjectInvalid, ErrCodeProjectPermission}},
+	{ExitDependency, "external tool or service failed", []ErrorCode{ErrCodeDependencyFailed, ErrCodeDependencyVersion}},
+	{ExitTimeout, "timed out", []ErrorCode{ErrCodeTimeout, ErrCodeClaudeTimeout}},
+	{ExitInterrupted, "interrupted", []ErrorCode{ErrCodeInterrupted}},
+}
+
+// ExitCode returns the process exit status for the error's code
+func (e *AGXError) ExitCode() int {
+	for _, info := range ExitCodes {
+		for _, code := range info.Codes {
+			if code == e.Code {
+				return info.Status
+			}
+		}
+	}
+	return ExitFailure
+}
+
+// ExitCodeFor returns the exit status for any error: the status of the first AGXError
+// in its chain, ExitFailure for other errors and ExitOK for nil
+func ExitCodeFor(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var agxErr *AGXError
+	if errors.As(err, &agxErr) {
+		return agxErr.ExitCode()
+	}
+	return ExitFailure
+}
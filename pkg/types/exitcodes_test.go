@@ -0,0 +1,46 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCodeFor(t *testing.T) {
+	assert.Equal(t, ExitOK, ExitCodeFor(nil))
+	assert.Equal(t, ExitFailure, ExitCodeFor(errors.New("plain")))
+	assert.Equal(t, ExitSessionNotFound, ExitCodeFor(NewSessionError(ErrCodeSessionNotFound, "missing", nil)))
+	assert.Equal(t, ExitClaudeNotFound, ExitCodeFor(NewClaudeError(ErrCodeClaudeNotFound, "no claude", nil)))
+	assert.Equal(t, ExitLocked, ExitCodeFor(NewStorageError(ErrCodeStorageLocked, "locked", nil)))
+
+	wrapped := fmt.Errorf("failed to setup Claude session: %w", NewClaudeError(ErrCodeClaudeStartFailed, "ended", nil))
+	assert.Equal(t, ExitClaude, ExitCodeFor(wrapped))
+}
+
+func TestExitCodes_CoverEveryCodeOnce(t *testing.T) {
+	seen := map[ErrorCode]int{}
+	statuses := map[int]bool{}
+	for _, info := range ExitCodes {
+		assert.False(t, statuses[info.Status], "status %d listed twice", info.Status)
+		statuses[info.Status] = true
+		for _, code := range info.Codes {
+			seen[code]++
+		}
+	}
+
+	for _, code := range []ErrorCode{
+		ErrCodeDependencyMissing, ErrCodeDependencyVersion, ErrCodeDependencyFailed,
+		ErrCodeSessionNotFound, ErrCodeSessionExists, ErrCodeSessionCorrupted, ErrCodeSessionLocked,
+		ErrCodeSessionInvalid, ErrCodeSessionProtected, ErrCodeSessionHeld,
+		ErrCodeStoragePermission, ErrCodeStorageNotFound, ErrCodeStorageCorrupted, ErrCodeStorageFull, ErrCodeStorageLocked,
+		ErrCodeClaudeNotFound, ErrCodeClaudeSessionInvalid, ErrCodeClaudeSessionNotFound, ErrCodeClaudeResumeFailed,
+		ErrCodeClaudeStartFailed, ErrCodeClaudeCommandFailed, ErrCodeClaudeTimeout,
+		ErrCodeConfigInvalid, ErrCodeConfigNotFound, ErrCodeConfigPermission,
+		ErrCodeProjectNotFound, ErrCodeProjectInvalid, ErrCodeProjectPermission,
+		ErrCodeInvalidInput, ErrCodeTimeout, ErrCodeInterrupted, ErrCodeUnknown,
+	} {
+		assert.Equal(t, 1, seen[code], "%s should map to exactly one exit status", code)
+	}
+}
@@ -0,0 +1,72 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// transitions lists the states each state may move to; staying in the same state is always allowed
+// The error state can be entered from anywhere, and every failure records its own change
+var transitions = map[SessionState][]SessionState{
+	SessionStateActive:    {SessionStatePaused, SessionStateCompleted, SessionStateArchived, SessionStateError},
+	SessionStatePaused:    {SessionStateActive, SessionStateCompleted, SessionStateArchived, SessionStateError},
+	SessionStateCompleted: {SessionStateActive, SessionStateArchived, SessionStateError},
+	SessionStateArchived:  {SessionStateActive, SessionStateError},
+	SessionStateError:     {SessionStateActive, SessionStatePaused, SessionStateCompleted, SessionStateArchived, SessionStateError},
+}
+
+// IsValid reports whether the state is one Kamui writes
+func (s SessionState) IsValid() bool {
+	_, ok := transitions[s]
+	return ok
+}
+
+// CanTransitionTo reports whether the lifecycle may move to state
+// A lifecycle in an unknown state, e.g. from a damaged file, may move to any valid state
+func (l *LifecycleInfo) CanTransitionTo(state SessionState) bool {
+	if !state.IsValid() {
+		return false
+	}
+	allowed, ok := transitions[l.State]
+	if !ok || state == l.State {
+		return true
+	}
+	for _, candidate := range allowed {
+		if candidate == state {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyTransition moves the lifecycle to state and records the change in its history
+// Staying in the same state records nothing, except for the error state, where each failure is
+// kept; a transition that isn't allowed returns an ErrCodeInvalidInput error and changes nothing
+func (l *LifecycleInfo) ApplyTransition(state SessionState, reason string, now time.Time) error {
+	if !l.CanTransitionTo(state) {
+		return NewSessionError(ErrCodeInvalidInput,
+			fmt.Sprintf("can't move a session from state '%s' to '%s'", l.State, state), nil)
+	}
+	if state == l.State && state != SessionStateError {
+		return nil
+	}
+	l.State = state
+	l.StateHistory = append(l.StateHistory, StateChange{
+		State:     state,
+		Timestamp: now,
+		Reason:    reason,
+	})
+	return nil
+}
+
+// ApplyTransition moves the session's lifecycle to state and updates LastModified when it changes
+func (s *Session) ApplyTransition(state SessionState, reason string, now time.Time) error {
+	recorded := len(s.Lifecycle.StateHistory)
+	if err := s.Lifecycle.ApplyTransition(state, reason, now); err != nil {
+		return err
+	}
+	if len(s.Lifecycle.StateHistory) != recorded {
+		s.LastModified = now
+	}
+	return nil
+}
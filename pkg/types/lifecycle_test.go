@@ -0,0 +1,79 @@
+package types
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionStateIsValid(t *testing.T) {
+	assert.True(t, SessionStateActive.IsValid())
+	assert.True(t, SessionStateError.IsValid())
+	assert.False(t, SessionState("running").IsValid())
+	assert.False(t, SessionState("").IsValid())
+}
+
+func TestCanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to SessionState
+		allowed  bool
+	}{
+		{SessionStateActive, SessionStateCompleted, true},
+		{SessionStateActive, SessionStateActive, true},
+		{SessionStateCompleted, SessionStateActive, true},
+		{SessionStateCompleted, SessionStatePaused, false},
+		{SessionStateArchived, SessionStateActive, true},
+		{SessionStateArchived, SessionStateCompleted, false},
+		{SessionStateArchived, SessionStateError, true},
+		{SessionStateError, SessionStateError, true},
+		{SessionStateActive, SessionState("running"), false},
+		{SessionState("running"), SessionStatePaused, true},
+	}
+
+	for _, tt := range tests {
+		lifecycle := LifecycleInfo{State: tt.from}
+		assert.Equal(t, tt.allowed, lifecycle.CanTransitionTo(tt.to), "%s -> %s", tt.from, tt.to)
+	}
+}
+
+func TestLifecycleApplyTransition(t *testing.T) {
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	lifecycle := LifecycleInfo{State: SessionStateActive}
+
+	require.NoError(t, lifecycle.ApplyTransition(SessionStateCompleted, "manually_completed", now))
+	assert.Equal(t, SessionStateCompleted, lifecycle.State)
+	assert.Equal(t, []StateChange{{State: SessionStateCompleted, Timestamp: now, Reason: "manually_completed"}}, lifecycle.StateHistory)
+
+	// staying in the same state records nothing
+	require.NoError(t, lifecycle.ApplyTransition(SessionStateCompleted, "again", now))
+	assert.Len(t, lifecycle.StateHistory, 1)
+
+	// a transition that isn't allowed changes nothing
+	err := lifecycle.ApplyTransition(SessionStatePaused, "pause", now)
+	var agxErr *AGXError
+	require.True(t, errors.As(err, &agxErr))
+	assert.Equal(t, ErrCodeInvalidInput, agxErr.Code)
+	assert.Equal(t, SessionStateCompleted, lifecycle.State)
+	assert.Len(t, lifecycle.StateHistory, 1)
+
+	// each failure is recorded
+	require.NoError(t, lifecycle.ApplyTransition(SessionStateError, "first", now))
+	require.NoError(t, lifecycle.ApplyTransition(SessionStateError, "second", now))
+	assert.Len(t, lifecycle.StateHistory, 3)
+}
+
+func TestSessionApplyTransition(t *testing.T) {
+	created := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	now := created.Add(time.Hour)
+	session := Session{LastModified: created, Lifecycle: LifecycleInfo{State: SessionStateActive}}
+
+	require.NoError(t, session.ApplyTransition(SessionStateActive, "noop", now))
+	assert.Equal(t, created, session.LastModified)
+
+	require.NoError(t, session.ApplyTransition(SessionStateArchived, "manually_archived", now))
+	assert.Equal(t, SessionStateArchived, session.Lifecycle.State)
+	assert.Equal(t, now, session.LastModified)
+}
@@ -25,6 +25,7 @@ type ProjectInfo struct {
 	Name             string `json:"name"`
 	Path             string `json:"path"`
 	WorkingDirectory string `json:"workingDirectory"`
+	Package          string `json:"package,omitempty"`
 	GitBranch        string `json:"gitBranch"`
 	GitCommit        string `json:"gitCommit"`
 	GitRemote        string `json:"gitRemote"`
@@ -39,6 +40,16 @@ type ClaudeInfo struct {
 	LastInteraction  time.Time   `json:"lastInteraction"`
 	ContextInfo      ContextInfo `json:"contextInfo"`
 	ResumeInfo       ResumeInfo  `json:"resumeInfo"`
+
+	// CLIVersion is the Claude Code version last used with the session
+	CLIVersion string `json:"cliVersion,omitempty"`
+
+	// PermissionMode is the Claude permission mode the session always launches in, if set
+	PermissionMode string `json:"permissionMode,omitempty"`
+
+	// Pending is set while SessionID was chosen by Kamui (--session-id) and Claude hasn't
+	// written the conversation yet; the next launch starts it rather than resuming it
+	Pending bool `json:"pending,omitempty"`
 }
 
 // ContextInfo contains metadata about the Claude conversation state
@@ -55,6 +66,30 @@ type ResumeInfo struct {
 	ResumeCommand     string     `json:"resumeCommand"`
 	LastResumeAttempt *time.Time `json:"lastResumeAttempt"`
 	ResumeErrors      []string   `json:"resumeErrors"`
+
+	// Fallbacks records how failed resumes were resolved, oldest first
+	Fallbacks []ResumeFallback `json:"fallbacks,omitempty"`
+}
+
+// ResumeChoice is how a failed resume was resolved
+type ResumeChoice string
+
+const (
+	// ResumeChoiceNewest bound the session to the project's newest unbound conversation
+	ResumeChoiceNewest ResumeChoice = "newest"
+	// ResumeChoicePicked bound the session to a conversation picked from those found
+	ResumeChoicePicked ResumeChoice = "picked"
+	// ResumeChoiceFresh unbound the session so Claude starts a new conversation
+	ResumeChoiceFresh ResumeChoice = "fresh"
+)
+
+// ResumeFallback is one failed resume and what was done about it
+type ResumeFallback struct {
+	Time   time.Time    `json:"time"`
+	Choice ResumeChoice `json:"choice"`
+	From   string       `json:"from"`
+	To     string       `json:"to,omitempty"`
+	Reason string       `json:"reason,omitempty"`
 }
 
 // SessionMeta contains session metadata and user-defined information
@@ -64,6 +99,51 @@ type SessionMeta struct {
 	Variant     string                 `json:"variant"`
 	IsDefault   bool                   `json:"isDefault"`
 	CustomData  map[string]interface{} `json:"customData"`
+	Notes       []Note                 `json:"notes,omitempty"`
+	Env         map[string]string      `json:"env,omitempty"`
+	Budget      *Budget                `json:"budget,omitempty"`
+	Links       []Link                 `json:"links,omitempty"`
+	ReadOnly    bool                   `json:"readOnly,omitempty"`
+	Protected   bool                   `json:"protected,omitempty"`
+	Pinned      bool                   `json:"pinned,omitempty"`
+	ClaudeArgs  []string               `json:"claudeArgs,omitempty"`
+}
+
+// Note is a timestamped piece of text attached to a session
+type Note struct {
+	Created time.Time `json:"created"`
+	Source  string    `json:"source"`
+	Text    string    `json:"text"`
+}
+
+// Link ties a session to an issue, pull request or other URL
+type Link struct {
+	Kind  string    `json:"kind"`
+	URL   string    `json:"url"`
+	Label string    `json:"label"`
+	Title string    `json:"title,omitempty"`
+	Added time.Time `json:"added"`
+}
+
+// Budget caps the estimated cost and/or tokens a session or project may use; zero means no limit
+type Budget struct {
+	CostUSD float64 `json:"costUsd,omitempty"`
+	Tokens  int64   `json:"tokens,omitempty"`
+}
+
+// SessionLock records the kam process running a session interactively
+type SessionLock struct {
+	PID      int       `json:"pid"`
+	Host     string    `json:"host"`
+	Acquired time.Time `json:"acquired"`
+}
+
+// ManualLock is a lock set by hand with kam lock, keeping a session from being resumed or
+// deleted on this machine until kam unlock releases it
+type ManualLock struct {
+	Reason string    `json:"reason,omitempty"`
+	User   string    `json:"user,omitempty"`
+	Locked time.Time `json:"locked"`
 }
 
 // SessionStats contains usage statistics for the session
@@ -74,6 +154,16 @@ type SessionStats struct {
 	LastSessionDuration  string `json:"lastSessionDuration"`
 	MostActiveDay        string `json:"mostActiveDay"`
 	CommandsExecuted     int    `json:"commandsExecuted"`
+
+	// LastExitCode is how Claude last exited when kam ran it; -1 when it was killed by a signal
+	LastExitCode int        `json:"lastExitCode"`
+	LastExitAt   *time.Time `json:"lastExitAt,omitempty"`
+
+	// ConsecutiveFailures counts the latest runs in a row that exited non-zero
+	ConsecutiveFailures int `json:"consecutiveFailures,omitempty"`
+
+	// CompletedAt is when the session was last completed
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
 }
 
 // LifecycleInfo tracks the session lifecycle and state management
@@ -99,25 +189,46 @@ type StateChange struct {
 	State     SessionState `json:"state"`
 	Timestamp time.Time    `json:"timestamp"`
 	Reason    string       `json:"reason"`
+	// Duration is the session's total active time when it was completed
+	Duration Duration `json:"duration,omitempty"`
 }
 
 // CleanupConfig controls automatic session cleanup behavior
 type CleanupConfig struct {
 	Enabled           bool      `json:"enabled"`
-	InactiveThreshold string    `json:"inactiveThreshold"`
+	InactiveThreshold Duration  `json:"inactiveThreshold"`
 	LastCleanupCheck  time.Time `json:"lastCleanupCheck"`
 }
 
+// IsInactive reports whether cleanup is enabled and a session last accessed at lastAccessed has
+// gone unused for longer than InactiveThreshold
+func (c CleanupConfig) IsInactive(lastAccessed, now time.Time) bool {
+	return c.Enabled && c.InactiveThreshold > 0 && now.Sub(lastAccessed) > c.InactiveThreshold.Std()
+}
+
 // GlobalIndex represents the global session discovery index
 type GlobalIndex struct {
 	Version       string           `json:"version"`
 	LastSync      time.Time        `json:"lastSync"`
-	SyncInterval  string           `json:"syncInterval"`
+	SyncInterval  Duration         `json:"syncInterval"`
 	Sessions      []IndexedSession `json:"sessions"`
 	Statistics    IndexStats       `json:"statistics"`
 	Configuration IndexConfig      `json:"configuration"`
 }
 
+// NeedsSync reports whether SyncInterval has passed since the index was last synced
+// An index never synced, or without an interval, always needs it
+func (g GlobalIndex) NeedsSync(now time.Time) bool {
+	return g.LastSync.IsZero() || now.Sub(g.LastSync) >= g.SyncInterval.Std()
+}
+
+// IsStale reports whether the index is older than Configuration.MaxIndexAge and shouldn't be
+// trusted without a sync; without a maximum age it never is
+func (g GlobalIndex) IsStale(now time.Time) bool {
+	maxAge := g.Configuration.MaxIndexAge
+	return maxAge > 0 && now.Sub(g.LastSync) > maxAge.Std()
+}
+
 // IndexedSession represents a session entry in the global index
 type IndexedSession struct {
 	SessionID   string      `json:"sessionId"`
@@ -170,27 +281,32 @@ type IndexStats struct {
 
 // IndexConfig contains configuration for index management
 type IndexConfig struct {
-	AutoIndexing       bool   `json:"autoIndexing"`
-	MaxIndexAge        string `json:"maxIndexAge"`
-	SyncFailureRetries int    `json:"syncFailureRetries"`
-	EnableStatistics   bool   `json:"enableStatistics"`
+	AutoIndexing       bool     `json:"autoIndexing"`
+	MaxIndexAge        Duration `json:"maxIndexAge"`
+	SyncFailureRetries int      `json:"syncFailureRetries"`
+	EnableStatistics   bool     `json:"enableStatistics"`
 }
 
 // Config represents the global AGX configuration
 type Config struct {
-	Version string        `json:"version"`
-	Default DefaultConfig `json:"default"`
-	Claude  ClaudeConfig  `json:"claude"`
-	Session SessionConfig `json:"session"`
-	Storage StorageConfig `json:"storage"`
-	UI      UIConfig      `json:"ui"`
+	Version    string           `json:"version"`
+	Default    DefaultConfig    `json:"default"`
+	Claude     ClaudeConfig     `json:"claude"`
+	Session    SessionConfig    `json:"session"`
+	Storage    StorageConfig    `json:"storage"`
+	UI         UIConfig         `json:"ui"`
+	Jira       JiraConfig       `json:"jira"`
+	GitHub     GitHubConfig     `json:"github"`
+	Timesheet  TimesheetConfig  `json:"timesheet"`
+	Transcript TranscriptConfig `json:"transcript"`
 }
 
 // DefaultConfig contains default behavior settings
 type DefaultConfig struct {
-	SessionVariant     string `json:"sessionVariant"`
-	AutoCreateSessions bool   `json:"autoCreateSessions"`
-	ProjectDetection   string `json:"projectDetection"`
+	SessionVariant     string   `json:"sessionVariant"`
+	AutoCreateSessions bool     `json:"autoCreateSessions"`
+	ProjectDetection   string   `json:"projectDetection"`
+	ProjectMarkers     []string `json:"projectMarkers"`
 }
 
 // ClaudeConfig contains Claude Code integration settings
@@ -200,31 +316,104 @@ type ClaudeConfig struct {
 	DefaultArgs         []string `json:"defaultArgs"`
 	RetryAttempts       int      `json:"retryAttempts"`
 	ContextPreservation bool     `json:"contextPreservation"`
+	InjectContext       bool     `json:"injectContext"`
+	MinVersion          string   `json:"minVersion"`
 }
 
 // SessionConfig contains session management settings
 type SessionConfig struct {
-	AutoBranchSessions  bool `json:"autoBranchSessions"`
-	CleanupInactiveDays int  `json:"cleanupInactiveDays"`
-	BackupCount         int  `json:"backupCount"`
-	AutoArchive         bool `json:"autoArchive"`
-	EnableStatistics    bool `json:"enableStatistics"`
+	AutoBranchSessions  bool   `json:"autoBranchSessions"`
+	CleanupInactiveDays int    `json:"cleanupInactiveDays"`
+	BackupCount         int    `json:"backupCount"`
+	AutoArchive         bool   `json:"autoArchive"`
+	EnableStatistics    bool   `json:"enableStatistics"`
+	CaseFolding         bool   `json:"caseFolding"`
+	Snapshots           bool   `json:"snapshots"`
+	SnapshotDirty       bool   `json:"snapshotDirty"`
+	SnapshotCount       int    `json:"snapshotCount"`
+	Record              bool   `json:"record"`
+	RecordingCount      int    `json:"recordingCount"`
+	Checkpoint          string `json:"checkpoint"`
 }
 
 // StorageConfig contains storage and indexing settings
 type StorageConfig struct {
-	IndexSyncInterval string `json:"indexSyncInterval"`
-	EnableGlobalIndex bool   `json:"enableGlobalIndex"`
-	CompactThreshold  string `json:"compactThreshold"`
-	LogRetentionDays  int    `json:"logRetentionDays"`
+	Backend           string              `json:"backend"`
+	DurableWrites     bool                `json:"durableWrites"`
+	Remote            RemoteStorageConfig `json:"remote"`
+	IndexSyncInterval Duration            `json:"indexSyncInterval"`
+	EnableGlobalIndex bool                `json:"enableGlobalIndex"`
+	CompactThreshold  string              `json:"compactThreshold"`
+	LogRetentionDays  int                 `json:"logRetentionDays"`
+	LogFileSize       string              `json:"logFileSize"`
+	LogMaxSize        string              `json:"logMaxSize"`
+}
+
+// RemoteStorageConfig points the remote storage backend at a kam serve instance
+type RemoteStorageConfig struct {
+	URL   string `json:"url"`
+	Token string `json:"token"`
 }
 
 // UIConfig contains user interface settings
 type UIConfig struct {
-	ColorOutput        bool   `json:"colorOutput"`
-	VerboseLogging     bool   `json:"verboseLogging"`
-	ConfirmDestructive bool   `json:"confirmDestructive"`
-	DefaultEditor      string `json:"defaultEditor"`
+	ColorOutput        bool         `json:"colorOutput"`
+	VerboseLogging     bool         `json:"verboseLogging"`
+	ConfirmDestructive bool         `json:"confirmDestructive"`
+	Picker             string       `json:"picker"`
+	DefaultEditor      string       `json:"defaultEditor"`
+	Notifications      bool         `json:"notifications"`
+	ExitAlert          []string     `json:"exitAlert"`
+	ITerm2             ITerm2Config `json:"iterm2"`
+	// Theme names the color theme: a built-in one or one defined in Themes
+	Theme  string                 `json:"theme"`
+	Themes map[string]ThemeConfig `json:"themes"`
+}
+
+// ThemeConfig styles each role text plays in kam's output, e.g. "bold bright-cyan", "#268bd2"
+// or raw SGR parameters such as "38;5;33"; roles left empty take Base's style
+type ThemeConfig struct {
+	Base     string `json:"base,omitempty"`
+	Accent   string `json:"accent,omitempty"`
+	Emphasis string `json:"emphasis,omitempty"`
+	Muted    string `json:"muted,omitempty"`
+	Success  string `json:"success,omitempty"`
+	Warning  string `json:"warning,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Match    string `json:"match,omitempty"`
+}
+
+// JiraConfig contains the optional Jira integration settings
+type JiraConfig struct {
+	BaseURL              string `json:"baseUrl"`
+	Email                string `json:"email"`
+	APIToken             string `json:"apiToken"`
+	TransitionOnComplete string `json:"transitionOnComplete"`
+}
+
+// GitHubConfig contains the GitHub settings used to share transcripts as gists
+type GitHubConfig struct {
+	APIURL string `json:"apiUrl"`
+	Token  string `json:"token"`
+}
+
+// TimesheetConfig contains time tracking settings
+type TimesheetConfig struct {
+	Enabled bool `json:"enabled"`
+}
+
+// TranscriptConfig limits how much of a Claude transcript is read and when unbound ones are collected
+type TranscriptConfig struct {
+	MaxLineSize string `json:"maxLineSize"`
+	MaxBytes    string `json:"maxBytes"`
+	MaxLines    int    `json:"maxLines"`
+	GCAfter     string `json:"gcAfter"`
+}
+
+// ITerm2Config contains iTerm2-specific integration settings
+type ITerm2Config struct {
+	Badge    bool              `json:"badge"`
+	Profiles map[string]string `json:"profiles"`
 }
 
 // ProjectConfig represents project-specific configuration
@@ -253,4 +442,19 @@ type SessionProjectConfig struct {
 	Variants       []string `json:"variants"`
 	BranchSessions bool     `json:"branchSessions"`
 	AutoCleanup    bool     `json:"autoCleanup"`
+
+	// MaxSessions is how many sessions, archived ones aside, the project is meant to keep; creating
+	// more only warns. Zero means no limit
+	MaxSessions int `json:"maxSessions,omitempty"`
+
+	// Prompts holds the instructions given to Claude in sessions of each variant, by variant name
+	Prompts map[string]VariantPrompt `json:"prompts,omitempty"`
+}
+
+// VariantPrompt is the set of instructions Claude gets in sessions of one variant
+type VariantPrompt struct {
+	// SystemPrompt is appended to Claude's system prompt on every launch
+	SystemPrompt string `json:"systemPrompt,omitempty"`
+	// ClaudeMD is kept in a Kamui-managed block of CLAUDE.md in the working directory
+	ClaudeMD string `json:"claudeMd,omitempty"`
 }
@@ -103,7 +103,7 @@ func TestSessionSerialization(t *testing.T) {
 			},
 			AutoCleanup: CleanupConfig{
 				Enabled:           true,
-				InactiveThreshold: "30d",
+				InactiveThreshold: Duration(30 * Day),
 				LastCleanupCheck:  now,
 			},
 		},
@@ -267,7 +267,7 @@ func TestCleanupConfig(t *testing.T) {
 
 	cleanup := CleanupConfig{
 		Enabled:           true,
-		InactiveThreshold: "30d",
+		InactiveThreshold: Duration(30 * Day),
 		LastCleanupCheck:  now,
 	}
 
@@ -290,7 +290,7 @@ func TestGlobalIndexSerialization(t *testing.T) {
 	globalIndex := GlobalIndex{
 		Version:      "1.0.0",
 		LastSync:     now,
-		SyncInterval: "5m",
+		SyncInterval: Duration(5 * time.Minute),
 		Sessions: []IndexedSession{
 			{
 				SessionID:   "session-1",
@@ -329,7 +329,7 @@ func TestGlobalIndexSerialization(t *testing.T) {
 		},
 		Configuration: IndexConfig{
 			AutoIndexing:       true,
-			MaxIndexAge:        "24h",
+			MaxIndexAge:        Duration(24 * time.Hour),
 			SyncFailureRetries: 3,
 			EnableStatistics:   true,
 		},